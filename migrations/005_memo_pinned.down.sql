@@ -0,0 +1,5 @@
+-- メモのピン留めフラグを削除
+
+DROP INDEX IF EXISTS idx_memos_pinned;
+
+ALTER TABLE memos DROP COLUMN IF EXISTS pinned;
@@ -0,0 +1,5 @@
+-- メモのピン留めフラグを追加
+
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_memos_pinned ON memos(pinned);
@@ -0,0 +1,7 @@
+-- メモの編集履歴と添付ファイルのテーブルを削除
+
+DROP INDEX IF EXISTS idx_memo_attachments_memo_id;
+DROP INDEX IF EXISTS idx_memo_revisions_memo_id;
+
+DROP TABLE IF EXISTS memo_attachments;
+DROP TABLE IF EXISTS memo_revisions;
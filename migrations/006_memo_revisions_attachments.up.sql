@@ -0,0 +1,25 @@
+-- メモの編集履歴と添付ファイルのテーブルを追加
+
+CREATE TABLE IF NOT EXISTS memo_revisions (
+    id SERIAL PRIMARY KEY,
+    memo_id INTEGER NOT NULL REFERENCES memos(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    content TEXT NOT NULL,
+    category VARCHAR(50),
+    tags JSONB DEFAULT '[]'::jsonb,
+    priority VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS memo_attachments (
+    id SERIAL PRIMARY KEY,
+    memo_id INTEGER NOT NULL REFERENCES memos(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    object_key VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_memo_revisions_memo_id ON memo_revisions(memo_id);
+CREATE INDEX IF NOT EXISTS idx_memo_attachments_memo_id ON memo_attachments(memo_id);
@@ -0,0 +1,5 @@
+-- ユーザーごとの最近の検索クエリ履歴テーブルを削除
+
+DROP INDEX IF EXISTS idx_search_queries_user_searched_at;
+
+DROP TABLE IF EXISTS search_queries;
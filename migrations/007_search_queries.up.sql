@@ -0,0 +1,11 @@
+-- ユーザーごとの最近の検索クエリ履歴テーブルを追加
+
+CREATE TABLE IF NOT EXISTS search_queries (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    query VARCHAR(200) NOT NULL,
+    searched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, query)
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_queries_user_searched_at ON search_queries(user_id, searched_at DESC);
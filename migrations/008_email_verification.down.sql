@@ -0,0 +1,3 @@
+-- メールアドレス確認フラグを削除
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
@@ -0,0 +1,3 @@
+-- メールアドレス確認フラグを追加
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT false;
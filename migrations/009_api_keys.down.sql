@@ -0,0 +1,4 @@
+-- APIキーテーブルを削除
+
+DROP INDEX IF EXISTS idx_api_keys_user_id;
+DROP TABLE IF EXISTS api_keys;
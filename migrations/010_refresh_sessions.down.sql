@@ -0,0 +1,4 @@
+-- リフレッシュトークン（ログインセッション）テーブルを削除
+
+DROP INDEX IF EXISTS idx_refresh_sessions_user_id;
+DROP TABLE IF EXISTS refresh_sessions;
@@ -0,0 +1,10 @@
+-- ゴミ箱の状態を削除（ゴミ箱のメモはアーカイブに戻す）
+
+UPDATE memos SET status = 'archived' WHERE status = 'trashed';
+
+DROP INDEX IF EXISTS idx_memos_trashed_at;
+
+ALTER TABLE memos DROP CONSTRAINT IF EXISTS memos_status_check;
+ALTER TABLE memos ADD CONSTRAINT memos_status_check CHECK (status IN ('active', 'archived'));
+
+ALTER TABLE memos DROP COLUMN IF EXISTS trashed_at;
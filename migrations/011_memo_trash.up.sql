@@ -0,0 +1,9 @@
+-- アーカイブとは別にゴミ箱（削除予定）の状態を追加
+-- ゴミ箱に移動した日時を記録し、完全削除はゴミ箱のメモのみを対象とする
+
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS trashed_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE memos DROP CONSTRAINT IF EXISTS memos_status_check;
+ALTER TABLE memos ADD CONSTRAINT memos_status_check CHECK (status IN ('active', 'archived', 'trashed'));
+
+CREATE INDEX IF NOT EXISTS idx_memos_trashed_at ON memos(trashed_at) WHERE trashed_at IS NOT NULL;
@@ -0,0 +1,4 @@
+-- ユーザー間のメモ共有テーブルを削除
+
+DROP INDEX IF EXISTS idx_memo_shares_shared_with_user_id;
+DROP TABLE IF EXISTS memo_shares;
@@ -0,0 +1,13 @@
+-- ユーザー間のメモ共有テーブルを追加
+-- 共有先ユーザーと付与した権限（read / write）を記録する
+
+CREATE TABLE IF NOT EXISTS memo_shares (
+    id SERIAL PRIMARY KEY,
+    memo_id INTEGER NOT NULL REFERENCES memos(id) ON DELETE CASCADE,
+    shared_with_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    permission VARCHAR(10) NOT NULL DEFAULT 'read' CHECK (permission IN ('read', 'write')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (memo_id, shared_with_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_memo_shares_shared_with_user_id ON memo_shares(shared_with_user_id);
@@ -0,0 +1,5 @@
+-- メモの期限日を削除
+
+DROP INDEX IF EXISTS idx_memos_due_date;
+
+ALTER TABLE memos DROP COLUMN IF EXISTS due_date;
@@ -0,0 +1,6 @@
+-- タスク型の利用向けにメモの期限日を追加
+-- 期限切れ（overdue）の絞り込みで期限のあるメモのみを対象にする
+
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS due_date TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_memos_due_date ON memos(due_date) WHERE due_date IS NOT NULL;
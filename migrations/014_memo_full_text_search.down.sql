@@ -0,0 +1,5 @@
+-- 全文検索用の列とインデックスを削除
+
+DROP INDEX IF EXISTS idx_memos_search_vector;
+
+ALTER TABLE memos DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,10 @@
+-- 検索の全文検索化（ILIKE ではインデックスが使えず関連度順に並べられないため）
+-- 言語に依存しないよう 'simple' 設定を使用し、タイトルの一致を本文より重く評価する
+
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(content, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_memos_search_vector ON memos USING GIN (search_vector);
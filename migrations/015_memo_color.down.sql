@@ -0,0 +1,3 @@
+-- メモの表示色を削除
+
+ALTER TABLE memos DROP COLUMN IF EXISTS color;
@@ -0,0 +1,4 @@
+-- メモの表示色（#RRGGBB）を追加
+-- 未設定の場合はNULL
+
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS color VARCHAR(7);
@@ -0,0 +1,3 @@
+-- メモのバージョンを削除
+
+ALTER TABLE memos DROP COLUMN IF EXISTS version;
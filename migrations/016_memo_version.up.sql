@@ -0,0 +1,4 @@
+-- 楽観的排他制御のためのメモのバージョンを追加
+-- 更新のたびに1ずつ増やし、更新時に期待するバージョンと一致しない場合は競合として扱う
+
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,4 @@
+-- パスワード再設定トークンテーブルを削除
+
+DROP INDEX IF EXISTS idx_password_resets_user_id;
+DROP TABLE IF EXISTS password_resets;
@@ -0,0 +1,3 @@
+-- ユーザーごとのトークンバージョンを削除
+
+ALTER TABLE users DROP COLUMN IF EXISTS token_version;
@@ -0,0 +1,4 @@
+-- ユーザーごとのトークンバージョンを追加
+-- 発行するJWTにバージョンを含め、これより古いバージョンのトークンは無効とする（全端末からのログアウト用）
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- 失効させたJWTのテーブルを削除
+
+DROP INDEX IF EXISTS idx_revoked_tokens_expires_at;
+DROP TABLE IF EXISTS revoked_tokens;
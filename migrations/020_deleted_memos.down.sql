@@ -0,0 +1,5 @@
+-- 完全削除したメモの退避先を削除
+
+DROP INDEX IF EXISTS idx_deleted_memos_deleted_at;
+DROP INDEX IF EXISTS idx_deleted_memos_user_id;
+DROP TABLE IF EXISTS deleted_memos;
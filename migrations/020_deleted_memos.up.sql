@@ -0,0 +1,26 @@
+-- 完全削除したメモの退避先（リサイクルログ）を追加
+-- 完全削除と同じ文で削除前の行を複製し、保持期間内であれば復元できるようにする
+
+CREATE TABLE IF NOT EXISTS deleted_memos (
+    id SERIAL PRIMARY KEY,
+    memo_id INTEGER NOT NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    content TEXT NOT NULL,
+    category VARCHAR(50),
+    tags JSONB DEFAULT '[]'::jsonb,
+    priority VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    pinned BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    trashed_at TIMESTAMP WITH TIME ZONE,
+    due_date TIMESTAMP WITH TIME ZONE,
+    color VARCHAR(7),
+    version INTEGER NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_deleted_memos_user_id ON deleted_memos(user_id, deleted_at DESC);
+CREATE INDEX IF NOT EXISTS idx_deleted_memos_deleted_at ON deleted_memos(deleted_at);
@@ -0,0 +1,9 @@
+-- メモのリマインダーを削除
+
+DROP INDEX IF EXISTS idx_memos_pending_reminders;
+
+ALTER TABLE deleted_memos DROP COLUMN IF EXISTS reminded;
+ALTER TABLE deleted_memos DROP COLUMN IF EXISTS remind_at;
+
+ALTER TABLE memos DROP COLUMN IF EXISTS reminded;
+ALTER TABLE memos DROP COLUMN IF EXISTS remind_at;
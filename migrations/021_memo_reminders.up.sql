@@ -0,0 +1,11 @@
+-- メモのリマインダーを追加
+-- remind_at を過ぎた未通知（reminded = false）のアクティブなメモをスケジューラーが通知し、reminded = true にする
+
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS remind_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS reminded BOOLEAN NOT NULL DEFAULT false;
+
+-- 完全削除したメモからの復元でリマインダーを失わないよう、リサイクルログにも複製する
+ALTER TABLE deleted_memos ADD COLUMN IF NOT EXISTS remind_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE deleted_memos ADD COLUMN IF NOT EXISTS reminded BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_memos_pending_reminders ON memos(remind_at) WHERE remind_at IS NOT NULL AND reminded = false;
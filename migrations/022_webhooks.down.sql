@@ -0,0 +1,4 @@
+-- Webhookのテーブルを削除
+
+DROP INDEX IF EXISTS idx_webhooks_user_id;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,3 @@
+-- ユーザーのロールを削除
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
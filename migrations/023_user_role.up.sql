@@ -0,0 +1,5 @@
+-- ユーザーのロールを追加（user または admin）
+-- 管理者用のエンドポイントは DB のロールで認可するため、降格したユーザーは発行済みのトークンでも即座にアクセスできなくなる
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user'
+    CHECK (role IN ('user', 'admin'));
@@ -0,0 +1,4 @@
+-- IPアドレスごとの登録数の期間を削除
+
+ALTER TABLE ip_registrations DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE ip_registrations DROP COLUMN IF EXISTS window_started_at;
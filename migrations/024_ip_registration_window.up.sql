@@ -0,0 +1,5 @@
+-- IPアドレスごとの登録数を期間で区切って数えるためのカラムを追加
+-- window_started_at から IP_REGISTRATION_WINDOW を過ぎると登録数を0から数え直す
+
+ALTER TABLE ip_registrations ADD COLUMN IF NOT EXISTS window_started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE ip_registrations ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
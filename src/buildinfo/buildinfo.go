@@ -0,0 +1,28 @@
+package buildinfo
+
+import "runtime"
+
+// ビルド時に -ldflags で埋め込むビルド情報（go run 等で埋め込まない場合は "dev"）
+//
+//	go build -ldflags "-X memo-app/src/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//	  -X memo-app/src/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" -o bin/memo-app src/main.go
+var (
+	Commit    = "dev" // ビルドしたgitコミット
+	BuildTime = "dev" // ビルド日時（RFC3339）
+)
+
+// Info デプロイされているビルドの情報
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get 現在のバイナリのビルド情報を返す（Goのバージョンは実行中のランタイムから取得）
+func Get() Info {
+	return Info{
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
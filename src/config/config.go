@@ -1,32 +1,74 @@
 package config
 
 import (
+	"fmt"
+	"math"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config アプリケーション設定
 type Config struct {
-	Server   ServerConfig
-	Log      LogConfig
-	S3       S3Config
-	Database DatabaseConfig
-	Auth     AuthConfig
+	Server     ServerConfig
+	Log        LogConfig
+	S3         S3Config
+	Database   DatabaseConfig
+	Auth       AuthConfig
+	Memo       MemoConfig
+	Mail       MailConfig
+	Reminder   ReminderConfig
+	Webhook    WebhookConfig
+	Attachment AttachmentConfig
 }
 
 // ServerConfig サーバー設定
 type ServerConfig struct {
-	Port string
+	Port    string
+	BaseURL string // メール内リンク等に使用する外部公開URL
+
+	SizeAlertBytes int // リクエスト/レスポンスボディがこのサイズ（バイト）を超えたら警告ログを出力（0は無効）
+
+	RateLimitRPS   float64 // クライアントごとに1秒あたり補充されるリクエスト数
+	RateLimitBurst int     // クライアントごとに連続して受け付けるリクエスト数の上限
+
+	AllowedOrigins       []string // CORSで許可するオリジン（空の場合は * を返す）
+	CORSAllowCredentials bool     // 許可したオリジンに Access-Control-Allow-Credentials: true を返すか
+
+	GzipMinSize int // このサイズ（バイト）以上のレスポンスをgzip圧縮（クライアントが対応している場合）
+
+	MaxRequestBytes int // リクエストボディの最大サイズ（バイト、インポートは別の上限）
+
+	RequestTimeout time.Duration // リクエスト処理のタイムアウト（超過時は503、エクスポートは対象外）
+
+	ShutdownTimeout time.Duration // シャットダウン時に処理中のリクエストの完了を待つ期限
+
+	SwaggerEnabled bool // /swagger/*any でAPI仕様書（Swagger UI）を公開するか
+
+	MetricsPort string // /metrics を別ポートで公開する場合のポート（空の場合はSERVER_PORTで公開）
+
+	ProblemJSONErrors bool // エラーを常にRFC 7807（application/problem+json）で返すか（false の場合は Accept で要求されたときのみ）
 }
 
 // LogConfig ログ設定
 type LogConfig struct {
-	Level          string
-	Directory      string
-	UploadEnabled  bool
-	UploadMaxAge   time.Duration
-	UploadInterval time.Duration
+	Level                     string
+	Directory                 string
+	UploadEnabled             bool
+	UploadMaxAge              time.Duration
+	UploadInterval            time.Duration
+	UploadConcurrency         int           // 同時にアップロードするファイル数の上限
+	ShutdownUploadConcurrency int           // シャットダウン時の同時アップロード数の上限（UploadConcurrency より小さい場合に適用）
+	ShutdownUploadTimeout     time.Duration // シャットダウン時のアップロード全体の期限
+	UploadRetries             int           // アップロードに失敗したファイルを再試行する回数（0は再試行しない）
+	UploadRetryBackoff        time.Duration // 最初の再試行までの待ち時間（以降は倍々）
+	UploadAlertThreshold      int           // アラートを出す定期アップロードの連続失敗回数
+	UploadAlertWebhookURL     string        // アラートをPOSTするURL（空の場合はログのみ）
+	MaxSize                   int           // ローテーションするファイルサイズ（MB）
+	MaxBackups                int           // 保持する世代数
+	MaxAge                    int           // 保持日数
+	Compress                  bool          // ローテーション済みファイルを圧縮するか
 }
 
 // S3Config S3設定
@@ -47,6 +89,13 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	MaxOpenConns    int           // 同時に開く接続数の上限
+	MaxIdleConns    int           // プールに保持するアイドル接続数の上限
+	ConnMaxLifetime time.Duration // 接続を再利用する最長期間
+
+	ReadRetries      int           // 一時的なエラー（接続断、フェイルオーバー等）で失敗した読み取りを再試行する回数（0は無効）
+	ReadRetryBackoff time.Duration // 最初の再試行までの待ち時間（以降は倍々）
 }
 
 // AuthConfig 認証設定
@@ -59,13 +108,177 @@ type AuthConfig struct {
 	GitHubRedirectURL  string
 	MaxAccountsPerIP   int
 	IPCooldownPeriod   time.Duration
+	ReservedUsernames  []string
+
+	IPRegistrationWindow time.Duration // IPアドレスごとの登録数を数える期間（0は期間で区切らず累計）
+
+	EmailVerificationExpiresIn time.Duration // メール確認トークンの有効期限
+	RequireEmailVerification   bool          // メール確認済みユーザーのみ特定の操作を許可するか
+	ReverifyEmailOnChange      bool          // メールアドレス変更時に未確認に戻して確認メールを再送するか
+	PasswordResetExpiresIn     time.Duration // パスワード再設定トークンの有効期限
+
+	GitHubOAuthBaseURL string        // GitHub OAuthエンドポイントのベースURL（GitHub Enterprise用）
+	GitHubAPIBaseURL   string        // GitHub APIのベースURL
+	OAuthMaxRetries    int           // OAuthプロバイダーへのリクエストのリトライ回数（初回を除く）
+	OAuthRetryBackoff  time.Duration // リトライ間隔の初期値（リトライごとに倍増）
+
+	MaxSessionsPerUser int // ユーザーごとの有効なセッション（リフレッシュトークン）数の上限（0は無制限）
+
+	RevokedTokenCleanupInterval time.Duration // 有効期限を過ぎた失効トークンの記録を削除する間隔
+
+	LoginMaxAttempts      int           // 同じメールアドレス・IPの組で許容するログイン失敗回数（0は無制限）
+	LoginMaxAttemptsPerIP int           // 同じIPから許容するログイン失敗回数（0は無制限）
+	LoginAttemptWindow    time.Duration // ログイン失敗回数を数える期間
+	LoginLockoutDuration  time.Duration // 失敗回数の上限に達した後にログインを拒否する期間
+
+	AdminToken string // 管理者用APIで要求するトークン（X-Admin-Tokenヘッダー、空の場合は管理者用APIを無効化。認証システム有効化までの暫定措置）
+}
+
+// MailConfig メール送信設定
+type MailConfig struct {
+	Driver       string // log（開発用、ログ出力のみ）または smtp
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// ReminderConfig リマインダー通知設定
+type ReminderConfig struct {
+	PollInterval time.Duration // 通知時刻を過ぎたリマインダーを確認する間隔
+	Notifier     string        // log（開発用、ログ出力のみ）または webhook
+	WebhookURL   string        // Notifier=webhook の場合の送信先
+}
+
+// WebhookConfig メモのイベントを通知するWebhookの送信設定
+type WebhookConfig struct {
+	Workers      int           // 送信するワーカーの数
+	QueueSize    int           // 送信待ちのキューの上限（満杯の場合はイベントを破棄）
+	MaxAttempts  int           // 1件の送信の最大試行回数（初回を含む）
+	RetryBackoff time.Duration // 再送までの初回の待ち時間（再送ごとに2倍）
+	Timeout      time.Duration // 1回の送信のタイムアウト
+}
+
+// AttachmentConfig メモの添付ファイル設定（ファイルはS3設定の接続先の Bucket に保存）
+type AttachmentConfig struct {
+	Enabled      bool          // 添付ファイルのAPIを有効にするか
+	Bucket       string        // 添付ファイルを保存するバケット
+	MaxSize      int64         // 1ファイルの最大サイズ（バイト）
+	AllowedTypes []string      // アップロードを許可するContent-Type（ファイルの内容から判定）
+	URLExpiry    time.Duration // ダウンロード用の署名付きURLの有効期限
+}
+
+// DefaultAttachmentConfig 添付ファイル設定のデフォルト値を返す
+func DefaultAttachmentConfig() *AttachmentConfig {
+	return &AttachmentConfig{
+		Bucket:       "memo-app-attachments",
+		MaxSize:      10 << 20,
+		AllowedTypes: []string{"image/png", "image/jpeg", "image/gif", "image/webp", "application/pdf"},
+		URLExpiry:    15 * time.Minute,
+	}
+}
+
+// MemoConfig メモAPI設定
+type MemoConfig struct {
+	PromotePriority         string        // promote時に設定する優先度
+	CaseInsensitiveCategory bool          // カテゴリーを大文字小文字を区別せずに扱うか
+	CaseInsensitiveTags     bool          // タグを小文字に正規化して保存・集計するか
+	StrictQueryParams       bool          // 不明なクエリパラメータを400で拒否するか
+	RecentQueriesLimit      int           // ユーザーごとに保持する最近の検索クエリ数
+	EmptyListStatus         int           // 一覧結果が空の場合のステータス（200 または 204）
+	ContentMaxLength        int           // 本文の最大文字数（超過時は拒否）
+	ContentSoftLimit        int           // 本文の推奨最大文字数（超過時は受け付けて警告を返す）
+	TagsDefaultLimit        int           // タグ一覧で返すタグ数のデフォルト値
+	TagsMaxLimit            int           // タグ一覧で返すタグ数の上限
+	MaxTagsPerMemo          int           // 1件のメモに付けられるタグ数の上限
+	DefaultPageSize         int           // 一覧で ?limit= を省略した場合の件数
+	MaxPageSize             int           // 一覧で ?limit= に指定できる最大値
+	ClampPageSize           bool          // 最大値を超える ?limit= を400で拒否せず最大値に切り詰めるか
+	LogValidationRejects    bool          // 攻撃の可能性がある入力の拒否を構造化ログに記録するか
+	TrashRetention          time.Duration // ゴミ箱のメモを完全に削除するまでの保持期間
+	TrashPurgeInterval      time.Duration // ゴミ箱の定期削除の実行間隔
+	DeletedRetention        time.Duration // 完全削除したメモをリサイクルログから復元できる期間
+	ProtectedCategories     []string      // 最後のactiveなメモのアーカイブ・削除を禁止するカテゴリー
+	ValidationErrorStatus   int           // 入力検証エラーのステータス（400 または 422。不正なJSONは常に400）
+	RevealMemoOwnership     bool          // 他ユーザーのメモへのアクセスを404ではなく403で返すか（管理・デバッグ用。メモの存在が漏れる）
+}
+
+// DefaultMemoConfig メモAPI設定のデフォルト値を返す
+func DefaultMemoConfig() *MemoConfig {
+	return &MemoConfig{
+		PromotePriority:       "high",
+		RecentQueriesLimit:    10,
+		EmptyListStatus:       200,
+		ContentMaxLength:      10000,
+		ContentSoftLimit:      8000,
+		TagsDefaultLimit:      100,
+		TagsMaxLimit:          1000,
+		MaxTagsPerMemo:        20,
+		DefaultPageSize:       10,
+		MaxPageSize:           100,
+		TrashRetention:        30 * 24 * time.Hour,
+		TrashPurgeInterval:    1 * time.Hour,
+		DeletedRetention:      30 * 24 * time.Hour,
+		ValidationErrorStatus: 400,
+	}
+}
+
+// PageSizes 一覧のデフォルト件数と最大件数を返す（未設定の場合はデフォルト値。デフォルト件数は最大件数以下）
+func (c *MemoConfig) PageSizes() (defaultSize, maxSize int) {
+	defaults := DefaultMemoConfig()
+	defaultSize, maxSize = c.DefaultPageSize, c.MaxPageSize
+	if maxSize <= 0 {
+		maxSize = defaults.MaxPageSize
+	}
+	if defaultSize <= 0 {
+		defaultSize = defaults.DefaultPageSize
+	}
+	if defaultSize > maxSize {
+		defaultSize = maxSize
+	}
+	return defaultSize, maxSize
+}
+
+// TagsPerMemoLimit 1件のメモに付けられるタグ数の上限を返す（未設定の場合はデフォルト値）
+func (c *MemoConfig) TagsPerMemoLimit() int {
+	if c.MaxTagsPerMemo <= 0 {
+		return DefaultMemoConfig().MaxTagsPerMemo
+	}
+	return c.MaxTagsPerMemo
 }
 
 // LoadConfig 環境変数から設定を読み込み
 func LoadConfig() *Config {
+	memoDefaults := DefaultMemoConfig()
+	attachmentDefaults := DefaultAttachmentConfig()
+
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8000"),
+			Port:    getEnv("SERVER_PORT", "8000"),
+			BaseURL: getEnv("APP_BASE_URL", "http://localhost:8000"),
+
+			SizeAlertBytes: getIntEnv("METRICS_SIZE_ALERT_BYTES", 0),
+
+			RateLimitRPS:   getFloatEnv("RATE_LIMIT_RPS", 10),
+			RateLimitBurst: getIntEnv("RATE_LIMIT_BURST", 20),
+
+			AllowedOrigins:       getSliceEnv("ALLOWED_ORIGINS", nil),
+			CORSAllowCredentials: getBoolEnv("CORS_ALLOW_CREDENTIALS", false),
+
+			GzipMinSize: getIntEnv("GZIP_MIN_SIZE", 1024),
+
+			MaxRequestBytes: getIntEnv("MAX_REQUEST_BYTES", 1<<20),
+
+			RequestTimeout: getDurationEnv("REQUEST_TIMEOUT", 30*time.Second),
+
+			ShutdownTimeout: getDurationEnv("SHUTDOWN_TIMEOUT", 30*time.Second),
+
+			SwaggerEnabled: getBoolEnv("SWAGGER_ENABLED", false),
+
+			MetricsPort: getEnv("METRICS_PORT", ""),
+
+			ProblemJSONErrors: getBoolEnv("PROBLEM_JSON_ERRORS", false),
 		},
 		Log: LogConfig{
 			Level:          getEnv("LOG_LEVEL", "info"),
@@ -73,6 +286,19 @@ func LoadConfig() *Config {
 			UploadEnabled:  getBoolEnv("LOG_UPLOAD_ENABLED", true),
 			UploadMaxAge:   getDurationEnv("LOG_UPLOAD_MAX_AGE", 24*time.Hour),
 			UploadInterval: getDurationEnv("LOG_UPLOAD_INTERVAL", 1*time.Hour),
+
+			UploadConcurrency:         getIntEnv("LOG_UPLOAD_CONCURRENCY", 4),
+			ShutdownUploadConcurrency: getIntEnv("LOG_UPLOAD_SHUTDOWN_CONCURRENCY", 2),
+			ShutdownUploadTimeout:     getDurationEnv("LOG_UPLOAD_SHUTDOWN_TIMEOUT", 10*time.Second),
+			UploadRetries:             getIntEnv("LOG_UPLOAD_RETRIES", 3),
+			UploadRetryBackoff:        getDurationEnv("LOG_UPLOAD_RETRY_BACKOFF", 2*time.Second),
+			UploadAlertThreshold:      getIntEnv("LOG_UPLOAD_ALERT_THRESHOLD", 3),
+			UploadAlertWebhookURL:     getEnv("LOG_UPLOAD_ALERT_WEBHOOK_URL", ""),
+
+			MaxSize:    getIntEnv("LOG_MAX_SIZE", 100),
+			MaxBackups: getIntEnv("LOG_MAX_BACKUPS", 3),
+			MaxAge:     getIntEnv("LOG_MAX_AGE", 28),
+			Compress:   getBoolEnv("LOG_COMPRESS", false),
 		},
 		S3: S3Config{
 			Endpoint:        getEnv("S3_ENDPOINT", "http://localhost:9000"), // MinIO用のデフォルト
@@ -89,6 +315,13 @@ func LoadConfig() *Config {
 			Password: getEnv("DB_PASSWORD", "password"),
 			DBName:   getEnv("DB_NAME", "memo_app"),
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+
+			MaxOpenConns:    getIntEnv("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    getIntEnv("DB_MAX_IDLE_CONNS", 25),
+			ConnMaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+
+			ReadRetries:      getIntEnv("DB_READ_RETRIES", 0),
+			ReadRetryBackoff: getDurationEnv("DB_READ_RETRY_BACKOFF", 100*time.Millisecond),
 		},
 		Auth: AuthConfig{
 			JWTSecret:          getEnv("JWT_SECRET", "your-super-secret-jwt-key-change-in-production"),
@@ -99,8 +332,459 @@ func LoadConfig() *Config {
 			GitHubRedirectURL:  getEnv("GITHUB_REDIRECT_URL", "http://localhost:3000/auth/github/callback"),
 			MaxAccountsPerIP:   getIntEnv("MAX_ACCOUNTS_PER_IP", 3),
 			IPCooldownPeriod:   getDurationEnv("IP_COOLDOWN_PERIOD", 24*time.Hour),
+			ReservedUsernames:  getSliceEnv("RESERVED_USERNAMES", nil),
+
+			IPRegistrationWindow: getDurationEnv("IP_REGISTRATION_WINDOW", 30*24*time.Hour),
+
+			EmailVerificationExpiresIn: getDurationEnv("EMAIL_VERIFICATION_EXPIRES_IN", 24*time.Hour),
+			RequireEmailVerification:   getBoolEnv("REQUIRE_EMAIL_VERIFICATION", false),
+			ReverifyEmailOnChange:      getBoolEnv("REVERIFY_EMAIL_ON_CHANGE", true),
+			PasswordResetExpiresIn:     getDurationEnv("PASSWORD_RESET_EXPIRES_IN", 1*time.Hour),
+
+			GitHubOAuthBaseURL: getEnv("GITHUB_OAUTH_BASE_URL", "https://github.com"),
+			GitHubAPIBaseURL:   getEnv("GITHUB_API_BASE_URL", "https://api.github.com"),
+			OAuthMaxRetries:    getIntEnv("OAUTH_MAX_RETRIES", 2),
+			OAuthRetryBackoff:  getDurationEnv("OAUTH_RETRY_BACKOFF", 500*time.Millisecond),
+
+			MaxSessionsPerUser: getIntEnv("MAX_SESSIONS_PER_USER", 0),
+
+			RevokedTokenCleanupInterval: getDurationEnv("REVOKED_TOKEN_CLEANUP_INTERVAL", 1*time.Hour),
+
+			LoginMaxAttempts:      getIntEnv("LOGIN_MAX_ATTEMPTS", 5),
+			LoginMaxAttemptsPerIP: getIntEnv("LOGIN_MAX_ATTEMPTS_PER_IP", 20),
+			LoginAttemptWindow:    getDurationEnv("LOGIN_ATTEMPT_WINDOW", 15*time.Minute),
+			LoginLockoutDuration:  getDurationEnv("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+
+			AdminToken: getEnv("ADMIN_TOKEN", ""),
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "noreply@memo-app.local"),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getIntEnv("SMTP_PORT", 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		},
+		Reminder: ReminderConfig{
+			PollInterval: getDurationEnv("REMINDER_POLL_INTERVAL", time.Minute),
+			Notifier:     getEnv("REMINDER_NOTIFIER", "log"),
+			WebhookURL:   getEnv("REMINDER_WEBHOOK_URL", ""),
+		},
+		Webhook: WebhookConfig{
+			Workers:      getIntEnv("WEBHOOK_WORKERS", 4),
+			QueueSize:    getIntEnv("WEBHOOK_QUEUE_SIZE", 1000),
+			MaxAttempts:  getIntEnv("WEBHOOK_MAX_ATTEMPTS", 5),
+			RetryBackoff: getDurationEnv("WEBHOOK_RETRY_BACKOFF", time.Second),
+			Timeout:      getDurationEnv("WEBHOOK_TIMEOUT", 10*time.Second),
 		},
+		Attachment: AttachmentConfig{
+			Enabled:      getBoolEnv("ATTACHMENTS_ENABLED", false),
+			Bucket:       getEnv("ATTACHMENT_S3_BUCKET", attachmentDefaults.Bucket),
+			MaxSize:      int64(getIntEnv("ATTACHMENT_MAX_SIZE", int(attachmentDefaults.MaxSize))),
+			AllowedTypes: getSliceEnv("ATTACHMENT_ALLOWED_TYPES", attachmentDefaults.AllowedTypes),
+			URLExpiry:    getDurationEnv("ATTACHMENT_URL_EXPIRY", attachmentDefaults.URLExpiry),
+		},
+		Memo: MemoConfig{
+			PromotePriority:         getEnv("MEMO_PROMOTE_PRIORITY", memoDefaults.PromotePriority),
+			CaseInsensitiveCategory: getBoolEnv("CASE_INSENSITIVE_CATEGORY", memoDefaults.CaseInsensitiveCategory),
+			CaseInsensitiveTags:     getBoolEnv("CASE_INSENSITIVE_TAGS", memoDefaults.CaseInsensitiveTags),
+			StrictQueryParams:       getBoolEnv("STRICT_QUERY_PARAMS", memoDefaults.StrictQueryParams),
+			RecentQueriesLimit:      getIntEnv("RECENT_SEARCH_QUERIES_LIMIT", memoDefaults.RecentQueriesLimit),
+			EmptyListStatus:         getIntEnv("EMPTY_LIST_STATUS", memoDefaults.EmptyListStatus),
+			ContentMaxLength:        getIntEnv("MEMO_CONTENT_MAX_LENGTH", memoDefaults.ContentMaxLength),
+			ContentSoftLimit:        getIntEnv("MEMO_CONTENT_SOFT_LIMIT", memoDefaults.ContentSoftLimit),
+			TagsDefaultLimit:        memoDefaults.TagsDefaultLimit,
+			TagsMaxLimit:            getIntEnv("TAGS_MAX_LIMIT", memoDefaults.TagsMaxLimit),
+			MaxTagsPerMemo:          getIntEnv("MAX_TAGS_PER_MEMO", memoDefaults.MaxTagsPerMemo),
+			DefaultPageSize:         getIntEnv("DEFAULT_PAGE_SIZE", memoDefaults.DefaultPageSize),
+			MaxPageSize:             getIntEnv("MAX_PAGE_SIZE", memoDefaults.MaxPageSize),
+			ClampPageSize:           getBoolEnv("CLAMP_PAGE_SIZE", memoDefaults.ClampPageSize),
+			LogValidationRejects:    getBoolEnv("LOG_VALIDATION_REJECTS", memoDefaults.LogValidationRejects),
+			TrashRetention:          getDurationEnv("MEMO_TRASH_RETENTION", memoDefaults.TrashRetention),
+			TrashPurgeInterval:      getDurationEnv("MEMO_TRASH_PURGE_INTERVAL", memoDefaults.TrashPurgeInterval),
+			DeletedRetention:        getDurationEnv("MEMO_DELETED_RETENTION", memoDefaults.DeletedRetention),
+			ProtectedCategories:     getSliceEnv("PROTECTED_CATEGORIES", memoDefaults.ProtectedCategories),
+			ValidationErrorStatus:   getIntEnv("VALIDATION_ERROR_STATUS", memoDefaults.ValidationErrorStatus),
+			RevealMemoOwnership:     getBoolEnv("MEMO_REVEAL_OWNERSHIP", memoDefaults.RevealMemoOwnership),
+		},
+	}
+}
+
+// Validate 起動時に設定値を検証
+// 環境変数が不正な値の場合、デフォルト値へ黙ってフォールバックせずエラーを返す
+func (c *Config) Validate() error {
+	var errs []string
+
+	// ログローテーション設定（正の整数）
+	for _, key := range []string{"LOG_MAX_SIZE", "LOG_MAX_BACKUPS", "LOG_MAX_AGE"} {
+		if err := validatePositiveIntEnv(key); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if err := validateBoolEnv("LOG_COMPRESS"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if c.Log.MaxSize <= 0 {
+		errs = append(errs, fmt.Sprintf("Log.MaxSize は正の整数である必要があります: %d", c.Log.MaxSize))
+	}
+	if c.Log.MaxBackups <= 0 {
+		errs = append(errs, fmt.Sprintf("Log.MaxBackups は正の整数である必要があります: %d", c.Log.MaxBackups))
+	}
+	if c.Log.MaxAge <= 0 {
+		errs = append(errs, fmt.Sprintf("Log.MaxAge は正の整数である必要があります: %d", c.Log.MaxAge))
+	}
+
+	// 空の一覧のステータス
+	if err := validatePositiveIntEnv("EMPTY_LIST_STATUS"); err != nil {
+		errs = append(errs, err.Error())
+	} else if c.Memo.EmptyListStatus != 200 && c.Memo.EmptyListStatus != 204 {
+		errs = append(errs, fmt.Sprintf("EMPTY_LIST_STATUS は 200 または 204 である必要があります: %d", c.Memo.EmptyListStatus))
+	}
+
+	// 本文の文字数制限（推奨上限は最大文字数より小さくする）
+	contentLimitErr := false
+	for _, key := range []string{"MEMO_CONTENT_MAX_LENGTH", "MEMO_CONTENT_SOFT_LIMIT"} {
+		if err := validatePositiveIntEnv(key); err != nil {
+			errs = append(errs, err.Error())
+			contentLimitErr = true
+		}
+	}
+	if !contentLimitErr && c.Memo.ContentSoftLimit >= c.Memo.ContentMaxLength {
+		errs = append(errs, fmt.Sprintf("MEMO_CONTENT_SOFT_LIMIT (%d) は MEMO_CONTENT_MAX_LENGTH (%d) より小さい必要があります",
+			c.Memo.ContentSoftLimit, c.Memo.ContentMaxLength))
+	}
+
+	for _, key := range []string{"LOG_VALIDATION_REJECTS", "CASE_INSENSITIVE_TAGS", "MEMO_REVEAL_OWNERSHIP", "REVERIFY_EMAIL_ON_CHANGE"} {
+		if err := validateBoolEnv(key); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	// 入力検証エラーのステータス
+	if err := validatePositiveIntEnv("VALIDATION_ERROR_STATUS"); err != nil {
+		errs = append(errs, err.Error())
+	} else if c.Memo.ValidationErrorStatus != 400 && c.Memo.ValidationErrorStatus != 422 {
+		errs = append(errs, fmt.Sprintf("VALIDATION_ERROR_STATUS は 400 または 422 である必要があります: %d", c.Memo.ValidationErrorStatus))
+	}
+
+	// タグ一覧の上限・1件のメモのタグ数の上限
+	for _, key := range []string{"TAGS_MAX_LIMIT", "MAX_TAGS_PER_MEMO"} {
+		if err := validatePositiveIntEnv(key); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	// 一覧のページサイズ（デフォルト件数は最大件数以下）
+	pageSizeValid := true
+	for _, key := range []string{"DEFAULT_PAGE_SIZE", "MAX_PAGE_SIZE"} {
+		if err := validatePositiveIntEnv(key); err != nil {
+			errs = append(errs, err.Error())
+			pageSizeValid = false
+		}
+	}
+	if pageSizeValid && c.Memo.DefaultPageSize > c.Memo.MaxPageSize {
+		errs = append(errs, fmt.Sprintf("DEFAULT_PAGE_SIZE は MAX_PAGE_SIZE 以下である必要があります: %d > %d", c.Memo.DefaultPageSize, c.Memo.MaxPageSize))
+	}
+	if err := validateBoolEnv("CLAMP_PAGE_SIZE"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// ゴミ箱・リサイクルログの保持期間と定期削除の間隔（正の期間）
+	for _, key := range []string{"MEMO_TRASH_RETENTION", "MEMO_TRASH_PURGE_INTERVAL", "MEMO_DELETED_RETENTION"} {
+		if err := validatePositiveDurationEnv(key); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	// ログアップロードの同時実行数とシャットダウン時の期限
+	for _, key := range []string{"LOG_UPLOAD_CONCURRENCY", "LOG_UPLOAD_SHUTDOWN_CONCURRENCY"} {
+		if err := validatePositiveIntEnv(key); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if err := validatePositiveDurationEnv("LOG_UPLOAD_SHUTDOWN_TIMEOUT"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// ログアップロードの再試行と連続失敗のアラート
+	if err := validateNonNegativeIntEnv("LOG_UPLOAD_RETRIES"); err != nil {
+		errs = append(errs, err.Error())
 	}
+	if err := validatePositiveDurationEnv("LOG_UPLOAD_RETRY_BACKOFF"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := validatePositiveIntEnv("LOG_UPLOAD_ALERT_THRESHOLD"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if url := c.Log.UploadAlertWebhookURL; url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		errs = append(errs, fmt.Sprintf("LOG_UPLOAD_ALERT_WEBHOOK_URL は http:// または https:// で始まるURLである必要があります: %q", url))
+	}
+
+	// ボディサイズの警告閾値（0は無効）とgzip圧縮の最小サイズ
+	for _, key := range []string{"METRICS_SIZE_ALERT_BYTES", "GZIP_MIN_SIZE"} {
+		if err := validateNonNegativeIntEnv(key); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	// リクエストボディの最大サイズ
+	if err := validatePositiveIntEnv("MAX_REQUEST_BYTES"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// リクエスト処理のタイムアウト
+	if err := validatePositiveDurationEnv("REQUEST_TIMEOUT"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// シャットダウン時に処理中のリクエストを待つ期限
+	if err := validatePositiveDurationEnv("SHUTDOWN_TIMEOUT"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// パスワード再設定トークンの有効期限
+	if err := validatePositiveDurationEnv("PASSWORD_RESET_EXPIRES_IN"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// IPアドレスごとの登録数を数える期間（0は累計）
+	if err := validateNonNegativeDurationEnv("IP_REGISTRATION_WINDOW"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// レート制限（正の値）
+	if err := validatePositiveFloatEnv("RATE_LIMIT_RPS"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := validatePositiveIntEnv("RATE_LIMIT_BURST"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// CORSの許可オリジン（スキーム付きのオリジン。資格情報の送信は許可リストがある場合のみ）
+	for _, origin := range c.Server.AllowedOrigins {
+		if !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			errs = append(errs, fmt.Sprintf("ALLOWED_ORIGINS には http:// または https:// で始まるオリジンを指定してください: %q", origin))
+		}
+	}
+	if err := validateBoolEnv("CORS_ALLOW_CREDENTIALS"); err != nil {
+		errs = append(errs, err.Error())
+	} else if c.Server.CORSAllowCredentials && len(c.Server.AllowedOrigins) == 0 {
+		errs = append(errs, "CORS_ALLOW_CREDENTIALS=true の場合は ALLOWED_ORIGINS の設定が必要です")
+	}
+
+	// API仕様書の公開
+	if err := validateBoolEnv("SWAGGER_ENABLED"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// RFC 7807 形式のエラーレスポンス
+	if err := validateBoolEnv("PROBLEM_JSON_ERRORS"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// メトリクスの公開ポート（空の場合はSERVER_PORTで公開）
+	if c.Server.MetricsPort != "" {
+		if port, err := strconv.Atoi(c.Server.MetricsPort); err != nil || port < 1 || port > 65535 {
+			errs = append(errs, fmt.Sprintf("METRICS_PORT は1〜65535のポート番号である必要があります: %q", c.Server.MetricsPort))
+		} else if c.Server.MetricsPort == c.Server.Port {
+			errs = append(errs, fmt.Sprintf("METRICS_PORT には SERVER_PORT と異なるポートを指定してください: %s", c.Server.MetricsPort))
+		}
+	}
+
+	// DBの接続プール（アイドル接続数は最大接続数以下）
+	poolValid := true
+	for _, key := range []string{"DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS"} {
+		if err := validatePositiveIntEnv(key); err != nil {
+			errs = append(errs, err.Error())
+			poolValid = false
+		}
+	}
+	if poolValid && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, fmt.Sprintf("DB_MAX_IDLE_CONNS は DB_MAX_OPEN_CONNS 以下である必要があります: %d > %d", c.Database.MaxIdleConns, c.Database.MaxOpenConns))
+	}
+	if err := validatePositiveDurationEnv("DB_CONN_MAX_LIFETIME"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// 一時的なDBエラーでの読み取りの再試行（0は無効）
+	if err := validateNonNegativeIntEnv("DB_READ_RETRIES"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := validatePositiveDurationEnv("DB_READ_RETRY_BACKOFF"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// セッション数の上限（0は無制限）
+	if err := validateNonNegativeIntEnv("MAX_SESSIONS_PER_USER"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// 失効トークンの定期削除の間隔
+	if err := validatePositiveDurationEnv("REVOKED_TOKEN_CLEANUP_INTERVAL"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// ログイン試行制限（回数は0で無効）
+	for _, key := range []string{"LOGIN_MAX_ATTEMPTS", "LOGIN_MAX_ATTEMPTS_PER_IP"} {
+		if err := validateNonNegativeIntEnv(key); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	for _, key := range []string{"LOGIN_ATTEMPT_WINDOW", "LOGIN_LOCKOUT_DURATION"} {
+		if err := validatePositiveDurationEnv(key); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	// メール送信設定
+	switch c.Mail.Driver {
+	case "log":
+	case "smtp":
+		if c.Mail.SMTPHost == "" {
+			errs = append(errs, "MAIL_DRIVER=smtp の場合は SMTP_HOST の設定が必要です")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("MAIL_DRIVER は log または smtp である必要があります: %q", c.Mail.Driver))
+	}
+
+	// リマインダー通知
+	if err := validatePositiveDurationEnv("REMINDER_POLL_INTERVAL"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	switch c.Reminder.Notifier {
+	case "log":
+	case "webhook":
+		if !strings.HasPrefix(c.Reminder.WebhookURL, "http://") && !strings.HasPrefix(c.Reminder.WebhookURL, "https://") {
+			errs = append(errs, fmt.Sprintf("REMINDER_NOTIFIER=webhook の場合は REMINDER_WEBHOOK_URL に http:// または https:// で始まるURLが必要です: %q", c.Reminder.WebhookURL))
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("REMINDER_NOTIFIER は log または webhook である必要があります: %q", c.Reminder.Notifier))
+	}
+
+	// Webhookの送信
+	for _, key := range []string{"WEBHOOK_WORKERS", "WEBHOOK_QUEUE_SIZE", "WEBHOOK_MAX_ATTEMPTS"} {
+		if err := validatePositiveIntEnv(key); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	for _, key := range []string{"WEBHOOK_RETRY_BACKOFF", "WEBHOOK_TIMEOUT"} {
+		if err := validatePositiveDurationEnv(key); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	// 添付ファイル（署名付きURLの有効期限はS3の上限の7日以内）
+	if err := validateBoolEnv("ATTACHMENTS_ENABLED"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := validatePositiveIntEnv("ATTACHMENT_MAX_SIZE"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := validatePositiveDurationEnv("ATTACHMENT_URL_EXPIRY"); err != nil {
+		errs = append(errs, err.Error())
+	} else if c.Attachment.URLExpiry > 7*24*time.Hour {
+		errs = append(errs, fmt.Sprintf("ATTACHMENT_URL_EXPIRY は7日以下である必要があります: %s", c.Attachment.URLExpiry))
+	}
+	if c.Attachment.Enabled && c.Attachment.Bucket == "" {
+		errs = append(errs, "ATTACHMENTS_ENABLED=true の場合は ATTACHMENT_S3_BUCKET が必要です")
+	}
+	if c.Attachment.Enabled && len(c.Attachment.AllowedTypes) == 0 {
+		errs = append(errs, "ATTACHMENTS_ENABLED=true の場合は ATTACHMENT_ALLOWED_TYPES が必要です")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("設定が不正です: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validatePositiveIntEnv 環境変数が設定されている場合、正の整数か検証
+func validatePositiveIntEnv(key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return fmt.Errorf("%s は正の整数である必要があります: %q", key, value)
+	}
+	return nil
+}
+
+// validateNonNegativeIntEnv 環境変数が設定されている場合、0以上の整数か検証
+func validateNonNegativeIntEnv(key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return fmt.Errorf("%s は0以上の整数である必要があります: %q", key, value)
+	}
+	return nil
+}
+
+// validatePositiveFloatEnv 環境変数が設定されている場合、正の数か検証
+func validatePositiveFloatEnv(key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed <= 0 || math.IsInf(parsed, 0) || math.IsNaN(parsed) {
+		return fmt.Errorf("%s は正の数である必要があります: %q", key, value)
+	}
+	return nil
+}
+
+// validatePositiveDurationEnv 環境変数が設定されている場合、正の期間（例: 720h）か検証
+func validatePositiveDurationEnv(key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		return fmt.Errorf("%s は正の期間（例: 720h）である必要があります: %q", key, value)
+	}
+	return nil
+}
+
+// validateNonNegativeDurationEnv 環境変数が設定されている場合、0以上の期間（例: 720h）か検証
+func validateNonNegativeDurationEnv(key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return fmt.Errorf("%s は0以上の期間（例: 720h）である必要があります: %q", key, value)
+	}
+	return nil
+}
+
+// validateBoolEnv 環境変数が設定されている場合、boolとして解釈できるか検証
+func validateBoolEnv(key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("%s は true/false である必要があります: %q", key, value)
+	}
+	return nil
+}
+
+// IsProduction ENV が production の場合にtrueを返す
+func IsProduction() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv("ENV")), "production")
+}
+
+// AllowNonDocker Docker外での実行を許可するか判定
+// ALLOW_NON_DOCKER=true かつ本番環境でない場合のみ許可する（本番のガードは常に有効）
+func AllowNonDocker() bool {
+	return getBoolEnv("ALLOW_NON_DOCKER", false) && !IsProduction()
 }
 
 // getEnv 環境変数を取得（デフォルト値付き）
@@ -131,6 +815,16 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getFloatEnv 環境変数をfloat64で取得
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
 // getDurationEnv 環境変数をtime.Durationで取得
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
@@ -140,3 +834,19 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+// getSliceEnv 環境変数をカンマ区切りの[]stringで取得
+func getSliceEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
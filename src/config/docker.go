@@ -0,0 +1,59 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// Docker環境と判定した理由
+const (
+	DockerReasonEnv       = "DOCKER_CONTAINER=true"
+	DockerReasonCgroup    = "cgroup"
+	DockerReasonDockerEnv = ".dockerenv"
+)
+
+// DockerProbe Docker環境の検出に使用する入力（テストでは一時ディレクトリのファイルに差し替える）
+type DockerProbe struct {
+	Getenv        func(key string) string
+	CgroupPath    string // 通常は /proc/self/cgroup
+	DockerEnvPath string // 通常は /.dockerenv
+}
+
+// DefaultDockerProbe 実行中のプロセスを対象とするDockerProbeを返す
+func DefaultDockerProbe() DockerProbe {
+	return DockerProbe{
+		Getenv:        os.Getenv,
+		CgroupPath:    "/proc/self/cgroup",
+		DockerEnvPath: "/.dockerenv",
+	}
+}
+
+// DetectDocker Dockerコンテナ内で実行されているかを判定し、その理由を返す
+// プロセスを終了させることはないため、判定結果の扱い（終了・警告）は呼び出し側で行う
+func DetectDocker(probe DockerProbe) (bool, string) {
+	// 環境変数でDocker環境を明示的にチェック
+	if probe.Getenv != nil && probe.Getenv("DOCKER_CONTAINER") == "true" {
+		return true, DockerReasonEnv
+	}
+
+	// Linuxの場合、cgroupファイルでDockerを検出
+	if file, err := os.Open(probe.CgroupPath); err == nil {
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.Contains(line, "docker") || strings.Contains(line, "containerd") {
+				return true, DockerReasonCgroup
+			}
+		}
+	}
+
+	// .dockerenvファイルの存在チェック（Docker特有）
+	if _, err := os.Stat(probe.DockerEnvPath); err == nil {
+		return true, DockerReasonDockerEnv
+	}
+
+	return false, ""
+}
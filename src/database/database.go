@@ -14,8 +14,18 @@ import (
 type DB struct {
 	*sql.DB
 	logger *logrus.Logger
+
+	// ReadRetry controls how repositories retry idempotent reads on transient errors (disabled when zero)
+	ReadRetry RetryConfig
 }
 
+// Default connection pool settings, used when the corresponding Config field is zero
+const (
+	DefaultMaxOpenConns    = 25
+	DefaultMaxIdleConns    = 25
+	DefaultConnMaxLifetime = 5 * time.Minute
+)
+
 // Config represents database configuration
 type Config struct {
 	Host     string
@@ -24,6 +34,26 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	MaxOpenConns    int           // 同時に開く接続数の上限
+	MaxIdleConns    int           // プールに保持するアイドル接続数の上限（MaxOpenConns を超える場合は MaxOpenConns）
+	ConnMaxLifetime time.Duration // 接続を再利用する最長期間
+
+	ReadRetries      int           // 一時的なエラーで失敗した読み取りを再試行する回数（0は再試行しない）
+	ReadRetryBackoff time.Duration // 最初の再試行までの待ち時間
+}
+
+// PoolStats is a JSON-friendly snapshot of the connection pool statistics
+type PoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
 }
 
 // NewDB creates a new database connection
@@ -41,19 +71,97 @@ func NewDB(config *Config, logger *logrus.Logger) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	// 接続プールの設定
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	// 接続プールの設定（未設定の項目はデフォルト値）
+	maxOpen, maxIdle, maxLifetime := poolSettings(config)
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	db.SetConnMaxLifetime(maxLifetime)
 
-	logger.Info("データベースに接続しました")
+	logger.WithFields(logrus.Fields{
+		"max_open_conns":    maxOpen,
+		"max_idle_conns":    maxIdle,
+		"conn_max_lifetime": maxLifetime,
+		"read_retries":      config.ReadRetries,
+	}).Info("データベースに接続しました")
 
 	return &DB{
 		DB:     db,
 		logger: logger,
+		ReadRetry: RetryConfig{
+			MaxRetries: config.ReadRetries,
+			Backoff:    config.ReadRetryBackoff,
+		},
 	}, nil
 }
 
+// poolSettings returns the effective pool settings of config. database/sql never keeps more idle
+// connections than it may open, so MaxIdleConns is capped at MaxOpenConns here as well
+func poolSettings(config *Config) (maxOpen, maxIdle int, maxLifetime time.Duration) {
+	maxOpen, maxIdle, maxLifetime = config.MaxOpenConns, config.MaxIdleConns, config.ConnMaxLifetime
+	if maxOpen <= 0 {
+		maxOpen = DefaultMaxOpenConns
+	}
+	if maxIdle <= 0 {
+		maxIdle = DefaultMaxIdleConns
+	}
+	if maxIdle > maxOpen {
+		maxIdle = maxOpen
+	}
+	if maxLifetime <= 0 {
+		maxLifetime = DefaultConnMaxLifetime
+	}
+	return maxOpen, maxIdle, maxLifetime
+}
+
+// PoolStats returns the current connection pool statistics
+func (db *DB) PoolStats() PoolStats {
+	stats := db.Stats()
+	return PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+}
+
+// Querier is the set of statement methods shared by *sql.DB and *sql.Tx
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// WithTx runs fn in a transaction. The transaction is committed when fn returns nil and rolled back
+// when it returns an error or panics; fn's error is returned as is
+func (db *DB) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
 	db.logger.Info("データベース接続を閉じています")
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// maxRetryBackoff caps the exponential backoff between retries
+const maxRetryBackoff = 5 * time.Second
+
+// RetryConfig controls how idempotent reads are retried on transient errors
+type RetryConfig struct {
+	MaxRetries int           // 失敗後に再試行する回数（0は再試行しない）
+	Backoff    time.Duration // 最初の再試行までの待ち時間（以降は倍々、上限5秒）
+}
+
+// transientErrorCodes are the Postgres error codes that may succeed when the statement is simply run again
+var transientErrorCodes = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// IsTransient reports whether err is a transient failure (lost connection, failover, serialization failure)
+// rather than a problem with the statement itself. Context cancellation is never transient
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// クラス08は接続の例外（08006 connection_failure 等）
+		return pqErr.Code.Class() == "08" || transientErrorCodes[pqErr.Code]
+	}
+
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// Retry runs fn and, while it fails with a transient error, runs it again up to cfg.MaxRetries times
+// with exponential backoff. Only use it for idempotent operations such as reads: a write whose
+// connection dropped may already have been applied. The last error is returned as is
+func Retry(ctx context.Context, cfg RetryConfig, logger logrus.FieldLogger, fn func() error) error {
+	delay := cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= cfg.MaxRetries || !IsTransient(err) {
+			return err
+		}
+
+		logger.WithError(err).WithFields(logrus.Fields{
+			"retry":    attempt + 1,
+			"retry_in": delay,
+		}).Warn("一時的なDBエラーのため再試行します")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		delay *= 2
+		if delay > maxRetryBackoff {
+			delay = maxRetryBackoff
+		}
+	}
+}
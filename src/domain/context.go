@@ -0,0 +1,32 @@
+package domain
+
+import "context"
+
+type contextKey string
+
+const (
+	userIDContextKey    contextKey = "user_id"
+	requestIDContextKey contextKey = "request_id"
+)
+
+// WithUserID returns a copy of ctx that carries the authenticated user ID
+func WithUserID(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, userIDContextKey, userID)
+}
+
+// UserIDFromContext extracts the authenticated user ID from ctx
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userIDContextKey).(int)
+	return userID, ok && userID > 0
+}
+
+// WithRequestID returns a copy of ctx that carries the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+// RequestIDFromContext extracts the request ID from ctx
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDContextKey).(string)
+	return requestID, ok && requestID != ""
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Memo represents a memo domain entity
@@ -13,9 +15,109 @@ type Memo struct {
 	Tags        []string
 	Priority    Priority
 	Status      Status
+	Pinned      bool
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	CompletedAt *time.Time
+	TrashedAt   *time.Time
+	DueDate     *time.Time
+	// RemindAt is when the owner wants to be reminded; Reminded is set once the reminder has been sent
+	RemindAt *time.Time
+	Reminded bool
+	// Color is the display color as #RRGGBB; empty when unset
+	Color string
+	// Version starts at 1 and is incremented on every update; used for optimistic concurrency control
+	Version int
+	// SearchRank is the full-text relevance of the memo; set only on full-text search results
+	SearchRank *float64
+}
+
+// MemoRevision represents a past version of a memo
+type MemoRevision struct {
+	ID        int
+	MemoID    int
+	Title     string
+	Content   string
+	Category  string
+	Tags      []string
+	Priority  Priority
+	CreatedAt time.Time
+}
+
+// MemoAttachment represents file metadata attached to a memo
+type MemoAttachment struct {
+	ID          int
+	MemoID      int
+	Filename    string
+	ContentType string
+	Size        int64
+	ObjectKey   string
+	CreatedAt   time.Time
+}
+
+// MemoExpand selects related collections to load together with a memo
+type MemoExpand struct {
+	Revisions   bool
+	Attachments bool
+}
+
+// MemoDetail represents a memo with its requested related collections
+type MemoDetail struct {
+	Memo        *Memo
+	Revisions   []MemoRevision
+	Attachments []MemoAttachment
+}
+
+// SharePermission represents the access granted to a user a memo is shared with
+type SharePermission string
+
+const (
+	SharePermissionRead  SharePermission = "read"
+	SharePermissionWrite SharePermission = "write"
+)
+
+// SharedMemo represents a memo another user has shared with the caller
+type SharedMemo struct {
+	Memo          Memo
+	OwnerUsername string
+	Permission    SharePermission
+	SharedAt      time.Time
+}
+
+// DeletedMemo represents a permanently deleted memo kept in the recycle log until its retention expires.
+// ID identifies the recycle log entry; Memo.ID is the ID the memo had before it was deleted
+type DeletedMemo struct {
+	ID        int
+	Memo      Memo
+	DeletedAt time.Time
+}
+
+// MemoReminder is a memo whose reminder time has passed, together with the user to notify
+type MemoReminder struct {
+	UserID int
+	Memo   Memo
+}
+
+// Memo events delivered to webhooks
+const (
+	MemoEventCreated = "memo.created"
+	MemoEventUpdated = "memo.updated"
+	MemoEventDeleted = "memo.deleted"
+)
+
+// MemoEvents lists every memo event a webhook can subscribe to
+var MemoEvents = []string{MemoEventCreated, MemoEventUpdated, MemoEventDeleted}
+
+// Webhook is an endpoint of a user that receives the memo events it subscribes to.
+// Secret signs each delivery so that the receiver can verify it came from this server
+type Webhook struct {
+	ID        int
+	UserID    int
+	URL       string
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 // Priority represents memo priority levels
@@ -33,6 +135,20 @@ type Status string
 const (
 	StatusActive   Status = "active"
 	StatusArchived Status = "archived"
+	// StatusTrashed marks a memo as pending permanent deletion, distinct from an intentional archive
+	StatusTrashed Status = "trashed"
+)
+
+// DeleteAction describes the step of the staged deletion that applies to a memo next
+type DeleteAction string
+
+const (
+	// DeleteActionArchive archives an active memo
+	DeleteActionArchive DeleteAction = "archive"
+	// DeleteActionTrash moves an archived memo to the trash, from where it can still be restored
+	DeleteActionTrash DeleteAction = "trash"
+	// DeleteActionPermanent removes a trashed memo from the memos, keeping a copy in the recycle log
+	DeleteActionPermanent DeleteAction = "permanent_delete"
 )
 
 // MemoFilter represents filter criteria for memo queries
@@ -42,10 +158,160 @@ type MemoFilter struct {
 	Priority Priority
 	Search   string
 	Tags     []string
-	Page     int
+	// DueBefore and DueAfter bound due_date exclusively; memos without a due date never match
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	// Overdue restricts the result to active memos whose due date has passed
+	Overdue bool
+	Page    int
+	Limit   int
+	// Cursor switches to keyset pagination ordered by pinned, created_at, id (descending), so pinned memos
+	// come first as with offset pagination. A non-nil zero cursor requests the first page; nil keeps offset
+	// pagination. Cursors cannot be combined with Sort.
+	Cursor *MemoCursor
+	// Sort overrides the default ordering; keys are applied in order
+	Sort []SortField
+}
+
+// SortField represents a single sort key
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// sortableMemoFields is the whitelist of fields memos can be sorted by
+var sortableMemoFields = map[string]bool{
+	"id":         true,
+	"title":      true,
+	"category":   true,
+	"priority":   true,
+	"status":     true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// IsSortableMemoField reports whether memos can be sorted by the given field
+func IsSortableMemoField(field string) bool {
+	return sortableMemoFields[field]
+}
+
+// MemoCursor identifies the last memo seen in keyset pagination
+type MemoCursor struct {
+	Pinned    bool
+	CreatedAt time.Time
+	ID        int
+}
+
+// IsZero reports whether the cursor points at the start of the list
+func (c MemoCursor) IsZero() bool {
+	return c.ID == 0 && c.CreatedAt.IsZero() && !c.Pinned
+}
+
+// MemoBulkUpdate represents a partial update applied to several memos at once.
+// Nil fields are left unchanged.
+type MemoBulkUpdate struct {
+	Category *string
+	Status   *Status
+}
+
+// MemoMetadataUpdate represents a change to the metadata of a single memo.
+// Nil fields are left unchanged; an empty Color clears the color. Title and content are never touched.
+// DueDate is only applied when DueDateSet is true, so a nil DueDate with DueDateSet clears the due date.
+type MemoMetadataUpdate struct {
+	Category   *string
+	Tags       []string
+	Priority   *Priority
+	Color      *string
+	DueDate    *time.Time
+	DueDateSet bool
+}
+
+// MemoStats summarizes the memos of a single user (or of all memos for unauthenticated callers).
+// Trashed memos are only counted in ByStatus; the other counts cover active and archived memos.
+type MemoStats struct {
+	Total             int
+	ByStatus          map[Status]int
+	ByPriority        map[Priority]int
+	ByCategory        map[string]int // 未分類のメモは含まない
+	CreatedLast7Days  int
+	CreatedLast30Days int
+}
+
+// MemoListState summarizes all memos of a user (every status) cheaply enough to validate cached memo lists.
+// It changes whenever a memo of the user is created, changed or deleted
+type MemoListState struct {
+	MaxUpdatedAt  time.Time // 最新の更新日時（メモがない場合はゼロ値）
+	Count         int
+	PinnedIDs     string // ピン留めされたメモのID（ID順のカンマ区切り）。ピン留めは updated_at を変えないため
+	RemindedCount int    // 通知済みのメモの数。リマインダーの通知は updated_at を変えないため
+}
+
+// NewMemoStats returns stats with every status and priority present and zero
+func NewMemoStats() *MemoStats {
+	return &MemoStats{
+		ByStatus:   map[Status]int{StatusActive: 0, StatusArchived: 0, StatusTrashed: 0},
+		ByPriority: map[Priority]int{PriorityLow: 0, PriorityMedium: 0, PriorityHigh: 0},
+		ByCategory: map[string]int{},
+	}
+}
+
+// TagCount represents a tag together with the number of memos using it
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// TagFilter represents options for listing tags with counts
+type TagFilter struct {
+	MinCount int
 	Limit    int
 }
 
+// CharCount returns the number of characters (runes) in the content
+func (m Memo) CharCount() int {
+	return utf8.RuneCountInString(m.Content)
+}
+
+// WordCount returns the number of words in the content. Words are runs of letters and digits;
+// Japanese and Chinese have no spaces between words, so each of their characters counts as one word
+func (m Memo) WordCount() int {
+	count := 0
+	inWord := false
+	for _, r := range m.Content {
+		switch {
+		case isSpacelessScript(r):
+			count++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			if !inWord {
+				count++
+				inWord = true
+			}
+		case inWord && (r == '\'' || r == '’' || r == '-'):
+			// don't や e-mail は1語として数える
+		default:
+			inWord = false
+		}
+	}
+	return count
+}
+
+// ContainsSpacelessScript reports whether s contains Japanese or Chinese characters, whose words
+// are not separated by spaces
+func ContainsSpacelessScript(s string) bool {
+	for _, r := range s {
+		if isSpacelessScript(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSpacelessScript reports whether r belongs to a script written without spaces between words
+func isSpacelessScript(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
 // IsValid validates if the priority is valid
 func (p Priority) IsValid() bool {
 	switch p {
@@ -59,13 +325,26 @@ func (p Priority) IsValid() bool {
 // IsValid validates if the status is valid
 func (s Status) IsValid() bool {
 	switch s {
-	case StatusActive, StatusArchived:
+	case StatusActive, StatusArchived, StatusTrashed:
 		return true
 	default:
 		return false
 	}
 }
 
+// NextDeleteAction returns what deleting a memo in this status does next: DELETE /api/memos/:id archives
+// an active memo and trashes an archived one, and only trashed memos are deleted permanently
+func (s Status) NextDeleteAction() DeleteAction {
+	switch s {
+	case StatusTrashed:
+		return DeleteActionPermanent
+	case StatusArchived:
+		return DeleteActionTrash
+	default:
+		return DeleteActionArchive
+	}
+}
+
 // String returns string representation of Priority
 func (p Priority) String() string {
 	return string(p)
@@ -75,3 +354,31 @@ func (p Priority) String() string {
 func (s Status) String() string {
 	return string(s)
 }
+
+// Code returns the numeric representation of Priority: low=1, medium=2, high=3 (0 if unknown)
+func (p Priority) Code() int {
+	switch p {
+	case PriorityLow:
+		return 1
+	case PriorityMedium:
+		return 2
+	case PriorityHigh:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Code returns the numeric representation of Status: active=1, archived=2, trashed=3 (0 if unknown)
+func (s Status) Code() int {
+	switch s {
+	case StatusActive:
+		return 1
+	case StatusArchived:
+		return 2
+	case StatusTrashed:
+		return 3
+	default:
+		return 0
+	}
+}
@@ -1,15 +1,118 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // MemoRepository defines the interface for memo data operations
 type MemoRepository interface {
 	Create(ctx context.Context, memo *Memo) (*Memo, error)
+	// CreateMany inserts memos with newly assigned IDs in a single transaction (all or nothing)
+	CreateMany(ctx context.Context, memos []Memo) ([]Memo, error)
+	// CreateWithIDs inserts memos keeping their given IDs in a single transaction and advances the ID sequence past them
+	CreateWithIDs(ctx context.Context, memos []Memo) ([]Memo, error)
 	GetByID(ctx context.Context, id int) (*Memo, error)
+	// Exists reports whether a memo with the ID exists for any user, ignoring the caller's scope
+	Exists(ctx context.Context, id int) (bool, error)
 	List(ctx context.Context, filter MemoFilter) ([]Memo, int, error)
+	// ForEach streams every memo of the caller (all statuses) in ID order to fn without loading them all;
+	// an error returned by fn stops the iteration and is returned as is
+	ForEach(ctx context.Context, fn func(*Memo) error) error
+	// Update overwrites the memo and increments its version, recording the overwritten title, content, category,
+	// tags and priority as a revision in the same transaction. When memo.Version is positive the update
+	// only succeeds if the stored version still matches it; otherwise a version conflict error is returned
 	Update(ctx context.Context, id int, memo *Memo) (*Memo, error)
+	// UpdateMetadata applies the metadata update to a memo in a single statement without touching its title or content,
+	// recording the previous values as a revision in the same transaction
+	UpdateMetadata(ctx context.Context, id int, update MemoMetadataUpdate) (*Memo, error)
+	// Delete moves a memo one stage towards deletion (active → archived → trashed) and never removes the row;
+	// it fails with "memo is already in trash" for a trashed memo
 	Delete(ctx context.Context, id int) error
+	// DeleteMany applies Delete to the given memos in a single transaction and returns the IDs actually changed
+	DeleteMany(ctx context.Context, ids []int) ([]int, error)
+	// BulkUpdate applies the update to the given memos in a single transaction and returns the IDs actually updated
+	BulkUpdate(ctx context.Context, ids []int, update MemoBulkUpdate) ([]int, error)
 	Archive(ctx context.Context, id int) error
+	// Trash moves a memo to the trash; PermanentDelete only removes memos that are already trashed
+	// and copies them into the recycle log in the same statement
+	Trash(ctx context.Context, id int) (*Memo, error)
+	PermanentDelete(ctx context.Context, id int) error
+	// ListDeleted lists the caller's recycle log entries, newest first; only Page and Limit of filter are used
+	ListDeleted(ctx context.Context, filter MemoFilter) ([]DeletedMemo, int, error)
+	// RestoreDeleted moves a recycle log entry back into the memos as an active memo. The memo keeps its
+	// old ID when that ID is still free and gets a new one otherwise
+	RestoreDeleted(ctx context.Context, id int) (*Memo, error)
+	// PurgeDeletedOlderThan removes recycle log entries of all users that were deleted longer than age ago
+	PurgeDeletedOlderThan(ctx context.Context, age time.Duration) (int64, error)
+	// PurgeTrashedOlderThan permanently deletes memos of all users that have been in the trash longer than age
+	PurgeTrashedOlderThan(ctx context.Context, age time.Duration) (int64, error)
 	Restore(ctx context.Context, id int) error
+	Promote(ctx context.Context, id int, priority Priority) (*Memo, error)
+	Touch(ctx context.Context, id int) (*Memo, error)
+	// SetPinned pins or unpins a memo without touching its other fields
+	SetPinned(ctx context.Context, id int, pinned bool) (*Memo, error)
+	ListRevisions(ctx context.Context, memoID int) ([]MemoRevision, error)
+	// GetRevision returns a revision of the given memo; revisions of other memos or users are not found
+	GetRevision(ctx context.Context, memoID, revisionID int) (*MemoRevision, error)
+	ListAttachments(ctx context.Context, memoID int) ([]MemoAttachment, error)
+	RecordSearchQuery(ctx context.Context, query string, limit int) error
+	ListSearchQueries(ctx context.Context) ([]string, error)
+	ClearSearchQueries(ctx context.Context) error
+	ListTags(ctx context.Context, filter TagFilter) ([]TagCount, error)
+	// ListCategories lists the distinct non-empty categories of the authenticated user's memos (all memos without one)
+	ListCategories(ctx context.Context) ([]string, error)
+	// Stats counts the authenticated user's memos (all memos without one) by status, priority, category and recent creation
+	Stats(ctx context.Context) (*MemoStats, error)
+	// MaxUpdatedAt returns the latest updated_at and count of the authenticated user's memos (all memos without one),
+	// plus the changes that do not touch updated_at, as a validator for cached memo lists
+	MaxUpdatedAt(ctx context.Context) (*MemoListState, error)
+	// ListSharedWithUser lists memos other users have shared with the caller; only Page and Limit of filter are used
+	ListSharedWithUser(ctx context.Context, filter MemoFilter) ([]SharedMemo, int, error)
 	Search(ctx context.Context, query string, filter MemoFilter) ([]Memo, int, error)
+	// ListOnThisDay lists memos created on the same month and day as date in earlier years
+	ListOnThisDay(ctx context.Context, date time.Time) ([]Memo, error)
+}
+
+// MemoTx is the MemoRepository handed to MemoTransactor.WithinTx; all of its operations share one transaction
+type MemoTx interface {
+	MemoRepository
+	// GetByIDForUpdate retrieves a memo like GetByID and locks its row until the transaction ends,
+	// so concurrent transactions on the same memo wait for each other
+	GetByIDForUpdate(ctx context.Context, id int) (*Memo, error)
+}
+
+// MemoTransactor is implemented by memo repositories that can run several operations atomically
+type MemoTransactor interface {
+	// WithinTx runs fn in a transaction that is committed when fn returns nil and rolled back otherwise
+	WithinTx(ctx context.Context, fn func(tx MemoTx) error) error
+}
+
+// ReminderRepository defines the data operations of the reminder scheduler. It works across all users
+type ReminderRepository interface {
+	// ListDueReminders lists up to limit active memos whose reminder time is at or before now and has not been sent, oldest first
+	ListDueReminders(ctx context.Context, now time.Time, limit int) ([]MemoReminder, error)
+	// MarkReminded records that the reminder of the memo has been sent
+	MarkReminded(ctx context.Context, id int) error
+}
+
+// WebhookRepository defines the data operations of webhooks. Like MemoRepository the CRUD operations are
+// scoped to the authenticated user in the context
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *Webhook) (*Webhook, error)
+	GetByID(ctx context.Context, id int) (*Webhook, error)
+	List(ctx context.Context) ([]Webhook, error)
+	Update(ctx context.Context, id int, webhook *Webhook) (*Webhook, error)
+	Delete(ctx context.Context, id int) error
+	// ListByEvent lists the webhooks of the user subscribed to event. It is used by the delivery worker
+	ListByEvent(ctx context.Context, userID int, event string) ([]Webhook, error)
+}
+
+// AttachmentRepository defines the data operations of memo attachment metadata. Like MemoRepository the
+// operations are scoped to the authenticated user in the context: attachments of other users' memos are not found
+type AttachmentRepository interface {
+	// Create records an attachment of the memo; it fails with "memo not found" if the memo is not the caller's
+	Create(ctx context.Context, attachment *MemoAttachment) (*MemoAttachment, error)
+	// GetByID returns an attachment of the given memo; attachments of other memos are not found
+	GetByID(ctx context.Context, memoID, id int) (*MemoAttachment, error)
 }
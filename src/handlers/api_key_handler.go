@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"memo-app/src/models"
+	"memo-app/src/problem"
+	"memo-app/src/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler APIキー管理ハンドラー
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+// NewAPIKeyHandler APIキー管理ハンドラーのコンストラクタ
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey APIキーを発行（平文のキーはこのレスポンスでのみ返す）
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	created, err := h.apiKeyService.CreateAPIKey(userID, req.Name)
+	if err != nil {
+		if strings.Contains(err.Error(), "api key name is required") {
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "API key name is required"})
+			return
+		}
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "API key created successfully. Store the key now; it will not be shown again",
+		"data":    created,
+	})
+}
+
+// ListAPIKeys APIキー一覧を取得（キーの平文は含まない）
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	keys, err := h.apiKeyService.ListAPIKeys(userID)
+	if err != nil {
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Failed to list API keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": keys,
+	})
+}
+
+// RevokeAPIKey APIキーを失効させる
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	keyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || keyID <= 0 {
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(userID, keyID); err != nil {
+		if strings.Contains(err.Error(), "api key not found") {
+			problem.JSON(c, http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API key revoked successfully",
+	})
+}
+
+// authenticatedUserID 認証ミドルウェアが設定したユーザーIDを取得
+func authenticatedUserID(c *gin.Context) (int, bool) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return 0, false
+	}
+	userID, ok := value.(int)
+	return userID, ok && userID > 0
+}
@@ -3,10 +3,15 @@ package handlers
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"memo-app/src/models"
+	"memo-app/src/problem"
 	"memo-app/src/service"
 
 	"github.com/gin-gonic/gin"
@@ -41,7 +46,7 @@ type LoginRequest struct {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request format"})
 		return
 	}
 
@@ -58,19 +63,23 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	// 新規登録処理
 	authResponse, err := h.authService.Register(registerReq, clientIP)
 	if err != nil {
+		if strings.Contains(err.Error(), "username is reserved") {
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Username is reserved", "field": "username"})
+			return
+		}
 		if strings.Contains(err.Error(), "username already exists") {
-			c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+			problem.JSON(c, http.StatusConflict, gin.H{"error": "Username already exists"})
 			return
 		}
 		if strings.Contains(err.Error(), "email already exists") {
-			c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+			problem.JSON(c, http.StatusConflict, gin.H{"error": "Email already exists"})
 			return
 		}
 		if strings.Contains(err.Error(), "IP limit exceeded") {
-			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many registrations from this IP address"})
+			problem.JSON(c, http.StatusTooManyRequests, gin.H{"error": "Too many registrations from this IP address"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Registration failed"})
 		return
 	}
 
@@ -84,7 +93,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request format"})
 		return
 	}
 
@@ -97,15 +106,25 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	// ログイン処理
 	authResponse, err := h.authService.Login(loginReq, getClientIP(c))
 	if err != nil {
+		var throttled *service.LoginThrottledError
+		if errors.As(err, &throttled) {
+			seconds := int(math.Ceil(throttled.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			problem.JSON(c, http.StatusTooManyRequests, gin.H{
+				"error":       "Too many login attempts",
+				"retry_after": seconds,
+			})
+			return
+		}
 		if strings.Contains(err.Error(), "invalid credentials") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+			problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
 			return
 		}
 		if strings.Contains(err.Error(), "account is deactivated") {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Account is deactivated"})
+			problem.JSON(c, http.StatusForbidden, gin.H{"error": "Account is deactivated"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Login failed"})
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Login failed"})
 		return
 	}
 
@@ -137,14 +156,14 @@ func (h *AuthHandler) GitHubCallback(c *gin.Context) {
 	state := c.Query("state")
 
 	if code == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Authorization code is required"})
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Authorization code is required"})
 		return
 	}
 
 	// stateの検証（本実装では簡略化）
 	storedState, err := c.Cookie("github_oauth_state")
 	if err != nil || storedState != state {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid state parameter"})
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid state parameter"})
 		return
 	}
 
@@ -158,14 +177,14 @@ func (h *AuthHandler) GitHubCallback(c *gin.Context) {
 	authResponse, err := h.authService.HandleGitHubCallback(code, state, clientIP)
 	if err != nil {
 		if strings.Contains(err.Error(), "IP limit exceeded") {
-			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many registrations from this IP address"})
+			problem.JSON(c, http.StatusTooManyRequests, gin.H{"error": "Too many registrations from this IP address"})
 			return
 		}
 		if strings.Contains(err.Error(), "email already exists") {
-			c.JSON(http.StatusConflict, gin.H{"error": "Email already exists with different authentication method"})
+			problem.JSON(c, http.StatusConflict, gin.H{"error": "Email already exists with different authentication method"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "GitHub authentication failed"})
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "GitHub authentication failed"})
 		return
 	}
 
@@ -183,17 +202,17 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 
 	var req RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request format"})
 		return
 	}
 
 	authResponse, err := h.authService.RefreshToken(req.RefreshToken)
 	if err != nil {
 		if strings.Contains(err.Error(), "invalid refresh token") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+			problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Token refresh failed"})
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Token refresh failed"})
 		return
 	}
 
@@ -203,18 +222,219 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	})
 }
 
-// GetProfile 現在のユーザープロフィールを取得
+// VerifyEmail メールアドレス確認
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Verification token is required"})
+		return
+	}
+
+	if err := h.authService.VerifyEmail(token); err != nil {
+		if strings.Contains(err.Error(), "verification token expired") {
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Verification token expired"})
+			return
+		}
+		if strings.Contains(err.Error(), "verification token already used") {
+			problem.JSON(c, http.StatusConflict, gin.H{"error": "Verification token already used"})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid verification token") {
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid verification token"})
+			return
+		}
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Email verification failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified successfully",
+	})
+}
+
+// ForgotPassword パスワード再設定メールの送信
+// メールアドレスが登録されているかに関わらず同じレスポンスを返す
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	if err := h.authService.RequestPasswordReset(req.Email); err != nil {
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Password reset request failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "If the email address is registered, a password reset link has been sent",
+	})
+}
+
+// ResetPassword パスワード再設定
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	if err := h.authService.ResetPassword(req.Token, req.NewPassword); err != nil {
+		if strings.Contains(err.Error(), "weak password") {
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Password does not meet the strength requirements"})
+			return
+		}
+		if strings.Contains(err.Error(), "password reset token expired") {
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Password reset token expired"})
+			return
+		}
+		if strings.Contains(err.Error(), "password reset token already used") {
+			problem.JSON(c, http.StatusConflict, gin.H{"error": "Password reset token already used"})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid password reset token") {
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid password reset token"})
+			return
+		}
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Password reset failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password has been reset successfully",
+	})
+}
+
+// ChangePassword ログイン中のユーザーのパスワード変更（AuthMiddlewareの後に適用）
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	id, ok := userID.(int)
+	if !ok {
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Invalid user data"})
+		return
+	}
+
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	if err := h.authService.ChangePassword(id, req.CurrentPassword, req.NewPassword); err != nil {
+		if strings.Contains(err.Error(), "external authentication") {
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Password cannot be changed for accounts that sign in with GitHub"})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid current password") {
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Current password is incorrect"})
+			return
+		}
+		if strings.Contains(err.Error(), "weak password") {
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Password does not meet the strength requirements"})
+			return
+		}
+		if strings.Contains(err.Error(), "must differ") {
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "New password must differ from the current password"})
+			return
+		}
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Password change failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password changed successfully",
+	})
+}
+
+// LogoutAll 全端末からログアウト（発行済みのトークンをすべて無効にする。AuthMiddlewareの後に適用）
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	id, ok := userID.(int)
+	if !ok {
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Invalid user data"})
+		return
+	}
+
+	if err := h.authService.LogoutAll(id); err != nil {
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Logout failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out from all sessions",
+	})
+}
+
+// GetProfile 現在のユーザープロフィールを取得（AuthMiddlewareの後に適用）
+// auth_provider でローカル認証かGitHub認証かを返すため、クライアントはJWTを解析しなくてよい
 func (h *AuthHandler) GetProfile(c *gin.Context) {
-	// ミドルウェアから認証されたユーザーを取得
-	userInterface, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	userID, ok := c.Get("user_id")
+	if !ok {
+		problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	id, ok := userID.(int)
+	if !ok {
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Invalid user data"})
+		return
+	}
+
+	user, err := h.authService.GetProfile(id)
+	if err != nil {
+		problem.JSON(c, http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
 
-	user, ok := userInterface.(*models.User)
+	c.JSON(http.StatusOK, gin.H{
+		"data": user.ToPublic(),
+	})
+}
+
+// UpdateProfile ログイン中のユーザーのユーザー名・メールアドレスを更新（AuthMiddlewareの後に適用）
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := c.Get("user_id")
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user data"})
+		problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	id, ok := userID.(int)
+	if !ok {
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Invalid user data"})
+		return
+	}
+
+	var req models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	user, err := h.authService.UpdateProfile(id, &req)
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case strings.Contains(msg, "read-only field"):
+			field := strings.TrimPrefix(msg, "read-only field: ")
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "This field is linked to GitHub and cannot be changed", "field": field})
+		case strings.Contains(msg, "invalid profile"):
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid username or email format"})
+		case strings.Contains(msg, "username already exists"):
+			problem.JSON(c, http.StatusConflict, gin.H{"error": "Username is already taken", "field": "username"})
+		case strings.Contains(msg, "email already exists"):
+			problem.JSON(c, http.StatusConflict, gin.H{"error": "Email is already registered", "field": "email"})
+		case strings.Contains(msg, "user not found"):
+			problem.JSON(c, http.StatusNotFound, gin.H{"error": "User not found"})
+		default:
+			problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Profile update failed"})
+		}
 		return
 	}
 
@@ -223,6 +443,46 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	})
 }
 
+// DeleteAccount ログイン中のユーザーのアカウントとメモを削除（AuthMiddlewareの後に適用）
+func (h *AuthHandler) DeleteAccount(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	id, ok := userID.(int)
+	if !ok {
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Invalid user data"})
+		return
+	}
+
+	// GitHub認証のみのアカウントはボディなしで削除できる
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	if err := h.authService.DeleteAccount(id, req.Password); err != nil {
+		msg := err.Error()
+		switch {
+		case strings.Contains(msg, "password required"):
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Password is required to delete the account"})
+		case strings.Contains(msg, "invalid password"):
+			problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Password is incorrect"})
+		case strings.HasPrefix(msg, "user not found"):
+			problem.JSON(c, http.StatusNotFound, gin.H{"error": "User not found"})
+		default:
+			problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Account deletion failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Account deleted",
+	})
+}
+
 // getClientIP クライアントのIPアドレスを取得
 func getClientIP(c *gin.Context) string {
 	// X-Forwarded-For ヘッダーをチェック
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+
+	"memo-app/src/logger"
+	"memo-app/src/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IPRegistrationResetter IPアドレスごとの登録数をリセットする
+type IPRegistrationResetter interface {
+	ResetIPRegistration(ipAddress string) error
+}
+
+// IPRegistrationHandler 新規登録のIP制限を管理する管理者用ハンドラー
+type IPRegistrationHandler struct {
+	resetter IPRegistrationResetter
+}
+
+// NewIPRegistrationHandler IP制限の管理ハンドラーを作成
+func NewIPRegistrationHandler(resetter IPRegistrationResetter) *IPRegistrationHandler {
+	return &IPRegistrationHandler{resetter: resetter}
+}
+
+// ResetIPRegistration IPアドレスの登録数をリセットし、期間を待たずに再び登録できるようにする
+func (h *IPRegistrationHandler) ResetIPRegistration(c *gin.Context) {
+	ip := net.ParseIP(c.Param("ip"))
+	if ip == nil {
+		problem.JSON(c, http.StatusBadRequest, gin.H{"error": "Invalid IP address"})
+		return
+	}
+
+	log := logger.WithRequestID(c).WithField("ip_address", ip.String())
+	if err := h.resetter.ResetIPRegistration(ip.String()); err != nil {
+		log.WithError(err).Error("IPアドレスの登録数のリセットに失敗")
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Failed to reset IP registration count"})
+		return
+	}
+
+	log.Info("管理者によりIPアドレスの登録数をリセットしました")
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "IP registration count reset",
+		"ip_address": ip.String(),
+	})
+}
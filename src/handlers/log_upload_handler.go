@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"memo-app/src/logger"
+	"memo-app/src/problem"
+	"memo-app/src/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// LogUploadRunner ログディレクトリのすべてのログファイルを今すぐアップロードする
+type LogUploadRunner interface {
+	UploadAll(ctx context.Context, logDir string) (storage.UploadSummary, error)
+}
+
+// LogUploadResponse 手動アップロードの結果
+type LogUploadResponse struct {
+	Uploaded int `json:"uploaded"`
+	Kept     int `json:"kept"` // アップロードしたが書き込み中のためローカルに残したファイル数
+	Failed   int `json:"failed"`
+	Skipped  int `json:"skipped"`
+}
+
+// LogUploadHandler ログのS3アップロードを手動で実行する管理者用ハンドラー
+type LogUploadHandler struct {
+	uploader LogUploadRunner
+	logDir   string
+}
+
+// NewLogUploadHandler 手動アップロードのハンドラーを作成（uploader が nil の場合は常に503を返す）
+func NewLogUploadHandler(uploader LogUploadRunner, logDir string) *LogUploadHandler {
+	return &LogUploadHandler{uploader: uploader, logDir: logDir}
+}
+
+// UploadLogs 定期アップロードを待たずに現在のログファイルをアップロードし、件数を返す
+func (h *LogUploadHandler) UploadLogs(c *gin.Context) {
+	if h.uploader == nil {
+		problem.JSON(c, http.StatusServiceUnavailable, gin.H{"error": "Log upload is not enabled"})
+		return
+	}
+
+	log := logger.WithRequestID(c).WithField("client_ip", c.ClientIP())
+	log.Info("管理者によりログの手動アップロードを開始")
+
+	summary, err := h.uploader.UploadAll(c.Request.Context(), h.logDir)
+	if err != nil {
+		log.WithError(err).Error("ログの手動アップロードに失敗")
+		problem.JSON(c, http.StatusInternalServerError, gin.H{"error": "Failed to upload logs"})
+		return
+	}
+
+	log.WithFields(logrus.Fields{
+		"uploaded": summary.Uploaded,
+		"kept":     summary.Kept,
+		"failed":   summary.Failed,
+		"skipped":  summary.Skipped,
+	}).Info("ログの手動アップロードが完了しました")
+
+	c.JSON(http.StatusOK, LogUploadResponse{
+		Uploaded: summary.Uploaded,
+		Kept:     summary.Kept,
+		Failed:   summary.Failed,
+		Skipped:  summary.Skipped,
+	})
+}
@@ -163,9 +163,9 @@ func (h *MemoHandler) UpdateMemo(c *gin.Context) {
 	c.JSON(http.StatusOK, memo)
 }
 
-// DeleteMemo deletes a memo
-// @Summary Delete a memo
-// @Description Delete a memo by its ID
+// DeleteMemo moves a memo one stage towards deletion (active → archived → trashed)
+// @Summary Archive or trash a memo (staged delete)
+// @Description Archive an active memo or move an archived memo to the trash
 // @Tags memos
 // @Param id path int true "Memo ID"
 // @Success 204
@@ -187,6 +187,10 @@ func (h *MemoHandler) DeleteMemo(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Memo not found"})
 			return
 		}
+		if err.Error() == "memo is already in trash" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Memo is already in trash"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete memo", "details": err.Error()})
 		return
 	}
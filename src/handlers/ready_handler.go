@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"memo-app/src/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultReadyTimeout 依存先ごとの確認のタイムアウトのデフォルト値
+const DefaultReadyTimeout = 2 * time.Second
+
+// ReadyCheck 依存先に接続できるか確認する（nil の場合は利用可能）
+type ReadyCheck func(ctx context.Context) error
+
+// ReadyStats 依存先の現在の統計情報を返す（レスポンスの stats にそのままJSONで含める）
+type ReadyStats func() interface{}
+
+// readyComponent 確認する依存先1件分
+type readyComponent struct {
+	name     string
+	check    ReadyCheck
+	required bool
+}
+
+// ComponentStatus 依存先1件分の確認結果
+type ComponentStatus struct {
+	Status   string `json:"status"` // "up" または "down"
+	Required bool   `json:"required"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ReadyResponse レディネスチェックのレスポンス
+type ReadyResponse struct {
+	Status     string                     `json:"status"` // "ready" または "not_ready"
+	Timestamp  string                     `json:"timestamp"`
+	Components map[string]ComponentStatus `json:"components"`
+	Stats      map[string]interface{}     `json:"stats,omitempty"`
+}
+
+// ReadyHandler 依存先を確認し、リクエストを受け付けられるかを返すハンドラー
+// /health は依存先を確認しないライブネスチェックのまま残す
+type ReadyHandler struct {
+	timeout    time.Duration
+	components []readyComponent
+	stats      []namedStats
+}
+
+// namedStats 報告する統計情報1件分
+type namedStats struct {
+	name  string
+	stats ReadyStats
+}
+
+// NewReadyHandler レディネスチェックハンドラーのコンストラクタ（timeout が0以下の場合はデフォルト値）
+func NewReadyHandler(timeout time.Duration) *ReadyHandler {
+	if timeout <= 0 {
+		timeout = DefaultReadyTimeout
+	}
+	return &ReadyHandler{timeout: timeout}
+}
+
+// AddCheck 利用できない場合に503を返す依存先を追加
+func (h *ReadyHandler) AddCheck(name string, check ReadyCheck) {
+	h.components = append(h.components, readyComponent{name: name, check: check, required: true})
+}
+
+// AddOptionalCheck 状態を報告するだけで、利用できなくても503にしない依存先を追加
+func (h *ReadyHandler) AddOptionalCheck(name string, check ReadyCheck) {
+	h.components = append(h.components, readyComponent{name: name, check: check})
+}
+
+// AddStats 確認結果とあわせて報告する統計情報（DBの接続プール等）を追加
+func (h *ReadyHandler) AddStats(name string, stats ReadyStats) {
+	h.stats = append(h.stats, namedStats{name: name, stats: stats})
+}
+
+// Ready すべての依存先を確認し、必須の依存先が利用できない場合は503を返す
+func (h *ReadyHandler) Ready(c *gin.Context) {
+	response := ReadyResponse{
+		Status:     "ready",
+		Timestamp:  time.Now().Format(time.RFC3339),
+		Components: make(map[string]ComponentStatus, len(h.components)),
+	}
+
+	for _, component := range h.components {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+		err := component.check(ctx)
+		cancel()
+
+		status := ComponentStatus{Status: "up", Required: component.required}
+		if err != nil {
+			status.Status = "down"
+			status.Error = err.Error()
+			if component.required {
+				response.Status = "not_ready"
+			}
+
+			logger.WithRequestID(c).WithFields(logrus.Fields{
+				"component": component.name,
+				"required":  component.required,
+			}).WithError(err).Warn("レディネスチェックで依存先に接続できません")
+		}
+		response.Components[component.name] = status
+	}
+
+	if len(h.stats) > 0 {
+		response.Stats = make(map[string]interface{}, len(h.stats))
+		for _, s := range h.stats {
+			response.Stats[s.name] = s.stats()
+		}
+	}
+
+	code := http.StatusOK
+	if response.Status != "ready" {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, response)
+}
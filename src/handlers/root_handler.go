@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"memo-app/src/buildinfo"
+	"memo-app/src/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RootHandler ルートとビルド情報を返すハンドラー
+// バージョンは固定値を持たず、ビルド時に埋め込んだビルド情報で識別する
+type RootHandler struct {
+	build buildinfo.Info
+}
+
+// NewRootHandler ルートハンドラーのコンストラクタ
+func NewRootHandler(build buildinfo.Info) *RootHandler {
+	return &RootHandler{build: build}
+}
+
+// Root サービス名とデプロイされているビルドの情報を返す
+func (h *RootHandler) Root(c *gin.Context) {
+	logger.WithField("endpoint", "/").Info("Hello Worldエンドポイントにアクセス")
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Hello World",
+		"service": "memo-app-api-server",
+		"build":   h.build,
+	})
+}
+
+// Version デプロイされているビルドの情報を返す
+func (h *RootHandler) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, h.build)
+}
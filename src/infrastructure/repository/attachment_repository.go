@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// attachmentColumns is the column list scanned by scanAttachment
+const attachmentColumns = `id, memo_id, filename, content_type, size, object_key, created_at`
+
+// AttachmentRepository implements domain.AttachmentRepository
+type AttachmentRepository struct {
+	db     *database.DB
+	logger *logrus.Logger
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *database.DB, logger *logrus.Logger) domain.AttachmentRepository {
+	return &AttachmentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create records an attachment of a memo of the authenticated user.
+// The memo is checked in the same statement, so nothing is inserted for other users' memos
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *domain.MemoAttachment) (*domain.MemoAttachment, error) {
+	created := *attachment
+	created.CreatedAt = time.Now()
+
+	scope, args := memoOwnerScope(ctx, attachment.MemoID)
+	n := len(args)
+	args = append(args, created.Filename, created.ContentType, created.Size, created.ObjectKey, created.CreatedAt)
+	query := fmt.Sprintf(`
+		INSERT INTO memo_attachments (memo_id, filename, content_type, size, object_key, created_at)
+		SELECT id, $%d, $%d, $%d, $%d, $%d FROM %s AS owned
+		RETURNING id`, n+1, n+2, n+3, n+4, n+5, scope)
+
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&created.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("memo not found")
+		}
+		r.logger.WithError(err).WithField("memo_id", attachment.MemoID).Error("添付ファイルの登録に失敗")
+		return nil, fmt.Errorf("failed to create memo attachment: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"memo_id":       created.MemoID,
+		"attachment_id": created.ID,
+		"size":          created.Size,
+	}).Info("添付ファイルを登録しました")
+	return &created, nil
+}
+
+// GetByID retrieves an attachment of a memo of the authenticated user
+func (r *AttachmentRepository) GetByID(ctx context.Context, memoID, id int) (*domain.MemoAttachment, error) {
+	scope, args := memoOwnerScope(ctx, memoID)
+	args = append(args, id)
+	query := `SELECT ` + attachmentColumns + ` FROM memo_attachments WHERE memo_id = ` + scope +
+		fmt.Sprintf(` AND id = $%d`, len(args))
+
+	attachment, err := scanAttachment(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("attachment not found")
+		}
+		r.logger.WithError(err).WithFields(logrus.Fields{"memo_id": memoID, "attachment_id": id}).Error("添付ファイルの取得に失敗")
+		return nil, fmt.Errorf("failed to get memo attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// scanAttachment scans a row selected with attachmentColumns
+func scanAttachment(row rowScanner) (*domain.MemoAttachment, error) {
+	var attachment domain.MemoAttachment
+	if err := row.Scan(
+		&attachment.ID, &attachment.MemoID, &attachment.Filename, &attachment.ContentType,
+		&attachment.Size, &attachment.ObjectKey, &attachment.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
@@ -5,29 +5,222 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"memo-app/src/config"
 	"memo-app/src/database"
 	"memo-app/src/domain"
 	"memo-app/src/security"
 
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
-// MemoRepository implements domain.MemoRepository
+// MemoRepository implements domain.MemoRepository and domain.MemoTransactor
 type MemoRepository struct {
 	db           *database.DB
+	q            database.Querier // db, or tx inside WithinTx
+	tx           *sql.Tx
 	logger       *logrus.Logger
 	sqlSanitizer *security.SQLSanitizer
+	config       *config.MemoConfig
 }
 
-// NewMemoRepository creates a new memo repository
+// NewMemoRepository creates a new memo repository with the default configuration
 func NewMemoRepository(db *database.DB, logger *logrus.Logger) domain.MemoRepository {
+	return NewMemoRepositoryWithConfig(db, logger, config.DefaultMemoConfig())
+}
+
+// NewMemoRepositoryWithConfig creates a new memo repository with the given configuration
+func NewMemoRepositoryWithConfig(db *database.DB, logger *logrus.Logger, cfg *config.MemoConfig) domain.MemoRepository {
+	if cfg == nil {
+		cfg = config.DefaultMemoConfig()
+	}
 	return &MemoRepository{
 		db:           db,
+		q:            db,
 		logger:       logger,
 		sqlSanitizer: security.NewSQLSanitizer(),
+		config:       cfg,
+	}
+}
+
+// txHandle is the transaction used by methods that run several statements
+type txHandle interface {
+	database.Querier
+	Commit() error
+	Rollback() error
+}
+
+// joinedTx lets such a method run inside the transaction of WithinTx; committing and rolling back are left to WithinTx
+type joinedTx struct {
+	*sql.Tx
+}
+
+func (joinedTx) Commit() error   { return nil }
+func (joinedTx) Rollback() error { return nil }
+
+// beginTx begins a transaction, or joins the one of WithinTx
+func (r *MemoRepository) beginTx(ctx context.Context) (txHandle, error) {
+	if r.tx != nil {
+		return joinedTx{r.tx}, nil
+	}
+	return r.db.BeginTx(ctx, nil)
+}
+
+// memoColumns はSELECT/RETURNINGで使用するカラム一覧（scanMemoと順序を一致させること）
+const memoColumns = `id, title, content, category, tags, priority, status, pinned, created_at, updated_at, completed_at, trashed_at, due_date, remind_at, reminded, color, version`
+
+// archivedMemoColumns はリサイクルログ（deleted_memos）に複製するカラム一覧（idは memo_id として別に保存する）
+var archivedMemoColumns = strings.TrimPrefix(memoColumns, "id, ")
+
+// deletedMemoColumns はリサイクルログからmemoColumnsと同じ順序で読み取るカラム一覧
+var deletedMemoColumns = "memo_id, " + archivedMemoColumns
+
+// memoListOrder は一覧・検索結果の並び順
+// ピン留めしたメモを先頭にし、その中は更新で変わらない created_at の順にする（更新順は sort=-updated_at で指定する）
+// 同一時刻のメモ（一括インポート等）でもページングが安定するようにidを最後のキーにする
+const memoListOrder = `pinned DESC, created_at DESC, id DESC`
+
+// memoSortExpressions は sort パラメータのフィールドと対応するSQL式（ホワイトリスト）
+// priority は文字列順ではなく low < medium < high の順で並べる
+var memoSortExpressions = map[string]string{
+	"id":         "id",
+	"title":      "title",
+	"category":   "category",
+	"priority":   "CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 ELSE 0 END",
+	"status":     "status",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// memoOrderBy は並び順の指定からORDER BY句の内容を組み立てる
+// 未指定の場合はデフォルトの並び順を使い、指定時もidを最後のキーにしてページングを安定させる
+func memoOrderBy(sort []domain.SortField) (string, error) {
+	if len(sort) == 0 {
+		return memoListOrder, nil
+	}
+
+	keys := make([]string, 0, len(sort)+1)
+	hasID := false
+	for _, field := range sort {
+		expr, ok := memoSortExpressions[field.Field]
+		if !ok {
+			return "", fmt.Errorf("invalid sort field: %s", field.Field)
+		}
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+		keys = append(keys, expr+" "+direction)
+		hasID = hasID || field.Field == "id"
+	}
+	if !hasID {
+		keys = append(keys, "id DESC")
+	}
+	return strings.Join(keys, ", "), nil
+}
+
+// fullTextConfig は全文検索で使用するテキスト検索設定（search_vector 列の定義と一致させること）
+const fullTextConfig = "simple"
+
+// minFullTextQueryLength は全文検索を使用する検索クエリの最小文字数（未満の場合は部分一致で検索）
+const minFullTextQueryLength = 3
+
+// memoCursorOrder はカーソルページネーション時の並び順
+// オフセットページングと同じ並び順にし、カーソルのキー (pinned, created_at, id) と一致させる
+const memoCursorOrder = memoListOrder
+
+// rowScanner は *sql.Row と *sql.Rows の共通インターフェース
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanMemo はmemoColumnsの順序で1行を読み取りドメインエンティティに変換する
+func scanMemo(scanner rowScanner) (*domain.Memo, error) {
+	var memo domain.Memo
+	var tagsJSON string
+	var priorityStr string
+	var statusStr string
+	var completedAt sql.NullTime
+	var trashedAt sql.NullTime
+	var dueDate sql.NullTime
+	var remindAt sql.NullTime
+	var color sql.NullString
+
+	if err := scanner.Scan(
+		&memo.ID, &memo.Title, &memo.Content, &memo.Category, &tagsJSON,
+		&priorityStr, &statusStr, &memo.Pinned, &memo.CreatedAt, &memo.UpdatedAt, &completedAt, &trashedAt, &dueDate, &remindAt,
+		&memo.Reminded, &color, &memo.Version,
+	); err != nil {
+		return nil, err
+	}
+
+	// JSON文字列からタグを復元
+	if err := json.Unmarshal([]byte(tagsJSON), &memo.Tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+
+	memo.Priority = domain.Priority(priorityStr)
+	memo.Status = domain.Status(statusStr)
+	if completedAt.Valid {
+		memo.CompletedAt = &completedAt.Time
+	}
+	if trashedAt.Valid {
+		memo.TrashedAt = &trashedAt.Time
+	}
+	if dueDate.Valid {
+		memo.DueDate = &dueDate.Time
+	}
+	if remindAt.Valid {
+		memo.RemindAt = &remindAt.Time
+	}
+	memo.Color = color.String
+
+	return &memo, nil
+}
+
+// extraColumnsScanner はmemoColumnsの後に続く追加の列をscanMemoと一緒に読み取る
+type extraColumnsScanner struct {
+	rowScanner
+	extra []interface{}
+}
+
+func (s extraColumnsScanner) Scan(dest ...interface{}) error {
+	return s.rowScanner.Scan(append(dest, s.extra...)...)
+}
+
+// qualifiedMemoColumns はJOIN用にmemoColumnsの各列へテーブル別名を付与する
+func qualifiedMemoColumns(alias string) string {
+	columns := strings.Split(memoColumns, ", ")
+	for i, column := range columns {
+		columns[i] = alias + "." + column
+	}
+	return strings.Join(columns, ", ")
+}
+
+// userScope は認証済みユーザーが存在する場合にuser_id条件を追加する
+func userScope(ctx context.Context, query string, args []interface{}) (string, []interface{}) {
+	if userID, ok := domain.UserIDFromContext(ctx); ok {
+		args = append(args, userID)
+		query += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+	return query, args
+}
+
+// log は認証済みユーザーIDとリクエストIDを付与したログエントリを返す
+// すべてのDB操作のログをユーザーとリクエストに紐付けられるようにする
+func (r *MemoRepository) log(ctx context.Context) *logrus.Entry {
+	fields := logrus.Fields{}
+	if userID, ok := domain.UserIDFromContext(ctx); ok {
+		fields["user_id"] = userID
+	}
+	if requestID, ok := domain.RequestIDFromContext(ctx); ok {
+		fields["request_id"] = requestID
 	}
+	return r.logger.WithFields(fields)
 }
 
 // Create creates a new memo
@@ -46,83 +239,247 @@ func (r *MemoRepository) Create(ctx context.Context, memo *domain.Memo) (*domain
 		Tags:      memo.Tags,
 		Priority:  memo.Priority,
 		Status:    domain.StatusActive,
+		DueDate:   memo.DueDate,
+		RemindAt:  memo.RemindAt,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 
+	// 認証済みの場合は所有者を設定
+	var userID interface{}
+	if id, ok := domain.UserIDFromContext(ctx); ok {
+		userID = id
+	}
+
 	query := `
-		INSERT INTO memos (title, content, category, tags, priority, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO memos (title, content, category, tags, priority, status, created_at, updated_at, user_id, due_date, remind_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id`
 
-	err = r.db.QueryRowContext(ctx, query,
+	err = r.q.QueryRowContext(ctx, query,
 		newMemo.Title, newMemo.Content, newMemo.Category, string(tagsJSON),
-		string(newMemo.Priority), string(newMemo.Status), newMemo.CreatedAt, newMemo.UpdatedAt,
+		string(newMemo.Priority), string(newMemo.Status), newMemo.CreatedAt, newMemo.UpdatedAt, userID, newMemo.DueDate, newMemo.RemindAt,
 	).Scan(&newMemo.ID)
 
 	if err != nil {
-		r.logger.WithError(err).Error("メモの作成に失敗")
+		r.log(ctx).WithError(err).Error("メモの作成に失敗")
 		return nil, fmt.Errorf("failed to create memo: %w", err)
 	}
 
-	r.logger.WithField("memo_id", newMemo.ID).Info("メモを作成しました")
+	r.log(ctx).WithField("memo_id", newMemo.ID).Info("メモを作成しました")
 	return newMemo, nil
 }
 
+// CreateMany inserts memos with newly assigned IDs in a single transaction; any failure rolls back all of them.
+func (r *MemoRepository) CreateMany(ctx context.Context, memos []domain.Memo) ([]domain.Memo, error) {
+	// 認証済みの場合は所有者を設定
+	var userID interface{}
+	if id, ok := domain.UserIDFromContext(ctx); ok {
+		userID = id
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	created := make([]domain.Memo, 0, len(memos))
+	for i, memo := range memos {
+		tagsJSON, err := json.Marshal(memo.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal tags: %w", err)
+		}
+
+		newMemo := domain.Memo{
+			Title:     memo.Title,
+			Content:   memo.Content,
+			Category:  memo.Category,
+			Tags:      memo.Tags,
+			Priority:  memo.Priority,
+			Status:    domain.StatusActive,
+			DueDate:   memo.DueDate,
+			RemindAt:  memo.RemindAt,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO memos (title, content, category, tags, priority, status, created_at, updated_at, user_id, due_date, remind_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING id`,
+			newMemo.Title, newMemo.Content, newMemo.Category, string(tagsJSON),
+			string(newMemo.Priority), string(newMemo.Status), newMemo.CreatedAt, newMemo.UpdatedAt, userID, newMemo.DueDate, newMemo.RemindAt,
+		).Scan(&newMemo.ID)
+		if err != nil {
+			r.log(ctx).WithError(err).WithField("index", i).Error("メモの一括作成に失敗")
+			return nil, fmt.Errorf("failed to create memo at index %d: %w", i, err)
+		}
+		created = append(created, newMemo)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit memo creation: %w", err)
+	}
+
+	r.log(ctx).WithField("count", len(created)).Info("メモを一括作成しました")
+	return created, nil
+}
+
+// CreateWithIDs inserts memos keeping their given IDs in a single transaction, then advances the
+// id sequence to the largest ID in the table so later Create calls do not collide.
+// An ID that is already taken fails the whole import with a "memo id already exists" error.
+func (r *MemoRepository) CreateWithIDs(ctx context.Context, memos []domain.Memo) ([]domain.Memo, error) {
+	// 認証済みの場合は所有者を設定
+	var userID interface{}
+	if id, ok := domain.UserIDFromContext(ctx); ok {
+		userID = id
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	created := make([]domain.Memo, 0, len(memos))
+	for _, memo := range memos {
+		tagsJSON, err := json.Marshal(memo.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal tags: %w", err)
+		}
+
+		newMemo := domain.Memo{
+			ID:        memo.ID,
+			Title:     memo.Title,
+			Content:   memo.Content,
+			Category:  memo.Category,
+			Tags:      memo.Tags,
+			Priority:  memo.Priority,
+			Status:    domain.StatusActive,
+			DueDate:   memo.DueDate,
+			RemindAt:  memo.RemindAt,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO memos (id, title, content, category, tags, priority, status, created_at, updated_at, user_id, due_date, remind_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+			newMemo.ID, newMemo.Title, newMemo.Content, newMemo.Category, string(tagsJSON),
+			string(newMemo.Priority), string(newMemo.Status), newMemo.CreatedAt, newMemo.UpdatedAt, userID, newMemo.DueDate, newMemo.RemindAt,
+		)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+				return nil, fmt.Errorf("memo id already exists: %d", memo.ID)
+			}
+			r.log(ctx).WithError(err).WithField("memo_id", memo.ID).Error("ID指定でのメモの作成に失敗")
+			return nil, fmt.Errorf("failed to import memo %d: %w", memo.ID, err)
+		}
+		created = append(created, newMemo)
+	}
+
+	// 以降の通常作成でIDが衝突しないよう、シーケンスを最大IDまで進める
+	if _, err := tx.ExecContext(ctx, `SELECT setval(pg_get_serial_sequence('memos', 'id'), (SELECT MAX(id) FROM memos))`); err != nil {
+		r.log(ctx).WithError(err).Error("メモIDシーケンスの更新に失敗")
+		return nil, fmt.Errorf("failed to advance memo id sequence: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit memo import: %w", err)
+	}
+
+	r.log(ctx).WithField("count", len(created)).Info("ID指定でメモをインポートしました")
+	return created, nil
+}
+
 // GetByID retrieves a memo by ID
 func (r *MemoRepository) GetByID(ctx context.Context, id int) (*domain.Memo, error) {
-	query := `
-		SELECT id, title, content, category, tags, priority, status, created_at, updated_at, completed_at
-		FROM memos WHERE id = $1`
+	return r.getByID(ctx, id, "")
+}
 
-	var memo domain.Memo
-	var tagsJSON string
-	var priorityStr string
-	var statusStr string
-	var completedAt sql.NullTime
+// retryRead runs an idempotent read with the retry settings of the database. Inside WithinTx it runs once,
+// since a failed statement aborts the transaction and running it again cannot succeed
+func (r *MemoRepository) retryRead(ctx context.Context, fn func() error) error {
+	if r.tx != nil {
+		return fn()
+	}
+	return database.Retry(ctx, r.db.ReadRetry, r.log(ctx), fn)
+}
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&memo.ID, &memo.Title, &memo.Content, &memo.Category, &tagsJSON,
-		&priorityStr, &statusStr, &memo.CreatedAt, &memo.UpdatedAt, &completedAt,
-	)
+// WithinTx runs fn with a repository whose operations all use one transaction.
+// Nested calls join the outer transaction
+func (r *MemoRepository) WithinTx(ctx context.Context, fn func(tx domain.MemoTx) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+	return r.db.WithTx(ctx, func(tx *sql.Tx) error {
+		txRepo := *r
+		txRepo.q = tx
+		txRepo.tx = tx
+		return fn(&txRepo)
+	})
+}
+
+// GetByIDForUpdate retrieves a memo and locks its row until the transaction ends.
+// Outside WithinTx the lock is released as soon as the statement finishes
+func (r *MemoRepository) GetByIDForUpdate(ctx context.Context, id int) (*domain.Memo, error) {
+	return r.getByID(ctx, id, " FOR UPDATE")
+}
 
+// getByID retrieves a memo of the authenticated user, appending lock (e.g. FOR UPDATE) to the query
+func (r *MemoRepository) getByID(ctx context.Context, id int, lock string) (*domain.Memo, error) {
+	query, args := userScope(ctx, `SELECT `+memoColumns+` FROM memos WHERE id = $1`, []interface{}{id})
+
+	var memo *domain.Memo
+	err := r.retryRead(ctx, func() error {
+		var err error
+		memo, err = scanMemo(r.q.QueryRowContext(ctx, query+lock, args...))
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("memo not found")
 		}
-		r.logger.WithError(err).WithField("memo_id", id).Error("メモの取得に失敗")
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモの取得に失敗")
 		return nil, fmt.Errorf("failed to get memo: %w", err)
 	}
 
-	// JSON文字列からタグを復元
-	if err := json.Unmarshal([]byte(tagsJSON), &memo.Tags); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
-	}
+	return memo, nil
+}
 
-	memo.Priority = domain.Priority(priorityStr)
-	memo.Status = domain.Status(statusStr)
-	if completedAt.Valid {
-		memo.CompletedAt = &completedAt.Time
+// Exists reports whether a memo with the ID exists for any user
+func (r *MemoRepository) Exists(ctx context.Context, id int) (bool, error) {
+	var exists bool
+	err := r.q.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM memos WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモの存在確認に失敗")
+		return false, fmt.Errorf("failed to check memo existence: %w", err)
 	}
-
-	return &memo, nil
+	return exists, nil
 }
 
 // List retrieves memos with filtering
 func (r *MemoRepository) List(ctx context.Context, filter domain.MemoFilter) ([]domain.Memo, int, error) {
-	// ベースクエリ
-	baseQuery := `FROM memos WHERE 1=1`
-	countQuery := `SELECT COUNT(*) ` + baseQuery
-	selectQuery := `
-		SELECT id, title, content, category, tags, priority, status, created_at, updated_at, completed_at
-		` + baseQuery
+	return r.list(ctx, filter, "")
+}
 
-	var args []interface{}
-	argIndex := 1
+// list retrieves memos with filtering. A non-empty fullText restricts the result to memos whose
+// search_vector matches it and, unless a sort or cursor is given, orders them by relevance.
+func (r *MemoRepository) list(ctx context.Context, filter domain.MemoFilter, fullText string) ([]domain.Memo, int, error) {
+	// ベースクエリ（認証済みの場合はユーザーのメモに限定）
+	baseQuery, args := userScope(ctx, `FROM memos WHERE 1=1`, nil)
+	argIndex := len(args) + 1
 
 	// フィルター条件を追加
 	if filter.Category != "" {
-		baseQuery += fmt.Sprintf(" AND category = $%d", argIndex)
+		if r.config.CaseInsensitiveCategory {
+			baseQuery += fmt.Sprintf(" AND lower(category) = lower($%d)", argIndex)
+		} else {
+			baseQuery += fmt.Sprintf(" AND category = $%d", argIndex)
+		}
 		args = append(args, filter.Category)
 		argIndex++
 	}
@@ -131,6 +488,9 @@ func (r *MemoRepository) List(ctx context.Context, filter domain.MemoFilter) ([]
 		baseQuery += fmt.Sprintf(" AND status = $%d", argIndex)
 		args = append(args, string(filter.Status))
 		argIndex++
+	} else {
+		// ステータス未指定の場合、ゴミ箱のメモは含めない
+		baseQuery += fmt.Sprintf(" AND status <> '%s'", domain.StatusTrashed)
 	}
 
 	if filter.Priority != "" {
@@ -139,6 +499,15 @@ func (r *MemoRepository) List(ctx context.Context, filter domain.MemoFilter) ([]
 		argIndex++
 	}
 
+	// 全文検索（関連度の計算でも同じパラメータを参照する）
+	rankColumn := ""
+	if fullText != "" {
+		baseQuery += fmt.Sprintf(" AND search_vector @@ plainto_tsquery('%s', $%d)", fullTextConfig, argIndex)
+		rankColumn = fmt.Sprintf(", ts_rank(search_vector, plainto_tsquery('%s', $%d)) AS rank", fullTextConfig, argIndex)
+		args = append(args, fullText)
+		argIndex++
+	}
+
 	if filter.Search != "" {
 		baseQuery += fmt.Sprintf(" AND (title ILIKE $%d OR content ILIKE $%d)", argIndex, argIndex)
 		// LIKE演算子用のエスケープ処理
@@ -148,71 +517,110 @@ func (r *MemoRepository) List(ctx context.Context, filter domain.MemoFilter) ([]
 	}
 
 	if len(filter.Tags) > 0 {
-		for _, tag := range filter.Tags {
-			baseQuery += fmt.Sprintf(" AND tags::text ILIKE $%d", argIndex)
-			// タグもエスケープ処理
-			escapedTag := r.sqlSanitizer.EscapeForLike(tag)
-			args = append(args, "%"+escapedTag+"%")
-			argIndex++
+		// JSONBの包含演算子で、指定したすべてのタグを含むメモに限定（完全一致・AND条件）
+		tagsJSON, err := json.Marshal(filter.Tags)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to marshal tag filter: %w", err)
 		}
+		baseQuery += fmt.Sprintf(" AND tags @> $%d::jsonb", argIndex)
+		args = append(args, string(tagsJSON))
+		argIndex++
+	}
+
+	if filter.DueBefore != nil {
+		baseQuery += fmt.Sprintf(" AND due_date < $%d", argIndex)
+		args = append(args, *filter.DueBefore)
+		argIndex++
+	}
+
+	if filter.DueAfter != nil {
+		baseQuery += fmt.Sprintf(" AND due_date > $%d", argIndex)
+		args = append(args, *filter.DueAfter)
+		argIndex++
 	}
 
-	// 更新されたクエリ
-	countQuery = `SELECT COUNT(*) ` + baseQuery
-	selectQuery = `
-		SELECT id, title, content, category, tags, priority, status, created_at, updated_at, completed_at
-		` + baseQuery
+	if filter.Overdue {
+		// 期限切れは未完了（active）のメモのみを対象にする
+		baseQuery += fmt.Sprintf(" AND due_date < NOW() AND status = '%s'", domain.StatusActive)
+	}
+
+	countQuery := `SELECT COUNT(*) ` + baseQuery
+	countArgs := args
+	selectQuery := `SELECT ` + memoColumns + rankColumn + ` ` + baseQuery
+
+	// ページネーションを追加
+	if filter.Cursor != nil {
+		// カーソル指定時はキーセットページネーション（総数はカーソル条件を含めずに数える）
+		if !filter.Cursor.IsZero() {
+			selectQuery += fmt.Sprintf(" AND (pinned, created_at, id) < ($%d, $%d, $%d)", argIndex, argIndex+1, argIndex+2)
+			args = append(args, filter.Cursor.Pinned, filter.Cursor.CreatedAt, filter.Cursor.ID)
+			argIndex += 3
+		}
+		selectQuery += " ORDER BY " + memoCursorOrder
+		selectQuery += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, filter.Limit)
+	} else {
+		orderBy, err := memoOrderBy(filter.Sort)
+		if err != nil {
+			return nil, 0, err
+		}
+		if fullText != "" && len(filter.Sort) == 0 {
+			// 並び順の指定がない全文検索は関連度の高い順
+			orderBy = "rank DESC, " + orderBy
+		}
+		selectQuery += " ORDER BY " + orderBy
+		selectQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+		args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	}
 
-	// 総数を取得
+	// 総数とメモを取得（一時的なエラーの場合は両方をやり直す）
+	var memos []domain.Memo
 	var total int
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	err := r.retryRead(ctx, func() error {
+		var err error
+		memos, total, err = r.queryList(ctx, countQuery, countArgs, selectQuery, args, fullText != "")
+		return err
+	})
 	if err != nil {
-		r.logger.WithError(err).Error("メモ総数の取得に失敗")
-		return nil, 0, fmt.Errorf("failed to count memos: %w", err)
+		return nil, 0, err
 	}
+	return memos, total, nil
+}
 
-	// ページネーションを追加
-	selectQuery += " ORDER BY updated_at DESC"
-	selectQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+// queryList runs the count and select queries built by list. With ranked the select query
+// returns the full-text rank after the memo columns
+func (r *MemoRepository) queryList(ctx context.Context, countQuery string, countArgs []interface{}, selectQuery string, args []interface{}, ranked bool) ([]domain.Memo, int, error) {
+	var total int
+	if err := r.q.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		r.log(ctx).WithError(err).Error("メモ総数の取得に失敗")
+		return nil, 0, fmt.Errorf("failed to count memos: %w", err)
+	}
 
 	// メモを取得
-	rows, err := r.db.QueryContext(ctx, selectQuery, args...)
+	rows, err := r.q.QueryContext(ctx, selectQuery, args...)
 	if err != nil {
-		r.logger.WithError(err).Error("メモリストの取得に失敗")
+		r.log(ctx).WithError(err).Error("メモリストの取得に失敗")
 		return nil, 0, fmt.Errorf("failed to get memos: %w", err)
 	}
 	defer rows.Close()
 
 	var memos []domain.Memo
 	for rows.Next() {
-		var memo domain.Memo
-		var tagsJSON string
-		var priorityStr string
-		var statusStr string
-		var completedAt sql.NullTime
-
-		err := rows.Scan(
-			&memo.ID, &memo.Title, &memo.Content, &memo.Category, &tagsJSON,
-			&priorityStr, &statusStr, &memo.CreatedAt, &memo.UpdatedAt, &completedAt,
-		)
-		if err != nil {
-			r.logger.WithError(err).Error("メモのスキャンに失敗")
-			return nil, 0, fmt.Errorf("failed to scan memo: %w", err)
+		var scanner rowScanner = rows
+		var rank float64
+		if ranked {
+			scanner = extraColumnsScanner{rowScanner: rows, extra: []interface{}{&rank}}
 		}
 
-		// JSON文字列からタグを復元
-		if err := json.Unmarshal([]byte(tagsJSON), &memo.Tags); err != nil {
-			return nil, 0, fmt.Errorf("failed to unmarshal tags: %w", err)
+		memo, err := scanMemo(scanner)
+		if err != nil {
+			r.log(ctx).WithError(err).Error("メモのスキャンに失敗")
+			return nil, 0, fmt.Errorf("failed to scan memo: %w", err)
 		}
-
-		memo.Priority = domain.Priority(priorityStr)
-		memo.Status = domain.Status(statusStr)
-		if completedAt.Valid {
-			memo.CompletedAt = &completedAt.Time
+		if ranked {
+			memo.SearchRank = &rank
 		}
-
-		memos = append(memos, memo)
+		memos = append(memos, *memo)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -222,6 +630,36 @@ func (r *MemoRepository) List(ctx context.Context, filter domain.MemoFilter) ([]
 	return memos, total, nil
 }
 
+// ForEach streams every memo of the caller (all statuses, including trashed) in ID order to fn.
+// Rows are scanned one at a time so exports do not hold the whole result in memory.
+func (r *MemoRepository) ForEach(ctx context.Context, fn func(*domain.Memo) error) error {
+	query, args := userScope(ctx, `SELECT `+memoColumns+` FROM memos WHERE 1=1`, nil)
+	query += ` ORDER BY id ASC`
+
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log(ctx).WithError(err).Error("メモの走査に失敗")
+		return fmt.Errorf("failed to iterate memos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		memo, err := scanMemo(rows)
+		if err != nil {
+			r.log(ctx).WithError(err).Error("メモのスキャンに失敗")
+			return fmt.Errorf("failed to scan memo: %w", err)
+		}
+		if err := fn(memo); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+	return nil
+}
+
 // Update updates a memo
 func (r *MemoRepository) Update(ctx context.Context, id int, memo *domain.Memo) (*domain.Memo, error) {
 	// タグを JSON 文字列に変換
@@ -238,7 +676,36 @@ func (r *MemoRepository) Update(ctx context.Context, id int, memo *domain.Memo)
 		memo.CompletedAt = &now
 	}
 
-	query := `
+	// ゴミ箱に移動した日時はtrashedの間だけ保持する
+	if memo.Status != domain.StatusTrashed {
+		memo.TrashedAt = nil
+	} else if memo.TrashedAt == nil {
+		memo.TrashedAt = &now
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 上書きされる内容を同じトランザクションで履歴に残す（タイトル・本文・カテゴリー・タグ・優先度が変わらない更新では記録しない）
+	revisionQuery, revisionArgs := userScope(ctx, `
+		INSERT INTO memo_revisions (memo_id, title, content, category, tags, priority, created_at)
+		SELECT id, title, content, category, tags, priority, $7 FROM memos
+		WHERE id = $1
+			AND (title, content, category, tags, priority) IS DISTINCT FROM ($2::text, $3::text, $4::text, $5::jsonb, $6::text)`,
+		[]interface{}{id, memo.Title, memo.Content, memo.Category, string(tagsJSON), string(memo.Priority), now})
+	if memo.Version > 0 {
+		revisionArgs = append(revisionArgs, memo.Version)
+		revisionQuery += fmt.Sprintf(" AND version = $%d", len(revisionArgs))
+	}
+	if _, err := tx.ExecContext(ctx, revisionQuery, revisionArgs...); err != nil {
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモ履歴の記録に失敗")
+		return nil, fmt.Errorf("failed to record memo revision: %w", err)
+	}
+
+	query, args := userScope(ctx, `
 		UPDATE memos SET 
 			title = $2, 
 			content = $3, 
@@ -247,86 +714,287 @@ func (r *MemoRepository) Update(ctx context.Context, id int, memo *domain.Memo)
 			priority = $6, 
 			status = $7, 
 			updated_at = $8, 
-			completed_at = $9
-		WHERE id = $1
-		RETURNING id, title, content, category, tags, priority, status, created_at, updated_at, completed_at`
-
-	var updatedMemo domain.Memo
-	var tagsJSONResult string
-	var priorityStr string
-	var statusStr string
-	var completedAt sql.NullTime
-
-	err = r.db.QueryRowContext(ctx, query,
+			completed_at = $9,
+			trashed_at = $10,
+			due_date = $11,
+			remind_at = $12,
+			reminded = $13,
+			version = version + 1
+		WHERE id = $1`, []interface{}{
 		id, memo.Title, memo.Content, memo.Category, string(tagsJSON),
-		string(memo.Priority), string(memo.Status), memo.UpdatedAt, memo.CompletedAt,
-	).Scan(
-		&updatedMemo.ID, &updatedMemo.Title, &updatedMemo.Content, &updatedMemo.Category, &tagsJSONResult,
-		&priorityStr, &statusStr, &updatedMemo.CreatedAt, &updatedMemo.UpdatedAt, &completedAt,
-	)
+		string(memo.Priority), string(memo.Status), memo.UpdatedAt, memo.CompletedAt, memo.TrashedAt, memo.DueDate,
+		memo.RemindAt, memo.Reminded,
+	})
+	// 期待するバージョンが指定されている場合は、その後に更新されていないときだけ上書きする
+	if memo.Version > 0 {
+		args = append(args, memo.Version)
+		query += fmt.Sprintf(" AND version = $%d", len(args))
+	}
+	query += ` RETURNING ` + memoColumns
 
+	updatedMemo, err := scanMemo(tx.QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
+			if memo.Version > 0 {
+				return nil, r.versionConflictError(ctx, id, memo.Version)
+			}
 			return nil, fmt.Errorf("memo not found")
 		}
-		r.logger.WithError(err).WithField("memo_id", id).Error("メモの更新に失敗")
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモの更新に失敗")
 		return nil, fmt.Errorf("failed to update memo: %w", err)
 	}
 
-	// JSON文字列からタグを復元
-	if err := json.Unmarshal([]byte(tagsJSONResult), &updatedMemo.Tags); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit memo update: %w", err)
 	}
 
-	updatedMemo.Priority = domain.Priority(priorityStr)
-	updatedMemo.Status = domain.Status(statusStr)
-	if completedAt.Valid {
-		updatedMemo.CompletedAt = &completedAt.Time
+	r.log(ctx).WithField("memo_id", id).Info("メモを更新しました")
+	return updatedMemo, nil
+}
+
+// versionConflictError distinguishes a missing memo from one whose version no longer matches after a conditional update matched no rows
+func (r *MemoRepository) versionConflictError(ctx context.Context, id, expected int) error {
+	query, args := userScope(ctx, `SELECT version FROM memos WHERE id = $1`, []interface{}{id})
+
+	var current int
+	if err := r.q.QueryRowContext(ctx, query, args...).Scan(&current); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("memo not found")
+		}
+		return fmt.Errorf("failed to check memo version: %w", err)
+	}
+
+	r.log(ctx).WithFields(logrus.Fields{
+		"memo_id":  id,
+		"expected": expected,
+		"current":  current,
+	}).Warn("メモのバージョンが一致しないため更新を中止しました")
+	return fmt.Errorf("memo version conflict: expected %d, current %d", expected, current)
+}
+
+// UpdateMetadata applies the metadata update to a memo in a single UPDATE statement.
+// Title and content are never written, so concurrent content edits are not overwritten. As with Update,
+// the overwritten values are recorded as a revision in the same transaction.
+func (r *MemoRepository) UpdateMetadata(ctx context.Context, id int, update domain.MemoMetadataUpdate) (*domain.Memo, error) {
+	var tagsJSON *string
+	if update.Tags != nil {
+		data, err := json.Marshal(update.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal tags: %w", err)
+		}
+		s := string(data)
+		tagsJSON = &s
+	}
+	var priority *string
+	if update.Priority != nil {
+		s := string(*update.Priority)
+		priority = &s
+	}
+
+	now := time.Now()
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Update と同じく上書きされる内容を同じトランザクションで履歴に残す（カテゴリー・タグ・優先度が変わらない更新では記録しない）
+	revisionQuery, revisionArgs := userScope(ctx, `
+		INSERT INTO memo_revisions (memo_id, title, content, category, tags, priority, created_at)
+		SELECT id, title, content, category, tags, priority, $5 FROM memos
+		WHERE id = $1
+			AND (category, tags, priority) IS DISTINCT FROM
+				(COALESCE($2::text, category), COALESCE($3::jsonb, tags), COALESCE($4::text, priority))`,
+		[]interface{}{id, update.Category, tagsJSON, priority, now})
+	if _, err := tx.ExecContext(ctx, revisionQuery, revisionArgs...); err != nil {
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモ履歴の記録に失敗")
+		return nil, fmt.Errorf("failed to record memo revision: %w", err)
+	}
+
+	// 未指定の項目は現在の値を維持し、空文字の色は未設定（NULL）に戻す
+	// 期限は DueDateSet の場合だけ DueDate で置き換える（nil の場合は解除）
+	query, args := userScope(ctx, `
+		UPDATE memos SET
+			category = COALESCE($2, category),
+			tags = COALESCE($3::jsonb, tags),
+			priority = COALESCE($4, priority),
+			color = NULLIF(COALESCE($5, color), ''),
+			due_date = CASE WHEN $6 THEN $7::timestamptz ELSE due_date END,
+			updated_at = $8,
+			version = version + 1
+		WHERE id = $1`, []interface{}{
+		id, update.Category, tagsJSON, priority, update.Color, update.DueDateSet, update.DueDate, now,
+	})
+	query += ` RETURNING ` + memoColumns
+
+	memo, err := scanMemo(tx.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("memo not found")
+		}
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモのメタデータの更新に失敗")
+		return nil, fmt.Errorf("failed to update memo metadata: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit memo metadata update: %w", err)
 	}
 
-	r.logger.WithField("memo_id", id).Info("メモを更新しました")
-	return &updatedMemo, nil
+	r.log(ctx).WithField("memo_id", id).Info("メモのメタデータを更新しました")
+	return memo, nil
 }
 
-// Delete deletes a memo
+// stagedDeleteSet moves a memo one stage towards deletion: active becomes archived (recording when it
+// was completed) and archived becomes trashed (recording when it was trashed). $2 is the current time
+const stagedDeleteSet = `
+	status = CASE status WHEN 'active' THEN 'archived' ELSE 'trashed' END,
+	completed_at = CASE WHEN status = 'active' AND completed_at IS NULL THEN $2 ELSE completed_at END,
+	trashed_at = CASE WHEN status = 'archived' THEN $2 ELSE trashed_at END,
+	updated_at = $2,
+	version = version + 1`
+
+// Delete moves a memo one stage towards deletion (active → archived → trashed).
+// It never removes the row: a memo that is already in the trash is only removed by PermanentDelete
 func (r *MemoRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM memos WHERE id = $1`
+	query, args := userScope(ctx, `UPDATE memos SET `+stagedDeleteSet+` WHERE id = $1 AND status <> $3`,
+		[]interface{}{id, time.Now(), string(domain.StatusTrashed)})
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := r.q.ExecContext(ctx, query, args...)
 	if err != nil {
-		r.logger.WithError(err).WithField("memo_id", id).Error("メモの削除に失敗")
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモの削除に失敗")
 		return fmt.Errorf("failed to delete memo: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("削除件数の取得に失敗")
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("memo not found")
+		// 存在しないメモとすでにゴミ箱にあるメモを区別する
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return fmt.Errorf("memo is already in trash")
 	}
 
-	r.logger.WithField("memo_id", id).Info("メモを削除しました")
+	r.log(ctx).WithField("memo_id", id).Info("メモを削除しました（アーカイブまたはゴミ箱に移動）")
 	return nil
 }
 
-// Archive archives a memo
-func (r *MemoRepository) Archive(ctx context.Context, id int) error {
-	memo, err := r.GetByID(ctx, id)
+// DeleteMany moves the given memos one stage towards deletion in a single transaction, like Delete,
+// and returns the IDs actually changed. Memos that do not exist, belong to another user or are already
+// in the trash are skipped
+func (r *MemoRepository) DeleteMany(ctx context.Context, ids []int) ([]int, error) {
+	tx, err := r.beginTx(ctx)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	memo.Status = domain.StatusArchived
 	now := time.Now()
-	memo.CompletedAt = &now
+	deleted := make([]int, 0, len(ids))
+	for _, id := range ids {
+		query, args := userScope(ctx, `UPDATE memos SET `+stagedDeleteSet+` WHERE id = $1 AND status <> $3`,
+			[]interface{}{id, now, string(domain.StatusTrashed)})
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモの一括削除に失敗")
+			return nil, fmt.Errorf("failed to delete memo %d: %w", id, err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected > 0 {
+			deleted = append(deleted, id)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit bulk delete: %w", err)
+	}
+
+	r.log(ctx).WithFields(logrus.Fields{
+		"requested": len(ids),
+		"deleted":   len(deleted),
+	}).Info("メモを一括削除しました")
+	return deleted, nil
+}
+
+// BulkUpdate applies the update to the given memos in a single transaction and returns the IDs actually updated.
+// Memos that do not exist or belong to another user are skipped.
+func (r *MemoRepository) BulkUpdate(ctx context.Context, ids []int, update domain.MemoBulkUpdate) ([]int, error) {
+	var status *string
+	if update.Status != nil {
+		s := string(*update.Status)
+		status = &s
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	updated := make([]int, 0, len(ids))
+	for _, id := range ids {
+		// 未指定の項目は現在の値を維持し、archivedにする場合は完了日時を設定する
+		query, args := userScope(ctx, `
+			UPDATE memos SET
+				category = COALESCE($2, category),
+				status = COALESCE($3, status),
+				completed_at = CASE WHEN $3 = 'archived' AND completed_at IS NULL THEN $4 ELSE completed_at END,
+				updated_at = $4,
+				version = version + 1
+			WHERE id = $1`, []interface{}{id, update.Category, status, now})
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモの一括更新に失敗")
+			return nil, fmt.Errorf("failed to update memo %d: %w", id, err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected > 0 {
+			updated = append(updated, id)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit bulk update: %w", err)
+	}
+
+	r.log(ctx).WithFields(logrus.Fields{
+		"requested": len(ids),
+		"updated":   len(updated),
+	}).Info("メモを一括更新しました")
+	return updated, nil
+}
+
+// Archive archives a memo
+func (r *MemoRepository) Archive(ctx context.Context, id int) error {
+	memo, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	memo.Status = domain.StatusArchived
+	now := time.Now()
+	memo.CompletedAt = &now
 
 	_, err = r.Update(ctx, id, memo)
 	return err
 }
 
-// Restore restores an archived memo
+// Restore restores an archived or trashed memo
 func (r *MemoRepository) Restore(ctx context.Context, id int) error {
 	memo, err := r.GetByID(ctx, id)
 	if err != nil {
@@ -340,11 +1008,781 @@ func (r *MemoRepository) Restore(ctx context.Context, id int) error {
 	return err
 }
 
-// Search searches memos by query
+// Trash moves a memo to the trash, recording when it was trashed
+func (r *MemoRepository) Trash(ctx context.Context, id int) (*domain.Memo, error) {
+	query, args := userScope(ctx,
+		`UPDATE memos SET status = $2, trashed_at = $3, updated_at = $3 WHERE id = $1`,
+		[]interface{}{id, string(domain.StatusTrashed), time.Now()})
+	query += ` RETURNING ` + memoColumns
+
+	memo, err := scanMemo(r.q.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("memo not found")
+		}
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモのゴミ箱への移動に失敗")
+		return nil, fmt.Errorf("failed to trash memo: %w", err)
+	}
+
+	r.log(ctx).WithField("memo_id", id).Info("メモをゴミ箱に移動しました")
+	return memo, nil
+}
+
+// recycleDeleted wraps a DELETE FROM memos statement so that the deleted rows are copied to the recycle log
+// (deleted_memos) in the same statement, and neither change can be applied without the other.
+// The rows affected by the result are the number of deleted memos
+func recycleDeleted(deleteQuery string) string {
+	return `WITH deleted AS (` + deleteQuery + ` RETURNING id, user_id, ` + archivedMemoColumns + `)
+		INSERT INTO deleted_memos (memo_id, user_id, ` + archivedMemoColumns + `)
+		SELECT id, user_id, ` + archivedMemoColumns + ` FROM deleted`
+}
+
+// PermanentDelete physically deletes a memo, but only if it is already in the trash
+func (r *MemoRepository) PermanentDelete(ctx context.Context, id int) error {
+	// 削除と同じ文でリサイクルログに複製し、どちらか一方だけが反映されることがないようにする
+	deleteQuery, args := userScope(ctx, `DELETE FROM memos WHERE id = $1 AND status = $2`,
+		[]interface{}{id, string(domain.StatusTrashed)})
+
+	result, err := r.q.ExecContext(ctx, recycleDeleted(deleteQuery), args...)
+	if err != nil {
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモの完全削除に失敗")
+		return fmt.Errorf("failed to permanently delete memo: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("完全削除の件数の取得に失敗")
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		// 存在しないメモとゴミ箱にないメモを区別する
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return fmt.Errorf("memo is not in trash")
+	}
+
+	r.log(ctx).WithField("memo_id", id).Info("メモを完全に削除しました")
+	return nil
+}
+
+// ListDeleted lists the caller's recycle log entries, most recently deleted first.
+// Only Page and Limit of filter are used.
+func (r *MemoRepository) ListDeleted(ctx context.Context, filter domain.MemoFilter) ([]domain.DeletedMemo, int, error) {
+	deleted := []domain.DeletedMemo{}
+
+	baseQuery, args := userScope(ctx, `FROM deleted_memos WHERE 1=1`, nil)
+
+	var total int
+	if err := r.q.QueryRowContext(ctx, `SELECT COUNT(*) `+baseQuery, args...).Scan(&total); err != nil {
+		r.log(ctx).WithError(err).Error("削除済みメモ総数の取得に失敗")
+		return nil, 0, fmt.Errorf("failed to count deleted memos: %w", err)
+	}
+
+	selectQuery := `SELECT ` + deletedMemoColumns + `, id, deleted_at ` + baseQuery +
+		fmt.Sprintf(` ORDER BY deleted_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	rows, err := r.q.QueryContext(ctx, selectQuery, args...)
+	if err != nil {
+		r.log(ctx).WithError(err).Error("削除済みメモの取得に失敗")
+		return nil, 0, fmt.Errorf("failed to get deleted memos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.DeletedMemo
+		memo, err := scanMemo(extraColumnsScanner{
+			rowScanner: rows,
+			extra:      []interface{}{&item.ID, &item.DeletedAt},
+		})
+		if err != nil {
+			r.log(ctx).WithError(err).Error("削除済みメモのスキャンに失敗")
+			return nil, 0, fmt.Errorf("failed to scan deleted memo: %w", err)
+		}
+		item.Memo = *memo
+		deleted = append(deleted, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return deleted, total, nil
+}
+
+// RestoreDeleted removes a recycle log entry and inserts its memo back as an active memo in a single
+// transaction. The memo keeps its old ID when no memo has taken it since; otherwise it gets a new ID.
+func (r *MemoRepository) RestoreDeleted(ctx context.Context, id int) (*domain.Memo, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args := userScope(ctx, `DELETE FROM deleted_memos WHERE id = $1`, []interface{}{id})
+	query += ` RETURNING ` + deletedMemoColumns + `, user_id`
+
+	var userID sql.NullInt64
+	memo, err := scanMemo(extraColumnsScanner{
+		rowScanner: tx.QueryRowContext(ctx, query, args...),
+		extra:      []interface{}{&userID},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("deleted memo not found")
+		}
+		r.log(ctx).WithError(err).WithField("deleted_id", id).Error("削除済みメモの取得に失敗")
+		return nil, fmt.Errorf("failed to get deleted memo: %w", err)
+	}
+
+	tagsJSON, err := json.Marshal(memo.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	values := []interface{}{
+		memo.Title, memo.Content, memo.Category, string(tagsJSON), string(memo.Priority),
+		string(domain.StatusActive), memo.Pinned, memo.CreatedAt, time.Now(), memo.CompletedAt,
+		memo.DueDate, memo.RemindAt, memo.Reminded, memo.Color, memo.Version, userID,
+	}
+	const insertColumns = `title, content, category, tags, priority, status, pinned, created_at, updated_at, completed_at, due_date, remind_at, reminded, color, version, user_id`
+	const insertValues = `$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NULLIF($14, ''), $15, $16`
+
+	// 元のIDが空いていればそのまま使い、他のメモが使っている場合は新しいIDを採番する
+	oldID := memo.ID
+	restored, err := scanMemo(tx.QueryRowContext(ctx,
+		`INSERT INTO memos (id, `+insertColumns+`) VALUES (`+insertValues+`, $17)
+		ON CONFLICT (id) DO NOTHING RETURNING `+memoColumns,
+		append(values, oldID)...,
+	))
+	if err == sql.ErrNoRows {
+		restored, err = scanMemo(tx.QueryRowContext(ctx,
+			`INSERT INTO memos (`+insertColumns+`) VALUES (`+insertValues+`) RETURNING `+memoColumns,
+			values...,
+		))
+	}
+	if err != nil {
+		r.log(ctx).WithError(err).WithField("deleted_id", id).Error("削除済みメモの復元に失敗")
+		return nil, fmt.Errorf("failed to restore deleted memo: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit deleted memo restore: %w", err)
+	}
+
+	r.log(ctx).WithFields(logrus.Fields{
+		"deleted_id": id,
+		"old_id":     oldID,
+		"memo_id":    restored.ID,
+	}).Info("削除済みメモを復元しました")
+	return restored, nil
+}
+
+// PurgeDeletedOlderThan removes every recycle log entry deleted longer than age ago.
+// This is a maintenance operation and is intentionally not scoped to a user.
+func (r *MemoRepository) PurgeDeletedOlderThan(ctx context.Context, age time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-age)
+	result, err := r.q.ExecContext(ctx, `DELETE FROM deleted_memos WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		r.log(ctx).WithError(err).Error("削除済みメモの自動削除に失敗")
+		return 0, fmt.Errorf("failed to purge deleted memos: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.log(ctx).WithFields(logrus.Fields{
+		"purged": purged,
+		"cutoff": cutoff,
+	}).Info("保持期間を過ぎた削除済みメモを削除しました")
+	return purged, nil
+}
+
+// PurgeTrashedOlderThan permanently deletes every trashed memo whose trashed_at is older than age,
+// copying them to the recycle log like PermanentDelete does.
+// This is a maintenance operation and is intentionally not scoped to a user.
+func (r *MemoRepository) PurgeTrashedOlderThan(ctx context.Context, age time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-age)
+	result, err := r.q.ExecContext(ctx,
+		recycleDeleted(`DELETE FROM memos WHERE status = $1 AND trashed_at < $2`),
+		string(domain.StatusTrashed), cutoff)
+	if err != nil {
+		r.log(ctx).WithError(err).Error("ゴミ箱のメモの自動削除に失敗")
+		return 0, fmt.Errorf("failed to purge trashed memos: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.log(ctx).WithFields(logrus.Fields{
+		"purged": purged,
+		"cutoff": cutoff,
+	}).Info("ゴミ箱のメモを自動削除しました")
+	return purged, nil
+}
+
+// Promote sets the priority and pins the memo in a single atomic UPDATE
+func (r *MemoRepository) Promote(ctx context.Context, id int, priority domain.Priority) (*domain.Memo, error) {
+	query, args := userScope(ctx,
+		`UPDATE memos SET priority = $2, pinned = true, updated_at = $3 WHERE id = $1`,
+		[]interface{}{id, string(priority), time.Now()},
+	)
+	query += ` RETURNING ` + memoColumns
+
+	memo, err := scanMemo(r.q.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("memo not found")
+		}
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモの昇格に失敗")
+		return nil, fmt.Errorf("failed to promote memo: %w", err)
+	}
+
+	r.log(ctx).WithFields(logrus.Fields{
+		"memo_id":  id,
+		"priority": priority,
+	}).Info("メモを昇格しました")
+	return memo, nil
+}
+
+// Touch bumps updated_at without changing any other field
+func (r *MemoRepository) Touch(ctx context.Context, id int) (*domain.Memo, error) {
+	query, args := userScope(ctx, `UPDATE memos SET updated_at = $2 WHERE id = $1`, []interface{}{id, time.Now()})
+	query += ` RETURNING ` + memoColumns
+
+	memo, err := scanMemo(r.q.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("memo not found")
+		}
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモの更新日時の更新に失敗")
+		return nil, fmt.Errorf("failed to touch memo: %w", err)
+	}
+
+	r.log(ctx).WithField("memo_id", id).Debug("メモの更新日時を更新しました")
+	return memo, nil
+}
+
+// memoOwnerScope はメモIDを所有者で絞り込むサブクエリを返す（$1 がメモID）
+func memoOwnerScope(ctx context.Context, memoID int) (string, []interface{}) {
+	query, args := userScope(ctx, `SELECT id FROM memos WHERE id = $1`, []interface{}{memoID})
+	return `(` + query + `)`, args
+}
+
+// SetPinned pins or unpins a memo. updated_at is left unchanged so pinning does not reorder recency.
+func (r *MemoRepository) SetPinned(ctx context.Context, id int, pinned bool) (*domain.Memo, error) {
+	query, args := userScope(ctx, `UPDATE memos SET pinned = $2 WHERE id = $1`, []interface{}{id, pinned})
+	query += ` RETURNING ` + memoColumns
+
+	memo, err := scanMemo(r.q.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("memo not found")
+		}
+		r.log(ctx).WithError(err).WithField("memo_id", id).Error("メモのピン留めの変更に失敗")
+		return nil, fmt.Errorf("failed to set memo pinned: %w", err)
+	}
+
+	r.log(ctx).WithFields(logrus.Fields{
+		"memo_id": id,
+		"pinned":  pinned,
+	}).Info("メモのピン留めを変更しました")
+	return memo, nil
+}
+
+// GetRevision retrieves a single revision of a memo owned by the authenticated user
+func (r *MemoRepository) GetRevision(ctx context.Context, memoID, revisionID int) (*domain.MemoRevision, error) {
+	scope, args := memoOwnerScope(ctx, memoID)
+	args = append(args, revisionID)
+	query := fmt.Sprintf(`
+		SELECT id, memo_id, title, content, category, tags, priority, created_at
+		FROM memo_revisions
+		WHERE memo_id = %s AND id = $%d`, scope, len(args))
+
+	revision, err := scanMemoRevision(r.q.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("revision not found")
+		}
+		r.log(ctx).WithError(err).WithFields(logrus.Fields{
+			"memo_id":     memoID,
+			"revision_id": revisionID,
+		}).Error("メモ履歴の取得に失敗")
+		return nil, fmt.Errorf("failed to get memo revision: %w", err)
+	}
+	return revision, nil
+}
+
+// scanMemoRevision はmemo_revisionsの1行をMemoRevisionに変換する
+func scanMemoRevision(scanner rowScanner) (*domain.MemoRevision, error) {
+	var revision domain.MemoRevision
+	var tagsJSON string
+	var priorityStr string
+	var category sql.NullString
+	if err := scanner.Scan(
+		&revision.ID, &revision.MemoID, &revision.Title, &revision.Content,
+		&category, &tagsJSON, &priorityStr, &revision.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(tagsJSON), &revision.Tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+	revision.Category = category.String
+	revision.Priority = domain.Priority(priorityStr)
+	return &revision, nil
+}
+
+// ListRevisions retrieves revisions of a memo, newest first
+func (r *MemoRepository) ListRevisions(ctx context.Context, memoID int) ([]domain.MemoRevision, error) {
+	scope, args := memoOwnerScope(ctx, memoID)
+	query := `
+		SELECT id, memo_id, title, content, category, tags, priority, created_at
+		FROM memo_revisions
+		WHERE memo_id = ` + scope + `
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log(ctx).WithError(err).WithField("memo_id", memoID).Error("メモ履歴の取得に失敗")
+		return nil, fmt.Errorf("failed to get memo revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []domain.MemoRevision{}
+	for rows.Next() {
+		revision, err := scanMemoRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan memo revision: %w", err)
+		}
+		revisions = append(revisions, *revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// ListAttachments retrieves attachment metadata of a memo, oldest first
+func (r *MemoRepository) ListAttachments(ctx context.Context, memoID int) ([]domain.MemoAttachment, error) {
+	scope, args := memoOwnerScope(ctx, memoID)
+	query := `
+		SELECT ` + attachmentColumns + `
+		FROM memo_attachments
+		WHERE memo_id = ` + scope + `
+		ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log(ctx).WithError(err).WithField("memo_id", memoID).Error("添付ファイルの取得に失敗")
+		return nil, fmt.Errorf("failed to get memo attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []domain.MemoAttachment{}
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan memo attachment: %w", err)
+		}
+		attachments = append(attachments, *attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// RecordSearchQuery stores the query in the user's recent search history,
+// deduplicating repeated queries and keeping only the newest limit entries
+func (r *MemoRepository) RecordSearchQuery(ctx context.Context, query string, limit int) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	query = strings.TrimSpace(r.sqlSanitizer.SanitizeSearchQuery(query))
+	if query == "" {
+		return nil
+	}
+	// 列の長さは文字数のため、マルチバイト文字を途中で切らないようルーン単位で切り詰める
+	if runes := []rune(query); len(runes) > 200 {
+		query = string(runes[:200])
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 同じクエリは日時のみ更新して重複させない
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO search_queries (user_id, query, searched_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, query) DO UPDATE SET searched_at = EXCLUDED.searched_at`,
+		userID, query, time.Now(),
+	); err != nil {
+		r.log(ctx).WithError(err).Error("検索履歴の保存に失敗")
+		return fmt.Errorf("failed to record search query: %w", err)
+	}
+
+	// 上限を超えた古い履歴を削除
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM search_queries
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM search_queries WHERE user_id = $1
+			ORDER BY searched_at DESC, id DESC
+			LIMIT $2
+		)`,
+		userID, limit,
+	); err != nil {
+		r.log(ctx).WithError(err).Error("古い検索履歴の削除に失敗")
+		return fmt.Errorf("failed to trim search queries: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListSearchQueries retrieves the user's recent search queries, newest first
+func (r *MemoRepository) ListSearchQueries(ctx context.Context) ([]string, error) {
+	queries := []string{}
+
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return queries, nil
+	}
+
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT query FROM search_queries
+		WHERE user_id = $1
+		ORDER BY searched_at DESC, id DESC`, userID)
+	if err != nil {
+		r.log(ctx).WithError(err).Error("検索履歴の取得に失敗")
+		return nil, fmt.Errorf("failed to get search queries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var query string
+		if err := rows.Scan(&query); err != nil {
+			return nil, fmt.Errorf("failed to scan search query: %w", err)
+		}
+		queries = append(queries, query)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return queries, nil
+}
+
+// ListTags returns tags in use with their memo counts, ordered by count desc then tag asc.
+// Tags are normalized the same way as on write (NFKC, trimmed, inner whitespace collapsed, lowercased
+// only with CaseInsensitiveTags), and a memo is counted once per tag even if it holds duplicates.
+// Memos in the trash are not counted.
+func (r *MemoRepository) ListTags(ctx context.Context, filter domain.TagFilter) ([]domain.TagCount, error) {
+	// jsonb_array_elements_text でタグを展開して集計（認証済みの場合はユーザーのメモに限定）
+	query, args := userScope(ctx, `
+		SELECT tag, COUNT(DISTINCT id) AS count
+		FROM memos CROSS JOIN LATERAL (
+			SELECT `+normalizedLabelSQL("raw_tag", r.config.CaseInsensitiveTags)+` AS tag
+			FROM jsonb_array_elements_text(
+				CASE WHEN jsonb_typeof(tags) = 'array' THEN tags ELSE '[]'::jsonb END
+			) AS raw_tag
+		) AS normalized
+		WHERE tag <> '' AND status <> '`+string(domain.StatusTrashed)+`'`, nil)
+	args = append(args, filter.MinCount, filter.Limit)
+	// min_count は表示する件数と同じく、重複タグを1件としたメモ数で判定する
+	query += fmt.Sprintf(`
+		GROUP BY tag
+		HAVING COUNT(DISTINCT id) >= $%d
+		ORDER BY count DESC, tag ASC
+		LIMIT $%d`, len(args)-1, len(args))
+
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log(ctx).WithError(err).Error("タグ一覧の取得に失敗")
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []domain.TagCount{}
+	for rows.Next() {
+		var tag domain.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return tags, nil
+}
+
+// normalizedLabelSQL returns an SQL expression normalizing a tag or category column the same way
+// as on write (NFKC, trimmed, inner whitespace collapsed, optionally lowercased), so values stored
+// before normalization still aggregate with their normalized variants
+func normalizedLabelSQL(column string, lowercase bool) string {
+	normalized := fmt.Sprintf(`btrim(regexp_replace(normalize(%s, NFKC), '\s+', ' ', 'g'))`, column)
+	if lowercase {
+		return "lower(" + normalized + ")"
+	}
+	return normalized
+}
+
+// ListCategories lists the distinct non-empty categories, alphabetically.
+// Like ListTags it covers the authenticated user's memos, or all memos without one.
+func (r *MemoRepository) ListCategories(ctx context.Context) ([]string, error) {
+	categories := []string{}
+
+	categoryExpr := normalizedLabelSQL("category", r.config.CaseInsensitiveCategory)
+	query, args := userScope(ctx, `
+		SELECT DISTINCT `+categoryExpr+` AS category FROM memos
+		WHERE `+categoryExpr+` <> ''`, nil)
+	rows, err := r.q.QueryContext(ctx, query+`
+		ORDER BY category`, args...)
+	if err != nil {
+		r.log(ctx).WithError(err).Error("カテゴリー一覧の取得に失敗")
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category string
+		if err := rows.Scan(&category); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return categories, nil
+}
+
+// Stats counts the memos of the authenticated user (all memos without one, like ListTags) in one query
+// with FILTER clauses plus one grouped query per category. Users without memos get zero counts rather than missing keys.
+func (r *MemoRepository) Stats(ctx context.Context) (*domain.MemoStats, error) {
+	stats := domain.NewMemoStats()
+	now := time.Now()
+
+	query, args := userScope(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'active'),
+			COUNT(*) FILTER (WHERE status = 'archived'),
+			COUNT(*) FILTER (WHERE status = 'trashed'),
+			COUNT(*) FILTER (WHERE status <> 'trashed' AND priority = 'low'),
+			COUNT(*) FILTER (WHERE status <> 'trashed' AND priority = 'medium'),
+			COUNT(*) FILTER (WHERE status <> 'trashed' AND priority = 'high'),
+			COUNT(*) FILTER (WHERE status <> 'trashed' AND created_at >= $1),
+			COUNT(*) FILTER (WHERE status <> 'trashed' AND created_at >= $2)
+		FROM memos
+		WHERE 1=1`, []interface{}{now.AddDate(0, 0, -7), now.AddDate(0, 0, -30)})
+
+	var active, archived, trashed, low, medium, high int
+	err := r.q.QueryRowContext(ctx, query, args...).
+		Scan(&active, &archived, &trashed, &low, &medium, &high, &stats.CreatedLast7Days, &stats.CreatedLast30Days)
+	if err != nil {
+		r.log(ctx).WithError(err).Error("メモの統計の取得に失敗")
+		return nil, fmt.Errorf("failed to get memo stats: %w", err)
+	}
+
+	stats.Total = active + archived
+	stats.ByStatus[domain.StatusActive] = active
+	stats.ByStatus[domain.StatusArchived] = archived
+	stats.ByStatus[domain.StatusTrashed] = trashed
+	stats.ByPriority[domain.PriorityLow] = low
+	stats.ByPriority[domain.PriorityMedium] = medium
+	stats.ByPriority[domain.PriorityHigh] = high
+
+	categoryExpr := normalizedLabelSQL("category", r.config.CaseInsensitiveCategory)
+	categoryQuery, categoryArgs := userScope(ctx, `
+		SELECT `+categoryExpr+` AS category, COUNT(*) FROM memos
+		WHERE status <> 'trashed' AND `+categoryExpr+` <> ''`, nil)
+	rows, err := r.q.QueryContext(ctx, categoryQuery+`
+		GROUP BY 1`, categoryArgs...)
+	if err != nil {
+		r.log(ctx).WithError(err).Error("カテゴリー別の件数の取得に失敗")
+		return nil, fmt.Errorf("failed to count memos by category: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category string
+		var count int
+		if err := rows.Scan(&category, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan category count: %w", err)
+		}
+		stats.ByCategory[category] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return stats, nil
+}
+
+// MaxUpdatedAt aggregates the authenticated user's memos (all memos without one) of every status in a single query.
+// Pinning and reminding leave updated_at alone, so the pinned IDs and reminded count are included too
+func (r *MemoRepository) MaxUpdatedAt(ctx context.Context) (*domain.MemoListState, error) {
+	query, args := userScope(ctx, `
+		SELECT
+			MAX(updated_at),
+			COUNT(*),
+			COALESCE(string_agg(id::text, ',' ORDER BY id) FILTER (WHERE pinned), ''),
+			COUNT(*) FILTER (WHERE reminded)
+		FROM memos
+		WHERE 1=1`, nil)
+
+	var state domain.MemoListState
+	err := r.retryRead(ctx, func() error {
+		var maxUpdatedAt sql.NullTime
+		err := r.q.QueryRowContext(ctx, query, args...).Scan(&maxUpdatedAt, &state.Count, &state.PinnedIDs, &state.RemindedCount)
+		state.MaxUpdatedAt = maxUpdatedAt.Time
+		return err
+	})
+	if err != nil {
+		r.log(ctx).WithError(err).Error("メモ一覧の更新状況の取得に失敗")
+		return nil, fmt.Errorf("failed to get memo list state: %w", err)
+	}
+	return &state, nil
+}
+
+// ListSharedWithUser lists memos other users have shared with the authenticated user, newest share first
+func (r *MemoRepository) ListSharedWithUser(ctx context.Context, filter domain.MemoFilter) ([]domain.SharedMemo, int, error) {
+	shared := []domain.SharedMemo{}
+
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return shared, 0, nil
+	}
+
+	// 自分のメモやゴミ箱のメモは含めない
+	baseQuery := `
+		FROM memo_shares s
+		JOIN memos m ON m.id = s.memo_id
+		JOIN users u ON u.id = m.user_id
+		WHERE s.shared_with_user_id = $1 AND m.user_id <> $1 AND m.status <> $2`
+	args := []interface{}{userID, string(domain.StatusTrashed)}
+
+	var total int
+	if err := r.q.QueryRowContext(ctx, `SELECT COUNT(*) `+baseQuery, args...).Scan(&total); err != nil {
+		r.log(ctx).WithError(err).Error("共有メモ総数の取得に失敗")
+		return nil, 0, fmt.Errorf("failed to count shared memos: %w", err)
+	}
+
+	selectQuery := `SELECT ` + qualifiedMemoColumns("m") + `, u.username, s.permission, s.created_at ` + baseQuery +
+		` ORDER BY s.created_at DESC, s.id DESC LIMIT $3 OFFSET $4`
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	rows, err := r.q.QueryContext(ctx, selectQuery, args...)
+	if err != nil {
+		r.log(ctx).WithError(err).Error("共有メモの取得に失敗")
+		return nil, 0, fmt.Errorf("failed to get shared memos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.SharedMemo
+		var permission string
+		memo, err := scanMemo(extraColumnsScanner{
+			rowScanner: rows,
+			extra:      []interface{}{&item.OwnerUsername, &permission, &item.SharedAt},
+		})
+		if err != nil {
+			r.log(ctx).WithError(err).Error("共有メモのスキャンに失敗")
+			return nil, 0, fmt.Errorf("failed to scan shared memo: %w", err)
+		}
+		item.Memo = *memo
+		item.Permission = domain.SharePermission(permission)
+		shared = append(shared, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return shared, total, nil
+}
+
+// ListOnThisDay lists the caller's memos created on the same month and day as date in earlier years,
+// newest first. Trashed memos are excluded.
+func (r *MemoRepository) ListOnThisDay(ctx context.Context, date time.Time) ([]domain.Memo, error) {
+	query, args := userScope(ctx, `
+		SELECT `+memoColumns+`
+		FROM memos
+		WHERE EXTRACT(MONTH FROM created_at) = $1
+			AND EXTRACT(DAY FROM created_at) = $2
+			AND EXTRACT(YEAR FROM created_at) < $3
+			AND status <> $4`,
+		[]interface{}{int(date.Month()), date.Day(), date.Year(), string(domain.StatusTrashed)},
+	)
+	query += ` ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log(ctx).WithError(err).Error("過去の同じ日のメモの取得に失敗")
+		return nil, fmt.Errorf("failed to get on-this-day memos: %w", err)
+	}
+	defer rows.Close()
+
+	memos := []domain.Memo{}
+	for rows.Next() {
+		memo, err := scanMemo(rows)
+		if err != nil {
+			r.log(ctx).WithError(err).Error("メモのスキャンに失敗")
+			return nil, fmt.Errorf("failed to scan memo: %w", err)
+		}
+		memos = append(memos, *memo)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return memos, nil
+}
+
+// ClearSearchQueries deletes the user's recent search history
+func (r *MemoRepository) ClearSearchQueries(ctx context.Context) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if _, err := r.q.ExecContext(ctx, `DELETE FROM search_queries WHERE user_id = $1`, userID); err != nil {
+		r.log(ctx).WithError(err).Error("検索履歴の削除に失敗")
+		return fmt.Errorf("failed to clear search queries: %w", err)
+	}
+
+	r.log(ctx).Info("検索履歴を削除しました")
+	return nil
+}
+
+// Search searches memos by query using PostgreSQL full-text search ranked by ts_rank.
+// Queries shorter than minFullTextQueryLength, and queries containing Japanese or Chinese text, fall back to
+// substring matching without a rank.
 func (r *MemoRepository) Search(ctx context.Context, query string, filter domain.MemoFilter) ([]domain.Memo, int, error) {
 	// 検索クエリのバリデーションとサニタイゼーション
 	if err := r.sqlSanitizer.ValidateSearchQuery(query); err != nil {
-		r.logger.WithError(err).WithField("query", query).Error("危険な検索クエリが検出されました")
+		r.log(ctx).WithError(err).WithField("query", query).Error("危険な検索クエリが検出されました")
 		return nil, 0, fmt.Errorf("invalid search query: %w", err)
 	}
 
@@ -354,11 +1792,17 @@ func (r *MemoRepository) Search(ctx context.Context, query string, filter domain
 	// ページネーションパラメータのバリデーション
 	offset := (filter.Page - 1) * filter.Limit
 	if err := r.sqlSanitizer.ValidateLimitOffset(filter.Limit, offset); err != nil {
-		r.logger.WithError(err).Error("無効なページネーションパラメータ")
+		r.log(ctx).WithError(err).Error("無効なページネーションパラメータ")
 		return nil, 0, fmt.Errorf("invalid pagination: %w", err)
 	}
 
-	// サニタイズされた検索クエリをフィルターに設定
-	filter.Search = sanitizedQuery
-	return r.List(ctx, filter)
+	// 短いクエリは全文検索の単語に一致しにくいため、部分一致（ILIKE）にフォールバック
+	// 'simple' 設定は空白のない日本語・中国語の文を1語として扱い、語の一部では一致しないため同様に部分一致で検索する
+	if utf8.RuneCountInString(strings.TrimSpace(sanitizedQuery)) < minFullTextQueryLength ||
+		domain.ContainsSpacelessScript(sanitizedQuery) {
+		filter.Search = sanitizedQuery
+		return r.list(ctx, filter, "")
+	}
+	filter.Search = ""
+	return r.list(ctx, filter, sanitizedQuery)
 }
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReminderRepository implements domain.ReminderRepository.
+// It is used by the background scheduler and therefore is not scoped to the authenticated user.
+type ReminderRepository struct {
+	db     *database.DB
+	logger *logrus.Logger
+}
+
+// NewReminderRepository creates a new reminder repository
+func NewReminderRepository(db *database.DB, logger *logrus.Logger) domain.ReminderRepository {
+	return &ReminderRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListDueReminders lists active memos whose reminder time has passed and has not been sent yet.
+// Archived and trashed memos are skipped; memos without an owner have nobody to notify and are skipped too.
+func (r *ReminderRepository) ListDueReminders(ctx context.Context, now time.Time, limit int) ([]domain.MemoReminder, error) {
+	query := `SELECT ` + memoColumns + `, user_id FROM memos
+		WHERE remind_at <= $1 AND reminded = false AND status = $2 AND user_id IS NOT NULL
+		ORDER BY remind_at, id
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, now, string(domain.StatusActive), limit)
+	if err != nil {
+		r.logger.WithError(err).Error("通知対象のリマインダーの取得に失敗")
+		return nil, fmt.Errorf("failed to list due reminders: %w", err)
+	}
+	defer rows.Close()
+
+	reminders := []domain.MemoReminder{}
+	for rows.Next() {
+		var reminder domain.MemoReminder
+		memo, err := scanMemo(extraColumnsScanner{
+			rowScanner: rows,
+			extra:      []interface{}{&reminder.UserID},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminder.Memo = *memo
+		reminders = append(reminders, reminder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// MarkReminded records that the reminder of the memo has been sent
+func (r *ReminderRepository) MarkReminded(ctx context.Context, id int) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE memos SET reminded = true WHERE id = $1`, id); err != nil {
+		r.logger.WithError(err).WithField("memo_id", id).Error("リマインダーの通知済みの記録に失敗")
+		return fmt.Errorf("failed to mark memo as reminded: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"memo-app/src/domain"
+	"memo-app/src/notifier"
+
+	"github.com/sirupsen/logrus"
+)
+
+// reminderBatchSize は1回のポーリングで通知するリマインダーの上限
+const reminderBatchSize = 100
+
+// StartReminderScheduler polls for memos whose reminder time has passed every interval, sends each
+// reminder through n and marks it as reminded. A reminder that fails to send is retried on the next poll.
+// The returned function stops the background goroutine.
+func StartReminderScheduler(repo domain.ReminderRepository, n notifier.Notifier, interval time.Duration, logger *logrus.Logger) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				SendDueReminders(context.Background(), repo, n, time.Now(), logger)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	logger.WithField("interval", interval).Info("リマインダーの定期通知を開始しました")
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// SendDueReminders sends the reminders that are due at now and returns how many were sent
+func SendDueReminders(ctx context.Context, repo domain.ReminderRepository, n notifier.Notifier, now time.Time, logger *logrus.Logger) int {
+	reminders, err := repo.ListDueReminders(ctx, now, reminderBatchSize)
+	if err != nil {
+		logger.WithError(err).Error("リマインダーの取得に失敗")
+		return 0
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		fields := logrus.Fields{"memo_id": reminder.Memo.ID, "user_id": reminder.UserID}
+		if err := n.Notify(ctx, reminder); err != nil {
+			logger.WithError(err).WithFields(fields).Error("リマインダーの通知に失敗")
+			continue
+		}
+		if err := repo.MarkReminded(ctx, reminder.Memo.ID); err != nil {
+			logger.WithError(err).WithFields(fields).Error("リマインダーの通知済みの記録に失敗")
+			continue
+		}
+		sent++
+	}
+	return sent
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"memo-app/src/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StartTrashPurge periodically purges memos that have been in the trash longer than retention.
+// The returned function stops the background goroutine.
+func StartTrashPurge(repo domain.MemoRepository, interval, retention time.Duration, logger *logrus.Logger) func() {
+	return StartTrashPurgeWithRecycleLog(repo, interval, retention, 0, logger)
+}
+
+// StartTrashPurgeWithRecycleLog works like StartTrashPurge and, when deletedRetention is positive, also
+// removes recycle log entries of permanently deleted memos older than deletedRetention on each tick.
+func StartTrashPurgeWithRecycleLog(repo domain.MemoRepository, interval, retention, deletedRetention time.Duration, logger *logrus.Logger) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := repo.PurgeTrashedOlderThan(context.Background(), retention); err != nil {
+					logger.WithError(err).Error("ゴミ箱の定期削除に失敗")
+				}
+				if deletedRetention > 0 {
+					if _, err := repo.PurgeDeletedOlderThan(context.Background(), deletedRetention); err != nil {
+						logger.WithError(err).Error("削除済みメモの定期削除に失敗")
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	logger.WithFields(logrus.Fields{
+		"interval":          interval,
+		"retention":         retention,
+		"deleted_retention": deletedRetention,
+	}).Info("ゴミ箱の定期削除を開始しました")
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// webhookColumns is the column list scanned by scanWebhook
+const webhookColumns = `id, user_id, url, secret, events, created_at, updated_at`
+
+// WebhookRepository implements domain.WebhookRepository
+type WebhookRepository struct {
+	db     *database.DB
+	logger *logrus.Logger
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *database.DB, logger *logrus.Logger) domain.WebhookRepository {
+	return &WebhookRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create registers a webhook owned by the authenticated user
+func (r *WebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	eventsJSON, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	userID, _ := domain.UserIDFromContext(ctx)
+	now := time.Now()
+	created := *webhook
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO webhooks (user_id, url, secret, events, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		userID, created.URL, created.Secret, string(eventsJSON), now, now,
+	).Scan(&created.ID)
+	if err != nil {
+		r.logger.WithError(err).WithField("user_id", userID).Error("Webhookの作成に失敗")
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{"webhook_id": created.ID, "user_id": userID}).Info("Webhookを作成しました")
+	return &created, nil
+}
+
+// GetByID retrieves a webhook of the authenticated user
+func (r *WebhookRepository) GetByID(ctx context.Context, id int) (*domain.Webhook, error) {
+	query, args := userScope(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, []interface{}{id})
+
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		r.logger.WithError(err).WithField("webhook_id", id).Error("Webhookの取得に失敗")
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// List lists the webhooks of the authenticated user, oldest first
+func (r *WebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	query, args := userScope(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE true`, nil)
+	return r.query(ctx, query+` ORDER BY id`, args...)
+}
+
+// Update replaces the URL, secret and events of a webhook of the authenticated user
+func (r *WebhookRepository) Update(ctx context.Context, id int, webhook *domain.Webhook) (*domain.Webhook, error) {
+	eventsJSON, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	query, args := userScope(ctx, `UPDATE webhooks SET url = $2, secret = $3, events = $4, updated_at = $5 WHERE id = $1`,
+		[]interface{}{id, webhook.URL, webhook.Secret, string(eventsJSON), time.Now()})
+
+	updated, err := scanWebhook(r.db.QueryRowContext(ctx, query+` RETURNING `+webhookColumns, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		r.logger.WithError(err).WithField("webhook_id", id).Error("Webhookの更新に失敗")
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	r.logger.WithField("webhook_id", id).Info("Webhookを更新しました")
+	return updated, nil
+}
+
+// Delete removes a webhook of the authenticated user
+func (r *WebhookRepository) Delete(ctx context.Context, id int) error {
+	query, args := userScope(ctx, `DELETE FROM webhooks WHERE id = $1`, []interface{}{id})
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithError(err).WithField("webhook_id", id).Error("Webhookの削除に失敗")
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithError(err).WithField("webhook_id", id).Error("削除件数の取得に失敗")
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found")
+	}
+
+	r.logger.WithField("webhook_id", id).Info("Webhookを削除しました")
+	return nil
+}
+
+// ListByEvent lists the webhooks of the user subscribed to event
+func (r *WebhookRepository) ListByEvent(ctx context.Context, userID int, event string) ([]domain.Webhook, error) {
+	eventJSON, err := json.Marshal([]string{event})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return r.query(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE user_id = $1 AND events @> $2::jsonb ORDER BY id`,
+		userID, string(eventJSON))
+}
+
+// query runs a webhook SELECT and scans every row
+func (r *WebhookRepository) query(ctx context.Context, query string, args ...interface{}) ([]domain.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Webhook一覧の取得に失敗")
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []domain.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// scanWebhook scans a row selected with webhookColumns
+func scanWebhook(row rowScanner) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	var eventsJSON string
+	if err := row.Scan(&webhook.ID, &webhook.UserID, &webhook.URL, &webhook.Secret, &eventsJSON,
+		&webhook.CreatedAt, &webhook.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(eventsJSON), &webhook.Events); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal events: %w", err)
+	}
+	return &webhook, nil
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"memo-app/src/domain"
+	"memo-app/src/problem"
+	"memo-app/src/usecase"
+	"memo-app/src/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AttachmentRequestOverhead is the room left for multipart headers on top of the maximum attachment size
+// when limiting the request body of the upload route
+const AttachmentRequestOverhead = 1 << 20
+
+// AttachmentHandler handles HTTP requests for files attached to the caller's memos
+type AttachmentHandler struct {
+	attachmentUsecase usecase.AttachmentUsecase
+	logger            *logrus.Logger
+	validator         *validator.CustomValidator
+	maxSize           int64
+}
+
+// NewAttachmentHandler creates a new attachment handler; maxSize is only used in error messages
+func NewAttachmentHandler(attachmentUsecase usecase.AttachmentUsecase, logger *logrus.Logger, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentUsecase: attachmentUsecase,
+		logger:            logger,
+		validator:         validator.NewCustomValidator(),
+		maxSize:           maxSize,
+	}
+}
+
+// UploadAttachment attaches an uploaded file to a memo of the caller
+// @Summary Attach a file to a memo
+// @Description The file is sent in the multipart field "file". Its content type is detected from the content and must be one of ATTACHMENT_ALLOWED_TYPES
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Memo ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} MemoAttachmentResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 413 {object} ErrorResponseDTO
+// @Failure 415 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos/{id}/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	memoID, ok := h.memoID(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.logger.WithError(err).Error("添付ファイルの読み込みに失敗")
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			problem.JSON(c, http.StatusRequestEntityTooLarge, ErrorResponseDTO{
+				Error:   "Failed to upload attachment",
+				Message: fmt.Sprintf("attachment must not exceed %d bytes", h.maxSize),
+			})
+			return
+		}
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Failed to upload attachment",
+			Message: `multipart field "file" is required`,
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.logger.WithError(err).Error("添付ファイルを開けません")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{Error: "Failed to upload attachment"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentUsecase.UploadAttachment(h.requestContext(c), memoID, usecase.UploadAttachmentRequest{
+		Filename: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		File:     file,
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", memoID).Error("添付ファイルのアップロードに失敗")
+		h.respondError(c, err, "Failed to upload attachment")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"memo_id":       memoID,
+		"attachment_id": attachment.ID,
+		"content_type":  attachment.ContentType,
+		"size":          attachment.Size,
+	}).Info("添付ファイルをアップロードしました")
+	c.JSON(http.StatusCreated, toMemoAttachmentResponseDTO(attachment))
+}
+
+// ListAttachments lists the files attached to a memo of the caller
+// @Summary List the attachments of a memo
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Memo ID"
+// @Success 200 {object} AttachmentListResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos/{id}/attachments [get]
+func (h *AttachmentHandler) ListAttachments(c *gin.Context) {
+	memoID, ok := h.memoID(c)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.attachmentUsecase.ListAttachments(h.requestContext(c), memoID)
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", memoID).Error("添付ファイル一覧の取得に失敗")
+		h.respondError(c, err, "Failed to list attachments")
+		return
+	}
+	c.JSON(http.StatusOK, AttachmentListResponseDTO{Attachments: toMemoAttachmentResponseDTOs(attachments)})
+}
+
+// DownloadAttachment returns a time-limited signed URL for downloading an attachment of a memo of the caller
+// @Summary Get a signed download URL for an attachment
+// @Description The URL can be used without credentials until expires_at (ATTACHMENT_URL_EXPIRY) and downloads the file under its original name
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Memo ID"
+// @Param attachmentID path int true "Attachment ID"
+// @Success 200 {object} AttachmentDownloadResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos/{id}/attachments/{attachmentID}/download [get]
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	memoID, ok := h.memoID(c)
+	if !ok {
+		return
+	}
+	rawID := c.Param("attachmentID")
+	attachmentID, err := h.validator.ValidateID(rawID)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", rawID).Error("無効な添付ファイルID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid attachment ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	attachment, download, err := h.attachmentUsecase.GetAttachmentDownload(h.requestContext(c), memoID, attachmentID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{"memo_id": memoID, "attachment_id": attachmentID}).Error("添付ファイルのダウンロードURLの発行に失敗")
+		h.respondError(c, err, "Failed to get attachment download URL")
+		return
+	}
+
+	// 署名付きURLは有効期限内なら誰でも使えるため、共有キャッシュに残さない
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, AttachmentDownloadResponseDTO{
+		MemoAttachmentResponseDTO: toMemoAttachmentResponseDTO(attachment),
+		URL:                       download.URL,
+		ExpiresAt:                 download.ExpiresAt,
+	})
+}
+
+// memoID parses the :id path parameter, responding 400 when it is invalid
+func (h *AttachmentHandler) memoID(c *gin.Context) (int, bool) {
+	idStr := c.Param("id")
+	id, err := h.validator.ValidateID(idStr)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid memo ID",
+			Message: err.Error(),
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError maps an attachment usecase error to its HTTP status
+func (h *AttachmentHandler) respondError(c *gin.Context, err error, message string) {
+	switch err {
+	case usecase.ErrAttachmentUnauthorized:
+		problem.JSON(c, http.StatusUnauthorized, ErrorResponseDTO{Error: message, Message: err.Error()})
+	case usecase.ErrMemoNotFound, usecase.ErrAttachmentNotFound:
+		problem.JSON(c, http.StatusNotFound, ErrorResponseDTO{Error: message})
+	case usecase.ErrInvalidAttachment:
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{Error: message, Message: err.Error()})
+	case usecase.ErrAttachmentTooLarge:
+		problem.JSON(c, http.StatusRequestEntityTooLarge, ErrorResponseDTO{
+			Error:   message,
+			Message: fmt.Sprintf("attachment must not exceed %d bytes", h.maxSize),
+		})
+	case usecase.ErrAttachmentTypeNotAllowed:
+		problem.JSON(c, http.StatusUnsupportedMediaType, ErrorResponseDTO{Error: message, Message: err.Error()})
+	default:
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{Error: message})
+	}
+}
+
+// requestContext returns the request context carrying the authenticated user ID, if any
+func (h *AttachmentHandler) requestContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(int); ok {
+			ctx = domain.WithUserID(ctx, id)
+		}
+	}
+	if requestID := requestIDFrom(c); requestID != "" {
+		ctx = domain.WithRequestID(ctx, requestID)
+	}
+	return ctx
+}
+
+func toMemoAttachmentResponseDTO(attachment *domain.MemoAttachment) MemoAttachmentResponseDTO {
+	return MemoAttachmentResponseDTO{
+		ID:          attachment.ID,
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+func toMemoAttachmentResponseDTOs(attachments []domain.MemoAttachment) []MemoAttachmentResponseDTO {
+	result := make([]MemoAttachmentResponseDTO, len(attachments))
+	for i := range attachments {
+		result[i] = toMemoAttachmentResponseDTO(&attachments[i])
+	}
+	return result
+}
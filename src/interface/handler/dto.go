@@ -1,64 +1,373 @@
 package handler
 
 import (
+	"encoding/json"
 	"time"
+
+	"memo-app/src/problem"
 )
 
 // CreateMemoRequestDTO represents HTTP request for creating a memo
 type CreateMemoRequestDTO struct {
-	Title    string   `json:"title" binding:"required,max=200,min=1" validate:"required,max=200,min=1,safe_text,no_sql_injection"`
-	Content  string   `json:"content" binding:"required" validate:"required,min=1,safe_text,no_sql_injection"`
-	Category string   `json:"category" binding:"max=50" validate:"omitempty,max=50,safe_category"`
-	Tags     []string `json:"tags" validate:"omitempty,dive,max=30,safe_tag"`
-	Priority string   `json:"priority" binding:"omitempty,oneof=low medium high" validate:"omitempty,oneof=low medium high"`
+	Title    string     `json:"title" binding:"required,max=200,min=1" validate:"required,max=200,min=1,safe_text,no_sql_injection"`
+	Content  string     `json:"content" binding:"required" validate:"required,min=1,safe_text,no_sql_injection"`
+	Category string     `json:"category" binding:"max=50" validate:"omitempty,max=50,safe_category"`
+	Tags     []string   `json:"tags" validate:"omitempty,dive,max=30,safe_tag"`
+	Priority string     `json:"priority" binding:"omitempty,oneof=low medium high" validate:"omitempty,oneof=low medium high"`
+	DueDate  *time.Time `json:"due_date"`
+	RemindAt *time.Time `json:"remind_at"`
+}
+
+// ImportMemoRequestDTO represents a memo with a client-supplied ID in an admin import
+type ImportMemoRequestDTO struct {
+	ID int `json:"id" binding:"required,min=1"`
+	CreateMemoRequestDTO
+}
+
+// ImportMemosRequestDTO represents HTTP request for importing memos with their original IDs
+type ImportMemosRequestDTO struct {
+	Memos []ImportMemoRequestDTO `json:"memos" binding:"required,min=1,max=100,dive" validate:"required,dive"`
+}
+
+// ImportMemosResponseDTO represents HTTP response for an admin import
+type ImportMemosResponseDTO struct {
+	Memos []MemoResponseDTO `json:"memos"`
+}
+
+// ImportFailureDTO reports why the memo at Index of an import payload was not created
+type ImportFailureDTO struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// ImportSummaryDTO represents HTTP response for importing memos from an export
+type ImportSummaryDTO struct {
+	Imported int                `json:"imported"`
+	Failed   []ImportFailureDTO `json:"failed"`
 }
 
 // UpdateMemoRequestDTO represents HTTP request for updating a memo
 type UpdateMemoRequestDTO struct {
-	Title    *string  `json:"title,omitempty" binding:"omitempty,max=200" validate:"omitempty,max=200,min=1,safe_text,no_sql_injection"`
-	Content  *string  `json:"content,omitempty" validate:"omitempty,min=1,safe_text,no_sql_injection"`
-	Category *string  `json:"category,omitempty" binding:"omitempty,max=50" validate:"omitempty,max=50,safe_category"`
-	Tags     []string `json:"tags,omitempty" validate:"omitempty,dive,max=30,safe_tag"`
-	Priority *string  `json:"priority,omitempty" binding:"omitempty,oneof=low medium high" validate:"omitempty,oneof=low medium high"`
-	Status   *string  `json:"status,omitempty" binding:"omitempty,oneof=active archived" validate:"omitempty,oneof=active archived"`
+	Title    *string    `json:"title,omitempty" binding:"omitempty,max=200" validate:"omitempty,max=200,min=1,safe_text,no_sql_injection"`
+	Content  *string    `json:"content,omitempty" validate:"omitempty,min=1,safe_text,no_sql_injection"`
+	Category *string    `json:"category,omitempty" binding:"omitempty,max=50" validate:"omitempty,max=50,safe_category"`
+	Tags     []string   `json:"tags,omitempty" validate:"omitempty,dive,max=30,safe_tag"`
+	Priority *string    `json:"priority,omitempty" binding:"omitempty,oneof=low medium high" validate:"omitempty,oneof=low medium high"`
+	Status   *string    `json:"status,omitempty" binding:"omitempty,oneof=active archived" validate:"omitempty,oneof=active archived"`
+	DueDate  *time.Time `json:"due_date,omitempty"`
+	RemindAt *time.Time `json:"remind_at,omitempty"`
+	Version  *int       `json:"version,omitempty" binding:"omitempty,min=1"` // If-Match ヘッダーでも指定可能
+}
+
+// UpdateMemoMetadataRequestDTO represents HTTP request for updating only the metadata of a memo.
+// Title and content are not accepted; an empty color clears it and a null due_date clears the due date.
+type UpdateMemoMetadataRequestDTO struct {
+	Category *string      `json:"category,omitempty" binding:"omitempty,max=50" validate:"omitempty,max=50,safe_category"`
+	Tags     []string     `json:"tags,omitempty" validate:"omitempty,dive,max=30,safe_tag"`
+	Priority *string      `json:"priority,omitempty" binding:"omitempty,oneof=low medium high" validate:"omitempty,oneof=low medium high"`
+	Color    *string      `json:"color,omitempty" validate:"omitempty,max=7"`
+	DueDate  NullableTime `json:"due_date,omitempty" swaggertype:"string" format:"date-time"`
+}
+
+// NullableTime is a JSON time that tells an explicit null (Set with a nil Value) apart from an omitted field
+type NullableTime struct {
+	Set   bool
+	Value *time.Time
 }
 
-// MemoResponseDTO represents HTTP response for a memo
+// UnmarshalJSON is only called when the field is present, including when it is null
+func (t *NullableTime) UnmarshalJSON(data []byte) error {
+	t.Set = true
+	if string(data) == "null" {
+		t.Value = nil
+		return nil
+	}
+	var value time.Time
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	t.Value = &value
+	return nil
+}
+
+// MemoResponseDTO represents HTTP response for a memo.
+// Priority and Status are strings by default and integers with ?enums=numeric (see domain Priority.Code and Status.Code).
 type MemoResponseDTO struct {
-	ID          int        `json:"id"`
-	Title       string     `json:"title"`
-	Content     string     `json:"content"`
-	Category    string     `json:"category"`
-	Tags        []string   `json:"tags"`
-	Priority    string     `json:"priority"`
-	Status      string     `json:"status"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ID          int         `json:"id"`
+	Title       string      `json:"title"`
+	Content     string      `json:"content"`
+	ContentHTML string      `json:"content_html,omitempty"` // Markdownから変換したサニタイズ済みのHTML（?render=html の場合のみ）
+	Category    string      `json:"category"`
+	Tags        []string    `json:"tags"`
+	Priority    interface{} `json:"priority"`
+	Status      interface{} `json:"status"`
+	Pinned      bool        `json:"pinned"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+	TrashedAt   *time.Time  `json:"trashed_at,omitempty"`
+	DueDate     *time.Time  `json:"due_date,omitempty"`
+	RemindAt    *time.Time  `json:"remind_at,omitempty"`
+	Reminded    bool        `json:"reminded"`
+	Color       string      `json:"color,omitempty"`
+	Version     int         `json:"version"`
+	WordCount   int         `json:"word_count"`     // 本文の単語数（日本語・中国語は1文字を1語として数える）
+	CharCount   int         `json:"char_count"`     // 本文の文字数
+	Rank        *float64    `json:"rank,omitempty"` // 全文検索の関連度（全文検索の結果のみ）
+	Warnings    []string    `json:"warnings,omitempty"`
+
+	DeletionPreview *DeletionPreviewDTO `json:"deletion_preview,omitempty"` // ?include=deletion_preview の場合のみ
+}
+
+// DeletionPreviewDTO describes what the next step of the staged deletion does to a memo,
+// so that clients can show the matching warning before deleting
+type DeletionPreviewDTO struct {
+	Status           interface{} `json:"status"`
+	NextDeleteAction string      `json:"next_delete_action"`         // archive、trash または permanent_delete
+	Endpoint         string      `json:"endpoint"`                   // 次の削除操作のエンドポイント
+	Reversible       bool        `json:"reversible"`                 // 次の削除操作の後に復元できるか
+	PurgeAt          *time.Time  `json:"purge_at,omitempty"`         // ゴミ箱のメモが自動的に完全削除される日時
+	RestorableUntil  *time.Time  `json:"restorable_until,omitempty"` // 今完全削除した場合にリサイクルログから復元できる期限
+}
+
+// MemoRevisionResponseDTO represents HTTP response for a memo revision
+type MemoRevisionResponseDTO struct {
+	ID        int         `json:"id"`
+	Title     string      `json:"title"`
+	Content   string      `json:"content"`
+	Category  string      `json:"category"`
+	Tags      []string    `json:"tags"`
+	Priority  interface{} `json:"priority"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// MemoHistoryResponseDTO represents HTTP response for the history of a memo
+type MemoHistoryResponseDTO struct {
+	Revisions []MemoRevisionResponseDTO `json:"revisions"`
+}
+
+// MemoAttachmentResponseDTO represents HTTP response for memo attachment metadata
+type MemoAttachmentResponseDTO struct {
+	ID          int       `json:"id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AttachmentListResponseDTO represents HTTP response for the attachments of a memo
+type AttachmentListResponseDTO struct {
+	Attachments []MemoAttachmentResponseDTO `json:"attachments"`
+}
+
+// AttachmentDownloadResponseDTO represents HTTP response with a signed URL for downloading an attachment
+type AttachmentDownloadResponseDTO struct {
+	MemoAttachmentResponseDTO
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MemoDetailResponseDTO represents HTTP response for a memo with expanded collections.
+// Collections are pointers so that requested-but-empty ones are rendered as [].
+type MemoDetailResponseDTO struct {
+	MemoResponseDTO
+	Revisions   *[]MemoRevisionResponseDTO   `json:"revisions,omitempty"`
+	Attachments *[]MemoAttachmentResponseDTO `json:"attachments,omitempty"`
 }
 
 // MemoListResponseDTO represents HTTP response for memo list
 type MemoListResponseDTO struct {
 	Memos      []MemoResponseDTO `json:"memos"`
+	Returned   int               `json:"returned"`
 	Total      int               `json:"total"`
 	Page       int               `json:"page"`
 	Limit      int               `json:"limit"`
 	TotalPages int               `json:"total_pages"`
+	NextCursor string            `json:"next_cursor"`
 }
 
 // MemoFilterDTO represents HTTP query parameters for filtering memos
 type MemoFilterDTO struct {
-	Category string `form:"category" validate:"omitempty,max=50,safe_category"`
-	Status   string `form:"status" binding:"omitempty,oneof=active archived" validate:"omitempty,oneof=active archived"`
-	Priority string `form:"priority" binding:"omitempty,oneof=low medium high" validate:"omitempty,oneof=low medium high"`
-	Search   string `form:"search" validate:"omitempty,max=200,safe_text,no_sql_injection"`
-	Tags     string `form:"tags" validate:"omitempty,max=200"`
-	Page     int    `form:"page,default=1" binding:"min=1" validate:"min=1,max=1000"`
-	Limit    int    `form:"limit,default=10" binding:"min=1,max=100" validate:"min=1,max=100"`
+	Category  string `form:"category" validate:"omitempty,max=50,safe_category"`
+	Status    string `form:"status" binding:"omitempty,oneof=active archived trashed" validate:"omitempty,oneof=active archived trashed"`
+	Priority  string `form:"priority" binding:"omitempty,oneof=low medium high" validate:"omitempty,oneof=low medium high"`
+	Search    string `form:"search" validate:"omitempty,max=200,safe_text,no_sql_injection"`
+	Tags      string `form:"tags" validate:"omitempty,max=200"`
+	DueBefore string `form:"due_before" validate:"omitempty,max=50"`
+	DueAfter  string `form:"due_after" validate:"omitempty,max=50"`
+	Overdue   string `form:"overdue" binding:"omitempty,oneof=true false" validate:"omitempty,oneof=true false"`
+	Page      int    `form:"page,default=1" binding:"min=1" validate:"min=1,max=1000"`
+	Limit     int    `form:"limit"` // 省略時・0以下はDEFAULT_PAGE_SIZE、上限はMAX_PAGE_SIZE
+	Cursor    string `form:"cursor" validate:"omitempty,max=200"`
+	Sort      string `form:"sort" validate:"omitempty,max=100"`
+}
+
+// PageDTO represents pagination query parameters
+type PageDTO struct {
+	Page  int `form:"page,default=1" binding:"min=1,max=1000"`
+	Limit int `form:"limit"` // 省略時・0以下はDEFAULT_PAGE_SIZE、上限はMAX_PAGE_SIZE
+}
+
+// TagFilterDTO represents query parameters for the tags endpoint
+type TagFilterDTO struct {
+	Limit    *int `form:"limit" binding:"omitempty,min=1"`
+	MinCount *int `form:"min_count" binding:"omitempty,min=1"`
+}
+
+// TagObjectDTO represents a memo tag serialized as an object (?tags=objects)
+type TagObjectDTO struct {
+	Name string `json:"name"`
+}
+
+// TagCountResponseDTO represents a tag with its usage count
+type TagCountResponseDTO struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TagListResponseDTO represents HTTP response for the tags endpoint
+type TagListResponseDTO struct {
+	Tags []TagCountResponseDTO `json:"tags"`
+}
+
+// BulkDeleteRequestDTO represents HTTP request for deleting several memos at once
+type BulkDeleteRequestDTO struct {
+	IDs []int `json:"ids" binding:"required,min=1,max=100,dive,min=1"`
+}
+
+// BulkUpdateRequestDTO represents HTTP request for applying the same status/category change to several memos
+type BulkUpdateRequestDTO struct {
+	IDs      []int   `json:"ids" binding:"required,min=1,max=100,dive,min=1"`
+	Category *string `json:"category,omitempty" binding:"omitempty,max=50" validate:"omitempty,max=50,safe_category"`
+	Status   *string `json:"status,omitempty" binding:"omitempty,oneof=active archived" validate:"omitempty,oneof=active archived"`
+}
+
+// BulkUpdateResponseDTO represents HTTP response for a bulk update
+type BulkUpdateResponseDTO struct {
+	Updated    int   `json:"updated"`
+	SkippedIDs []int `json:"skipped_ids"`
+}
+
+// BulkResultDTO represents the outcome of a bulk operation for a single memo
+type BulkResultDTO struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+// BulkResponseDTO represents HTTP response for bulk operations
+type BulkResponseDTO struct {
+	Results []BulkResultDTO `json:"results"`
+}
+
+// SharedMemoResponseDTO represents a memo shared with the caller together with its owner and granted permission
+type SharedMemoResponseDTO struct {
+	MemoResponseDTO
+	OwnerUsername string    `json:"owner_username"`
+	Permission    string    `json:"permission"`
+	SharedAt      time.Time `json:"shared_at"`
+}
+
+// SharedMemoListResponseDTO represents HTTP response for memos shared with the caller
+type SharedMemoListResponseDTO struct {
+	Memos      []SharedMemoResponseDTO `json:"memos"`
+	Total      int                     `json:"total"`
+	Page       int                     `json:"page"`
+	Limit      int                     `json:"limit"`
+	TotalPages int                     `json:"total_pages"`
+}
+
+// DeletedMemoResponseDTO represents a permanently deleted memo kept in the recycle log.
+// DeletedID identifies the entry for the restore endpoint; the embedded id is the ID the memo had before deletion
+type DeletedMemoResponseDTO struct {
+	MemoResponseDTO
+	DeletedID int       `json:"deleted_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// DeletedMemoListResponseDTO represents HTTP response for the recycle log of permanently deleted memos
+type DeletedMemoListResponseDTO struct {
+	Memos      []DeletedMemoResponseDTO `json:"memos"`
+	Total      int                      `json:"total"`
+	Page       int                      `json:"page"`
+	Limit      int                      `json:"limit"`
+	TotalPages int                      `json:"total_pages"`
+}
+
+// CategoryListResponseDTO represents HTTP response for the categories endpoint
+type CategoryListResponseDTO struct {
+	Categories []string `json:"categories"`
+}
+
+// MemoStatsResponseDTO represents HTTP response for the memo stats endpoint
+type MemoStatsResponseDTO struct {
+	Total             int            `json:"total"`
+	ByStatus          map[string]int `json:"by_status"`
+	ByPriority        map[string]int `json:"by_priority"`
+	ByCategory        map[string]int `json:"by_category"`
+	CreatedLast7Days  int            `json:"created_last_7_days"`
+	CreatedLast30Days int            `json:"created_last_30_days"`
+}
+
+// OnThisDayResponseDTO represents HTTP response for memos created on this day in previous years
+type OnThisDayResponseDTO struct {
+	Memos []MemoResponseDTO `json:"memos"`
+}
+
+// RecentQueriesResponseDTO represents HTTP response for recent search queries
+type RecentQueriesResponseDTO struct {
+	Queries []string `json:"queries"`
 }
 
 // ErrorResponseDTO represents HTTP error response
 type ErrorResponseDTO struct {
 	Error   string `json:"error"`
+	Code    string `json:"code,omitempty"`
 	Message string `json:"message,omitempty"`
 }
+
+// Problem converts the error to RFC 7807 problem details: Error becomes the title and Message the detail
+func (e ErrorResponseDTO) Problem(status int) problem.Details {
+	d := problem.New(status, e.Error)
+	d.Detail = e.Message
+	d.Code = e.Code
+	return d
+}
+
+// Error codes returned in ErrorResponseDTO.Code
+const (
+	ErrorCodeMalformedJSON    = "MALFORMED_JSON"
+	ErrorCodeValidationFailed = "VALIDATION_FAILED"
+	ErrorCodeVersionConflict  = "VERSION_CONFLICT"
+	ErrorCodeRequestTooLarge  = "REQUEST_TOO_LARGE"
+)
+
+// CreateWebhookRequestDTO represents HTTP request for registering a webhook.
+// Events defaults to every memo event and a secret is generated when omitted
+type CreateWebhookRequestDTO struct {
+	URL    string   `json:"url" binding:"required,max=2048"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+// UpdateWebhookRequestDTO represents HTTP request for updating a webhook; omitted fields are unchanged
+type UpdateWebhookRequestDTO struct {
+	URL    *string  `json:"url,omitempty" binding:"omitempty,max=2048"`
+	Secret *string  `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+// WebhookResponseDTO represents a webhook in HTTP responses.
+// The secret is only returned when the webhook is created
+type WebhookResponseDTO struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookListResponseDTO represents HTTP response for the caller's webhooks
+type WebhookListResponseDTO struct {
+	Webhooks []WebhookResponseDTO `json:"webhooks"`
+}
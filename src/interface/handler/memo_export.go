@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"memo-app/src/domain"
+	"memo-app/src/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Export formats selectable with ?format= on the export endpoint
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+)
+
+// exportQueryKeys is the set of query keys accepted by the export endpoint
+var exportQueryKeys = withKeys(nil, "format", "enums")
+
+// exportCSVHeader is the header row of CSV exports; tags are joined with ";"
+var exportCSVHeader = []string{
+	"id", "title", "content", "category", "tags", "priority", "status", "pinned",
+	"created_at", "updated_at", "completed_at", "trashed_at", "due_date",
+}
+
+// memoExportWriter writes exported memos in one format
+type memoExportWriter interface {
+	begin() error
+	write(memo MemoResponseDTO) error
+	end() error
+}
+
+// ExportMemos streams every memo of the caller as a JSON array or CSV file download.
+// Memos are written as they are read from the repository instead of being loaded up front.
+func (h *MemoHandler) ExportMemos(c *gin.Context) {
+	if !h.checkQueryParams(c, exportQueryKeys) {
+		return
+	}
+
+	format := c.DefaultQuery("format", ExportFormatJSON)
+	var writer memoExportWriter
+	switch format {
+	case ExportFormatJSON:
+		writer = &jsonExportWriter{w: c.Writer}
+	case ExportFormatCSV:
+		writer = &csvExportWriter{w: csv.NewWriter(c.Writer)}
+	default:
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid format parameter",
+			Message: fmt.Sprintf("format must be %s or %s", ExportFormatJSON, ExportFormatCSV),
+		})
+		return
+	}
+
+	// 最初のメモを書き込む時点でヘッダーを送信し、それまでのエラーは通常のエラーレスポンスで返す
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		contentType := "application/json; charset=utf-8"
+		if format == ExportFormatCSV {
+			contentType = "text/csv; charset=utf-8"
+		}
+		filename := fmt.Sprintf("memos-%s.%s", time.Now().Format("20060102"), format)
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.Status(http.StatusOK)
+		return writer.begin()
+	}
+
+	ctx := h.requestContext(c)
+	count := 0
+	err := h.memoUsecase.ExportMemos(ctx, func(memo *domain.Memo) error {
+		if err := start(); err != nil {
+			return err
+		}
+		count++
+		return writer.write(h.toMemoResponseDTO(ctx, memo))
+	})
+	if err == nil {
+		if err = start(); err == nil {
+			err = writer.end()
+		}
+	}
+	if err != nil {
+		h.logger.WithError(err).WithField("exported", count).Error("メモのエクスポートに失敗")
+		if !started {
+			problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+				Error: "Failed to export memos",
+			})
+			return
+		}
+		// 送信済みのレスポンスは変更できないため途中で打ち切る
+		c.Abort()
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{"format": format, "count": count}).Info("メモをエクスポートしました")
+}
+
+// jsonExportWriter writes memos as a JSON array of MemoResponseDTO
+type jsonExportWriter struct {
+	w       io.Writer
+	written int
+}
+
+func (e *jsonExportWriter) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonExportWriter) write(memo MemoResponseDTO) error {
+	data, err := json.Marshal(memo)
+	if err != nil {
+		return err
+	}
+	if e.written > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+	e.written++
+	return nil
+}
+
+func (e *jsonExportWriter) end() error {
+	_, err := io.WriteString(e.w, "]")
+	return err
+}
+
+// csvExportWriter writes memos as CSV rows below exportCSVHeader
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (e *csvExportWriter) begin() error {
+	return e.w.Write(exportCSVHeader)
+}
+
+func (e *csvExportWriter) write(memo MemoResponseDTO) error {
+	if err := e.w.Write([]string{
+		strconv.Itoa(memo.ID),
+		memo.Title,
+		memo.Content,
+		memo.Category,
+		strings.Join(memo.Tags, ";"),
+		fmt.Sprint(memo.Priority),
+		fmt.Sprint(memo.Status),
+		strconv.FormatBool(memo.Pinned),
+		memo.CreatedAt.Format(time.RFC3339),
+		memo.UpdatedAt.Format(time.RFC3339),
+		formatOptionalTime(memo.CompletedAt),
+		formatOptionalTime(memo.TrashedAt),
+		formatOptionalTime(memo.DueDate),
+	}); err != nil {
+		return err
+	}
+	// csv.Writer はバッファするため、1行ずつレスポンスへ書き出す
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExportWriter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// formatOptionalTime formats t as RFC3339, or returns an empty string when t is nil
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+
+	"memo-app/src/domain"
+	"memo-app/src/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedEntryLimit is the number of recent memos included in the Atom feed
+const FeedEntryLimit = 20
+
+// AtomContentType is the Content-Type of the Atom feed
+const AtomContentType = "application/atom+xml"
+
+// atomNamespace is the XML namespace of Atom 1.0
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+// feedQueryKeys is the set of query keys accepted by the feed endpoint (api_key is consumed by the auth middleware)
+var feedQueryKeys = withKeys(nil, "api_key")
+
+// atomFeed is the root element of an Atom 1.0 feed
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Link       atomLink       `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    atomText       `xml:"summary"`
+}
+
+// GetMemoFeed renders the caller's most recently updated active memos as an Atom feed.
+// Feed readers cannot send Authorization headers, so the route authenticates with ?api_key=
+// @Summary Atom feed of recent memos
+// @Description The 20 most recently updated active memos of the API key's owner
+// @Tags memos
+// @Produce xml
+// @Param api_key query string true "API key"
+// @Success 200 {string} string "Atom feed"
+// @Failure 401 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos/feed.xml [get]
+func (h *MemoHandler) GetMemoFeed(c *gin.Context) {
+	if !h.checkQueryParams(c, feedQueryKeys) {
+		return
+	}
+
+	ctx := h.requestContext(c)
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		problem.JSON(c, http.StatusUnauthorized, ErrorResponseDTO{Error: "Authentication required"})
+		return
+	}
+
+	memos, _, err := h.memoUsecase.ListMemos(ctx, domain.MemoFilter{
+		Status: domain.StatusActive,
+		Page:   1,
+		Limit:  FeedEntryLimit,
+		Sort:   []domain.SortField{{Field: "updated_at", Desc: true}},
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("user_id", userID).Error("フィードのメモの取得に失敗")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{Error: "Failed to render feed"})
+		return
+	}
+
+	body, err := xml.MarshalIndent(newAtomFeed(baseURL(c), userID, memos), "", "  ")
+	if err != nil {
+		h.logger.WithError(err).WithField("user_id", userID).Error("フィードの作成に失敗")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{Error: "Failed to render feed"})
+		return
+	}
+
+	c.Data(http.StatusOK, AtomContentType, append([]byte(xml.Header), body...))
+}
+
+// newAtomFeed builds the feed; its updated time is that of the newest memo, or now when there are none
+func newAtomFeed(base string, userID int, memos []domain.Memo) atomFeed {
+	updated := time.Now()
+	if len(memos) > 0 {
+		updated = memos[0].UpdatedAt
+	}
+
+	feed := atomFeed{
+		Xmlns:   atomNamespace,
+		ID:      fmt.Sprintf("urn:memo-app:user:%d:memos", userID),
+		Title:   "Recent memos",
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: "memo-app"},
+		// self のリンクにはAPIキーを含めない
+		Links:   []atomLink{{Href: base + "/api/memos/feed.xml", Rel: "self", Type: AtomContentType}},
+		Entries: make([]atomEntry, len(memos)),
+	}
+	for i, memo := range memos {
+		entry := atomEntry{
+			ID:        fmt.Sprintf("urn:memo-app:memo:%d", memo.ID),
+			Title:     memo.Title,
+			Updated:   memo.UpdatedAt.UTC().Format(time.RFC3339),
+			Published: memo.CreatedAt.UTC().Format(time.RFC3339),
+			Link:      atomLink{Href: fmt.Sprintf("%s/api/memos/%d", base, memo.ID), Rel: "alternate"},
+			Summary:   atomText{Type: "text", Body: memo.Content},
+		}
+		if memo.Category != "" {
+			entry.Categories = append(entry.Categories, atomCategory{Term: memo.Category})
+		}
+		for _, tag := range memo.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		feed.Entries[i] = entry
+	}
+	return feed
+}
+
+// baseURL returns the scheme and host the request was made to, honoring X-Forwarded-Proto behind a proxy
+func baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -1,14 +1,32 @@
 package handler
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
 	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"memo-app/src/config"
 	"memo-app/src/domain"
+	"memo-app/src/markdown"
+	"memo-app/src/problem"
 	"memo-app/src/usecase"
 	"memo-app/src/validator"
 
 	"github.com/gin-gonic/gin"
+	playground "github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 )
 
@@ -17,37 +35,111 @@ type MemoHandler struct {
 	memoUsecase usecase.MemoUsecase
 	logger      *logrus.Logger
 	validator   *validator.CustomValidator
+	config      *config.MemoConfig
+	markdown    *markdown.Renderer
+
+	memosCreatedHook MemosCreatedHook
 }
 
-// NewMemoHandler creates a new memo handler
+// MemosCreatedHook is called with how memos were created and how many, e.g. to count them in metrics
+type MemosCreatedHook func(source string, count int)
+
+// Memo creation sources reported to MemosCreatedHook
+const (
+	MemoSourceAPI         = "api"
+	MemoSourceImport      = "import"
+	MemoSourceAdminImport = "admin_import"
+)
+
+// memoFilterQueryKeys is the set of query keys accepted by list and search endpoints
+var memoFilterQueryKeys = withKeys(queryKeysOf(MemoFilterDTO{}), "enums")
+
+// tagFilterQueryKeys is the set of query keys accepted by the tags endpoint
+var tagFilterQueryKeys = queryKeysOf(TagFilterDTO{})
+
+// pageQueryKeys is the set of query keys accepted by endpoints that only paginate
+var pageQueryKeys = queryKeysOf(PageDTO{})
+
+// sharedMemoQueryKeys is the set of query keys accepted by the shared-with-me endpoint
+var sharedMemoQueryKeys = withKeys(pageQueryKeys, "tags", "enums")
+
+// deletedMemoQueryKeys is the set of query keys accepted by the recycle log endpoint
+var deletedMemoQueryKeys = withKeys(pageQueryKeys, "tags", "enums")
+
+// onThisDayQueryKeys is the set of query keys accepted by the on-this-day endpoint
+var onThisDayQueryKeys = withKeys(nil, "tags", "enums")
+
+// Enum serialization formats selectable with ?enums= on memo responses
+const (
+	EnumFormatString  = "string"
+	EnumFormatNumeric = "numeric"
+)
+
+// Tag serialization formats selectable with ?tags= on memo responses
+const (
+	TagFormatStrings = "strings"
+	TagFormatObjects = "objects"
+)
+
+// NewMemoHandler creates a new memo handler with the default configuration
 func NewMemoHandler(memoUsecase usecase.MemoUsecase, logger *logrus.Logger) *MemoHandler {
+	return NewMemoHandlerWithConfig(memoUsecase, logger, config.DefaultMemoConfig())
+}
+
+// NewMemoHandlerWithConfig creates a new memo handler with the given configuration
+func NewMemoHandlerWithConfig(memoUsecase usecase.MemoUsecase, logger *logrus.Logger, cfg *config.MemoConfig) *MemoHandler {
+	if cfg == nil {
+		cfg = config.DefaultMemoConfig()
+	}
 	return &MemoHandler{
 		memoUsecase: memoUsecase,
 		logger:      logger,
 		validator:   validator.NewCustomValidator(),
+		config:      cfg,
+		markdown:    markdown.NewRenderer(),
+	}
+}
+
+// SetMemosCreatedHook registers a hook called after memos are created
+func (h *MemoHandler) SetMemosCreatedHook(hook MemosCreatedHook) {
+	h.memosCreatedHook = hook
+}
+
+// memosCreated reports created memos to the hook, if any
+func (h *MemoHandler) memosCreated(source string, count int) {
+	if h.memosCreatedHook != nil && count > 0 {
+		h.memosCreatedHook(source, count)
 	}
 }
 
 // CreateMemo creates a new memo
+// @Summary Create a new memo
+// @Tags memos
+// @Accept json
+// @Produce json
+// @Param memo body CreateMemoRequestDTO true "Memo data"
+// @Success 201 {object} MemoResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 422 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos [post]
 func (h *MemoHandler) CreateMemo(c *gin.Context) {
 	var req CreateMemoRequestDTO
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.WithError(err).Error("リクエストのバインドに失敗")
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
-			Error:   "Invalid request format",
-			Message: err.Error(),
-		})
+		problem.JSON(c, bindJSONErrorStatus(err), bindJSONErrorResponse(err))
 		return
 	}
 
 	// カスタムバリデーション実行
-	if err := h.validator.Validate(&req); err != nil {
+	if err := h.validateMemoRequest(&req, req.Tags); err != nil {
 		h.logger.WithError(err).Error("バリデーションエラー")
+		h.logValidationRejects(c, err)
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
-			c.JSON(http.StatusBadRequest, validationErrors)
+			problem.JSON(c, h.validationErrorStatus(), validationErrors)
 			return
 		}
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
+		problem.JSON(c, h.validationErrorStatus(), ErrorResponseDTO{
 			Error:   "Validation failed",
 			Message: err.Error(),
 		})
@@ -61,6 +153,8 @@ func (h *MemoHandler) CreateMemo(c *gin.Context) {
 		Category: h.validator.SanitizeInput(req.Category),
 		Tags:     h.validator.SanitizeTags(req.Tags),
 		Priority: req.Priority, // 列挙値なのでサニタイズ不要
+		DueDate:  req.DueDate,
+		RemindAt: req.RemindAt,
 	}
 
 	usecaseReq := usecase.CreateMemoRequest{
@@ -69,18 +163,22 @@ func (h *MemoHandler) CreateMemo(c *gin.Context) {
 		Category: sanitizedReq.Category,
 		Tags:     sanitizedReq.Tags,
 		Priority: sanitizedReq.Priority,
+		DueDate:  sanitizedReq.DueDate,
+		RemindAt: sanitizedReq.RemindAt,
 	}
 
-	memo, err := h.memoUsecase.CreateMemo(c.Request.Context(), usecaseReq)
+	ctx := h.requestContext(c)
+	memo, err := h.memoUsecase.CreateMemo(ctx, usecaseReq)
 	if err != nil {
 		h.logger.WithError(err).Error("メモの作成に失敗")
 
 		status := http.StatusInternalServerError
-		if err == usecase.ErrInvalidTitle || err == usecase.ErrInvalidContent || err == usecase.ErrInvalidPriority {
+		if err == usecase.ErrInvalidTitle || err == usecase.ErrInvalidContent ||
+			err == usecase.ErrContentTooLong || err == usecase.ErrInvalidPriority {
 			status = http.StatusBadRequest
 		}
 
-		c.JSON(status, ErrorResponseDTO{
+		problem.JSON(c, status, ErrorResponseDTO{
 			Error:   "Failed to create memo",
 			Message: err.Error(),
 		})
@@ -88,45 +186,328 @@ func (h *MemoHandler) CreateMemo(c *gin.Context) {
 	}
 
 	h.logger.WithField("memo_id", memo.ID).Info("メモを作成しました")
-	c.JSON(http.StatusCreated, h.toMemoResponseDTO(memo))
+	h.memosCreated(MemoSourceAPI, 1)
+	resp := h.toMemoResponseDTO(ctx, memo)
+	resp.Warnings = h.contentWarnings(memo.Content)
+	h.respondMemo(c, http.StatusCreated, resp)
+}
+
+// ImportMemosWithIDs creates memos keeping their client-supplied IDs (admin only).
+// The import is all or nothing; CreateMemo keeps ignoring client IDs.
+func (h *MemoHandler) ImportMemosWithIDs(c *gin.Context) {
+	var req ImportMemosRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("インポートリクエストのバインドに失敗")
+		problem.JSON(c, bindJSONErrorStatus(err), bindJSONErrorResponse(err))
+		return
+	}
+
+	// カスタムバリデーション実行
+	if err := h.validateMemoRequest(&req, importTags(req.Memos)...); err != nil {
+		h.logger.WithError(err).Error("バリデーションエラー")
+		h.logValidationRejects(c, err)
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			problem.JSON(c, h.validationErrorStatus(), validationErrors)
+			return
+		}
+		problem.JSON(c, h.validationErrorStatus(), ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// 入力値のサニタイゼーション
+	usecaseReqs := make([]usecase.ImportMemoRequest, len(req.Memos))
+	for i, memo := range req.Memos {
+		usecaseReqs[i] = usecase.ImportMemoRequest{
+			ID: memo.ID,
+			CreateMemoRequest: usecase.CreateMemoRequest{
+				Title:    h.validator.SanitizeInput(memo.Title),
+				Content:  h.validator.SanitizeInput(memo.Content),
+				Category: h.validator.SanitizeInput(memo.Category),
+				Tags:     h.validator.SanitizeTags(memo.Tags),
+				Priority: memo.Priority, // 列挙値なのでサニタイズ不要
+				DueDate:  memo.DueDate,
+				RemindAt: memo.RemindAt,
+			},
+		}
+	}
+
+	ctx := h.requestContext(c)
+	memos, err := h.memoUsecase.ImportMemosWithIDs(ctx, usecaseReqs)
+	if err != nil {
+		h.logger.WithError(err).Error("メモのインポートに失敗")
+
+		switch err {
+		case usecase.ErrInvalidImportIDs, usecase.ErrInvalidTitle, usecase.ErrInvalidContent,
+			usecase.ErrContentTooLong, usecase.ErrInvalidPriority:
+			problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+				Error:   "Invalid request",
+				Message: err.Error(),
+			})
+		case usecase.ErrMemoIDConflict:
+			problem.JSON(c, http.StatusConflict, ErrorResponseDTO{
+				Error:   "Failed to import memos",
+				Message: err.Error(),
+			})
+		default:
+			problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+				Error: "Failed to import memos",
+			})
+		}
+		return
+	}
+
+	h.logger.WithField("count", len(memos)).Info("ID指定でメモをインポートしました")
+	h.memosCreated(MemoSourceAdminImport, len(memos))
+	h.respondMemo(c, http.StatusCreated, ImportMemosResponseDTO{Memos: h.toMemoResponseDTOs(ctx, memos)})
 }
 
 // GetMemo retrieves a memo by ID
+// @Summary Get a memo by ID
+// @Tags memos
+// @Produce json
+// @Param id path int true "Memo ID"
+// @Param expand query string false "Comma separated collections to embed" Enums(revisions, attachments)
+// @Param include query string false "Extra fields to include" Enums(deletion_preview)
+// @Param render query string false "Also return the content rendered from Markdown to sanitized HTML as content_html" Enums(html)
+// @Success 200 {object} MemoDetailResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 403 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos/{id} [get]
 func (h *MemoHandler) GetMemo(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := h.validator.ValidateID(idStr)
 	if err != nil {
 		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
 			Error:   "Invalid memo ID",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	memo, err := h.memoUsecase.GetMemo(c.Request.Context(), id)
+	deletionPreview, err := parseInclude(c.Query("include"))
+	if err != nil {
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid include parameter",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	renderHTML, err := parseRender(c.Query("render"))
+	if err != nil {
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid render parameter",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// expand指定時のみ関連データを取得
+	if rawExpand, ok := c.GetQuery("expand"); ok {
+		expand, err := parseExpand(rawExpand)
+		if err != nil {
+			problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+				Error:   "Invalid expand parameter",
+				Message: err.Error(),
+			})
+			return
+		}
+		h.getMemoDetail(c, id, expand, deletionPreview, renderHTML)
+		return
+	}
+
+	ctx := h.requestContext(c)
+	memo, err := h.memoUsecase.GetMemo(ctx, id)
 	if err != nil {
 		h.logger.WithError(err).WithField("memo_id", id).Error("メモの取得に失敗")
 
 		status := http.StatusInternalServerError
 		if err == usecase.ErrMemoNotFound {
 			status = http.StatusNotFound
+		} else if err == usecase.ErrMemoForbidden {
+			status = http.StatusForbidden
+		}
+
+		problem.JSON(c, status, ErrorResponseDTO{
+			Error: "Failed to get memo",
+		})
+		return
+	}
+
+	resp := h.toMemoResponseDTO(ctx, memo)
+	if deletionPreview {
+		resp.DeletionPreview = h.toDeletionPreviewDTO(memo, resp.Status)
+	}
+	if renderHTML {
+		resp.ContentHTML = h.renderContentHTML(memo)
+	}
+	h.respondMemo(c, http.StatusOK, resp)
+}
+
+// getMemoDetail responds with a memo and its expanded related collections
+func (h *MemoHandler) getMemoDetail(c *gin.Context, id int, expand domain.MemoExpand, deletionPreview, renderHTML bool) {
+	ctx := h.requestContext(c)
+	detail, err := h.memoUsecase.GetMemoDetail(ctx, id, expand)
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", id).Error("メモ詳細の取得に失敗")
+
+		status := http.StatusInternalServerError
+		if err == usecase.ErrMemoNotFound {
+			status = http.StatusNotFound
+		} else if err == usecase.ErrMemoForbidden {
+			status = http.StatusForbidden
 		}
 
-		c.JSON(status, ErrorResponseDTO{
+		problem.JSON(c, status, ErrorResponseDTO{
 			Error: "Failed to get memo",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, h.toMemoResponseDTO(memo))
+	response := MemoDetailResponseDTO{MemoResponseDTO: h.toMemoResponseDTO(ctx, detail.Memo)}
+	if deletionPreview {
+		response.DeletionPreview = h.toDeletionPreviewDTO(detail.Memo, response.Status)
+	}
+	if renderHTML {
+		response.ContentHTML = h.renderContentHTML(detail.Memo)
+	}
+	if expand.Revisions {
+		revisions := h.toMemoRevisionResponseDTOs(ctx, detail.Revisions)
+		response.Revisions = &revisions
+	}
+	if expand.Attachments {
+		attachments := toMemoAttachmentResponseDTOs(detail.Attachments)
+		response.Attachments = &attachments
+	}
+
+	h.respondMemo(c, http.StatusOK, response)
+}
+
+// parseExpand parses a comma separated expand parameter
+func parseExpand(raw string) (domain.MemoExpand, error) {
+	var expand domain.MemoExpand
+	for _, item := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(item) {
+		case "revisions":
+			expand.Revisions = true
+		case "attachments":
+			expand.Attachments = true
+		case "":
+			// 空要素は無視
+		default:
+			return expand, fmt.Errorf("unsupported expand value %q (allowed: revisions, attachments)", strings.TrimSpace(item))
+		}
+	}
+	return expand, nil
+}
+
+// parseInclude parses the comma separated include query parameter of GetMemo and
+// reports whether the deletion preview was requested
+func parseInclude(raw string) (bool, error) {
+	var deletionPreview bool
+	for _, item := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(item) {
+		case "deletion_preview":
+			deletionPreview = true
+		case "":
+			// 空要素は無視
+		default:
+			return false, fmt.Errorf("unsupported include value %q (allowed: deletion_preview)", strings.TrimSpace(item))
+		}
+	}
+	return deletionPreview, nil
+}
+
+// parseRender parses the render parameter; only html is supported
+func parseRender(raw string) (bool, error) {
+	switch strings.TrimSpace(raw) {
+	case "":
+		return false, nil
+	case "html":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported render value %q (allowed: html)", strings.TrimSpace(raw))
+	}
+}
+
+// renderContentHTML renders the Markdown content of the memo to sanitized HTML.
+// Content saved through the API is HTML-escaped, so it is unescaped first to get back the Markdown source;
+// any HTML in it is then dropped by the renderer rather than passed through
+func (h *MemoHandler) renderContentHTML(memo *domain.Memo) string {
+	rendered, err := h.markdown.Render(html.UnescapeString(memo.Content))
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", memo.ID).Warn("Markdownの変換に失敗")
+		return ""
+	}
+	return rendered
+}
+
+// toDeletionPreviewDTO describes the next step of the staged deletion of the memo
+// (DELETE /api/memos/:id archives active memos and trashes archived ones, trashed memos are deleted permanently).
+// Every step can be undone: permanently deleted memos stay restorable from the recycle log for DeletedRetention
+func (h *MemoHandler) toDeletionPreviewDTO(memo *domain.Memo, status interface{}) *DeletionPreviewDTO {
+	preview := &DeletionPreviewDTO{
+		Status:           status,
+		NextDeleteAction: string(memo.Status.NextDeleteAction()),
+		Reversible:       true,
+	}
+
+	switch memo.Status.NextDeleteAction() {
+	case domain.DeleteActionPermanent:
+		preview.Endpoint = fmt.Sprintf("DELETE /api/memos/%d/permanent", memo.ID)
+		if memo.TrashedAt != nil && h.config.TrashRetention > 0 {
+			purgeAt := memo.TrashedAt.Add(h.config.TrashRetention)
+			preview.PurgeAt = &purgeAt
+		}
+		// リサイクルログの保持期間が0の場合は期限なく復元できる
+		if h.config.DeletedRetention > 0 {
+			restorableUntil := time.Now().Add(h.config.DeletedRetention)
+			preview.RestorableUntil = &restorableUntil
+		}
+	default:
+		preview.Endpoint = fmt.Sprintf("DELETE /api/memos/%d", memo.ID)
+	}
+	return preview
 }
 
 // ListMemos retrieves memos with filtering
+// @Summary List memos with filtering
+// @Description Lists the caller's memos; trashed memos are only included with status=trashed
+// @Tags memos
+// @Produce json
+// @Param category query string false "Category filter"
+// @Param status query string false "Status filter" Enums(active, archived, trashed)
+// @Param priority query string false "Priority filter" Enums(low, medium, high)
+// @Param tags query string false "Comma separated tags; memos must have all of them"
+// @Param due_before query string false "Only memos due before this RFC3339 time"
+// @Param due_after query string false "Only memos due after this RFC3339 time"
+// @Param overdue query bool false "Only active memos past their due date"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page; defaults to DEFAULT_PAGE_SIZE and must not exceed MAX_PAGE_SIZE" default(10)
+// @Param cursor query string false "Cursor from next_cursor of the previous page; pages are ordered pinned first, then by creation time, and cannot be combined with sort"
+// @Param sort query string false "Sort fields, e.g. -priority,title; without it pinned memos come first, then newest created (use -updated_at for recently updated)"
+// @Param enums query string false "Enum format of priority and status" Enums(string, numeric)
+// @Param If-None-Match header string false "ETag of a previous response; 304 is returned while the list is unchanged"
+// @Success 200 {object} MemoListResponseDTO
+// @Success 204 "No memos matched (only with EMPTY_LIST_STATUS=204)"
+// @Success 304 "The list has not changed since the ETag in If-None-Match"
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos [get]
 func (h *MemoHandler) ListMemos(c *gin.Context) {
+	if !h.checkQueryParams(c, memoFilterQueryKeys) {
+		return
+	}
+
 	var filterDTO MemoFilterDTO
 	if err := c.ShouldBindQuery(&filterDTO); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
 			Error:   "Invalid query parameters",
 			Message: err.Error(),
 		})
@@ -136,11 +517,12 @@ func (h *MemoHandler) ListMemos(c *gin.Context) {
 	// フィルターのバリデーション
 	if err := h.validator.Validate(&filterDTO); err != nil {
 		h.logger.WithError(err).Error("フィルターバリデーションエラー")
+		h.logValidationRejects(c, err)
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
-			c.JSON(http.StatusBadRequest, validationErrors)
+			problem.JSON(c, h.validationErrorStatus(), validationErrors)
 			return
 		}
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
+		problem.JSON(c, h.validationErrorStatus(), ErrorResponseDTO{
 			Error:   "Filter validation failed",
 			Message: err.Error(),
 		})
@@ -149,18 +531,37 @@ func (h *MemoHandler) ListMemos(c *gin.Context) {
 
 	// フィルター値のサニタイゼーション
 	sanitizedFilter := MemoFilterDTO{
-		Category: h.validator.SanitizeInput(filterDTO.Category),
-		Status:   filterDTO.Status,   // 列挙値なのでサニタイズ不要
-		Priority: filterDTO.Priority, // 列挙値なのでサニタイズ不要
-		Search:   h.validator.SanitizeInput(filterDTO.Search),
-		Tags:     h.validator.SanitizeInput(filterDTO.Tags),
-		Page:     filterDTO.Page,
-		Limit:    filterDTO.Limit,
+		Category:  h.validator.SanitizeInput(filterDTO.Category),
+		Status:    filterDTO.Status,   // 列挙値なのでサニタイズ不要
+		Priority:  filterDTO.Priority, // 列挙値なのでサニタイズ不要
+		Search:    h.validator.SanitizeInput(filterDTO.Search),
+		Tags:      h.validator.SanitizeInput(filterDTO.Tags),
+		DueBefore: filterDTO.DueBefore, // RFC3339として厳密に解析するためサニタイズ不要
+		DueAfter:  filterDTO.DueAfter,  // RFC3339として厳密に解析するためサニタイズ不要
+		Overdue:   filterDTO.Overdue,   // 列挙値なのでサニタイズ不要
+		Page:      filterDTO.Page,
+		Limit:     filterDTO.Limit,
+		Cursor:    filterDTO.Cursor, // デコード時に厳密に検証するためサニタイズ不要
+		Sort:      filterDTO.Sort,   // ホワイトリストで検証するためサニタイズ不要
+	}
+
+	filter, err := h.toDomainFilter(c, sanitizedFilter)
+	if err != nil {
+		problem.JSON(c, http.StatusBadRequest, filterErrorResponse(err))
+		return
 	}
 
-	filter := h.toDomainFilter(sanitizedFilter)
+	// 一覧の更新状況からETagを作り、クライアントのキャッシュが最新なら本文を返さない
+	if etag := h.memoListETag(c, filter); etag != "" {
+		c.Header("ETag", etag)
+		c.Header("Cache-Control", "private, no-cache")
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
 
-	memos, total, err := h.memoUsecase.ListMemos(c.Request.Context(), filter)
+	memos, total, err := h.memoUsecase.ListMemos(h.requestContext(c), filter)
 	if err != nil {
 		h.logger.WithError(err).Error("メモリストの取得に失敗")
 
@@ -169,31 +570,38 @@ func (h *MemoHandler) ListMemos(c *gin.Context) {
 			status = http.StatusBadRequest
 		}
 
-		c.JSON(status, ErrorResponseDTO{
+		problem.JSON(c, status, ErrorResponseDTO{
 			Error:   "Failed to get memos",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	response := MemoListResponseDTO{
-		Memos:      h.toMemoResponseDTOs(memos),
-		Total:      total,
-		Page:       filter.Page,
-		Limit:      filter.Limit,
-		TotalPages: (total + filter.Limit - 1) / filter.Limit,
-	}
-
-	c.JSON(http.StatusOK, response)
+	h.respondMemoList(c, memos, total, filter)
 }
 
 // UpdateMemo updates an existing memo
+// @Summary Update a memo
+// @Tags memos
+// @Accept json
+// @Produce json
+// @Param id path int true "Memo ID"
+// @Param If-Match header string false "Version the client last read"
+// @Param memo body UpdateMemoRequestDTO true "Fields to update"
+// @Success 200 {object} MemoResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 403 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 409 {object} ErrorResponseDTO
+// @Failure 422 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos/{id} [put]
 func (h *MemoHandler) UpdateMemo(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := h.validator.ValidateID(idStr)
 	if err != nil {
 		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
 			Error:   "Invalid memo ID",
 			Message: err.Error(),
 		})
@@ -203,21 +611,34 @@ func (h *MemoHandler) UpdateMemo(c *gin.Context) {
 	var req UpdateMemoRequestDTO
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.WithError(err).Error("リクエストのバインドに失敗")
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
-			Error:   "Invalid request format",
-			Message: err.Error(),
-		})
+		problem.JSON(c, bindJSONErrorStatus(err), bindJSONErrorResponse(err))
 		return
 	}
 
+	// 期待するバージョンはボディの version を優先し、なければ If-Match ヘッダーから取得
+	if req.Version == nil {
+		if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+			version, err := parseIfMatchVersion(ifMatch)
+			if err != nil {
+				problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+					Error:   "Invalid If-Match header",
+					Message: err.Error(),
+				})
+				return
+			}
+			req.Version = &version
+		}
+	}
+
 	// カスタムバリデーション実行
-	if err := h.validator.Validate(&req); err != nil {
+	if err := h.validateMemoRequest(&req, req.Tags); err != nil {
 		h.logger.WithError(err).Error("バリデーションエラー")
+		h.logValidationRejects(c, err)
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
-			c.JSON(http.StatusBadRequest, validationErrors)
+			problem.JSON(c, h.validationErrorStatus(), validationErrors)
 			return
 		}
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
+		problem.JSON(c, h.validationErrorStatus(), ErrorResponseDTO{
 			Error:   "Validation failed",
 			Message: err.Error(),
 		})
@@ -228,6 +649,8 @@ func (h *MemoHandler) UpdateMemo(c *gin.Context) {
 	sanitizedReq := UpdateMemoRequestDTO{
 		Priority: req.Priority, // 列挙値なのでサニタイズ不要
 		Status:   req.Status,   // 列挙値なのでサニタイズ不要
+		DueDate:  req.DueDate,
+		RemindAt: req.RemindAt,
 	}
 
 	if req.Title != nil {
@@ -253,21 +676,36 @@ func (h *MemoHandler) UpdateMemo(c *gin.Context) {
 		Tags:     sanitizedReq.Tags,
 		Priority: sanitizedReq.Priority,
 		Status:   sanitizedReq.Status,
+		DueDate:  sanitizedReq.DueDate,
+		RemindAt: sanitizedReq.RemindAt,
+		Version:  req.Version,
 	}
 
-	memo, err := h.memoUsecase.UpdateMemo(c.Request.Context(), id, usecaseReq)
+	ctx := h.requestContext(c)
+	memo, err := h.memoUsecase.UpdateMemo(ctx, id, usecaseReq)
 	if err != nil {
 		h.logger.WithError(err).WithField("memo_id", id).Error("メモの更新に失敗")
 
 		status := http.StatusInternalServerError
 		if err == usecase.ErrMemoNotFound {
 			status = http.StatusNotFound
-		} else if err == usecase.ErrInvalidTitle || err == usecase.ErrInvalidContent ||
+		} else if err == usecase.ErrMemoForbidden {
+			status = http.StatusForbidden
+		} else if err == usecase.ErrInvalidTitle || err == usecase.ErrInvalidContent || err == usecase.ErrContentTooLong ||
 			err == usecase.ErrInvalidPriority || err == usecase.ErrInvalidStatus {
 			status = http.StatusBadRequest
+		} else if err == usecase.ErrLastActiveInCategory {
+			status = http.StatusConflict
+		} else if err == usecase.ErrVersionConflict {
+			problem.JSON(c, http.StatusConflict, ErrorResponseDTO{
+				Error:   "Version conflict",
+				Code:    ErrorCodeVersionConflict,
+				Message: "the memo was modified by another request; fetch the latest version with GET /api/memos/:id, reapply your changes and retry with its version",
+			})
+			return
 		}
 
-		c.JSON(status, ErrorResponseDTO{
+		problem.JSON(c, status, ErrorResponseDTO{
 			Error:   "Failed to update memo",
 			Message: err.Error(),
 		})
@@ -275,212 +713,1621 @@ func (h *MemoHandler) UpdateMemo(c *gin.Context) {
 	}
 
 	h.logger.WithField("memo_id", id).Info("メモを更新しました")
-	c.JSON(http.StatusOK, h.toMemoResponseDTO(memo))
+	resp := h.toMemoResponseDTO(ctx, memo)
+	if req.Content != nil {
+		resp.Warnings = h.contentWarnings(memo.Content)
+	}
+	h.respondMemo(c, http.StatusOK, resp)
 }
 
-// DeleteMemo deletes a memo
-func (h *MemoHandler) DeleteMemo(c *gin.Context) {
+// UpdateMemoMetadata updates only the category, tags, priority, color and due date of a memo
+func (h *MemoHandler) UpdateMemoMetadata(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := h.validator.ValidateID(idStr)
 	if err != nil {
 		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
 			Error:   "Invalid memo ID",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	err = h.memoUsecase.DeleteMemo(c.Request.Context(), id)
+	var req UpdateMemoMetadataRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("リクエストのバインドに失敗")
+		problem.JSON(c, bindJSONErrorStatus(err), bindJSONErrorResponse(err))
+		return
+	}
+
+	// カスタムバリデーション実行
+	if err := h.validateMemoRequest(&req, req.Tags); err != nil {
+		h.logger.WithError(err).Error("バリデーションエラー")
+		h.logValidationRejects(c, err)
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			problem.JSON(c, h.validationErrorStatus(), validationErrors)
+			return
+		}
+		problem.JSON(c, h.validationErrorStatus(), ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// サニタイゼーション処理
+	usecaseReq := usecase.UpdateMemoMetadataRequest{
+		Priority:     req.Priority, // 列挙値なのでサニタイズ不要
+		Color:        req.Color,    // ユースケースで形式を検証
+		DueDate:      req.DueDate.Value,
+		ClearDueDate: req.DueDate.Set && req.DueDate.Value == nil,
+	}
+	if req.Category != nil {
+		sanitized := h.validator.SanitizeInput(*req.Category)
+		usecaseReq.Category = &sanitized
+	}
+	if req.Tags != nil {
+		usecaseReq.Tags = h.validator.SanitizeTags(req.Tags)
+	}
+
+	ctx := h.requestContext(c)
+	memo, err := h.memoUsecase.UpdateMemoMetadata(ctx, id, usecaseReq)
 	if err != nil {
-		h.logger.WithError(err).WithField("memo_id", id).Error("メモの削除に失敗")
+		h.logger.WithError(err).WithField("memo_id", id).Error("メモのメタデータの更新に失敗")
 
 		status := http.StatusInternalServerError
 		if err == usecase.ErrMemoNotFound {
 			status = http.StatusNotFound
+		} else if err == usecase.ErrMemoForbidden {
+			status = http.StatusForbidden
+		} else if err == usecase.ErrEmptyMetadataUpdate || err == usecase.ErrInvalidPriority || err == usecase.ErrInvalidColor {
+			status = http.StatusBadRequest
 		}
 
-		c.JSON(status, ErrorResponseDTO{
-			Error: "Failed to delete memo",
+		problem.JSON(c, status, ErrorResponseDTO{
+			Error:   "Failed to update memo metadata",
+			Message: err.Error(),
 		})
 		return
 	}
 
-	h.logger.WithField("memo_id", id).Info("メモを削除しました")
-	c.Status(http.StatusNoContent)
+	h.logger.WithField("memo_id", id).Info("メモのメタデータを更新しました")
+	h.respondMemo(c, http.StatusOK, h.toMemoResponseDTO(ctx, memo))
 }
 
-// ArchiveMemo archives a memo
-func (h *MemoHandler) ArchiveMemo(c *gin.Context) {
+// parseIfMatchVersion parses a memo version from an If-Match header such as `3`, `"3"` or `W/"3"`
+func parseIfMatchVersion(header string) (int, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(header), "W/")
+	value = strings.Trim(value, `"`)
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("If-Match must contain a memo version: %q", header)
+	}
+	return version, nil
+}
+
+// GetMemoHistory lists the previous versions of a memo, newest first
+func (h *MemoHandler) GetMemoHistory(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := h.validator.ValidateID(idStr)
 	if err != nil {
 		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
 			Error:   "Invalid memo ID",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	err = h.memoUsecase.ArchiveMemo(c.Request.Context(), id)
+	ctx := h.requestContext(c)
+	revisions, err := h.memoUsecase.ListMemoHistory(ctx, id)
 	if err != nil {
-		h.logger.WithError(err).WithField("memo_id", id).Error("メモのアーカイブに失敗")
+		h.logger.WithError(err).WithField("memo_id", id).Error("メモ履歴の取得に失敗")
 
 		status := http.StatusInternalServerError
 		if err == usecase.ErrMemoNotFound {
 			status = http.StatusNotFound
 		}
 
-		c.JSON(status, ErrorResponseDTO{
-			Error: "Failed to archive memo",
+		problem.JSON(c, status, ErrorResponseDTO{
+			Error: "Failed to get memo history",
 		})
 		return
 	}
 
-	h.logger.WithField("memo_id", id).Info("メモをアーカイブしました")
-	c.Status(http.StatusNoContent)
+	h.respondMemo(c, http.StatusOK, MemoHistoryResponseDTO{Revisions: h.toMemoRevisionResponseDTOs(ctx, revisions)})
 }
 
-// RestoreMemo restores an archived memo
-func (h *MemoHandler) RestoreMemo(c *gin.Context) {
+// RevertMemo restores a memo to a previous revision; the replaced version is kept in the history
+func (h *MemoHandler) RevertMemo(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := h.validator.ValidateID(idStr)
 	if err != nil {
 		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
 			Error:   "Invalid memo ID",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	err = h.memoUsecase.RestoreMemo(c.Request.Context(), id)
+	revisionIDStr := c.Param("revisionID")
+	revisionID, err := h.validator.ValidateID(revisionIDStr)
 	if err != nil {
-		h.logger.WithError(err).WithField("memo_id", id).Error("メモの復元に失敗")
+		h.logger.WithError(err).WithField("raw_id", revisionIDStr).Error("無効なリビジョンID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid revision ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx := h.requestContext(c)
+	memo, err := h.memoUsecase.RevertMemo(ctx, id, revisionID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"memo_id":     id,
+			"revision_id": revisionID,
+		}).Error("メモの復元に失敗")
 
 		status := http.StatusInternalServerError
-		if err == usecase.ErrMemoNotFound {
+		switch err {
+		case usecase.ErrMemoNotFound, usecase.ErrRevisionNotFound:
 			status = http.StatusNotFound
+		case usecase.ErrVersionConflict:
+			status = http.StatusConflict
 		}
 
-		c.JSON(status, ErrorResponseDTO{
-			Error: "Failed to restore memo",
+		problem.JSON(c, status, ErrorResponseDTO{
+			Error:   "Failed to revert memo",
+			Message: err.Error(),
 		})
 		return
 	}
 
-	h.logger.WithField("memo_id", id).Info("メモを復元しました")
-	c.Status(http.StatusNoContent)
+	h.logger.WithFields(logrus.Fields{
+		"memo_id":     id,
+		"revision_id": revisionID,
+	}).Info("メモを以前のバージョンに復元しました")
+	h.respondMemo(c, http.StatusOK, h.toMemoResponseDTO(ctx, memo))
 }
 
-// SearchMemos searches memos
-func (h *MemoHandler) SearchMemos(c *gin.Context) {
-	var filterDTO MemoFilterDTO
-	if err := c.ShouldBindQuery(&filterDTO); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
-			Error:   "Invalid query parameters",
+// DeleteMemo moves a memo one stage towards deletion: active memos are archived and archived memos are
+// moved to the trash. Memos already in the trash are only removed by PermanentDeleteMemo
+// @Summary Archive or trash a memo (staged delete)
+// @Tags memos
+// @Param id path int true "Memo ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 403 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 409 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos/{id} [delete]
+func (h *MemoHandler) DeleteMemo(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := h.validator.ValidateID(idStr)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid memo ID",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	// フィルターのバリデーション
-	if err := h.validator.Validate(&filterDTO); err != nil {
-		h.logger.WithError(err).Error("検索フィルターバリデーションエラー")
-		if validationErrors, ok := err.(validator.ValidationErrors); ok {
-			c.JSON(http.StatusBadRequest, validationErrors)
-			return
+	err = h.memoUsecase.DeleteMemo(h.requestContext(c), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", id).Error("メモの削除に失敗")
+
+		switch err {
+		case usecase.ErrMemoNotFound:
+			problem.JSON(c, http.StatusNotFound, ErrorResponseDTO{
+				Error: "Failed to delete memo",
+			})
+		case usecase.ErrMemoForbidden:
+			problem.JSON(c, http.StatusForbidden, ErrorResponseDTO{
+				Error: "Failed to delete memo",
+			})
+		case usecase.ErrLastActiveInCategory, usecase.ErrMemoAlreadyTrashed:
+			problem.JSON(c, http.StatusConflict, ErrorResponseDTO{
+				Error:   "Failed to delete memo",
+				Message: err.Error(),
+			})
+		default:
+			problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+				Error: "Failed to delete memo",
+			})
 		}
-		c.JSON(http.StatusBadRequest, ErrorResponseDTO{
-			Error:   "Filter validation failed",
-			Message: err.Error(),
-		})
 		return
 	}
 
-	// サニタイゼーション
-	sanitizedFilter := MemoFilterDTO{
-		Category: h.validator.SanitizeInput(filterDTO.Category),
-		Status:   filterDTO.Status,
-		Priority: filterDTO.Priority,
-		Search:   h.validator.SanitizeInput(filterDTO.Search),
-		Tags:     h.validator.SanitizeInput(filterDTO.Tags),
-		Page:     filterDTO.Page,
-		Limit:    filterDTO.Limit,
-	}
+	h.logger.WithField("memo_id", id).Info("メモを削除しました")
+	c.Status(http.StatusNoContent)
+}
 
-	query := sanitizedFilter.Search
-	filter := h.toDomainFilter(sanitizedFilter)
+// BulkDeleteMemos deletes several memos in one transaction and reports the outcome per ID
+func (h *MemoHandler) BulkDeleteMemos(c *gin.Context) {
+	var req BulkDeleteRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("一括削除リクエストのバインドに失敗")
+		problem.JSON(c, bindJSONErrorStatus(err), bindJSONErrorResponse(err))
+		return
+	}
 
-	memos, total, err := h.memoUsecase.SearchMemos(c.Request.Context(), query, filter)
+	results, err := h.memoUsecase.BulkDeleteMemos(h.requestContext(c), req.IDs)
 	if err != nil {
-		h.logger.WithError(err).Error("メモ検索に失敗")
-
-		status := http.StatusInternalServerError
-		if err == usecase.ErrInvalidPage || err == usecase.ErrInvalidLimit {
-			status = http.StatusBadRequest
+		h.logger.WithError(err).WithField("ids", req.IDs).Error("メモの一括削除に失敗")
+
+		switch err {
+		case usecase.ErrInvalidBulkIDs:
+			problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+				Error:   "Invalid request",
+				Message: err.Error(),
+			})
+		case usecase.ErrLastActiveInCategory:
+			problem.JSON(c, http.StatusConflict, ErrorResponseDTO{
+				Error:   "Failed to delete memos",
+				Message: err.Error(),
+			})
+		default:
+			problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+				Error: "Failed to delete memos",
+			})
 		}
-
-		c.JSON(status, ErrorResponseDTO{
-			Error:   "Failed to search memos",
-			Message: err.Error(),
-		})
 		return
 	}
 
-	response := MemoListResponseDTO{
-		Memos:      h.toMemoResponseDTOs(memos),
-		Total:      total,
-		Page:       filter.Page,
-		Limit:      filter.Limit,
-		TotalPages: (total + filter.Limit - 1) / filter.Limit,
+	response := BulkResponseDTO{Results: make([]BulkResultDTO, len(results))}
+	for i, result := range results {
+		response.Results[i] = BulkResultDTO{ID: result.ID, Status: result.Status}
 	}
 
+	h.logger.WithField("count", len(results)).Info("メモを一括削除しました")
 	c.JSON(http.StatusOK, response)
 }
 
-// Helper methods for conversion
-
-func (h *MemoHandler) toMemoResponseDTO(memo *domain.Memo) MemoResponseDTO {
-	return MemoResponseDTO{
-		ID:          memo.ID,
-		Title:       memo.Title,
-		Content:     memo.Content,
-		Category:    memo.Category,
-		Tags:        memo.Tags,
-		Priority:    memo.Priority.String(),
-		Status:      memo.Status.String(),
-		CreatedAt:   memo.CreatedAt,
-		UpdatedAt:   memo.UpdatedAt,
-		CompletedAt: memo.CompletedAt,
-	}
-}
-
-func (h *MemoHandler) toMemoResponseDTOs(memos []domain.Memo) []MemoResponseDTO {
-	result := make([]MemoResponseDTO, len(memos))
-	for i, memo := range memos {
-		result[i] = h.toMemoResponseDTO(&memo)
+// BulkUpdateMemos applies the same status and/or category change to several memos in one transaction
+func (h *MemoHandler) BulkUpdateMemos(c *gin.Context) {
+	var req BulkUpdateRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("一括更新リクエストのバインドに失敗")
+		problem.JSON(c, bindJSONErrorStatus(err), bindJSONErrorResponse(err))
+		return
 	}
-	return result
-}
 
-func (h *MemoHandler) toDomainFilter(dto MemoFilterDTO) domain.MemoFilter {
-	var tags []string
-	if dto.Tags != "" {
-		tags = strings.Split(dto.Tags, ",")
-		for i := range tags {
-			tags[i] = strings.TrimSpace(tags[i])
-		}
+	// カスタムバリデーション実行
+	if err := h.validator.Validate(&req); err != nil {
+		h.logger.WithError(err).Error("バリデーションエラー")
+		h.logValidationRejects(c, err)
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			problem.JSON(c, h.validationErrorStatus(), validationErrors)
+			return
+		}
+		problem.JSON(c, h.validationErrorStatus(), ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	usecaseReq := usecase.UpdateMemoRequest{
+		Status: req.Status, // 列挙値なのでサニタイズ不要
+	}
+	if req.Category != nil {
+		sanitized := h.validator.SanitizeInput(*req.Category)
+		usecaseReq.Category = &sanitized
+	}
+
+	result, err := h.memoUsecase.BulkUpdateMemos(h.requestContext(c), req.IDs, usecaseReq)
+	if err != nil {
+		h.logger.WithError(err).WithField("ids", req.IDs).Error("メモの一括更新に失敗")
+
+		switch err {
+		case usecase.ErrInvalidBulkIDs, usecase.ErrInvalidBulkUpdate, usecase.ErrInvalidStatus:
+			problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+				Error:   "Invalid request",
+				Message: err.Error(),
+			})
+		case usecase.ErrLastActiveInCategory:
+			problem.JSON(c, http.StatusConflict, ErrorResponseDTO{
+				Error:   "Failed to update memos",
+				Message: err.Error(),
+			})
+		default:
+			problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+				Error: "Failed to update memos",
+			})
+		}
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"updated": result.Updated,
+		"skipped": len(result.SkippedIDs),
+	}).Info("メモを一括更新しました")
+	c.JSON(http.StatusOK, BulkUpdateResponseDTO{
+		Updated:    result.Updated,
+		SkippedIDs: result.SkippedIDs,
+	})
+}
+
+// ArchiveMemo archives a memo
+// @Summary Archive a memo
+// @Tags memos
+// @Param id path int true "Memo ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 403 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 409 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos/{id}/archive [patch]
+func (h *MemoHandler) ArchiveMemo(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := h.validator.ValidateID(idStr)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid memo ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.memoUsecase.ArchiveMemo(h.requestContext(c), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", id).Error("メモのアーカイブに失敗")
+
+		switch err {
+		case usecase.ErrMemoNotFound:
+			problem.JSON(c, http.StatusNotFound, ErrorResponseDTO{
+				Error: "Failed to archive memo",
+			})
+		case usecase.ErrMemoForbidden:
+			problem.JSON(c, http.StatusForbidden, ErrorResponseDTO{
+				Error: "Failed to archive memo",
+			})
+		case usecase.ErrLastActiveInCategory:
+			problem.JSON(c, http.StatusConflict, ErrorResponseDTO{
+				Error:   "Failed to archive memo",
+				Message: err.Error(),
+			})
+		default:
+			problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+				Error: "Failed to archive memo",
+			})
+		}
+		return
+	}
+
+	h.logger.WithField("memo_id", id).Info("メモをアーカイブしました")
+	c.Status(http.StatusNoContent)
+}
+
+// TrashMemo moves a memo to the trash
+func (h *MemoHandler) TrashMemo(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := h.validator.ValidateID(idStr)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid memo ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx := h.requestContext(c)
+	memo, err := h.memoUsecase.TrashMemo(ctx, id)
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", id).Error("メモのゴミ箱への移動に失敗")
+
+		switch err {
+		case usecase.ErrMemoNotFound:
+			problem.JSON(c, http.StatusNotFound, ErrorResponseDTO{
+				Error: "Failed to trash memo",
+			})
+		case usecase.ErrMemoForbidden:
+			problem.JSON(c, http.StatusForbidden, ErrorResponseDTO{
+				Error: "Failed to trash memo",
+			})
+		case usecase.ErrLastActiveInCategory:
+			problem.JSON(c, http.StatusConflict, ErrorResponseDTO{
+				Error:   "Failed to trash memo",
+				Message: err.Error(),
+			})
+		default:
+			problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+				Error: "Failed to trash memo",
+			})
+		}
+		return
+	}
+
+	h.logger.WithField("memo_id", id).Info("メモをゴミ箱に移動しました")
+	h.respondMemo(c, http.StatusOK, h.toMemoResponseDTO(ctx, memo))
+}
+
+// PermanentDeleteMemo physically deletes a memo that is already in the trash
+// @Summary Permanently delete a trashed memo
+// @Description The memo is kept in the recycle log and can be restored from /api/memos/deleted until its retention expires
+// @Tags memos
+// @Param id path int true "Memo ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 403 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 409 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos/{id}/permanent [delete]
+func (h *MemoHandler) PermanentDeleteMemo(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := h.validator.ValidateID(idStr)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid memo ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.memoUsecase.PermanentDeleteMemo(h.requestContext(c), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", id).Error("メモの完全削除に失敗")
+
+		switch err {
+		case usecase.ErrMemoNotFound:
+			problem.JSON(c, http.StatusNotFound, ErrorResponseDTO{
+				Error: "Failed to delete memo",
+			})
+		case usecase.ErrMemoForbidden:
+			problem.JSON(c, http.StatusForbidden, ErrorResponseDTO{
+				Error: "Failed to delete memo",
+			})
+		case usecase.ErrMemoNotTrashed:
+			problem.JSON(c, http.StatusConflict, ErrorResponseDTO{
+				Error:   "Failed to delete memo",
+				Message: err.Error(),
+			})
+		default:
+			problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+				Error: "Failed to delete memo",
+			})
+		}
+		return
+	}
+
+	h.logger.WithField("memo_id", id).Info("メモを完全に削除しました")
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeletedMemos returns the caller's permanently deleted memos that can still be restored
+func (h *MemoHandler) ListDeletedMemos(c *gin.Context) {
+	if !h.checkQueryParams(c, deletedMemoQueryKeys) {
+		return
+	}
+
+	var pageDTO PageDTO
+	if err := c.ShouldBindQuery(&pageDTO); err != nil {
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	limit, err := h.pageLimit(pageDTO.Limit)
+	if err != nil {
+		problem.JSON(c, http.StatusBadRequest, filterErrorResponse(err))
+		return
+	}
+
+	filter := domain.MemoFilter{Page: pageDTO.Page, Limit: limit}
+	ctx := h.requestContext(c)
+	deleted, total, err := h.memoUsecase.ListDeletedMemos(ctx, filter)
+	if err != nil {
+		h.logger.WithError(err).Error("削除済みメモの取得に失敗")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+			Error: "Failed to get deleted memos",
+		})
+		return
+	}
+
+	response := DeletedMemoListResponseDTO{
+		Memos:      make([]DeletedMemoResponseDTO, len(deleted)),
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages(total, filter.Limit),
+	}
+	for i, item := range deleted {
+		response.Memos[i] = DeletedMemoResponseDTO{
+			MemoResponseDTO: h.toMemoResponseDTO(ctx, &item.Memo),
+			DeletedID:       item.ID,
+			DeletedAt:       item.DeletedAt,
+		}
+	}
+	h.respondMemo(c, http.StatusOK, response)
+}
+
+// RestoreDeletedMemo restores a permanently deleted memo from the recycle log as an active memo.
+// The response carries the memo's ID, which differs from its old ID if that ID has been reused
+func (h *MemoHandler) RestoreDeletedMemo(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := h.validator.ValidateID(idStr)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid deleted memo ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx := h.requestContext(c)
+	memo, err := h.memoUsecase.RestoreDeletedMemo(ctx, id)
+	if err != nil {
+		h.logger.WithError(err).WithField("deleted_id", id).Error("削除済みメモの復元に失敗")
+
+		status := http.StatusInternalServerError
+		if err == usecase.ErrDeletedMemoNotFound {
+			status = http.StatusNotFound
+		}
+
+		problem.JSON(c, status, ErrorResponseDTO{
+			Error: "Failed to restore deleted memo",
+		})
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"deleted_id": id,
+		"memo_id":    memo.ID,
+	}).Info("削除済みメモを復元しました")
+	h.respondMemo(c, http.StatusCreated, h.toMemoResponseDTO(ctx, memo))
+}
+
+// RestoreMemo restores an archived or trashed memo
+// @Summary Restore an archived or trashed memo
+// @Tags memos
+// @Param id path int true "Memo ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 403 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos/{id}/restore [patch]
+func (h *MemoHandler) RestoreMemo(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := h.validator.ValidateID(idStr)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid memo ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.memoUsecase.RestoreMemo(h.requestContext(c), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", id).Error("メモの復元に失敗")
+
+		status := http.StatusInternalServerError
+		if err == usecase.ErrMemoNotFound {
+			status = http.StatusNotFound
+		} else if err == usecase.ErrMemoForbidden {
+			status = http.StatusForbidden
+		}
+
+		problem.JSON(c, status, ErrorResponseDTO{
+			Error: "Failed to restore memo",
+		})
+		return
+	}
+
+	h.logger.WithField("memo_id", id).Info("メモを復元しました")
+	c.Status(http.StatusNoContent)
+}
+
+// SearchMemos searches memos
+// @Summary Search memos
+// @Description Full-text search ordered by relevance; each memo carries its rank
+// @Tags memos
+// @Produce json
+// @Param search query string false "Search query"
+// @Param category query string false "Category filter"
+// @Param status query string false "Status filter" Enums(active, archived, trashed)
+// @Param priority query string false "Priority filter" Enums(low, medium, high)
+// @Param tags query string false "Comma separated tags; memos must have all of them"
+// @Param due_before query string false "Only memos due before this RFC3339 time"
+// @Param due_after query string false "Only memos due after this RFC3339 time"
+// @Param overdue query bool false "Only active memos past their due date"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page; defaults to DEFAULT_PAGE_SIZE and must not exceed MAX_PAGE_SIZE" default(10)
+// @Param cursor query string false "Cursor from next_cursor of the previous page; pages are ordered pinned first, then by creation time, and cannot be combined with sort"
+// @Param sort query string false "Sort fields, e.g. -priority,title"
+// @Param enums query string false "Enum format of priority and status" Enums(string, numeric)
+// @Success 200 {object} MemoListResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/memos/search [get]
+func (h *MemoHandler) SearchMemos(c *gin.Context) {
+	if !h.checkQueryParams(c, memoFilterQueryKeys) {
+		return
+	}
+
+	var filterDTO MemoFilterDTO
+	if err := c.ShouldBindQuery(&filterDTO); err != nil {
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// フィルターのバリデーション
+	if err := h.validator.Validate(&filterDTO); err != nil {
+		h.logger.WithError(err).Error("検索フィルターバリデーションエラー")
+		h.logValidationRejects(c, err)
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			problem.JSON(c, h.validationErrorStatus(), validationErrors)
+			return
+		}
+		problem.JSON(c, h.validationErrorStatus(), ErrorResponseDTO{
+			Error:   "Filter validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// サニタイゼーション
+	sanitizedFilter := MemoFilterDTO{
+		Category:  h.validator.SanitizeInput(filterDTO.Category),
+		Status:    filterDTO.Status,
+		Priority:  filterDTO.Priority,
+		Search:    h.validator.SanitizeInput(filterDTO.Search),
+		Tags:      h.validator.SanitizeInput(filterDTO.Tags),
+		DueBefore: filterDTO.DueBefore, // RFC3339として厳密に解析するためサニタイズ不要
+		DueAfter:  filterDTO.DueAfter,  // RFC3339として厳密に解析するためサニタイズ不要
+		Overdue:   filterDTO.Overdue,   // 列挙値なのでサニタイズ不要
+		Page:      filterDTO.Page,
+		Limit:     filterDTO.Limit,
+		Cursor:    filterDTO.Cursor, // デコード時に厳密に検証するためサニタイズ不要
+		Sort:      filterDTO.Sort,   // ホワイトリストで検証するためサニタイズ不要
+	}
+
+	query := sanitizedFilter.Search
+	filter, err := h.toDomainFilter(c, sanitizedFilter)
+	if err != nil {
+		problem.JSON(c, http.StatusBadRequest, filterErrorResponse(err))
+		return
+	}
+
+	memos, total, err := h.memoUsecase.SearchMemos(h.requestContext(c), query, filter)
+	if err != nil {
+		h.logger.WithError(err).Error("メモ検索に失敗")
+
+		status := http.StatusInternalServerError
+		if err == usecase.ErrInvalidPage || err == usecase.ErrInvalidLimit {
+			status = http.StatusBadRequest
+		}
+
+		problem.JSON(c, status, ErrorResponseDTO{
+			Error:   "Failed to search memos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.respondMemoList(c, memos, total, filter)
+}
+
+// GetRecentSearchQueries returns the user's recent search queries
+func (h *MemoHandler) GetRecentSearchQueries(c *gin.Context) {
+	queries, err := h.memoUsecase.RecentSearchQueries(h.requestContext(c))
+	if err != nil {
+		h.logger.WithError(err).Error("検索履歴の取得に失敗")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+			Error: "Failed to get recent search queries",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, RecentQueriesResponseDTO{Queries: queries})
+}
+
+// ClearRecentSearchQueries clears the user's recent search queries
+func (h *MemoHandler) ClearRecentSearchQueries(c *gin.Context) {
+	if err := h.memoUsecase.ClearRecentSearchQueries(h.requestContext(c)); err != nil {
+		h.logger.WithError(err).Error("検索履歴の削除に失敗")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+			Error: "Failed to clear recent search queries",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListTags returns tags in use with their counts
+func (h *MemoHandler) ListTags(c *gin.Context) {
+	if !h.checkQueryParams(c, tagFilterQueryKeys) {
+		return
+	}
+
+	var filterDTO TagFilterDTO
+	if err := c.ShouldBindQuery(&filterDTO); err != nil {
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// 未指定の場合はゼロ値のままにし、usecase側でデフォルト値を適用する
+	var filter domain.TagFilter
+	if filterDTO.Limit != nil {
+		filter.Limit = *filterDTO.Limit
+	}
+	if filterDTO.MinCount != nil {
+		filter.MinCount = *filterDTO.MinCount
+	}
+
+	tags, err := h.memoUsecase.ListTags(h.requestContext(c), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("タグ一覧の取得に失敗")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+			Error: "Failed to get tags",
+		})
+		return
+	}
+
+	response := TagListResponseDTO{Tags: make([]TagCountResponseDTO, len(tags))}
+	for i, tag := range tags {
+		response.Tags[i] = TagCountResponseDTO{Tag: tag.Tag, Count: tag.Count}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ListCategories lists the distinct categories the user has already used, for autocomplete
+func (h *MemoHandler) ListCategories(c *gin.Context) {
+	categories, err := h.memoUsecase.ListCategories(h.requestContext(c))
+	if err != nil {
+		h.logger.WithError(err).Error("カテゴリー一覧の取得に失敗")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+			Error: "Failed to get categories",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CategoryListResponseDTO{Categories: categories})
+}
+
+// GetMemoStats returns memo counts of the caller for dashboards
+func (h *MemoHandler) GetMemoStats(c *gin.Context) {
+	stats, err := h.memoUsecase.GetMemoStats(h.requestContext(c))
+	if err != nil {
+		h.logger.WithError(err).Error("メモの統計の取得に失敗")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+			Error: "Failed to get memo stats",
+		})
+		return
+	}
+
+	resp := MemoStatsResponseDTO{
+		Total:             stats.Total,
+		ByStatus:          make(map[string]int, len(stats.ByStatus)),
+		ByPriority:        make(map[string]int, len(stats.ByPriority)),
+		ByCategory:        make(map[string]int, len(stats.ByCategory)),
+		CreatedLast7Days:  stats.CreatedLast7Days,
+		CreatedLast30Days: stats.CreatedLast30Days,
+	}
+	for status, count := range stats.ByStatus {
+		resp.ByStatus[string(status)] = count
+	}
+	for priority, count := range stats.ByPriority {
+		resp.ByPriority[string(priority)] = count
+	}
+	for category, count := range stats.ByCategory {
+		resp.ByCategory[category] = count
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListSharedMemos returns memos other users have shared with the caller, kept separate from owned memos
+func (h *MemoHandler) ListSharedMemos(c *gin.Context) {
+	if !h.checkQueryParams(c, sharedMemoQueryKeys) {
+		return
+	}
+
+	var pageDTO PageDTO
+	if err := c.ShouldBindQuery(&pageDTO); err != nil {
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	limit, err := h.pageLimit(pageDTO.Limit)
+	if err != nil {
+		problem.JSON(c, http.StatusBadRequest, filterErrorResponse(err))
+		return
+	}
+
+	filter := domain.MemoFilter{Page: pageDTO.Page, Limit: limit}
+	ctx := h.requestContext(c)
+	shared, total, err := h.memoUsecase.ListSharedMemos(ctx, filter)
+	if err != nil {
+		h.logger.WithError(err).Error("共有メモの取得に失敗")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+			Error: "Failed to get shared memos",
+		})
+		return
+	}
+
+	response := SharedMemoListResponseDTO{
+		Memos:      make([]SharedMemoResponseDTO, len(shared)),
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages(total, filter.Limit),
+	}
+	for i, item := range shared {
+		response.Memos[i] = SharedMemoResponseDTO{
+			MemoResponseDTO: h.toMemoResponseDTO(ctx, &item.Memo),
+			OwnerUsername:   item.OwnerUsername,
+			Permission:      string(item.Permission),
+			SharedAt:        item.SharedAt,
+		}
+	}
+	h.respondMemo(c, http.StatusOK, response)
+}
+
+// ListOnThisDay lists memos created on today's month and day in previous years
+func (h *MemoHandler) ListOnThisDay(c *gin.Context) {
+	if !h.checkQueryParams(c, onThisDayQueryKeys) {
+		return
+	}
+
+	ctx := h.requestContext(c)
+	memos, err := h.memoUsecase.ListOnThisDay(ctx)
+	if err != nil {
+		h.logger.WithError(err).Error("過去の同じ日のメモの取得に失敗")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+			Error: "Failed to get on-this-day memos",
+		})
+		return
+	}
+
+	h.respondMemo(c, http.StatusOK, OnThisDayResponseDTO{Memos: h.toMemoResponseDTOs(ctx, memos)})
+}
+
+// PromoteMemo raises a memo's priority and pins it in one operation
+func (h *MemoHandler) PromoteMemo(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := h.validator.ValidateID(idStr)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid memo ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx := h.requestContext(c)
+	memo, err := h.memoUsecase.PromoteMemo(ctx, id)
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", id).Error("メモの昇格に失敗")
+
+		status := http.StatusInternalServerError
+		if err == usecase.ErrMemoNotFound {
+			status = http.StatusNotFound
+		} else if err == usecase.ErrMemoForbidden {
+			status = http.StatusForbidden
+		}
+
+		problem.JSON(c, status, ErrorResponseDTO{
+			Error: "Failed to promote memo",
+		})
+		return
+	}
+
+	h.logger.WithField("memo_id", id).Info("メモを昇格しました")
+	h.respondMemo(c, http.StatusOK, h.toMemoResponseDTO(ctx, memo))
+}
+
+// TouchMemo bumps a memo's updated_at without changing its content
+func (h *MemoHandler) TouchMemo(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := h.validator.ValidateID(idStr)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid memo ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx := h.requestContext(c)
+	memo, err := h.memoUsecase.TouchMemo(ctx, id)
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", id).Error("メモの更新日時の更新に失敗")
+
+		status := http.StatusInternalServerError
+		if err == usecase.ErrMemoNotFound {
+			status = http.StatusNotFound
+		} else if err == usecase.ErrMemoForbidden {
+			status = http.StatusForbidden
+		}
+
+		problem.JSON(c, status, ErrorResponseDTO{
+			Error: "Failed to touch memo",
+		})
+		return
+	}
+
+	h.respondMemo(c, http.StatusOK, h.toMemoResponseDTO(ctx, memo))
+}
+
+// PinMemo pins a memo so that it is listed first
+func (h *MemoHandler) PinMemo(c *gin.Context) {
+	h.setPinned(c, true)
+}
+
+// UnpinMemo removes the pin from a memo
+func (h *MemoHandler) UnpinMemo(c *gin.Context) {
+	h.setPinned(c, false)
+}
+
+func (h *MemoHandler) setPinned(c *gin.Context, pinned bool) {
+	idStr := c.Param("id")
+	id, err := h.validator.ValidateID(idStr)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid memo ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx := h.requestContext(c)
+	var memo *domain.Memo
+	if pinned {
+		memo, err = h.memoUsecase.PinMemo(ctx, id)
+	} else {
+		memo, err = h.memoUsecase.UnpinMemo(ctx, id)
+	}
+	if err != nil {
+		h.logger.WithError(err).WithField("memo_id", id).Error("メモのピン留めの変更に失敗")
+
+		status := http.StatusInternalServerError
+		if err == usecase.ErrMemoNotFound {
+			status = http.StatusNotFound
+		} else if err == usecase.ErrMemoForbidden {
+			status = http.StatusForbidden
+		}
+
+		problem.JSON(c, status, ErrorResponseDTO{
+			Error: "Failed to update memo pin",
+		})
+		return
+	}
+
+	h.respondMemo(c, http.StatusOK, h.toMemoResponseDTO(ctx, memo))
+}
+
+// respondMemoList writes a paginated memo list, honoring the configured status for empty results
+func (h *MemoHandler) respondMemoList(c *gin.Context, memos []domain.Memo, total int, filter domain.MemoFilter) {
+	if len(memos) == 0 && h.config.EmptyListStatus == http.StatusNoContent {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	memoDTOs := h.toMemoResponseDTOs(h.requestContext(c), memos)
+	response := MemoListResponseDTO{
+		Memos:      memoDTOs,
+		Returned:   len(memoDTOs),
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages(total, filter.Limit),
+	}
+
+	// カーソルモードでページが埋まった場合のみ次のカーソルを返す（空の場合は終端）
+	if filter.Cursor != nil && len(memos) > 0 && len(memos) >= filter.Limit {
+		response.NextCursor = encodeMemoCursor(memos[len(memos)-1])
+	}
+
+	h.respondMemo(c, http.StatusOK, response)
+}
+
+// memoListETag returns a weak ETag for the memo list requested by c, or "" when the list cannot be validated:
+// with ?overdue (its result changes with the current time) or when the state cannot be read. Unauthenticated
+// lists cover all memos and are validated against the state of all memos. The state is read before the list,
+// so a concurrent change can only make the ETag older than the body, which costs the client one more full
+// response but never serves stale data
+func (h *MemoHandler) memoListETag(c *gin.Context, filter domain.MemoFilter) string {
+	if filter.Overdue {
+		return ""
+	}
+
+	ctx := h.requestContext(c)
+	userID, _ := domain.UserIDFromContext(ctx) // 未認証の場合は0
+
+	state, err := h.memoUsecase.GetMemoListState(ctx)
+	if err != nil {
+		h.logger.WithError(err).Warn("メモ一覧の更新状況の取得に失敗したためETagを付与しません")
+		return ""
+	}
+	if state == nil {
+		return ""
+	}
+
+	// 同じ更新状況でもクエリ（フィルター・ページ・表現形式）ごとに本文が異なる
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d\n%s\n%d\n%d\n%s\n%d", userID, c.Request.URL.Query().Encode(),
+		state.MaxUpdatedAt.UnixNano(), state.Count, state.PinnedIDs, state.RemindedCount)
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using the weak comparison RFC 9110 requires
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || (candidate != "" && strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// respondMemo writes a response containing memos, serializing their tags as requested by ?tags=
+func (h *MemoHandler) respondMemo(c *gin.Context, status int, body interface{}) {
+	if tagFormatFrom(c) != TagFormatObjects {
+		c.JSON(status, body)
+		return
+	}
+
+	transformed, err := tagsAsObjects(body)
+	if err != nil {
+		h.logger.WithError(err).Error("タグの変換に失敗")
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+			Error: "Failed to encode response",
+		})
+		return
+	}
+	c.JSON(status, transformed)
+}
+
+// tagFormatFrom returns the tag serialization requested by ?tags=.
+// Values other than "objects" and "strings" are tag filters and select the default format.
+func tagFormatFrom(c *gin.Context) string {
+	if isTagFormat(c.Query("tags")) {
+		return c.Query("tags")
+	}
+	return TagFormatStrings
+}
+
+// isTagFormat reports whether a ?tags= value selects a serialization format rather than a tag filter
+func isTagFormat(value string) bool {
+	return value == TagFormatStrings || value == TagFormatObjects
+}
+
+// tagsAsObjects rewrites the string tags of every memo in body into {"name": ...} objects
+func tagsAsObjects(body interface{}) (interface{}, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	transformTags(generic)
+	return generic, nil
+}
+
+// transformTags walks decoded JSON and converts the tags array of each memo object in place
+func transformTags(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if tags, ok := child.([]interface{}); ok && key == "tags" {
+				if _, isMemo := v["id"]; isMemo {
+					v[key] = tagObjects(tags)
+					continue
+				}
+			}
+			transformTags(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			transformTags(child)
+		}
+	}
+}
+
+// tagObjects converts a list of tag names into tag objects
+func tagObjects(tags []interface{}) []TagObjectDTO {
+	objects := make([]TagObjectDTO, 0, len(tags))
+	for _, tag := range tags {
+		if name, ok := tag.(string); ok {
+			objects = append(objects, TagObjectDTO{Name: name})
+		}
+	}
+	return objects
+}
+
+// bindJSONErrorStatus returns 413 when the body exceeded the request size limit and 400 otherwise
+func bindJSONErrorStatus(err error) int {
+	var sizeErr *http.MaxBytesError
+	if errors.As(err, &sizeErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// bindJSONErrorResponse distinguishes malformed JSON from schema mismatches in ShouldBindJSON errors
+func bindJSONErrorResponse(err error) ErrorResponseDTO {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var fieldErrs playground.ValidationErrors
+	var timeErr *time.ParseError
+	var sizeErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &sizeErr):
+		return ErrorResponseDTO{
+			Error:   "Request Entity Too Large",
+			Code:    ErrorCodeRequestTooLarge,
+			Message: fmt.Sprintf("request body must not exceed %d bytes", sizeErr.Limit),
+		}
+	case errors.As(err, &syntaxErr):
+		return ErrorResponseDTO{
+			Error:   "Malformed JSON",
+			Code:    ErrorCodeMalformedJSON,
+			Message: fmt.Sprintf("request body is not valid JSON (at offset %d): %s", syntaxErr.Offset, syntaxErr.Error()),
+		}
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return ErrorResponseDTO{
+			Error:   "Malformed JSON",
+			Code:    ErrorCodeMalformedJSON,
+			Message: "request body is empty or truncated",
+		}
+	case errors.As(err, &typeErr):
+		return ErrorResponseDTO{
+			Error:   "Validation failed",
+			Code:    ErrorCodeValidationFailed,
+			Message: fmt.Sprintf("field '%s' must be of type %s, got %s", typeErr.Field, typeErr.Type.String(), typeErr.Value),
+		}
+	case errors.As(err, &timeErr):
+		return ErrorResponseDTO{
+			Error:   "Validation failed",
+			Code:    ErrorCodeValidationFailed,
+			Message: fmt.Sprintf("date-time value %q must be in RFC3339 format (e.g. 2024-01-02T15:04:05Z)", timeErr.Value),
+		}
+	case errors.As(err, &fieldErrs):
+		messages := make([]string, len(fieldErrs))
+		for i, fieldErr := range fieldErrs {
+			messages[i] = fmt.Sprintf("field '%s' failed '%s' validation", fieldErr.Field(), fieldErr.Tag())
+		}
+		return ErrorResponseDTO{
+			Error:   "Validation failed",
+			Code:    ErrorCodeValidationFailed,
+			Message: strings.Join(messages, "; "),
+		}
+	default:
+		return ErrorResponseDTO{
+			Error:   "Invalid request format",
+			Code:    ErrorCodeValidationFailed,
+			Message: err.Error(),
+		}
+	}
+}
+
+// checkQueryParams rejects unknown query keys with 400 when strict mode is enabled.
+// It returns false if the response has already been written.
+func (h *MemoHandler) checkQueryParams(c *gin.Context, known map[string]bool) bool {
+	if !h.config.StrictQueryParams {
+		return true
+	}
+
+	var unknown []string
+	for key := range c.Request.URL.Query() {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return true
+	}
+
+	sort.Strings(unknown)
+	h.logger.WithField("unknown_params", unknown).Warn("不明なクエリパラメータ")
+	problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+		Error:   "Unknown query parameters",
+		Message: fmt.Sprintf("unknown query parameters: %s", strings.Join(unknown, ", ")),
+	})
+	return false
+}
+
+// queryKeysOf collects the `form` tag names of a query DTO
+func queryKeysOf(dto interface{}) map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(dto)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("form"), ",")[0]
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// withKeys returns a copy of keys extended with the given extra query keys
+func withKeys(keys map[string]bool, extra ...string) map[string]bool {
+	merged := make(map[string]bool, len(keys)+len(extra))
+	for key := range keys {
+		merged[key] = true
+	}
+	for _, key := range extra {
+		merged[key] = true
+	}
+	return merged
+}
+
+// validateMemoRequest runs the struct validation and then limits the number of tags of each memo in the request
+func (h *MemoHandler) validateMemoRequest(req any, tagLists ...[]string) error {
+	if err := h.validator.Validate(req); err != nil {
+		return err
+	}
+	for _, tags := range tagLists {
+		if err := h.validator.ValidateTagCount(tags, h.config.TagsPerMemoLimit()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importTags returns the tags of each memo in an admin import
+func importTags(memos []ImportMemoRequestDTO) [][]string {
+	tags := make([][]string, len(memos))
+	for i, memo := range memos {
+		tags[i] = memo.Tags
+	}
+	return tags
+}
+
+// logValidationRejects emits a structured event for each input rejected as a possible attack.
+// The raw payload is never logged.
+func (h *MemoHandler) logValidationRejects(c *gin.Context, err error) {
+	if !h.config.LogValidationRejects {
+		return
+	}
+	for _, rejection := range h.validator.SecurityRejections(err) {
+		h.logger.WithFields(logrus.Fields{
+			"event":     "validation_reject",
+			"rule":      rejection.Rule,
+			"field":     rejection.Field,
+			"client_ip": c.ClientIP(),
+			"method":    c.Request.Method,
+			"path":      c.FullPath(),
+		}).Warn("攻撃の可能性がある入力を拒否しました")
+	}
+}
+
+// validationErrorStatus returns the status for requests rejected by CustomValidator.
+// Malformed JSON is always 400; validation failures are 400 or 422 depending on configuration.
+func (h *MemoHandler) validationErrorStatus() int {
+	if h.config.ValidationErrorStatus == http.StatusUnprocessableEntity {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
+
+// contentWarnings returns non-blocking warnings for content above the configured soft limit
+func (h *MemoHandler) contentWarnings(content string) []string {
+	if h.config.ContentSoftLimit <= 0 {
+		return nil
+	}
+	length := utf8.RuneCountInString(content)
+	if length <= h.config.ContentSoftLimit {
+		return nil
+	}
+	return []string{fmt.Sprintf("content is very long (%d characters; recommended maximum is %d)", length, h.config.ContentSoftLimit)}
+}
+
+// requestContext returns the request context carrying the authenticated user ID and request ID, if any
+func (h *MemoHandler) requestContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(int); ok {
+			ctx = domain.WithUserID(ctx, id)
+		}
+	}
+	if requestID := requestIDFrom(c); requestID != "" {
+		ctx = domain.WithRequestID(ctx, requestID)
+	}
+	if c.Query("enums") == EnumFormatNumeric {
+		ctx = context.WithValue(ctx, enumFormatKey{}, EnumFormatNumeric)
+	}
+	return ctx
+}
+
+// enumFormatKey is the context key for the enum serialization selected with ?enums=
+type enumFormatKey struct{}
+
+// enumFormatFrom returns the enum serialization carried by ctx, defaulting to strings
+func enumFormatFrom(ctx context.Context) string {
+	if format, ok := ctx.Value(enumFormatKey{}).(string); ok {
+		return format
+	}
+	return EnumFormatString
+}
+
+// priorityValue renders a priority as its name or, in numeric mode, its code
+func priorityValue(priority domain.Priority, numeric bool) interface{} {
+	if numeric {
+		return priority.Code()
+	}
+	return priority.String()
+}
+
+// statusValue renders a status as its name or, in numeric mode, its code
+func statusValue(status domain.Status, numeric bool) interface{} {
+	if numeric {
+		return status.Code()
+	}
+	return status.String()
+}
+
+// requestIDFrom returns the request ID set by middleware, falling back to the X-Request-ID header
+func requestIDFrom(c *gin.Context) string {
+	if requestID := c.GetString("request_id"); requestID != "" {
+		return requestID
+	}
+	return c.GetHeader("X-Request-ID")
+}
+
+// Helper methods for conversion
+
+func (h *MemoHandler) toMemoResponseDTO(ctx context.Context, memo *domain.Memo) MemoResponseDTO {
+	return NewMemoResponseDTO(memo, enumFormatFrom(ctx) == EnumFormatNumeric)
+}
+
+// NewMemoResponseDTO converts a memo to its API representation, e.g. for webhook payloads.
+// With numeric the priority and status are rendered as their codes
+func NewMemoResponseDTO(memo *domain.Memo, numeric bool) MemoResponseDTO {
+	return MemoResponseDTO{
+		ID:          memo.ID,
+		Title:       memo.Title,
+		Content:     memo.Content,
+		Category:    memo.Category,
+		Tags:        memo.Tags,
+		Priority:    priorityValue(memo.Priority, numeric),
+		Status:      statusValue(memo.Status, numeric),
+		Pinned:      memo.Pinned,
+		CreatedAt:   memo.CreatedAt,
+		UpdatedAt:   memo.UpdatedAt,
+		CompletedAt: memo.CompletedAt,
+		TrashedAt:   memo.TrashedAt,
+		DueDate:     memo.DueDate,
+		RemindAt:    memo.RemindAt,
+		Reminded:    memo.Reminded,
+		Color:       memo.Color,
+		Version:     memo.Version,
+		WordCount:   memo.WordCount(),
+		CharCount:   memo.CharCount(),
+		Rank:        memo.SearchRank,
+	}
+}
+
+func (h *MemoHandler) toMemoRevisionResponseDTOs(ctx context.Context, revisions []domain.MemoRevision) []MemoRevisionResponseDTO {
+	numeric := enumFormatFrom(ctx) == EnumFormatNumeric
+	result := make([]MemoRevisionResponseDTO, len(revisions))
+	for i, revision := range revisions {
+		result[i] = MemoRevisionResponseDTO{
+			ID:        revision.ID,
+			Title:     revision.Title,
+			Content:   revision.Content,
+			Category:  revision.Category,
+			Tags:      revision.Tags,
+			Priority:  priorityValue(revision.Priority, numeric),
+			CreatedAt: revision.CreatedAt,
+		}
+	}
+	return result
+}
+
+func (h *MemoHandler) toMemoResponseDTOs(ctx context.Context, memos []domain.Memo) []MemoResponseDTO {
+	result := make([]MemoResponseDTO, len(memos))
+	for i, memo := range memos {
+		result[i] = h.toMemoResponseDTO(ctx, &memo)
+	}
+	return result
+}
+
+func (h *MemoHandler) toDomainFilter(c *gin.Context, dto MemoFilterDTO) (domain.MemoFilter, error) {
+	var tags []string
+	if dto.Tags != "" && !isTagFormat(dto.Tags) {
+		for _, tag := range strings.Split(dto.Tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
 	}
 
-	return domain.MemoFilter{
+	filter := domain.MemoFilter{
 		Category: dto.Category,
 		Status:   domain.Status(dto.Status),
 		Priority: domain.Priority(dto.Priority),
 		Search:   dto.Search,
 		Tags:     tags,
 		Page:     dto.Page,
-		Limit:    dto.Limit,
 	}
+
+	limit, err := h.pageLimit(dto.Limit)
+	if err != nil {
+		return filter, err
+	}
+	filter.Limit = limit
+
+	if dto.DueBefore != "" {
+		dueBefore, err := time.Parse(time.RFC3339, dto.DueBefore)
+		if err != nil {
+			return filter, &filterParamError{title: "Invalid due_before parameter", err: fmt.Errorf("due_before must be in RFC3339 format: %q", dto.DueBefore)}
+		}
+		filter.DueBefore = &dueBefore
+	}
+	if dto.DueAfter != "" {
+		dueAfter, err := time.Parse(time.RFC3339, dto.DueAfter)
+		if err != nil {
+			return filter, &filterParamError{title: "Invalid due_after parameter", err: fmt.Errorf("due_after must be in RFC3339 format: %q", dto.DueAfter)}
+		}
+		filter.DueAfter = &dueAfter
+	}
+	filter.Overdue = dto.Overdue == "true"
+
+	sort, err := parseSort(dto.Sort)
+	if err != nil {
+		return filter, &filterParamError{title: "Invalid sort parameter", err: err}
+	}
+	filter.Sort = sort
+
+	// cursor パラメータが指定された場合（空文字を含む）はカーソルページネーションに切り替える
+	if _, ok := c.GetQuery("cursor"); ok {
+		if len(sort) > 0 {
+			return filter, &filterParamError{title: "Invalid sort parameter", err: errors.New("sort cannot be combined with cursor")}
+		}
+		cursor, err := decodeMemoCursor(dto.Cursor)
+		if err != nil {
+			return filter, &filterParamError{title: "Invalid cursor", err: err}
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
+
+// filterParamError describes a query parameter rejected while building a domain filter
+type filterParamError struct {
+	title string
+	err   error
+}
+
+func (e *filterParamError) Error() string {
+	return e.err.Error()
+}
+
+// pageLimit applies the configured page sizes to a requested limit: zero or less selects DEFAULT_PAGE_SIZE and
+// a limit above MAX_PAGE_SIZE is clamped with CLAMP_PAGE_SIZE and rejected otherwise
+func (h *MemoHandler) pageLimit(limit int) (int, error) {
+	defaultSize, maxSize := h.config.PageSizes()
+	switch {
+	case limit <= 0:
+		return defaultSize, nil
+	case limit <= maxSize:
+		return limit, nil
+	case h.config.ClampPageSize:
+		return maxSize, nil
+	default:
+		return 0, &filterParamError{title: "Invalid limit parameter", err: fmt.Errorf("limit must not exceed %d: %d", maxSize, limit)}
+	}
+}
+
+// totalPages returns the number of pages of limit items needed for total items (0 for a non-positive limit)
+func totalPages(total, limit int) int {
+	if limit <= 0 {
+		return 0
+	}
+	return (total + limit - 1) / limit
+}
+
+// filterErrorResponse converts an error from toDomainFilter into an error response
+func filterErrorResponse(err error) ErrorResponseDTO {
+	var paramErr *filterParamError
+	if errors.As(err, &paramErr) {
+		return ErrorResponseDTO{Error: paramErr.title, Message: paramErr.err.Error()}
+	}
+	return ErrorResponseDTO{Error: "Invalid query parameters", Message: err.Error()}
+}
+
+// parseSort parses a comma-separated sort specification such as "priority,-created_at".
+// A leading "-" sorts the field in descending order; unknown or repeated fields are rejected.
+func parseSort(raw string) ([]domain.SortField, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var fields []domain.SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(part, "-")
+
+		if !domain.IsSortableMemoField(name) {
+			return nil, fmt.Errorf("unknown sort field: %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate sort field: %q", name)
+		}
+		seen[name] = true
+		fields = append(fields, domain.SortField{Field: name, Desc: desc})
+	}
+	return fields, nil
+}
+
+// encodeMemoCursor encodes the position of a memo (created_at, id and whether it is pinned) as an opaque cursor
+func encodeMemoCursor(memo domain.Memo) string {
+	raw := fmt.Sprintf("%s,%d", memo.CreatedAt.UTC().Format(time.RFC3339Nano), memo.ID)
+	if memo.Pinned {
+		raw += ",pinned"
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeMemoCursor decodes a cursor produced by encodeMemoCursor; an empty cursor starts from the first page.
+// Cursors issued before pinned memos were ordered first have no pinned part and continue among unpinned memos
+func decodeMemoCursor(raw string) (*domain.MemoCursor, error) {
+	if raw == "" {
+		return &domain.MemoCursor{}, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errors.New("cursor is not valid base64")
+	}
+
+	parts := strings.Split(string(decoded), ",")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, errors.New("cursor is malformed")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, errors.New("cursor timestamp is malformed")
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil || id <= 0 {
+		return nil, errors.New("cursor id is malformed")
+	}
+	pinned := len(parts) == 3
+	if pinned && parts[2] != "pinned" {
+		return nil, errors.New("cursor is malformed")
+	}
+
+	return &domain.MemoCursor{Pinned: pinned, CreatedAt: createdAt, ID: id}, nil
 }
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"memo-app/src/problem"
+	"memo-app/src/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// MaxImportFileBytes is the maximum size of an uploaded import file
+const MaxImportFileBytes = 10 << 20
+
+// MaxImportRequestBytes is the request body limit for import routes, leaving room for multipart headers
+const MaxImportRequestBytes = MaxImportFileBytes + 1<<20
+
+// importQueryKeys is the set of query keys accepted by the import endpoint
+var importQueryKeys = withKeys(nil, "atomic")
+
+// errImportTooLarge is returned when the import payload exceeds MaxImportFileBytes
+var errImportTooLarge = fmt.Errorf("import payload must not exceed %d bytes", MaxImportFileBytes)
+
+// ImportMemos recreates memos from a previously exported JSON array for the caller.
+// The array is read from the multipart field "file" or from the request body. Each memo
+// gets a new ID; fields other than title, content, category, tags, priority and due_date
+// (such as id or status) are ignored. With ?atomic=true nothing is imported when any memo fails.
+func (h *MemoHandler) ImportMemos(c *gin.Context) {
+	if !h.checkQueryParams(c, importQueryKeys) {
+		return
+	}
+
+	atomic := false
+	if raw := c.Query("atomic"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+				Error:   "Invalid atomic parameter",
+				Message: "atomic must be true or false",
+			})
+			return
+		}
+		atomic = parsed
+	}
+
+	items, err := h.readImportPayload(c)
+	if err != nil {
+		h.logger.WithError(err).Error("インポートファイルの読み込みに失敗")
+		status := http.StatusBadRequest
+		if errors.Is(err, errImportTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		problem.JSON(c, status, ErrorResponseDTO{
+			Error:   "Invalid import payload",
+			Message: err.Error(),
+		})
+		return
+	}
+	if len(items) == 0 || len(items) > usecase.MaxImportMemos {
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid import payload",
+			Message: usecase.ErrInvalidImport.Error(),
+		})
+		return
+	}
+
+	// 各メモをバインド・検証し、通過したものだけをユースケースへ渡す（indexes は元の位置）
+	summary := ImportSummaryDTO{Failed: []ImportFailureDTO{}}
+	reqs := make([]usecase.CreateMemoRequest, 0, len(items))
+	indexes := make([]int, 0, len(items))
+	for i, item := range items {
+		var memo CreateMemoRequestDTO
+		if err := json.Unmarshal(item, &memo); err != nil {
+			summary.Failed = append(summary.Failed, ImportFailureDTO{Index: i, Error: err.Error()})
+			continue
+		}
+		if err := h.validateMemoRequest(&memo, memo.Tags); err != nil {
+			h.logValidationRejects(c, err)
+			summary.Failed = append(summary.Failed, ImportFailureDTO{Index: i, Error: err.Error()})
+			continue
+		}
+
+		reqs = append(reqs, usecase.CreateMemoRequest{
+			Title:    h.validator.SanitizeInput(memo.Title),
+			Content:  h.validator.SanitizeInput(memo.Content),
+			Category: h.validator.SanitizeInput(memo.Category),
+			Tags:     h.validator.SanitizeTags(memo.Tags),
+			Priority: memo.Priority, // 列挙値なのでサニタイズ不要
+			DueDate:  memo.DueDate,
+		})
+		indexes = append(indexes, i)
+	}
+
+	if len(reqs) > 0 && !(atomic && len(summary.Failed) > 0) {
+		ctx := h.requestContext(c)
+		result, err := h.memoUsecase.ImportMemos(ctx, reqs, atomic)
+		if err != nil {
+			h.logger.WithError(err).Error("メモのインポートに失敗")
+			problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{
+				Error: "Failed to import memos",
+			})
+			return
+		}
+		summary.Imported = result.Imported
+		for _, failure := range result.Failed {
+			summary.Failed = append(summary.Failed, ImportFailureDTO{Index: indexes[failure.Index], Error: failure.Err.Error()})
+		}
+	}
+	sortImportFailures(summary.Failed)
+
+	// アトミックモードで失敗がある場合は何も作成されていない
+	status := http.StatusOK
+	if atomic && len(summary.Failed) > 0 {
+		summary.Imported = 0
+		status = h.validationErrorStatus()
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"imported": summary.Imported,
+		"failed":   len(summary.Failed),
+		"atomic":   atomic,
+	}).Info("メモをインポートしました")
+	h.memosCreated(MemoSourceImport, summary.Imported)
+	c.JSON(status, summary)
+}
+
+// readImportPayload reads the JSON array of memos from the multipart field "file" or the request body
+func (h *MemoHandler) readImportPayload(c *gin.Context) ([]json.RawMessage, error) {
+	var r io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			var sizeErr *http.MaxBytesError
+			if errors.As(err, &sizeErr) {
+				return nil, errImportTooLarge
+			}
+			return nil, fmt.Errorf("multipart field \"file\" is required: %w", err)
+		}
+		if fileHeader.Size > MaxImportFileBytes {
+			return nil, errImportTooLarge
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+	if r == nil {
+		return nil, errors.New("request body is required")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxImportFileBytes+1))
+	if err != nil {
+		var sizeErr *http.MaxBytesError
+		if errors.As(err, &sizeErr) {
+			return nil, errImportTooLarge
+		}
+		return nil, err
+	}
+	if len(data) > MaxImportFileBytes {
+		return nil, errImportTooLarge
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("import payload must be a JSON array of memos: %w", err)
+	}
+	return items, nil
+}
+
+// sortImportFailures orders failures by their index in the payload
+func sortImportFailures(failures []ImportFailureDTO) {
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Index < failures[j].Index })
+}
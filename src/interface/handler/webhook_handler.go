@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"memo-app/src/domain"
+	"memo-app/src/problem"
+	"memo-app/src/usecase"
+	"memo-app/src/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// WebhookHandler handles HTTP requests for managing the caller's webhooks
+type WebhookHandler struct {
+	webhookUsecase usecase.WebhookUsecase
+	logger         *logrus.Logger
+	validator      *validator.CustomValidator
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookUsecase usecase.WebhookUsecase, logger *logrus.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUsecase: webhookUsecase,
+		logger:         logger,
+		validator:      validator.NewCustomValidator(),
+	}
+}
+
+// CreateWebhook registers a webhook that receives the caller's memo events
+// @Summary Register a webhook for memo events
+// @Description Deliveries are POSTed with an X-Webhook-Signature header (sha256=HMAC-SHA256 of the body keyed by the secret)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body CreateWebhookRequestDTO true "Webhook data"
+// @Success 201 {object} WebhookResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req CreateWebhookRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("リクエストのバインドに失敗")
+		problem.JSON(c, bindJSONErrorStatus(err), bindJSONErrorResponse(err))
+		return
+	}
+
+	webhook, err := h.webhookUsecase.CreateWebhook(h.requestContext(c), usecase.CreateWebhookRequest{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("Webhookの作成に失敗")
+		h.respondError(c, err, "Failed to create webhook")
+		return
+	}
+
+	// 署名の検証に必要なため、シークレットは作成時のみ返す
+	resp := toWebhookResponseDTO(webhook)
+	resp.Secret = webhook.Secret
+	h.logger.WithField("webhook_id", webhook.ID).Info("Webhookを作成しました")
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListWebhooks lists the caller's webhooks
+// @Summary List webhooks
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} WebhookListResponseDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookUsecase.ListWebhooks(h.requestContext(c))
+	if err != nil {
+		h.logger.WithError(err).Error("Webhook一覧の取得に失敗")
+		h.respondError(c, err, "Failed to list webhooks")
+		return
+	}
+
+	resp := WebhookListResponseDTO{Webhooks: make([]WebhookResponseDTO, len(webhooks))}
+	for i := range webhooks {
+		resp.Webhooks[i] = toWebhookResponseDTO(&webhooks[i])
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetWebhook retrieves a webhook of the caller
+// @Summary Get a webhook
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} WebhookResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, ok := h.webhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookUsecase.GetWebhook(h.requestContext(c), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("webhook_id", id).Error("Webhookの取得に失敗")
+		h.respondError(c, err, "Failed to get webhook")
+		return
+	}
+	c.JSON(http.StatusOK, toWebhookResponseDTO(webhook))
+}
+
+// UpdateWebhook changes the URL, secret or events of a webhook of the caller
+// @Summary Update a webhook
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param webhook body UpdateWebhookRequestDTO true "Fields to update"
+// @Success 200 {object} WebhookResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := h.webhookID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("リクエストのバインドに失敗")
+		problem.JSON(c, bindJSONErrorStatus(err), bindJSONErrorResponse(err))
+		return
+	}
+
+	webhook, err := h.webhookUsecase.UpdateWebhook(h.requestContext(c), id, usecase.UpdateWebhookRequest{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("webhook_id", id).Error("Webhookの更新に失敗")
+		h.respondError(c, err, "Failed to update webhook")
+		return
+	}
+
+	h.logger.WithField("webhook_id", id).Info("Webhookを更新しました")
+	c.JSON(http.StatusOK, toWebhookResponseDTO(webhook))
+}
+
+// DeleteWebhook removes a webhook of the caller
+// @Summary Delete a webhook
+// @Tags webhooks
+// @Param id path int true "Webhook ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := h.webhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookUsecase.DeleteWebhook(h.requestContext(c), id); err != nil {
+		h.logger.WithError(err).WithField("webhook_id", id).Error("Webhookの削除に失敗")
+		h.respondError(c, err, "Failed to delete webhook")
+		return
+	}
+
+	h.logger.WithField("webhook_id", id).Info("Webhookを削除しました")
+	c.Status(http.StatusNoContent)
+}
+
+// webhookID parses the :id path parameter, responding 400 when it is invalid
+func (h *WebhookHandler) webhookID(c *gin.Context) (int, bool) {
+	idStr := c.Param("id")
+	id, err := h.validator.ValidateID(idStr)
+	if err != nil {
+		h.logger.WithError(err).WithField("raw_id", idStr).Error("無効なID形式")
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{
+			Error:   "Invalid webhook ID",
+			Message: err.Error(),
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError maps a webhook usecase error to its HTTP status
+func (h *WebhookHandler) respondError(c *gin.Context, err error, message string) {
+	switch err {
+	case usecase.ErrWebhookUnauthorized:
+		problem.JSON(c, http.StatusUnauthorized, ErrorResponseDTO{Error: message, Message: err.Error()})
+	case usecase.ErrWebhookNotFound:
+		problem.JSON(c, http.StatusNotFound, ErrorResponseDTO{Error: message})
+	case usecase.ErrInvalidWebhookURL, usecase.ErrInvalidWebhookEvents, usecase.ErrInvalidWebhookSecret:
+		problem.JSON(c, http.StatusBadRequest, ErrorResponseDTO{Error: message, Message: err.Error()})
+	default:
+		problem.JSON(c, http.StatusInternalServerError, ErrorResponseDTO{Error: message})
+	}
+}
+
+// requestContext returns the request context carrying the authenticated user ID, if any
+func (h *WebhookHandler) requestContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(int); ok {
+			ctx = domain.WithUserID(ctx, id)
+		}
+	}
+	return ctx
+}
+
+func toWebhookResponseDTO(webhook *domain.Webhook) WebhookResponseDTO {
+	return WebhookResponseDTO{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
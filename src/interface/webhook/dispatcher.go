@@ -0,0 +1,252 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"memo-app/src/domain"
+	"memo-app/src/interface/handler"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Headers sent with every delivery
+const (
+	// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of the body keyed by the webhook secret
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+)
+
+// maxRetryBackoff caps the exponential backoff between retries
+const maxRetryBackoff = time.Hour
+
+// Payload is the JSON body POSTed to a webhook
+type Payload struct {
+	Event      string                  `json:"event"`
+	OccurredAt time.Time               `json:"occurred_at"`
+	Memo       handler.MemoResponseDTO `json:"memo"`
+}
+
+// Config controls the delivery workers
+type Config struct {
+	// Workers is the number of goroutines sending deliveries
+	Workers int
+	// QueueSize is the capacity of the delivery queue; events are dropped when it is full
+	QueueSize int
+	// MaxAttempts is how many times a delivery is tried, including the first attempt
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry; it doubles with every further retry
+	RetryBackoff time.Duration
+	// Timeout limits a single HTTP request
+	Timeout time.Duration
+}
+
+// DefaultConfig returns the default delivery configuration
+func DefaultConfig() Config {
+	return Config{
+		Workers:      4,
+		QueueSize:    1000,
+		MaxAttempts:  5,
+		RetryBackoff: time.Second,
+		Timeout:      10 * time.Second,
+	}
+}
+
+// job is a queued unit of work. Without a webhook it is a new event whose subscribers are looked up first
+type job struct {
+	userID  int
+	event   string
+	body    []byte
+	webhook *domain.Webhook
+	attempt int
+}
+
+// Dispatcher delivers memo events to the subscribed webhooks of their owner.
+// Publishing only enqueues the event, so a slow or failing endpoint never delays API responses;
+// failed deliveries are retried with exponential backoff up to MaxAttempts times.
+type Dispatcher struct {
+	repo   domain.WebhookRepository
+	client *http.Client
+	config Config
+	logger *logrus.Logger
+
+	mu     sync.Mutex
+	queue  chan job
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher and starts its workers. Non-positive settings fall back to DefaultConfig
+func NewDispatcher(repo domain.WebhookRepository, cfg Config, logger *logrus.Logger) *Dispatcher {
+	defaults := DefaultConfig()
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaults.Workers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaults.QueueSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaults.MaxAttempts
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaults.RetryBackoff
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+
+	d := &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+		config: cfg,
+		logger: logger,
+		queue:  make(chan job, cfg.QueueSize),
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+	return d
+}
+
+// PublishMemoEvent implements usecase.MemoEventPublisher. Memos without an authenticated owner are not delivered
+func (d *Dispatcher) PublishMemoEvent(ctx context.Context, event string, memo *domain.Memo) {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(Payload{
+		Event:      event,
+		OccurredAt: time.Now(),
+		Memo:       handler.NewMemoResponseDTO(memo, false),
+	})
+	if err != nil {
+		d.logger.WithError(err).WithField("memo_id", memo.ID).Error("Webhookの本文の作成に失敗")
+		return
+	}
+
+	d.enqueue(job{userID: userID, event: event, body: body})
+}
+
+// Close stops accepting events, waits for the queued deliveries to finish and drops pending retries
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	close(d.queue)
+	d.mu.Unlock()
+
+	d.wg.Wait()
+}
+
+// enqueue adds a job to the queue without blocking; it is dropped when the queue is full or closed
+func (d *Dispatcher) enqueue(j job) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+
+	select {
+	case d.queue <- j:
+	default:
+		d.logger.WithFields(logrus.Fields{"event": j.event, "user_id": j.userID}).Warn("送信キューが満杯のためWebhookのイベントを破棄しました")
+	}
+}
+
+// work processes jobs until the queue is closed
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for j := range d.queue {
+		if j.webhook != nil {
+			d.attempt(j)
+			continue
+		}
+
+		webhooks, err := d.repo.ListByEvent(context.Background(), j.userID, j.event)
+		if err != nil {
+			d.logger.WithError(err).WithFields(logrus.Fields{"event": j.event, "user_id": j.userID}).Error("送信先のWebhookの取得に失敗")
+			continue
+		}
+		for i := range webhooks {
+			delivery := j
+			delivery.webhook = &webhooks[i]
+			delivery.attempt = 1
+			d.attempt(delivery)
+		}
+	}
+}
+
+// attempt sends a delivery once and schedules a retry when it fails
+func (d *Dispatcher) attempt(j job) {
+	fields := logrus.Fields{"event": j.event, "webhook_id": j.webhook.ID, "attempt": j.attempt}
+	err := d.send(j)
+	if err == nil {
+		d.logger.WithFields(fields).Debug("Webhookを送信しました")
+		return
+	}
+
+	if j.attempt >= d.config.MaxAttempts {
+		d.logger.WithError(err).WithFields(fields).Error("Webhookの送信に失敗しました（再送の上限に達したため破棄）")
+		return
+	}
+
+	delay := retryDelay(d.config.RetryBackoff, j.attempt)
+	d.logger.WithError(err).WithFields(fields).WithField("retry_in", delay).Warn("Webhookの送信に失敗したため再送します")
+	retry := j
+	retry.attempt++
+	time.AfterFunc(delay, func() { d.enqueue(retry) })
+}
+
+// send POSTs the signed payload and treats any non-2xx response as a failure
+func (d *Dispatcher) send(j job) error {
+	req, err := http.NewRequest(http.MethodPost, j.webhook.URL, bytes.NewReader(j.body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, j.event)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(j.webhook.Secret, j.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// retryDelay returns base doubled for every attempt after the first, capped at maxRetryBackoff
+func retryDelay(base time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+	return delay
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed by secret, as sent in SignatureHeader after "sha256="
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
@@ -88,12 +89,31 @@ func CloseLogger() {
 	}
 }
 
+// current 初期化済みのロガーを返す（InitLogger前（テスト等）は標準ロガー）
+func current() *logrus.Logger {
+	if Log == nil {
+		return logrus.StandardLogger()
+	}
+	return Log
+}
+
 // WithFields フィールド付きログエントリを作成
 func WithFields(fields logrus.Fields) *logrus.Entry {
-	return Log.WithFields(fields)
+	return current().WithFields(fields)
+}
+
+// RequestIDKey リクエストIDを保存するgin contextのキー、およびログのフィールド名
+const RequestIDKey = "request_id"
+
+// WithRequestID リクエストID付きログエントリを作成（RequestIDMiddleware未適用の場合はフィールドなし）
+func WithRequestID(c *gin.Context) *logrus.Entry {
+	if requestID := c.GetString(RequestIDKey); requestID != "" {
+		return current().WithField(RequestIDKey, requestID)
+	}
+	return logrus.NewEntry(current())
 }
 
 // WithField フィールド付きログエントリを作成（単一フィールド）
 func WithField(key string, value interface{}) *logrus.Entry {
-	return Log.WithField(key, value)
+	return current().WithField(key, value)
 }
@@ -0,0 +1,72 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 利用可能なメール送信ドライバー
+const (
+	DriverLog  = "log"
+	DriverSMTP = "smtp"
+)
+
+// Mailer メール送信のインターフェース
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// New 設定されたドライバーに応じてMailerを作成
+func New(driver string, smtpConfig *SMTPConfig, logger *logrus.Logger) (Mailer, error) {
+	switch driver {
+	case "", DriverLog:
+		return NewLogMailer(logger), nil
+	case DriverSMTP:
+		if smtpConfig == nil || smtpConfig.Host == "" {
+			return nil, fmt.Errorf("SMTPドライバーにはSMTP_HOSTの設定が必要です")
+		}
+		if smtpConfig.From == "" {
+			return nil, fmt.Errorf("SMTPドライバーにはMAIL_FROMの設定が必要です")
+		}
+		return NewSMTPMailer(smtpConfig), nil
+	default:
+		return nil, fmt.Errorf("不明なメールドライバー: %s", driver)
+	}
+}
+
+// LogMailer メールを送信せずログに出力する開発用のMailer
+type LogMailer struct {
+	logger *logrus.Logger
+}
+
+// NewLogMailer ログ出力のみのMailerを作成
+func NewLogMailer(logger *logrus.Logger) *LogMailer {
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+	return &LogMailer{logger: logger}
+}
+
+// Send メール内容をログに出力
+func (m *LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.logger.WithFields(logrus.Fields{
+		"to":      to,
+		"subject": subject,
+		"body":    body,
+	}).Info("メールを送信しました（ログ出力のみ）")
+	return nil
+}
+
+// SendTemplate テンプレートを描画してメールを送信
+func SendTemplate(ctx context.Context, m Mailer, to string, tmpl *Template, data interface{}) error {
+	subject, body, err := tmpl.Render(data)
+	if err != nil {
+		return err
+	}
+	if err := m.Send(ctx, to, subject, body); err != nil {
+		return fmt.Errorf("メール送信に失敗: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// SMTPConfig SMTP設定
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer SMTPサーバー経由でメールを送信するMailer
+type SMTPMailer struct {
+	config *SMTPConfig
+}
+
+// NewSMTPMailer SMTPMailerを作成
+func NewSMTPMailer(config *SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: config}
+}
+
+// Send SMTPでメールを送信
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// ヘッダーインジェクション対策
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return fmt.Errorf("宛先または件名に改行を含めることはできません")
+	}
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.config.From,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("SMTP送信に失敗: %w", err)
+	}
+	return nil
+}
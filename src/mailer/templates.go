@@ -0,0 +1,63 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// Template 件名と本文のメールテンプレート
+type Template struct {
+	Name    string
+	subject *template.Template
+	body    *template.Template
+}
+
+// NewTemplate 件名と本文のテンプレート文字列からTemplateを作成
+func NewTemplate(name, subject, body string) *Template {
+	return &Template{
+		Name:    name,
+		subject: template.Must(template.New(name + "_subject").Parse(subject)),
+		body:    template.Must(template.New(name + "_body").Parse(body)),
+	}
+}
+
+// Render データを埋め込んで件名と本文を生成
+func (t *Template) Render(data interface{}) (string, string, error) {
+	var subject, body bytes.Buffer
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return "", "", fmt.Errorf("件名テンプレート %s の描画に失敗: %w", t.Name, err)
+	}
+	if err := t.body.Execute(&body, data); err != nil {
+		return "", "", fmt.Errorf("本文テンプレート %s の描画に失敗: %w", t.Name, err)
+	}
+	return subject.String(), body.String(), nil
+}
+
+// EmailVerificationTemplate メールアドレス確認用テンプレート（Username, Link, ExpiresIn）
+var EmailVerificationTemplate = NewTemplate("email_verification",
+	"[Memo App] メールアドレスの確認",
+	`{{.Username}} 様
+
+Memo App へのご登録ありがとうございます。
+以下のリンクからメールアドレスを確認してください。
+
+{{.Link}}
+
+このリンクの有効期限は {{.ExpiresIn}} です。
+心当たりがない場合はこのメールを破棄してください。
+`)
+
+// PasswordResetTemplate パスワードリセット用テンプレート（Username, Link, ExpiresIn）
+var PasswordResetTemplate = NewTemplate("password_reset",
+	"[Memo App] パスワードの再設定",
+	`{{.Username}} 様
+
+パスワード再設定のリクエストを受け付けました。
+以下のリンクから新しいパスワードを設定してください。
+
+{{.Link}}
+
+このリンクの有効期限は {{.ExpiresIn}} です。
+心当たりがない場合はこのメールを破棄してください。
+`)
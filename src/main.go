@@ -1,22 +1,30 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
+	"memo-app/src/buildinfo"
 	"memo-app/src/config"
 	"memo-app/src/database"
+	"memo-app/src/handlers"
 	"memo-app/src/infrastructure/repository"
 	"memo-app/src/interface/handler"
+	"memo-app/src/interface/webhook"
 	"memo-app/src/logger"
 	"memo-app/src/middleware"
+	"memo-app/src/notifier"
+	"memo-app/src/problem"
+	authRepository "memo-app/src/repository"
 	"memo-app/src/routes"
+	"memo-app/src/server"
+	"memo-app/src/service"
 	"memo-app/src/storage"
 	"memo-app/src/usecase"
 
@@ -24,17 +32,29 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// @title Memo App API
+// @version 2.0
+// @description メモ管理APIサーバー。仕様は src/interface/handler のアノテーションから swag で生成する（make swagger-gen）
+// @BasePath /
 func main() {
-	// Docker専用実行ガード - ローカル実行を防止
-	if !isRunningInDocker() {
-		fmt.Println("⚠️  エラー: このアプリケーションはDocker環境でのみ実行できます")
-		fmt.Println("   Docker Composeを使用して起動してください:")
-		fmt.Println("   docker-compose up -d")
-		os.Exit(1)
+	// Docker専用実行ガード - ローカル実行を防止（判定は config.DetectDocker、終了はここでのみ行う）
+	if inDocker, _ := config.DetectDocker(config.DefaultDockerProbe()); !inDocker {
+		if !config.AllowNonDocker() {
+			fmt.Println("⚠️  エラー: このアプリケーションはDocker環境でのみ実行できます")
+			fmt.Println("   Docker Composeを使用して起動してください:")
+			fmt.Println("   docker-compose up -d")
+			os.Exit(1)
+		}
+		// 開発・CI向けの明示的なエスケープハッチ（本番環境では無効）
+		fmt.Println("⚠️  警告: ALLOW_NON_DOCKER=true のためDocker外で実行しています")
+		fmt.Println("   これは開発・CI専用の設定です。本番環境では使用しないでください")
 	}
 
 	// 設定を読み込み
 	cfg := config.LoadConfig()
+	if err := cfg.Validate(); err != nil {
+		logrus.WithError(err).Fatal("設定の検証に失敗しました")
+	}
 
 	// ロガーを初期化
 	if err := logger.InitLogger(); err != nil {
@@ -42,7 +62,12 @@ func main() {
 	}
 	defer logger.CloseLogger()
 
-	logger.Log.Info("アプリケーションを開始しています")
+	build := buildinfo.Get()
+	logger.Log.WithFields(logrus.Fields{
+		"commit":     build.Commit,
+		"build_time": build.BuildTime,
+		"go_version": build.GoVersion,
+	}).Info("アプリケーションを開始しています")
 
 	// データベースに接続
 	dbConfig := &database.Config{
@@ -52,41 +77,119 @@ func main() {
 		Password: cfg.Database.Password,
 		DBName:   cfg.Database.DBName,
 		SSLMode:  cfg.Database.SSLMode,
+
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+
+		ReadRetries:      cfg.Database.ReadRetries,
+		ReadRetryBackoff: cfg.Database.ReadRetryBackoff,
 	}
 
 	db, err := database.NewDB(dbConfig, logger.Log)
 	if err != nil {
 		logger.Log.WithError(err).Fatal("データベースの接続に失敗")
 	}
-	defer db.Close()
 
 	// リポジトリ、ユースケース、ハンドラーを初期化（クリーンアーキテクチャ）
-	memoRepo := repository.NewMemoRepository(db, logger.Log)
-	memoUsecase := usecase.NewMemoUsecase(memoRepo)
-	memoHandler := handler.NewMemoHandler(memoUsecase, logger.Log)
+	memoRepo := repository.NewMemoRepositoryWithConfig(db, logger.Log, &cfg.Memo)
+	webhookRepo := repository.NewWebhookRepository(db, logger.Log)
+	// メモの作成・更新・削除はWebhookに非同期で通知する
+	webhookDispatcher := webhook.NewDispatcher(webhookRepo, webhook.Config{
+		Workers:      cfg.Webhook.Workers,
+		QueueSize:    cfg.Webhook.QueueSize,
+		MaxAttempts:  cfg.Webhook.MaxAttempts,
+		RetryBackoff: cfg.Webhook.RetryBackoff,
+		Timeout:      cfg.Webhook.Timeout,
+	}, logger.Log)
+	memoUsecase := usecase.NewMemoUsecaseWithPublisher(memoRepo, &cfg.Memo, webhookDispatcher)
+	memoHandler := handler.NewMemoHandlerWithConfig(memoUsecase, logger.Log, &cfg.Memo)
+	webhookHandler := handler.NewWebhookHandler(usecase.NewWebhookUsecase(webhookRepo), logger.Log)
+
+	// Prometheusメトリクス（リクエスト、メモの作成数、DBコネクションプール）
+	metrics := middleware.DefaultHTTPMetrics
+	memoHandler.SetMemosCreatedHook(metrics.ObserveMemosCreated)
+	if err := metrics.RegisterDBStats(db.DB, cfg.Database.DBName); err != nil {
+		logger.Log.WithError(err).Error("DBコネクションプールのメトリクス登録に失敗")
+	}
+
+	// S3クライアント（ログのアップロードと添付ファイルで同じ接続設定を共有する）
+	s3Config := &storage.S3Config{
+		Endpoint:        cfg.S3.Endpoint,
+		AccessKeyID:     cfg.S3.AccessKeyID,
+		SecretAccessKey: cfg.S3.SecretAccessKey,
+		Region:          cfg.S3.Region,
+		Bucket:          cfg.S3.Bucket,
+		UseSSL:          cfg.S3.UseSSL,
+		Concurrency:     cfg.Log.UploadConcurrency,
+		Retries:         cfg.Log.UploadRetries,
+		RetryBackoff:    cfg.Log.UploadRetryBackoff,
+		AlertThreshold:  cfg.Log.UploadAlertThreshold,
+	}
+	var s3Client *storage.Client
+	var s3ClientErr error
+	if cfg.Log.UploadEnabled || cfg.Attachment.Enabled {
+		s3Client, s3ClientErr = storage.NewClient(s3Config)
+	}
 
 	// S3アップローダーを初期化（設定が有効な場合）
 	var uploader *storage.LogUploader
+	var uploaderErr error
+	stopPeriodicUpload := func() {}
 	if cfg.Log.UploadEnabled {
-		s3Config := &storage.S3Config{
-			Endpoint:        cfg.S3.Endpoint,
-			AccessKeyID:     cfg.S3.AccessKeyID,
-			SecretAccessKey: cfg.S3.SecretAccessKey,
-			Region:          cfg.S3.Region,
-			Bucket:          cfg.S3.Bucket,
-			UseSSL:          cfg.S3.UseSSL,
-		}
-
-		var err error
-		uploader, err = storage.NewLogUploader(s3Config, logger.Log)
-		if err != nil {
-			logger.Log.WithError(err).Error("S3アップローダーの初期化に失敗")
+		if s3ClientErr != nil {
+			uploaderErr = s3ClientErr
+			logger.Log.WithError(uploaderErr).Error("S3アップローダーの初期化に失敗")
 		} else {
+			uploader = storage.NewLogUploaderWithClient(s3Client, s3Config, logger.Log)
+			// 書き込み中のログファイルはアップロードしても削除しない
+			uploader.SetActiveFile(logger.GetCurrentLogFile)
+			// 連続失敗のアラートはログに加えてWebhookにも通知（設定されている場合）
+			if cfg.Log.UploadAlertWebhookURL != "" {
+				uploader.SetFailureAlertHook(storage.NewUploadAlertWebhook(cfg.Log.UploadAlertWebhookURL, nil))
+			}
 			// 定期的なログアップロードを開始
-			uploader.StartPeriodicUpload(cfg.Log.Directory, cfg.Log.UploadInterval, cfg.Log.UploadMaxAge)
+			stopPeriodicUpload = uploader.StartPeriodicUpload(cfg.Log.Directory, cfg.Log.UploadInterval, cfg.Log.UploadMaxAge)
+		}
+	}
+
+	// 添付ファイル（有効な場合のみ。ファイルはログと同じS3接続先の別バケットに保存）
+	var attachmentHandler *handler.AttachmentHandler
+	if cfg.Attachment.Enabled {
+		if s3ClientErr != nil {
+			logger.Log.WithError(s3ClientErr).Fatal("添付ファイルのストレージの初期化に失敗")
 		}
+		attachmentStore := storage.NewAttachmentStore(s3Client.WithBucket(cfg.Attachment.Bucket))
+		attachmentUsecase := usecase.NewAttachmentUsecase(memoRepo, repository.NewAttachmentRepository(db, logger.Log), attachmentStore, &cfg.Attachment, logger.Log)
+		attachmentHandler = handler.NewAttachmentHandler(attachmentUsecase, logger.Log, cfg.Attachment.MaxSize)
 	}
 
+	// ゴミ箱とリサイクルログの定期削除を開始
+	stopTrashPurge := repository.StartTrashPurgeWithRecycleLog(memoRepo, cfg.Memo.TrashPurgeInterval, cfg.Memo.TrashRetention, cfg.Memo.DeletedRetention, logger.Log)
+
+	// 通知時刻を過ぎたリマインダーの定期通知を開始
+	reminderNotifier, err := notifier.New(cfg.Reminder.Notifier, cfg.Reminder.WebhookURL, logger.Log)
+	if err != nil {
+		logger.Log.WithError(err).Fatal("リマインダー通知の初期化に失敗")
+	}
+	stopReminders := repository.StartReminderScheduler(repository.NewReminderRepository(db, logger.Log), reminderNotifier, cfg.Reminder.PollInterval, logger.Log)
+
+	// レディネスチェック（DBは必須、ログアップロードは有効な場合に状態のみ報告）
+	readyHandler := handlers.NewReadyHandler(handlers.DefaultReadyTimeout)
+	readyHandler.AddCheck("database", db.PingContext)
+	readyHandler.AddStats("database", func() interface{} { return db.PoolStats() })
+	if cfg.Log.UploadEnabled {
+		readyHandler.AddOptionalCheck("log_uploader", func(ctx context.Context) error {
+			if uploader == nil {
+				return uploaderErr
+			}
+			return uploader.Health(ctx)
+		})
+	}
+
+	// 有効期限を過ぎた失効トークンの記録の定期削除を開始
+	stopRevokedTokenCleanup := authRepository.StartRevokedTokenCleanup(authRepository.NewRevokedTokenRepository(db.DB), cfg.Auth.RevokedTokenCleanupInterval, logger.Log)
+
 	// Ginルーターを初期化
 	r := gin.Default()
 
@@ -97,7 +200,7 @@ func main() {
 			"uri":       c.Request.RequestURI,
 			"client_ip": c.ClientIP(),
 		}).Warn("404: ルートが見つかりません")
-		c.JSON(http.StatusNotFound, gin.H{"error": "Route not found"})
+		problem.JSON(c, http.StatusNotFound, gin.H{"error": "Route not found"})
 	})
 
 	// NoMethodハンドラー（405）
@@ -107,26 +210,58 @@ func main() {
 			"uri":       c.Request.RequestURI,
 			"client_ip": c.ClientIP(),
 		}).Warn("405: サポートされていないメソッド")
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed"})
+		problem.JSON(c, http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed"})
 	})
 
-	// グローバルmiddlewareを適用
+	// グローバルmiddlewareを適用（リクエストIDは他のmiddlewareのログに含めるため最初に設定）
+	r.Use(middleware.RequestIDMiddleware())
+	// エラーレスポンスの形式（PROBLEM_JSON_ERRORS=true の場合は常にRFC 7807）
+	r.Use(problem.Middleware(cfg.Server.ProblemJSONErrors))
 	r.Use(middleware.LoggerMiddleware())
-	r.Use(middleware.CORSMiddleware())
-	r.Use(middleware.RateLimitMiddleware())
+	r.Use(middleware.MetricsMiddleware())
+	r.Use(middleware.CORSMiddlewareWithConfig(middleware.CORSConfig{
+		AllowedOrigins:   cfg.Server.AllowedOrigins,
+		AllowCredentials: cfg.Server.CORSAllowCredentials,
+	}))
+	r.Use(middleware.RateLimitMiddlewareWithConfig(middleware.RateLimitConfig{
+		RPS:   cfg.Server.RateLimitRPS,
+		Burst: cfg.Server.RateLimitBurst,
+	}))
+
+	// リクエスト/レスポンスボディサイズのメトリクス
+	sizeMetrics := middleware.NewSizeMetrics(nil)
+	sizeMetrics.SetAlertHook(int64(cfg.Server.SizeAlertBytes), middleware.LogSizeAlert)
+	r.Use(middleware.SizeMetricsMiddleware(sizeMetrics))
+	if err := metrics.Register(sizeMetrics); err != nil {
+		logger.Log.WithError(err).Error("ボディサイズのメトリクス登録に失敗")
+	}
+
+	// レスポンスのgzip圧縮（メトリクスには圧縮後のサイズを記録するため、その内側で適用）
+	gzipConfig := middleware.DefaultGzipConfig()
+	gzipConfig.MinSize = cfg.Server.GzipMinSize
+	r.Use(middleware.GzipMiddlewareWithConfig(gzipConfig))
+
+	// リクエストボディのサイズ制限（インポートはファイルを受け付けるため上限を広げる）
+	r.Use(middleware.MaxBodySizeMiddlewareWithOverrides(int64(cfg.Server.MaxRequestBytes), map[string]int64{
+		"/api/memos/import":                handler.MaxImportRequestBytes,
+		"/api/admin/memos/import-with-ids": handler.MaxImportRequestBytes,
+		"/api/memos/:id/attachments":       cfg.Attachment.MaxSize + handler.AttachmentRequestOverhead,
+	}))
+
+	// リクエスト処理のタイムアウト（エクスポートはストリーミングで時間がかかるため対象外）
+	r.Use(middleware.TimeoutMiddlewareWithOverrides(cfg.Server.RequestTimeout, map[string]time.Duration{
+		"/api/memos/export": 0,
+	}))
 
 	// 認証が不要なパブリックルート
+	rootHandler := handlers.NewRootHandler(build)
 	public := r.Group("/")
 	{
 		// Hello WorldのGETエンドポイント
-		public.GET("/", func(c *gin.Context) {
-			logger.WithField("endpoint", "/").Info("Hello Worldエンドポイントにアクセス")
-			c.JSON(http.StatusOK, gin.H{
-				"message": "Hello World",
-				"version": "2.0",
-				"service": "memo-app-api-server",
-			})
-		})
+		public.GET("/", rootHandler.Root)
+
+		// デプロイされているビルドの情報
+		public.GET("/version", rootHandler.Version)
 
 		// サポートされていないHTTPメソッドのハンドラー（405エラー）
 		public.POST("/", func(c *gin.Context) {
@@ -134,31 +269,31 @@ func main() {
 				"method": c.Request.Method,
 				"uri":    c.Request.RequestURI,
 			}).Warn("405: サポートされていないメソッド")
-			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed"})
+			problem.JSON(c, http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed"})
 		})
 		public.PUT("/", func(c *gin.Context) {
 			logger.WithFields(logrus.Fields{
 				"method": c.Request.Method,
 				"uri":    c.Request.RequestURI,
 			}).Warn("405: サポートされていないメソッド")
-			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed"})
+			problem.JSON(c, http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed"})
 		})
 		public.DELETE("/", func(c *gin.Context) {
 			logger.WithFields(logrus.Fields{
 				"method": c.Request.Method,
 				"uri":    c.Request.RequestURI,
 			}).Warn("405: サポートされていないメソッド")
-			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed"})
+			problem.JSON(c, http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed"})
 		})
 		public.PATCH("/", func(c *gin.Context) {
 			logger.WithFields(logrus.Fields{
 				"method": c.Request.Method,
 				"uri":    c.Request.RequestURI,
 			}).Warn("405: サポートされていないメソッド")
-			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed"})
+			problem.JSON(c, http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed"})
 		})
 
-		// ヘルスチェック用のエンドポイント
+		// ヘルスチェック用のエンドポイント（依存先を確認しないライブネスチェック）
 		public.GET("/health", func(c *gin.Context) {
 			logger.WithField("endpoint", "/health").Debug("ヘルスチェックエンドポイントにアクセス")
 			c.JSON(http.StatusOK, gin.H{
@@ -172,6 +307,14 @@ func main() {
 			c.Status(http.StatusOK)
 		})
 
+		// レディネスチェック用のエンドポイント（依存先に接続できない場合は503）
+		public.GET("/ready", readyHandler.Ready)
+
+		// メトリクス（Prometheusテキスト形式。METRICS_PORT が設定されている場合はそちらで公開）
+		if cfg.Server.MetricsPort == "" {
+			public.GET("/metrics", gin.WrapH(metrics.Handler()))
+		}
+
 		// 別のHello Worldエンドポイント（テキスト形式）
 		public.GET("/hello", func(c *gin.Context) {
 			logger.WithField("endpoint", "/hello").Info("Hello（テキスト）エンドポイントにアクセス")
@@ -197,64 +340,93 @@ func main() {
 
 	// メモAPIのルートを設定
 	routes.SetupRoutes(r, memoHandler)
-
-	// グレースフルシャットダウンの設定
-	go func() {
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-		<-sigChan
-
-		logger.Log.Info("シャットダウンシグナルを受信しました")
-
-		// 最後のログアップロードを実行
-		if uploader != nil {
-			logger.Log.Info("最後のログアップロードを実行中...")
-			if err := uploader.UploadOldLogs(cfg.Log.Directory, 0); err != nil {
-				logger.Log.WithError(err).Error("最後のログアップロードに失敗")
+	routes.SetupWebhookRoutes(r, webhookHandler)
+	if attachmentHandler != nil {
+		routes.SetupAttachmentRoutes(r, attachmentHandler)
+	}
+	// フィードリーダー向けのAtomフィード（APIキーをクエリパラメータで受け付ける）
+	userRepo := authRepository.NewUserRepository(db.DB)
+	feedAuth := middleware.APIKeyQueryAuthMiddleware(
+		userRepo,
+		service.NewAPIKeyService(authRepository.NewAPIKeyRepository(db.DB)),
+	)
+	routes.SetupFeedRoutes(r, memoHandler, feedAuth)
+	// ログの手動アップロード（アップローダーが無効な場合は503）
+	var logUploadRunner handlers.LogUploadRunner
+	if uploader != nil {
+		logUploadRunner = uploader
+	}
+	routes.SetupAdminRoutes(r, memoHandler, handlers.NewLogUploadHandler(logUploadRunner, cfg.Log.Directory), handlers.NewIPRegistrationHandler(userRepo), cfg.Auth.AdminToken)
+	routes.SetupSwaggerRoutes(r, cfg.Server.SwaggerEnabled)
+
+	// メトリクス専用のサーバー（METRICS_PORT が設定されている場合）
+	var metricsServer *http.Server
+	if cfg.Server.MetricsPort != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{Addr: ":" + cfg.Server.MetricsPort, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			logger.Log.WithField("port", cfg.Server.MetricsPort).Info("メトリクスサーバーを開始します")
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Log.WithError(err).Error("メトリクスサーバーの起動に失敗")
 			}
-		}
-
-		logger.CloseLogger()
-		os.Exit(0)
-	}()
+		}()
+	}
 
 	// サーバーを起動
 	serverAddr := ":" + cfg.Server.Port
-	logger.Log.WithField("port", cfg.Server.Port).Info("サーバーを開始します")
-
-	if err := r.Run(serverAddr); err != nil {
+	listener, err := net.Listen("tcp", serverAddr)
+	if err != nil {
 		logger.Log.WithError(err).Fatal("サーバーの起動に失敗")
 	}
-}
+	logger.Log.WithField("port", cfg.Server.Port).Info("サーバーを開始します")
 
-// isRunningInDocker は、アプリケーションがDockerコンテナ内で実行されているかどうかを判定します。
-func isRunningInDocker() bool {
-	// 環境変数でDocker環境を明示的にチェック
-	if os.Getenv("DOCKER_CONTAINER") == "true" {
-		return true
+	// グレースフルシャットダウン: シグナルを受信したら新しい接続の受付を止め、処理中のリクエストの完了を待つ
+	ctx, stopSignal := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignal()
+
+	httpServer := &http.Server{Handler: r, ReadHeaderTimeout: 10 * time.Second}
+	serveErr := server.Serve(ctx, httpServer, listener, cfg.Server.ShutdownTimeout, logger.Log)
+	if serveErr != nil {
+		logger.Log.WithError(serveErr).Error("サーバーの実行または停止に失敗")
 	}
 
-	// Linuxの場合、/proc/self/cgroupファイルでDockerを検出
-	if _, err := os.Stat("/proc/self/cgroup"); err == nil {
-		file, err := os.Open("/proc/self/cgroup")
-		if err != nil {
-			return false
+	if metricsServer != nil {
+		metricsCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		if err := metricsServer.Shutdown(metricsCtx); err != nil {
+			logger.Log.WithError(err).Warn("メトリクスサーバーの停止に失敗")
 		}
-		defer file.Close()
+		cancel()
+	}
 
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if strings.Contains(line, "docker") || strings.Contains(line, "containerd") {
-				return true
-			}
-		}
+	// DBを使うバックグラウンド処理を止める（Webhookはリクエストが発行したイベントを送り切ってから止める）
+	stopTrashPurge()
+	stopReminders()
+	stopRevokedTokenCleanup()
+	webhookDispatcher.Close()
+
+	// すべての利用者が止まってからDBを閉じる
+	if err := db.Close(); err != nil {
+		logger.Log.WithError(err).Warn("データベース接続のクローズに失敗")
 	}
 
-	// /.dockerenvファイルの存在チェック（Docker特有）
-	if _, err := os.Stat("/.dockerenv"); err == nil {
-		return true
+	// 最後のログアップロードを実行（ここまでのログを含めるため最後に行う）
+	if uploader != nil {
+		stopPeriodicUpload()
+		logger.Log.Info("最後のログアップロードを実行中...")
+		uploadCtx, cancel := context.WithTimeout(context.Background(), cfg.Log.ShutdownUploadTimeout)
+		summary, err := uploader.UploadOnShutdown(uploadCtx, cfg.Log.Directory, cfg.Log.ShutdownUploadConcurrency)
+		cancel()
+		if err != nil {
+			logger.Log.WithError(err).Error("最後のログアップロードに失敗")
+		} else if summary.Skipped > 0 {
+			logger.Log.WithField("skipped", summary.Skipped).Warn("期限内にアップロードできなかったログファイルがあります")
+		}
 	}
 
-	return false
+	logger.Log.Info("シャットダウンが完了しました")
+	if serveErr != nil {
+		logger.CloseLogger()
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,40 @@
+// Package markdown renders memo content written in Markdown to HTML that is safe to embed in a page
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Renderer converts Markdown to HTML and sanitizes the result
+type Renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+}
+
+// NewRenderer creates a renderer for GitHub flavored Markdown.
+// Raw HTML in the source is dropped by the converter, and the output is additionally passed through
+// a strict allowlist: no script, style, iframe or event handler attributes, and links and images only
+// with http, https or mailto URLs (javascript: and data: URLs are removed)
+func NewRenderer() *Renderer {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowURLSchemes("http", "https", "mailto")
+	policy.RequireNoReferrerOnLinks(true)
+
+	return &Renderer{
+		markdown: goldmark.New(goldmark.WithExtensions(extension.GFM)),
+		policy:   policy,
+	}
+}
+
+// Render returns the sanitized HTML rendering of the Markdown source
+func (r *Renderer) Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := r.markdown.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return r.policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"memo-app/src/logger"
+	"memo-app/src/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// adminTokenHeader 管理者用APIのトークンを渡すヘッダー
+const adminTokenHeader = "X-Admin-Token"
+
+// AdminTokenMiddleware X-Admin-Tokenヘッダーで管理者用APIを保護するmiddleware
+// tokenが空の場合は管理者用APIを無効とし、存在しないルートと同じく404を返す
+func AdminTokenMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			problem.JSON(c, http.StatusNotFound, gin.H{"error": "Route not found"})
+			c.Abort()
+			return
+		}
+
+		provided := c.GetHeader(adminTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			logger.WithFields(logrus.Fields{
+				"method":    c.Request.Method,
+				"uri":       c.Request.RequestURI,
+				"client_ip": c.ClientIP(),
+			}).Warn("管理者認証失敗: 無効な管理者トークン")
+			problem.JSON(c, http.StatusForbidden, gin.H{"error": "Admin token required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
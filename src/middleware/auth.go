@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"memo-app/src/logger"
+	"memo-app/src/models"
+	"memo-app/src/problem"
 	"memo-app/src/repository"
 	"memo-app/src/service"
 	"net/http"
@@ -11,8 +13,20 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// AuthMiddleware ユーザー認証用のmiddleware
+// apiKeyScheme APIキー認証で使用するAuthorizationヘッダーのスキーム
+const apiKeyScheme = "ApiKey "
+
+// APIKeyHeader APIキー認証で使用するヘッダー（Authorizationヘッダーより優先）
+const APIKeyHeader = "X-API-Key"
+
+// AuthMiddleware ユーザー認証用のmiddleware（Bearer JWTのみ）
 func AuthMiddleware(jwtService service.JWTService, userRepo repository.UserRepository) gin.HandlerFunc {
+	return AuthMiddlewareWithAPIKeys(jwtService, userRepo, nil)
+}
+
+// AuthMiddlewareWithAPIKeys Bearer JWTに加えて X-API-Key ヘッダーまたは「Authorization: ApiKey <key>」形式のAPIキー認証を受け付けるmiddleware
+// apiKeyServiceがnilの場合はAPIキー認証を無効にする
+func AuthMiddlewareWithAPIKeys(jwtService service.JWTService, userRepo repository.UserRepository, apiKeyService service.APIKeyService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		logger.WithFields(logrus.Fields{
 			"method":    c.Request.Method,
@@ -20,45 +34,155 @@ func AuthMiddleware(jwtService service.JWTService, userRepo repository.UserRepos
 			"client_ip": c.ClientIP(),
 		}).Info("認証ミドルウェア: リクエストを処理中")
 
-		// Authorizationヘッダーを取得
+		// Authorizationヘッダー・X-API-Keyヘッダーを取得
 		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		apiKey, usesAPIKey := "", false
+		if apiKeyService != nil {
+			if key := c.GetHeader(APIKeyHeader); key != "" {
+				apiKey, usesAPIKey = key, true
+			} else if strings.HasPrefix(authHeader, apiKeyScheme) {
+				apiKey, usesAPIKey = strings.TrimPrefix(authHeader, apiKeyScheme), true
+			}
+		}
+		if authHeader == "" && !usesAPIKey {
 			logger.WithField("client_ip", c.ClientIP()).Warn("認証失敗: Authorizationヘッダーがありません")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
 			c.Abort()
 			return
 		}
 
-		// Bearer tokenの形式をチェック
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			logger.WithField("client_ip", c.ClientIP()).Warn("認証失敗: Bearer tokenの形式が正しくありません")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		var userID int
+		tokenVersion := -1 // APIキー認証の場合はトークンバージョンを確認しない
+		if usesAPIKey {
+			// APIキー検証
+			if apiKey == "" {
+				logger.WithField("client_ip", c.ClientIP()).Warn("認証失敗: APIキーが空です")
+				problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "API key is empty"})
+				c.Abort()
+				return
+			}
+
+			id, err := apiKeyService.ValidateAPIKey(apiKey)
+			if err != nil {
+				logger.WithFields(logrus.Fields{
+					"client_ip": c.ClientIP(),
+					"error":     err.Error(),
+				}).Warn("認証失敗: 無効なAPIキー")
+				problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+				c.Abort()
+				return
+			}
+			userID = id
+			c.Set("auth_method", "api_key")
+		} else {
+			// Bearer tokenの形式をチェック
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				logger.WithField("client_ip", c.ClientIP()).Warn("認証失敗: Bearer tokenの形式が正しくありません")
+				problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+				c.Abort()
+				return
+			}
+
+			// tokenを抽出
+			token := strings.TrimPrefix(authHeader, "Bearer ")
+			if token == "" {
+				logger.WithField("client_ip", c.ClientIP()).Warn("認証失敗: tokenが空です")
+				problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "Token is empty"})
+				c.Abort()
+				return
+			}
+
+			// JWT token検証
+			claims, err := jwtService.ValidateToken(token)
+			if err != nil {
+				logger.WithFields(logrus.Fields{
+					"client_ip": c.ClientIP(),
+					"error":     err.Error(),
+				}).Warn("認証失敗: 無効なJWTトークン")
+				problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+				c.Abort()
+				return
+			}
+			userID = claims.UserID
+			tokenVersion = claims.TokenVersion
+			c.Set("auth_method", "jwt")
+		}
+
+		// ユーザー情報を取得
+		user, err := userRepo.GetByID(userID)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"client_ip": c.ClientIP(),
+				"user_id":   userID,
+				"error":     err.Error(),
+			}).Warn("認証失敗: ユーザーが見つかりません")
+			problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not found"})
 			c.Abort()
 			return
 		}
 
-		// tokenを抽出
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == "" {
-			logger.WithField("client_ip", c.ClientIP()).Warn("認証失敗: tokenが空です")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token is empty"})
+		// ユーザーがアクティブかチェック
+		if !user.IsActive {
+			logger.WithFields(logrus.Fields{
+				"client_ip": c.ClientIP(),
+				"user_id":   userID,
+			}).Warn("認証失敗: ユーザーアカウントが無効です")
+			problem.JSON(c, http.StatusForbidden, gin.H{"error": "Account is deactivated"})
+			c.Abort()
+			return
+		}
+
+		// 全端末からのログアウト以前に発行されたトークンは無効
+		if tokenVersion >= 0 && tokenVersion < user.TokenVersion {
+			logger.WithFields(logrus.Fields{
+				"client_ip": c.ClientIP(),
+				"user_id":   userID,
+			}).Warn("認証失敗: 失効したトークンです")
+			problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
+
+		// リクエストコンテキストにユーザー情報を設定
+		c.Set("user", user)
+		c.Set("user_id", userID)
+
+		// 認証成功
+		logger.WithFields(logrus.Fields{
+			"client_ip": c.ClientIP(),
+			"user_id":   userID,
+			"username":  user.Username,
+		}).Info("認証成功")
+		c.Next()
+	}
+}
+
+// APIKeyQueryParam フィードリーダー等ヘッダーを送れないクライアント向けにAPIキーを渡すクエリパラメータ
+const APIKeyQueryParam = "api_key"
+
+// APIKeyQueryAuthMiddleware クエリパラメータ（?api_key=）のAPIキーで認証するmiddleware
+// ヘッダーを設定できないフィードリーダー向けのため、フィード等の読み取り専用のルートにのみ使用する
+func APIKeyQueryAuthMiddleware(userRepo repository.UserRepository, apiKeyService service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.Query(APIKeyQueryParam)
+		if apiKey == "" {
+			logger.WithField("client_ip", c.ClientIP()).Warn("認証失敗: APIキーのクエリパラメータがありません")
+			problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "api_key query parameter required"})
 			c.Abort()
 			return
 		}
 
-		// JWT token検証
-		userID, err := jwtService.ValidateAccessToken(token)
+		userID, err := apiKeyService.ValidateAPIKey(apiKey)
 		if err != nil {
 			logger.WithFields(logrus.Fields{
 				"client_ip": c.ClientIP(),
 				"error":     err.Error(),
-			}).Warn("認証失敗: 無効なJWTトークン")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			}).Warn("認証失敗: 無効なAPIキー")
+			problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
 			c.Abort()
 			return
 		}
 
-		// ユーザー情報を取得
 		user, err := userRepo.GetByID(userID)
 		if err != nil {
 			logger.WithFields(logrus.Fields{
@@ -66,32 +190,81 @@ func AuthMiddleware(jwtService service.JWTService, userRepo repository.UserRepos
 				"user_id":   userID,
 				"error":     err.Error(),
 			}).Warn("認証失敗: ユーザーが見つかりません")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not found"})
 			c.Abort()
 			return
 		}
-
-		// ユーザーがアクティブかチェック
 		if !user.IsActive {
 			logger.WithFields(logrus.Fields{
 				"client_ip": c.ClientIP(),
 				"user_id":   userID,
 			}).Warn("認証失敗: ユーザーアカウントが無効です")
-			c.JSON(http.StatusForbidden, gin.H{"error": "Account is deactivated"})
+			problem.JSON(c, http.StatusForbidden, gin.H{"error": "Account is deactivated"})
 			c.Abort()
 			return
 		}
 
-		// リクエストコンテキストにユーザー情報を設定
 		c.Set("user", user)
 		c.Set("user_id", userID)
+		c.Set("auth_method", "api_key")
+		c.Next()
+	}
+}
+
+// RequireEmailVerified メールアドレス確認済みのユーザーのみ許可するmiddleware
+// AuthMiddlewareの後に使用する。enabledがfalseの場合は何もしない
+func RequireEmailVerified(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		userInterface, exists := c.Get("user")
+		user, ok := userInterface.(*models.User)
+		if !exists || !ok {
+			problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		if !user.EmailVerified {
+			logger.WithField("user_id", user.ID).Warn("メールアドレス未確認のユーザーによる操作を拒否しました")
+			problem.JSON(c, http.StatusForbidden, gin.H{"error": "Email verification required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole role を持つユーザーのみ許可するmiddleware（管理者用APIなど）
+// AuthMiddlewareの後に使用する。トークンのロールではなく、AuthMiddlewareがリクエストごとにDBから取得したユーザーのロールで判定するため、
+// 降格したユーザーは発行済みのトークンでも即座にアクセスできなくなる
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userInterface, exists := c.Get("user")
+		user, ok := userInterface.(*models.User)
+		if !exists || !ok {
+			problem.JSON(c, http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		if !user.HasRole(role) {
+			logger.WithFields(logrus.Fields{
+				"client_ip":     c.ClientIP(),
+				"user_id":       user.ID,
+				"role":          user.Role,
+				"required_role": role,
+				"uri":           c.Request.RequestURI,
+			}).Warn("権限のないユーザーによる操作を拒否しました")
+			problem.JSON(c, http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
 
-		// 認証成功
-		logger.WithFields(logrus.Fields{
-			"client_ip": c.ClientIP(),
-			"user_id":   userID,
-			"username":  user.Username,
-		}).Info("認証成功")
 		c.Next()
 	}
 }
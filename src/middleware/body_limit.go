@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"memo-app/src/logger"
+	"memo-app/src/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultMaxRequestBytes リクエストボディの最大サイズのデフォルト値（1MB）
+const DefaultMaxRequestBytes int64 = 1 << 20
+
+// MaxBodySizeMiddleware リクエストボディを limit バイトに制限するmiddleware
+func MaxBodySizeMiddleware(limit int64) gin.HandlerFunc {
+	return MaxBodySizeMiddlewareWithOverrides(limit, nil)
+}
+
+// MaxBodySizeMiddlewareWithOverrides ルート（c.FullPath()）ごとに上限を変えられるボディサイズ制限middleware
+// Content-Length が上限を超える場合はハンドラーを呼ばずに413を返し、
+// Content-Length のない（chunked）リクエストは読み込み時に *http.MaxBytesError になる（ハンドラーで413に変換する）
+func MaxBodySizeMiddlewareWithOverrides(limit int64, overrides map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		routeLimit := limit
+		if override, ok := overrides[c.FullPath()]; ok {
+			routeLimit = override
+		}
+		if routeLimit <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > routeLimit {
+			logger.WithRequestID(c).WithFields(logrus.Fields{
+				"method":         c.Request.Method,
+				"uri":            c.Request.RequestURI,
+				"content_length": c.Request.ContentLength,
+				"limit":          routeLimit,
+			}).Warn("リクエストボディが上限を超えています")
+
+			problem.AbortWithJSON(c, http.StatusRequestEntityTooLarge, gin.H{
+				"error":   "Request Entity Too Large",
+				"message": fmt.Sprintf("request body must not exceed %d bytes", routeLimit),
+			})
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, routeLimit)
+		c.Next()
+	}
+}
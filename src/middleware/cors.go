@@ -1,14 +1,33 @@
 package middleware
 
 import (
+	"strings"
+
 	"memo-app/src/logger"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
-// CORSMiddleware CORS設定用のmiddleware
+// CORSConfig CORS設定
+type CORSConfig struct {
+	AllowedOrigins   []string // 許可するオリジン（空の場合はすべてのオリジンに * を返す）
+	AllowCredentials bool     // 許可したオリジンに Access-Control-Allow-Credentials: true を返すか（* の場合は返さない）
+}
+
+// CORSMiddleware すべてのオリジンを許可するCORS用のmiddleware
 func CORSMiddleware() gin.HandlerFunc {
+	return CORSMiddlewareWithConfig(CORSConfig{})
+}
+
+// CORSMiddlewareWithConfig 許可リストを指定したCORS用のmiddleware
+// 許可リストがある場合はリクエストのOriginが含まれるときだけそのOriginを返す
+func CORSMiddlewareWithConfig(cfg CORSConfig) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		allowed[normalizeOrigin(origin)] = true
+	}
+
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 
@@ -18,11 +37,27 @@ func CORSMiddleware() gin.HandlerFunc {
 			"uri":    c.Request.RequestURI,
 		}).Debug("CORS middleware processing")
 
-		// TODO: 将来的にここで適切なCORS設定を実装
-		// セキュリティのため、本番環境では適切なオリジンを設定すること
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		if len(allowed) == 0 {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			// 応答がOriginによって変わるため、キャッシュにも区別させる
+			c.Header("Vary", "Origin")
+			if origin != "" && allowed[normalizeOrigin(origin)] {
+				c.Header("Access-Control-Allow-Origin", origin)
+				if cfg.AllowCredentials {
+					c.Header("Access-Control-Allow-Credentials", "true")
+				}
+			} else if origin != "" {
+				logger.WithFields(logrus.Fields{
+					"origin": origin,
+					"uri":    c.Request.RequestURI,
+				}).Debug("許可されていないオリジンからのリクエスト")
+			}
+		}
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-API-Key, If-Match, If-None-Match, X-Request-ID")
+		// ブラウザのスクリプトから読めるのは既定ではCORSセーフリストのヘッダーだけのため、APIが返すヘッダーを公開する
+		c.Header("Access-Control-Expose-Headers", "ETag, X-Request-ID, Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining")
 		c.Header("Access-Control-Max-Age", "86400") // 24時間
 
 		if c.Request.Method == "OPTIONS" {
@@ -38,3 +73,8 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// normalizeOrigin 比較用にオリジンの末尾の / を除き小文字にする
+func normalizeOrigin(origin string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+}
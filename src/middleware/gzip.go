@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GzipConfig レスポンス圧縮の設定
+type GzipConfig struct {
+	MinSize      int      // このサイズ（バイト）以上のレスポンスのみ圧縮
+	Level        int      // 圧縮レベル（gzip.DefaultCompression 等）
+	ExcludePaths []string // 圧縮しないパス
+}
+
+// DefaultGzipConfig レスポンス圧縮設定のデフォルト値を返す
+func DefaultGzipConfig() GzipConfig {
+	return GzipConfig{
+		MinSize:      1024,
+		Level:        gzip.DefaultCompression,
+		ExcludePaths: []string{"/health"},
+	}
+}
+
+// compressedContentTypes 既に圧縮されているため再圧縮しないContent-Typeの接頭辞
+var compressedContentTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"font/woff",
+}
+
+// GzipMiddleware デフォルト設定のレスポンス圧縮middleware
+func GzipMiddleware() gin.HandlerFunc {
+	return GzipMiddlewareWithConfig(DefaultGzipConfig())
+}
+
+// GzipMiddlewareWithConfig クライアントが gzip を受け付ける場合、MinSize 以上のレスポンスを圧縮するmiddleware
+// MinSize に達するまでは本文をバッファし、小さいレスポンスはそのまま返す
+func GzipMiddlewareWithConfig(cfg GzipConfig) gin.HandlerFunc {
+	if cfg.MinSize < 0 {
+		cfg.MinSize = 0
+	}
+	if cfg.Level == 0 {
+		cfg.Level = gzip.DefaultCompression
+	}
+	excluded := make(map[string]bool, len(cfg.ExcludePaths))
+	for _, path := range cfg.ExcludePaths {
+		excluded[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || excluded[c.Request.URL.Path] || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: c.Writer, config: cfg}
+		c.Writer = writer
+		defer writer.finish()
+
+		c.Next()
+	}
+}
+
+// acceptsGzip Accept-Encoding が gzip（または *）を q=0 以外で含むか判定
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		q := strings.ReplaceAll(strings.TrimSpace(params), " ", "")
+		if q == "q=0" || q == "q=0.0" || q == "q=0.00" || q == "q=0.000" {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter 本文が MinSize に達した時点で圧縮するかを決めるResponseWriter
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	config GzipConfig
+
+	buf      bytes.Buffer
+	decided  bool
+	compress bool
+	gz       *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.compress {
+			return w.gz.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf.Write(data)
+	if w.buf.Len() < w.config.MinSize {
+		return len(data), nil
+	}
+	if err := w.decide(); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush ストリーミングレスポンス（エクスポート等）のために圧縮済みの分を送信する
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+	if w.compress {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide バッファした本文とヘッダーから圧縮するかを決め、バッファを書き出す
+func (w *gzipResponseWriter) decide() error {
+	w.decided = true
+	w.compress = w.buf.Len() >= w.config.MinSize && w.compressible()
+
+	if w.compress {
+		header := w.Header()
+		header.Set("Content-Encoding", "gzip")
+		header.Add("Vary", "Accept-Encoding")
+		header.Del("Content-Length")
+
+		gz, err := gzip.NewWriterLevel(w.ResponseWriter, w.config.Level)
+		if err != nil {
+			w.compress = false
+		} else {
+			w.gz = gz
+		}
+	}
+
+	data := w.buf.Bytes()
+	w.buf = bytes.Buffer{}
+	if len(data) == 0 {
+		return nil
+	}
+	if w.compress {
+		_, err := w.gz.Write(data)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(data)
+	return err
+}
+
+// compressible ヘッダー送信前で、エンコード済み・圧縮済みのContent-Typeでないか判定
+func (w *gzipResponseWriter) compressible() bool {
+	if w.ResponseWriter.Written() || w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+	switch w.Status() {
+	case http.StatusNoContent, http.StatusNotModified:
+		return false
+	}
+
+	contentType := strings.ToLower(w.Header().Get("Content-Type"))
+	for _, prefix := range compressedContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// finish 小さいレスポンスはそのまま書き出し、圧縮した場合はgzipストリームを閉じる
+func (w *gzipResponseWriter) finish() {
+	if !w.decided {
+		if w.buf.Len() == 0 {
+			return
+		}
+		_ = w.decide()
+	}
+	if w.compress {
+		_ = w.gz.Close()
+	}
+}
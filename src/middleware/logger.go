@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"net/url"
 	"time"
 
 	"memo-app/src/logger"
@@ -15,10 +16,10 @@ func LoggerMiddleware() gin.HandlerFunc {
 		// リクエスト開始時刻を記録
 		start := time.Now()
 
-		// リクエスト情報をログに記録
-		logger.WithFields(logrus.Fields{
+		// リクエスト情報をログに記録（RequestIDMiddleware適用時はリクエストIDを含める）
+		logger.WithRequestID(c).WithFields(logrus.Fields{
 			"method":     c.Request.Method,
-			"uri":        c.Request.RequestURI,
+			"uri":        redactedURI(c),
 			"client_ip":  c.ClientIP(),
 			"user_agent": c.Request.UserAgent(),
 			"referer":    c.Request.Referer(),
@@ -31,9 +32,9 @@ func LoggerMiddleware() gin.HandlerFunc {
 		latency := time.Since(start)
 		statusCode := c.Writer.Status()
 
-		logEntry := logger.WithFields(logrus.Fields{
+		logEntry := logger.WithRequestID(c).WithFields(logrus.Fields{
 			"method":        c.Request.Method,
-			"uri":           c.Request.RequestURI,
+			"uri":           redactedURI(c),
 			"client_ip":     c.ClientIP(),
 			"status_code":   statusCode,
 			"latency_ms":    latency.Milliseconds(),
@@ -55,11 +56,22 @@ func LoggerMiddleware() gin.HandlerFunc {
 
 		// エラーがある場合は追加でログ出力
 		if len(c.Errors) > 0 {
-			logger.WithFields(logrus.Fields{
+			logger.WithRequestID(c).WithFields(logrus.Fields{
 				"method": c.Request.Method,
-				"uri":    c.Request.RequestURI,
+				"uri":    redactedURI(c),
 				"errors": c.Errors.String(),
 			}).Error("リクエスト処理中にエラーが発生")
 		}
 	}
 }
+
+// redactedURI クエリパラメータのAPIキーを伏せたリクエストURI（APIキーをログに残さない）
+func redactedURI(c *gin.Context) string {
+	query := c.Request.URL.Query()
+	if query.Get(APIKeyQueryParam) == "" {
+		return c.Request.RequestURI
+	}
+	query.Set(APIKeyQueryParam, "REDACTED")
+	redacted := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+	return redacted.RequestURI()
+}
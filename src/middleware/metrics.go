@@ -0,0 +1,266 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"memo-app/src/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// メトリクス名
+const (
+	RequestSizeMetric  = "http_request_size_bytes"
+	ResponseSizeMetric = "http_response_size_bytes"
+)
+
+// DefaultSizeBuckets ボディサイズ用ヒストグラムのデフォルトのバケット境界（バイト）
+var DefaultSizeBuckets = []float64{256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304}
+
+// unmatchedRoute ルートに一致しなかったリクエストのラベル
+const unmatchedRoute = "unmatched"
+
+// HistogramSnapshot ヒストグラムのある時点の値
+// Counts[i] は Buckets[i] 以下の観測数（累積）
+type HistogramSnapshot struct {
+	Buckets []float64
+	Counts  []uint64
+	Count   uint64
+	Sum     float64
+}
+
+// histogram 固定バケットの累積ヒストグラム
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func (h *histogram) observe(value float64) {
+	for i, bound := range h.buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+func (h *histogram) snapshot() HistogramSnapshot {
+	return HistogramSnapshot{
+		Buckets: append([]float64(nil), h.buckets...),
+		Counts:  append([]uint64(nil), h.counts...),
+		Count:   h.count,
+		Sum:     h.sum,
+	}
+}
+
+// SizeAlert ボディサイズが閾値を超えたときに通知される内容
+type SizeAlert struct {
+	Metric string // RequestSizeMetric または ResponseSizeMetric
+	Route  string
+	Method string
+	Size   int64
+}
+
+// SizeAlertHook ボディサイズが閾値を超えたときに呼び出される関数
+type SizeAlertHook func(alert SizeAlert)
+
+// SizeMetrics ルートごとのリクエスト/レスポンスボディサイズのヒストグラム
+type SizeMetrics struct {
+	mu             sync.Mutex
+	buckets        []float64
+	histograms     map[string]map[string]*histogram // メトリクス名 -> ルート -> ヒストグラム
+	alertThreshold int64
+	alertHook      SizeAlertHook
+}
+
+// NewSizeMetrics バケット境界を指定してSizeMetricsを作成（nilの場合はDefaultSizeBuckets）
+func NewSizeMetrics(buckets []float64) *SizeMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultSizeBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &SizeMetrics{
+		buckets: sorted,
+		histograms: map[string]map[string]*histogram{
+			RequestSizeMetric:  {},
+			ResponseSizeMetric: {},
+		},
+	}
+}
+
+// SetAlertHook ボディサイズが threshold バイトを超えたときに hook を呼び出す（threshold が0以下の場合は無効）
+func (m *SizeMetrics) SetAlertHook(threshold int64, hook SizeAlertHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alertThreshold = threshold
+	m.alertHook = hook
+}
+
+// Observe 指定したメトリクス・ルートにサイズを記録
+func (m *SizeMetrics) Observe(metric, route string, size int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	routes, ok := m.histograms[metric]
+	if !ok {
+		routes = map[string]*histogram{}
+		m.histograms[metric] = routes
+	}
+	h, ok := routes[route]
+	if !ok {
+		h = &histogram{buckets: m.buckets, counts: make([]uint64, len(m.buckets))}
+		routes[route] = h
+	}
+	h.observe(float64(size))
+}
+
+// Snapshot 指定したメトリクス・ルートのヒストグラムを取得
+func (m *SizeMetrics) Snapshot(metric, route string) (HistogramSnapshot, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.histograms[metric][route]
+	if !ok {
+		return HistogramSnapshot{}, false
+	}
+	return h.snapshot(), true
+}
+
+// WriteText Prometheusのテキスト形式でヒストグラムを書き出す
+func (m *SizeMetrics) WriteText(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metrics := make([]string, 0, len(m.histograms))
+	for metric := range m.histograms {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(w, "# TYPE %s histogram\n", metric); err != nil {
+			return err
+		}
+
+		routes := make([]string, 0, len(m.histograms[metric]))
+		for route := range m.histograms[metric] {
+			routes = append(routes, route)
+		}
+		sort.Strings(routes)
+
+		for _, route := range routes {
+			h := m.histograms[metric][route]
+			for i, bound := range h.buckets {
+				if _, err := fmt.Fprintf(w, "%s_bucket{route=%q,le=\"%g\"} %d\n", metric, route, bound, h.counts[i]); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "%s_bucket{route=%q,le=\"+Inf\"} %d\n%s_sum{route=%q} %g\n%s_count{route=%q} %d\n",
+				metric, route, h.count, metric, route, h.sum, metric, route, h.count); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Describe prometheus.Collector の実装（ルートは観測時に増えるため記述子を固定しない）
+func (m *SizeMetrics) Describe(ch chan<- *prometheus.Desc) {}
+
+// Collect prometheus.Collector の実装。WriteText と同じヒストグラムをレジストリに渡す
+func (m *SizeMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for metric, routes := range m.histograms {
+		desc := prometheus.NewDesc(metric, "ルートごとのボディサイズ（バイト）", []string{"route"}, nil)
+		for route, h := range routes {
+			buckets := make(map[float64]uint64, len(h.buckets))
+			for i, bound := range h.buckets {
+				buckets[bound] = h.counts[i]
+			}
+			ch <- prometheus.MustNewConstHistogram(desc, h.count, h.sum, buckets, route)
+		}
+	}
+}
+
+// alert 閾値を超えていればフックを呼び出す
+func (m *SizeMetrics) alert(alert SizeAlert) {
+	m.mu.Lock()
+	threshold, hook := m.alertThreshold, m.alertHook
+	m.mu.Unlock()
+
+	if hook != nil && threshold > 0 && alert.Size > threshold {
+		hook(alert)
+	}
+}
+
+// countingReader 読み込んだバイト数を数えるio.ReadCloser
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// SizeMetricsMiddleware リクエスト/レスポンスボディのサイズをルートごとのヒストグラムに記録するmiddleware
+func SizeMetricsMiddleware(m *SizeMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Content-Length が不明な場合（chunked等）は実際に読み込んだバイト数を使用
+		var body *countingReader
+		if c.Request.Body != nil && c.Request.ContentLength < 0 {
+			body = &countingReader{ReadCloser: c.Request.Body}
+			c.Request.Body = body
+		}
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		requestSize := c.Request.ContentLength
+		if body != nil {
+			requestSize = body.n
+		}
+		if requestSize < 0 {
+			requestSize = 0
+		}
+
+		responseSize := int64(c.Writer.Size())
+		if responseSize < 0 {
+			responseSize = 0
+		}
+
+		m.Observe(RequestSizeMetric, route, requestSize)
+		m.Observe(ResponseSizeMetric, route, responseSize)
+
+		m.alert(SizeAlert{Metric: RequestSizeMetric, Route: route, Method: c.Request.Method, Size: requestSize})
+		m.alert(SizeAlert{Metric: ResponseSizeMetric, Route: route, Method: c.Request.Method, Size: responseSize})
+	}
+}
+
+// LogSizeAlert 閾値を超えたボディサイズを警告ログに出力するSizeAlertHook
+func LogSizeAlert(alert SizeAlert) {
+	logger.WithFields(logrus.Fields{
+		"metric": alert.Metric,
+		"route":  alert.Route,
+		"method": alert.Method,
+		"size":   alert.Size,
+	}).Warn("ボディサイズが警告閾値を超えました")
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheusのメトリクス名
+const (
+	RequestsTotalMetric    = "http_requests_total"
+	RequestDurationMetric  = "http_request_duration_seconds"
+	RequestsInFlightMetric = "http_requests_in_flight"
+	MemosCreatedMetric     = "memos_created_total"
+)
+
+// HTTPMetrics ルート・ステータスコードごとのリクエスト数、処理時間、処理中のリクエスト数を記録するPrometheusメトリクス
+type HTTPMetrics struct {
+	registry     *prometheus.Registry
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	inFlight     *prometheus.GaugeVec
+	memosCreated *prometheus.CounterVec
+}
+
+// DefaultHTTPMetrics MetricsMiddleware が記録するメトリクス
+var DefaultHTTPMetrics = NewHTTPMetrics()
+
+// NewHTTPMetrics 専用のレジストリを持つHTTPMetricsを作成（Goランタイムとプロセスのメトリクスも登録する）
+func NewHTTPMetrics() *HTTPMetrics {
+	m := &HTTPMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: RequestsTotalMetric,
+			Help: "処理したHTTPリクエスト数",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    RequestDurationMetric,
+			Help:    "HTTPリクエストの処理時間（秒）",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: RequestsInFlightMetric,
+			Help: "処理中のHTTPリクエスト数",
+		}, []string{"method", "route"}),
+		memosCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MemosCreatedMetric,
+			Help: "作成されたメモの数",
+		}, []string{"source"}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		m.inFlight,
+		m.memosCreated,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Registry メトリクスを登録しているレジストリ
+func (m *HTTPMetrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Register 追加のコレクター（SizeMetrics等）を登録
+func (m *HTTPMetrics) Register(collector prometheus.Collector) error {
+	return m.registry.Register(collector)
+}
+
+// RegisterDBStats コネクションプールの統計をゲージとして登録（dbName はラベルに使用）
+func (m *HTTPMetrics) RegisterDBStats(db *sql.DB, dbName string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// ObserveMemosCreated 作成されたメモの数を作成元（api, import等）ごとに記録
+func (m *HTTPMetrics) ObserveMemosCreated(source string, count int) {
+	if count <= 0 {
+		return
+	}
+	m.memosCreated.WithLabelValues(source).Add(float64(count))
+}
+
+// Handler レジストリの内容をPrometheusのテキスト形式で返すhttp.Handler
+func (m *HTTPMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// MetricsMiddleware DefaultHTTPMetrics にリクエストを記録するmiddleware
+func MetricsMiddleware() gin.HandlerFunc {
+	return HTTPMetricsMiddleware(DefaultHTTPMetrics)
+}
+
+// HTTPMetricsMiddleware 指定したHTTPMetricsにリクエストを記録するmiddleware
+func HTTPMetricsMiddleware(m *HTTPMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// ルートはラベルの数が増えすぎないよう実際のパスではなくパターンを使用
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+
+		inFlight := m.inFlight.WithLabelValues(method, route)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		c.Next()
+
+		status := strconv.Itoa(c.Writer.Status())
+		m.requests.WithLabelValues(method, route, status).Inc()
+		m.duration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
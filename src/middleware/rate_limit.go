@@ -1,37 +1,174 @@
 package middleware
 
 import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
 	"memo-app/src/logger"
+	"memo-app/src/problem"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 )
 
-// RateLimitMiddleware レート制限用のmiddleware
+// RateLimitConfig レート制限の設定（クライアントごとのトークンバケット）
+type RateLimitConfig struct {
+	RPS             float64       // 1秒あたりに補充されるリクエスト数
+	Burst           int           // 連続して受け付けるリクエスト数の上限（バケットの容量）
+	IdleTTL         time.Duration // この期間リクエストのないクライアントのリミッターを破棄
+	CleanupInterval time.Duration // 古いリミッターを掃除する間隔
+}
+
+// DefaultRateLimitConfig レート制限設定のデフォルト値を返す
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		RPS:             10,
+		Burst:           20,
+		IdleTTL:         10 * time.Minute,
+		CleanupInterval: 1 * time.Minute,
+	}
+}
+
+// rateLimitClient クライアント1件分のリミッター
+type rateLimitClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter クライアントIP（認証済みの場合はユーザーID）ごとのトークンバケット
+type RateLimiter struct {
+	config RateLimitConfig
+
+	mu          sync.Mutex
+	clients     map[string]*rateLimitClient
+	lastCleanup time.Time
+}
+
+// NewRateLimiter 設定を指定してRateLimiterを作成（0以下の値はデフォルト値を使用）
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	defaults := DefaultRateLimitConfig()
+	if cfg.RPS <= 0 {
+		cfg.RPS = defaults.RPS
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = defaults.Burst
+	}
+	if cfg.IdleTTL <= 0 {
+		cfg.IdleTTL = defaults.IdleTTL
+	}
+	if cfg.CleanupInterval <= 0 {
+		cfg.CleanupInterval = defaults.CleanupInterval
+	}
+
+	return &RateLimiter{
+		config:      cfg,
+		clients:     make(map[string]*rateLimitClient),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Allow キーのトークンを1つ消費する
+// 残りのトークン数と、拒否した場合は次のトークンが補充されるまでの時間を返す
+func (l *RateLimiter) Allow(key string) (allowed bool, remaining int, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.cleanupLocked(now)
+
+	client, ok := l.clients[key]
+	if !ok {
+		client = &rateLimitClient{limiter: rate.NewLimiter(rate.Limit(l.config.RPS), l.config.Burst)}
+		l.clients[key] = client
+	}
+	client.lastSeen = now
+
+	if client.limiter.AllowN(now, 1) {
+		return true, int(math.Max(0, math.Floor(client.limiter.TokensAt(now)))), 0
+	}
+
+	// 予約して待ち時間を求め、すぐに取り消す（トークンは消費しない）
+	reservation := client.limiter.ReserveN(now, 1)
+	retryAfter = reservation.DelayFrom(now)
+	reservation.CancelAt(now)
+	return false, 0, retryAfter
+}
+
+// Len 保持しているクライアント数を返す
+func (l *RateLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.clients)
+}
+
+// cleanupLocked CleanupInterval ごとに IdleTTL を超えて使われていないリミッターを破棄（l.mu を保持して呼ぶ）
+func (l *RateLimiter) cleanupLocked(now time.Time) {
+	if now.Sub(l.lastCleanup) < l.config.CleanupInterval {
+		return
+	}
+	l.lastCleanup = now
+
+	for key, client := range l.clients {
+		if now.Sub(client.lastSeen) > l.config.IdleTTL {
+			delete(l.clients, key)
+		}
+	}
+}
+
+// rateLimitKey 認証済みの場合はユーザーID、それ以外はクライアントIPでバケットを分ける
+func rateLimitKey(c *gin.Context) string {
+	if userID, ok := c.Get("user_id"); ok {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitMiddleware デフォルト設定のレート制限middleware
 func RateLimitMiddleware() gin.HandlerFunc {
+	return RateLimitMiddlewareWithConfig(DefaultRateLimitConfig())
+}
+
+// RateLimitMiddlewareWithConfig 設定を指定したレート制限middleware
+// 認証middlewareより後に適用した場合はユーザーごとに制限する
+func RateLimitMiddlewareWithConfig(cfg RateLimitConfig) gin.HandlerFunc {
+	return RateLimitMiddlewareWithLimiter(NewRateLimiter(cfg))
+}
+
+// RateLimitMiddlewareWithLimiter 既存のRateLimiterを使うレート制限middleware
+// 上限を超えた場合は429とRetry-Afterヘッダーを返す
+func RateLimitMiddlewareWithLimiter(limiter *RateLimiter) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// TODO: 将来的にここでレート制限機能を実装
-		// 例：Redis やメモリベースのレート制限
-
-		clientIP := c.ClientIP()
-
-		logger.WithFields(logrus.Fields{
-			"client_ip": clientIP,
-			"method":    c.Request.Method,
-			"uri":       c.Request.RequestURI,
-		}).Debug("レート制限チェック中")
-
-		// 実際のレート制限ロジックをここに実装予定
-		// 例：
-		// if isRateLimited(clientIP) {
-		//     logger.WithField("client_ip", clientIP).Warn("レート制限に達しました")
-		//     c.JSON(http.StatusTooManyRequests, gin.H{
-		//         "error": "Too Many Requests",
-		//         "retry_after": 60,
-		//     })
-		//     c.Abort()
-		//     return
-		// }
+		key := rateLimitKey(c)
+		allowed, remaining, retryAfter := limiter.Allow(key)
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.config.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		if !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+
+			logger.WithFields(logrus.Fields{
+				"key":         key,
+				"method":      c.Request.Method,
+				"uri":         c.Request.RequestURI,
+				"retry_after": seconds,
+			}).Warn("レート制限に達しました")
+
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			problem.AbortWithJSON(c, http.StatusTooManyRequests, gin.H{
+				"error":       "Too Many Requests",
+				"retry_after": seconds,
+			})
+			return
+		}
 
 		c.Next()
 	}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"memo-app/src/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader リクエストIDを受け渡すヘッダー
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength 受け入れるリクエストIDの最大長（超える場合は新しく生成）
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware リクエストごとの相関IDを設定するmiddleware
+// X-Request-ID ヘッダーがあればそれを使い、なければUUIDを生成してgin contextとレスポンスヘッダーに設定する
+// 他のmiddlewareのログにも含めるため、最初に適用すること
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(logger.RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// isValidRequestID ログに出力しても安全な長さと文字（表示可能なASCII）か判定
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < 0x21 || requestID[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"memo-app/src/logger"
+	"memo-app/src/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRequestTimeout リクエスト処理のタイムアウトのデフォルト値
+const DefaultRequestTimeout = 30 * time.Second
+
+// TimeoutMiddleware リクエストのcontextに期限 d を設定するmiddleware
+func TimeoutMiddleware(d time.Duration) gin.HandlerFunc {
+	return TimeoutMiddlewareWithOverrides(d, nil)
+}
+
+// TimeoutMiddlewareWithOverrides ルート（c.FullPath()）ごとにタイムアウトを変えられるmiddleware（0以下は無制限）
+// 期限を設定したcontextで c.Request を置き換えるため、リポジトリの QueryContext/ExecContext も期限で中断される。
+// 期限までにレスポンスを書き始めなかった場合は、ハンドラーの応答（中断によるエラー等）を捨てて503を返す
+func TimeoutMiddlewareWithOverrides(d time.Duration, overrides map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := d
+		if override, ok := overrides[c.FullPath()]; ok {
+			timeout = override
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		original := c.Writer
+		writer := &timeoutResponseWriter{ResponseWriter: original, ctx: ctx}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = original
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) || (original.Written() && !writer.timedOut) {
+			return
+		}
+
+		logger.WithRequestID(c).WithFields(logrus.Fields{
+			"method":  c.Request.Method,
+			"uri":     c.Request.RequestURI,
+			"timeout": timeout.String(),
+		}).Warn("リクエストがタイムアウトしました")
+
+		if !original.Written() {
+			problem.AbortWithJSON(c, http.StatusServiceUnavailable, gin.H{
+				"error":   "Service Unavailable",
+				"message": fmt.Sprintf("request did not complete within %s", timeout),
+			})
+		}
+	}
+}
+
+// timeoutResponseWriter 期限を過ぎてから書き始めたレスポンスを捨てるResponseWriter
+// 書き始めた後（ストリーミング中）に期限を過ぎた場合はそのまま書き続ける
+type timeoutResponseWriter struct {
+	gin.ResponseWriter
+	ctx      context.Context
+	timedOut bool
+}
+
+// expired レスポンスを書き始める前に期限を過ぎたか判定し、以降の書き込みを捨てるよう記録する
+func (w *timeoutResponseWriter) expired() bool {
+	if !w.timedOut && !w.ResponseWriter.Written() && w.ctx.Err() != nil {
+		w.timedOut = true
+	}
+	return w.timedOut
+}
+
+func (w *timeoutResponseWriter) WriteHeader(code int) {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutResponseWriter) WriteHeaderNow() {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timeoutResponseWriter) Write(data []byte) (int, error) {
+	if w.expired() {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutResponseWriter) WriteString(s string) (int, error) {
+	if w.expired() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timeoutResponseWriter) Flush() {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.Flush()
+}
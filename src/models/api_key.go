@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// APIKey マシンクライアント用のAPIキー（平文のキーは保持しない）
+type APIKey struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	KeyPrefix  string     `json:"key_prefix" db:"key_prefix"` // 識別用のキー先頭部分
+	KeyHash    string     `json:"-" db:"key_hash"`            // SHA-256ハッシュ（JSON出力しない）
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsRevoked APIキーが失効済みかどうか
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// CreateAPIKeyRequest APIキー発行リクエスト
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required,max=100" validate:"required,max=100"`
+}
+
+// CreateAPIKeyResponse APIキー発行レスポンス（平文のキーはこのレスポンスでのみ返す）
+type CreateAPIKeyResponse struct {
+	APIKey *APIKey `json:"api_key"`
+	Key    string  `json:"key"`
+}
@@ -12,10 +12,11 @@ type Memo struct {
 	Category    string     `json:"category" db:"category" binding:"max=50"`
 	Tags        string     `json:"tags" db:"tags"` // JSON文字列として保存
 	Priority    string     `json:"priority" db:"priority" binding:"oneof=low medium high"`
-	Status      string     `json:"status" db:"status" binding:"oneof=active archived"`
+	Status      string     `json:"status" db:"status" binding:"oneof=active archived trashed"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
 	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	TrashedAt   *time.Time `json:"trashed_at,omitempty" db:"trashed_at"` // ゴミ箱に移動した日時
 }
 
 // CreateMemoRequest represents the request payload for creating a memo
@@ -49,7 +50,7 @@ type MemoListResponse struct {
 // MemoFilter represents filter options for memo queries
 type MemoFilter struct {
 	Category string `form:"category"`
-	Status   string `form:"status" binding:"omitempty,oneof=active archived"`
+	Status   string `form:"status" binding:"omitempty,oneof=active archived trashed"`
 	Priority string `form:"priority" binding:"omitempty,oneof=low medium high"`
 	Search   string `form:"search"`
 	Tags     string `form:"tags"`
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+// PasswordReset 発行済みのパスワード再設定トークン（1回のみ使用可能）
+type PasswordReset struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"` // SHA-256ハッシュ（JSON出力しない）
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsUsable 未使用かつ有効期限内かどうか
+func (p *PasswordReset) IsUsable(now time.Time) bool {
+	return p.UsedAt == nil && now.Before(p.ExpiresAt)
+}
+
+// ForgotPasswordRequest パスワード再設定メールの送信リクエスト
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" validate:"required,email"`
+}
+
+// ChangePasswordRequest ログイン中のユーザーによるパスワード変更リクエスト
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required" validate:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,max=128" validate:"required,min=8,max=128,password_strength"`
+}
+
+// DeleteAccountRequest アカウント削除リクエスト（GitHub認証のみのアカウントはパスワード不要）
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// ResetPasswordRequest パスワード再設定リクエスト
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required" validate:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=128" validate:"required,min=8,max=128,password_strength"`
+}
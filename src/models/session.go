@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// RefreshSession 発行済みリフレッシュトークン（端末ごとのログインセッション）
+type RefreshSession struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"` // SHA-256ハッシュ（JSON出力しない）
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsActive 失効しておらず有効期限内かどうか
+func (s *RefreshSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
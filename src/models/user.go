@@ -14,6 +14,9 @@ type User struct {
 	GitHubUsername *string    `json:"github_username" db:"github_username"`
 	AvatarURL      *string    `json:"avatar_url" db:"avatar_url"`
 	IsActive       bool       `json:"is_active" db:"is_active"`
+	EmailVerified  bool       `json:"email_verified" db:"email_verified"`
+	TokenVersion   int        `json:"-" db:"token_version"` // これより古いバージョンのトークンは無効（JSON出力しない）
+	Role           string     `json:"role" db:"role"`       // RoleUser または RoleAdmin
 	LastLoginAt    *time.Time `json:"last_login_at" db:"last_login_at"`
 	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
@@ -22,13 +25,30 @@ type User struct {
 
 // PublicUser 公開用ユーザー情報（センシティブな情報を除外）
 type PublicUser struct {
-	ID             int       `json:"id"`
-	Username       string    `json:"username"`
-	Email          string    `json:"email"`
-	GitHubUsername *string   `json:"github_username,omitempty"`
-	AvatarURL      *string   `json:"avatar_url,omitempty"`
-	IsActive       bool      `json:"is_active"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID             int          `json:"id"`
+	Username       string       `json:"username"`
+	Email          string       `json:"email"`
+	GitHubUsername *string      `json:"github_username,omitempty"`
+	AvatarURL      *string      `json:"avatar_url,omitempty"`
+	IsActive       bool         `json:"is_active"`
+	EmailVerified  bool         `json:"email_verified"`
+	Role           string       `json:"role"`
+	AuthProvider   AuthProvider `json:"auth_provider"` // github の場合はパスワードがなく変更できない
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// ユーザーのロール
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// HasRole ユーザーが role を持つか（ロール未設定のユーザーは RoleUser として扱う）
+func (u *User) HasRole(role string) bool {
+	if u.Role == "" {
+		return role == RoleUser
+	}
+	return u.Role == role
 }
 
 // ToPublic センシティブな情報を除外したPublicUserを返す
@@ -40,6 +60,9 @@ func (u *User) ToPublic() *PublicUser {
 		GitHubUsername: u.GitHubUsername,
 		AvatarURL:      u.AvatarURL,
 		IsActive:       u.IsActive,
+		EmailVerified:  u.EmailVerified,
+		Role:           u.Role,
+		AuthProvider:   u.GetAuthProvider(),
 		CreatedAt:      u.CreatedAt,
 	}
 }
@@ -62,12 +85,21 @@ func (u *User) GetAuthProvider() AuthProvider {
 
 // IPRegistration IP制限用のモデル
 type IPRegistration struct {
-	ID         int       `json:"id" db:"id"`
-	IPAddress  string    `json:"ip_address" db:"ip_address"`
-	UserCount  int       `json:"user_count" db:"user_count"`
-	LastUsedAt time.Time `json:"last_used_at" db:"last_used_at"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	ID              int       `json:"id" db:"id"`
+	IPAddress       string    `json:"ip_address" db:"ip_address"`
+	UserCount       int       `json:"user_count" db:"registration_count"`       // WindowStartedAt 以降の登録数
+	LastUsedAt      time.Time `json:"last_used_at" db:"last_registration_at"`   // 最後に登録した日時
+	WindowStartedAt time.Time `json:"window_started_at" db:"window_started_at"` // 登録数を数え始めた日時
+	CreatedAt       time.Time `json:"created_at" db:"first_registration_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CountWithin window の期間内の登録数を返す（WindowStartedAt から window を過ぎていれば0。window が0以下の場合は期間で区切らない）
+func (r *IPRegistration) CountWithin(window time.Duration, now time.Time) int {
+	if window > 0 && now.Sub(r.WindowStartedAt) >= window {
+		return 0
+	}
+	return r.UserCount
 }
 
 // LoginRequest ログインリクエスト
@@ -83,6 +115,17 @@ type RegisterRequest struct {
 	Password string `json:"password" binding:"required,min=8,max=128" validate:"required,min=8,max=128,password_strength"`
 }
 
+// UpdateProfileRequest プロフィール更新リクエスト（指定したフィールドのみ更新）
+// GitHub連携のフィールドはGitHubから取得するため変更できず、指定した場合は拒否する
+type UpdateProfileRequest struct {
+	Username *string `json:"username,omitempty" validate:"omitempty,username_format"`
+	Email    *string `json:"email,omitempty" validate:"omitempty,email"`
+
+	GitHubID       *int64  `json:"github_id,omitempty" validate:"-"`
+	GitHubUsername *string `json:"github_username,omitempty" validate:"-"`
+	AvatarURL      *string `json:"avatar_url,omitempty" validate:"-"`
+}
+
 // GitHubAuthRequest GitHub認証リクエスト
 type GitHubAuthRequest struct {
 	Code  string `json:"code" binding:"required" validate:"required"`
@@ -0,0 +1,146 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"memo-app/src/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 利用可能な通知ドライバー
+const (
+	DriverLog     = "log"
+	DriverWebhook = "webhook"
+)
+
+// ReminderEvent Webhookで送信するリマインダーのイベント名
+const ReminderEvent = "memo.reminder"
+
+// DefaultWebhookTimeout Webhook送信のタイムアウト
+const DefaultWebhookTimeout = 10 * time.Second
+
+// Notifier リマインダー通知のインターフェース
+type Notifier interface {
+	Notify(ctx context.Context, reminder domain.MemoReminder) error
+}
+
+// New 設定されたドライバーに応じてNotifierを作成
+func New(driver, webhookURL string, logger *logrus.Logger) (Notifier, error) {
+	switch driver {
+	case "", DriverLog:
+		return NewLogNotifier(logger), nil
+	case DriverWebhook:
+		if webhookURL == "" {
+			return nil, fmt.Errorf("webhookドライバーにはREMINDER_WEBHOOK_URLの設定が必要です")
+		}
+		return NewWebhookNotifier(webhookURL, nil), nil
+	default:
+		return nil, fmt.Errorf("不明な通知ドライバー: %s", driver)
+	}
+}
+
+// LogNotifier 通知を送信せずログに出力する開発用のNotifier
+type LogNotifier struct {
+	logger *logrus.Logger
+}
+
+// NewLogNotifier ログ出力のみのNotifierを作成
+func NewLogNotifier(logger *logrus.Logger) *LogNotifier {
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+	return &LogNotifier{logger: logger}
+}
+
+// Notify リマインダーの内容をログに出力
+func (n *LogNotifier) Notify(ctx context.Context, reminder domain.MemoReminder) error {
+	n.logger.WithFields(logrus.Fields{
+		"user_id":   reminder.UserID,
+		"memo_id":   reminder.Memo.ID,
+		"title":     reminder.Memo.Title,
+		"remind_at": reminder.Memo.RemindAt,
+	}).Info("リマインダーを通知しました（ログ出力のみ）")
+	return nil
+}
+
+// WebhookPayload Webhookに送信するJSON
+type WebhookPayload struct {
+	Event  string             `json:"event"`
+	UserID int                `json:"user_id"`
+	Memo   WebhookMemoPayload `json:"memo"`
+}
+
+// WebhookMemoPayload Webhookに含めるメモの内容
+type WebhookMemoPayload struct {
+	ID       int        `json:"id"`
+	Title    string     `json:"title"`
+	Content  string     `json:"content"`
+	Category string     `json:"category"`
+	Tags     []string   `json:"tags"`
+	Priority string     `json:"priority"`
+	DueDate  *time.Time `json:"due_date,omitempty"`
+	RemindAt *time.Time `json:"remind_at,omitempty"`
+}
+
+// WebhookNotifier リマインダーをJSONでWebhookにPOSTするNotifier
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier WebhookNotifierを作成（clientがnilの場合はDefaultWebhookTimeoutのクライアントを使用）
+func NewWebhookNotifier(url string, client *http.Client) *WebhookNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+	return &WebhookNotifier{url: url, client: client}
+}
+
+// Notify リマインダーをWebhookにPOST（2xx以外の応答はエラー）
+func (n *WebhookNotifier) Notify(ctx context.Context, reminder domain.MemoReminder) error {
+	memo := reminder.Memo
+	tags := memo.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	body, err := json.Marshal(WebhookPayload{
+		Event:  ReminderEvent,
+		UserID: reminder.UserID,
+		Memo: WebhookMemoPayload{
+			ID:       memo.ID,
+			Title:    memo.Title,
+			Content:  memo.Content,
+			Category: memo.Category,
+			Tags:     tags,
+			Priority: string(memo.Priority),
+			DueDate:  memo.DueDate,
+			RemindAt: memo.RemindAt,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("webhookの本文の作成に失敗: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhookのリクエストの作成に失敗: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhookの送信に失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhookがエラーを返しました: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Package problem RFC 7807 形式（application/problem+json）のエラーレスポンス
+//
+// 既存のクライアントとの互換性のため、problem+json は Accept ヘッダーで要求された場合、
+// または PROBLEM_JSON_ERRORS=true（Middleware で有効化）の場合のみ返し、それ以外は従来のエラーボディを返す
+package problem
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"memo-app/src/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType problem+json のメディアタイプ
+const ContentType = "application/problem+json"
+
+// DefaultType 問題の種類を個別に定義しない場合の type（RFC 7807 3.1）
+const DefaultType = "about:blank"
+
+// enabledKey 全レスポンスを problem+json にするかを gin context に保存するキー
+const enabledKey = "problem_json_enabled"
+
+// Details RFC 7807 の問題の詳細
+// Code・Errors と Extensions は拡張メンバーとして同じ階層に出力する
+type Details struct {
+	Type     string                      `json:"type"`
+	Title    string                      `json:"title"`
+	Status   int                         `json:"status"`
+	Detail   string                      `json:"detail,omitempty"`
+	Instance string                      `json:"instance,omitempty"`
+	Code     string                      `json:"code,omitempty"`
+	Errors   []validator.ValidationError `json:"errors,omitempty"`
+
+	Extensions map[string]any `json:"-"`
+}
+
+// Convertible problem+json に変換できる従来のエラーボディ
+type Convertible interface {
+	Problem(status int) Details
+}
+
+// MarshalJSON 拡張メンバーを標準メンバーと同じ階層に出力する（標準メンバーは上書きしない）
+func (d Details) MarshalJSON() ([]byte, error) {
+	type details Details
+	base, err := json.Marshal(details(d))
+	if err != nil || len(d.Extensions) == 0 {
+		return base, err
+	}
+
+	merged := make(map[string]any, len(d.Extensions)+7)
+	for k, v := range d.Extensions {
+		merged[k] = v
+	}
+	var members map[string]any
+	if err := json.Unmarshal(base, &members); err != nil {
+		return nil, err
+	}
+	for k, v := range members {
+		merged[k] = v
+	}
+	return json.Marshal(merged)
+}
+
+// New status のエラーを title で作成（title が空の場合はHTTPステータスの説明）
+func New(status int, title string) Details {
+	if title == "" {
+		title = http.StatusText(status)
+	}
+	return Details{Type: DefaultType, Title: title, Status: status}
+}
+
+// Enable このリクエストのエラーを Accept ヘッダーに関わらず problem+json で返す
+func Enable(c *gin.Context) {
+	c.Set(enabledKey, true)
+}
+
+// Middleware enabled の場合、すべてのエラーレスポンスを problem+json で返す
+func Middleware(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if enabled {
+			Enable(c)
+		}
+		c.Next()
+	}
+}
+
+// Wanted problem+json で返すか（設定で有効、またはクライアントが Accept で要求した場合）
+func Wanted(c *gin.Context) bool {
+	if c.GetBool(enabledKey) {
+		return true
+	}
+	for _, accept := range strings.Split(c.GetHeader("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && mediaType == ContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON エラーレスポンスを返す共通のヘルパー
+// problem+json で返す場合は body を Details に変換し、それ以外は body をそのまま返す
+func JSON(c *gin.Context, status int, body any) {
+	if !Wanted(c) {
+		c.JSON(status, body)
+		return
+	}
+
+	d := From(status, body)
+	if d.Instance == "" && c.Request != nil {
+		d.Instance = c.Request.URL.Path
+	}
+	c.Header("Content-Type", ContentType)
+	c.JSON(status, d)
+}
+
+// AbortWithJSON JSON でエラーレスポンスを返し、以降のハンドラーを実行しない（middleware用）
+func AbortWithJSON(c *gin.Context, status int, body any) {
+	JSON(c, status, body)
+	c.Abort()
+}
+
+// From 従来のエラーボディを Details に変換
+func From(status int, body any) Details {
+	var d Details
+	switch b := body.(type) {
+	case Details:
+		d = b
+	case *Details:
+		d = *b
+	case Convertible:
+		d = b.Problem(status)
+	case validator.ValidationErrors:
+		d = New(status, "Validation failed")
+		d.Code = "VALIDATION_FAILED"
+		d.Errors = b.Errors
+	case gin.H:
+		d = fromMap(status, b)
+	case map[string]any:
+		d = fromMap(status, b)
+	default:
+		d = New(status, "")
+	}
+
+	d.Status = status
+	if d.Type == "" {
+		d.Type = DefaultType
+	}
+	if d.Title == "" {
+		d.Title = http.StatusText(status)
+	}
+	return d
+}
+
+// fromMap gin.H{"error": ..., "message": ...} 形式のボディを変換
+// field は errors に、その他のキー（retry_after 等）は拡張メンバーとして残す
+func fromMap(status int, body map[string]any) Details {
+	d := New(status, "")
+	for key, value := range body {
+		s, isString := value.(string)
+		switch {
+		case key == "error" && isString:
+			d.Title = s
+		case key == "message" && isString:
+			d.Detail = s
+		case key == "code" && isString:
+			d.Code = s
+		case key == "field" && isString:
+			d.Errors = append(d.Errors, validator.ValidationError{Field: s})
+		default:
+			if d.Extensions == nil {
+				d.Extensions = make(map[string]any)
+			}
+			d.Extensions[key] = value
+		}
+	}
+	for i := range d.Errors {
+		d.Errors[i].Message = d.Title
+	}
+	return d
+}
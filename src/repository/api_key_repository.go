@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"memo-app/src/models"
+)
+
+// APIKeyRepository APIキーデータアクセス層のインターフェース
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error
+	GetByHash(keyHash string) (*models.APIKey, error)
+	ListByUserID(userID int) ([]*models.APIKey, error)
+	Revoke(userID, keyID int) error
+	UpdateLastUsed(keyID int) error
+}
+
+// apiKeyRepository APIキーリポジトリの実装
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository APIキーリポジトリを作成
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// Create APIキーを作成
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	query := `
+		INSERT INTO api_keys (user_id, name, key_prefix, key_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRow(query, key.UserID, key.Name, key.KeyPrefix, key.KeyHash, time.Now()).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+// GetByHash キーハッシュでAPIキーを取得
+func (r *apiKeyRepository) GetByHash(keyHash string) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	query := `
+		SELECT id, user_id, name, key_prefix, key_hash, last_used_at, revoked_at, created_at
+		FROM api_keys WHERE key_hash = $1`
+
+	err := r.db.QueryRow(query, keyHash).Scan(
+		&key.ID, &key.UserID, &key.Name, &key.KeyPrefix, &key.KeyHash,
+		&key.LastUsedAt, &key.RevokedAt, &key.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("api key not found")
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return key, nil
+}
+
+// ListByUserID ユーザーのAPIキー一覧を取得（新しい順）
+func (r *apiKeyRepository) ListByUserID(userID int) ([]*models.APIKey, error) {
+	query := `
+		SELECT id, user_id, name, key_prefix, key_hash, last_used_at, revoked_at, created_at
+		FROM api_keys WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []*models.APIKey{}
+	for rows.Next() {
+		key := &models.APIKey{}
+		if err := rows.Scan(
+			&key.ID, &key.UserID, &key.Name, &key.KeyPrefix, &key.KeyHash,
+			&key.LastUsedAt, &key.RevokedAt, &key.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke ユーザーのAPIキーを失効させる（失効済みのキーは対象外）
+func (r *apiKeyRepository) Revoke(userID, keyID int) error {
+	query := `UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL`
+	result, err := r.db.Exec(query, time.Now(), keyID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("api key not found")
+	}
+	return nil
+}
+
+// UpdateLastUsed APIキーの最終使用日時を更新
+func (r *apiKeyRepository) UpdateLastUsed(keyID int) error {
+	query := `UPDATE api_keys SET last_used_at = $1 WHERE id = $2`
+	if _, err := r.db.Exec(query, time.Now(), keyID); err != nil {
+		return fmt.Errorf("failed to update api key last used: %w", err)
+	}
+	return nil
+}
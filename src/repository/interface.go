@@ -13,4 +13,6 @@ type MemoRepositoryInterface interface {
 	List(ctx context.Context, filter *models.MemoFilter) (*models.MemoListResponse, error)
 	Update(ctx context.Context, id int, req *models.UpdateMemoRequest) (*models.Memo, error)
 	Delete(ctx context.Context, id int) error
+	Trash(ctx context.Context, id int) (*models.Memo, error)
+	PermanentDelete(ctx context.Context, id int) error
 }
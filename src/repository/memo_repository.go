@@ -9,15 +9,20 @@ import (
 	"time"
 
 	"memo-app/src/database"
+	"memo-app/src/domain"
+	memoRepository "memo-app/src/infrastructure/repository"
 	"memo-app/src/models"
 
 	"github.com/sirupsen/logrus"
 )
 
-// MemoRepository represents the memo repository
+// MemoRepository represents the memo repository.
+// Delete, Trash and PermanentDelete delegate to the domain.MemoRepository implementation
+// so both APIs share the same staged-delete behavior (user scoping, recycle log).
 type MemoRepository struct {
 	db     *database.DB
 	logger *logrus.Logger
+	memos  domain.MemoRepository
 }
 
 // NewMemoRepository creates a new memo repository
@@ -25,6 +30,7 @@ func NewMemoRepository(db *database.DB, logger *logrus.Logger) *MemoRepository {
 	return &MemoRepository{
 		db:     db,
 		logger: logger,
+		memos:  memoRepository.NewMemoRepository(db, logger),
 	}
 }
 
@@ -70,13 +76,13 @@ func (r *MemoRepository) Create(ctx context.Context, req *models.CreateMemoReque
 // GetByID retrieves a memo by ID
 func (r *MemoRepository) GetByID(ctx context.Context, id int) (*models.Memo, error) {
 	query := `
-		SELECT id, title, content, category, tags, priority, status, created_at, updated_at, completed_at
+		SELECT id, title, content, category, tags, priority, status, created_at, updated_at, completed_at, trashed_at
 		FROM memos WHERE id = $1`
 
 	memo := &models.Memo{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&memo.ID, &memo.Title, &memo.Content, &memo.Category, &memo.Tags,
-		&memo.Priority, &memo.Status, &memo.CreatedAt, &memo.UpdatedAt, &memo.CompletedAt,
+		&memo.Priority, &memo.Status, &memo.CreatedAt, &memo.UpdatedAt, &memo.CompletedAt, &memo.TrashedAt,
 	)
 
 	if err != nil {
@@ -96,7 +102,7 @@ func (r *MemoRepository) List(ctx context.Context, filter *models.MemoFilter) (*
 	baseQuery := `FROM memos WHERE 1=1`
 	countQuery := `SELECT COUNT(*) ` + baseQuery
 	selectQuery := `
-		SELECT id, title, content, category, tags, priority, status, created_at, updated_at, completed_at
+		SELECT id, title, content, category, tags, priority, status, created_at, updated_at, completed_at, trashed_at
 		` + baseQuery
 
 	var args []interface{}
@@ -113,6 +119,9 @@ func (r *MemoRepository) List(ctx context.Context, filter *models.MemoFilter) (*
 		baseQuery += fmt.Sprintf(" AND status = $%d", argIndex)
 		args = append(args, filter.Status)
 		argIndex++
+	} else {
+		// ステータス未指定の場合、ゴミ箱のメモは含めない
+		baseQuery += " AND status <> 'trashed'"
 	}
 
 	if filter.Priority != "" {
@@ -136,7 +145,7 @@ func (r *MemoRepository) List(ctx context.Context, filter *models.MemoFilter) (*
 	// 更新されたクエリ
 	countQuery = `SELECT COUNT(*) ` + baseQuery
 	selectQuery = `
-		SELECT id, title, content, category, tags, priority, status, created_at, updated_at, completed_at
+		SELECT id, title, content, category, tags, priority, status, created_at, updated_at, completed_at, trashed_at
 		` + baseQuery
 
 	// 総数を取得
@@ -148,7 +157,8 @@ func (r *MemoRepository) List(ctx context.Context, filter *models.MemoFilter) (*
 	}
 
 	// ページネーションを追加
-	selectQuery += " ORDER BY updated_at DESC"
+	// 同一時刻のメモでもページングが安定するようにidを第2キーにする
+	selectQuery += " ORDER BY updated_at DESC, id DESC"
 	selectQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
 	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
 
@@ -165,7 +175,7 @@ func (r *MemoRepository) List(ctx context.Context, filter *models.MemoFilter) (*
 		var memo models.Memo
 		err := rows.Scan(
 			&memo.ID, &memo.Title, &memo.Content, &memo.Category, &memo.Tags,
-			&memo.Priority, &memo.Status, &memo.CreatedAt, &memo.UpdatedAt, &memo.CompletedAt,
+			&memo.Priority, &memo.Status, &memo.CreatedAt, &memo.UpdatedAt, &memo.CompletedAt, &memo.TrashedAt,
 		)
 		if err != nil {
 			r.logger.WithError(err).Error("メモのスキャンに失敗")
@@ -178,7 +188,11 @@ func (r *MemoRepository) List(ctx context.Context, filter *models.MemoFilter) (*
 		return nil, fmt.Errorf("rows error: %w", err)
 	}
 
-	totalPages := (total + filter.Limit - 1) / filter.Limit
+	// 上限未指定（0以下）の場合に0除算しない
+	totalPages := 0
+	if filter.Limit > 0 {
+		totalPages = (total + filter.Limit - 1) / filter.Limit
+	}
 
 	return &models.MemoListResponse{
 		Memos:      memos,
@@ -247,6 +261,11 @@ func (r *MemoRepository) Update(ctx context.Context, id int, req *models.UpdateM
 			args = append(args, time.Now())
 			argIndex++
 		}
+
+		// ゴミ箱以外のステータスに変更した場合、ゴミ箱に移動した日時をクリア
+		if *req.Status != "trashed" {
+			setParts = append(setParts, "trashed_at = NULL")
+		}
 	}
 
 	if len(setParts) == 0 {
@@ -264,13 +283,13 @@ func (r *MemoRepository) Update(ctx context.Context, id int, req *models.UpdateM
 	query := fmt.Sprintf(`
 		UPDATE memos SET %s
 		WHERE id = $%d
-		RETURNING id, title, content, category, tags, priority, status, created_at, updated_at, completed_at`,
+		RETURNING id, title, content, category, tags, priority, status, created_at, updated_at, completed_at, trashed_at`,
 		strings.Join(setParts, ", "), argIndex)
 
 	memo := &models.Memo{}
 	err = r.db.QueryRowContext(ctx, query, args...).Scan(
 		&memo.ID, &memo.Title, &memo.Content, &memo.Category, &memo.Tags,
-		&memo.Priority, &memo.Status, &memo.CreatedAt, &memo.UpdatedAt, &memo.CompletedAt,
+		&memo.Priority, &memo.Status, &memo.CreatedAt, &memo.UpdatedAt, &memo.CompletedAt, &memo.TrashedAt,
 	)
 
 	if err != nil {
@@ -284,23 +303,44 @@ func (r *MemoRepository) Update(ctx context.Context, id int, req *models.UpdateM
 
 // Delete deletes a memo
 func (r *MemoRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM memos WHERE id = $1`
+	return r.memos.Delete(ctx, id)
+}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+// Trash moves a memo to the trash (status trashed with trashed_at set)
+func (r *MemoRepository) Trash(ctx context.Context, id int) (*models.Memo, error) {
+	memo, err := r.memos.Trash(ctx, id)
 	if err != nil {
-		r.logger.WithError(err).WithField("memo_id", id).Error("メモの削除に失敗")
-		return fmt.Errorf("failed to delete memo: %w", err)
+		return nil, err
 	}
+	return toModelMemo(memo)
+}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+// PermanentDelete physically deletes a memo that is in the trash, keeping it in the recycle log
+func (r *MemoRepository) PermanentDelete(ctx context.Context, id int) error {
+	return r.memos.PermanentDelete(ctx, id)
+}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("memo not found")
+// toModelMemo converts a domain memo to the legacy model (tags as a JSON string)
+func toModelMemo(memo *domain.Memo) (*models.Memo, error) {
+	tagList := memo.Tags
+	if tagList == nil {
+		tagList = []string{}
 	}
-
-	r.logger.WithField("memo_id", id).Info("メモを削除しました")
-	return nil
+	tags, err := json.Marshal(tagList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	return &models.Memo{
+		ID:          memo.ID,
+		Title:       memo.Title,
+		Content:     memo.Content,
+		Category:    memo.Category,
+		Tags:        string(tags),
+		Priority:    string(memo.Priority),
+		Status:      string(memo.Status),
+		CreatedAt:   memo.CreatedAt,
+		UpdatedAt:   memo.UpdatedAt,
+		CompletedAt: memo.CompletedAt,
+		TrashedAt:   memo.TrashedAt,
+	}, nil
 }
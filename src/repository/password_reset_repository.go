@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"memo-app/src/models"
+)
+
+// PasswordResetRepository パスワード再設定トークンのデータアクセス層のインターフェース
+type PasswordResetRepository interface {
+	Create(reset *models.PasswordReset) error
+	GetByTokenHash(tokenHash string) (*models.PasswordReset, error)
+	MarkUsed(resetID int) error
+	InvalidateByUserID(userID int) error
+}
+
+// passwordResetRepository パスワード再設定トークンリポジトリの実装
+type passwordResetRepository struct {
+	db *sql.DB
+}
+
+// NewPasswordResetRepository パスワード再設定トークンリポジトリを作成
+func NewPasswordResetRepository(db *sql.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create トークンを作成
+func (r *passwordResetRepository) Create(reset *models.PasswordReset) error {
+	query := `
+		INSERT INTO password_resets (user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRow(query, reset.UserID, reset.TokenHash, reset.ExpiresAt, time.Now()).
+		Scan(&reset.ID, &reset.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+	return nil
+}
+
+// GetByTokenHash トークンハッシュで取得
+func (r *passwordResetRepository) GetByTokenHash(tokenHash string) (*models.PasswordReset, error) {
+	reset := &models.PasswordReset{}
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM password_resets WHERE token_hash = $1`
+
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&reset.ID, &reset.UserID, &reset.TokenHash,
+		&reset.ExpiresAt, &reset.UsedAt, &reset.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("password reset not found")
+		}
+		return nil, fmt.Errorf("failed to get password reset: %w", err)
+	}
+	return reset, nil
+}
+
+// MarkUsed トークンを使用済みにする
+// 同時に使用された場合に1件だけ成功するよう、未使用の場合のみ更新する
+func (r *passwordResetRepository) MarkUsed(resetID int) error {
+	query := `UPDATE password_resets SET used_at = $1 WHERE id = $2 AND used_at IS NULL`
+	result, err := r.db.Exec(query, time.Now(), resetID)
+	if err != nil {
+		return fmt.Errorf("failed to mark password reset used: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("password reset already used")
+	}
+	return nil
+}
+
+// InvalidateByUserID ユーザーの未使用のトークンをすべて無効にする
+func (r *passwordResetRepository) InvalidateByUserID(userID int) error {
+	query := `UPDATE password_resets SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL`
+	if _, err := r.db.Exec(query, time.Now(), userID); err != nil {
+		return fmt.Errorf("failed to invalidate password resets: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RevokedTokenRepository 失効させたJWT（jti）のデータアクセス層のインターフェース
+type RevokedTokenRepository interface {
+	Revoke(jti string, expiresAt time.Time) error
+	IsRevoked(jti string) (bool, error)
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// revokedTokenRepository 失効トークンリポジトリの実装
+type revokedTokenRepository struct {
+	db *sql.DB
+}
+
+// NewRevokedTokenRepository 失効トークンリポジトリを作成
+func NewRevokedTokenRepository(db *sql.DB) RevokedTokenRepository {
+	return &revokedTokenRepository{db: db}
+}
+
+// Revoke トークンを失効させる（失効済みの場合は何もしない）
+func (r *revokedTokenRepository) Revoke(jti string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, expires_at, revoked_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING`
+
+	if _, err := r.db.Exec(query, jti, expiresAt, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// IsRevoked トークンが失効しているか確認
+func (r *revokedTokenRepository) IsRevoked(jti string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)`
+	if err := r.db.QueryRow(query, jti).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+	return exists, nil
+}
+
+// DeleteExpired 有効期限を過ぎた行を削除し、削除件数を返す（期限切れのトークンは失効の記録がなくても検証に失敗する）
+func (r *revokedTokenRepository) DeleteExpired(now time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM revoked_tokens WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired revoked tokens: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return deleted, nil
+}
+
+// StartRevokedTokenCleanup 有効期限を過ぎた失効トークンを定期的に削除する
+// 返り値の関数でバックグラウンドの処理を停止する
+func StartRevokedTokenCleanup(repo RevokedTokenRepository, interval time.Duration, logger *logrus.Logger) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := repo.DeleteExpired(time.Now()); err != nil {
+					logger.WithError(err).Error("失効トークンの定期削除に失敗")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	logger.WithField("interval", interval).Info("失効トークンの定期削除を開始しました")
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"memo-app/src/models"
+)
+
+// SessionRepository リフレッシュトークン（ログインセッション）データアクセス層のインターフェース
+type SessionRepository interface {
+	Create(session *models.RefreshSession) error
+	GetByTokenHash(tokenHash string) (*models.RefreshSession, error)
+	ListActiveByUserID(userID int) ([]*models.RefreshSession, error)
+	Revoke(sessionID int) error
+}
+
+// sessionRepository セッションリポジトリの実装
+type sessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository セッションリポジトリを作成
+func NewSessionRepository(db *sql.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create セッションを作成
+func (r *sessionRepository) Create(session *models.RefreshSession) error {
+	query := `
+		INSERT INTO refresh_sessions (user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRow(query, session.UserID, session.TokenHash, session.ExpiresAt, time.Now()).
+		Scan(&session.ID, &session.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// GetByTokenHash トークンハッシュでセッションを取得
+func (r *sessionRepository) GetByTokenHash(tokenHash string) (*models.RefreshSession, error) {
+	session := &models.RefreshSession{}
+	query := `
+		SELECT id, user_id, token_hash, expires_at, revoked_at, created_at
+		FROM refresh_sessions WHERE token_hash = $1`
+
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&session.ID, &session.UserID, &session.TokenHash,
+		&session.ExpiresAt, &session.RevokedAt, &session.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("session not found")
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return session, nil
+}
+
+// ListActiveByUserID ユーザーの有効なセッション一覧を取得（古い順）
+func (r *sessionRepository) ListActiveByUserID(userID int) ([]*models.RefreshSession, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, revoked_at, created_at
+		FROM refresh_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(query, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*models.RefreshSession{}
+	for rows.Next() {
+		session := &models.RefreshSession{}
+		if err := rows.Scan(
+			&session.ID, &session.UserID, &session.TokenHash,
+			&session.ExpiresAt, &session.RevokedAt, &session.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// Revoke セッションを失効させる（失効済みのセッションは対象外）
+func (r *sessionRepository) Revoke(sessionID int) error {
+	query := `UPDATE refresh_sessions SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+	if _, err := r.db.Exec(query, time.Now(), sessionID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
@@ -18,12 +18,16 @@ type UserRepository interface {
 	GetByUsername(username string) (*models.User, error)
 	Update(user *models.User) error
 	UpdateLastLogin(userID int) error
+	MarkEmailVerified(userID int) error
+	IncrementTokenVersion(userID int) (int, error)
+	Delete(userID int) error
 
 	// IP制限管理
 	GetIPRegistration(ipAddress string) (*models.IPRegistration, error)
 	CreateIPRegistration(ipReg *models.IPRegistration) error
 	UpdateIPRegistration(ipReg *models.IPRegistration) error
 	GetUserCountByIP(ipAddress string) (int, error)
+	ResetIPRegistration(ipAddress string) error
 
 	// セキュリティ
 	IsEmailExists(email string) (bool, error)
@@ -44,9 +48,9 @@ func NewUserRepository(db *sql.DB) UserRepository {
 // Create ユーザーを作成
 func (r *userRepository) Create(user *models.User) error {
 	query := `
-		INSERT INTO users (username, email, password_hash, github_id, github_username, avatar_url, is_active, created_ip, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id, created_at, updated_at`
+		INSERT INTO users (username, email, password_hash, github_id, github_username, avatar_url, is_active, email_verified, created_ip, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, role, created_at, updated_at`
 
 	err := r.db.QueryRow(
 		query,
@@ -57,10 +61,11 @@ func (r *userRepository) Create(user *models.User) error {
 		user.GitHubUsername,
 		user.AvatarURL,
 		user.IsActive,
+		user.EmailVerified,
 		user.CreatedIP,
 		time.Now(),
 		time.Now(),
-	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	).Scan(&user.ID, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
@@ -74,13 +79,13 @@ func (r *userRepository) GetByID(id int) (*models.User, error) {
 	user := &models.User{}
 	query := `
 		SELECT id, username, email, password_hash, github_id, github_username, avatar_url, 
-		       is_active, last_login_at, created_at, updated_at, created_ip
+		       is_active, email_verified, token_version, role, last_login_at, created_at, updated_at, created_ip
 		FROM users WHERE id = $1`
 
 	err := r.db.QueryRow(query, id).Scan(
 		&user.ID, &user.Username, &user.Email, &user.PasswordHash,
 		&user.GitHubID, &user.GitHubUsername, &user.AvatarURL,
-		&user.IsActive, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt, &user.CreatedIP,
+		&user.IsActive, &user.EmailVerified, &user.TokenVersion, &user.Role, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt, &user.CreatedIP,
 	)
 
 	if err != nil {
@@ -98,13 +103,13 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	user := &models.User{}
 	query := `
 		SELECT id, username, email, password_hash, github_id, github_username, avatar_url, 
-		       is_active, last_login_at, created_at, updated_at, created_ip
+		       is_active, email_verified, token_version, role, last_login_at, created_at, updated_at, created_ip
 		FROM users WHERE email = $1`
 
 	err := r.db.QueryRow(query, email).Scan(
 		&user.ID, &user.Username, &user.Email, &user.PasswordHash,
 		&user.GitHubID, &user.GitHubUsername, &user.AvatarURL,
-		&user.IsActive, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt, &user.CreatedIP,
+		&user.IsActive, &user.EmailVerified, &user.TokenVersion, &user.Role, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt, &user.CreatedIP,
 	)
 
 	if err != nil {
@@ -122,13 +127,13 @@ func (r *userRepository) GetByGitHubID(githubID int64) (*models.User, error) {
 	user := &models.User{}
 	query := `
 		SELECT id, username, email, password_hash, github_id, github_username, avatar_url, 
-		       is_active, last_login_at, created_at, updated_at, created_ip
+		       is_active, email_verified, token_version, role, last_login_at, created_at, updated_at, created_ip
 		FROM users WHERE github_id = $1`
 
 	err := r.db.QueryRow(query, githubID).Scan(
 		&user.ID, &user.Username, &user.Email, &user.PasswordHash,
 		&user.GitHubID, &user.GitHubUsername, &user.AvatarURL,
-		&user.IsActive, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt, &user.CreatedIP,
+		&user.IsActive, &user.EmailVerified, &user.TokenVersion, &user.Role, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt, &user.CreatedIP,
 	)
 
 	if err != nil {
@@ -146,13 +151,13 @@ func (r *userRepository) GetByUsername(username string) (*models.User, error) {
 	user := &models.User{}
 	query := `
 		SELECT id, username, email, password_hash, github_id, github_username, avatar_url, 
-		       is_active, last_login_at, created_at, updated_at, created_ip
+		       is_active, email_verified, token_version, role, last_login_at, created_at, updated_at, created_ip
 		FROM users WHERE username = $1`
 
 	err := r.db.QueryRow(query, username).Scan(
 		&user.ID, &user.Username, &user.Email, &user.PasswordHash,
 		&user.GitHubID, &user.GitHubUsername, &user.AvatarURL,
-		&user.IsActive, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt, &user.CreatedIP,
+		&user.IsActive, &user.EmailVerified, &user.TokenVersion, &user.Role, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt, &user.CreatedIP,
 	)
 
 	if err != nil {
@@ -170,14 +175,15 @@ func (r *userRepository) Update(user *models.User) error {
 	query := `
 		UPDATE users 
 		SET username = $2, email = $3, password_hash = $4, github_id = $5, 
-		    github_username = $6, avatar_url = $7, is_active = $8, updated_at = $9
+		    github_username = $6, avatar_url = $7, is_active = $8, updated_at = $9,
+		    email_verified = $10
 		WHERE id = $1`
 
 	_, err := r.db.Exec(
 		query,
 		user.ID, user.Username, user.Email, user.PasswordHash,
 		user.GitHubID, user.GitHubUsername, user.AvatarURL,
-		user.IsActive, time.Now(),
+		user.IsActive, time.Now(), user.EmailVerified,
 	)
 
 	if err != nil {
@@ -197,16 +203,80 @@ func (r *userRepository) UpdateLastLogin(userID int) error {
 	return nil
 }
 
+// MarkEmailVerified メールアドレスを確認済みにする
+func (r *userRepository) MarkEmailVerified(userID int) error {
+	query := `UPDATE users SET email_verified = true, updated_at = $1 WHERE id = $2`
+	result, err := r.db.Exec(query, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// IncrementTokenVersion トークンバージョンを1つ進め、更新後の値を返す
+// 以前のバージョンで発行されたアクセストークン・リフレッシュトークンはすべて無効になる
+func (r *userRepository) IncrementTokenVersion(userID int) (int, error) {
+	query := `UPDATE users SET token_version = token_version + 1, updated_at = $1 WHERE id = $2 RETURNING token_version`
+
+	var version int
+	if err := r.db.QueryRow(query, time.Now(), userID).Scan(&version); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("user not found")
+		}
+		return 0, fmt.Errorf("failed to increment token version: %w", err)
+	}
+	return version, nil
+}
+
+// Delete ユーザーとそのメモを1つのトランザクションで削除
+// セッション・APIキーなどユーザーに紐づくその他のデータは外部キーの ON DELETE CASCADE で削除される
+func (r *userRepository) Delete(userID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM memos WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete memos: %w", err)
+	}
+
+	result, err := tx.Exec(`DELETE FROM users WHERE id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // GetIPRegistration IP登録情報を取得
 func (r *userRepository) GetIPRegistration(ipAddress string) (*models.IPRegistration, error) {
 	ipReg := &models.IPRegistration{}
 	query := `
-		SELECT id, ip_address, user_count, last_used_at, created_at, updated_at
+		SELECT id, ip_address, registration_count, last_registration_at, window_started_at, first_registration_at, updated_at
 		FROM ip_registrations WHERE ip_address = $1`
 
 	err := r.db.QueryRow(query, ipAddress).Scan(
 		&ipReg.ID, &ipReg.IPAddress, &ipReg.UserCount,
-		&ipReg.LastUsedAt, &ipReg.CreatedAt, &ipReg.UpdatedAt,
+		&ipReg.LastUsedAt, &ipReg.WindowStartedAt, &ipReg.CreatedAt, &ipReg.UpdatedAt,
 	)
 
 	if err != nil {
@@ -222,8 +292,8 @@ func (r *userRepository) GetIPRegistration(ipAddress string) (*models.IPRegistra
 // CreateIPRegistration IP登録情報を作成
 func (r *userRepository) CreateIPRegistration(ipReg *models.IPRegistration) error {
 	query := `
-		INSERT INTO ip_registrations (ip_address, user_count, last_used_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO ip_registrations (ip_address, registration_count, last_registration_at, window_started_at, first_registration_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id`
 
 	err := r.db.QueryRow(
@@ -231,6 +301,7 @@ func (r *userRepository) CreateIPRegistration(ipReg *models.IPRegistration) erro
 		ipReg.IPAddress,
 		ipReg.UserCount,
 		ipReg.LastUsedAt,
+		ipReg.WindowStartedAt,
 		time.Now(),
 		time.Now(),
 	).Scan(&ipReg.ID)
@@ -246,7 +317,7 @@ func (r *userRepository) CreateIPRegistration(ipReg *models.IPRegistration) erro
 func (r *userRepository) UpdateIPRegistration(ipReg *models.IPRegistration) error {
 	query := `
 		UPDATE ip_registrations 
-		SET user_count = $2, last_used_at = $3, updated_at = $4
+		SET registration_count = $2, last_registration_at = $3, window_started_at = $4, updated_at = $5
 		WHERE id = $1`
 
 	_, err := r.db.Exec(
@@ -254,6 +325,7 @@ func (r *userRepository) UpdateIPRegistration(ipReg *models.IPRegistration) erro
 		ipReg.ID,
 		ipReg.UserCount,
 		ipReg.LastUsedAt,
+		ipReg.WindowStartedAt,
 		time.Now(),
 	)
 
@@ -264,6 +336,14 @@ func (r *userRepository) UpdateIPRegistration(ipReg *models.IPRegistration) erro
 	return nil
 }
 
+// ResetIPRegistration 指定IPアドレスの登録数をリセット（記録がない場合も成功）
+func (r *userRepository) ResetIPRegistration(ipAddress string) error {
+	if _, err := r.db.Exec(`DELETE FROM ip_registrations WHERE ip_address = $1`, ipAddress); err != nil {
+		return fmt.Errorf("failed to reset IP registration: %w", err)
+	}
+	return nil
+}
+
 // GetUserCountByIP 指定IPアドレスのユーザー数を取得
 func (r *userRepository) GetUserCountByIP(ipAddress string) (int, error) {
 	var count int
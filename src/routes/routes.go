@@ -1,10 +1,14 @@
 package routes
 
 import (
+	_ "memo-app/src/docs" // swag で生成したAPI仕様書を登録
+	"memo-app/src/handlers"
 	"memo-app/src/interface/handler"
 	"memo-app/src/middleware"
 
 	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 // SetupRoutes sets up all API routes
@@ -12,8 +16,8 @@ func SetupRoutes(r *gin.Engine, memoHandler *handler.MemoHandler) {
 	// パブリックルートのグループ化
 	api := r.Group("/api")
 	api.Use(middleware.LoggerMiddleware())
-	api.Use(middleware.CORSMiddleware())
-	api.Use(middleware.RateLimitMiddleware())
+	// CORSとレート制限はグローバルmiddlewareで適用済み
+	// （ここで重ねると許可オリジンの設定が * で上書きされ、レート制限のバケットが二重になる）
 
 	// TODO: 認証システムを完全に統合後に有効化
 	// 認証関連のパブリックルート
@@ -24,7 +28,29 @@ func SetupRoutes(r *gin.Engine, memoHandler *handler.MemoHandler) {
 	//     auth.POST("/refresh", authHandler.RefreshToken)
 	//     auth.GET("/github/url", authHandler.GetGitHubAuthURL)
 	//     auth.GET("/github/callback", authHandler.GitHubCallback)
+	//     auth.GET("/verify", authHandler.VerifyEmail)
+	//     auth.POST("/password/forgot", authHandler.ForgotPassword)
+	//     auth.POST("/password/reset", authHandler.ResetPassword)
+	//     // パスワード変更（要認証）
+	//     auth.POST("/password/change", middleware.AuthMiddleware(jwtService, userRepo), authHandler.ChangePassword)
+	//     // 全端末からのログアウト（要認証）
+	//     auth.POST("/logout-all", middleware.AuthMiddleware(jwtService, userRepo), authHandler.LogoutAll)
+	//     // ログイン中のユーザーのプロフィール（要認証）
+	//     auth.GET("/me", middleware.AuthMiddleware(jwtService, userRepo), authHandler.GetProfile)
+	//     auth.PUT("/me", middleware.AuthMiddleware(jwtService, userRepo), authHandler.UpdateProfile)
+	//     auth.DELETE("/me", middleware.AuthMiddleware(jwtService, userRepo), authHandler.DeleteAccount)
 	// }
+	//
+	// APIキー管理（要認証。Bearer JWT、X-API-Key ヘッダーまたは ApiKey で認証）
+	// apiKeys := auth.Group("/api-keys")
+	// apiKeys.Use(middleware.AuthMiddlewareWithAPIKeys(jwtService, userRepo, apiKeyService))
+	// {
+	//     apiKeys.POST("", apiKeyHandler.CreateAPIKey)
+	//     apiKeys.GET("", apiKeyHandler.ListAPIKeys)
+	//     apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+	// }
+	//
+	// 管理者用APIは SetupAdminRoutes で登録する
 
 	// 一時的に認証なしでメモAPIを利用可能にする
 	memos := api.Group("/memos")
@@ -37,10 +63,106 @@ func SetupRoutes(r *gin.Engine, memoHandler *handler.MemoHandler) {
 		memos.DELETE("/:id", memoHandler.DeleteMemo) // DELETE /api/memos/:id
 
 		// メモの特別な操作
-		memos.PATCH("/:id/archive", memoHandler.ArchiveMemo) // PATCH /api/memos/:id/archive
-		memos.PATCH("/:id/restore", memoHandler.RestoreMemo) // PATCH /api/memos/:id/restore
+		memos.PATCH("/:id/archive", memoHandler.ArchiveMemo)            // PATCH /api/memos/:id/archive
+		memos.PATCH("/:id/restore", memoHandler.RestoreMemo)            // PATCH /api/memos/:id/restore
+		memos.POST("/:id/trash", memoHandler.TrashMemo)                 // POST /api/memos/:id/trash
+		memos.DELETE("/:id/permanent", memoHandler.PermanentDeleteMemo) // DELETE /api/memos/:id/permanent
+		memos.POST("/:id/promote", memoHandler.PromoteMemo)             // POST /api/memos/:id/promote
+		memos.POST("/:id/touch", memoHandler.TouchMemo)                 // POST /api/memos/:id/touch
+		memos.GET("/:id/history", memoHandler.GetMemoHistory)           // GET /api/memos/:id/history
+		memos.POST("/:id/revert/:revisionID", memoHandler.RevertMemo)   // POST /api/memos/:id/revert/:revisionID
+		memos.PATCH("/:id/metadata", memoHandler.UpdateMemoMetadata)    // PATCH /api/memos/:id/metadata
+		memos.PATCH("/:id/pin", memoHandler.PinMemo)                    // PATCH /api/memos/:id/pin
+		memos.PATCH("/:id/unpin", memoHandler.UnpinMemo)                // PATCH /api/memos/:id/unpin
+
+		// 一括操作
+		memos.POST("/bulk-delete", memoHandler.BulkDeleteMemos) // POST /api/memos/bulk-delete
+		memos.PATCH("/bulk", memoHandler.BulkUpdateMemos)       // PATCH /api/memos/bulk
+
+		// 完全削除したメモのリサイクルログ（保持期間内は復元できる）
+		memos.GET("/deleted", memoHandler.ListDeletedMemos)                // GET /api/memos/deleted
+		memos.POST("/deleted/:id/restore", memoHandler.RestoreDeletedMemo) // POST /api/memos/deleted/:id/restore
+
+		// 他のユーザーから共有されたメモ（自分のメモ一覧とは分ける）
+		memos.GET("/shared-with-me", memoHandler.ListSharedMemos) // GET /api/memos/shared-with-me
+
+		// 過去の同じ日に作成されたメモ
+		memos.GET("/on-this-day", memoHandler.ListOnThisDay) // GET /api/memos/on-this-day
+
+		// エクスポート（バックアップ用）
+		memos.GET("/export", memoHandler.ExportMemos)  // GET /api/memos/export?format=json|csv
+		memos.POST("/import", memoHandler.ImportMemos) // POST /api/memos/import?atomic=true
+
+		// タグ一覧
+		memos.GET("/tags", memoHandler.ListTags) // GET /api/memos/tags
+
+		// カテゴリー一覧（オートコンプリート用）
+		memos.GET("/categories", memoHandler.ListCategories) // GET /api/memos/categories
+
+		// 統計（ダッシュボード用）
+		memos.GET("/stats", memoHandler.GetMemoStats) // GET /api/memos/stats
 
 		// 検索機能
-		memos.GET("/search", memoHandler.SearchMemos) // GET /api/memos/search
+		memos.GET("/search", memoHandler.SearchMemos)                                // GET /api/memos/search
+		memos.GET("/search/recent-queries", memoHandler.GetRecentSearchQueries)      // GET /api/memos/search/recent-queries
+		memos.DELETE("/search/recent-queries", memoHandler.ClearRecentSearchQueries) // DELETE /api/memos/search/recent-queries
+	}
+}
+
+// SetupFeedRoutes sets up the Atom feed of the caller's recent memos. Feed readers cannot send
+// Authorization headers, so auth is expected to authenticate with the api_key query parameter
+func SetupFeedRoutes(r *gin.Engine, memoHandler *handler.MemoHandler, auth gin.HandlerFunc) {
+	r.GET("/api/memos/feed.xml", middleware.LoggerMiddleware(), auth, memoHandler.GetMemoFeed) // GET /api/memos/feed.xml?api_key=
+}
+
+// SetupWebhookRoutes sets up the routes for managing the caller's memo event webhooks
+func SetupWebhookRoutes(r *gin.Engine, webhookHandler *handler.WebhookHandler) {
+	webhooks := r.Group("/api/webhooks")
+	webhooks.Use(middleware.LoggerMiddleware())
+	{
+		webhooks.POST("", webhookHandler.CreateWebhook)       // POST /api/webhooks
+		webhooks.GET("", webhookHandler.ListWebhooks)         // GET /api/webhooks
+		webhooks.GET("/:id", webhookHandler.GetWebhook)       // GET /api/webhooks/:id
+		webhooks.PUT("/:id", webhookHandler.UpdateWebhook)    // PUT /api/webhooks/:id
+		webhooks.DELETE("/:id", webhookHandler.DeleteWebhook) // DELETE /api/webhooks/:id
+	}
+}
+
+// SetupAttachmentRoutes sets up the routes for files attached to the caller's memos
+func SetupAttachmentRoutes(r *gin.Engine, attachmentHandler *handler.AttachmentHandler) {
+	attachments := r.Group("/api/memos/:id/attachments")
+	attachments.Use(middleware.LoggerMiddleware())
+	{
+		attachments.POST("", attachmentHandler.UploadAttachment)                         // POST /api/memos/:id/attachments
+		attachments.GET("", attachmentHandler.ListAttachments)                           // GET /api/memos/:id/attachments
+		attachments.GET("/:attachmentID/download", attachmentHandler.DownloadAttachment) // GET /api/memos/:id/attachments/:attachmentID/download
+	}
+}
+
+// SetupAdminRoutes sets up admin-only routes guarded by the admin token.
+// With an empty token the routes respond 404.
+//
+// The X-Admin-Token header is a stopgap while the auth routes above are disabled: nobody can obtain a
+// JWT yet, so AuthMiddleware + RequireRole(models.RoleAdmin) would lock every admin out. Once the auth
+// system is enabled, this group should switch to those middlewares and drop ADMIN_TOKEN.
+func SetupAdminRoutes(r *gin.Engine, memoHandler *handler.MemoHandler, logUploadHandler *handlers.LogUploadHandler, ipRegistrationHandler *handlers.IPRegistrationHandler, adminToken string) {
+	admin := r.Group("/api/admin")
+	admin.Use(middleware.AdminTokenMiddleware(adminToken))
+	{
+		// 元のIDを保持したメモのインポート（移行ツール用）
+		admin.POST("/memos/import-with-ids", memoHandler.ImportMemosWithIDs) // POST /api/admin/memos/import-with-ids
+		// 定期アップロードを待たずにログをS3へアップロード（障害対応用）
+		admin.POST("/logs/upload", logUploadHandler.UploadLogs) // POST /api/admin/logs/upload
+		// 共有NATなどで上限に達したIPアドレスの登録数をリセット
+		admin.DELETE("/ip-registrations/:ip", ipRegistrationHandler.ResetIPRegistration) // DELETE /api/admin/ip-registrations/:ip
+	}
+}
+
+// SetupSwaggerRoutes serves the generated API documentation at /swagger/index.html
+// (the spec itself at /swagger/doc.json). Nothing is registered when disabled.
+func SetupSwaggerRoutes(r *gin.Engine, enabled bool) {
+	if !enabled {
+		return
 	}
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 }
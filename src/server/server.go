@@ -0,0 +1,48 @@
+// Package server runs the HTTP server until shutdown is requested
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Serve serves srv on ln until ctx is cancelled, then stops accepting new connections and waits
+// up to timeout for in-flight requests to finish. Connections still open after timeout are closed.
+// It returns nil after a clean shutdown, or the error that stopped the server otherwise
+func Serve(ctx context.Context, srv *http.Server, ln net.Listener, timeout time.Duration, logger *logrus.Logger) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.WithField("timeout", timeout).Info("新しい接続の受付を停止し、処理中のリクエストの完了を待っています")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// 期限内に完了しなかったリクエストの接続は切断する
+		srv.Close()
+		return fmt.Errorf("failed to shut down gracefully: %w", err)
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	logger.Info("処理中のリクエストがすべて完了しました")
+	return nil
+}
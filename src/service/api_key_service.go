@@ -0,0 +1,113 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"memo-app/src/models"
+	"memo-app/src/repository"
+)
+
+// APIKeyPrefix 発行するAPIキーの接頭辞
+const APIKeyPrefix = "memo_"
+
+// apiKeySecretBytes APIキーのランダム部分のバイト数
+const apiKeySecretBytes = 32
+
+// apiKeyDisplayPrefixLen 一覧表示用に保存するキー先頭部分の長さ
+const apiKeyDisplayPrefixLen = 12
+
+// APIKeyService APIキーサービスのインターフェース
+type APIKeyService interface {
+	CreateAPIKey(userID int, name string) (*models.CreateAPIKeyResponse, error)
+	ListAPIKeys(userID int) ([]*models.APIKey, error)
+	RevokeAPIKey(userID, keyID int) error
+	// ValidateAPIKey 平文のAPIキーを検証し、所有ユーザーのIDを返す
+	ValidateAPIKey(rawKey string) (int, error)
+}
+
+// apiKeyService APIキーサービスの実装
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+// NewAPIKeyService APIキーサービスを作成
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{apiKeyRepo: apiKeyRepo}
+}
+
+// CreateAPIKey APIキーを発行（平文のキーは戻り値でのみ返し、ハッシュのみ保存する）
+func (s *apiKeyService) CreateAPIKey(userID int, name string) (*models.CreateAPIKeyResponse, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("api key name is required")
+	}
+
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	rawKey := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := &models.APIKey{
+		UserID:    userID,
+		Name:      name,
+		KeyPrefix: rawKey[:apiKeyDisplayPrefixLen],
+		KeyHash:   HashAPIKey(rawKey),
+	}
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return &models.CreateAPIKeyResponse{
+		APIKey: key,
+		Key:    rawKey,
+	}, nil
+}
+
+// ListAPIKeys ユーザーのAPIキー一覧を取得
+func (s *apiKeyService) ListAPIKeys(userID int) ([]*models.APIKey, error) {
+	keys, err := s.apiKeyRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey ユーザーのAPIキーを失効させる
+func (s *apiKeyService) RevokeAPIKey(userID, keyID int) error {
+	return s.apiKeyRepo.Revoke(userID, keyID)
+}
+
+// ValidateAPIKey APIキーを検証
+func (s *apiKeyService) ValidateAPIKey(rawKey string) (int, error) {
+	if !strings.HasPrefix(rawKey, APIKeyPrefix) {
+		return 0, fmt.Errorf("invalid api key")
+	}
+
+	key, err := s.apiKeyRepo.GetByHash(HashAPIKey(rawKey))
+	if err != nil {
+		return 0, fmt.Errorf("invalid api key")
+	}
+	if key.IsRevoked() {
+		return 0, fmt.Errorf("api key revoked")
+	}
+
+	// 最終使用日時の更新失敗は認証結果に影響させない
+	if err := s.apiKeyRepo.UpdateLastUsed(key.ID); err != nil {
+		fmt.Printf("Warning: failed to update api key last used: %v\n", err)
+	}
+
+	return key.UserID, nil
+}
+
+// HashAPIKey APIキーの保存用ハッシュを計算
+// キーは十分なエントロピーを持つため、bcryptではなく検索可能なSHA-256を使用する
+func HashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
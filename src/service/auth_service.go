@@ -1,9 +1,13 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,11 +15,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 
 	"memo-app/src/config"
+	"memo-app/src/mailer"
 	"memo-app/src/models"
 	"memo-app/src/repository"
+	"memo-app/src/validator"
 )
 
 // AuthService 認証サービスのインターフェース
@@ -34,26 +41,84 @@ type AuthService interface {
 
 	// IP制限チェック
 	CheckIPLimit(clientIP string) error
+
+	// メールアドレス確認
+	VerifyEmail(token string) error
+
+	// パスワード再設定
+	RequestPasswordReset(email string) error
+	ResetPassword(token, newPassword string) error
+
+	// パスワード変更（ログイン中のユーザー）
+	ChangePassword(userID int, currentPassword, newPassword string) error
+
+	// 全端末からのログアウト
+	LogoutAll(userID int) error
+
+	// ログイン中のユーザーのプロフィール
+	GetProfile(userID int) (*models.User, error)
+	UpdateProfile(userID int, req *models.UpdateProfileRequest) (*models.User, error)
+
+	// アカウント削除（ローカル認証のアカウントはパスワードの再入力が必要）
+	DeleteAccount(userID int, password string) error
 }
 
 // authService 認証サービスの実装
 type authService struct {
-	userRepo   repository.UserRepository
-	jwtService JWTService
-	config     *config.Config
+	userRepo    repository.UserRepository
+	jwtService  JWTService
+	config      *config.Config
+	mailer      mailer.Mailer
+	oauthClient *OAuthHTTPClient
+	sessionRepo repository.SessionRepository       // nilの場合はリフレッシュトークンを永続化しない
+	resetRepo   repository.PasswordResetRepository // nilの場合はパスワード再設定を利用できない
+	validator   *validator.CustomValidator
+	loginLimit  *LoginThrottle
 }
 
-// NewAuthService 認証サービスを作成
+// NewAuthService 認証サービスを作成（メールはログ出力のみ）
 func NewAuthService(userRepo repository.UserRepository, jwtService JWTService, cfg *config.Config) AuthService {
+	return NewAuthServiceWithMailer(userRepo, jwtService, cfg, mailer.NewLogMailer(nil))
+}
+
+// NewAuthServiceWithMailer メール送信に使用するMailerを指定して認証サービスを作成
+func NewAuthServiceWithMailer(userRepo repository.UserRepository, jwtService JWTService, cfg *config.Config, m mailer.Mailer) AuthService {
+	return NewAuthServiceWithSessions(userRepo, jwtService, cfg, m, nil)
+}
+
+// NewAuthServiceWithSessions リフレッシュトークンを永続化するセッションストアを指定して認証サービスを作成
+// セッションストアを指定するとリフレッシュトークンの失効とユーザーごとのセッション数上限が有効になる
+func NewAuthServiceWithSessions(userRepo repository.UserRepository, jwtService JWTService, cfg *config.Config, m mailer.Mailer, sessionRepo repository.SessionRepository) AuthService {
+	return NewAuthServiceWithPasswordResets(userRepo, jwtService, cfg, m, sessionRepo, nil)
+}
+
+// NewAuthServiceWithPasswordResets パスワード再設定トークンのストアを指定して認証サービスを作成
+func NewAuthServiceWithPasswordResets(userRepo repository.UserRepository, jwtService JWTService, cfg *config.Config, m mailer.Mailer, sessionRepo repository.SessionRepository, resetRepo repository.PasswordResetRepository) AuthService {
 	return &authService{
-		userRepo:   userRepo,
-		jwtService: jwtService,
-		config:     cfg,
+		userRepo:    userRepo,
+		jwtService:  jwtService,
+		config:      cfg,
+		mailer:      m,
+		oauthClient: NewOAuthHTTPClient(&http.Client{Timeout: 10 * time.Second}, cfg.Auth.OAuthMaxRetries, cfg.Auth.OAuthRetryBackoff),
+		sessionRepo: sessionRepo,
+		resetRepo:   resetRepo,
+		validator:   validator.NewUsernameValidator(cfg.Auth.ReservedUsernames),
+		loginLimit: NewLoginThrottle(LoginThrottleConfig{
+			MaxAttempts:      cfg.Auth.LoginMaxAttempts,
+			MaxAttemptsPerIP: cfg.Auth.LoginMaxAttemptsPerIP,
+			Window:           cfg.Auth.LoginAttemptWindow,
+			Lockout:          cfg.Auth.LoginLockoutDuration,
+		}),
 	}
 }
 
 // Register 新規ユーザー登録（ローカル認証）
 func (s *authService) Register(req *models.RegisterRequest, clientIP string) (*models.AuthResponse, error) {
+	// 予約語（デフォルト + RESERVED_USERNAMES）のユーザー名は登録できない
+	if s.validator.IsReservedUsername(req.Username) {
+		return nil, fmt.Errorf("username is reserved")
+	}
+
 	// IP制限チェック
 	if err := s.CheckIPLimit(clientIP); err != nil {
 		return nil, err
@@ -102,15 +167,28 @@ func (s *authService) Register(req *models.RegisterRequest, clientIP string) (*m
 		fmt.Printf("Warning: failed to update IP registration: %v\n", err)
 	}
 
+	// 確認メールを送信
+	if err := s.sendVerificationEmail(user); err != nil {
+		// ログに記録するが、エラーで失敗させない
+		fmt.Printf("Warning: failed to send verification email: %v\n", err)
+	}
+
 	// トークン生成
 	return s.generateAuthResponse(user)
 }
 
 // Login ユーザーログイン（ローカル認証）
+// 認証情報の誤りが続いたメールアドレス・IPからのログインは一定期間拒否する
 func (s *authService) Login(req *models.LoginRequest, clientIP string) (*models.AuthResponse, error) {
+	// ログイン試行制限チェック
+	if err := s.loginLimit.Check(req.Email, clientIP); err != nil {
+		return nil, err
+	}
+
 	// ユーザー取得
 	user, err := s.userRepo.GetByEmail(req.Email)
 	if err != nil {
+		s.loginLimit.RecordFailure(req.Email, clientIP)
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
@@ -125,8 +203,10 @@ func (s *authService) Login(req *models.LoginRequest, clientIP string) (*models.
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte(req.Password)); err != nil {
+		s.loginLimit.RecordFailure(req.Email, clientIP)
 		return nil, fmt.Errorf("invalid credentials")
 	}
+	s.loginLimit.RecordSuccess(req.Email, clientIP)
 
 	// 最終ログイン時刻更新
 	if err := s.userRepo.UpdateLastLogin(user.ID); err != nil {
@@ -141,7 +221,7 @@ func (s *authService) Login(req *models.LoginRequest, clientIP string) (*models.
 // GetGitHubAuthURL GitHub認証URLを取得
 func (s *authService) GetGitHubAuthURL(state string) string {
 	// GitHub OAuth2 URLを手動で構築
-	baseURL := "https://github.com/login/oauth/authorize"
+	baseURL := s.config.Auth.GitHubOAuthBaseURL + "/login/oauth/authorize"
 	return fmt.Sprintf("%s?client_id=%s&redirect_uri=%s&scope=%s&state=%s",
 		baseURL,
 		s.config.Auth.GitHubClientID,
@@ -252,6 +332,10 @@ func (s *authService) ValidateToken(tokenString string) (*models.User, error) {
 		return nil, fmt.Errorf("account is deactivated")
 	}
 
+	if claims.TokenVersion < user.TokenVersion {
+		return nil, fmt.Errorf("invalid token: token revoked")
+	}
+
 	return user, nil
 }
 
@@ -271,17 +355,38 @@ func (s *authService) RefreshToken(refreshToken string) (*models.AuthResponse, e
 		return nil, fmt.Errorf("account is deactivated")
 	}
 
+	// 全端末からのログアウト以前に発行されたトークンは無効
+	if claims.TokenVersion < user.TokenVersion {
+		return nil, fmt.Errorf("invalid refresh token: token revoked")
+	}
+
+	// 永続化されたセッションがある場合は失効済みでないか確認し、使用済みのトークンは失効させる（ローテーション）
+	if s.sessionRepo != nil {
+		session, err := s.sessionRepo.GetByTokenHash(HashToken(refreshToken))
+		if err != nil || !session.IsActive(time.Now()) || session.UserID != user.ID {
+			return nil, fmt.Errorf("invalid refresh token: session revoked")
+		}
+		if err := s.sessionRepo.Revoke(session.ID); err != nil {
+			return nil, fmt.Errorf("failed to rotate session: %w", err)
+		}
+	}
+
 	return s.generateAuthResponse(user)
 }
 
 // CheckIPLimit IP制限をチェック
+// IP_REGISTRATION_WINDOW の期間内の登録数のみ数えるため、共有NATのIPも期間が過ぎれば再び登録できる
 func (s *authService) CheckIPLimit(clientIP string) error {
-	// 現在のユーザー数を取得
-	currentCount, err := s.userRepo.GetUserCountByIP(clientIP)
+	ipReg, err := s.userRepo.GetIPRegistration(clientIP)
 	if err != nil {
 		return fmt.Errorf("failed to check IP limit: %w", err)
 	}
 
+	currentCount := 0
+	if ipReg != nil {
+		currentCount = ipReg.CountWithin(s.config.Auth.IPRegistrationWindow, time.Now())
+	}
+
 	if currentCount >= s.config.Auth.MaxAccountsPerIP {
 		return fmt.Errorf("maximum number of accounts per IP address exceeded")
 	}
@@ -289,18 +394,349 @@ func (s *authService) CheckIPLimit(clientIP string) error {
 	return nil
 }
 
+// VerifyEmail 確認トークンを検証してメールアドレスを確認済みにする
+// トークンは期限付きで、確認済みになった時点で再利用できなくなる
+func (s *authService) VerifyEmail(token string) error {
+	claims, err := s.jwtService.ValidateEmailVerificationToken(token)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return fmt.Errorf("verification token expired")
+		}
+		return fmt.Errorf("invalid verification token: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return fmt.Errorf("invalid verification token: %w", err)
+	}
+
+	// 発行後にメールアドレスが変更された場合は無効
+	if !strings.EqualFold(user.Email, claims.Email) {
+		return fmt.Errorf("invalid verification token: email mismatch")
+	}
+
+	if user.EmailVerified {
+		return fmt.Errorf("verification token already used")
+	}
+
+	if err := s.userRepo.MarkEmailVerified(user.ID); err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return nil
+}
+
+// RequestPasswordReset パスワード再設定用のトークンを発行してメールで送信
+// メールアドレスが登録されているかを推測されないよう、該当するユーザーがいない場合もエラーにしない
+func (s *authService) RequestPasswordReset(email string) error {
+	if s.resetRepo == nil {
+		return fmt.Errorf("password reset is not available")
+	}
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil || !user.IsActive || user.PasswordHash == nil {
+		// 未登録・無効・外部認証のアカウントには送信しない
+		return nil
+	}
+
+	// 以前に発行した未使用のトークンは無効にする
+	if err := s.resetRepo.InvalidateByUserID(user.ID); err != nil {
+		return fmt.Errorf("failed to invalidate password resets: %w", err)
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+
+	reset := &models.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: HashToken(token),
+		ExpiresAt: time.Now().Add(s.config.Auth.PasswordResetExpiresIn),
+	}
+	if err := s.resetRepo.Create(reset); err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+
+	link := strings.TrimRight(s.config.Server.BaseURL, "/") + "/reset-password?token=" + url.QueryEscape(token)
+
+	if err := mailer.SendTemplate(context.Background(), s.mailer, user.Email, mailer.PasswordResetTemplate, map[string]string{
+		"Username":  user.Username,
+		"Link":      link,
+		"ExpiresIn": s.config.Auth.PasswordResetExpiresIn.String(),
+	}); err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword トークンを検証して新しいパスワードを設定する
+// トークンは1回のみ使用でき、成功した時点でそのユーザーの未使用のトークンもすべて無効になる
+func (s *authService) ResetPassword(token, newPassword string) error {
+	if s.resetRepo == nil {
+		return fmt.Errorf("password reset is not available")
+	}
+
+	if err := s.validator.Validate(&models.ResetPasswordRequest{Token: token, NewPassword: newPassword}); err != nil {
+		return fmt.Errorf("weak password: %w", err)
+	}
+
+	reset, err := s.resetRepo.GetByTokenHash(HashToken(token))
+	if err != nil {
+		return fmt.Errorf("invalid password reset token")
+	}
+	if reset.UsedAt != nil {
+		return fmt.Errorf("password reset token already used")
+	}
+	if !reset.IsUsable(time.Now()) {
+		return fmt.Errorf("password reset token expired")
+	}
+
+	user, err := s.userRepo.GetByID(reset.UserID)
+	if err != nil || !user.IsActive {
+		return fmt.Errorf("invalid password reset token")
+	}
+
+	// 先に使用済みにして、同じトークンでの同時リクエストは1件だけ成功させる
+	if err := s.resetRepo.MarkUsed(reset.ID); err != nil {
+		return fmt.Errorf("password reset token already used")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.PasswordHash = stringPtr(string(hashedPassword))
+	if err := s.userRepo.Update(user); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.resetRepo.InvalidateByUserID(user.ID); err != nil {
+		// パスワードは更新済みのため失敗させない
+		fmt.Printf("Warning: failed to invalidate password resets: %v\n", err)
+	}
+	return nil
+}
+
+// ChangePassword 現在のパスワードを確認して新しいパスワードに変更する
+// 成功した場合はそのユーザーのセッション（リフレッシュトークン）と未使用のパスワード再設定トークンを無効にする
+func (s *authService) ChangePassword(userID int, currentPassword, newPassword string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	// GitHub認証のみのアカウントにはパスワードがない
+	if user.PasswordHash == nil {
+		return fmt.Errorf("this account uses external authentication")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte(currentPassword)); err != nil {
+		return fmt.Errorf("invalid current password")
+	}
+
+	if err := s.validator.Validate(&models.ChangePasswordRequest{CurrentPassword: currentPassword, NewPassword: newPassword}); err != nil {
+		return fmt.Errorf("weak password: %w", err)
+	}
+	if newPassword == currentPassword {
+		return fmt.Errorf("new password must differ from the current password")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.PasswordHash = stringPtr(string(hashedPassword))
+	if err := s.userRepo.Update(user); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	// パスワードは更新済みのため、無効化に失敗しても失敗させない
+	if err := s.revokeSessions(user.ID); err != nil {
+		fmt.Printf("Warning: failed to revoke sessions: %v\n", err)
+	}
+	if s.resetRepo != nil {
+		if err := s.resetRepo.InvalidateByUserID(user.ID); err != nil {
+			fmt.Printf("Warning: failed to invalidate password resets: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// LogoutAll ユーザーのトークンバージョンを進め、発行済みのアクセストークン・リフレッシュトークンをすべて無効にする
+func (s *authService) LogoutAll(userID int) error {
+	if _, err := s.userRepo.IncrementTokenVersion(userID); err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	// トークンはバージョンで無効になっているため、セッションの失効に失敗しても失敗させない
+	if err := s.revokeSessions(userID); err != nil {
+		fmt.Printf("Warning: failed to revoke sessions: %v\n", err)
+	}
+	return nil
+}
+
+// GetProfile ログイン中のユーザーをDBから取得
+func (s *authService) GetProfile(userID int) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	return user, nil
+}
+
+// UpdateProfile ユーザー名・メールアドレスを更新
+// 重複チェックは値が変わる場合のみ行う（自分自身の行とは重複扱いしない）
+func (s *authService) UpdateProfile(userID int, req *models.UpdateProfileRequest) (*models.User, error) {
+	switch {
+	case req.GitHubID != nil:
+		return nil, fmt.Errorf("read-only field: github_id")
+	case req.GitHubUsername != nil:
+		return nil, fmt.Errorf("read-only field: github_username")
+	case req.AvatarURL != nil:
+		return nil, fmt.Errorf("read-only field: avatar_url")
+	}
+
+	if err := s.validator.Validate(req); err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if req.Username != nil && *req.Username != user.Username {
+		exists, err := s.userRepo.IsUsernameExists(*req.Username)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check username existence: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("username already exists")
+		}
+		user.Username = *req.Username
+	}
+
+	emailChanged := false
+	if req.Email != nil && !strings.EqualFold(*req.Email, user.Email) {
+		exists, err := s.userRepo.IsEmailExists(*req.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check email existence: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("email already exists")
+		}
+		emailChanged = true
+	}
+	if req.Email != nil {
+		user.Email = *req.Email
+	}
+	if emailChanged && s.config.Auth.ReverifyEmailOnChange {
+		user.EmailVerified = false
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	// 新しいメールアドレスに確認メールを送信
+	if emailChanged && s.config.Auth.ReverifyEmailOnChange {
+		if err := s.sendVerificationEmail(user); err != nil {
+			// ログに記録するが、エラーで失敗させない
+			fmt.Printf("Warning: failed to send verification email: %v\n", err)
+		}
+	}
+
+	return user, nil
+}
+
+// DeleteAccount ユーザーとそのメモを削除
+// 削除前にトークンバージョンを進めるため、削除に失敗しても発行済みのトークンは使えなくなる
+func (s *authService) DeleteAccount(userID int, password string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	// GitHub認証のみのアカウントにはパスワードがない
+	if user.PasswordHash != nil {
+		if password == "" {
+			return fmt.Errorf("password required")
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte(password)); err != nil {
+			return fmt.Errorf("invalid password")
+		}
+	}
+
+	if _, err := s.userRepo.IncrementTokenVersion(user.ID); err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	if err := s.userRepo.Delete(user.ID); err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	return nil
+}
+
+// revokeSessions ユーザーの有効なセッションをすべて失効させる
+func (s *authService) revokeSessions(userID int) error {
+	if s.sessionRepo == nil {
+		return nil
+	}
+
+	sessions, err := s.sessionRepo.ListActiveByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	for _, session := range sessions {
+		if err := s.sessionRepo.Revoke(session.ID); err != nil {
+			return fmt.Errorf("failed to revoke session: %w", err)
+		}
+	}
+	return nil
+}
+
+// generateResetToken パスワード再設定用のランダムなトークンを生成
+func generateResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// sendVerificationEmail 確認リンクを含むメールを送信
+func (s *authService) sendVerificationEmail(user *models.User) error {
+	token, err := s.jwtService.GenerateEmailVerificationToken(user.ID, user.Email)
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	link := strings.TrimRight(s.config.Server.BaseURL, "/") + "/api/auth/verify?token=" + url.QueryEscape(token)
+
+	return mailer.SendTemplate(context.Background(), s.mailer, user.Email, mailer.EmailVerificationTemplate, map[string]string{
+		"Username":  user.Username,
+		"Link":      link,
+		"ExpiresIn": s.config.Auth.EmailVerificationExpiresIn.String(),
+	})
+}
+
 // generateAuthResponse 認証レスポンスを生成
 func (s *authService) generateAuthResponse(user *models.User) (*models.AuthResponse, error) {
-	accessToken, err := s.jwtService.GenerateAccessToken(user.ID)
+	accessToken, err := s.jwtService.GenerateAccessTokenWithRole(user.ID, user.TokenVersion, user.Role)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	refreshToken, err := s.jwtService.GenerateRefreshToken(user.ID)
+	refreshToken, err := s.jwtService.GenerateRefreshTokenWithVersion(user.ID, user.TokenVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
+	if err := s.startSession(user.ID, refreshToken); err != nil {
+		return nil, err
+	}
+
 	return &models.AuthResponse{
 		User:         user.ToPublic(),
 		AccessToken:  accessToken,
@@ -309,6 +745,46 @@ func (s *authService) generateAuthResponse(user *models.User) (*models.AuthRespo
 	}, nil
 }
 
+// startSession リフレッシュトークンをセッションとして保存し、上限を超えた古いセッションを失効させる
+func (s *authService) startSession(userID int, refreshToken string) error {
+	if s.sessionRepo == nil {
+		return nil
+	}
+
+	session := &models.RefreshSession{
+		UserID:    userID,
+		TokenHash: HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.config.Auth.RefreshExpiresIn),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	maxSessions := s.config.Auth.MaxSessionsPerUser
+	if maxSessions <= 0 {
+		return nil
+	}
+
+	sessions, err := s.sessionRepo.ListActiveByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	// 古い順に並んでいるため、先頭から超過分を失効させる
+	for i := 0; i < len(sessions)-maxSessions; i++ {
+		if err := s.sessionRepo.Revoke(sessions[i].ID); err != nil {
+			return fmt.Errorf("failed to revoke session: %w", err)
+		}
+	}
+	return nil
+}
+
+// HashToken セッション保存用にリフレッシュトークンのSHA-256ハッシュを計算
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // updateIPRegistration IP登録情報を更新
 func (s *authService) updateIPRegistration(clientIP string) error {
 	ipReg, err := s.userRepo.GetIPRegistration(clientIP)
@@ -316,25 +792,31 @@ func (s *authService) updateIPRegistration(clientIP string) error {
 		return err
 	}
 
+	now := time.Now()
 	if ipReg == nil {
 		// 新規作成
 		ipReg = &models.IPRegistration{
-			IPAddress:  clientIP,
-			UserCount:  1,
-			LastUsedAt: time.Now(),
+			IPAddress:       clientIP,
+			UserCount:       1,
+			LastUsedAt:      now,
+			WindowStartedAt: now,
 		}
 		return s.userRepo.CreateIPRegistration(ipReg)
-	} else {
-		// 更新
-		ipReg.UserCount++
-		ipReg.LastUsedAt = time.Now()
-		return s.userRepo.UpdateIPRegistration(ipReg)
 	}
+
+	// 期間を過ぎていれば、この登録から数え直す
+	if ipReg.CountWithin(s.config.Auth.IPRegistrationWindow, now) == 0 {
+		ipReg.UserCount = 0
+		ipReg.WindowStartedAt = now
+	}
+	ipReg.UserCount++
+	ipReg.LastUsedAt = now
+	return s.userRepo.UpdateIPRegistration(ipReg)
 }
 
 // getGitHubUser GitHubユーザー情報を取得
 func (s *authService) getGitHubUser(accessToken string) (*models.GitHubUser, error) {
-	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
+	req, err := http.NewRequest("GET", s.config.Auth.GitHubAPIBaseURL+"/user", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -342,8 +824,7 @@ func (s *authService) getGitHubUser(accessToken string) (*models.GitHubUser, err
 	req.Header.Set("Authorization", "token "+accessToken)
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := s.oauthClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -376,7 +857,7 @@ func (s *authService) getGitHubUser(accessToken string) (*models.GitHubUser, err
 
 // getGitHubUserEmails GitHubユーザーのメールアドレスを取得
 func (s *authService) getGitHubUserEmails(accessToken string) ([]string, error) {
-	req, err := http.NewRequest("GET", "https://api.github.com/user/emails", nil)
+	req, err := http.NewRequest("GET", s.config.Auth.GitHubAPIBaseURL+"/user/emails", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -384,8 +865,7 @@ func (s *authService) getGitHubUserEmails(accessToken string) ([]string, error)
 	req.Header.Set("Authorization", "token "+accessToken)
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := s.oauthClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -431,7 +911,7 @@ func GenerateRandomState() string {
 
 // exchangeCodeForToken GitHubのコードをアクセストークンに交換
 func (s *authService) exchangeCodeForToken(code string) (string, error) {
-	tokenURL := "https://github.com/login/oauth/access_token"
+	tokenURL := s.config.Auth.GitHubOAuthBaseURL + "/login/oauth/access_token"
 
 	data := url.Values{}
 	data.Set("client_id", s.config.Auth.GitHubClientID)
@@ -446,8 +926,7 @@ func (s *authService) exchangeCodeForToken(code string) (string, error) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := s.oauthClient.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -1,10 +1,14 @@
 package service
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
 	"memo-app/src/config"
+	"memo-app/src/repository"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -14,6 +18,10 @@ type JWTClaims struct {
 	UserID int    `json:"user_id"`
 	Email  string `json:"email"`
 	Type   string `json:"type"` // "access" or "refresh"
+	// TokenVersion 発行時のユーザーのトークンバージョン（ユーザーのバージョンより古いトークンは無効）
+	TokenVersion int `json:"token_version"`
+	// Role 発行時のユーザーのロール（クライアントの表示用。認可には常にDBのロールを使う）
+	Role string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -21,32 +29,67 @@ type JWTClaims struct {
 type JWTService interface {
 	GenerateAccessToken(userID int) (string, error)
 	GenerateRefreshToken(userID int) (string, error)
+	GenerateAccessTokenWithVersion(userID, tokenVersion int) (string, error)
+	GenerateAccessTokenWithRole(userID, tokenVersion int, role string) (string, error)
+	GenerateRefreshTokenWithVersion(userID, tokenVersion int) (string, error)
 	ValidateToken(tokenString string) (*JWTClaims, error)
 	ValidateAccessToken(tokenString string) (int, error)
 	ValidateRefreshToken(tokenString string) (*JWTClaims, error)
+	GenerateEmailVerificationToken(userID int, email string) (string, error)
+	ValidateEmailVerificationToken(tokenString string) (*JWTClaims, error)
+
+	// トークンの失効（ログアウト等）
+	InvalidateToken(tokenString string) error
+	IsTokenInvalidated(jti string) (bool, error)
 }
 
 // jwtService JWT管理サービスの実装
 type jwtService struct {
-	config *config.Config
+	config        *config.Config
+	revokedTokens repository.RevokedTokenRepository // nilの場合はトークン単位の失効を利用できない
 }
 
 // NewJWTService JWT管理サービスを作成
 func NewJWTService(cfg *config.Config) JWTService {
-	return &jwtService{config: cfg}
+	return NewJWTServiceWithRevocation(cfg, nil)
+}
+
+// NewJWTServiceWithRevocation 失効させたトークンのストアを指定してJWT管理サービスを作成
+// 失効の記録はDBに保存されるため、再起動後も失効したトークンは使用できない
+func NewJWTServiceWithRevocation(cfg *config.Config, revokedTokens repository.RevokedTokenRepository) JWTService {
+	return &jwtService{config: cfg, revokedTokens: revokedTokens}
 }
 
-// GenerateAccessToken アクセストークンを生成
+// GenerateAccessToken アクセストークンを生成（トークンバージョン0）
 func (s *jwtService) GenerateAccessToken(userID int) (string, error) {
+	return s.GenerateAccessTokenWithVersion(userID, 0)
+}
+
+// GenerateAccessTokenWithVersion ユーザーのトークンバージョンを含むアクセストークンを生成（ロールなし）
+func (s *jwtService) GenerateAccessTokenWithVersion(userID, tokenVersion int) (string, error) {
+	return s.GenerateAccessTokenWithRole(userID, tokenVersion, "")
+}
+
+// GenerateAccessTokenWithRole ユーザーのトークンバージョンとロールを含むアクセストークンを生成
+// トークン単位で失効できるよう jti を付与する
+func (s *jwtService) GenerateAccessTokenWithRole(userID, tokenVersion int, role string) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
 	claims := &JWTClaims{
-		UserID: userID,
-		Type:   "access",
+		UserID:       userID,
+		Type:         "access",
+		TokenVersion: tokenVersion,
+		Role:         role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.Auth.JWTExpiresIn)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "memo-app",
 			Subject:   fmt.Sprintf("user:%d", userID),
+			ID:        jti,
 		},
 	}
 
@@ -54,17 +97,30 @@ func (s *jwtService) GenerateAccessToken(userID int) (string, error) {
 	return token.SignedString([]byte(s.config.Auth.JWTSecret))
 }
 
-// GenerateRefreshToken リフレッシュトークンを生成
+// GenerateRefreshToken リフレッシュトークンを生成（トークンバージョン0）
 func (s *jwtService) GenerateRefreshToken(userID int) (string, error) {
+	return s.GenerateRefreshTokenWithVersion(userID, 0)
+}
+
+// GenerateRefreshTokenWithVersion ユーザーのトークンバージョンを含むリフレッシュトークンを生成
+// 同一秒内に発行されても区別できるよう jti を付与する
+func (s *jwtService) GenerateRefreshTokenWithVersion(userID, tokenVersion int) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
 	claims := &JWTClaims{
-		UserID: userID,
-		Type:   "refresh",
+		UserID:       userID,
+		Type:         "refresh",
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.Auth.RefreshExpiresIn)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "memo-app",
 			Subject:   fmt.Sprintf("user:%d", userID),
+			ID:        jti,
 		},
 	}
 
@@ -72,6 +128,15 @@ func (s *jwtService) GenerateRefreshToken(userID int) (string, error) {
 	return token.SignedString([]byte(s.config.Auth.JWTSecret))
 }
 
+// newTokenID トークン識別子（jti）を生成
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
 // ValidateToken アクセストークンを検証
 func (s *jwtService) ValidateToken(tokenString string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
@@ -89,6 +154,9 @@ func (s *jwtService) ValidateToken(tokenString string) (*JWTClaims, error) {
 		if claims.Type != "access" {
 			return nil, fmt.Errorf("invalid token type")
 		}
+		if err := s.checkNotInvalidated(claims); err != nil {
+			return nil, err
+		}
 		return claims, nil
 	}
 
@@ -112,6 +180,9 @@ func (s *jwtService) ValidateRefreshToken(tokenString string) (*JWTClaims, error
 		if claims.Type != "refresh" {
 			return nil, fmt.Errorf("invalid token type")
 		}
+		if err := s.checkNotInvalidated(claims); err != nil {
+			return nil, err
+		}
 		return claims, nil
 	}
 
@@ -135,8 +206,104 @@ func (s *jwtService) ValidateAccessToken(tokenString string) (int, error) {
 		if claims.Type != "access" {
 			return 0, fmt.Errorf("invalid token type")
 		}
+		if err := s.checkNotInvalidated(claims); err != nil {
+			return 0, err
+		}
 		return claims.UserID, nil
 	}
 
 	return 0, fmt.Errorf("invalid access token")
 }
+
+// GenerateEmailVerificationToken メールアドレス確認用トークンを生成
+func (s *jwtService) GenerateEmailVerificationToken(userID int, email string) (string, error) {
+	claims := &JWTClaims{
+		UserID: userID,
+		Email:  email,
+		Type:   "email_verification",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.Auth.EmailVerificationExpiresIn)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    "memo-app",
+			Subject:   fmt.Sprintf("user:%d", userID),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.config.Auth.JWTSecret))
+}
+
+// ValidateEmailVerificationToken メールアドレス確認用トークンを検証
+func (s *jwtService) ValidateEmailVerificationToken(tokenString string) (*JWTClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(s.config.Auth.JWTSecret), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+		if claims.Type != "email_verification" {
+			return nil, fmt.Errorf("invalid token type")
+		}
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid verification token")
+}
+
+// InvalidateToken アクセストークンまたはリフレッシュトークンを有効期限まで失効させる
+// 既に期限切れのトークンは検証に失敗するため何もしない
+func (s *jwtService) InvalidateToken(tokenString string) error {
+	if s.revokedTokens == nil {
+		return fmt.Errorf("token revocation is not available")
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(s.config.Auth.JWTSecret), nil
+	})
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil
+		}
+		return fmt.Errorf("invalid token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid || (claims.Type != "access" && claims.Type != "refresh") {
+		return fmt.Errorf("invalid token")
+	}
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return fmt.Errorf("invalid token: token cannot be revoked individually")
+	}
+
+	return s.revokedTokens.Revoke(claims.ID, claims.ExpiresAt.Time)
+}
+
+// IsTokenInvalidated jti のトークンが失効しているか確認
+func (s *jwtService) IsTokenInvalidated(jti string) (bool, error) {
+	if s.revokedTokens == nil || jti == "" {
+		return false, nil
+	}
+	return s.revokedTokens.IsRevoked(jti)
+}
+
+// checkNotInvalidated 失効したトークンの場合はエラーを返す（確認に失敗した場合も拒否する）
+func (s *jwtService) checkNotInvalidated(claims *JWTClaims) error {
+	invalidated, err := s.IsTokenInvalidated(claims.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	if invalidated {
+		return fmt.Errorf("token has been revoked")
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LoginThrottleConfig ログイン試行制限の設定
+type LoginThrottleConfig struct {
+	MaxAttempts      int           // 同じメールアドレス・IPの組で許容する失敗回数（0は無制限）
+	MaxAttemptsPerIP int           // 同じIPから許容する失敗回数（メールアドレスを問わない、0は無制限）
+	Window           time.Duration // 失敗回数を数える期間
+	Lockout          time.Duration // 上限に達した後にログインを拒否する期間
+}
+
+// LoginThrottledError 失敗回数の上限に達したためログインを拒否したエラー
+type LoginThrottledError struct {
+	RetryAfter time.Duration // ログインを再試行できるまでの時間
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many login attempts, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+// loginAttempt キー1件分の失敗回数
+type loginAttempt struct {
+	failures    int
+	windowStart time.Time
+	lockedUntil time.Time
+}
+
+// LoginThrottle メールアドレス・IPごとにログインの失敗回数を数え、上限に達したキーを一定期間ロックする
+// メールアドレスはIPと組にして数えるため、別のIPの攻撃者が被害者のアカウントをロックし続けることはできない
+type LoginThrottle struct {
+	config LoginThrottleConfig
+	now    func() time.Time
+
+	mu          sync.Mutex
+	attempts    map[string]*loginAttempt
+	lastCleanup time.Time
+}
+
+// NewLoginThrottle ログイン試行制限を作成
+func NewLoginThrottle(cfg LoginThrottleConfig) *LoginThrottle {
+	return NewLoginThrottleWithClock(cfg, time.Now)
+}
+
+// NewLoginThrottleWithClock 現在時刻の取得関数を指定してログイン試行制限を作成（テスト用）
+func NewLoginThrottleWithClock(cfg LoginThrottleConfig, now func() time.Time) *LoginThrottle {
+	return &LoginThrottle{
+		config:      cfg,
+		now:         now,
+		attempts:    make(map[string]*loginAttempt),
+		lastCleanup: now(),
+	}
+}
+
+// Check ロック中の場合は LoginThrottledError を返す
+func (t *LoginThrottle) Check(email, clientIP string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.cleanupLocked(now)
+
+	var retryAfter time.Duration
+	for _, key := range t.keys(email, clientIP) {
+		if attempt, ok := t.attempts[key]; ok && attempt.lockedUntil.After(now) {
+			if wait := attempt.lockedUntil.Sub(now); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+	}
+	if retryAfter > 0 {
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure 失敗を記録し、上限に達したキーをロックする
+func (t *LoginThrottle) RecordFailure(email, clientIP string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	limits := map[string]int{
+		emailKey(email, clientIP): t.config.MaxAttempts,
+		ipKey(clientIP):           t.config.MaxAttemptsPerIP,
+	}
+	for key, limit := range limits {
+		if limit <= 0 {
+			continue
+		}
+
+		attempt, ok := t.attempts[key]
+		if !ok || now.Sub(attempt.windowStart) > t.config.Window {
+			attempt = &loginAttempt{windowStart: now, lockedUntil: attemptLockedUntil(attempt)}
+			t.attempts[key] = attempt
+		}
+		attempt.failures++
+		if attempt.failures >= limit {
+			attempt.lockedUntil = now.Add(t.config.Lockout)
+			attempt.failures = 0
+			attempt.windowStart = now
+		}
+	}
+}
+
+// RecordSuccess ログインに成功したメールアドレス・IPの組の失敗回数を消す
+// IP単位の失敗回数は、攻撃者が自分のアカウントでログインして消せないよう期間の経過まで残す
+func (t *LoginThrottle) RecordSuccess(email, clientIP string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.attempts, emailKey(email, clientIP))
+}
+
+// keys 確認対象のキー（上限が0のものは除く）
+func (t *LoginThrottle) keys(email, clientIP string) []string {
+	var keys []string
+	if t.config.MaxAttempts > 0 {
+		keys = append(keys, emailKey(email, clientIP))
+	}
+	if t.config.MaxAttemptsPerIP > 0 {
+		keys = append(keys, ipKey(clientIP))
+	}
+	return keys
+}
+
+// cleanupLocked 期間を過ぎてロックもされていないキーを破棄（t.mu を保持して呼ぶ）
+func (t *LoginThrottle) cleanupLocked(now time.Time) {
+	if now.Sub(t.lastCleanup) < t.config.Window {
+		return
+	}
+	t.lastCleanup = now
+
+	for key, attempt := range t.attempts {
+		if now.Sub(attempt.windowStart) > t.config.Window && !attempt.lockedUntil.After(now) {
+			delete(t.attempts, key)
+		}
+	}
+}
+
+// attemptLockedUntil 期間をやり直す際に引き継ぐロックの期限
+func attemptLockedUntil(attempt *loginAttempt) time.Time {
+	if attempt == nil {
+		return time.Time{}
+	}
+	return attempt.lockedUntil
+}
+
+func emailKey(email, clientIP string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email)) + "|ip:" + clientIP
+}
+
+func ipKey(clientIP string) string {
+	return "ip:" + clientIP
+}
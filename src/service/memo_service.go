@@ -91,6 +91,24 @@ func (s *MemoService) DeleteMemo(ctx context.Context, id int) error {
 	return s.repo.Delete(ctx, id)
 }
 
+// TrashMemo moves a memo to the trash (sets status to trashed)
+func (s *MemoService) TrashMemo(ctx context.Context, id int) (*models.Memo, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid memo ID: %d", id)
+	}
+
+	return s.repo.Trash(ctx, id)
+}
+
+// PermanentDeleteMemo physically deletes a memo; only trashed memos can be deleted
+func (s *MemoService) PermanentDeleteMemo(ctx context.Context, id int) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid memo ID: %d", id)
+	}
+
+	return s.repo.PermanentDelete(ctx, id)
+}
+
 // ArchiveMemo archives a memo (sets status to archived)
 func (s *MemoService) ArchiveMemo(ctx context.Context, id int) (*models.Memo, error) {
 	status := "archived"
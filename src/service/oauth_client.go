@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// OAuthHTTPClient OAuthプロバイダーへのHTTPリクエストをリトライ付きで送信するクライアント
+//
+// 冪等なGET（ユーザー情報取得など）は接続エラー・5xx・429 でリトライする。
+// 非冪等なPOST（認可コードのトークン交換など）は認可コードが消費されている可能性があるため、
+// リクエストがプロバイダーに届いていないことが確実な接続エラーの場合のみリトライする。
+type OAuthHTTPClient struct {
+	client     *http.Client
+	maxRetries int
+	backoff    time.Duration
+	sleep      func(time.Duration)
+}
+
+// NewOAuthHTTPClient OAuth用HTTPクライアントを作成（maxRetriesは初回を除くリトライ回数）
+func NewOAuthHTTPClient(client *http.Client, maxRetries int, backoff time.Duration) *OAuthHTTPClient {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	return &OAuthHTTPClient{
+		client:     client,
+		maxRetries: maxRetries,
+		backoff:    backoff,
+		sleep:      time.Sleep,
+	}
+}
+
+// Do リクエストを送信する。GET/HEAD は冪等として扱い、それ以外は接続エラーのみリトライする
+func (c *OAuthHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			// 指数バックオフ（backoff, 2*backoff, 4*backoff, ...）
+			c.sleep(c.backoff << (attempt - 1))
+
+			if req.Body != nil {
+				if req.GetBody == nil {
+					return nil, fmt.Errorf("cannot retry request without GetBody")
+				}
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+		}
+
+		resp, err := c.client.Do(req)
+		canRetry := attempt < c.maxRetries
+		if err != nil {
+			if canRetry && (idempotent || isConnectionError(err)) {
+				continue
+			}
+			return nil, err
+		}
+
+		if canRetry && idempotent && isRetryableStatus(resp.StatusCode) {
+			resp.Body.Close()
+			continue
+		}
+		return resp, nil
+	}
+}
+
+// isRetryableStatus 一時的な障害とみなすステータスコードかどうか
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// isConnectionError リクエストが送信される前に失敗した接続エラーかどうか
+func isConnectionError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
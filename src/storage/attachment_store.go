@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// AttachmentStore メモの添付ファイルをS3に保存し、ダウンロード用の署名付きURLを発行する
+type AttachmentStore struct {
+	client *Client
+}
+
+// NewAttachmentStore 添付ファイルのストアを作成（保存先は client のバケット）
+func NewAttachmentStore(client *Client) *AttachmentStore {
+	return &AttachmentStore{client: client}
+}
+
+// Put 添付ファイルを key に保存
+func (s *AttachmentStore) Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error {
+	return s.client.PutObject(ctx, key, body, PutOptions{ContentType: contentType})
+}
+
+// Delete key の添付ファイルを削除
+func (s *AttachmentStore) Delete(ctx context.Context, key string) error {
+	return s.client.DeleteObject(ctx, key)
+}
+
+// PresignGet key の添付ファイルを expiry の間、filename を付けてダウンロードできる署名付きURLを返す
+func (s *AttachmentStore) PresignGet(key, filename string, expiry time.Duration) (string, error) {
+	return s.client.PresignGet(key, filename, expiry)
+}
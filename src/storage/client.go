@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Client S3（またはMinIOなどのS3互換ストレージ）の1つのバケットを操作する
+// ログのアップロードや添付ファイルなど、同じ接続設定を使う機能で共有する
+type Client struct {
+	s3     *s3.S3
+	bucket string
+}
+
+// PutOptions PutObject で保存するオブジェクトの属性
+type PutOptions struct {
+	ContentType string
+	Metadata    map[string]string
+}
+
+// NewClient config の接続先とバケットに対するクライアントを作成
+func NewClient(config *S3Config) (*Client, error) {
+	// AWS設定
+	awsConfig := &aws.Config{
+		Region:           aws.String(config.Region),
+		Credentials:      credentials.NewStaticCredentials(config.AccessKeyID, config.SecretAccessKey, ""),
+		DisableSSL:       aws.Bool(!config.UseSSL),
+		S3ForcePathStyle: aws.Bool(true), // MinIOなどのS3互換ストレージ用
+	}
+
+	// エンドポイントが指定されている場合（MinIOなど）
+	if config.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(config.Endpoint)
+	}
+
+	// セッションを作成
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("AWSセッションの作成に失敗: %v", err)
+	}
+
+	return &Client{s3: s3.New(sess), bucket: config.Bucket}, nil
+}
+
+// WithBucket 同じ接続で別のバケットを操作するクライアントを返す
+func (c *Client) WithBucket(bucket string) *Client {
+	return &Client{s3: c.s3, bucket: bucket}
+}
+
+// Bucket 操作対象のバケット名
+func (c *Client) Bucket() string {
+	return c.bucket
+}
+
+// PutObject body を key に保存
+func (c *Client) PutObject(ctx context.Context, key string, body io.ReadSeeker, opts PutOptions) error {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+	if len(opts.Metadata) > 0 {
+		input.Metadata = aws.StringMap(opts.Metadata)
+	}
+
+	if _, err := c.s3.PutObjectWithContext(ctx, input); err != nil {
+		return fmt.Errorf("S3アップロードに失敗: %v", err)
+	}
+	return nil
+}
+
+// GetObject key のオブジェクトを読み出す（呼び出し側で Close する）
+func (c *Client) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := c.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("S3オブジェクトの取得に失敗: %v", err)
+	}
+	return out.Body, nil
+}
+
+// DeleteObject key のオブジェクトを削除
+func (c *Client) DeleteObject(ctx context.Context, key string) error {
+	if _, err := c.s3.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}); err != nil {
+		return fmt.Errorf("S3オブジェクトの削除に失敗: %v", err)
+	}
+	return nil
+}
+
+// PresignGet key のオブジェクトを expiry の間ダウンロードできる署名付きURLを返す
+// filename を指定した場合は、ブラウザで開いてもページとして表示されないよう attachment としてダウンロードさせる
+func (c *Client) PresignGet(key, filename string, expiry time.Duration) (string, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}
+	if filename != "" {
+		input.ResponseContentDisposition = aws.String(mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+
+	req, _ := c.s3.GetObjectRequest(input)
+	url, err := req.Presign(expiry)
+	if err != nil {
+		return "", fmt.Errorf("署名付きURLの作成に失敗: %v", err)
+	}
+	return url, nil
+}
+
+// Health バケットに接続できるか確認（レディネスチェック用）
+func (c *Client) Health(ctx context.Context) error {
+	if _, err := c.s3.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(c.bucket),
+	}); err != nil {
+		return fmt.Errorf("S3バケットに接続できません: %v", err)
+	}
+	return nil
+}
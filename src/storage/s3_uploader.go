@@ -1,16 +1,14 @@
 package storage
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/sirupsen/logrus"
 )
 
@@ -21,44 +19,121 @@ type S3Config struct {
 	Region          string
 	Bucket          string
 	UseSSL          bool
+	Concurrency     int           // 同時にアップロードするファイル数の上限（0以下の場合は1）
+	Retries         int           // アップロードに失敗したファイルを再試行する回数（0は再試行しない）
+	RetryBackoff    time.Duration // 最初の再試行までの待ち時間（以降は倍々、上限1分）
+	AlertThreshold  int           // アラートを出す定期アップロードの連続失敗回数（0以下の場合は DefaultUploadAlertThreshold）
 }
 
+// DefaultUploadAlertThreshold 連続失敗のアラートを出すまでの定期アップロードの回数のデフォルト
+const DefaultUploadAlertThreshold = 3
+
+// maxUploadRetryBackoff 再試行の待ち時間の上限
+const maxUploadRetryBackoff = time.Minute
+
+// UploadRetry ログファイルのアップロードを再試行する設定
+type UploadRetry struct {
+	MaxRetries int           // 失敗後に再試行する回数（0は再試行しない）
+	Backoff    time.Duration // 最初の再試行までの待ち時間（以降は倍々、上限1分）
+}
+
+// FileUploader 1つのログファイルをアップロードする
+type FileUploader interface {
+	UploadLogFileWithContext(ctx context.Context, filePath string) error
+}
+
+// LocalKeeper アップロード後もローカルに残すファイルを判定する（FileUploader が任意で実装する）
+type LocalKeeper interface {
+	KeepLocal(filePath string) bool
+}
+
+// UploadSummary ログファイルの一括アップロードの結果
+type UploadSummary struct {
+	Uploaded int // アップロードしたファイル数（Kept を除きローカルから削除済み）
+	Kept     int // アップロードしたが書き込み中のためローカルに残したファイル数
+	Failed   int // アップロードに失敗したファイル数
+	Skipped  int // 期限切れのため開始しなかった、または中断したファイル数
+}
+
+// LogUploader ログファイルを Client のバケットにアップロードする
 type LogUploader struct {
-	s3Client *s3.S3
-	config   *S3Config
-	logger   *logrus.Logger
+	client         *Client
+	concurrency    int
+	retry          UploadRetry
+	alertThreshold int
+	logger         *logrus.Logger
+
+	mu                  sync.Mutex
+	consecutiveFailures int // 定期アップロードが連続して失敗した回数
+	alertHook           UploadAlertHook
+	activeFile          func() string
 }
 
 // NewLogUploader S3アップローダーを作成
 func NewLogUploader(config *S3Config, logger *logrus.Logger) (*LogUploader, error) {
-	// AWS設定
-	awsConfig := &aws.Config{
-		Region:           aws.String(config.Region),
-		Credentials:      credentials.NewStaticCredentials(config.AccessKeyID, config.SecretAccessKey, ""),
-		DisableSSL:       aws.Bool(!config.UseSSL),
-		S3ForcePathStyle: aws.Bool(true), // MinIOなどのS3互換ストレージ用
+	client, err := NewClient(config)
+	if err != nil {
+		return nil, err
 	}
+	return NewLogUploaderWithClient(client, config, logger), nil
+}
 
-	// エンドポイントが指定されている場合（MinIOなど）
-	if config.Endpoint != "" {
-		awsConfig.Endpoint = aws.String(config.Endpoint)
+// NewLogUploaderWithClient 既存のクライアントを使うS3アップローダーを作成
+// config からは同時実行数・再試行・アラートの設定のみを使用する（接続先は client のもの）
+func NewLogUploaderWithClient(client *Client, config *S3Config, logger *logrus.Logger) *LogUploader {
+	alertThreshold := config.AlertThreshold
+	if alertThreshold <= 0 {
+		alertThreshold = DefaultUploadAlertThreshold
+	}
+	return &LogUploader{
+		client:         client,
+		concurrency:    config.Concurrency,
+		retry:          UploadRetry{MaxRetries: config.Retries, Backoff: config.RetryBackoff},
+		alertThreshold: alertThreshold,
+		logger:         logger,
 	}
+}
 
-	// セッションを作成
-	sess, err := session.NewSession(awsConfig)
-	if err != nil {
-		return nil, fmt.Errorf("AWSセッションの作成に失敗: %v", err)
+// SetFailureAlertHook 連続失敗のアラートをログ以外にも通知する関数を設定（Webhookなど）
+func (u *LogUploader) SetFailureAlertHook(hook UploadAlertHook) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.alertHook = hook
+}
+
+// SetActiveFile 書き込み中のログファイルのパスを返す関数を設定
+// このファイルはアップロードしてもローカルから削除しない（削除すると以降のログが失われるため）
+func (u *LogUploader) SetActiveFile(activeFile func() string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.activeFile = activeFile
+}
+
+// KeepLocal filePath が書き込み中のログファイルかどうか
+func (u *LogUploader) KeepLocal(filePath string) bool {
+	u.mu.Lock()
+	activeFile := u.activeFile
+	u.mu.Unlock()
+	if activeFile == nil {
+		return false
 	}
 
-	return &LogUploader{
-		s3Client: s3.New(sess),
-		config:   config,
-		logger:   logger,
-	}, nil
+	active := activeFile()
+	if active == "" {
+		return false
+	}
+	activeAbs, err1 := filepath.Abs(active)
+	fileAbs, err2 := filepath.Abs(filePath)
+	return err1 == nil && err2 == nil && activeAbs == fileAbs
 }
 
 // UploadLogFile ログファイルをS3にアップロード
 func (u *LogUploader) UploadLogFile(filePath string) error {
+	return u.UploadLogFileWithContext(context.Background(), filePath)
+}
+
+// UploadLogFileWithContext ログファイルをS3にアップロード（ctxがキャンセルされた場合は中断）
+func (u *LogUploader) UploadLogFileWithContext(ctx context.Context, filePath string) error {
 	// ファイルパスの基本的な検証
 	if filePath == "" {
 		return fmt.Errorf("ファイルパスが空です")
@@ -81,85 +156,266 @@ func (u *LogUploader) UploadLogFile(filePath string) error {
 	objectKey := fmt.Sprintf("logs/%s", fileName)
 
 	// S3にアップロード
-	_, err = u.s3Client.PutObject(&s3.PutObjectInput{
-		Bucket:      aws.String(u.config.Bucket),
-		Key:         aws.String(objectKey),
-		Body:        file,
-		ContentType: aws.String("text/plain"),
-		Metadata: map[string]*string{
-			"upload-time": aws.String(time.Now().Format(time.RFC3339)),
-			"source":      aws.String("memo-app-api-server"),
+	err = u.client.PutObject(ctx, objectKey, file, PutOptions{
+		ContentType: "text/plain",
+		Metadata: map[string]string{
+			"upload-time": time.Now().Format(time.RFC3339),
+			"source":      "memo-app-api-server",
 		},
 	})
-
 	if err != nil {
-		return fmt.Errorf("S3アップロードに失敗: %v", err)
+		return err
 	}
 
 	u.logger.WithFields(logrus.Fields{
 		"file":   fileName,
-		"bucket": u.config.Bucket,
+		"bucket": u.client.Bucket(),
 		"key":    objectKey,
 	}).Info("ログファイルをS3にアップロードしました")
 
 	return nil
 }
 
-// UploadOldLogs 古いログファイルをアップロードして削除
+// Health アップロード先のバケットに接続できるか確認（レディネスチェック用）
+func (u *LogUploader) Health(ctx context.Context) error {
+	return u.client.Health(ctx)
+}
+
+// UploadOldLogs 古いログファイルをアップロードして削除（失敗したファイルは設定に従って再試行する）
+// 再試行しても失敗が続く実行が AlertThreshold 回連続した場合はアラートを出す
 func (u *LogUploader) UploadOldLogs(logDir string, maxAge time.Duration) error {
+	summary, err := UploadLogsWithRetry(context.Background(), u, logDir, maxAge, u.concurrency, u.retry, u.logger)
+	u.recordResult(summary, err)
+	return err
+}
+
+// UploadAll logDir内のすべてのログファイルを今すぐアップロードする（障害対応などで手動で実行する場合）
+// 書き込み中のファイルはアップロードのみ行いローカルに残す。結果は定期アップロードと同じく連続失敗として数える
+func (u *LogUploader) UploadAll(ctx context.Context, logDir string) (UploadSummary, error) {
+	summary, err := UploadLogsWithRetry(ctx, u, logDir, 0, u.concurrency, u.retry, u.logger)
+	u.recordResult(summary, err)
+	return summary, err
+}
+
+// ConsecutiveFailures 定期アップロードが連続して失敗している回数
+func (u *LogUploader) ConsecutiveFailures() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.consecutiveFailures
+}
+
+// recordResult 定期アップロードの結果から連続失敗回数を更新し、閾値以上であればアラートを出す
+func (u *LogUploader) recordResult(summary UploadSummary, err error) {
+	u.mu.Lock()
+	if err == nil && summary.Failed == 0 {
+		if u.consecutiveFailures >= u.alertThreshold {
+			u.logger.WithField("consecutive_failures", u.consecutiveFailures).Info("ログのアップロードが回復しました")
+		}
+		u.consecutiveFailures = 0
+		u.mu.Unlock()
+		return
+	}
+	u.consecutiveFailures++
+	failures := u.consecutiveFailures
+	hook := u.alertHook
+	u.mu.Unlock()
+
+	if failures < u.alertThreshold {
+		return
+	}
+
+	alert := UploadFailureAlert{
+		ConsecutiveFailures: failures,
+		Failed:              summary.Failed,
+		Bucket:              u.client.Bucket(),
+		OccurredAt:          time.Now(),
+	}
+	if err != nil {
+		alert.Error = err.Error()
+	}
+	u.logger.WithFields(logrus.Fields{
+		"alert":                UploadAlertEvent,
+		"consecutive_failures": alert.ConsecutiveFailures,
+		"failed":               alert.Failed,
+		"bucket":               alert.Bucket,
+	}).Error("【アラート】ログのアップロードが連続して失敗しています。ログが失われる可能性があります")
+
+	if hook != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultAlertWebhookTimeout)
+		defer cancel()
+		if err := hook(ctx, alert); err != nil {
+			u.logger.WithError(err).Error("ログアップロードのアラートの通知に失敗")
+		}
+	}
+}
+
+// UploadOnShutdown シャットダウン時に残りのログファイルをすべてアップロード
+// 同時アップロード数は通常の設定と maxConcurrency の小さい方に制限し、ctxの期限を過ぎたファイルはスキップする
+func (u *LogUploader) UploadOnShutdown(ctx context.Context, logDir string, maxConcurrency int) (UploadSummary, error) {
+	concurrency := u.concurrency
+	if maxConcurrency > 0 && (concurrency <= 0 || maxConcurrency < concurrency) {
+		concurrency = maxConcurrency
+	}
+	return UploadLogsWithRetry(ctx, u, logDir, 0, concurrency, u.retry, u.logger)
+}
+
+// UploadLogs logDir内の maxAge より古いログファイルを最大 concurrency 件ずつ並行してアップロードし、成功したものを削除する
+// ctxが終了した後は新しいアップロードを開始せず、実行中のものも中断してスキップとして数える
+func UploadLogs(ctx context.Context, uploader FileUploader, logDir string, maxAge time.Duration, concurrency int, logger *logrus.Logger) (UploadSummary, error) {
+	return UploadLogsWithRetry(ctx, uploader, logDir, maxAge, concurrency, UploadRetry{}, logger)
+}
+
+// UploadLogsWithRetry UploadLogs と同様にアップロードし、失敗したファイルは retry に従って待ち時間を倍々にしながら再試行する
+// 再試行の待機中にctxが終了した場合はその時点の失敗を返す
+func UploadLogsWithRetry(ctx context.Context, uploader FileUploader, logDir string, maxAge time.Duration, concurrency int, retry UploadRetry, logger *logrus.Logger) (UploadSummary, error) {
+	var summary UploadSummary
+
 	entries, err := os.ReadDir(logDir)
 	if err != nil {
-		return fmt.Errorf("ログディレクトリの読み取りに失敗: %v", err)
+		return summary, fmt.Errorf("ログディレクトリの読み取りに失敗: %v", err)
 	}
 
 	cutoffTime := time.Now().Add(-maxAge)
 
+	var files []string
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".log") {
 			continue
 		}
 
-		filePath := filepath.Join(logDir, entry.Name())
 		fileInfo, err := entry.Info()
 		if err != nil {
-			u.logger.WithError(err).WithField("file", entry.Name()).Error("ファイル情報の取得に失敗")
+			logger.WithError(err).WithField("file", entry.Name()).Error("ファイル情報の取得に失敗")
 			continue
 		}
 
-		// ファイルが古い場合はアップロードして削除
+		// ファイルが古い場合はアップロード対象にする
 		if fileInfo.ModTime().Before(cutoffTime) {
-			u.logger.WithFields(logrus.Fields{
+			logger.WithFields(logrus.Fields{
 				"file":    entry.Name(),
 				"modTime": fileInfo.ModTime(),
 				"cutoff":  cutoffTime,
 			}).Info("古いログファイルをアップロード中")
+			files = append(files, filepath.Join(logDir, entry.Name()))
+		}
+	}
 
-			// S3にアップロード
-			if err := u.UploadLogFile(filePath); err != nil {
-				u.logger.WithError(err).WithField("file", entry.Name()).Error("ログファイルのアップロードに失敗")
-				continue
-			}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
 
-			// ローカルファイルを削除
-			if err := os.Remove(filePath); err != nil {
-				u.logger.WithError(err).WithField("file", entry.Name()).Error("ローカルファイルの削除に失敗")
-			} else {
-				u.logger.WithField("file", entry.Name()).Info("ローカルファイルを削除しました")
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i, filePath := range files {
+		// 期限を過ぎていれば残りは開始しない
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
 			}
 		}
+		if ctx.Err() != nil {
+			mu.Lock()
+			summary.Skipped += len(files) - i
+			mu.Unlock()
+			break
+		}
+
+		wg.Add(1)
+		go func(filePath string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			fileName := filepath.Base(filePath)
+			err := uploadWithRetry(ctx, uploader, filePath, retry, logger)
+			keep := false
+			if keeper, ok := uploader.(LocalKeeper); ok && err == nil {
+				keep = keeper.KeepLocal(filePath)
+			}
+			if err == nil && !keep {
+				// ローカルファイルを削除
+				if err := os.Remove(filePath); err != nil {
+					logger.WithError(err).WithField("file", fileName).Error("ローカルファイルの削除に失敗")
+				} else {
+					logger.WithField("file", fileName).Info("ローカルファイルを削除しました")
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				summary.Uploaded++
+				if keep {
+					summary.Kept++
+				}
+			case ctx.Err() != nil:
+				summary.Skipped++
+			default:
+				summary.Failed++
+				logger.WithError(err).WithField("file", fileName).Error("ログファイルのアップロードに失敗")
+			}
+		}(filePath)
 	}
+	wg.Wait()
 
-	return nil
+	if len(files) > 0 {
+		logger.WithFields(logrus.Fields{
+			"uploaded": summary.Uploaded,
+			"kept":     summary.Kept,
+			"failed":   summary.Failed,
+			"skipped":  summary.Skipped,
+		}).Info("ログファイルのアップロードが完了しました")
+	}
+	return summary, nil
 }
 
-// StartPeriodicUpload 定期的なアップロードを開始
-func (u *LogUploader) StartPeriodicUpload(logDir string, interval time.Duration, maxAge time.Duration) {
+// uploadWithRetry 1つのファイルをアップロードし、失敗した場合は retry.MaxRetries 回まで再試行する
+func uploadWithRetry(ctx context.Context, uploader FileUploader, filePath string, retry UploadRetry, logger *logrus.Logger) error {
+	delay := retry.Backoff
+	for attempt := 0; ; attempt++ {
+		err := uploader.UploadLogFileWithContext(ctx, filePath)
+		if err == nil || attempt >= retry.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		logger.WithError(err).WithFields(logrus.Fields{
+			"file":     filepath.Base(filePath),
+			"retry":    attempt + 1,
+			"retry_in": delay,
+		}).Warn("ログファイルのアップロードに失敗したため再試行します")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		delay *= 2
+		if delay > maxUploadRetryBackoff {
+			delay = maxUploadRetryBackoff
+		}
+	}
+}
+
+// StartPeriodicUpload 定期的なアップロードを開始（返り値の関数で停止する）
+func (u *LogUploader) StartPeriodicUpload(logDir string, interval time.Duration, maxAge time.Duration) func() {
 	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
 	go func() {
-		for range ticker.C {
-			u.logger.Info("定期的なログアップロードを開始")
-			if err := u.UploadOldLogs(logDir, maxAge); err != nil {
-				u.logger.WithError(err).Error("定期的なログアップロードに失敗")
+		for {
+			select {
+			case <-ticker.C:
+				u.logger.Info("定期的なログアップロードを開始")
+				if err := u.UploadOldLogs(logDir, maxAge); err != nil {
+					u.logger.WithError(err).Error("定期的なログアップロードに失敗")
+				}
+			case <-done:
+				return
 			}
 		}
 	}()
@@ -168,4 +424,9 @@ func (u *LogUploader) StartPeriodicUpload(logDir string, interval time.Duration,
 		"interval": interval,
 		"maxAge":   maxAge,
 	}).Info("定期的なログアップロードを開始しました")
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
 }
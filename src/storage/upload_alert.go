@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// UploadAlertEvent ログアップロードの連続失敗のアラートのイベント名
+const UploadAlertEvent = "log_upload.failing"
+
+// DefaultAlertWebhookTimeout アラートのWebhook送信のタイムアウト
+const DefaultAlertWebhookTimeout = 10 * time.Second
+
+// UploadFailureAlert ログアップロードが連続して失敗していることの通知
+type UploadFailureAlert struct {
+	ConsecutiveFailures int       `json:"consecutive_failures"` // 連続して失敗した定期アップロードの回数
+	Failed              int       `json:"failed"`               // 直近の実行で再試行しても失敗したファイル数
+	Bucket              string    `json:"bucket"`
+	Error               string    `json:"error,omitempty"` // ログディレクトリの読み取りなど、実行全体の失敗
+	OccurredAt          time.Time `json:"occurred_at"`
+}
+
+// UploadAlertHook 連続失敗のアラートを通知する関数
+type UploadAlertHook func(ctx context.Context, alert UploadFailureAlert) error
+
+// uploadAlertPayload Webhookに送信するJSON
+type uploadAlertPayload struct {
+	Event string `json:"event"`
+	UploadFailureAlert
+}
+
+// NewUploadAlertWebhook アラートをJSONで url にPOSTする UploadAlertHook を作成
+// clientがnilの場合はDefaultAlertWebhookTimeoutのクライアントを使用し、2xx以外の応答はエラーとする
+func NewUploadAlertWebhook(url string, client *http.Client) UploadAlertHook {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultAlertWebhookTimeout}
+	}
+	return func(ctx context.Context, alert UploadFailureAlert) error {
+		body, err := json.Marshal(uploadAlertPayload{Event: UploadAlertEvent, UploadFailureAlert: alert})
+		if err != nil {
+			return fmt.Errorf("アラートの本文の作成に失敗: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("アラートのリクエストの作成に失敗: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("アラートの送信に失敗: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("アラートの送信先がエラーを返しました: %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"memo-app/src/config"
+	"memo-app/src/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrAttachmentNotFound       = errors.New("attachment not found")
+	ErrAttachmentUnauthorized   = errors.New("attachments require an authenticated user")
+	ErrInvalidAttachment        = errors.New("attachment must be a non-empty file with a filename")
+	ErrAttachmentTooLarge       = errors.New("attachment exceeds the maximum size")
+	ErrAttachmentTypeNotAllowed = errors.New("attachment content type is not allowed")
+)
+
+// maxAttachmentFilenameLength is the maximum length of a stored attachment filename in characters (memo_attachments.filename)
+const maxAttachmentFilenameLength = 255
+
+// AttachmentStorage stores attachment files, e.g. in S3, and issues time-limited download URLs for them
+type AttachmentStorage interface {
+	Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error
+	Delete(ctx context.Context, key string) error
+	PresignGet(key, filename string, expiry time.Duration) (string, error)
+}
+
+// UploadAttachmentRequest represents an uploaded file. The content type is detected from the file
+// itself; the type declared by the client is not trusted
+type UploadAttachmentRequest struct {
+	Filename string
+	Size     int64
+	File     io.ReadSeeker
+}
+
+// AttachmentDownload is a signed URL for downloading an attachment until ExpiresAt
+type AttachmentDownload struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+// AttachmentUsecase defines the interface for files attached to the caller's memos
+type AttachmentUsecase interface {
+	UploadAttachment(ctx context.Context, memoID int, req UploadAttachmentRequest) (*domain.MemoAttachment, error)
+	ListAttachments(ctx context.Context, memoID int) ([]domain.MemoAttachment, error)
+	GetAttachmentDownload(ctx context.Context, memoID, id int) (*domain.MemoAttachment, *AttachmentDownload, error)
+}
+
+type attachmentUsecase struct {
+	memoRepo       domain.MemoRepository
+	attachmentRepo domain.AttachmentRepository
+	storage        AttachmentStorage
+	config         *config.AttachmentConfig
+	logger         logrus.FieldLogger
+}
+
+// NewAttachmentUsecase creates a new attachment usecase
+func NewAttachmentUsecase(memoRepo domain.MemoRepository, attachmentRepo domain.AttachmentRepository, storage AttachmentStorage, cfg *config.AttachmentConfig, logger logrus.FieldLogger) AttachmentUsecase {
+	if cfg == nil {
+		cfg = config.DefaultAttachmentConfig()
+	}
+	return &attachmentUsecase{
+		memoRepo:       memoRepo,
+		attachmentRepo: attachmentRepo,
+		storage:        storage,
+		config:         cfg,
+		logger:         logger,
+	}
+}
+
+// UploadAttachment stores the file and records it as an attachment of a memo of the caller.
+// The file is rejected when it is larger than the configured maximum or its detected content type is not allowed
+func (u *attachmentUsecase) UploadAttachment(ctx context.Context, memoID int, req UploadAttachmentRequest) (*domain.MemoAttachment, error) {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return nil, ErrAttachmentUnauthorized
+	}
+
+	filename := sanitizeAttachmentFilename(req.Filename)
+	if filename == "" || req.Size <= 0 || req.File == nil {
+		return nil, ErrInvalidAttachment
+	}
+	if req.Size > u.config.MaxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	contentType, err := detectContentType(req.File)
+	if err != nil {
+		return nil, err
+	}
+	if !u.allowedType(contentType) {
+		return nil, ErrAttachmentTypeNotAllowed
+	}
+
+	// 他のユーザーのメモにはファイルを保存しない
+	if _, err := u.memoRepo.GetByID(ctx, memoID); err != nil {
+		if strings.Contains(err.Error(), "memo not found") {
+			return nil, ErrMemoNotFound
+		}
+		return nil, err
+	}
+
+	key, err := attachmentObjectKey(userID, memoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.storage.Put(ctx, key, req.File, contentType); err != nil {
+		return nil, err
+	}
+
+	attachment, err := u.attachmentRepo.Create(ctx, &domain.MemoAttachment{
+		MemoID:      memoID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        req.Size,
+		ObjectKey:   key,
+	})
+	if err != nil {
+		// 記録できなかったファイルは参照されないため削除する
+		if deleteErr := u.storage.Delete(context.WithoutCancel(ctx), key); deleteErr != nil {
+			u.logger.WithError(deleteErr).WithField("object_key", key).Warn("記録できなかった添付ファイルの削除に失敗")
+		}
+		if strings.Contains(err.Error(), "memo not found") {
+			return nil, ErrMemoNotFound
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// ListAttachments lists the attachments of a memo of the caller, oldest first
+func (u *attachmentUsecase) ListAttachments(ctx context.Context, memoID int) ([]domain.MemoAttachment, error) {
+	if _, ok := domain.UserIDFromContext(ctx); !ok {
+		return nil, ErrAttachmentUnauthorized
+	}
+	if _, err := u.memoRepo.GetByID(ctx, memoID); err != nil {
+		if strings.Contains(err.Error(), "memo not found") {
+			return nil, ErrMemoNotFound
+		}
+		return nil, err
+	}
+	return u.memoRepo.ListAttachments(ctx, memoID)
+}
+
+// GetAttachmentDownload issues a signed URL for downloading an attachment of a memo of the caller
+func (u *attachmentUsecase) GetAttachmentDownload(ctx context.Context, memoID, id int) (*domain.MemoAttachment, *AttachmentDownload, error) {
+	if _, ok := domain.UserIDFromContext(ctx); !ok {
+		return nil, nil, ErrAttachmentUnauthorized
+	}
+
+	attachment, err := u.attachmentRepo.GetByID(ctx, memoID, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "attachment not found") {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+
+	expiresAt := time.Now().Add(u.config.URLExpiry)
+	url, err := u.storage.PresignGet(attachment.ObjectKey, attachment.Filename, u.config.URLExpiry)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, &AttachmentDownload{URL: url, ExpiresAt: expiresAt}, nil
+}
+
+// allowedType reports whether the content type is in the configured allowlist
+func (u *attachmentUsecase) allowedType(contentType string) bool {
+	for _, allowed := range u.config.AllowedTypes {
+		if strings.EqualFold(allowed, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectContentType sniffs the media type (without parameters) from the first bytes of the file
+// and rewinds it for the upload
+func detectContentType(file io.ReadSeeker) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind attachment: %w", err)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if err != nil {
+		return "application/octet-stream", nil
+	}
+	return mediaType, nil
+}
+
+// sanitizeAttachmentFilename keeps only the base name of the uploaded filename without control characters,
+// truncated to the column length
+func sanitizeAttachmentFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	filename = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, filename))
+	if filename == "." || filename == "/" || filename == ".." {
+		return ""
+	}
+	if utf8.RuneCountInString(filename) > maxAttachmentFilenameLength {
+		filename = string([]rune(filename)[:maxAttachmentFilenameLength])
+	}
+	return filename
+}
+
+// attachmentObjectKey returns a new random object key under the owner's and memo's prefix
+func attachmentObjectKey(userID, memoID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate attachment key: %w", err)
+	}
+	return fmt.Sprintf("attachments/%d/%d/%s", userID, memoID, hex.EncodeToString(b)), nil
+}
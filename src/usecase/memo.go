@@ -3,22 +3,81 @@ package usecase
 import (
 	"context"
 	"errors"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"memo-app/src/config"
 	"memo-app/src/domain"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 var (
-	ErrMemoNotFound    = errors.New("memo not found")
-	ErrInvalidTitle    = errors.New("title is required and must be less than 200 characters")
-	ErrInvalidContent  = errors.New("content is required")
-	ErrInvalidPriority = errors.New("priority must be low, medium, or high")
-	ErrInvalidStatus   = errors.New("status must be active or archived")
-	ErrInvalidPage     = errors.New("page must be greater than 0")
-	ErrInvalidLimit    = errors.New("limit must be between 1 and 100")
+	ErrMemoNotFound         = errors.New("memo not found")
+	ErrMemoForbidden        = errors.New("memo belongs to another user")
+	ErrInvalidTitle         = errors.New("title is required and must be less than 200 characters")
+	ErrInvalidContent       = errors.New("content is required")
+	ErrContentTooLong       = errors.New("content exceeds the maximum length")
+	ErrInvalidPriority      = errors.New("priority must be low, medium, or high")
+	ErrInvalidStatus        = errors.New("status must be active, archived, or trashed")
+	ErrMemoNotTrashed       = errors.New("memo must be in the trash before it can be permanently deleted")
+	ErrMemoAlreadyTrashed   = errors.New("memo is already in the trash; delete it permanently with DELETE /api/memos/:id/permanent")
+	ErrLastActiveInCategory = errors.New("cannot archive or delete the last active memo in a protected category; create or restore another active memo in this category first")
+	ErrInvalidPage          = errors.New("page must be greater than 0")
+	ErrInvalidLimit         = errors.New("limit must be between 1 and 100")
+	ErrInvalidBulkIDs       = errors.New("ids must contain between 1 and 100 positive memo IDs")
+	ErrInvalidBulkUpdate    = errors.New("bulk update requires status or category and supports no other fields")
+	ErrInvalidImportIDs     = errors.New("import requires between 1 and 100 memos with unique positive IDs")
+	ErrInvalidImport        = errors.New("import requires between 1 and 1000 memos")
+	ErrMemoIDConflict       = errors.New("a memo with one of the given IDs already exists")
+	ErrEmptyMetadataUpdate  = errors.New("metadata update requires at least one of category, tags, priority, color or due_date")
+	ErrInvalidColor         = errors.New("color must be a hex color like #1a2b3c, or empty to clear it")
+	ErrVersionConflict      = errors.New("memo was modified by another request")
+	ErrRevisionNotFound     = errors.New("revision not found")
+	ErrDeletedMemoNotFound  = errors.New("deleted memo not found")
+)
+
+// MaxBulkIDs is the maximum number of memos a single bulk operation may target
+const MaxBulkIDs = 100
+
+// MaxImportMemos is the maximum number of memos a single import may contain
+const MaxImportMemos = 1000
+
+// hexColorPattern matches a memo color in #RRGGBB form
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// Bulk operation result statuses
+const (
+	BulkStatusDeleted  = "deleted"
+	BulkStatusNotFound = "not_found"
 )
 
+// BulkResult represents the outcome of a bulk operation for a single memo
+type BulkResult struct {
+	ID     int
+	Status string
+}
+
+// BulkUpdateResult represents the outcome of a bulk update
+type BulkUpdateResult struct {
+	Updated    int
+	SkippedIDs []int
+}
+
+// ImportFailure reports why the memo at Index of an import was not created
+type ImportFailure struct {
+	Index int
+	Err   error
+}
+
+// ImportResult represents the outcome of an import
+type ImportResult struct {
+	Imported int
+	Failed   []ImportFailure
+}
+
 // CreateMemoRequest represents input for creating a memo
 type CreateMemoRequest struct {
 	Title    string
@@ -26,6 +85,14 @@ type CreateMemoRequest struct {
 	Category string
 	Tags     []string
 	Priority string
+	DueDate  *time.Time
+	RemindAt *time.Time
+}
+
+// ImportMemoRequest represents input for creating a memo with a client-supplied ID
+type ImportMemoRequest struct {
+	ID int
+	CreateMemoRequest
 }
 
 // UpdateMemoRequest represents input for updating a memo
@@ -36,28 +103,94 @@ type UpdateMemoRequest struct {
 	Tags     []string
 	Priority *string
 	Status   *string
+	DueDate  *time.Time
+	// RemindAt reschedules the reminder; it is sent again even if the previous reminder was already sent
+	RemindAt *time.Time
+	// Version is the version the client last read; when set the update fails with ErrVersionConflict if the memo changed since
+	Version *int
+}
+
+// UpdateMemoMetadataRequest represents input for updating only the metadata of a memo
+type UpdateMemoMetadataRequest struct {
+	Category *string
+	Tags     []string
+	Priority *string
+	Color    *string
+	DueDate  *time.Time
+	// ClearDueDate removes the due date; DueDate is ignored when it is set
+	ClearDueDate bool
 }
 
 // MemoUsecase defines the interface for memo business logic
 type MemoUsecase interface {
 	CreateMemo(ctx context.Context, req CreateMemoRequest) (*domain.Memo, error)
+	ImportMemosWithIDs(ctx context.Context, reqs []ImportMemoRequest) ([]domain.Memo, error)
+	ImportMemos(ctx context.Context, reqs []CreateMemoRequest, atomic bool) (*ImportResult, error)
 	GetMemo(ctx context.Context, id int) (*domain.Memo, error)
 	ListMemos(ctx context.Context, filter domain.MemoFilter) ([]domain.Memo, int, error)
+	ExportMemos(ctx context.Context, fn func(*domain.Memo) error) error
 	UpdateMemo(ctx context.Context, id int, req UpdateMemoRequest) (*domain.Memo, error)
+	UpdateMemoMetadata(ctx context.Context, id int, req UpdateMemoMetadataRequest) (*domain.Memo, error)
+	ListMemoHistory(ctx context.Context, id int) ([]domain.MemoRevision, error)
+	RevertMemo(ctx context.Context, id, revisionID int) (*domain.Memo, error)
 	DeleteMemo(ctx context.Context, id int) error
+	BulkDeleteMemos(ctx context.Context, ids []int) ([]BulkResult, error)
+	BulkUpdateMemos(ctx context.Context, ids []int, req UpdateMemoRequest) (*BulkUpdateResult, error)
 	ArchiveMemo(ctx context.Context, id int) error
+	TrashMemo(ctx context.Context, id int) (*domain.Memo, error)
+	PermanentDeleteMemo(ctx context.Context, id int) error
+	ListDeletedMemos(ctx context.Context, filter domain.MemoFilter) ([]domain.DeletedMemo, int, error)
+	RestoreDeletedMemo(ctx context.Context, id int) (*domain.Memo, error)
 	RestoreMemo(ctx context.Context, id int) error
 	SearchMemos(ctx context.Context, query string, filter domain.MemoFilter) ([]domain.Memo, int, error)
+	PromoteMemo(ctx context.Context, id int) (*domain.Memo, error)
+	TouchMemo(ctx context.Context, id int) (*domain.Memo, error)
+	PinMemo(ctx context.Context, id int) (*domain.Memo, error)
+	UnpinMemo(ctx context.Context, id int) (*domain.Memo, error)
+	GetMemoDetail(ctx context.Context, id int, expand domain.MemoExpand) (*domain.MemoDetail, error)
+	RecentSearchQueries(ctx context.Context) ([]string, error)
+	ClearRecentSearchQueries(ctx context.Context) error
+	ListTags(ctx context.Context, filter domain.TagFilter) ([]domain.TagCount, error)
+	ListCategories(ctx context.Context) ([]string, error)
+	GetMemoStats(ctx context.Context) (*domain.MemoStats, error)
+	GetMemoListState(ctx context.Context) (*domain.MemoListState, error)
+	ListSharedMemos(ctx context.Context, filter domain.MemoFilter) ([]domain.SharedMemo, int, error)
+	ListOnThisDay(ctx context.Context) ([]domain.Memo, error)
+}
+
+// MemoEventPublisher receives memo changes made through the usecase, e.g. to deliver them to webhooks.
+// PublishMemoEvent must not block; ctx carries the authenticated user and the memo is its state after the
+// change (before the deletion for domain.MemoEventDeleted)
+type MemoEventPublisher interface {
+	PublishMemoEvent(ctx context.Context, event string, memo *domain.Memo)
 }
 
 type memoUsecase struct {
-	memoRepo domain.MemoRepository
+	memoRepo  domain.MemoRepository
+	config    *config.MemoConfig
+	publisher MemoEventPublisher
 }
 
-// NewMemoUsecase creates a new memo usecase
+// NewMemoUsecase creates a new memo usecase with the default configuration
 func NewMemoUsecase(memoRepo domain.MemoRepository) MemoUsecase {
+	return NewMemoUsecaseWithConfig(memoRepo, config.DefaultMemoConfig())
+}
+
+// NewMemoUsecaseWithConfig creates a new memo usecase with the given configuration
+func NewMemoUsecaseWithConfig(memoRepo domain.MemoRepository, cfg *config.MemoConfig) MemoUsecase {
+	return NewMemoUsecaseWithPublisher(memoRepo, cfg, nil)
+}
+
+// NewMemoUsecaseWithPublisher creates a new memo usecase that reports created, updated and deleted memos
+// to publisher (nil disables events)
+func NewMemoUsecaseWithPublisher(memoRepo domain.MemoRepository, cfg *config.MemoConfig, publisher MemoEventPublisher) MemoUsecase {
+	if cfg == nil {
+		cfg = config.DefaultMemoConfig()
+	}
 	return &memoUsecase{
-		memoRepo: memoRepo,
+		memoRepo:  memoRepo,
+		config:    cfg,
+		publisher: publisher,
 	}
 }
 
@@ -75,15 +208,127 @@ func (u *memoUsecase) CreateMemo(ctx context.Context, req CreateMemoRequest) (*d
 	memo := &domain.Memo{
 		Title:     req.Title,
 		Content:   req.Content,
-		Category:  req.Category,
+		Category:  u.normalizeCategory(req.Category),
 		Tags:      u.normalizeTags(req.Tags),
 		Priority:  priority,
 		Status:    domain.StatusActive,
+		DueDate:   req.DueDate,
+		RemindAt:  req.RemindAt,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	return u.memoRepo.Create(ctx, memo)
+	created, err := u.memoRepo.Create(ctx, memo)
+	if err != nil {
+		return nil, err
+	}
+	u.publish(ctx, domain.MemoEventCreated, created)
+	return created, nil
+}
+
+// ImportMemos recreates memos (for example from an export) for the caller with new IDs.
+// Without atomic each memo is created independently and failures are reported per index.
+// With atomic nothing is created when any memo is invalid, and the inserts share one transaction.
+func (u *memoUsecase) ImportMemos(ctx context.Context, reqs []CreateMemoRequest, atomic bool) (*ImportResult, error) {
+	if len(reqs) == 0 || len(reqs) > MaxImportMemos {
+		return nil, ErrInvalidImport
+	}
+
+	result := &ImportResult{Failed: []ImportFailure{}}
+	if !atomic {
+		for i, req := range reqs {
+			if _, err := u.CreateMemo(ctx, req); err != nil {
+				result.Failed = append(result.Failed, ImportFailure{Index: i, Err: err})
+				continue
+			}
+			result.Imported++
+		}
+		return result, nil
+	}
+
+	memos := make([]domain.Memo, 0, len(reqs))
+	for i, req := range reqs {
+		if err := u.validateCreateRequest(req); err != nil {
+			result.Failed = append(result.Failed, ImportFailure{Index: i, Err: err})
+			continue
+		}
+
+		priority := domain.Priority(req.Priority)
+		if req.Priority == "" {
+			priority = domain.PriorityMedium // デフォルト値
+		}
+		memos = append(memos, domain.Memo{
+			Title:    req.Title,
+			Content:  req.Content,
+			Category: u.normalizeCategory(req.Category),
+			Tags:     u.normalizeTags(req.Tags),
+			Priority: priority,
+			Status:   domain.StatusActive,
+			DueDate:  req.DueDate,
+			RemindAt: req.RemindAt,
+		})
+	}
+	if len(result.Failed) > 0 {
+		return result, nil
+	}
+
+	created, err := u.memoRepo.CreateMany(ctx, memos)
+	if err != nil {
+		return nil, err
+	}
+	// CreateMemo と同じく作成イベントを通知する（CreateMany はコミット済みで返る）
+	for i := range created {
+		u.publish(ctx, domain.MemoEventCreated, &created[i])
+	}
+	result.Imported = len(created)
+	return result, nil
+}
+
+// ImportMemosWithIDs creates memos keeping their client-supplied IDs, all or nothing.
+// It is meant for admin import and migration tools; CreateMemo always assigns a new ID.
+func (u *memoUsecase) ImportMemosWithIDs(ctx context.Context, reqs []ImportMemoRequest) ([]domain.Memo, error) {
+	if len(reqs) == 0 || len(reqs) > MaxBulkIDs {
+		return nil, ErrInvalidImportIDs
+	}
+
+	seen := make(map[int]bool, len(reqs))
+	memos := make([]domain.Memo, 0, len(reqs))
+	for _, req := range reqs {
+		if req.ID <= 0 || seen[req.ID] {
+			return nil, ErrInvalidImportIDs
+		}
+		seen[req.ID] = true
+
+		if err := u.validateCreateRequest(req.CreateMemoRequest); err != nil {
+			return nil, err
+		}
+
+		priority := domain.Priority(req.Priority)
+		if req.Priority == "" {
+			priority = domain.PriorityMedium // デフォルト値
+		}
+
+		memos = append(memos, domain.Memo{
+			ID:       req.ID,
+			Title:    req.Title,
+			Content:  req.Content,
+			Category: u.normalizeCategory(req.Category),
+			Tags:     u.normalizeTags(req.Tags),
+			Priority: priority,
+			Status:   domain.StatusActive,
+			DueDate:  req.DueDate,
+			RemindAt: req.RemindAt,
+		})
+	}
+
+	created, err := u.memoRepo.CreateWithIDs(ctx, memos)
+	if err != nil {
+		if strings.Contains(err.Error(), "memo id already exists") {
+			return nil, ErrMemoIDConflict
+		}
+		return nil, err
+	}
+	return created, nil
 }
 
 // GetMemo retrieves a memo by ID
@@ -91,7 +336,7 @@ func (u *memoUsecase) GetMemo(ctx context.Context, id int) (*domain.Memo, error)
 	memo, err := u.memoRepo.GetByID(ctx, id)
 	if err != nil {
 		if strings.Contains(err.Error(), "memo not found") {
-			return nil, ErrMemoNotFound
+			return nil, u.memoNotFound(ctx, id)
 		}
 		return nil, err
 	}
@@ -107,6 +352,11 @@ func (u *memoUsecase) ListMemos(ctx context.Context, filter domain.MemoFilter) (
 	return u.memoRepo.List(ctx, filter)
 }
 
+// ExportMemos streams every memo of the caller to fn in ID order, for backups
+func (u *memoUsecase) ExportMemos(ctx context.Context, fn func(*domain.Memo) error) error {
+	return u.memoRepo.ForEach(ctx, fn)
+}
+
 // UpdateMemo updates an existing memo
 func (u *memoUsecase) UpdateMemo(ctx context.Context, id int, req UpdateMemoRequest) (*domain.Memo, error) {
 	if err := u.validateUpdateRequest(req); err != nil {
@@ -114,12 +364,15 @@ func (u *memoUsecase) UpdateMemo(ctx context.Context, id int, req UpdateMemoRequ
 	}
 
 	// 既存のメモを取得
-	existingMemo, err := u.memoRepo.GetByID(ctx, id)
+	existingMemo, err := u.GetMemo(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if req.Version != nil && *req.Version != existingMemo.Version {
+		return nil, ErrVersionConflict
+	}
 
-	// 更新フィールドを適用
+	// 更新フィールドを適用（取得後に他の更新があった場合もリポジトリで競合として検出する）
 	updatedMemo := *existingMemo
 
 	if req.Title != nil {
@@ -129,7 +382,7 @@ func (u *memoUsecase) UpdateMemo(ctx context.Context, id int, req UpdateMemoRequ
 		updatedMemo.Content = *req.Content
 	}
 	if req.Category != nil {
-		updatedMemo.Category = *req.Category
+		updatedMemo.Category = u.normalizeCategory(*req.Category)
 	}
 	if req.Tags != nil {
 		updatedMemo.Tags = u.normalizeTags(req.Tags)
@@ -139,26 +392,348 @@ func (u *memoUsecase) UpdateMemo(ctx context.Context, id int, req UpdateMemoRequ
 	}
 	if req.Status != nil {
 		updatedMemo.Status = domain.Status(*req.Status)
+		if updatedMemo.Status != domain.StatusActive {
+			if err := u.guardProtectedCategory(ctx, u.memoRepo, existingMemo); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if req.DueDate != nil {
+		updatedMemo.DueDate = req.DueDate
+	}
+	if req.RemindAt != nil {
+		updatedMemo.RemindAt = req.RemindAt
+		updatedMemo.Reminded = false
 	}
 
 	updatedMemo.UpdatedAt = time.Now()
 
-	return u.memoRepo.Update(ctx, id, &updatedMemo)
+	memo, err := u.memoRepo.Update(ctx, id, &updatedMemo)
+	if err != nil {
+		if strings.Contains(err.Error(), "version conflict") {
+			return nil, ErrVersionConflict
+		}
+		if strings.Contains(err.Error(), "memo not found") {
+			return nil, u.memoNotFound(ctx, id)
+		}
+		return nil, err
+	}
+	u.publish(ctx, domain.MemoEventUpdated, memo)
+	return memo, nil
+}
+
+// ListMemoHistory returns the previous versions of a memo, newest first
+func (u *memoUsecase) ListMemoHistory(ctx context.Context, id int) ([]domain.MemoRevision, error) {
+	if _, err := u.GetMemo(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.memoRepo.ListRevisions(ctx, id)
 }
 
-// DeleteMemo deletes a memo
+// RevertMemo restores the title, content, category, tags and priority of a previous revision.
+// The revert is a regular update, so the replaced version is kept as a new revision and no history is lost.
+func (u *memoUsecase) RevertMemo(ctx context.Context, id, revisionID int) (*domain.Memo, error) {
+	existingMemo, err := u.GetMemo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := u.memoRepo.GetRevision(ctx, id, revisionID)
+	if err != nil {
+		if strings.Contains(err.Error(), "revision not found") {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+
+	revertedMemo := *existingMemo
+	revertedMemo.Title = revision.Title
+	revertedMemo.Content = revision.Content
+	revertedMemo.Category = revision.Category
+	revertedMemo.Tags = revision.Tags
+	revertedMemo.Priority = revision.Priority
+	revertedMemo.UpdatedAt = time.Now()
+
+	memo, err := u.memoRepo.Update(ctx, id, &revertedMemo)
+	if err != nil {
+		if strings.Contains(err.Error(), "version conflict") {
+			return nil, ErrVersionConflict
+		}
+		return nil, err
+	}
+	u.publish(ctx, domain.MemoEventUpdated, memo)
+	return memo, nil
+}
+
+// UpdateMemoMetadata updates only the category, tags, priority, color and due date of a memo.
+// Unlike UpdateMemo it never reads or writes the content, so partial clients cannot overwrite it.
+func (u *memoUsecase) UpdateMemoMetadata(ctx context.Context, id int, req UpdateMemoMetadataRequest) (*domain.Memo, error) {
+	if req.Category == nil && req.Tags == nil && req.Priority == nil && req.Color == nil && req.DueDate == nil && !req.ClearDueDate {
+		return nil, ErrEmptyMetadataUpdate
+	}
+	if req.Priority != nil && !domain.Priority(*req.Priority).IsValid() {
+		return nil, ErrInvalidPriority
+	}
+	if req.Color != nil && *req.Color != "" && !hexColorPattern.MatchString(*req.Color) {
+		return nil, ErrInvalidColor
+	}
+
+	update := domain.MemoMetadataUpdate{Color: req.Color, DueDate: req.DueDate, DueDateSet: req.DueDate != nil}
+	if req.ClearDueDate {
+		update.DueDate = nil
+		update.DueDateSet = true
+	}
+	if req.Category != nil {
+		category := u.normalizeCategory(*req.Category)
+		update.Category = &category
+	}
+	if req.Tags != nil {
+		update.Tags = u.normalizeTags(req.Tags)
+	}
+	if req.Priority != nil {
+		priority := domain.Priority(*req.Priority)
+		update.Priority = &priority
+	}
+
+	memo, err := u.memoRepo.UpdateMetadata(ctx, id, update)
+	if err != nil {
+		if strings.Contains(err.Error(), "memo not found") {
+			return nil, u.memoNotFound(ctx, id)
+		}
+		return nil, err
+	}
+	u.publish(ctx, domain.MemoEventUpdated, memo)
+	return memo, nil
+}
+
+// DeleteMemo moves a memo one stage towards deletion: an active memo is archived and an archived memo is
+// moved to the trash. A memo already in the trash is rejected with ErrMemoAlreadyTrashed; only
+// PermanentDeleteMemo removes it. When the memo has to be inspected first (protected categories) its
+// row is locked and changed in the same transaction, so a concurrent change cannot slip in between
 func (u *memoUsecase) DeleteMemo(ctx context.Context, id int) error {
-	return u.memoRepo.Delete(ctx, id)
+	err := u.withinTx(ctx, func(tx domain.MemoTx) error {
+		if len(u.config.ProtectedCategories) > 0 {
+			memo, err := tx.GetByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err := u.guardProtectedCategory(ctx, tx, memo); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(ctx, id)
+	})
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "memo not found"):
+			return u.memoNotFound(ctx, id)
+		case strings.Contains(err.Error(), "memo is already in trash"):
+			return ErrMemoAlreadyTrashed
+		}
+		return err
+	}
+	u.publishByID(ctx, domain.MemoEventUpdated, id)
+	return nil
+}
+
+// BulkDeleteMemos moves the given memos one stage towards deletion atomically, like DeleteMemo, and reports
+// the outcome per ID. Duplicate IDs are collapsed; memos that are missing, owned by another user or already
+// in the trash are reported as not found.
+func (u *memoUsecase) BulkDeleteMemos(ctx context.Context, ids []int) ([]BulkResult, error) {
+	unique, err := uniqueBulkIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.guardProtectedCategoryBulk(ctx, unique); err != nil {
+		return nil, err
+	}
+
+	deletedIDs, err := u.memoRepo.DeleteMany(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make(map[int]bool, len(deletedIDs))
+	for _, id := range deletedIDs {
+		deleted[id] = true
+	}
+
+	results := make([]BulkResult, 0, len(unique))
+	for _, id := range unique {
+		status := BulkStatusNotFound
+		if deleted[id] {
+			status = BulkStatusDeleted
+		}
+		results = append(results, BulkResult{ID: id, Status: status})
+	}
+	return results, nil
+}
+
+// BulkUpdateMemos applies the same status and/or category change to several memos atomically.
+// The request follows UpdateMemo semantics; fields other than status and category are rejected.
+func (u *memoUsecase) BulkUpdateMemos(ctx context.Context, ids []int, req UpdateMemoRequest) (*BulkUpdateResult, error) {
+	unique, err := uniqueBulkIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil || req.Content != nil || req.Tags != nil || req.Priority != nil || req.DueDate != nil || req.RemindAt != nil {
+		return nil, ErrInvalidBulkUpdate
+	}
+	if req.Status == nil && req.Category == nil {
+		return nil, ErrInvalidBulkUpdate
+	}
+	if err := u.validateUpdateRequest(req); err != nil {
+		return nil, err
+	}
+
+	var update domain.MemoBulkUpdate
+	if req.Category != nil {
+		category := u.normalizeCategory(*req.Category)
+		update.Category = &category
+	}
+	if req.Status != nil {
+		status := domain.Status(*req.Status)
+		update.Status = &status
+		if status != domain.StatusActive {
+			if err := u.guardProtectedCategoryBulk(ctx, unique); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	updatedIDs, err := u.memoRepo.BulkUpdate(ctx, unique, update)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make(map[int]bool, len(updatedIDs))
+	for _, id := range updatedIDs {
+		updated[id] = true
+	}
+
+	result := &BulkUpdateResult{Updated: len(updatedIDs), SkippedIDs: []int{}}
+	for _, id := range unique {
+		if !updated[id] {
+			result.SkippedIDs = append(result.SkippedIDs, id)
+		}
+	}
+	return result, nil
+}
+
+// uniqueBulkIDs validates the IDs of a bulk operation and removes duplicates while keeping order
+func uniqueBulkIDs(ids []int) ([]int, error) {
+	if len(ids) == 0 || len(ids) > MaxBulkIDs {
+		return nil, ErrInvalidBulkIDs
+	}
+
+	unique := make([]int, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, ErrInvalidBulkIDs
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique, nil
 }
 
 // ArchiveMemo archives a memo
 func (u *memoUsecase) ArchiveMemo(ctx context.Context, id int) error {
-	return u.memoRepo.Archive(ctx, id)
+	if err := u.guardProtectedCategoryByID(ctx, id); err != nil {
+		return err
+	}
+	if err := u.memoRepo.Archive(ctx, id); err != nil {
+		if strings.Contains(err.Error(), "memo not found") {
+			return u.memoNotFound(ctx, id)
+		}
+		return err
+	}
+	u.publishByID(ctx, domain.MemoEventUpdated, id)
+	return nil
+}
+
+// TrashMemo moves a memo to the trash
+func (u *memoUsecase) TrashMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	if err := u.guardProtectedCategoryByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	memo, err := u.memoRepo.Trash(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "memo not found") {
+			return nil, u.memoNotFound(ctx, id)
+		}
+		return nil, err
+	}
+	u.publish(ctx, domain.MemoEventUpdated, memo)
+	return memo, nil
 }
 
-// RestoreMemo restores an archived memo
+// PermanentDeleteMemo physically deletes a memo that is in the trash. The memo reported in the event is
+// read under a row lock in the same transaction as the delete
+func (u *memoUsecase) PermanentDeleteMemo(ctx context.Context, id int) error {
+	var deleted *domain.Memo
+	err := u.withinTx(ctx, func(tx domain.MemoTx) error {
+		if u.publisher != nil {
+			memo, err := tx.GetByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			deleted = memo
+		}
+		return tx.PermanentDelete(ctx, id)
+	})
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "memo not found"):
+			return u.memoNotFound(ctx, id)
+		case strings.Contains(err.Error(), "memo is not in trash"):
+			return ErrMemoNotTrashed
+		}
+		return err
+	}
+	u.publish(ctx, domain.MemoEventDeleted, deleted)
+	return nil
+}
+
+// ListDeletedMemos lists the caller's permanently deleted memos that are still in the recycle log
+func (u *memoUsecase) ListDeletedMemos(ctx context.Context, filter domain.MemoFilter) ([]domain.DeletedMemo, int, error) {
+	if err := u.validateAndNormalizeFilter(&filter); err != nil {
+		return nil, 0, err
+	}
+
+	return u.memoRepo.ListDeleted(ctx, filter)
+}
+
+// RestoreDeletedMemo restores a permanently deleted memo from the recycle log as an active memo.
+// The memo keeps its old ID unless another memo has taken it in the meantime
+func (u *memoUsecase) RestoreDeletedMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	memo, err := u.memoRepo.RestoreDeleted(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "deleted memo not found") {
+			return nil, ErrDeletedMemoNotFound
+		}
+		return nil, err
+	}
+	// リサイクルログからの復元は購読側からはメモが再び作成されたように見える
+	u.publish(ctx, domain.MemoEventCreated, memo)
+	return memo, nil
+}
+
+// RestoreMemo restores an archived or trashed memo
 func (u *memoUsecase) RestoreMemo(ctx context.Context, id int) error {
-	return u.memoRepo.Restore(ctx, id)
+	if err := u.memoRepo.Restore(ctx, id); err != nil {
+		if strings.Contains(err.Error(), "memo not found") {
+			return u.memoNotFound(ctx, id)
+		}
+		return err
+	}
+	u.publishByID(ctx, domain.MemoEventUpdated, id)
+	return nil
 }
 
 // SearchMemos searches memos
@@ -167,7 +742,188 @@ func (u *memoUsecase) SearchMemos(ctx context.Context, query string, filter doma
 		return nil, 0, err
 	}
 
-	return u.memoRepo.Search(ctx, query, filter)
+	memos, total, err := u.memoRepo.Search(ctx, query, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 検索履歴の保存失敗は検索結果に影響させない（リポジトリ側でログ出力済み）
+	if strings.TrimSpace(query) != "" && u.config.RecentQueriesLimit > 0 {
+		_ = u.memoRepo.RecordSearchQuery(ctx, query, u.config.RecentQueriesLimit)
+	}
+
+	return memos, total, nil
+}
+
+// ListSharedMemos lists memos other users have shared with the caller
+func (u *memoUsecase) ListSharedMemos(ctx context.Context, filter domain.MemoFilter) ([]domain.SharedMemo, int, error) {
+	if err := u.validateAndNormalizeFilter(&filter); err != nil {
+		return nil, 0, err
+	}
+
+	return u.memoRepo.ListSharedWithUser(ctx, filter)
+}
+
+// ListOnThisDay lists memos created on today's month and day in previous years
+func (u *memoUsecase) ListOnThisDay(ctx context.Context) ([]domain.Memo, error) {
+	return u.memoRepo.ListOnThisDay(ctx, time.Now())
+}
+
+// RecentSearchQueries returns the user's recent search queries, newest first
+func (u *memoUsecase) RecentSearchQueries(ctx context.Context) ([]string, error) {
+	return u.memoRepo.ListSearchQueries(ctx)
+}
+
+// ClearRecentSearchQueries clears the user's recent search queries
+func (u *memoUsecase) ClearRecentSearchQueries(ctx context.Context) error {
+	return u.memoRepo.ClearSearchQueries(ctx)
+}
+
+// ListTags returns the tags in use ordered by count desc then tag asc.
+// The limit defaults to TagsDefaultLimit and is capped at TagsMaxLimit.
+func (u *memoUsecase) ListTags(ctx context.Context, filter domain.TagFilter) ([]domain.TagCount, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = u.config.TagsDefaultLimit
+	}
+	if u.config.TagsMaxLimit > 0 && filter.Limit > u.config.TagsMaxLimit {
+		filter.Limit = u.config.TagsMaxLimit
+	}
+	if filter.MinCount <= 0 {
+		filter.MinCount = 1
+	}
+	return u.memoRepo.ListTags(ctx, filter)
+}
+
+// ListCategories returns the distinct categories the user has already used
+func (u *memoUsecase) ListCategories(ctx context.Context) ([]string, error) {
+	return u.memoRepo.ListCategories(ctx)
+}
+
+// GetMemoStats returns the memo counts of the authenticated user, or of all memos when unauthenticated
+func (u *memoUsecase) GetMemoStats(ctx context.Context) (*domain.MemoStats, error) {
+	return u.memoRepo.Stats(ctx)
+}
+
+// GetMemoListState returns the state of the authenticated user's memos, or of all memos when unauthenticated,
+// for validating cached lists
+func (u *memoUsecase) GetMemoListState(ctx context.Context) (*domain.MemoListState, error) {
+	return u.memoRepo.MaxUpdatedAt(ctx)
+}
+
+// PromoteMemo raises the memo to the configured priority and pins it
+func (u *memoUsecase) PromoteMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	priority := domain.Priority(u.config.PromotePriority)
+	if !priority.IsValid() {
+		priority = domain.PriorityHigh
+	}
+
+	memo, err := u.memoRepo.Promote(ctx, id, priority)
+	if err != nil {
+		if strings.Contains(err.Error(), "memo not found") {
+			return nil, u.memoNotFound(ctx, id)
+		}
+		return nil, err
+	}
+	u.publish(ctx, domain.MemoEventUpdated, memo)
+	return memo, nil
+}
+
+// TouchMemo marks the memo as recently used by bumping updated_at only
+func (u *memoUsecase) TouchMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	memo, err := u.memoRepo.Touch(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "memo not found") {
+			return nil, u.memoNotFound(ctx, id)
+		}
+		return nil, err
+	}
+	return memo, nil
+}
+
+// PinMemo pins a memo so that it is listed first
+func (u *memoUsecase) PinMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	return u.setPinned(ctx, id, true)
+}
+
+// UnpinMemo removes the pin from a memo
+func (u *memoUsecase) UnpinMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	return u.setPinned(ctx, id, false)
+}
+
+func (u *memoUsecase) setPinned(ctx context.Context, id int, pinned bool) (*domain.Memo, error) {
+	memo, err := u.memoRepo.SetPinned(ctx, id, pinned)
+	if err != nil {
+		if strings.Contains(err.Error(), "memo not found") {
+			return nil, u.memoNotFound(ctx, id)
+		}
+		return nil, err
+	}
+	u.publish(ctx, domain.MemoEventUpdated, memo)
+	return memo, nil
+}
+
+// GetMemoDetail retrieves a memo and only the related collections requested by expand
+func (u *memoUsecase) GetMemoDetail(ctx context.Context, id int, expand domain.MemoExpand) (*domain.MemoDetail, error) {
+	memo, err := u.GetMemo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &domain.MemoDetail{Memo: memo}
+
+	if expand.Revisions {
+		detail.Revisions, err = u.memoRepo.ListRevisions(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if expand.Attachments {
+		detail.Attachments, err = u.memoRepo.ListAttachments(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return detail, nil
+}
+
+// publish reports a memo change to the publisher, if any
+func (u *memoUsecase) publish(ctx context.Context, event string, memo *domain.Memo) {
+	if u.publisher == nil || memo == nil {
+		return
+	}
+	u.publisher.PublishMemoEvent(ctx, event, memo)
+}
+
+// publishByID reloads a memo changed by an operation that does not return it and reports the change
+func (u *memoUsecase) publishByID(ctx context.Context, event string, id int) {
+	if u.publisher == nil {
+		return
+	}
+	memo, err := u.memoRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	u.publish(ctx, event, memo)
+}
+
+// withinTx runs fn in a transaction when the repository supports it (domain.MemoTransactor).
+// Otherwise fn runs directly on the repository and GetByIDForUpdate is a plain GetByID
+func (u *memoUsecase) withinTx(ctx context.Context, fn func(tx domain.MemoTx) error) error {
+	if transactor, ok := u.memoRepo.(domain.MemoTransactor); ok {
+		return transactor.WithinTx(ctx, fn)
+	}
+	return fn(unlockedMemoTx{u.memoRepo})
+}
+
+// unlockedMemoTx adapts a repository without transaction support to domain.MemoTx
+type unlockedMemoTx struct {
+	domain.MemoRepository
+}
+
+func (r unlockedMemoTx) GetByIDForUpdate(ctx context.Context, id int) (*domain.Memo, error) {
+	return r.GetByID(ctx, id)
 }
 
 // validateCreateRequest validates create memo request
@@ -178,6 +934,9 @@ func (u *memoUsecase) validateCreateRequest(req CreateMemoRequest) error {
 	if req.Content == "" {
 		return ErrInvalidContent
 	}
+	if u.contentTooLong(req.Content) {
+		return ErrContentTooLong
+	}
 	if req.Priority != "" && !domain.Priority(req.Priority).IsValid() {
 		return ErrInvalidPriority
 	}
@@ -192,6 +951,9 @@ func (u *memoUsecase) validateUpdateRequest(req UpdateMemoRequest) error {
 	if req.Content != nil && *req.Content == "" {
 		return ErrInvalidContent
 	}
+	if req.Content != nil && u.contentTooLong(*req.Content) {
+		return ErrContentTooLong
+	}
 	if req.Priority != nil && !domain.Priority(*req.Priority).IsValid() {
 		return ErrInvalidPriority
 	}
@@ -201,16 +963,22 @@ func (u *memoUsecase) validateUpdateRequest(req UpdateMemoRequest) error {
 	return nil
 }
 
+// contentTooLong reports whether content exceeds the configured hard limit (in characters)
+func (u *memoUsecase) contentTooLong(content string) bool {
+	return u.config.ContentMaxLength > 0 && utf8.RuneCountInString(content) > u.config.ContentMaxLength
+}
+
 // validateAndNormalizeFilter validates and normalizes filter
 func (u *memoUsecase) validateAndNormalizeFilter(filter *domain.MemoFilter) error {
 	if filter.Page <= 0 {
 		filter.Page = 1
 	}
+	defaultSize, maxSize := u.config.PageSizes()
 	if filter.Limit <= 0 {
-		filter.Limit = 10
+		filter.Limit = defaultSize
 	}
-	if filter.Limit > 100 {
-		filter.Limit = 100
+	if filter.Limit > maxSize {
+		filter.Limit = maxSize
 	}
 
 	if filter.Status != "" && !filter.Status.IsValid() {
@@ -220,10 +988,16 @@ func (u *memoUsecase) validateAndNormalizeFilter(filter *domain.MemoFilter) erro
 		return ErrInvalidPriority
 	}
 
+	// 保存時と同じ形に揃えて、正規化済みのカテゴリー・タグに一致させる
+	filter.Category = u.normalizeCategory(filter.Category)
+	if len(filter.Tags) > 0 {
+		filter.Tags = u.normalizeTags(filter.Tags)
+	}
+
 	return nil
 }
 
-// normalizeTags normalizes tags by removing empty ones and duplicates
+// normalizeTags normalizes each tag with normalizeLabel and removes empty ones and duplicates
 func (u *memoUsecase) normalizeTags(tags []string) []string {
 	if len(tags) == 0 {
 		return []string{}
@@ -233,12 +1007,141 @@ func (u *memoUsecase) normalizeTags(tags []string) []string {
 	result := make([]string, 0, len(tags))
 
 	for _, tag := range tags {
-		trimmed := strings.TrimSpace(tag)
-		if trimmed != "" && !seen[trimmed] {
-			seen[trimmed] = true
-			result = append(result, trimmed)
+		normalized := normalizeLabel(tag, u.config.CaseInsensitiveTags)
+		if normalized != "" && !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
 		}
 	}
 
 	return result
 }
+
+// normalizeLabel brings a tag or category into Unicode NFKC form, trims it, collapses inner whitespace
+// to single spaces and optionally lowercases it, so "Work", "work ", "WORK" and "Ｗｏｒｋ" are stored
+// and aggregated as one value. NFKC also unifies half-width katakana ("ﾒﾓ" becomes "メモ")
+func normalizeLabel(label string, lowercase bool) string {
+	normalized := strings.Join(strings.Fields(norm.NFKC.String(label)), " ")
+	if lowercase {
+		return strings.ToLower(normalized)
+	}
+	return normalized
+}
+
+// guardProtectedCategoryByID loads the memo and applies guardProtectedCategory.
+// The lookup is skipped entirely when no protected categories are configured.
+func (u *memoUsecase) guardProtectedCategoryByID(ctx context.Context, id int) error {
+	if len(u.config.ProtectedCategories) == 0 {
+		return nil
+	}
+
+	memo, err := u.memoRepo.GetByID(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "memo not found") {
+			return u.memoNotFound(ctx, id)
+		}
+		return err
+	}
+	return u.guardProtectedCategory(ctx, u.memoRepo, memo)
+}
+
+// memoNotFound returns the error for a memo the caller cannot see. Unless RevealMemoOwnership is enabled
+// it is always ErrMemoNotFound so that the existence of other users' memos is not leaked; otherwise a memo
+// that exists for another user yields ErrMemoForbidden
+func (u *memoUsecase) memoNotFound(ctx context.Context, id int) error {
+	if !u.config.RevealMemoOwnership {
+		return ErrMemoNotFound
+	}
+
+	exists, err := u.memoRepo.Exists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrMemoForbidden
+	}
+	return ErrMemoNotFound
+}
+
+// guardProtectedCategoryBulk rejects a bulk operation that would take every remaining
+// active memo of a protected category out of the active state. Missing memos are ignored.
+func (u *memoUsecase) guardProtectedCategoryBulk(ctx context.Context, ids []int) error {
+	if len(u.config.ProtectedCategories) == 0 {
+		return nil
+	}
+
+	targeted := make(map[string]int)
+	categories := make(map[string]string)
+	for _, id := range ids {
+		memo, err := u.memoRepo.GetByID(ctx, id)
+		if err != nil {
+			if strings.Contains(err.Error(), "memo not found") {
+				continue
+			}
+			return err
+		}
+		if memo.Status != domain.StatusActive || !u.isProtectedCategory(memo.Category) {
+			continue
+		}
+		key := u.normalizeCategory(memo.Category)
+		targeted[key]++
+		categories[key] = memo.Category
+	}
+
+	for key, count := range targeted {
+		_, active, err := u.memoRepo.List(ctx, domain.MemoFilter{
+			Category: categories[key],
+			Status:   domain.StatusActive,
+			Page:     1,
+			Limit:    1,
+		})
+		if err != nil {
+			return err
+		}
+		if active <= count {
+			return ErrLastActiveInCategory
+		}
+	}
+	return nil
+}
+
+// guardProtectedCategory rejects taking the memo out of the active state
+// when it is the last active memo of a protected category. The active memos are counted with repo
+// so that the check can run inside a transaction
+func (u *memoUsecase) guardProtectedCategory(ctx context.Context, repo domain.MemoRepository, memo *domain.Memo) error {
+	if memo.Status != domain.StatusActive || !u.isProtectedCategory(memo.Category) {
+		return nil
+	}
+
+	_, active, err := repo.List(ctx, domain.MemoFilter{
+		Category: memo.Category,
+		Status:   domain.StatusActive,
+		Page:     1,
+		Limit:    1,
+	})
+	if err != nil {
+		return err
+	}
+	if active <= 1 {
+		return ErrLastActiveInCategory
+	}
+	return nil
+}
+
+// isProtectedCategory reports whether the category must always keep at least one active memo
+func (u *memoUsecase) isProtectedCategory(category string) bool {
+	if category == "" {
+		return false
+	}
+	for _, protected := range u.config.ProtectedCategories {
+		if u.normalizeCategory(protected) == u.normalizeCategory(category) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeCategory normalizes the category with normalizeLabel, lowercasing it when case-insensitive matching is enabled
+func (u *memoUsecase) normalizeCategory(category string) string {
+	return normalizeLabel(category, u.config.CaseInsensitiveCategory)
+}
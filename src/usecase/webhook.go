@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+
+	"memo-app/src/domain"
+)
+
+var (
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrWebhookUnauthorized  = errors.New("webhooks require an authenticated user")
+	ErrInvalidWebhookURL    = errors.New("url must be an absolute http or https URL")
+	ErrInvalidWebhookEvents = errors.New("events must contain at least one of memo.created, memo.updated or memo.deleted")
+	ErrInvalidWebhookSecret = errors.New("secret must be between 16 and 255 characters")
+)
+
+// Webhook secret length limits
+const (
+	MinWebhookSecretLength = 16
+	MaxWebhookSecretLength = 255
+)
+
+// webhookSecretBytes is the number of random bytes of a generated secret (hex encoded to twice the length)
+const webhookSecretBytes = 32
+
+// CreateWebhookRequest represents input for registering a webhook.
+// Events defaults to every memo event and Secret is generated when empty
+type CreateWebhookRequest struct {
+	URL    string
+	Secret string
+	Events []string
+}
+
+// UpdateWebhookRequest represents input for updating a webhook; nil fields are left unchanged
+type UpdateWebhookRequest struct {
+	URL    *string
+	Secret *string
+	Events []string
+}
+
+// WebhookUsecase defines the interface for managing the caller's webhooks
+type WebhookUsecase interface {
+	CreateWebhook(ctx context.Context, req CreateWebhookRequest) (*domain.Webhook, error)
+	GetWebhook(ctx context.Context, id int) (*domain.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	UpdateWebhook(ctx context.Context, id int, req UpdateWebhookRequest) (*domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int) error
+}
+
+type webhookUsecase struct {
+	webhookRepo domain.WebhookRepository
+}
+
+// NewWebhookUsecase creates a new webhook usecase
+func NewWebhookUsecase(webhookRepo domain.WebhookRepository) WebhookUsecase {
+	return &webhookUsecase{webhookRepo: webhookRepo}
+}
+
+// CreateWebhook registers a webhook for the caller
+func (u *webhookUsecase) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (*domain.Webhook, error) {
+	if _, ok := domain.UserIDFromContext(ctx); !ok {
+		return nil, ErrWebhookUnauthorized
+	}
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	events := req.Events
+	if len(events) == 0 {
+		events = domain.MemoEvents
+	}
+	events, err := normalizeWebhookEvents(events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	} else if err := validateWebhookSecret(secret); err != nil {
+		return nil, err
+	}
+
+	return u.webhookRepo.Create(ctx, &domain.Webhook{URL: req.URL, Secret: secret, Events: events})
+}
+
+// GetWebhook retrieves a webhook of the caller
+func (u *webhookUsecase) GetWebhook(ctx context.Context, id int) (*domain.Webhook, error) {
+	if _, ok := domain.UserIDFromContext(ctx); !ok {
+		return nil, ErrWebhookUnauthorized
+	}
+
+	webhook, err := u.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "webhook not found") {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// ListWebhooks lists the caller's webhooks
+func (u *webhookUsecase) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	if _, ok := domain.UserIDFromContext(ctx); !ok {
+		return nil, ErrWebhookUnauthorized
+	}
+	return u.webhookRepo.List(ctx)
+}
+
+// UpdateWebhook changes the URL, secret or events of a webhook of the caller
+func (u *webhookUsecase) UpdateWebhook(ctx context.Context, id int, req UpdateWebhookRequest) (*domain.Webhook, error) {
+	existing, err := u.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		updated.URL = *req.URL
+	}
+	if req.Secret != nil {
+		if err := validateWebhookSecret(*req.Secret); err != nil {
+			return nil, err
+		}
+		updated.Secret = *req.Secret
+	}
+	if req.Events != nil {
+		if updated.Events, err = normalizeWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook, err := u.webhookRepo.Update(ctx, id, &updated)
+	if err != nil {
+		if strings.Contains(err.Error(), "webhook not found") {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook of the caller
+func (u *webhookUsecase) DeleteWebhook(ctx context.Context, id int) error {
+	if _, ok := domain.UserIDFromContext(ctx); !ok {
+		return ErrWebhookUnauthorized
+	}
+
+	if err := u.webhookRepo.Delete(ctx, id); err != nil {
+		if strings.Contains(err.Error(), "webhook not found") {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// validateWebhookURL accepts only absolute http(s) URLs
+func validateWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// validateWebhookSecret checks the length of a client-supplied secret
+func validateWebhookSecret(secret string) error {
+	if len(secret) < MinWebhookSecretLength || len(secret) > MaxWebhookSecretLength {
+		return ErrInvalidWebhookSecret
+	}
+	return nil
+}
+
+// normalizeWebhookEvents rejects unknown events and removes duplicates, keeping the given order
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return nil, ErrInvalidWebhookEvents
+	}
+
+	seen := make(map[string]bool, len(events))
+	normalized := make([]string, 0, len(events))
+	for _, event := range events {
+		if !isMemoEvent(event) {
+			return nil, ErrInvalidWebhookEvents
+		}
+		if seen[event] {
+			continue
+		}
+		seen[event] = true
+		normalized = append(normalized, event)
+	}
+	return normalized, nil
+}
+
+// isMemoEvent reports whether event is one of domain.MemoEvents
+func isMemoEvent(event string) bool {
+	for _, known := range domain.MemoEvents {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
+
+// generateWebhookSecret returns a random hex-encoded secret
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -8,14 +8,23 @@ import (
 	"unicode/utf8"
 
 	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/unicode/norm"
 )
 
+// defaultReservedUsernames ユーザー名として使用できない予約語（デフォルト）
+var defaultReservedUsernames = []string{
+	"admin", "root", "api", "www", "mail", "ftp", "localhost",
+	"test", "user", "guest", "demo", "support", "help", "info",
+	"null", "undefined", "system", "config", "settings",
+}
+
 // CustomValidator は拡張バリデーション機能を提供
 type CustomValidator struct {
 	validator           *validator.Validate
 	categoryPattern     *regexp.Regexp
 	tagPattern          *regexp.Regexp
 	sqlInjectionPattern *regexp.Regexp
+	reservedUsernames   map[string]bool
 }
 
 // ValidationError はバリデーションエラーの詳細情報
@@ -35,6 +44,20 @@ func (ve ValidationErrors) Error() string {
 	return fmt.Sprintf("validation failed: %d errors", len(ve.Errors))
 }
 
+// SecurityRejection は攻撃の可能性があるとして拒否された入力の情報（値そのものは含めない）
+type SecurityRejection struct {
+	Field string
+	Rule  string
+}
+
+// securityRules はセキュリティ監視の対象とするバリデーションルール
+var securityRules = map[string]bool{
+	"safe_text":        true,
+	"safe_category":    true,
+	"safe_tag":         true,
+	"no_sql_injection": true,
+}
+
 // NewCustomValidator creates a new custom validator instance
 func NewCustomValidator() *CustomValidator {
 	v := validator.New()
@@ -42,6 +65,7 @@ func NewCustomValidator() *CustomValidator {
 		validator:           v,
 		categoryPattern:     regexp.MustCompile(`^[a-zA-Z0-9_\-\x{3040}-\x{309F}\x{30A0}-\x{30FF}\x{4E00}-\x{9FAF}]+$`),   // 英数字、ひらがな、カタカナ、漢字
 		tagPattern:          regexp.MustCompile(`^[a-zA-Z0-9_\-\x{3040}-\x{309F}\x{30A0}-\x{30FF}\x{4E00}-\x{9FAF}\s]+$`), // タグは空白も許可
+		reservedUsernames:   make(map[string]bool),
 		sqlInjectionPattern: regexp.MustCompile(`(?i)(\bunion\s+select\b|\bselect\s+.*\bfrom\b|\binsert\s+into\b|\bupdate\s+.*\bset\b|\bdelete\s+from\b|\bdrop\s+table\b|\bcreate\s+table\b|\balter\s+table\b|\bexec\s*\(|<script|</script>|onload\s*=|onerror\s*=|--|/\*|\*/|\|\||(\bor\b|\band\b)\s*(1\s*=\s*1|true|\d+\s*=\s*\d+))`),
 	}
 
@@ -55,9 +79,33 @@ func NewCustomValidator() *CustomValidator {
 	v.RegisterValidation("safe_tag", cv.validateSafeTag)
 	v.RegisterValidation("no_sql_injection", cv.validateNoSQLInjection)
 
+	cv.AddReservedUsernames(defaultReservedUsernames...)
+
+	return cv
+}
+
+// NewUsernameValidator デフォルトの予約語に reserved（RESERVED_USERNAMES）を加えたバリデーターを作成
+func NewUsernameValidator(reserved []string) *CustomValidator {
+	cv := NewCustomValidator()
+	cv.AddReservedUsernames(reserved...)
 	return cv
 }
 
+// IsReservedUsername username が予約語か（大文字小文字は区別しない）
+func (cv *CustomValidator) IsReservedUsername(username string) bool {
+	return cv.reservedUsernames[strings.ToLower(strings.TrimSpace(username))]
+}
+
+// AddReservedUsernames 予約語リストにユーザー名を追加（大文字小文字は区別しない）
+func (cv *CustomValidator) AddReservedUsernames(words ...string) {
+	for _, word := range words {
+		normalized := strings.ToLower(strings.TrimSpace(word))
+		if normalized != "" {
+			cv.reservedUsernames[normalized] = true
+		}
+	}
+}
+
 // Validate validates a struct and returns detailed error information
 func (cv *CustomValidator) Validate(s interface{}) error {
 	if err := cv.validator.Struct(s); err != nil {
@@ -80,6 +128,29 @@ func (cv *CustomValidator) Validate(s interface{}) error {
 	return nil
 }
 
+// SecurityRejections はバリデーションエラーのうち攻撃の可能性があるものを返す
+// 値がSQLインジェクション・XSSパターンに一致した場合は、どのルールで拒否されたかに関わらず no_sql_injection として報告する
+func (cv *CustomValidator) SecurityRejections(err error) []SecurityRejection {
+	validationErrors, ok := err.(ValidationErrors)
+	if !ok {
+		return nil
+	}
+
+	var rejections []SecurityRejection
+	for _, ve := range validationErrors.Errors {
+		if !securityRules[ve.Tag] {
+			continue
+		}
+
+		rule := ve.Tag
+		if value, ok := ve.Value.(string); ok && cv.sqlInjectionPattern.MatchString(value) {
+			rule = "no_sql_injection"
+		}
+		rejections = append(rejections, SecurityRejection{Field: ve.Field, Rule: rule})
+	}
+	return rejections
+}
+
 // SanitizeInput sanitizes input data to prevent XSS and other attacks
 func (cv *CustomValidator) SanitizeInput(input string) string {
 	// HTMLエスケープ
@@ -94,7 +165,10 @@ func (cv *CustomValidator) SanitizeInput(input string) string {
 	return sanitized
 }
 
-// SanitizeTags sanitizes and normalizes tags
+// SanitizeTags sanitizes and normalizes tags.
+// Tags are first brought into Unicode NFKC form, so full-width letters and digits and half-width
+// katakana (e.g. "Ｇｏ", "ﾒﾓ") are kept as "Go" and "メモ" instead of failing the tag pattern,
+// and duplicates are detected on that canonical form
 func (cv *CustomValidator) SanitizeTags(tags []string) []string {
 	if len(tags) == 0 {
 		return []string{}
@@ -104,8 +178,8 @@ func (cv *CustomValidator) SanitizeTags(tags []string) []string {
 	result := make([]string, 0, len(tags))
 
 	for _, tag := range tags {
-		// サニタイズ
-		sanitized := cv.SanitizeInput(tag)
+		// 全角英数字・半角カナなどを標準の表記に揃えてからサニタイズ
+		sanitized := cv.SanitizeInput(norm.NFKC.String(tag))
 
 		// 長さチェック
 		if utf8.RuneCountInString(sanitized) > 30 {
@@ -193,6 +267,20 @@ func (cv *CustomValidator) generateErrorMessage(err validator.FieldError) string
 	}
 }
 
+// ValidateTagCount rejects more than max tags on a memo (max <= 0 disables the check).
+// The error is a ValidationErrors entry for the Tags field, like the struct validations
+func (cv *CustomValidator) ValidateTagCount(tags []string, max int) error {
+	if max <= 0 || len(tags) <= max {
+		return nil
+	}
+	return ValidationErrors{Errors: []ValidationError{{
+		Field:   "Tags",
+		Tag:     "max_tags",
+		Message: fmt.Sprintf("Tags は %d 個以下で指定してください", max),
+		Value:   len(tags),
+	}}}
+}
+
 // ValidateID validates ID parameters for SQL injection
 func (cv *CustomValidator) ValidateID(idStr string) (int, error) {
 	// 数値以外の文字をチェック
@@ -311,18 +399,9 @@ func (cv *CustomValidator) validateUsernameFormat(fl validator.FieldLevel) bool
 		return false
 	}
 
-	// 予約語チェック
-	reservedWords := []string{
-		"admin", "root", "api", "www", "mail", "ftp", "localhost",
-		"test", "user", "guest", "demo", "support", "help", "info",
-		"null", "undefined", "system", "config", "settings",
-	}
-
-	lowerUsername := strings.ToLower(username)
-	for _, reserved := range reservedWords {
-		if lowerUsername == reserved {
-			return false
-		}
+	// 予約語チェック（デフォルト + 設定で追加された予約語）
+	if cv.IsReservedUsername(username) {
+		return false
 	}
 
 	return true
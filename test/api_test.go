@@ -10,7 +10,9 @@ import (
 	"os"
 	"testing"
 
+	"memo-app/src/buildinfo"
 	"memo-app/src/domain"
+	"memo-app/src/handlers"
 	"memo-app/src/interface/handler"
 	"memo-app/src/logger"
 	"memo-app/src/middleware"
@@ -36,6 +38,18 @@ func (m *MockJWTService) GenerateRefreshToken(userID int) (string, error) {
 	return "mock-refresh-token", nil
 }
 
+func (m *MockJWTService) GenerateAccessTokenWithVersion(userID, tokenVersion int) (string, error) {
+	return "mock-access-token", nil
+}
+
+func (m *MockJWTService) GenerateAccessTokenWithRole(userID, tokenVersion int, role string) (string, error) {
+	return "mock-access-token", nil
+}
+
+func (m *MockJWTService) GenerateRefreshTokenWithVersion(userID, tokenVersion int) (string, error) {
+	return "mock-refresh-token", nil
+}
+
 func (m *MockJWTService) ValidateToken(tokenString string) (*service.JWTClaims, error) {
 	if tokenString == "valid-token-123" {
 		return &service.JWTClaims{
@@ -63,6 +77,22 @@ func (m *MockJWTService) ValidateRefreshToken(tokenString string) (*service.JWTC
 	return nil, assert.AnError
 }
 
+func (m *MockJWTService) GenerateEmailVerificationToken(userID int, email string) (string, error) {
+	return "mock-verification-token", nil
+}
+
+func (m *MockJWTService) ValidateEmailVerificationToken(tokenString string) (*service.JWTClaims, error) {
+	return nil, assert.AnError
+}
+
+func (m *MockJWTService) InvalidateToken(tokenString string) error {
+	return nil
+}
+
+func (m *MockJWTService) IsTokenInvalidated(jti string) (bool, error) {
+	return false, nil
+}
+
 // MockUserRepository APIテスト用のモック
 type MockUserRepository struct{}
 
@@ -83,12 +113,16 @@ func (m *MockUserRepository) GetByGitHubID(githubID int64) (*models.User, error)
 func (m *MockUserRepository) GetByUsername(username string) (*models.User, error) { return nil, nil }
 func (m *MockUserRepository) Update(user *models.User) error                      { return nil }
 func (m *MockUserRepository) UpdateLastLogin(userID int) error                    { return nil }
+func (m *MockUserRepository) MarkEmailVerified(userID int) error                  { return nil }
+func (m *MockUserRepository) IncrementTokenVersion(userID int) (int, error)       { return 1, nil }
+func (m *MockUserRepository) Delete(userID int) error                             { return nil }
 func (m *MockUserRepository) GetIPRegistration(ipAddress string) (*models.IPRegistration, error) {
 	return nil, nil
 }
 func (m *MockUserRepository) CreateIPRegistration(ipReg *models.IPRegistration) error { return nil }
 func (m *MockUserRepository) UpdateIPRegistration(ipReg *models.IPRegistration) error { return nil }
 func (m *MockUserRepository) GetUserCountByIP(ipAddress string) (int, error)          { return 0, nil }
+func (m *MockUserRepository) ResetIPRegistration(ipAddress string) error              { return nil }
 func (m *MockUserRepository) IsEmailExists(email string) (bool, error)                { return false, nil }
 func (m *MockUserRepository) IsUsernameExists(username string) (bool, error)          { return false, nil }
 func (m *MockUserRepository) IsGitHubIDExists(githubID int64) (bool, error)           { return false, nil }
@@ -127,6 +161,74 @@ func (m *MockMemoUsecase) CreateMemo(ctx context.Context, req usecase.CreateMemo
 	return args.Get(0).(*domain.Memo), args.Error(1)
 }
 
+func (m *MockMemoUsecase) ImportMemosWithIDs(ctx context.Context, reqs []usecase.ImportMemoRequest) ([]domain.Memo, error) {
+	args := m.Called(ctx, reqs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ExportMemos(ctx context.Context, fn func(*domain.Memo) error) error {
+	args := m.Called(ctx, fn)
+	if memos, ok := args.Get(0).([]domain.Memo); ok {
+		for i := range memos {
+			if err := fn(&memos[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockMemoUsecase) ImportMemos(ctx context.Context, reqs []usecase.CreateMemoRequest, atomic bool) (*usecase.ImportResult, error) {
+	args := m.Called(ctx, reqs, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ImportResult), args.Error(1)
+}
+
+func (m *MockMemoUsecase) UpdateMemoMetadata(ctx context.Context, id int, req usecase.UpdateMemoMetadataRequest) (*domain.Memo, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) GetMemoStats(ctx context.Context) (*domain.MemoStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoStats), args.Error(1)
+}
+
+func (m *MockMemoUsecase) GetMemoListState(ctx context.Context) (*domain.MemoListState, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoListState), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ListMemoHistory(ctx context.Context, id int) ([]domain.MemoRevision, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MemoRevision), args.Error(1)
+}
+
+func (m *MockMemoUsecase) RevertMemo(ctx context.Context, id, revisionID int) (*domain.Memo, error) {
+	args := m.Called(ctx, id, revisionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
 func (m *MockMemoUsecase) GetMemo(ctx context.Context, id int) (*domain.Memo, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -153,6 +255,75 @@ func (m *MockMemoUsecase) DeleteMemo(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
+func (m *MockMemoUsecase) BulkDeleteMemos(ctx context.Context, ids []int) ([]usecase.BulkResult, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]usecase.BulkResult), args.Error(1)
+}
+
+func (m *MockMemoUsecase) BulkUpdateMemos(ctx context.Context, ids []int, req usecase.UpdateMemoRequest) (*usecase.BulkUpdateResult, error) {
+	args := m.Called(ctx, ids, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.BulkUpdateResult), args.Error(1)
+}
+
+func (m *MockMemoUsecase) TrashMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) PermanentDeleteMemo(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMemoUsecase) ListDeletedMemos(ctx context.Context, filter domain.MemoFilter) ([]domain.DeletedMemo, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]domain.DeletedMemo), args.Int(1), args.Error(2)
+}
+
+func (m *MockMemoUsecase) RestoreDeletedMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ListSharedMemos(ctx context.Context, filter domain.MemoFilter) ([]domain.SharedMemo, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]domain.SharedMemo), args.Int(1), args.Error(2)
+}
+
+func (m *MockMemoUsecase) ListCategories(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ListOnThisDay(ctx context.Context) ([]domain.Memo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Memo), args.Error(1)
+}
+
 func (m *MockMemoUsecase) ArchiveMemo(ctx context.Context, id int) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -168,6 +339,67 @@ func (m *MockMemoUsecase) SearchMemos(ctx context.Context, query string, filter
 	return args.Get(0).([]domain.Memo), args.Get(1).(int), args.Error(2)
 }
 
+func (m *MockMemoUsecase) PromoteMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) TouchMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) PinMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) UnpinMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) GetMemoDetail(ctx context.Context, id int, expand domain.MemoExpand) (*domain.MemoDetail, error) {
+	args := m.Called(ctx, id, expand)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoDetail), args.Error(1)
+}
+
+func (m *MockMemoUsecase) RecentSearchQueries(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ClearRecentSearchQueries(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockMemoUsecase) ListTags(ctx context.Context, filter domain.TagFilter) ([]domain.TagCount, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TagCount), args.Error(1)
+}
+
 func setupTestRouter(mockUsecase *MockMemoUsecase) *gin.Engine {
 	r := gin.New()
 
@@ -182,13 +414,7 @@ func setupTestRouter(mockUsecase *MockMemoUsecase) *gin.Engine {
 	// パブリックルート
 	public := r.Group("/")
 	{
-		public.GET("/", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{
-				"message": "Hello World",
-				"version": "2.0",
-				"service": "memo-app-api-server",
-			})
-		})
+		public.GET("/", handlers.NewRootHandler(buildinfo.Get()).Root)
 
 		public.GET("/health", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{
@@ -332,7 +558,8 @@ func TestRootEndpoint(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, "Hello World", response["message"])
-	assert.Equal(t, "2.0", response["version"])
+	assert.NotContains(t, response, "version")
+	assert.Contains(t, response, "build")
 	assert.Equal(t, "memo-app-api-server", response["service"])
 }
 
@@ -633,6 +860,7 @@ func TestMemoAPIWithMocks(t *testing.T) {
 			{ID: 1, Title: "Memo 1", Content: "Content 1", Status: domain.StatusActive},
 			{ID: 2, Title: "Memo 2", Content: "Content 2", Status: domain.StatusActive},
 		}, 2, nil)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(&domain.MemoListState{Count: 2}, nil)
 
 		// リクエストの実行
 		w := httptest.NewRecorder()
@@ -642,6 +870,7 @@ func TestMemoAPIWithMocks(t *testing.T) {
 
 		// レスポンスの検証
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, w.Header().Get("ETag"))
 
 		mockUsecase.AssertExpectations(t)
 	})
@@ -0,0 +1,32 @@
+package buildinfo_test
+
+import (
+	"runtime"
+	"testing"
+
+	"memo-app/src/buildinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	t.Run("埋め込まない場合はdev", func(t *testing.T) {
+		info := buildinfo.Get()
+
+		assert.Equal(t, "dev", info.Commit)
+		assert.Equal(t, "dev", info.BuildTime)
+		assert.Equal(t, runtime.Version(), info.GoVersion)
+	})
+
+	t.Run("埋め込んだ値を返す", func(t *testing.T) {
+		commit, buildTime := buildinfo.Commit, buildinfo.BuildTime
+		defer func() { buildinfo.Commit, buildinfo.BuildTime = commit, buildTime }()
+		buildinfo.Commit = "abc1234"
+		buildinfo.BuildTime = "2024-05-01T12:00:00Z"
+
+		info := buildinfo.Get()
+
+		assert.Equal(t, "abc1234", info.Commit)
+		assert.Equal(t, "2024-05-01T12:00:00Z", info.BuildTime)
+	})
+}
@@ -107,6 +107,233 @@ func TestLoadConfig(t *testing.T) {
 	})
 }
 
+func TestLoadConfig_ReservedUsernames(t *testing.T) {
+	defer os.Unsetenv("RESERVED_USERNAMES")
+
+	t.Run("未設定の場合は空", func(t *testing.T) {
+		os.Unsetenv("RESERVED_USERNAMES")
+		cfg := config.LoadConfig()
+		assert.Empty(t, cfg.Auth.ReservedUsernames)
+	})
+
+	t.Run("カンマ区切りで読み込み", func(t *testing.T) {
+		os.Setenv("RESERVED_USERNAMES", "acme, brand ,,offensive")
+		cfg := config.LoadConfig()
+		assert.Equal(t, []string{"acme", "brand", "offensive"}, cfg.Auth.ReservedUsernames)
+	})
+}
+
+func TestLoadConfig_ProtectedCategories(t *testing.T) {
+	defer os.Unsetenv("PROTECTED_CATEGORIES")
+
+	t.Run("未設定の場合は空", func(t *testing.T) {
+		os.Unsetenv("PROTECTED_CATEGORIES")
+		cfg := config.LoadConfig()
+		assert.Empty(t, cfg.Memo.ProtectedCategories)
+	})
+
+	t.Run("カンマ区切りで読み込み", func(t *testing.T) {
+		os.Setenv("PROTECTED_CATEGORIES", "inbox, backlog")
+		cfg := config.LoadConfig()
+		assert.Equal(t, []string{"inbox", "backlog"}, cfg.Memo.ProtectedCategories)
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	keys := []string{"LOG_MAX_SIZE", "LOG_MAX_BACKUPS", "LOG_MAX_AGE", "LOG_COMPRESS", "EMPTY_LIST_STATUS", "MAIL_DRIVER", "MEMO_CONTENT_MAX_LENGTH", "MEMO_CONTENT_SOFT_LIMIT", "TAGS_MAX_LIMIT", "MAX_TAGS_PER_MEMO", "DEFAULT_PAGE_SIZE", "MAX_PAGE_SIZE", "CLAMP_PAGE_SIZE", "LOG_VALIDATION_REJECTS", "MAX_SESSIONS_PER_USER", "MEMO_TRASH_RETENTION", "MEMO_TRASH_PURGE_INTERVAL", "METRICS_SIZE_ALERT_BYTES", "LOG_UPLOAD_CONCURRENCY", "LOG_UPLOAD_SHUTDOWN_CONCURRENCY", "LOG_UPLOAD_SHUTDOWN_TIMEOUT", "LOG_UPLOAD_RETRIES", "LOG_UPLOAD_RETRY_BACKOFF", "LOG_UPLOAD_ALERT_THRESHOLD", "LOG_UPLOAD_ALERT_WEBHOOK_URL", "VALIDATION_ERROR_STATUS", "RATE_LIMIT_RPS", "RATE_LIMIT_BURST", "CASE_INSENSITIVE_TAGS", "ALLOWED_ORIGINS", "CORS_ALLOW_CREDENTIALS", "GZIP_MIN_SIZE", "MAX_REQUEST_BYTES", "REQUEST_TIMEOUT", "SHUTDOWN_TIMEOUT", "DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME", "DB_READ_RETRIES", "DB_READ_RETRY_BACKOFF", "PASSWORD_RESET_EXPIRES_IN", "REVOKED_TOKEN_CLEANUP_INTERVAL", "LOGIN_MAX_ATTEMPTS", "LOGIN_MAX_ATTEMPTS_PER_IP", "LOGIN_ATTEMPT_WINDOW", "LOGIN_LOCKOUT_DURATION", "MEMO_REVEAL_OWNERSHIP", "IP_REGISTRATION_WINDOW", "REVERIFY_EMAIL_ON_CHANGE", "MEMO_DELETED_RETENTION", "SWAGGER_ENABLED", "PROBLEM_JSON_ERRORS", "METRICS_PORT", "REMINDER_POLL_INTERVAL", "REMINDER_NOTIFIER", "REMINDER_WEBHOOK_URL", "WEBHOOK_WORKERS", "WEBHOOK_QUEUE_SIZE", "WEBHOOK_MAX_ATTEMPTS", "WEBHOOK_RETRY_BACKOFF", "WEBHOOK_TIMEOUT", "ATTACHMENTS_ENABLED", "ATTACHMENT_S3_BUCKET", "ATTACHMENT_MAX_SIZE", "ATTACHMENT_ALLOWED_TYPES", "ATTACHMENT_URL_EXPIRY"}
+	unsetLogEnv := func() {
+		for _, key := range keys {
+			os.Unsetenv(key)
+		}
+	}
+	defer unsetLogEnv()
+
+	t.Run("有効な値", func(t *testing.T) {
+		unsetLogEnv()
+		os.Setenv("LOG_MAX_SIZE", "1")
+		os.Setenv("LOG_MAX_BACKUPS", "3")
+		os.Setenv("LOG_MAX_AGE", "1")
+		os.Setenv("LOG_COMPRESS", "false")
+		os.Setenv("METRICS_PORT", "9100")
+		os.Setenv("REMINDER_NOTIFIER", "webhook")
+		os.Setenv("REMINDER_WEBHOOK_URL", "https://hooks.example.com/reminders")
+
+		cfg := config.LoadConfig()
+		assert.NoError(t, cfg.Validate())
+		assert.Equal(t, "9100", cfg.Server.MetricsPort)
+		assert.Equal(t, "webhook", cfg.Reminder.Notifier)
+		assert.Equal(t, 1, cfg.Log.MaxSize)
+		assert.Equal(t, 3, cfg.Log.MaxBackups)
+		assert.Equal(t, 1, cfg.Log.MaxAge)
+		assert.False(t, cfg.Log.Compress)
+	})
+
+	t.Run("未設定時はデフォルト値", func(t *testing.T) {
+		unsetLogEnv()
+		cfg := config.LoadConfig()
+		assert.NoError(t, cfg.Validate())
+		assert.Equal(t, 100, cfg.Log.MaxSize)
+		assert.Equal(t, 3, cfg.Log.MaxBackups)
+		assert.Equal(t, 28, cfg.Log.MaxAge)
+		assert.Equal(t, 0, cfg.Auth.MaxSessionsPerUser)
+		assert.Equal(t, 10.0, cfg.Server.RateLimitRPS)
+		assert.Equal(t, 20, cfg.Server.RateLimitBurst)
+		assert.Equal(t, 25, cfg.Database.MaxOpenConns)
+		assert.Equal(t, 25, cfg.Database.MaxIdleConns)
+		assert.Equal(t, 5*time.Minute, cfg.Database.ConnMaxLifetime)
+	})
+
+	t.Run("DBのアイドル接続数が最大接続数を超える場合はエラー", func(t *testing.T) {
+		unsetLogEnv()
+		os.Setenv("DB_MAX_OPEN_CONNS", "10")
+		os.Setenv("DB_MAX_IDLE_CONNS", "20")
+
+		cfg := config.LoadConfig()
+		err := cfg.Validate()
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "DB_MAX_IDLE_CONNS")
+		}
+	})
+
+	t.Run("デフォルトのページサイズが最大値を超える場合はエラー", func(t *testing.T) {
+		unsetLogEnv()
+		os.Setenv("DEFAULT_PAGE_SIZE", "50")
+		os.Setenv("MAX_PAGE_SIZE", "20")
+
+		cfg := config.LoadConfig()
+		err := cfg.Validate()
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "DEFAULT_PAGE_SIZE")
+		}
+	})
+
+	invalid := []struct {
+		key   string
+		value string
+	}{
+		{"LOG_MAX_SIZE", "abc"},
+		{"LOG_MAX_SIZE", "0"},
+		{"LOG_MAX_BACKUPS", "-1"},
+		{"LOG_MAX_AGE", "1.5"},
+		{"LOG_COMPRESS", "maybe"},
+		{"EMPTY_LIST_STATUS", "201"},
+		{"EMPTY_LIST_STATUS", "none"},
+		{"MAIL_DRIVER", "sendgrid"},
+		{"MEMO_CONTENT_MAX_LENGTH", "0"},
+		{"MEMO_CONTENT_SOFT_LIMIT", "abc"},
+		{"MEMO_CONTENT_SOFT_LIMIT", "10000"},
+		{"TAGS_MAX_LIMIT", "-5"},
+		{"MAX_TAGS_PER_MEMO", "0"},
+		{"LOG_VALIDATION_REJECTS", "sometimes"},
+		{"MAX_SESSIONS_PER_USER", "-1"},
+		{"MEMO_TRASH_RETENTION", "30days"},
+		{"MEMO_TRASH_RETENTION", "0s"},
+		{"MEMO_TRASH_PURGE_INTERVAL", "-1h"},
+		{"MEMO_DELETED_RETENTION", "0s"},
+		{"METRICS_SIZE_ALERT_BYTES", "-1"},
+		{"METRICS_SIZE_ALERT_BYTES", "1MB"},
+		{"LOG_UPLOAD_CONCURRENCY", "0"},
+		{"LOG_UPLOAD_SHUTDOWN_CONCURRENCY", "two"},
+		{"LOG_UPLOAD_SHUTDOWN_TIMEOUT", "0s"},
+		{"LOG_UPLOAD_RETRIES", "-1"},
+		{"LOG_UPLOAD_RETRY_BACKOFF", "0s"},
+		{"LOG_UPLOAD_ALERT_THRESHOLD", "0"},
+		{"LOG_UPLOAD_ALERT_WEBHOOK_URL", "hooks.example.com/alerts"},
+		{"VALIDATION_ERROR_STATUS", "409"},
+		{"VALIDATION_ERROR_STATUS", "unprocessable"},
+		{"RATE_LIMIT_RPS", "0"},
+		{"RATE_LIMIT_RPS", "fast"},
+		{"RATE_LIMIT_BURST", "0"},
+		{"CASE_INSENSITIVE_TAGS", "sometimes"},
+		{"ALLOWED_ORIGINS", "localhost:3000"},
+		{"CORS_ALLOW_CREDENTIALS", "maybe"},
+		{"SWAGGER_ENABLED", "maybe"},
+		{"METRICS_PORT", "metrics"},
+		{"METRICS_PORT", "70000"},
+		{"METRICS_PORT", "8000"},
+		{"CORS_ALLOW_CREDENTIALS", "true"},
+		{"GZIP_MIN_SIZE", "-1"},
+		{"GZIP_MIN_SIZE", "1KB"},
+		{"MAX_REQUEST_BYTES", "0"},
+		{"REQUEST_TIMEOUT", "0s"},
+		{"REQUEST_TIMEOUT", "soon"},
+		{"SHUTDOWN_TIMEOUT", "0s"},
+		{"SHUTDOWN_TIMEOUT", "-5s"},
+		{"DEFAULT_PAGE_SIZE", "0"},
+		{"MAX_PAGE_SIZE", "-10"},
+		{"CLAMP_PAGE_SIZE", "maybe"},
+		{"DB_MAX_OPEN_CONNS", "0"},
+		{"DB_MAX_IDLE_CONNS", "many"},
+		{"DB_CONN_MAX_LIFETIME", "0s"},
+		{"DB_READ_RETRIES", "-1"},
+		{"DB_READ_RETRY_BACKOFF", "0s"},
+		{"PASSWORD_RESET_EXPIRES_IN", "-1h"},
+		{"REVOKED_TOKEN_CLEANUP_INTERVAL", "0"},
+		{"LOGIN_MAX_ATTEMPTS", "-1"},
+		{"REVERIFY_EMAIL_ON_CHANGE", "sometimes"},
+		{"IP_REGISTRATION_WINDOW", "-24h"},
+		{"PROBLEM_JSON_ERRORS", "maybe"},
+		{"LOGIN_MAX_ATTEMPTS_PER_IP", "abc"},
+		{"LOGIN_ATTEMPT_WINDOW", "0"},
+		{"LOGIN_LOCKOUT_DURATION", "-1m"},
+		{"MEMO_REVEAL_OWNERSHIP", "maybe"},
+		{"REMINDER_POLL_INTERVAL", "0s"},
+		{"REMINDER_NOTIFIER", "sms"},
+		{"REMINDER_NOTIFIER", "webhook"},
+		{"WEBHOOK_WORKERS", "0"},
+		{"WEBHOOK_QUEUE_SIZE", "many"},
+		{"WEBHOOK_MAX_ATTEMPTS", "-1"},
+		{"WEBHOOK_RETRY_BACKOFF", "0s"},
+		{"WEBHOOK_TIMEOUT", "soon"},
+		{"ATTACHMENTS_ENABLED", "maybe"},
+		{"ATTACHMENT_MAX_SIZE", "0"},
+		{"ATTACHMENT_MAX_SIZE", "10MB"},
+		{"ATTACHMENT_URL_EXPIRY", "0s"},
+		{"ATTACHMENT_URL_EXPIRY", "200h"},
+	}
+	for _, tt := range invalid {
+		t.Run("不正な値 "+tt.key+"="+tt.value, func(t *testing.T) {
+			unsetLogEnv()
+			os.Setenv(tt.key, tt.value)
+
+			err := config.LoadConfig().Validate()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.key)
+		})
+	}
+}
+
+func TestAllowNonDocker(t *testing.T) {
+	defer func() {
+		os.Unsetenv("ALLOW_NON_DOCKER")
+		os.Unsetenv("ENV")
+	}()
+
+	tests := []struct {
+		name     string
+		flag     string
+		env      string
+		expected bool
+	}{
+		{name: "フラグ未設定", flag: "", env: "", expected: false},
+		{name: "フラグfalse", flag: "false", env: "development", expected: false},
+		{name: "開発環境でフラグtrue", flag: "true", env: "development", expected: true},
+		{name: "ENV未設定でフラグtrue", flag: "true", env: "", expected: true},
+		{name: "本番環境ではフラグtrueでも拒否", flag: "true", env: "production", expected: false},
+		{name: "本番環境（大文字）ではフラグtrueでも拒否", flag: "true", env: "PRODUCTION", expected: false},
+		{name: "不正なフラグ値", flag: "yes-please", env: "development", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("ALLOW_NON_DOCKER", tt.flag)
+			os.Setenv("ENV", tt.env)
+
+			assert.Equal(t, tt.expected, config.AllowNonDocker())
+		})
+	}
+}
+
 func TestConfigStructure(t *testing.T) {
 	cfg := config.LoadConfig()
 
@@ -0,0 +1,95 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"memo-app/src/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectDocker(t *testing.T) {
+	noEnv := func(string) string { return "" }
+
+	// 一時ディレクトリにcgroup/.dockerenvを用意したDockerProbeを作成
+	newProbe := func(t *testing.T, getenv func(string) string, cgroup string, dockerEnv bool) config.DockerProbe {
+		dir := t.TempDir()
+		probe := config.DockerProbe{
+			Getenv:        getenv,
+			CgroupPath:    filepath.Join(dir, "cgroup"),
+			DockerEnvPath: filepath.Join(dir, ".dockerenv"),
+		}
+		if cgroup != "" {
+			require.NoError(t, os.WriteFile(probe.CgroupPath, []byte(cgroup), 0o644))
+		}
+		if dockerEnv {
+			require.NoError(t, os.WriteFile(probe.DockerEnvPath, nil, 0o644))
+		}
+		return probe
+	}
+
+	tests := []struct {
+		name           string
+		getenv         func(string) string
+		cgroup         string
+		dockerEnv      bool
+		expectedDocker bool
+		expectedReason string
+	}{
+		{
+			name:           "環境変数DOCKER_CONTAINER=true",
+			getenv:         func(key string) string { return map[string]string{"DOCKER_CONTAINER": "true"}[key] },
+			expectedDocker: true,
+			expectedReason: config.DockerReasonEnv,
+		},
+		{
+			name:           "cgroupにdockerを含む",
+			getenv:         noEnv,
+			cgroup:         "12:cpuset:/\n0::/docker/0123456789abcdef\n",
+			expectedDocker: true,
+			expectedReason: config.DockerReasonCgroup,
+		},
+		{
+			name:           "cgroupにcontainerdを含む",
+			getenv:         noEnv,
+			cgroup:         "0::/system.slice/containerd.service\n",
+			expectedDocker: true,
+			expectedReason: config.DockerReasonCgroup,
+		},
+		{
+			name:           ".dockerenvが存在する",
+			getenv:         noEnv,
+			cgroup:         "0::/user.slice\n",
+			dockerEnv:      true,
+			expectedDocker: true,
+			expectedReason: config.DockerReasonDockerEnv,
+		},
+		{
+			name:           "Dockerの痕跡なし",
+			getenv:         noEnv,
+			cgroup:         "0::/user.slice/user-1000.slice\n",
+			expectedDocker: false,
+			expectedReason: "",
+		},
+		{
+			name:           "cgroupファイルもなし",
+			getenv:         func(key string) string { return map[string]string{"DOCKER_CONTAINER": "false"}[key] },
+			expectedDocker: false,
+			expectedReason: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := newProbe(t, tt.getenv, tt.cgroup, tt.dockerEnv)
+
+			inDocker, reason := config.DetectDocker(probe)
+
+			assert.Equal(t, tt.expectedDocker, inDocker)
+			assert.Equal(t, tt.expectedReason, reason)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+
+	"github.com/lib/pq"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"シリアライズの失敗", &pq.Error{Code: "40001"}, true},
+		{"デッドロック", &pq.Error{Code: "40P01"}, true},
+		{"接続の失敗", &pq.Error{Code: "08006"}, true},
+		{"管理者によるシャットダウン", &pq.Error{Code: "57P01"}, true},
+		{"ラップされた接続の失敗", fmt.Errorf("failed to get memos: %w", &pq.Error{Code: "08003"}), true},
+		{"切断された接続", driver.ErrBadConn, true},
+		{"予期しないEOF", io.ErrUnexpectedEOF, true},
+		{"接続のリセット", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"構文エラー", &pq.Error{Code: "42601"}, false},
+		{"一意制約違反", &pq.Error{Code: "23505"}, false},
+		{"キャンセル", context.Canceled, false},
+		{"タイムアウト", context.DeadlineExceeded, false},
+		{"その他のエラー", errors.New("memo not found"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, database.IsTransient(tt.err))
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	logger, hook := logtest.NewNullLogger()
+	cfg := database.RetryConfig{MaxRetries: 2, Backoff: time.Millisecond}
+	transient := &pq.Error{Code: "40001"}
+
+	t.Run("一時的なエラーの後に成功すれば成功を返す", func(t *testing.T) {
+		hook.Reset()
+		calls := 0
+		err := database.Retry(context.Background(), cfg, logger, func() error {
+			calls++
+			if calls < 3 {
+				return transient
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Len(t, hook.AllEntries(), 2)
+	})
+
+	t.Run("再試行回数を使い切ると最後のエラーを返す", func(t *testing.T) {
+		calls := 0
+		err := database.Retry(context.Background(), cfg, logger, func() error {
+			calls++
+			return transient
+		})
+		assert.Equal(t, transient, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("一時的でないエラーは再試行しない", func(t *testing.T) {
+		calls := 0
+		syntaxErr := &pq.Error{Code: "42601"}
+		err := database.Retry(context.Background(), cfg, logger, func() error {
+			calls++
+			return syntaxErr
+		})
+		assert.Equal(t, syntaxErr, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("既定の設定では再試行しない", func(t *testing.T) {
+		calls := 0
+		err := database.Retry(context.Background(), database.RetryConfig{}, logger, func() error {
+			calls++
+			return transient
+		})
+		assert.Equal(t, transient, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("コンテキストが終了すると待たずにエラーを返す", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		start := time.Now()
+		err := database.Retry(ctx, database.RetryConfig{MaxRetries: 5, Backoff: time.Minute}, logger, func() error {
+			calls++
+			cancel()
+			return transient
+		})
+		assert.Equal(t, transient, err)
+		assert.Equal(t, 1, calls)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
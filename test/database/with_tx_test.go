@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"memo-app/src/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_WithTx(t *testing.T) {
+	newDB := func(t *testing.T) (*database.DB, sqlmock.Sqlmock) {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+		return &database.DB{DB: sqlDB}, mock
+	}
+
+	t.Run("成功した場合はコミットする", func(t *testing.T) {
+		db, mock := newDB(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE memos`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM memos`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := db.WithTx(context.Background(), func(tx *sql.Tx) error {
+			if _, err := tx.Exec(`UPDATE memos SET status = 'archived'`); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`DELETE FROM memos WHERE id = 1`)
+			return err
+		})
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("エラーの場合はロールバックしてエラーをそのまま返す", func(t *testing.T) {
+		db, mock := newDB(t)
+		fnErr := errors.New("memo not found")
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		err := db.WithTx(context.Background(), func(tx *sql.Tx) error { return fnErr })
+		assert.Equal(t, fnErr, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("パニックした場合はロールバックして再度パニックする", func(t *testing.T) {
+		db, mock := newDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.PanicsWithValue(t, "boom", func() {
+			db.WithTx(context.Background(), func(tx *sql.Tx) error { panic("boom") })
+		})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("開始とコミットの失敗を返す", func(t *testing.T) {
+		db, mock := newDB(t)
+		mock.ExpectBegin().WillReturnError(errors.New("too many connections"))
+		err := db.WithTx(context.Background(), func(tx *sql.Tx) error { return nil })
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to begin transaction")
+
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(errors.New("serialization failure"))
+		err = db.WithTx(context.Background(), func(tx *sql.Tx) error { return nil })
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to commit transaction")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
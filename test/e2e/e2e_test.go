@@ -13,7 +13,14 @@ import (
 	"testing"
 	"time"
 
+	"memo-app/src/storage"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -286,9 +293,102 @@ func (suite *E2ETestSuite) TestLoggingIntegration(t *testing.T) {
 
 // S3統合テスト
 func (suite *E2ETestSuite) TestS3Integration(t *testing.T) {
-	// MinIOコンテナに対する統合テスト
-	// TODO: MinIOクライアントを使用してバケットとオブジェクトの操作をテスト
-	t.Skip("S3/MinIO統合テストは別途実装が必要")
+	// Docker環境内でテストを実行している場合のチェック
+	inDocker := os.Getenv("DOCKER_CONTAINER") == "true"
+
+	endpoint := os.Getenv("S3_ENDPOINT")
+	if endpoint == "" {
+		if inDocker {
+			endpoint = "http://minio:9000" // Docker環境内ではminioコンテナ名を使用
+		} else {
+			endpoint = "http://localhost:9000" // ローカル環境
+		}
+	}
+	s3Config := &storage.S3Config{
+		Endpoint:        endpoint,
+		AccessKeyID:     getEnvOrDefault("S3_ACCESS_KEY_ID", "minioadmin"),
+		SecretAccessKey: getEnvOrDefault("S3_SECRET_ACCESS_KEY", "minioadmin"),
+		Region:          getEnvOrDefault("S3_REGION", "us-east-1"),
+		Bucket:          getEnvOrDefault("S3_BUCKET", "memo-app-logs"),
+		UseSSL:          os.Getenv("S3_USE_SSL") == "true",
+	}
+
+	// バケットの作成と一覧の確認に使うクライアント
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:         aws.String(s3Config.Endpoint),
+		Region:           aws.String(s3Config.Region),
+		Credentials:      credentials.NewStaticCredentials(s3Config.AccessKeyID, s3Config.SecretAccessKey, ""),
+		DisableSSL:       aws.Bool(!s3Config.UseSSL),
+		S3ForcePathStyle: aws.Bool(true),
+	})
+	require.NoError(t, err)
+	admin := s3.New(sess)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// MinIOが起動していない場合はスキップ
+	if _, err := admin.ListBucketsWithContext(ctx, &s3.ListBucketsInput{}); err != nil {
+		t.Skipf("MinIOが起動していないため、S3統合テストをスキップします: %v", err)
+	}
+
+	// バケットがなければ作成
+	if _, err := admin.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(s3Config.Bucket)}); err != nil {
+		_, err = admin.CreateBucketWithContext(ctx, &s3.CreateBucketInput{Bucket: aws.String(s3Config.Bucket)})
+		require.NoError(t, err, "バケットの作成に失敗")
+	}
+
+	// アップロード対象になるよう更新日時を古くしたテスト用のログファイルを作成
+	logDir := t.TempDir()
+	fileName := fmt.Sprintf("e2e_%d.log", time.Now().UnixNano())
+	filePath := filepath.Join(logDir, fileName)
+	content := []byte("level=info msg=\"E2Eテスト用のログ\"\n")
+	require.NoError(t, os.WriteFile(filePath, content, 0644))
+	oldTime := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(filePath, oldTime, oldTime))
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	uploader, err := storage.NewLogUploader(s3Config, logger)
+	require.NoError(t, err)
+	require.NoError(t, uploader.UploadOldLogs(logDir, time.Hour))
+
+	objectKey := "logs/" + fileName
+	defer admin.DeleteObject(&s3.DeleteObjectInput{Bucket: aws.String(s3Config.Bucket), Key: aws.String(objectKey)})
+
+	// アップロードしたローカルファイルは削除される
+	_, err = os.Stat(filePath)
+	assert.True(t, os.IsNotExist(err), "アップロード後もローカルファイルが残っています")
+
+	// オブジェクトの一覧にアップロードしたログが含まれる
+	list, err := admin.ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s3Config.Bucket),
+		Prefix: aws.String(objectKey),
+	})
+	require.NoError(t, err)
+	var keys []string
+	for _, object := range list.Contents {
+		keys = append(keys, aws.StringValue(object.Key))
+	}
+	assert.Contains(t, keys, objectKey)
+
+	// ダウンロードした内容が元のファイルと一致する
+	object, err := admin.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s3Config.Bucket),
+		Key:    aws.String(objectKey),
+	})
+	require.NoError(t, err)
+	defer object.Body.Close()
+	downloaded, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
 }
 
 // クリーンアップ
@@ -0,0 +1,174 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"memo-app/src/domain"
+	"memo-app/src/interface/handler"
+	"memo-app/src/usecase"
+
+	"github.com/gin-gonic/gin"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAttachmentUsecase は受け取ったアップロードを記録し、設定されたエラーを返す
+type stubAttachmentUsecase struct {
+	err      error
+	uploaded usecase.UploadAttachmentRequest
+	content  []byte
+}
+
+func (u *stubAttachmentUsecase) UploadAttachment(ctx context.Context, memoID int, req usecase.UploadAttachmentRequest) (*domain.MemoAttachment, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+	u.uploaded = req
+	u.content, _ = io.ReadAll(req.File)
+	return &domain.MemoAttachment{ID: 1, MemoID: memoID, Filename: req.Filename, ContentType: "image/png", Size: req.Size}, nil
+}
+
+func (u *stubAttachmentUsecase) ListAttachments(ctx context.Context, memoID int) ([]domain.MemoAttachment, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+	return []domain.MemoAttachment{{ID: 1, MemoID: memoID, Filename: "photo.png", ContentType: "image/png", Size: 3}}, nil
+}
+
+func (u *stubAttachmentUsecase) GetAttachmentDownload(ctx context.Context, memoID, id int) (*domain.MemoAttachment, *usecase.AttachmentDownload, error) {
+	if u.err != nil {
+		return nil, nil, u.err
+	}
+	return &domain.MemoAttachment{ID: id, MemoID: memoID, Filename: "photo.png", ContentType: "image/png", Size: 3},
+		&usecase.AttachmentDownload{URL: "https://s3.example.com/signed", ExpiresAt: time.Now().Add(15 * time.Minute)}, nil
+}
+
+func setupAttachmentRouter(uc usecase.AttachmentUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger, _ := logtest.NewNullLogger()
+	h := handler.NewAttachmentHandler(uc, logger, 10<<20)
+
+	r := gin.New()
+	r.POST("/api/memos/:id/attachments", h.UploadAttachment)
+	r.GET("/api/memos/:id/attachments", h.ListAttachments)
+	r.GET("/api/memos/:id/attachments/:attachmentID/download", h.DownloadAttachment)
+	return r
+}
+
+func multipartFile(t *testing.T, field, filename string, content []byte) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestAttachmentHandler_UploadAttachment(t *testing.T) {
+	t.Run("multipartのfileを添付する", func(t *testing.T) {
+		uc := &stubAttachmentUsecase{}
+		body, contentType := multipartFile(t, "file", "photo.png", []byte("png"))
+		req, _ := http.NewRequest("POST", "/api/memos/7/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		setupAttachmentRouter(uc).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var resp handler.MemoAttachmentResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "photo.png", resp.Filename)
+		assert.Equal(t, "image/png", resp.ContentType)
+		assert.Equal(t, int64(3), uc.uploaded.Size)
+		assert.Equal(t, []byte("png"), uc.content)
+		// オブジェクトキーはレスポンスに含めない
+		assert.NotContains(t, w.Body.String(), "object_key")
+	})
+
+	t.Run("fileがない場合は400", func(t *testing.T) {
+		body, contentType := multipartFile(t, "other", "photo.png", []byte("png"))
+		req, _ := http.NewRequest("POST", "/api/memos/7/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		setupAttachmentRouter(&stubAttachmentUsecase{}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	errorCases := []struct {
+		err    error
+		status int
+	}{
+		{usecase.ErrAttachmentUnauthorized, http.StatusUnauthorized},
+		{usecase.ErrMemoNotFound, http.StatusNotFound},
+		{usecase.ErrInvalidAttachment, http.StatusBadRequest},
+		{usecase.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge},
+		{usecase.ErrAttachmentTypeNotAllowed, http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			body, contentType := multipartFile(t, "file", "photo.png", []byte("png"))
+			req, _ := http.NewRequest("POST", "/api/memos/7/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			setupAttachmentRouter(&stubAttachmentUsecase{err: tt.err}).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestAttachmentHandler_ListAttachments(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/memos/7/attachments", nil)
+	w := httptest.NewRecorder()
+	setupAttachmentRouter(&stubAttachmentUsecase{}).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp handler.AttachmentListResponseDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Attachments, 1)
+	assert.Equal(t, "photo.png", resp.Attachments[0].Filename)
+}
+
+func TestAttachmentHandler_DownloadAttachment(t *testing.T) {
+	t.Run("署名付きURLを返す", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/memos/7/attachments/1/download", nil)
+		w := httptest.NewRecorder()
+		setupAttachmentRouter(&stubAttachmentUsecase{}).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		var resp handler.AttachmentDownloadResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "https://s3.example.com/signed", resp.URL)
+		assert.Equal(t, "photo.png", resp.Filename)
+		assert.False(t, resp.ExpiresAt.IsZero())
+	})
+
+	t.Run("見つからない場合は404", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/memos/7/attachments/1/download", nil)
+		w := httptest.NewRecorder()
+		setupAttachmentRouter(&stubAttachmentUsecase{err: usecase.ErrAttachmentNotFound}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("不正なIDは400", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/memos/7/attachments/abc/download", nil)
+		w := httptest.NewRecorder()
+		setupAttachmentRouter(&stubAttachmentUsecase{}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
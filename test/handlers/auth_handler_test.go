@@ -3,12 +3,15 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"memo-app/src/handlers"
 	"memo-app/src/models"
+	"memo-app/src/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -70,6 +73,52 @@ func (m *MockAuthService) CheckIPLimit(clientIP string) error {
 	return args.Error(0)
 }
 
+func (m *MockAuthService) VerifyEmail(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) RequestPasswordReset(email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResetPassword(token, newPassword string) error {
+	args := m.Called(token, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ChangePassword(userID int, currentPassword, newPassword string) error {
+	args := m.Called(userID, currentPassword, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) LogoutAll(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) UpdateProfile(userID int, req *models.UpdateProfileRequest) (*models.User, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) DeleteAccount(userID int, password string) error {
+	args := m.Called(userID, password)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) GetProfile(userID int) (*models.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
 func TestAuthHandler_Register(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -220,6 +269,19 @@ func TestAuthHandler_Login(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "Login failed",
 		},
+		{
+			name: "ログイン試行回数の上限",
+			requestBody: map[string]string{
+				"email":    "test@example.com",
+				"password": "wrongpassword",
+			},
+			setupMock: func(m *MockAuthService) {
+				m.On("Login", mock.AnythingOfType("*models.LoginRequest"), mock.AnythingOfType("string")).
+					Return(nil, &service.LoginThrottledError{RetryAfter: 90 * time.Second})
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   `"retry_after":90`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -368,3 +430,648 @@ func TestAuthHandler_RefreshToken(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_VerifyEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		token          string
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "正常な確認",
+			token: "valid-token",
+			setupMock: func(m *MockAuthService) {
+				m.On("VerifyEmail", "valid-token").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Email verified successfully",
+		},
+		{
+			name:           "トークンなし",
+			token:          "",
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Verification token is required",
+		},
+		{
+			name:  "期限切れのトークン",
+			token: "expired-token",
+			setupMock: func(m *MockAuthService) {
+				m.On("VerifyEmail", "expired-token").Return(fmt.Errorf("verification token expired"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Verification token expired",
+		},
+		{
+			name:  "使用済みのトークン",
+			token: "used-token",
+			setupMock: func(m *MockAuthService) {
+				m.On("VerifyEmail", "used-token").Return(fmt.Errorf("verification token already used"))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "Verification token already used",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+
+			handler := handlers.NewAuthHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/verify?token="+tt.token, nil)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.VerifyEmail(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_ForgotPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "登録済みのメールアドレス",
+			body: `{"email":"user@example.com"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("RequestPasswordReset", "user@example.com").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "If the email address is registered",
+		},
+		{
+			// サービスは未登録の場合もエラーを返さないため、レスポンスは登録済みの場合と同じになる
+			name: "未登録のメールアドレス",
+			body: `{"email":"unknown@example.com"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("RequestPasswordReset", "unknown@example.com").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "If the email address is registered",
+		},
+		{
+			name:           "不正なメールアドレス",
+			body:           `{"email":"not-an-email"}`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+
+			handler := handlers.NewAuthHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/password/forgot", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.ForgotPassword(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_ResetPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "正常な再設定",
+			body: `{"token":"valid-token","new_password":"Sunny-Harbor7"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ResetPassword", "valid-token", "Sunny-Harbor7").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Password has been reset successfully",
+		},
+		{
+			name:           "トークンなし",
+			body:           `{"new_password":"Sunny-Harbor7"}`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request format",
+		},
+		{
+			name: "弱いパスワード",
+			body: `{"token":"valid-token","new_password":"password"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ResetPassword", "valid-token", "password").Return(fmt.Errorf("weak password: validation failed"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Password does not meet the strength requirements",
+		},
+		{
+			name: "期限切れのトークン",
+			body: `{"token":"expired-token","new_password":"Sunny-Harbor7"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ResetPassword", "expired-token", "Sunny-Harbor7").Return(fmt.Errorf("password reset token expired"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Password reset token expired",
+		},
+		{
+			name: "使用済みのトークン",
+			body: `{"token":"used-token","new_password":"Sunny-Harbor7"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ResetPassword", "used-token", "Sunny-Harbor7").Return(fmt.Errorf("password reset token already used"))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "Password reset token already used",
+		},
+		{
+			name: "不正なトークン",
+			body: `{"token":"unknown-token","new_password":"Sunny-Harbor7"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ResetPassword", "unknown-token", "Sunny-Harbor7").Return(fmt.Errorf("invalid password reset token"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid password reset token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+
+			handler := handlers.NewAuthHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/password/reset", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.ResetPassword(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		userID         interface{}
+		body           string
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "正常な変更",
+			userID: 1,
+			body:   `{"current_password":"Quiet-River9","new_password":"Sunny-Harbor7"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ChangePassword", 1, "Quiet-River9", "Sunny-Harbor7").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Password changed successfully",
+		},
+		{
+			name:           "未認証",
+			body:           `{"current_password":"Quiet-River9","new_password":"Sunny-Harbor7"}`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "User not authenticated",
+		},
+		{
+			name:           "現在のパスワードなし",
+			userID:         1,
+			body:           `{"new_password":"Sunny-Harbor7"}`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request format",
+		},
+		{
+			name:   "GitHub認証のみのアカウント",
+			userID: 2,
+			body:   `{"current_password":"anything","new_password":"Sunny-Harbor7"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ChangePassword", 2, "anything", "Sunny-Harbor7").Return(fmt.Errorf("this account uses external authentication"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "sign in with GitHub",
+		},
+		{
+			name:   "現在のパスワードが違う",
+			userID: 1,
+			body:   `{"current_password":"Wrong-Pass1","new_password":"Sunny-Harbor7"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ChangePassword", 1, "Wrong-Pass1", "Sunny-Harbor7").Return(fmt.Errorf("invalid current password"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Current password is incorrect",
+		},
+		{
+			name:   "弱いパスワード",
+			userID: 1,
+			body:   `{"current_password":"Quiet-River9","new_password":"password"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ChangePassword", 1, "Quiet-River9", "password").Return(fmt.Errorf("weak password: validation failed"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Password does not meet the strength requirements",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+
+			handler := handlers.NewAuthHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/password/change", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			if tt.userID != nil {
+				c.Set("user_id", tt.userID)
+			}
+
+			handler.ChangePassword(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_LogoutAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		userID         interface{}
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "正常なログアウト",
+			userID: 1,
+			setupMock: func(m *MockAuthService) {
+				m.On("LogoutAll", 1).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Logged out from all sessions",
+		},
+		{
+			name:           "未認証",
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "User not authenticated",
+		},
+		{
+			name:   "失効に失敗",
+			userID: 1,
+			setupMock: func(m *MockAuthService) {
+				m.On("LogoutAll", 1).Return(fmt.Errorf("failed to revoke tokens: db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Logout failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+
+			handler := handlers.NewAuthHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/logout-all", nil)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			if tt.userID != nil {
+				c.Set("user_id", tt.userID)
+			}
+
+			handler.LogoutAll(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_GetProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	passwordHash := "hashed"
+	githubID := int64(12345)
+
+	tests := []struct {
+		name             string
+		userID           interface{}
+		setupMock        func(*MockAuthService)
+		expectedStatus   int
+		expectedBody     string
+		expectedProvider models.AuthProvider
+	}{
+		{
+			name:   "ローカル認証のユーザー",
+			userID: 1,
+			setupMock: func(m *MockAuthService) {
+				m.On("GetProfile", 1).Return(&models.User{ID: 1, Username: "testuser", Email: "test@example.com", PasswordHash: &passwordHash, IsActive: true}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedBody:     "testuser",
+			expectedProvider: models.AuthProviderLocal,
+		},
+		{
+			name:   "GitHub認証のユーザー",
+			userID: 2,
+			setupMock: func(m *MockAuthService) {
+				m.On("GetProfile", 2).Return(&models.User{ID: 2, Username: "octocat", Email: "octocat@example.com", GitHubID: &githubID, IsActive: true}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedBody:     "octocat",
+			expectedProvider: models.AuthProviderGitHub,
+		},
+		{
+			name:           "未認証",
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "User not authenticated",
+		},
+		{
+			name:   "ユーザーが存在しない",
+			userID: 3,
+			setupMock: func(m *MockAuthService) {
+				m.On("GetProfile", 3).Return(nil, fmt.Errorf("user not found: sql: no rows in result set"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "User not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+
+			handler := handlers.NewAuthHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/me", nil)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			if tt.userID != nil {
+				c.Set("user_id", tt.userID)
+			}
+
+			handler.GetProfile(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			if tt.expectedProvider != "" {
+				var resp struct {
+					Data models.PublicUser `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.expectedProvider, resp.Data.AuthProvider)
+				assert.NotContains(t, w.Body.String(), "password")
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_UpdateProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		userID         interface{}
+		requestBody    string
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "正常な更新",
+			userID:      1,
+			requestBody: `{"username": "newname"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("UpdateProfile", 1, mock.AnythingOfType("*models.UpdateProfileRequest")).
+					Return(&models.User{ID: 1, Username: "newname", Email: "test@example.com", IsActive: true}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "newname",
+		},
+		{
+			name:           "未認証",
+			requestBody:    `{"username": "newname"}`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "User not authenticated",
+		},
+		{
+			name:        "ユーザー名の重複",
+			userID:      1,
+			requestBody: `{"username": "taken"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("UpdateProfile", 1, mock.AnythingOfType("*models.UpdateProfileRequest")).Return(nil, fmt.Errorf("username already exists"))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `"field":"username"`,
+		},
+		{
+			name:        "メールアドレスの重複",
+			userID:      1,
+			requestBody: `{"email": "taken@example.com"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("UpdateProfile", 1, mock.AnythingOfType("*models.UpdateProfileRequest")).Return(nil, fmt.Errorf("email already exists"))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `"field":"email"`,
+		},
+		{
+			name:        "GitHub連携のフィールド",
+			userID:      1,
+			requestBody: `{"github_username": "octocat"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("UpdateProfile", 1, mock.AnythingOfType("*models.UpdateProfileRequest")).Return(nil, fmt.Errorf("read-only field: github_username"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"field":"github_username"`,
+		},
+		{
+			name:        "不正な形式",
+			userID:      1,
+			requestBody: `{"username": "bad name!"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("UpdateProfile", 1, mock.AnythingOfType("*models.UpdateProfileRequest")).Return(nil, fmt.Errorf("invalid profile: username format"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid username or email format",
+		},
+		{
+			name:           "不正なJSON",
+			userID:         1,
+			requestBody:    `{"username":`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+
+			handler := handlers.NewAuthHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/auth/me", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			if tt.userID != nil {
+				c.Set("user_id", tt.userID)
+			}
+
+			handler.UpdateProfile(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_DeleteAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		userID         interface{}
+		requestBody    string
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "正常な削除",
+			userID:      1,
+			requestBody: `{"password": "SecurePass123!"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("DeleteAccount", 1, "SecurePass123!").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Account deleted",
+		},
+		{
+			name:   "ボディなし（GitHub認証のみのアカウント）",
+			userID: 2,
+			setupMock: func(m *MockAuthService) {
+				m.On("DeleteAccount", 2, "").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Account deleted",
+		},
+		{
+			name:           "未認証",
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "User not authenticated",
+		},
+		{
+			name:   "パスワード未入力",
+			userID: 1,
+			setupMock: func(m *MockAuthService) {
+				m.On("DeleteAccount", 1, "").Return(fmt.Errorf("password required"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Password is required to delete the account",
+		},
+		{
+			name:        "パスワードの誤り",
+			userID:      1,
+			requestBody: `{"password": "wrong"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("DeleteAccount", 1, "wrong").Return(fmt.Errorf("invalid password"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Password is incorrect",
+		},
+		{
+			name:        "削除に失敗",
+			userID:      1,
+			requestBody: `{"password": "SecurePass123!"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("DeleteAccount", 1, "SecurePass123!").Return(fmt.Errorf("failed to delete account: db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Account deletion failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+
+			handler := handlers.NewAuthHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/auth/me", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			if tt.userID != nil {
+				c.Set("user_id", tt.userID)
+			}
+
+			handler.DeleteAccount(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -3,20 +3,33 @@ package handlers_test
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
+	"memo-app/src/config"
 	"memo-app/src/domain"
 	"memo-app/src/interface/handler"
+	"memo-app/src/middleware"
+	"memo-app/src/problem"
+	"memo-app/src/routes"
 	"memo-app/src/usecase"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Helper function to create string pointer
@@ -24,6 +37,11 @@ func stringPtr(s string) *string {
 	return &s
 }
 
+// Helper function to create time pointer
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
 // MockMemoUsecase は MemoUsecase のモック実装
 type MockMemoUsecase struct {
 	mock.Mock
@@ -37,6 +55,74 @@ func (m *MockMemoUsecase) CreateMemo(ctx context.Context, req usecase.CreateMemo
 	return args.Get(0).(*domain.Memo), args.Error(1)
 }
 
+func (m *MockMemoUsecase) ImportMemosWithIDs(ctx context.Context, reqs []usecase.ImportMemoRequest) ([]domain.Memo, error) {
+	args := m.Called(ctx, reqs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ExportMemos(ctx context.Context, fn func(*domain.Memo) error) error {
+	args := m.Called(ctx, fn)
+	if memos, ok := args.Get(0).([]domain.Memo); ok {
+		for i := range memos {
+			if err := fn(&memos[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockMemoUsecase) ImportMemos(ctx context.Context, reqs []usecase.CreateMemoRequest, atomic bool) (*usecase.ImportResult, error) {
+	args := m.Called(ctx, reqs, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ImportResult), args.Error(1)
+}
+
+func (m *MockMemoUsecase) UpdateMemoMetadata(ctx context.Context, id int, req usecase.UpdateMemoMetadataRequest) (*domain.Memo, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) GetMemoStats(ctx context.Context) (*domain.MemoStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoStats), args.Error(1)
+}
+
+func (m *MockMemoUsecase) GetMemoListState(ctx context.Context) (*domain.MemoListState, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoListState), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ListMemoHistory(ctx context.Context, id int) ([]domain.MemoRevision, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MemoRevision), args.Error(1)
+}
+
+func (m *MockMemoUsecase) RevertMemo(ctx context.Context, id, revisionID int) (*domain.Memo, error) {
+	args := m.Called(ctx, id, revisionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
 func (m *MockMemoUsecase) GetMemo(ctx context.Context, id int) (*domain.Memo, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -63,6 +149,75 @@ func (m *MockMemoUsecase) DeleteMemo(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
+func (m *MockMemoUsecase) BulkDeleteMemos(ctx context.Context, ids []int) ([]usecase.BulkResult, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]usecase.BulkResult), args.Error(1)
+}
+
+func (m *MockMemoUsecase) BulkUpdateMemos(ctx context.Context, ids []int, req usecase.UpdateMemoRequest) (*usecase.BulkUpdateResult, error) {
+	args := m.Called(ctx, ids, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.BulkUpdateResult), args.Error(1)
+}
+
+func (m *MockMemoUsecase) TrashMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) PermanentDeleteMemo(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMemoUsecase) ListDeletedMemos(ctx context.Context, filter domain.MemoFilter) ([]domain.DeletedMemo, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]domain.DeletedMemo), args.Int(1), args.Error(2)
+}
+
+func (m *MockMemoUsecase) RestoreDeletedMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ListSharedMemos(ctx context.Context, filter domain.MemoFilter) ([]domain.SharedMemo, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]domain.SharedMemo), args.Int(1), args.Error(2)
+}
+
+func (m *MockMemoUsecase) ListCategories(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ListOnThisDay(ctx context.Context) ([]domain.Memo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Memo), args.Error(1)
+}
+
 func (m *MockMemoUsecase) ArchiveMemo(ctx context.Context, id int) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -78,6 +233,67 @@ func (m *MockMemoUsecase) SearchMemos(ctx context.Context, query string, filter
 	return args.Get(0).([]domain.Memo), args.Get(1).(int), args.Error(2)
 }
 
+func (m *MockMemoUsecase) PromoteMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) TouchMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) PinMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) UnpinMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) GetMemoDetail(ctx context.Context, id int, expand domain.MemoExpand) (*domain.MemoDetail, error) {
+	args := m.Called(ctx, id, expand)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoDetail), args.Error(1)
+}
+
+func (m *MockMemoUsecase) RecentSearchQueries(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ClearRecentSearchQueries(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockMemoUsecase) ListTags(ctx context.Context, filter domain.TagFilter) ([]domain.TagCount, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TagCount), args.Error(1)
+}
+
 func setupTestRouter(mockUsecase *MockMemoUsecase) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -96,6 +312,12 @@ func setupTestRouter(mockUsecase *MockMemoUsecase) *gin.Engine {
 		api.PATCH("/:id/archive", memoHandler.ArchiveMemo)
 		api.PATCH("/:id/restore", memoHandler.RestoreMemo)
 		api.GET("/search", memoHandler.SearchMemos)
+		api.GET("/search/recent-queries", memoHandler.GetRecentSearchQueries)
+		api.DELETE("/search/recent-queries", memoHandler.ClearRecentSearchQueries)
+		api.POST("/:id/promote", memoHandler.PromoteMemo)
+		api.POST("/:id/touch", memoHandler.TouchMemo)
+		api.PATCH("/:id/pin", memoHandler.PinMemo)
+		api.PATCH("/:id/unpin", memoHandler.UnpinMemo)
 	}
 
 	return r
@@ -173,6 +395,82 @@ func TestMemoHandler_CreateMemo(t *testing.T) {
 	}
 }
 
+func TestMemoHandler_BindJSONErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectedCode string
+	}{
+		{
+			name:         "create with truncated JSON",
+			method:       "POST",
+			path:         "/api/memos",
+			body:         `{"title": "Test Memo", "content": "trunc`,
+			expectedCode: handler.ErrorCodeMalformedJSON,
+		},
+		{
+			name:         "create with syntax error",
+			method:       "POST",
+			path:         "/api/memos",
+			body:         `{"title": "Test Memo",, }`,
+			expectedCode: handler.ErrorCodeMalformedJSON,
+		},
+		{
+			name:         "create with wrong-typed field",
+			method:       "POST",
+			path:         "/api/memos",
+			body:         `{"title": 123, "content": "Content"}`,
+			expectedCode: handler.ErrorCodeValidationFailed,
+		},
+		{
+			name:         "create with missing required field",
+			method:       "POST",
+			path:         "/api/memos",
+			body:         `{"content": "Content"}`,
+			expectedCode: handler.ErrorCodeValidationFailed,
+		},
+		{
+			name:         "update with truncated JSON",
+			method:       "PUT",
+			path:         "/api/memos/1",
+			body:         `{"title": "Updated`,
+			expectedCode: handler.ErrorCodeMalformedJSON,
+		},
+		{
+			name:         "update with wrong-typed field",
+			method:       "PUT",
+			path:         "/api/memos/1",
+			body:         `{"tags": "not-an-array"}`,
+			expectedCode: handler.ErrorCodeValidationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			router := setupTestRouter(mockUsecase)
+
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response handler.ErrorResponseDTO
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, response.Code)
+			assert.NotEmpty(t, response.Message)
+
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
 func TestMemoHandler_GetMemo(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -207,6 +505,14 @@ func TestMemoHandler_GetMemo(t *testing.T) {
 			},
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name:   "memo of another user",
+			memoID: "5",
+			mockSetup: func(m *MockMemoUsecase) {
+				m.On("GetMemo", mock.Anything, 5).Return(nil, usecase.ErrMemoForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
 	}
 
 	for _, tt := range tests {
@@ -235,73 +541,609 @@ func TestMemoHandler_GetMemo(t *testing.T) {
 	}
 }
 
-func TestMemoHandler_ListMemos(t *testing.T) {
-	mockUsecase := new(MockMemoUsecase)
+func TestMemoHandler_ProblemJSON(t *testing.T) {
+	t.Run("Accept で要求された場合は problem+json でエラーを返す", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemo", mock.Anything, 999).Return(nil, usecase.ErrMemoNotFound)
+		router := setupTestRouter(mockUsecase)
+
+		req, _ := http.NewRequest("GET", "/api/memos/999", nil)
+		req.Header.Set("Accept", problem.ContentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, problem.DefaultType, response["type"])
+		assert.Equal(t, float64(http.StatusNotFound), response["status"])
+		assert.Equal(t, "/api/memos/999", response["instance"])
+		assert.NotEmpty(t, response["title"])
+		assert.NotContains(t, response, "error")
+	})
+
+	t.Run("Accept がない場合は従来のエラーボディを返す", func(t *testing.T) {
+		router := setupTestRouter(new(MockMemoUsecase))
+
+		req, _ := http.NewRequest("GET", "/api/memos/invalid", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		var response handler.ErrorResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.NotEmpty(t, response.Error)
+	})
+}
 
-	mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{
+func TestMemoHandler_GetMemo_Expand(t *testing.T) {
+	memo := &domain.Memo{
+		ID:      1,
+		Title:   "Test Memo",
+		Content: "This is a test memo",
+		Status:  domain.StatusActive,
+	}
+	revisions := []domain.MemoRevision{
+		{ID: 10, MemoID: 1, Title: "Old Title", Content: "Old content", Priority: domain.PriorityLow},
+	}
+	attachments := []domain.MemoAttachment{
+		{ID: 20, MemoID: 1, Filename: "image.png", ContentType: "image/png", Size: 1024},
+	}
+
+	tests := []struct {
+		name              string
+		query             string
+		expand            domain.MemoExpand
+		detail            *domain.MemoDetail
+		expectedStatus    int
+		expectRevisions   bool
+		expectAttachments bool
+	}{
 		{
-			ID:      1,
-			Title:   "Test Memo 1",
-			Content: "Content 1",
-			Status:  domain.StatusActive,
+			name:              "expand revisions",
+			query:             "revisions",
+			expand:            domain.MemoExpand{Revisions: true},
+			detail:            &domain.MemoDetail{Memo: memo, Revisions: revisions},
+			expectedStatus:    http.StatusOK,
+			expectRevisions:   true,
+			expectAttachments: false,
 		},
 		{
-			ID:      2,
-			Title:   "Test Memo 2",
-			Content: "Content 2",
-			Status:  domain.StatusActive,
+			name:              "expand attachments",
+			query:             "attachments",
+			expand:            domain.MemoExpand{Attachments: true},
+			detail:            &domain.MemoDetail{Memo: memo, Attachments: attachments},
+			expectedStatus:    http.StatusOK,
+			expectRevisions:   false,
+			expectAttachments: true,
 		},
-	}, 2, nil)
+		{
+			name:              "expand both",
+			query:             "revisions,attachments",
+			expand:            domain.MemoExpand{Revisions: true, Attachments: true},
+			detail:            &domain.MemoDetail{Memo: memo, Revisions: revisions, Attachments: attachments},
+			expectedStatus:    http.StatusOK,
+			expectRevisions:   true,
+			expectAttachments: true,
+		},
+		{
+			name:           "invalid expand value",
+			query:          "revisions,comments",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
 
-	router := setupTestRouter(mockUsecase)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			if tt.detail != nil {
+				mockUsecase.On("GetMemoDetail", mock.Anything, 1, tt.expand).Return(tt.detail, nil)
+			}
 
-	req, _ := http.NewRequest("GET", "/api/memos", nil)
+			router := setupTestRouter(mockUsecase)
 
-	w := httptest.NewRecorder()
-	router.ServeHTTP(w, req)
+			req, _ := http.NewRequest("GET", "/api/memos/1?expand="+tt.query, nil)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
 
-	// レスポンス形式をチェック（実際のレスポンス構造に合わせて調整）
-	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, w.Code)
 
-	mockUsecase.AssertExpectations(t)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "Test Memo", response["title"])
+
+				_, hasRevisions := response["revisions"]
+				_, hasAttachments := response["attachments"]
+				assert.Equal(t, tt.expectRevisions, hasRevisions)
+				assert.Equal(t, tt.expectAttachments, hasAttachments)
+				if tt.expectRevisions {
+					assert.Len(t, response["revisions"], 1)
+				}
+				if tt.expectAttachments {
+					assert.Len(t, response["attachments"], 1)
+				}
+			}
+
+			mockUsecase.AssertExpectations(t)
+		})
+	}
 }
 
-func TestMemoHandler_UpdateMemo(t *testing.T) {
+func TestMemoHandler_GetMemo_DeletionPreview(t *testing.T) {
+	trashedAt := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+
 	tests := []struct {
-		name           string
-		memoID         string
-		requestBody    interface{}
-		mockSetup      func(*MockMemoUsecase)
-		expectedStatus int
+		name            string
+		memo            *domain.Memo
+		action          string
+		endpoint        string
+		purgeAt         *time.Time
+		restorableUntil bool
 	}{
 		{
-			name:   "successful update",
-			memoID: "1",
-			requestBody: usecase.UpdateMemoRequest{
-				Title:   stringPtr("Updated Title"),
-				Content: stringPtr("Updated Content"),
-			},
-			mockSetup: func(m *MockMemoUsecase) {
-				m.On("UpdateMemo", mock.Anything, 1, mock.AnythingOfType("usecase.UpdateMemoRequest")).Return(&domain.Memo{
-					ID:        1,
-					Title:     "Updated Title",
-					Content:   "Updated Content",
-					Status:    domain.StatusActive,
-					UpdatedAt: time.Now(),
-				}, nil)
-			},
-			expectedStatus: http.StatusOK,
+			name:     "active memo is archived by DELETE next",
+			memo:     &domain.Memo{ID: 1, Status: domain.StatusActive, Priority: domain.PriorityMedium},
+			action:   "archive",
+			endpoint: "DELETE /api/memos/1",
 		},
 		{
-			name:           "invalid memo ID",
-			memoID:         "invalid",
-			requestBody:    map[string]string{"title": "test"},
-			mockSetup:      func(m *MockMemoUsecase) {},
-			expectedStatus: http.StatusBadRequest,
+			name:     "archived memo is trashed by DELETE next",
+			memo:     &domain.Memo{ID: 1, Status: domain.StatusArchived, Priority: domain.PriorityMedium},
+			action:   "trash",
+			endpoint: "DELETE /api/memos/1",
+		},
+		{
+			name:            "trashed memo is deleted permanently next and stays restorable from the recycle log",
+			memo:            &domain.Memo{ID: 1, Status: domain.StatusTrashed, Priority: domain.PriorityMedium, TrashedAt: &trashedAt},
+			action:          "permanent_delete",
+			endpoint:        "DELETE /api/memos/1/permanent",
+			purgeAt:         timePtr(trashedAt.Add(30 * 24 * time.Hour)),
+			restorableUntil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			mockUsecase.On("GetMemo", mock.Anything, 1).Return(tt.memo, nil)
+
+			before := time.Now()
+			req, _ := http.NewRequest("GET", "/api/memos/1?include=deletion_preview", nil)
+			w := httptest.NewRecorder()
+			setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var resp handler.MemoResponseDTO
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.NotNil(t, resp.DeletionPreview)
+			assert.Equal(t, string(tt.memo.Status), resp.DeletionPreview.Status)
+			assert.Equal(t, tt.action, resp.DeletionPreview.NextDeleteAction)
+			assert.Equal(t, tt.endpoint, resp.DeletionPreview.Endpoint)
+			assert.True(t, resp.DeletionPreview.Reversible)
+			if tt.purgeAt != nil {
+				require.NotNil(t, resp.DeletionPreview.PurgeAt)
+				assert.True(t, tt.purgeAt.Equal(*resp.DeletionPreview.PurgeAt))
+			} else {
+				assert.Nil(t, resp.DeletionPreview.PurgeAt)
+			}
+			if tt.restorableUntil {
+				// 既定の MEMO_DELETED_RETENTION（30日）の間はリサイクルログから復元できる
+				require.NotNil(t, resp.DeletionPreview.RestorableUntil)
+				assert.False(t, resp.DeletionPreview.RestorableUntil.Before(before.Add(30*24*time.Hour)))
+			} else {
+				assert.Nil(t, resp.DeletionPreview.RestorableUntil)
+			}
+		})
+	}
+
+	t.Run("omitted unless requested", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemo", mock.Anything, 1).Return(&domain.Memo{ID: 1, Status: domain.StatusActive}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/1", nil)
+		w := httptest.NewRecorder()
+		setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "deletion_preview")
+	})
+
+	t.Run("combined with expand", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoDetail", mock.Anything, 1, domain.MemoExpand{Revisions: true}).
+			Return(&domain.MemoDetail{Memo: &domain.Memo{ID: 1, Status: domain.StatusTrashed}}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/1?expand=revisions&include=deletion_preview", nil)
+		w := httptest.NewRecorder()
+		setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"next_delete_action":"permanent_delete"`)
+	})
+
+	t.Run("rejects unknown include values", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+
+		req, _ := http.NewRequest("GET", "/api/memos/1?include=owner", nil)
+		w := httptest.NewRecorder()
+		setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUsecase.AssertNotCalled(t, "GetMemo", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemoHandler_GetMemo_RenderHTML(t *testing.T) {
+	getMemo := func(mockUsecase *MockMemoUsecase, path string) (*httptest.ResponseRecorder, handler.MemoResponseDTO) {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+		var resp handler.MemoResponseDTO
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w, resp
+	}
+
+	t.Run("returns sanitized HTML alongside the raw content", func(t *testing.T) {
+		// APIで保存された本文はHTMLエスケープされている
+		content := "**bold** [link](javascript:alert(1)) &lt;script&gt;alert(2)&lt;/script&gt;"
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemo", mock.Anything, 1).Return(&domain.Memo{ID: 1, Content: content, Status: domain.StatusActive}, nil)
+
+		w, resp := getMemo(mockUsecase, "/api/memos/1?render=html")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, content, resp.Content)
+		assert.Contains(t, resp.ContentHTML, "<strong>bold</strong>")
+		assert.NotContains(t, resp.ContentHTML, "javascript:")
+		assert.NotContains(t, resp.ContentHTML, "<script")
+	})
+
+	t.Run("combined with expand", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoDetail", mock.Anything, 1, domain.MemoExpand{Revisions: true}).
+			Return(&domain.MemoDetail{Memo: &domain.Memo{ID: 1, Content: "# Title", Status: domain.StatusActive}}, nil)
+
+		w, resp := getMemo(mockUsecase, "/api/memos/1?expand=revisions&render=html")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, resp.ContentHTML, "<h1>Title</h1>")
+	})
+
+	t.Run("omitted unless requested", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemo", mock.Anything, 1).Return(&domain.Memo{ID: 1, Content: "**bold**", Status: domain.StatusActive}, nil)
+
+		w, _ := getMemo(mockUsecase, "/api/memos/1")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "content_html")
+	})
+
+	t.Run("rejects unknown render values", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+
+		w, _ := getMemo(mockUsecase, "/api/memos/1?render=pdf")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUsecase.AssertNotCalled(t, "GetMemo", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemoHandler_GetMemo_Timeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		r := gin.New()
+		r.Use(middleware.TimeoutMiddleware(50 * time.Millisecond))
+		r.GET("/api/memos/:id", handler.NewMemoHandler(mockUsecase, logrus.New()).GetMemo)
+		return r
+	}
+
+	t.Run("slow usecase is canceled and the request returns 503", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		canceled := make(chan error, 1)
+		// DBクエリのように、contextが中断されるまで応答しないユースケース
+		mockUsecase.On("GetMemo", mock.Anything, 1).Run(func(args mock.Arguments) {
+			ctx := args.Get(0).(context.Context)
+			select {
+			case <-ctx.Done():
+				canceled <- ctx.Err()
+			case <-time.After(5 * time.Second):
+				canceled <- nil
+			}
+		}).Return(nil, context.DeadlineExceeded)
+
+		req, _ := http.NewRequest("GET", "/api/memos/1", nil)
+		w := httptest.NewRecorder()
+		start := time.Now()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "request did not complete within 50ms")
+		assert.NotContains(t, w.Body.String(), "Failed to get memo")
+		assert.Less(t, time.Since(start), 2*time.Second)
+		assert.ErrorIs(t, <-canceled, context.DeadlineExceeded)
+	})
+
+	t.Run("fast usecase is not affected", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemo", mock.Anything, 1).Return(&domain.Memo{ID: 1, Title: "Test Memo"}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/1", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Test Memo")
+	})
+}
+
+func TestMemoHandler_ListMemos(t *testing.T) {
+	mockUsecase := new(MockMemoUsecase)
+
+	mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+	mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{
+		{
+			ID:      1,
+			Title:   "Test Memo 1",
+			Content: "Content 1",
+			Status:  domain.StatusActive,
+		},
+		{
+			ID:      2,
+			Title:   "Test Memo 2",
+			Content: "Content 2",
+			Status:  domain.StatusActive,
+		},
+	}, 2, nil)
+
+	router := setupTestRouter(mockUsecase)
+
+	req, _ := http.NewRequest("GET", "/api/memos", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// レスポンス形式をチェック（実際のレスポンス構造に合わせて調整）
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestMemoHandler_ListMemos_PartialLastPage(t *testing.T) {
+	mockUsecase := new(MockMemoUsecase)
+
+	// 全12件、1ページ10件の2ページ目（最終ページ）は2件のみ
+	mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+	mockUsecase.On("ListMemos", mock.Anything, mock.MatchedBy(func(f domain.MemoFilter) bool {
+		return f.Page == 2 && f.Limit == 10
+	})).Return([]domain.Memo{
+		{ID: 11, Title: "Test Memo 11", Content: "Content 11", Status: domain.StatusActive},
+		{ID: 12, Title: "Test Memo 12", Content: "Content 12", Status: domain.StatusActive},
+	}, 12, nil)
+
+	router := setupTestRouter(mockUsecase)
+
+	req, _ := http.NewRequest("GET", "/api/memos?page=2&limit=10", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response handler.MemoListResponseDTO
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, len(response.Memos), response.Returned)
+	assert.Equal(t, 2, response.Returned)
+	assert.Less(t, response.Returned, response.Limit)
+	assert.Equal(t, 12, response.Total)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestMemoHandler_ListMemos_PageSize(t *testing.T) {
+	tests := []struct {
+		name           string
+		clamp          bool
+		query          string
+		expectedLimit  int
+		expectedStatus int
+	}{
+		{name: "default page size when limit is omitted", query: "", expectedLimit: 20, expectedStatus: http.StatusOK},
+		{name: "default page size for zero limit", query: "?limit=0", expectedLimit: 20, expectedStatus: http.StatusOK},
+		{name: "limit up to the maximum is kept", query: "?limit=50", expectedLimit: 50, expectedStatus: http.StatusOK},
+		{name: "limit above the maximum is rejected", query: "?limit=51", expectedStatus: http.StatusBadRequest},
+		{name: "limit above the maximum is clamped when configured", clamp: true, query: "?limit=500", expectedLimit: 50, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+			mockUsecase.On("ListMemos", mock.Anything, mock.MatchedBy(func(f domain.MemoFilter) bool {
+				return f.Limit == tt.expectedLimit
+			})).Return([]domain.Memo{{ID: 1, Title: "Memo", Content: "Content", Status: domain.StatusActive}}, 45, nil)
+
+			cfg := config.DefaultMemoConfig()
+			cfg.DefaultPageSize = 20
+			cfg.MaxPageSize = 50
+			cfg.ClampPageSize = tt.clamp
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.GET("/api/memos", handler.NewMemoHandlerWithConfig(mockUsecase, logrus.New(), cfg).ListMemos)
+
+			req, _ := http.NewRequest("GET", "/api/memos"+tt.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				assert.Contains(t, w.Body.String(), "limit must not exceed 50")
+				mockUsecase.AssertNotCalled(t, "ListMemos", mock.Anything, mock.Anything)
+				return
+			}
+
+			var response handler.MemoListResponseDTO
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedLimit, response.Limit)
+			assert.Equal(t, (45+tt.expectedLimit-1)/tt.expectedLimit, response.TotalPages)
+		})
+	}
+
+	t.Run("handlers built with a partial config fall back to the defaults", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+		mockUsecase.On("ListMemos", mock.Anything, mock.MatchedBy(func(f domain.MemoFilter) bool {
+			return f.Limit == 10
+		})).Return([]domain.Memo{}, 0, nil)
+
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.GET("/api/memos", handler.NewMemoHandlerWithConfig(mockUsecase, logrus.New(), &config.MemoConfig{}).ListMemos)
+
+		req, _ := http.NewRequest("GET", "/api/memos", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response handler.MemoListResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 0, response.TotalPages)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestMemoHandler_ZeroLimit(t *testing.T) {
+	defaultLimit := mock.MatchedBy(func(f domain.MemoFilter) bool { return f.Limit == 10 })
+	memo := domain.Memo{ID: 1, Title: "Memo", Content: "Content", Status: domain.StatusActive}
+
+	mockUsecase := new(MockMemoUsecase)
+	mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+	mockUsecase.On("ListMemos", mock.Anything, defaultLimit).Return([]domain.Memo{memo}, 25, nil)
+	mockUsecase.On("SearchMemos", mock.Anything, "memo", defaultLimit).Return([]domain.Memo{memo}, 25, nil)
+	mockUsecase.On("ListDeletedMemos", mock.Anything, defaultLimit).Return([]domain.DeletedMemo{{ID: 1, Memo: memo}}, 25, nil)
+	mockUsecase.On("ListSharedMemos", mock.Anything, defaultLimit).Return([]domain.SharedMemo{{Memo: memo}}, 25, nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	routes.SetupRoutes(r, handler.NewMemoHandler(mockUsecase, logrus.New()))
+
+	// limit=0 や負の値は除算に使われる前にデフォルトの件数に置き換わる
+	for _, path := range []string{
+		"/api/memos?limit=0",
+		"/api/memos?limit=-5",
+		"/api/memos/search?search=memo&limit=0",
+		"/api/memos/deleted?limit=0",
+		"/api/memos/shared-with-me?limit=0",
+	} {
+		t.Run(path, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var response struct {
+				Limit      int `json:"limit"`
+				TotalPages int `json:"total_pages"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, 10, response.Limit)
+			assert.Equal(t, 3, response.TotalPages)
+		})
+	}
+}
+
+func TestMemoHandler_ListMemos_UnknownQueryParams(t *testing.T) {
+	tests := []struct {
+		name           string
+		strict         bool
+		expectedStatus int
+	}{
+		{
+			name:           "lenient mode ignores typo'd param",
+			strict:         false,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "strict mode rejects typo'd param",
+			strict:         true,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			if !tt.strict {
+				mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+				mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{}, 0, nil)
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			memoHandler := handler.NewMemoHandlerWithConfig(mockUsecase, logrus.New(), &config.MemoConfig{StrictQueryParams: tt.strict})
+			router.GET("/api/memos", memoHandler.ListMemos)
+
+			req, _ := http.NewRequest("GET", "/api/memos?statuss=active&page=1", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.strict {
+				var response handler.ErrorResponseDTO
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Contains(t, response.Message, "statuss")
+				assert.NotContains(t, response.Message, "page")
+			}
+
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoHandler_UpdateMemo(t *testing.T) {
+	tests := []struct {
+		name           string
+		memoID         string
+		requestBody    interface{}
+		mockSetup      func(*MockMemoUsecase)
+		expectedStatus int
+	}{
+		{
+			name:   "successful update",
+			memoID: "1",
+			requestBody: usecase.UpdateMemoRequest{
+				Title:   stringPtr("Updated Title"),
+				Content: stringPtr("Updated Content"),
+			},
+			mockSetup: func(m *MockMemoUsecase) {
+				m.On("UpdateMemo", mock.Anything, 1, mock.AnythingOfType("usecase.UpdateMemoRequest")).Return(&domain.Memo{
+					ID:        1,
+					Title:     "Updated Title",
+					Content:   "Updated Content",
+					Status:    domain.StatusActive,
+					UpdatedAt: time.Now(),
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid memo ID",
+			memoID:         "invalid",
+			requestBody:    map[string]string{"title": "test"},
+			mockSetup:      func(m *MockMemoUsecase) {},
+			expectedStatus: http.StatusBadRequest,
 		},
 		{
 			name:           "invalid request body",
@@ -388,6 +1230,22 @@ func TestMemoHandler_DeleteMemo(t *testing.T) {
 			},
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name:   "memo of another user",
+			memoID: "5",
+			mockSetup: func(m *MockMemoUsecase) {
+				m.On("DeleteMemo", mock.Anything, 5).Return(usecase.ErrMemoForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "memo already in trash",
+			memoID: "6",
+			mockSetup: func(m *MockMemoUsecase) {
+				m.On("DeleteMemo", mock.Anything, 6).Return(usecase.ErrMemoAlreadyTrashed)
+			},
+			expectedStatus: http.StatusConflict,
+		},
 	}
 
 	for _, tt := range tests {
@@ -409,41 +1267,55 @@ func TestMemoHandler_DeleteMemo(t *testing.T) {
 	}
 }
 
-func TestMemoHandler_SearchMemos(t *testing.T) {
+func TestMemoHandler_PromoteMemo(t *testing.T) {
 	tests := []struct {
 		name           string
-		queryParams    string
+		memoID         string
+		userID         int
 		mockSetup      func(*MockMemoUsecase)
 		expectedStatus int
 	}{
 		{
-			name:        "successful search",
-			queryParams: "?search=test&limit=10&page=1",
+			name:   "successful promote",
+			memoID: "1",
 			mockSetup: func(m *MockMemoUsecase) {
-				m.On("SearchMemos", mock.Anything, "test", mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{
-					{
-						ID:      1,
-						Title:   "Test Memo",
-						Content: "Test content",
-						Status:  domain.StatusActive,
-					},
-				}, 1, nil)
+				m.On("PromoteMemo", mock.Anything, 1).Return(&domain.Memo{
+					ID:       1,
+					Title:    "Test Memo",
+					Content:  "This is a test memo",
+					Priority: domain.PriorityHigh,
+					Status:   domain.StatusActive,
+					Pinned:   true,
+				}, nil)
 			},
 			expectedStatus: http.StatusOK,
 		},
 		{
-			name:        "empty search query",
-			queryParams: "?search=",
+			name:           "invalid memo ID",
+			memoID:         "invalid",
+			mockSetup:      func(m *MockMemoUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "memo not found",
+			memoID: "999",
 			mockSetup: func(m *MockMemoUsecase) {
-				m.On("SearchMemos", mock.Anything, "", mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{}, 0, nil)
+				m.On("PromoteMemo", mock.Anything, 999).Return(nil, usecase.ErrMemoNotFound)
 			},
-			expectedStatus: http.StatusOK,
+			expectedStatus: http.StatusNotFound,
 		},
 		{
-			name:           "search with invalid limit",
-			queryParams:    "?search=test&limit=invalid",
-			mockSetup:      func(m *MockMemoUsecase) {},
-			expectedStatus: http.StatusBadRequest,
+			name:   "other user's memo",
+			memoID: "1",
+			userID: 2,
+			mockSetup: func(m *MockMemoUsecase) {
+				// ユーザーIDがコンテキスト経由でユースケースに渡されることを確認
+				m.On("PromoteMemo", mock.MatchedBy(func(ctx context.Context) bool {
+					userID, ok := domain.UserIDFromContext(ctx)
+					return ok && userID == 2
+				}), 1).Return(nil, usecase.ErrMemoNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
 		},
 	}
 
@@ -452,16 +1324,2156 @@ func TestMemoHandler_SearchMemos(t *testing.T) {
 			mockUsecase := new(MockMemoUsecase)
 			tt.mockSetup(mockUsecase)
 
-			router := setupTestRouter(mockUsecase)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			if tt.userID > 0 {
+				// 認証ミドルウェアの代わりにユーザーIDを設定
+				router.Use(func(c *gin.Context) {
+					c.Set("user_id", tt.userID)
+					c.Next()
+				})
+			}
+			memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+			router.POST("/api/memos/:id/promote", memoHandler.PromoteMemo)
 
-			req, _ := http.NewRequest("GET", "/api/memos/search"+tt.queryParams, nil)
+			req, _ := http.NewRequest("POST", "/api/memos/"+tt.memoID+"/promote", nil)
 
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 
+			if tt.expectedStatus == http.StatusOK {
+				var response handler.MemoResponseDTO
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "high", response.Priority)
+				assert.True(t, response.Pinned)
+			}
+
 			mockUsecase.AssertExpectations(t)
 		})
 	}
 }
+
+func TestMemoHandler_TouchMemo(t *testing.T) {
+	updatedAt := time.Now()
+
+	tests := []struct {
+		name           string
+		memoID         string
+		mockSetup      func(*MockMemoUsecase)
+		expectedStatus int
+	}{
+		{
+			name:   "successful touch",
+			memoID: "1",
+			mockSetup: func(m *MockMemoUsecase) {
+				m.On("TouchMemo", mock.Anything, 1).Return(&domain.Memo{
+					ID:        1,
+					Title:     "Test Memo",
+					Content:   "This is a test memo",
+					Status:    domain.StatusActive,
+					UpdatedAt: updatedAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid memo ID",
+			memoID:         "invalid",
+			mockSetup:      func(m *MockMemoUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "memo not found",
+			memoID: "999",
+			mockSetup: func(m *MockMemoUsecase) {
+				m.On("TouchMemo", mock.Anything, 999).Return(nil, usecase.ErrMemoNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			tt.mockSetup(mockUsecase)
+
+			router := setupTestRouter(mockUsecase)
+
+			req, _ := http.NewRequest("POST", "/api/memos/"+tt.memoID+"/touch", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var response handler.MemoResponseDTO
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "Test Memo", response.Title)
+				assert.True(t, updatedAt.Equal(response.UpdatedAt))
+			}
+
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoHandler_SearchMemos_Rank(t *testing.T) {
+	rank := 0.6
+	mockUsecase := new(MockMemoUsecase)
+	mockUsecase.On("SearchMemos", mock.Anything, "kubernetes", mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{
+		{ID: 1, Title: "Kubernetes", Content: "Ranked", Status: domain.StatusActive, SearchRank: &rank},
+		{ID: 2, Title: "Fallback", Content: "Unranked", Status: domain.StatusActive},
+	}, 2, nil)
+
+	req, _ := http.NewRequest("GET", "/api/memos/search?search=kubernetes", nil)
+	w := httptest.NewRecorder()
+	setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Memos []map[string]interface{} `json:"memos"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Memos, 2)
+	assert.Equal(t, 0.6, response.Memos[0]["rank"])
+	assert.NotContains(t, response.Memos[1], "rank")
+}
+
+func TestMemoHandler_SearchMemos(t *testing.T) {
+	tests := []struct {
+		name           string
+		queryParams    string
+		mockSetup      func(*MockMemoUsecase)
+		expectedStatus int
+	}{
+		{
+			name:        "successful search",
+			queryParams: "?search=test&limit=10&page=1",
+			mockSetup: func(m *MockMemoUsecase) {
+				m.On("SearchMemos", mock.Anything, "test", mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{
+					{
+						ID:      1,
+						Title:   "Test Memo",
+						Content: "Test content",
+						Status:  domain.StatusActive,
+					},
+				}, 1, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "empty search query",
+			queryParams: "?search=",
+			mockSetup: func(m *MockMemoUsecase) {
+				m.On("SearchMemos", mock.Anything, "", mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{}, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "search with invalid limit",
+			queryParams:    "?search=test&limit=invalid",
+			mockSetup:      func(m *MockMemoUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			tt.mockSetup(mockUsecase)
+
+			router := setupTestRouter(mockUsecase)
+
+			req, _ := http.NewRequest("GET", "/api/memos/search"+tt.queryParams, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoHandler_RecentSearchQueries(t *testing.T) {
+	t.Run("get recent queries", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("RecentSearchQueries", mock.Anything).Return([]string{"golang", "docker"}, nil)
+
+		router := setupTestRouter(mockUsecase)
+
+		req, _ := http.NewRequest("GET", "/api/memos/search/recent-queries", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response handler.RecentQueriesResponseDTO
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"golang", "docker"}, response.Queries)
+
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("clear recent queries", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ClearRecentSearchQueries", mock.Anything).Return(nil)
+
+		router := setupTestRouter(mockUsecase)
+
+		req, _ := http.NewRequest("DELETE", "/api/memos/search/recent-queries", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestMemoHandler_EmptyListStatus(t *testing.T) {
+	paths := []string{
+		"/api/memos",
+		"/api/memos?status=archived",
+		"/api/memos/search?search=nothing",
+	}
+
+	tests := []struct {
+		name           string
+		emptyStatus    int
+		expectedStatus int
+	}{
+		{name: "default returns 200 with empty array", emptyStatus: http.StatusOK, expectedStatus: http.StatusOK},
+		{name: "configured 204 returns no content", emptyStatus: http.StatusNoContent, expectedStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		for _, path := range paths {
+			t.Run(tt.name+" "+path, func(t *testing.T) {
+				mockUsecase := new(MockMemoUsecase)
+				mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+				mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{}, 0, nil).Maybe()
+				mockUsecase.On("SearchMemos", mock.Anything, mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{}, 0, nil).Maybe()
+
+				gin.SetMode(gin.TestMode)
+				router := gin.New()
+				memoHandler := handler.NewMemoHandlerWithConfig(mockUsecase, logrus.New(), &config.MemoConfig{EmptyListStatus: tt.emptyStatus})
+				router.GET("/api/memos", memoHandler.ListMemos)
+				router.GET("/api/memos/search", memoHandler.SearchMemos)
+
+				req, _ := http.NewRequest("GET", path, nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				if tt.expectedStatus == http.StatusNoContent {
+					assert.Empty(t, w.Body.Bytes())
+					return
+				}
+
+				var response map[string]interface{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, []interface{}{}, response["memos"])
+				assert.Equal(t, float64(0), response["total"])
+				assert.Equal(t, float64(0), response["returned"])
+				assert.Equal(t, float64(0), response["total_pages"])
+			})
+		}
+	}
+}
+
+func TestMemoHandler_ContentSoftLimit(t *testing.T) {
+	cfg := &config.MemoConfig{ContentMaxLength: 100, ContentSoftLimit: 50}
+
+	tests := []struct {
+		name            string
+		content         string
+		mockErr         error
+		expectedStatus  int
+		expectedWarning bool
+	}{
+		{name: "below soft limit has no warnings", content: strings.Repeat("a", 50), expectedStatus: http.StatusCreated},
+		{name: "between soft and hard limits succeeds with warning", content: strings.Repeat("a", 80), expectedStatus: http.StatusCreated, expectedWarning: true},
+		{name: "above hard limit fails", content: strings.Repeat("a", 101), mockErr: usecase.ErrContentTooLong, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			if tt.mockErr != nil {
+				mockUsecase.On("CreateMemo", mock.Anything, mock.AnythingOfType("usecase.CreateMemoRequest")).Return(nil, tt.mockErr)
+			} else {
+				mockUsecase.On("CreateMemo", mock.Anything, mock.AnythingOfType("usecase.CreateMemoRequest")).Return(&domain.Memo{
+					ID:       1,
+					Title:    "Long memo",
+					Content:  tt.content,
+					Priority: domain.PriorityMedium,
+					Status:   domain.StatusActive,
+				}, nil)
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			memoHandler := handler.NewMemoHandlerWithConfig(mockUsecase, logrus.New(), cfg)
+			router.POST("/api/memos", memoHandler.CreateMemo)
+
+			body, err := json.Marshal(map[string]string{"title": "Long memo", "content": tt.content})
+			assert.NoError(t, err)
+			req, _ := http.NewRequest("POST", "/api/memos", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+
+			var response handler.MemoResponseDTO
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.expectedWarning {
+				assert.Len(t, response.Warnings, 1)
+				assert.Contains(t, response.Warnings[0], "content is very long")
+			} else {
+				assert.Empty(t, response.Warnings)
+				assert.NotContains(t, w.Body.String(), "warnings")
+			}
+		})
+	}
+}
+
+func TestMemoHandler_ListTags(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedFilter *domain.TagFilter
+		expectedStatus int
+	}{
+		{name: "no parameters", query: "", expectedFilter: &domain.TagFilter{}, expectedStatus: http.StatusOK},
+		{name: "limit and min_count", query: "?limit=2&min_count=3", expectedFilter: &domain.TagFilter{MinCount: 3, Limit: 2}, expectedStatus: http.StatusOK},
+		{name: "zero limit rejected", query: "?limit=0", expectedStatus: http.StatusBadRequest},
+		{name: "non-numeric min_count rejected", query: "?min_count=many", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			if tt.expectedFilter != nil {
+				mockUsecase.On("ListTags", mock.Anything, *tt.expectedFilter).Return([]domain.TagCount{
+					{Tag: "golang", Count: 12},
+					{Tag: "docker", Count: 3},
+				}, nil)
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+			router.GET("/api/memos/tags", memoHandler.ListTags)
+
+			req, _ := http.NewRequest("GET", "/api/memos/tags"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response handler.TagListResponseDTO
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, []handler.TagCountResponseDTO{
+					{Tag: "golang", Count: 12},
+					{Tag: "docker", Count: 3},
+				}, response.Tags)
+			}
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoHandler_ListMemos_Cursor(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 123456000, time.UTC)
+	fullPage := []domain.Memo{
+		{ID: 9, Title: "Newest", Content: "c", Priority: domain.PriorityMedium, Status: domain.StatusActive, CreatedAt: createdAt},
+		{ID: 8, Title: "Older", Content: "c", Priority: domain.PriorityMedium, Status: domain.StatusActive, CreatedAt: createdAt},
+	}
+
+	listWith := func(query string, memos []domain.Memo, total int) (*MockMemoUsecase, *httptest.ResponseRecorder) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+		mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return(memos, total, nil).Maybe()
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+		router.GET("/api/memos", memoHandler.ListMemos)
+
+		req, _ := http.NewRequest("GET", "/api/memos"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return mockUsecase, w
+	}
+
+	t.Run("offset paging remains the default", func(t *testing.T) {
+		mockUsecase, w := listWith("?limit=2", fullPage, 5)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		filter := mockUsecase.Calls[len(mockUsecase.Calls)-1].Arguments.Get(1).(domain.MemoFilter) // 一覧の取得は更新状況の取得の後
+		assert.Nil(t, filter.Cursor)
+
+		var response handler.MemoListResponseDTO
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Empty(t, response.NextCursor)
+	})
+
+	t.Run("empty cursor starts keyset paging and returns next cursor", func(t *testing.T) {
+		mockUsecase, w := listWith("?limit=2&cursor=", fullPage, 5)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		filter := mockUsecase.Calls[len(mockUsecase.Calls)-1].Arguments.Get(1).(domain.MemoFilter) // 一覧の取得は更新状況の取得の後
+		assert.NotNil(t, filter.Cursor)
+		assert.True(t, filter.Cursor.IsZero())
+
+		var response handler.MemoListResponseDTO
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.NotEmpty(t, response.NextCursor)
+
+		// 次のカーソルは最後のメモの created_at と id を表す
+		next, _ := listWith("?limit=2&cursor="+response.NextCursor, fullPage[:1], 5)
+		nextFilter := next.Calls[len(next.Calls)-1].Arguments.Get(1).(domain.MemoFilter) // 一覧の取得は更新状況の取得の後
+		assert.Equal(t, 8, nextFilter.Cursor.ID)
+		assert.True(t, createdAt.Equal(nextFilter.Cursor.CreatedAt))
+	})
+
+	t.Run("cursor of a pinned memo keeps pinned-first ordering", func(t *testing.T) {
+		pinnedPage := []domain.Memo{fullPage[0], fullPage[1]}
+		pinnedPage[1].Pinned = true
+		_, w := listWith("?limit=2&cursor=", pinnedPage, 5)
+		var response handler.MemoListResponseDTO
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		next, _ := listWith("?limit=2&cursor="+response.NextCursor, fullPage[:1], 5)
+		nextFilter := next.Calls[len(next.Calls)-1].Arguments.Get(1).(domain.MemoFilter) // 一覧の取得は更新状況の取得の後
+		assert.True(t, nextFilter.Cursor.Pinned)
+		assert.Equal(t, 8, nextFilter.Cursor.ID)
+	})
+
+	t.Run("cursor without pinned part continues among unpinned memos", func(t *testing.T) {
+		legacy := base64.RawURLEncoding.EncodeToString([]byte(createdAt.Format(time.RFC3339Nano) + ",8"))
+		mockUsecase, w := listWith("?limit=2&cursor="+legacy, fullPage[:1], 5)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		filter := mockUsecase.Calls[len(mockUsecase.Calls)-1].Arguments.Get(1).(domain.MemoFilter) // 一覧の取得は更新状況の取得の後
+		assert.False(t, filter.Cursor.Pinned)
+		assert.Equal(t, 8, filter.Cursor.ID)
+	})
+
+	t.Run("sort cannot be combined with cursor", func(t *testing.T) {
+		mockUsecase, w := listWith("?cursor=&sort=-priority", nil, 0)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUsecase.AssertNotCalled(t, "ListMemos", mock.Anything, mock.Anything)
+	})
+
+	t.Run("next cursor is empty when exhausted", func(t *testing.T) {
+		_, w := listWith("?limit=2&cursor=", fullPage[:1], 1)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response handler.MemoListResponseDTO
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Empty(t, response.NextCursor)
+		assert.Contains(t, w.Body.String(), `"next_cursor":""`)
+	})
+
+	t.Run("malformed cursor is rejected", func(t *testing.T) {
+		mockUsecase, w := listWith("?cursor=not-a-cursor", nil, 0)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid cursor")
+		mockUsecase.AssertNotCalled(t, "ListMemos", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemoHandler_LogValidationRejects(t *testing.T) {
+	payload := "x' OR 1=1 --"
+
+	tests := []struct {
+		name          string
+		enabled       bool
+		expectedEvent bool
+	}{
+		{name: "enabled emits structured event", enabled: true, expectedEvent: true},
+		{name: "disabled by default", enabled: false, expectedEvent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := logtest.NewNullLogger()
+			mockUsecase := new(MockMemoUsecase)
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			memoHandler := handler.NewMemoHandlerWithConfig(mockUsecase, logger, &config.MemoConfig{LogValidationRejects: tt.enabled})
+			router.POST("/api/memos", memoHandler.CreateMemo)
+
+			body, err := json.Marshal(map[string]string{"title": payload, "content": "content"})
+			assert.NoError(t, err)
+			req, _ := http.NewRequest("POST", "/api/memos", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.RemoteAddr = "203.0.113.7:12345"
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockUsecase.AssertNotCalled(t, "CreateMemo", mock.Anything, mock.Anything)
+
+			var events []*logrus.Entry
+			for _, entry := range hook.AllEntries() {
+				if entry.Data["event"] == "validation_reject" {
+					events = append(events, entry)
+				}
+			}
+
+			if !tt.expectedEvent {
+				assert.Empty(t, events)
+				return
+			}
+
+			assert.Len(t, events, 1)
+			event := events[0]
+			assert.Equal(t, "no_sql_injection", event.Data["rule"])
+			assert.Equal(t, "Title", event.Data["field"])
+			assert.Equal(t, "203.0.113.7", event.Data["client_ip"])
+			for key, value := range event.Data {
+				assert.NotContains(t, fmt.Sprint(value), payload, "raw payload must not be logged (field %s)", key)
+			}
+		})
+	}
+}
+
+func TestMemoHandler_Sort(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedSort   []domain.SortField
+		expectedStatus int
+	}{
+		{
+			name:           "no sort keeps default ordering",
+			path:           "/api/memos",
+			expectedSort:   nil,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "multi-key sort",
+			path: "/api/memos?sort=priority,-created_at",
+			expectedSort: []domain.SortField{
+				{Field: "priority"},
+				{Field: "created_at", Desc: true},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "search supports sort",
+			path:           "/api/memos/search?search=golang&sort=-updated_at,title",
+			expectedSort:   []domain.SortField{{Field: "updated_at", Desc: true}, {Field: "title"}},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "unknown column rejected", path: "/api/memos?sort=password_hash", expectedStatus: http.StatusBadRequest},
+		{name: "injection attempt rejected", path: "/api/memos?sort=" + url.QueryEscape("created_at;DROP TABLE memos"), expectedStatus: http.StatusBadRequest},
+		{name: "duplicate field rejected", path: "/api/memos?sort=title,-title", expectedStatus: http.StatusBadRequest},
+		{name: "empty key rejected", path: "/api/memos?sort=title,", expectedStatus: http.StatusBadRequest},
+		{name: "sort with cursor rejected", path: "/api/memos?sort=title&cursor=", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+			mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{}, 0, nil).Maybe()
+			mockUsecase.On("SearchMemos", mock.Anything, mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{}, 0, nil).Maybe()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+			router.GET("/api/memos", memoHandler.ListMemos)
+			router.GET("/api/memos/search", memoHandler.SearchMemos)
+
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Contains(t, w.Body.String(), "Invalid sort parameter")
+				assert.Empty(t, mockUsecase.Calls)
+				return
+			}
+
+			// 一覧では更新状況の取得が先に呼ばれるため、最後の呼び出しが一覧・検索になる
+			require.NotEmpty(t, mockUsecase.Calls)
+			call := mockUsecase.Calls[len(mockUsecase.Calls)-1]
+			filter := call.Arguments.Get(len(call.Arguments) - 1).(domain.MemoFilter)
+			assert.Equal(t, tt.expectedSort, filter.Sort)
+		})
+	}
+}
+
+func TestMemoHandler_BulkDeleteMemos(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedIDs    []int
+		mockResults    []usecase.BulkResult
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:        "mixed results",
+			body:        `{"ids":[1,2]}`,
+			expectedIDs: []int{1, 2},
+			mockResults: []usecase.BulkResult{
+				{ID: 1, Status: usecase.BulkStatusDeleted},
+				{ID: 2, Status: usecase.BulkStatusNotFound},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "empty ids rejected", body: `{"ids":[]}`, expectedStatus: http.StatusBadRequest},
+		{name: "missing ids rejected", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{name: "non-positive id rejected", body: `{"ids":[1,-2]}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "repository failure",
+			body:           `{"ids":[1]}`,
+			expectedIDs:    []int{1},
+			mockError:      fmt.Errorf("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			if tt.expectedIDs != nil {
+				mockUsecase.On("BulkDeleteMemos", mock.Anything, tt.expectedIDs).Return(tt.mockResults, tt.mockError)
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+			router.POST("/api/memos/bulk-delete", memoHandler.BulkDeleteMemos)
+
+			req, _ := http.NewRequest("POST", "/api/memos/bulk-delete", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response handler.BulkResponseDTO
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, []handler.BulkResultDTO{
+					{ID: 1, Status: "deleted"},
+					{ID: 2, Status: "not_found"},
+				}, response.Results)
+			}
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoHandler_BulkUpdateMemos(t *testing.T) {
+	archived := "archived"
+	work := "work"
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedReq    *usecase.UpdateMemoRequest
+		mockResult     *usecase.BulkUpdateResult
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "status and category",
+			body:           `{"ids":[1,2,3],"status":"archived","category":"work"}`,
+			expectedReq:    &usecase.UpdateMemoRequest{Status: &archived, Category: &work},
+			mockResult:     &usecase.BulkUpdateResult{Updated: 2, SkippedIDs: []int{3}},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "invalid status rejected", body: `{"ids":[1],"status":"deleted"}`, expectedStatus: http.StatusBadRequest},
+		{name: "missing ids rejected", body: `{"status":"archived"}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "no fields to update",
+			body:           `{"ids":[1]}`,
+			expectedReq:    &usecase.UpdateMemoRequest{},
+			mockError:      usecase.ErrInvalidBulkUpdate,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			if tt.expectedReq != nil {
+				mockUsecase.On("BulkUpdateMemos", mock.Anything, mock.AnythingOfType("[]int"), *tt.expectedReq).Return(tt.mockResult, tt.mockError)
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+			router.PATCH("/api/memos/bulk", memoHandler.BulkUpdateMemos)
+
+			req, _ := http.NewRequest("PATCH", "/api/memos/bulk", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response handler.BulkUpdateResponseDTO
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, handler.BulkUpdateResponseDTO{Updated: 2, SkippedIDs: []int{3}}, response)
+			}
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoHandler_TrashAndPermanentDelete(t *testing.T) {
+	trashedAt := time.Now()
+
+	t.Run("trash returns the trashed memo", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("TrashMemo", mock.Anything, 1).Return(&domain.Memo{
+			ID: 1, Title: "Memo", Priority: domain.PriorityMedium, Status: domain.StatusTrashed, TrashedAt: &trashedAt,
+		}, nil)
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+		router.POST("/api/memos/:id/trash", memoHandler.TrashMemo)
+
+		req, _ := http.NewRequest("POST", "/api/memos/1/trash", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response handler.MemoResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "trashed", response.Status)
+		assert.NotNil(t, response.TrashedAt)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("trash of another user's memo is forbidden when ownership is revealed", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("TrashMemo", mock.Anything, 1).Return(nil, usecase.ErrMemoForbidden)
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.POST("/api/memos/:id/trash", handler.NewMemoHandler(mockUsecase, logrus.New()).TrashMemo)
+
+		req, _ := http.NewRequest("POST", "/api/memos/1/trash", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "trashed memo is deleted", expectedStatus: http.StatusNoContent},
+		{name: "memo not in trash", mockError: usecase.ErrMemoNotTrashed, expectedStatus: http.StatusConflict},
+		{name: "memo not found", mockError: usecase.ErrMemoNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run("permanent delete: "+tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			mockUsecase.On("PermanentDeleteMemo", mock.Anything, 1).Return(tt.mockError)
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+			router.DELETE("/api/memos/:id/permanent", memoHandler.PermanentDeleteMemo)
+
+			req, _ := http.NewRequest("DELETE", "/api/memos/1/permanent", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoHandler_ListSharedMemos(t *testing.T) {
+	sharedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("includes owner and permission", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ListSharedMemos", mock.Anything, domain.MemoFilter{Page: 2, Limit: 5}).Return([]domain.SharedMemo{
+			{
+				Memo:          domain.Memo{ID: 7, Title: "Team notes", Priority: domain.PriorityHigh, Status: domain.StatusActive},
+				OwnerUsername: "alice",
+				Permission:    domain.SharePermissionWrite,
+				SharedAt:      sharedAt,
+			},
+		}, 6, nil)
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+		router.GET("/api/memos/shared-with-me", memoHandler.ListSharedMemos)
+
+		req, _ := http.NewRequest("GET", "/api/memos/shared-with-me?page=2&limit=5", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response handler.SharedMemoListResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Memos, 1)
+		assert.Equal(t, 7, response.Memos[0].ID)
+		assert.Equal(t, "Team notes", response.Memos[0].Title)
+		assert.Equal(t, "alice", response.Memos[0].OwnerUsername)
+		assert.Equal(t, "write", response.Memos[0].Permission)
+		assert.True(t, sharedAt.Equal(response.Memos[0].SharedAt))
+		assert.Equal(t, 6, response.Total)
+		assert.Equal(t, 2, response.TotalPages)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("rejects owned-list filters", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		memoHandler := handler.NewMemoHandlerWithConfig(mockUsecase, logrus.New(), &config.MemoConfig{StrictQueryParams: true})
+		router.GET("/api/memos/shared-with-me", memoHandler.ListSharedMemos)
+
+		req, _ := http.NewRequest("GET", "/api/memos/shared-with-me?status=active", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUsecase.AssertNotCalled(t, "ListSharedMemos", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemoHandler_DeletedMemos(t *testing.T) {
+	deletedAt := time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC)
+
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+		router.GET("/api/memos/deleted", memoHandler.ListDeletedMemos)
+		router.POST("/api/memos/deleted/:id/restore", memoHandler.RestoreDeletedMemo)
+		return router
+	}
+
+	t.Run("lists the recycle log with the entry ID and deletion time", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ListDeletedMemos", mock.Anything, domain.MemoFilter{Page: 1, Limit: 2}).Return([]domain.DeletedMemo{
+			{
+				ID:        11,
+				Memo:      domain.Memo{ID: 4, Title: "Old plan", Priority: domain.PriorityLow, Status: domain.StatusTrashed},
+				DeletedAt: deletedAt,
+			},
+		}, 3, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/deleted?page=1&limit=2", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response handler.DeletedMemoListResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Memos, 1)
+		assert.Equal(t, 4, response.Memos[0].ID)
+		assert.Equal(t, 11, response.Memos[0].DeletedID)
+		assert.True(t, deletedAt.Equal(response.Memos[0].DeletedAt))
+		assert.Equal(t, 3, response.Total)
+		assert.Equal(t, 2, response.TotalPages)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("restore returns the memo with its possibly new ID", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("RestoreDeletedMemo", mock.Anything, 11).Return(&domain.Memo{ID: 42, Title: "Old plan", Priority: domain.PriorityLow, Status: domain.StatusActive}, nil)
+
+		req, _ := http.NewRequest("POST", "/api/memos/deleted/11/restore", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var response handler.MemoResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 42, response.ID)
+		assert.Equal(t, "active", response.Status)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("restore of an unknown entry is not found", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("RestoreDeletedMemo", mock.Anything, 99).Return(nil, usecase.ErrDeletedMemoNotFound)
+
+		req, _ := http.NewRequest("POST", "/api/memos/deleted/99/restore", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestMemoHandler_TagFormat(t *testing.T) {
+	taggedMemo := domain.Memo{ID: 3, Title: "Tagged", Tags: []string{"work", "urgent"}, Priority: domain.PriorityMedium, Status: domain.StatusActive}
+
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+		router.GET("/api/memos", memoHandler.ListMemos)
+		router.GET("/api/memos/:id", memoHandler.GetMemo)
+		return router
+	}
+
+	for _, query := range []string{"", "?tags=strings"} {
+		t.Run("tags as strings "+query, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			mockUsecase.On("GetMemo", mock.Anything, 3).Return(&taggedMemo, nil)
+
+			req, _ := http.NewRequest("GET", "/api/memos/3"+query, nil)
+			w := httptest.NewRecorder()
+			newRouter(mockUsecase).ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, []interface{}{"work", "urgent"}, response["tags"])
+		})
+	}
+
+	t.Run("tags as objects", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemo", mock.Anything, 3).Return(&taggedMemo, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/3?tags=objects", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			ID    int                    `json:"id"`
+			Title string                 `json:"title"`
+			Tags  []handler.TagObjectDTO `json:"tags"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 3, response.ID)
+		assert.Equal(t, "Tagged", response.Title)
+		assert.Equal(t, []handler.TagObjectDTO{{Name: "work"}, {Name: "urgent"}}, response.Tags)
+	})
+
+	t.Run("list tags as objects without filtering", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+		mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{taggedMemo}, 1, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos?tags=objects", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Memos []struct {
+				Tags []handler.TagObjectDTO `json:"tags"`
+			} `json:"memos"`
+			Total int `json:"total"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Memos, 1)
+		assert.Equal(t, []handler.TagObjectDTO{{Name: "work"}, {Name: "urgent"}}, response.Memos[0].Tags)
+		assert.Equal(t, 1, response.Total)
+
+		filter := mockUsecase.Calls[len(mockUsecase.Calls)-1].Arguments.Get(1).(domain.MemoFilter) // 一覧の取得は更新状況の取得の後
+		assert.Empty(t, filter.Tags)
+	})
+
+	t.Run("other values still filter by tag", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+		mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{taggedMemo}, 1, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos?tags=work", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		memos := response["memos"].([]interface{})
+		assert.Equal(t, []interface{}{"work", "urgent"}, memos[0].(map[string]interface{})["tags"])
+
+		filter := mockUsecase.Calls[len(mockUsecase.Calls)-1].Arguments.Get(1).(domain.MemoFilter) // 一覧の取得は更新状況の取得の後
+		assert.Equal(t, []string{"work"}, filter.Tags)
+	})
+
+	t.Run("multiple tags are trimmed and empty entries dropped", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+		mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{taggedMemo}, 1, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos?tags=work,%20urgent%20,,", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		filter := mockUsecase.Calls[len(mockUsecase.Calls)-1].Arguments.Get(1).(domain.MemoFilter) // 一覧の取得は更新状況の取得の後
+		assert.Equal(t, []string{"work", "urgent"}, filter.Tags)
+	})
+}
+
+func TestMemoHandler_DueDate(t *testing.T) {
+	dueDate := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+
+	t.Run("create stores and returns due date", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("CreateMemo", mock.Anything, mock.MatchedBy(func(req usecase.CreateMemoRequest) bool {
+			return req.DueDate != nil && req.DueDate.Equal(dueDate)
+		})).Return(&domain.Memo{ID: 1, Title: "Task", Content: "Do it", Priority: domain.PriorityMedium, Status: domain.StatusActive, DueDate: &dueDate}, nil)
+
+		body := `{"title":"Task","content":"Do it","due_date":"2024-06-30T09:00:00Z"}`
+		req, _ := http.NewRequest("POST", "/api/memos", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var response handler.MemoResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.NotNil(t, response.DueDate)
+		assert.True(t, dueDate.Equal(*response.DueDate))
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("update with invalid due date returns 400", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+
+		req, _ := http.NewRequest("PUT", "/api/memos/1", strings.NewReader(`{"due_date":"2024-06-30"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response handler.ErrorResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Contains(t, response.Message, "RFC3339")
+		mockUsecase.AssertNotCalled(t, "UpdateMemo", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("list passes due range and overdue filters", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+		mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{}, 0, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos?due_after=2024-06-01T00:00:00Z&due_before=2024-07-01T00:00:00%2B09:00&overdue=true", nil)
+		w := httptest.NewRecorder()
+		setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		filter := mockUsecase.Calls[len(mockUsecase.Calls)-1].Arguments.Get(1).(domain.MemoFilter) // 一覧の取得は更新状況の取得の後
+		require.NotNil(t, filter.DueAfter)
+		require.NotNil(t, filter.DueBefore)
+		assert.True(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC).Equal(*filter.DueAfter))
+		assert.True(t, time.Date(2024, 6, 30, 15, 0, 0, 0, time.UTC).Equal(*filter.DueBefore))
+		assert.True(t, filter.Overdue)
+	})
+
+	for _, query := range []string{"due_before=tomorrow", "due_after=2024-06-01", "overdue=yes"} {
+		t.Run("invalid filter "+query, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+
+			req, _ := http.NewRequest("GET", "/api/memos?"+query, nil)
+			w := httptest.NewRecorder()
+			setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockUsecase.AssertNotCalled(t, "ListMemos", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMemoHandler_ProtectedCategoryConflict(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		setup  func(*MockMemoUsecase)
+	}{
+		{
+			name:   "archive",
+			method: "PATCH",
+			path:   "/api/memos/1/archive",
+			setup: func(m *MockMemoUsecase) {
+				m.On("ArchiveMemo", mock.Anything, 1).Return(usecase.ErrLastActiveInCategory)
+			},
+		},
+		{
+			name:   "delete",
+			method: "DELETE",
+			path:   "/api/memos/1",
+			setup: func(m *MockMemoUsecase) {
+				m.On("DeleteMemo", mock.Anything, 1).Return(usecase.ErrLastActiveInCategory)
+			},
+		},
+		{
+			name:   "update status",
+			method: "PUT",
+			path:   "/api/memos/1",
+			body:   `{"status":"archived"}`,
+			setup: func(m *MockMemoUsecase) {
+				m.On("UpdateMemo", mock.Anything, 1, mock.AnythingOfType("usecase.UpdateMemoRequest")).Return(nil, usecase.ErrLastActiveInCategory)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			tt.setup(mockUsecase)
+
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusConflict, w.Code)
+			var response handler.ErrorResponseDTO
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Contains(t, response.Message, "protected category")
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoHandler_PinMemo(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		method         string
+		pinned         bool
+		err            error
+		expectedStatus int
+	}{
+		{name: "pin", path: "/api/memos/1/pin", method: "PinMemo", pinned: true, expectedStatus: http.StatusOK},
+		{name: "unpin", path: "/api/memos/1/unpin", method: "UnpinMemo", pinned: false, expectedStatus: http.StatusOK},
+		{name: "pin missing memo", path: "/api/memos/1/pin", method: "PinMemo", err: usecase.ErrMemoNotFound, expectedStatus: http.StatusNotFound},
+		{name: "unpin missing memo", path: "/api/memos/1/unpin", method: "UnpinMemo", err: usecase.ErrMemoNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := new(MockMemoUsecase)
+			if tt.err != nil {
+				mockUsecase.On(tt.method, mock.Anything, 1).Return(nil, tt.err)
+			} else {
+				mockUsecase.On(tt.method, mock.Anything, 1).Return(&domain.Memo{
+					ID: 1, Title: "Important", Priority: domain.PriorityMedium, Status: domain.StatusActive, Pinned: tt.pinned,
+				}, nil)
+			}
+
+			req, _ := http.NewRequest("PATCH", tt.path, nil)
+			w := httptest.NewRecorder()
+			setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response handler.MemoResponseDTO
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.pinned, response.Pinned)
+			}
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("invalid id", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+
+		req, _ := http.NewRequest("PATCH", "/api/memos/abc/pin", nil)
+		w := httptest.NewRecorder()
+		setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUsecase.AssertNotCalled(t, "PinMemo", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemoHandler_ListOnThisDay(t *testing.T) {
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+		router.GET("/api/memos/on-this-day", memoHandler.ListOnThisDay)
+		return router
+	}
+
+	t.Run("returns memos", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ListOnThisDay", mock.Anything).Return([]domain.Memo{
+			{ID: 4, Title: "Last year", Priority: domain.PriorityMedium, Status: domain.StatusActive, CreatedAt: time.Date(2023, 6, 15, 9, 0, 0, 0, time.UTC)},
+		}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/on-this-day", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response handler.OnThisDayResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Memos, 1)
+		assert.Equal(t, 4, response.Memos[0].ID)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("empty result is an empty array", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ListOnThisDay", mock.Anything).Return([]domain.Memo{}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/on-this-day", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"memos":[]}`, w.Body.String())
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ListOnThisDay", mock.Anything).Return(nil, fmt.Errorf("database error"))
+
+		req, _ := http.NewRequest("GET", "/api/memos/on-this-day", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestMemoHandler_ListCategories(t *testing.T) {
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+		router.GET("/api/memos/categories", memoHandler.ListCategories)
+		return router
+	}
+
+	t.Run("returns categories", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ListCategories", mock.Anything).Return([]string{"personal", "work"}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/categories", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"categories":["personal","work"]}`, w.Body.String())
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ListCategories", mock.Anything).Return(nil, fmt.Errorf("database error"))
+
+		req, _ := http.NewRequest("GET", "/api/memos/categories", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestMemoHandler_NumericEnums(t *testing.T) {
+	memo := domain.Memo{ID: 5, Title: "Enum", Priority: domain.PriorityHigh, Status: domain.StatusArchived}
+
+	t.Run("default is strings", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemo", mock.Anything, 5).Return(&memo, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/5", nil)
+		w := httptest.NewRecorder()
+		setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "high", response["priority"])
+		assert.Equal(t, "archived", response["status"])
+	})
+
+	t.Run("numeric on a single memo", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemo", mock.Anything, 5).Return(&memo, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/5?enums=numeric", nil)
+		w := httptest.NewRecorder()
+		setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, float64(3), response["priority"])
+		assert.Equal(t, float64(2), response["status"])
+	})
+
+	t.Run("numeric on a list", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
+		mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{
+			{ID: 1, Priority: domain.PriorityLow, Status: domain.StatusActive},
+			{ID: 2, Priority: domain.PriorityMedium, Status: domain.StatusTrashed},
+		}, 2, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos?enums=numeric&tags=objects", nil)
+		w := httptest.NewRecorder()
+		setupTestRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Memos []struct {
+				Priority int `json:"priority"`
+				Status   int `json:"status"`
+			} `json:"memos"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Memos, 2)
+		assert.Equal(t, 1, response.Memos[0].Priority)
+		assert.Equal(t, 1, response.Memos[0].Status)
+		assert.Equal(t, 2, response.Memos[1].Priority)
+		assert.Equal(t, 3, response.Memos[1].Status)
+	})
+}
+
+func TestMemoHandler_ImportMemosWithIDs(t *testing.T) {
+	// 管理者トークンの検証は middleware のテストで確認する
+	newAdminRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.POST("/api/admin/memos/import-with-ids", handler.NewMemoHandler(mockUsecase, logrus.New()).ImportMemosWithIDs)
+		return r
+	}
+	body := `{"memos":[{"id":42,"title":"Imported","content":"Original content","tags":["legacy"]},{"id":7,"title":"Second","content":"More"}]}`
+
+	t.Run("imports with the given IDs", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ImportMemosWithIDs", mock.Anything, mock.AnythingOfType("[]usecase.ImportMemoRequest")).Return([]domain.Memo{
+			{ID: 42, Title: "Imported", Content: "Original content", Tags: []string{"legacy"}, Priority: domain.PriorityMedium, Status: domain.StatusActive},
+			{ID: 7, Title: "Second", Content: "More", Priority: domain.PriorityMedium, Status: domain.StatusActive},
+		}, nil)
+
+		req, _ := http.NewRequest("POST", "/api/admin/memos/import-with-ids", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newAdminRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var response handler.ImportMemosResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Memos, 2)
+		assert.Equal(t, 42, response.Memos[0].ID)
+
+		reqs := mockUsecase.Calls[0].Arguments.Get(1).([]usecase.ImportMemoRequest)
+		require.Len(t, reqs, 2)
+		assert.Equal(t, 42, reqs[0].ID)
+		assert.Equal(t, "Imported", reqs[0].Title)
+		assert.Equal(t, []string{"legacy"}, reqs[0].Tags)
+		assert.Equal(t, 7, reqs[1].ID)
+	})
+
+	t.Run("passes remind_at through", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ImportMemosWithIDs", mock.Anything, mock.AnythingOfType("[]usecase.ImportMemoRequest")).Return([]domain.Memo{
+			{ID: 42, Title: "Imported", Content: "Content", Priority: domain.PriorityMedium, Status: domain.StatusActive},
+		}, nil)
+
+		req, _ := http.NewRequest("POST", "/api/admin/memos/import-with-ids",
+			strings.NewReader(`{"memos":[{"id":42,"title":"Imported","content":"Content","remind_at":"2030-01-02T09:00:00Z"}]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newAdminRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		reqs := mockUsecase.Calls[0].Arguments.Get(1).([]usecase.ImportMemoRequest)
+		require.Len(t, reqs, 1)
+		require.NotNil(t, reqs[0].RemindAt)
+		assert.True(t, reqs[0].RemindAt.Equal(time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("missing ID is rejected", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+
+		req, _ := http.NewRequest("POST", "/api/admin/memos/import-with-ids", strings.NewReader(`{"memos":[{"title":"No ID","content":"Content"}]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newAdminRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUsecase.AssertNotCalled(t, "ImportMemosWithIDs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("taken ID returns 409", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ImportMemosWithIDs", mock.Anything, mock.Anything).Return(nil, usecase.ErrMemoIDConflict)
+
+		req, _ := http.NewRequest("POST", "/api/admin/memos/import-with-ids", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newAdminRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestNewMemoResponseDTO_TextStats(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedWords int
+		expectedChars int
+	}{
+		{name: "empty", content: "", expectedWords: 0, expectedChars: 0},
+		{name: "english words separated by spaces and punctuation", content: "Hello, world!  Don't forget e-mail.", expectedWords: 5, expectedChars: 35},
+		{name: "japanese counts each character", content: "今日はメモー", expectedWords: 6, expectedChars: 6},
+		{name: "mixed japanese and latin", content: "Goの勉強 2 回目", expectedWords: 7, expectedChars: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := handler.NewMemoResponseDTO(&domain.Memo{ID: 1, Content: tt.content}, false)
+			assert.Equal(t, tt.expectedWords, response.WordCount)
+			assert.Equal(t, tt.expectedChars, response.CharCount)
+		})
+	}
+}
+
+func TestMemoHandler_ExportMemos(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	due := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	memos := []domain.Memo{
+		{ID: 1, Title: "First", Content: "Hello, world", Category: "work", Tags: []string{"a", "b"}, Priority: domain.PriorityHigh, Status: domain.StatusActive, CreatedAt: created, UpdatedAt: created, DueDate: &due},
+		{ID: 2, Title: "Second", Content: "Line1\nLine2", Priority: domain.PriorityLow, Status: domain.StatusTrashed, CreatedAt: created, UpdatedAt: created},
+	}
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.GET("/api/memos/export", handler.NewMemoHandler(mockUsecase, logrus.New()).ExportMemos)
+		return r
+	}
+
+	t.Run("json is the default format", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ExportMemos", mock.Anything, mock.Anything).Return(memos, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/export", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.Regexp(t, `^attachment; filename="memos-\d{8}\.json"$`, w.Header().Get("Content-Disposition"))
+
+		var exported []handler.MemoResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &exported))
+		require.Len(t, exported, 2)
+		assert.Equal(t, 1, exported[0].ID)
+		assert.Equal(t, []string{"a", "b"}, exported[0].Tags)
+		assert.Equal(t, "trashed", exported[1].Status)
+	})
+
+	t.Run("csv writes a header and one row per memo", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ExportMemos", mock.Anything, mock.Anything).Return(memos, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/export?format=csv", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+		assert.Regexp(t, `^attachment; filename="memos-\d{8}\.csv"$`, w.Header().Get("Content-Disposition"))
+
+		records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{"id", "title", "content", "category", "tags", "priority", "status", "pinned",
+			"created_at", "updated_at", "completed_at", "trashed_at", "due_date"}, records[0])
+		assert.Equal(t, []string{"1", "First", "Hello, world", "work", "a;b", "high", "active", "false",
+			"2024-01-02T03:04:05Z", "2024-01-02T03:04:05Z", "", "", "2024-02-01T00:00:00Z"}, records[1])
+		assert.Equal(t, "Line1\nLine2", records[2][2])
+		assert.Equal(t, "", records[2][4])
+	})
+
+	t.Run("empty export is a valid document", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ExportMemos", mock.Anything, mock.Anything).Return([]domain.Memo{}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/export", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[]`, w.Body.String())
+	})
+
+	t.Run("unknown format is rejected", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+
+		req, _ := http.NewRequest("GET", "/api/memos/export?format=xml", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUsecase.AssertNotCalled(t, "ExportMemos", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error before any memo returns 500", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ExportMemos", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		req, _ := http.NewRequest("GET", "/api/memos/export", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+	})
+}
+
+func TestMemoHandler_ValidationErrorStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		configured     int
+		body           string
+		expectedStatus int
+	}{
+		{name: "default is 400", configured: 0, body: `{"title":"<script>alert(1)</script>","content":"x"}`, expectedStatus: http.StatusBadRequest},
+		{name: "400 when configured", configured: 400, body: `{"title":"<script>alert(1)</script>","content":"x"}`, expectedStatus: http.StatusBadRequest},
+		{name: "422 when configured", configured: 422, body: `{"title":"<script>alert(1)</script>","content":"x"}`, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "malformed JSON stays 400", configured: 422, body: `{"title":`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			mockUsecase := new(MockMemoUsecase)
+			memoHandler := handler.NewMemoHandlerWithConfig(mockUsecase, logrus.New(), &config.MemoConfig{ValidationErrorStatus: tt.configured})
+			router := gin.New()
+			router.POST("/api/memos", memoHandler.CreateMemo)
+
+			req, _ := http.NewRequest("POST", "/api/memos", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUsecase.AssertNotCalled(t, "CreateMemo", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMemoHandler_MaxTagsPerMemo(t *testing.T) {
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		memoHandler := handler.NewMemoHandlerWithConfig(mockUsecase, logrus.New(), &config.MemoConfig{MaxTagsPerMemo: 2})
+		r := gin.New()
+		r.POST("/api/memos", memoHandler.CreateMemo)
+		r.PUT("/api/memos/:id", memoHandler.UpdateMemo)
+		return r
+	}
+	send := func(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	assertTagsError := func(t *testing.T, w *httptest.ResponseRecorder) {
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response struct {
+			Errors []struct {
+				Field string `json:"field"`
+				Tag   string `json:"tag"`
+			} `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, "Tags", response.Errors[0].Field)
+		assert.Equal(t, "max_tags", response.Errors[0].Tag)
+	}
+
+	t.Run("create accepts tags up to the limit", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("CreateMemo", mock.Anything, mock.MatchedBy(func(req usecase.CreateMemoRequest) bool {
+			return len(req.Tags) == 2
+		})).Return(&domain.Memo{ID: 1, Title: "Title", Tags: []string{"a", "b"}, Status: domain.StatusActive}, nil)
+
+		w := send(newRouter(mockUsecase), "POST", "/api/memos", `{"title":"Title","content":"Content","tags":["a","b"]}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("create rejects more tags than the limit", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		w := send(newRouter(mockUsecase), "POST", "/api/memos", `{"title":"Title","content":"Content","tags":["a","b","c"]}`)
+		assertTagsError(t, w)
+		mockUsecase.AssertNotCalled(t, "CreateMemo", mock.Anything, mock.Anything)
+	})
+
+	t.Run("update rejects more tags than the limit", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		w := send(newRouter(mockUsecase), "PUT", "/api/memos/1", `{"tags":["a","b","c"]}`)
+		assertTagsError(t, w)
+		mockUsecase.AssertNotCalled(t, "UpdateMemo", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemoHandler_UpdateMemo_Version(t *testing.T) {
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.PUT("/api/memos/:id", handler.NewMemoHandler(mockUsecase, logrus.New()).UpdateMemo)
+		return r
+	}
+	versionIs := func(version int) interface{} {
+		return mock.MatchedBy(func(req usecase.UpdateMemoRequest) bool {
+			return req.Version != nil && *req.Version == version
+		})
+	}
+
+	t.Run("If-Match header sets the expected version", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("UpdateMemo", mock.Anything, 1, versionIs(3)).
+			Return(&domain.Memo{ID: 1, Title: "Updated", Status: domain.StatusActive, Version: 4}, nil)
+
+		req, _ := http.NewRequest("PUT", "/api/memos/1", strings.NewReader(`{"title":"Updated"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `W/"3"`)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp handler.MemoResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 4, resp.Version)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("body version takes precedence over If-Match", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("UpdateMemo", mock.Anything, 1, versionIs(5)).
+			Return(&domain.Memo{ID: 1, Title: "Updated", Status: domain.StatusActive, Version: 6}, nil)
+
+		req, _ := http.NewRequest("PUT", "/api/memos/1", strings.NewReader(`{"title":"Updated","version":5}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"2"`)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("conflict returns 409 with retry guidance", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("UpdateMemo", mock.Anything, 1, versionIs(2)).Return(nil, usecase.ErrVersionConflict)
+
+		req, _ := http.NewRequest("PUT", "/api/memos/1", strings.NewReader(`{"title":"Updated","version":2}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		var resp handler.ErrorResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, handler.ErrorCodeVersionConflict, resp.Code)
+		assert.Contains(t, resp.Message, "retry")
+	})
+
+	t.Run("invalid If-Match is rejected", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		req, _ := http.NewRequest("PUT", "/api/memos/1", strings.NewReader(`{"title":"Updated"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", "*")
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUsecase.AssertNotCalled(t, "UpdateMemo", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemoHandler_GetMemoStats(t *testing.T) {
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.GET("/api/memos/stats", handler.NewMemoHandler(mockUsecase, logrus.New()).GetMemoStats)
+		return r
+	}
+
+	t.Run("empty account returns zeroes instead of null", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoStats", mock.Anything).Return(domain.NewMemoStats(), nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/stats", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"total": 0,
+			"by_status": {"active": 0, "archived": 0, "trashed": 0},
+			"by_priority": {"low": 0, "medium": 0, "high": 0},
+			"by_category": {},
+			"created_last_7_days": 0,
+			"created_last_30_days": 0
+		}`, w.Body.String())
+	})
+
+	t.Run("returns counts", func(t *testing.T) {
+		stats := domain.NewMemoStats()
+		stats.Total = 3
+		stats.ByStatus[domain.StatusActive] = 2
+		stats.ByStatus[domain.StatusArchived] = 1
+		stats.ByPriority[domain.PriorityHigh] = 3
+		stats.ByCategory["work"] = 2
+		stats.CreatedLast7Days = 1
+		stats.CreatedLast30Days = 3
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoStats", mock.Anything).Return(stats, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/stats", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp handler.MemoStatsResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 3, resp.Total)
+		assert.Equal(t, 2, resp.ByStatus["active"])
+		assert.Equal(t, 3, resp.ByPriority["high"])
+		assert.Equal(t, map[string]int{"work": 2}, resp.ByCategory)
+		assert.Equal(t, 1, resp.CreatedLast7Days)
+	})
+
+	t.Run("usecase error returns 500", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoStats", mock.Anything).Return(nil, errors.New("db down"))
+
+		req, _ := http.NewRequest("GET", "/api/memos/stats", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestMemoHandler_UpdateMemoMetadata(t *testing.T) {
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.PATCH("/api/memos/:id/metadata", handler.NewMemoHandler(mockUsecase, logrus.New()).UpdateMemoMetadata)
+		return r
+	}
+
+	t.Run("passes only the provided metadata and ignores content", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("UpdateMemoMetadata", mock.Anything, 1, usecase.UpdateMemoMetadataRequest{
+			Tags:  []string{"work"},
+			Color: stringPtr("#ff8800"),
+		}).Return(&domain.Memo{ID: 1, Title: "Title", Content: "Original", Tags: []string{"work"}, Color: "#ff8800",
+			Priority: domain.PriorityMedium, Status: domain.StatusActive}, nil)
+
+		body := `{"tags":["work"],"color":"#ff8800","content":"overwritten","title":"overwritten"}`
+		req, _ := http.NewRequest("PATCH", "/api/memos/1/metadata", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp handler.MemoResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Original", resp.Content)
+		assert.Equal(t, "#ff8800", resp.Color)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("null due_date clears it while an omitted due_date is left unchanged", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("UpdateMemoMetadata", mock.Anything, 1, usecase.UpdateMemoMetadataRequest{ClearDueDate: true}).
+			Return(&domain.Memo{ID: 1, Priority: domain.PriorityMedium, Status: domain.StatusActive}, nil).Once()
+		mockUsecase.On("UpdateMemoMetadata", mock.Anything, 1, usecase.UpdateMemoMetadataRequest{Color: stringPtr("#ff8800")}).
+			Return(&domain.Memo{ID: 1, Priority: domain.PriorityMedium, Status: domain.StatusActive}, nil).Once()
+
+		for _, body := range []string{`{"due_date":null}`, `{"color":"#ff8800"}`} {
+			req, _ := http.NewRequest("PATCH", "/api/memos/1/metadata", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newRouter(mockUsecase).ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code, body)
+		}
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("maps usecase errors", func(t *testing.T) {
+		tests := []struct {
+			err    error
+			status int
+		}{
+			{usecase.ErrEmptyMetadataUpdate, http.StatusBadRequest},
+			{usecase.ErrInvalidColor, http.StatusBadRequest},
+			{usecase.ErrMemoNotFound, http.StatusNotFound},
+			{usecase.ErrMemoForbidden, http.StatusForbidden},
+			{errors.New("db down"), http.StatusInternalServerError},
+		}
+		for _, tt := range tests {
+			mockUsecase := new(MockMemoUsecase)
+			mockUsecase.On("UpdateMemoMetadata", mock.Anything, 1, mock.Anything).Return(nil, tt.err)
+
+			req, _ := http.NewRequest("PATCH", "/api/memos/1/metadata", strings.NewReader(`{"color":"red"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newRouter(mockUsecase).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, tt.err.Error())
+		}
+	})
+
+	t.Run("rejects invalid priority", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		req, _ := http.NewRequest("PATCH", "/api/memos/1/metadata", strings.NewReader(`{"priority":"urgent"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUsecase.AssertNotCalled(t, "UpdateMemoMetadata", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemoHandler_SingleMemoNotFoundAndForbidden(t *testing.T) {
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		r := setupTestRouter(mockUsecase)
+		memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+		r.DELETE("/api/memos/:id/permanent", memoHandler.PermanentDeleteMemo)
+		return r
+	}
+
+	operations := []struct {
+		name   string
+		method string
+		path   string
+		mock   func(m *MockMemoUsecase, err error)
+	}{
+		{"archive", "PATCH", "/api/memos/5/archive", func(m *MockMemoUsecase, err error) {
+			m.On("ArchiveMemo", mock.Anything, 5).Return(err)
+		}},
+		{"restore", "PATCH", "/api/memos/5/restore", func(m *MockMemoUsecase, err error) {
+			m.On("RestoreMemo", mock.Anything, 5).Return(err)
+		}},
+		{"permanent delete", "DELETE", "/api/memos/5/permanent", func(m *MockMemoUsecase, err error) {
+			m.On("PermanentDeleteMemo", mock.Anything, 5).Return(err)
+		}},
+		{"promote", "POST", "/api/memos/5/promote", func(m *MockMemoUsecase, err error) {
+			m.On("PromoteMemo", mock.Anything, 5).Return(nil, err)
+		}},
+		{"touch", "POST", "/api/memos/5/touch", func(m *MockMemoUsecase, err error) {
+			m.On("TouchMemo", mock.Anything, 5).Return(nil, err)
+		}},
+		{"pin", "PATCH", "/api/memos/5/pin", func(m *MockMemoUsecase, err error) {
+			m.On("PinMemo", mock.Anything, 5).Return(nil, err)
+		}},
+		{"unpin", "PATCH", "/api/memos/5/unpin", func(m *MockMemoUsecase, err error) {
+			m.On("UnpinMemo", mock.Anything, 5).Return(nil, err)
+		}},
+	}
+
+	for _, op := range operations {
+		for _, tt := range []struct {
+			err    error
+			status int
+		}{
+			{usecase.ErrMemoNotFound, http.StatusNotFound},
+			{usecase.ErrMemoForbidden, http.StatusForbidden},
+		} {
+			t.Run(op.name+"/"+tt.err.Error(), func(t *testing.T) {
+				mockUsecase := new(MockMemoUsecase)
+				op.mock(mockUsecase, tt.err)
+
+				req, _ := http.NewRequest(op.method, op.path, nil)
+				w := httptest.NewRecorder()
+				newRouter(mockUsecase).ServeHTTP(w, req)
+
+				assert.Equal(t, tt.status, w.Code, w.Body.String())
+				mockUsecase.AssertExpectations(t)
+			})
+		}
+	}
+}
+
+func TestMemoHandler_History(t *testing.T) {
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		memoHandler := handler.NewMemoHandler(mockUsecase, logrus.New())
+		r.GET("/api/memos/:id/history", memoHandler.GetMemoHistory)
+		r.POST("/api/memos/:id/revert/:revisionID", memoHandler.RevertMemo)
+		return r
+	}
+
+	t.Run("lists revisions", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ListMemoHistory", mock.Anything, 1).Return([]domain.MemoRevision{
+			{ID: 2, MemoID: 1, Title: "Second", Tags: []string{"go"}, Priority: domain.PriorityHigh},
+			{ID: 1, MemoID: 1, Title: "First", Priority: domain.PriorityLow},
+		}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/memos/1/history", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp handler.MemoHistoryResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Revisions, 2)
+		assert.Equal(t, 2, resp.Revisions[0].ID)
+		assert.Equal(t, "Second", resp.Revisions[0].Title)
+		assert.Equal(t, "high", resp.Revisions[0].Priority)
+	})
+
+	t.Run("returns 404 for unknown memos", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ListMemoHistory", mock.Anything, 99).Return(nil, usecase.ErrMemoNotFound)
+
+		req, _ := http.NewRequest("GET", "/api/memos/99/history", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("reverts to a revision", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("RevertMemo", mock.Anything, 1, 7).Return(&domain.Memo{ID: 1, Title: "Old", Priority: domain.PriorityLow,
+			Status: domain.StatusActive, Version: 4}, nil)
+
+		req, _ := http.NewRequest("POST", "/api/memos/1/revert/7", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp handler.MemoResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Old", resp.Title)
+		assert.Equal(t, 4, resp.Version)
+	})
+
+	t.Run("maps revert errors", func(t *testing.T) {
+		tests := []struct {
+			err    error
+			status int
+		}{
+			{usecase.ErrMemoNotFound, http.StatusNotFound},
+			{usecase.ErrRevisionNotFound, http.StatusNotFound},
+			{usecase.ErrVersionConflict, http.StatusConflict},
+			{errors.New("db down"), http.StatusInternalServerError},
+		}
+		for _, tt := range tests {
+			mockUsecase := new(MockMemoUsecase)
+			mockUsecase.On("RevertMemo", mock.Anything, 1, 7).Return(nil, tt.err)
+
+			req, _ := http.NewRequest("POST", "/api/memos/1/revert/7", nil)
+			w := httptest.NewRecorder()
+			newRouter(mockUsecase).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, tt.err.Error())
+		}
+	})
+
+	t.Run("rejects invalid revision IDs", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		req, _ := http.NewRequest("POST", "/api/memos/1/revert/abc", nil)
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUsecase.AssertNotCalled(t, "RevertMemo", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemoHandler_CreateMemo_BodyTooLarge(t *testing.T) {
+	mockUsecase := new(MockMemoUsecase)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	// MaxBodySizeMiddleware と同様にボディの読み込みを制限する（chunked の場合は読み込み時に超過が分かる）
+	r.Use(func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 1024)
+	})
+	r.POST("/api/memos", handler.NewMemoHandler(mockUsecase, logrus.New()).CreateMemo)
+
+	body := `{"title":"Title","content":"` + strings.Repeat("a", 2048) + `"}`
+	req, _ := http.NewRequest("POST", "/api/memos", strings.NewReader(body))
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var resp handler.ErrorResponseDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, handler.ErrorCodeRequestTooLarge, resp.Code)
+	mockUsecase.AssertNotCalled(t, "CreateMemo", mock.Anything, mock.Anything)
+}
+
+func TestMemoHandler_ImportMemos(t *testing.T) {
+	newRouter := func(mockUsecase *MockMemoUsecase) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.POST("/api/memos/import", handler.NewMemoHandler(mockUsecase, logrus.New()).ImportMemos)
+		return r
+	}
+	payload := `[
+		{"id": 7, "title": "First", "content": "Hello", "category": "work", "tags": ["a"], "priority": "high", "status": "trashed"},
+		{"id": 8, "title": "", "content": "No title"},
+		{"id": 9, "title": "Third", "content": "World"}
+	]`
+
+	t.Run("json body ignores ids and reports invalid memos by index", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ImportMemos", mock.Anything, []usecase.CreateMemoRequest{
+			{Title: "First", Content: "Hello", Category: "work", Tags: []string{"a"}, Priority: "high"},
+			{Title: "Third", Content: "World", Tags: []string{}},
+		}, false).Return(&usecase.ImportResult{
+			Imported: 1,
+			Failed:   []usecase.ImportFailure{{Index: 1, Err: errors.New("insert failed")}},
+		}, nil)
+
+		req, _ := http.NewRequest("POST", "/api/memos/import", strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var summary handler.ImportSummaryDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+		assert.Equal(t, 1, summary.Imported)
+		require.Len(t, summary.Failed, 2)
+		assert.Equal(t, 1, summary.Failed[0].Index)
+		// ユースケースでの失敗は元のペイロードの位置に戻される
+		assert.Equal(t, 2, summary.Failed[1].Index)
+		assert.Equal(t, "insert failed", summary.Failed[1].Error)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("multipart file upload", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ImportMemos", mock.Anything, mock.Anything, false).
+			Return(&usecase.ImportResult{Imported: 1, Failed: []usecase.ImportFailure{}}, nil)
+
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "memos-20240101.json")
+		require.NoError(t, err)
+		_, err = part.Write([]byte(`[{"title": "Uploaded", "content": "From file"}]`))
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		req, _ := http.NewRequest("POST", "/api/memos/import", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"imported": 1, "failed": []}`, w.Body.String())
+		mockUsecase.AssertCalled(t, "ImportMemos", mock.Anything,
+			[]usecase.CreateMemoRequest{{Title: "Uploaded", Content: "From file", Tags: []string{}}}, false)
+	})
+
+	t.Run("atomic rejects the whole import when a memo is invalid", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+
+		req, _ := http.NewRequest("POST", "/api/memos/import?atomic=true", strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newRouter(mockUsecase).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var summary handler.ImportSummaryDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+		assert.Equal(t, 0, summary.Imported)
+		require.Len(t, summary.Failed, 1)
+		assert.Equal(t, 1, summary.Failed[0].Index)
+		mockUsecase.AssertNotCalled(t, "ImportMemos", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects payloads that are not a memo array", func(t *testing.T) {
+		for _, body := range []string{`{"title": "x"}`, `[]`, `not json`} {
+			mockUsecase := new(MockMemoUsecase)
+			req, _ := http.NewRequest("POST", "/api/memos/import", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newRouter(mockUsecase).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+
+		req, _ := http.NewRequest("POST", "/api/memos/import?atomic=maybe", strings.NewReader(`[]`))
+		w := httptest.NewRecorder()
+		newRouter(new(MockMemoUsecase)).ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestMemoHandler_MemosCreatedHook(t *testing.T) {
+	mockUsecase := new(MockMemoUsecase)
+	mockUsecase.On("CreateMemo", mock.Anything, mock.AnythingOfType("usecase.CreateMemoRequest")).Return(&domain.Memo{
+		ID:       1,
+		Title:    "Test Memo",
+		Content:  "content",
+		Priority: domain.PriorityMedium,
+		Status:   domain.StatusActive,
+	}, nil).Once()
+
+	h := handler.NewMemoHandler(mockUsecase, logrus.New())
+	created := map[string]int{}
+	h.SetMemosCreatedHook(func(source string, count int) {
+		created[source] += count
+	})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/memos", h.CreateMemo)
+
+	send := func(body string) int {
+		req, _ := http.NewRequest("POST", "/api/memos", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusCreated, send(`{"title":"Test Memo","content":"content"}`))
+	// 作成に失敗したリクエストは数えない
+	require.Equal(t, http.StatusBadRequest, send(`invalid json`))
+
+	assert.Equal(t, map[string]int{handler.MemoSourceAPI: 1}, created)
+	mockUsecase.AssertExpectations(t)
+}
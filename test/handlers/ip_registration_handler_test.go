@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"memo-app/src/handlers"
+	"memo-app/src/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIPRegistrationResetter はリセットしたIPアドレスを記録する
+type fakeIPRegistrationResetter struct {
+	err   error
+	reset []string
+}
+
+func (r *fakeIPRegistrationResetter) ResetIPRegistration(ipAddress string) error {
+	r.reset = append(r.reset, ipAddress)
+	return r.err
+}
+
+func serveIPRegistrationReset(resetter handlers.IPRegistrationResetter, ip string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.SetupAdminRoutes(router, nil, nil, handlers.NewIPRegistrationHandler(resetter), "secret-admin-token")
+
+	req, _ := http.NewRequest("DELETE", "/api/admin/ip-registrations/"+ip, nil)
+	req.Header.Set("X-Admin-Token", "secret-admin-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIPRegistrationHandler_Reset(t *testing.T) {
+	t.Run("IPアドレスの登録数をリセット", func(t *testing.T) {
+		resetter := &fakeIPRegistrationResetter{}
+
+		w := serveIPRegistrationReset(resetter, "203.0.113.7")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"ip_address":"203.0.113.7"`)
+		assert.Equal(t, []string{"203.0.113.7"}, resetter.reset)
+	})
+
+	t.Run("IPv6アドレスは正規化してリセット", func(t *testing.T) {
+		resetter := &fakeIPRegistrationResetter{}
+
+		w := serveIPRegistrationReset(resetter, "2001:DB8:0:0::1")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"2001:db8::1"}, resetter.reset)
+	})
+
+	t.Run("不正なIPアドレスは400", func(t *testing.T) {
+		resetter := &fakeIPRegistrationResetter{}
+
+		w := serveIPRegistrationReset(resetter, "not-an-ip")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, resetter.reset)
+	})
+
+	t.Run("リセットに失敗した場合は500", func(t *testing.T) {
+		resetter := &fakeIPRegistrationResetter{err: errors.New("connection refused")}
+
+		w := serveIPRegistrationReset(resetter, "203.0.113.7")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
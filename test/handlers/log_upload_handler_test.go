@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"memo-app/src/handlers"
+	"memo-app/src/routes"
+	"memo-app/src/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLogUploadRunner は呼び出されたログディレクトリを記録し、設定された結果を返す
+type fakeLogUploadRunner struct {
+	summary storage.UploadSummary
+	err     error
+	logDirs []string
+}
+
+func (r *fakeLogUploadRunner) UploadAll(ctx context.Context, logDir string) (storage.UploadSummary, error) {
+	r.logDirs = append(r.logDirs, logDir)
+	return r.summary, r.err
+}
+
+func serveLogUpload(runner handlers.LogUploadRunner, adminToken string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.SetupAdminRoutes(router, nil, handlers.NewLogUploadHandler(runner, "logs"), nil, "secret-admin-token")
+
+	req, _ := http.NewRequest("POST", "/api/admin/logs/upload", nil)
+	if adminToken != "" {
+		req.Header.Set("X-Admin-Token", adminToken)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestLogUploadHandler(t *testing.T) {
+	t.Run("アップロードした件数を返す", func(t *testing.T) {
+		runner := &fakeLogUploadRunner{summary: storage.UploadSummary{Uploaded: 3, Kept: 1, Failed: 1}}
+
+		w := serveLogUpload(runner, "secret-admin-token")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response handlers.LogUploadResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, handlers.LogUploadResponse{Uploaded: 3, Kept: 1, Failed: 1}, response)
+		assert.Equal(t, []string{"logs"}, runner.logDirs)
+	})
+
+	t.Run("管理者トークンがない場合は403", func(t *testing.T) {
+		runner := &fakeLogUploadRunner{}
+
+		w := serveLogUpload(runner, "")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, runner.logDirs)
+	})
+
+	t.Run("アップローダーが無効な場合は503", func(t *testing.T) {
+		w := serveLogUpload(nil, "secret-admin-token")
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	t.Run("ログディレクトリを読み取れない場合は500", func(t *testing.T) {
+		runner := &fakeLogUploadRunner{err: errors.New("ログディレクトリの読み取りに失敗")}
+
+		w := serveLogUpload(runner, "secret-admin-token")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
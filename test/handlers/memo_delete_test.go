@@ -0,0 +1,83 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/infrastructure/repository"
+	"memo-app/src/interface/handler"
+	"memo-app/src/usecase"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupStagedDeleteRouter 実際のリポジトリとユースケースをsqlmockのDBで動かすルーター
+func setupStagedDeleteRouter(t *testing.T) (*gin.Engine, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	logger, _ := logtest.NewNullLogger()
+	repo := repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger)
+	memoHandler := handler.NewMemoHandler(usecase.NewMemoUsecase(repo), logger)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", 42)
+		c.Next()
+	})
+	r.DELETE("/api/memos/:id", memoHandler.DeleteMemo)
+	return r, mock
+}
+
+// DELETE /api/memos/:id は1段階ずつ進めるだけで、行を物理削除しない（物理削除は /permanent のみ）
+func TestMemoHandler_DeleteMemo_Staged(t *testing.T) {
+	memoColumns := []string{"id", "title", "content", "category", "tags", "priority", "status", "pinned",
+		"created_at", "updated_at", "completed_at", "trashed_at", "due_date", "remind_at", "reminded", "color", "version"}
+
+	t.Run("アクティブなメモはアーカイブされ、行は残る", func(t *testing.T) {
+		router, mock := setupStagedDeleteRouter(t)
+		mock.ExpectBegin()
+		// DELETE 文が発行されると sqlmock が期待外のクエリとして失敗させる
+		mock.ExpectExec(`UPDATE memos SET\s+status = CASE status WHEN 'active' THEN 'archived' ELSE 'trashed' END`).
+			WithArgs(7, sqlmock.AnyArg(), "trashed", 42).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/memos/7", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ゴミ箱のメモは409で、完全削除のエンドポイントを使う必要がある", func(t *testing.T) {
+		router, mock := setupStagedDeleteRouter(t)
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE memos SET`).WithArgs(7, sqlmock.AnyArg(), "trashed", 42).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT .* FROM memos WHERE id = \$1 AND user_id = \$2`).WithArgs(7, 42).
+			WillReturnRows(sqlmock.NewRows(memoColumns).
+				AddRow(7, "Title", "Content", "", `[]`, "medium", "trashed", false, now, now, now, now, nil, nil, false, nil, 3))
+		mock.ExpectRollback()
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/memos/7", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "/permanent")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
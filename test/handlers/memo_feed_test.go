@@ -0,0 +1,85 @@
+package handlers_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"memo-app/src/domain"
+	"memo-app/src/interface/handler"
+	"memo-app/src/routes"
+
+	"github.com/gin-gonic/gin"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupFeedRouter はメモAPIとフィードのルートを登録する（authはuserIDを設定するだけの認証）
+func setupFeedRouter(mockUsecase *MockMemoUsecase, userID int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger, _ := logtest.NewNullLogger()
+	memoHandler := handler.NewMemoHandler(mockUsecase, logger)
+
+	r := gin.New()
+	routes.SetupRoutes(r, memoHandler)
+	routes.SetupFeedRoutes(r, memoHandler, func(c *gin.Context) {
+		if userID > 0 {
+			c.Set("user_id", userID)
+		}
+	})
+	return r
+}
+
+func TestMemoHandler_GetMemoFeed(t *testing.T) {
+	updated := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+
+	t.Run("最近更新したアクティブなメモをAtomフィードで返す", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ListMemos", mock.Anything, domain.MemoFilter{
+			Status: domain.StatusActive,
+			Page:   1,
+			Limit:  handler.FeedEntryLimit,
+			Sort:   []domain.SortField{{Field: "updated_at", Desc: true}},
+		}).Return([]domain.Memo{
+			{ID: 2, Title: "Newest", Content: "Body <b>2</b>", Category: "Work", Tags: []string{"go"}, CreatedAt: updated.Add(-time.Hour), UpdatedAt: updated},
+			{ID: 1, Title: "Older", Content: "Body 1", CreatedAt: updated.Add(-2 * time.Hour), UpdatedAt: updated.Add(-time.Hour)},
+		}, 2, nil)
+
+		w := httptest.NewRecorder()
+		setupFeedRouter(mockUsecase, 42).ServeHTTP(w, httptest.NewRequest("GET", "/api/memos/feed.xml?api_key=memo_key", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, handler.AtomContentType, w.Header().Get("Content-Type"))
+
+		var feed struct {
+			XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+			ID      string   `xml:"id"`
+			Updated string   `xml:"updated"`
+			Entries []struct {
+				Title   string `xml:"title"`
+				Updated string `xml:"updated"`
+				Summary string `xml:"summary"`
+			} `xml:"entry"`
+		}
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &feed))
+		assert.Equal(t, "urn:memo-app:user:42:memos", feed.ID)
+		assert.Equal(t, "2024-06-30T09:00:00Z", feed.Updated)
+		require.Len(t, feed.Entries, 2)
+		assert.Equal(t, "Newest", feed.Entries[0].Title)
+		assert.Equal(t, "Body <b>2</b>", feed.Entries[0].Summary)
+		assert.Equal(t, "2024-06-30T09:00:00Z", feed.Entries[0].Updated)
+		assert.NotContains(t, w.Body.String(), "memo_key")
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("未認証は401", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		w := httptest.NewRecorder()
+		setupFeedRouter(mockUsecase, 0).ServeHTTP(w, httptest.NewRequest("GET", "/api/memos/feed.xml", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockUsecase.AssertNotCalled(t, "ListMemos", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,186 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+	"memo-app/src/infrastructure/repository"
+	"memo-app/src/interface/handler"
+	"memo-app/src/routes"
+	"memo-app/src/usecase"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupETagRouter はuserIDを設定してメモ一覧を返すルーターを作る
+func setupETagRouter(mockUsecase *MockMemoUsecase, userID int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger, _ := logtest.NewNullLogger()
+	memoHandler := handler.NewMemoHandler(mockUsecase, logger)
+
+	r := gin.New()
+	r.GET("/api/memos", func(c *gin.Context) {
+		if userID > 0 {
+			c.Set("user_id", userID)
+		}
+		memoHandler.ListMemos(c)
+	})
+	return r
+}
+
+func getMemoList(r *gin.Engine, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMemoHandler_ListMemos_ETag(t *testing.T) {
+	updated := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+	state := &domain.MemoListState{MaxUpdatedAt: updated, Count: 1}
+	memos := []domain.Memo{{ID: 1, Title: "Title", Content: "Content", CreatedAt: updated, UpdatedAt: updated}}
+
+	t.Run("ETagとCache-Controlを返し、一致するIf-None-Matchには304を返す", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(state, nil)
+		mockUsecase.On("ListMemos", mock.Anything, mock.Anything).Return(memos, 1, nil).Once()
+		r := setupETagRouter(mockUsecase, 42)
+
+		w := getMemoList(r, "/api/memos", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		etag := w.Header().Get("ETag")
+		assert.Regexp(t, `^W/"[0-9a-f]+"$`, etag)
+		assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+		w = getMemoList(r, "/api/memos", etag)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+
+		// 弱い比較のため W/ の有無や複数指定でも一致する
+		w = getMemoList(r, "/api/memos", `"other", `+etag[2:])
+		assert.Equal(t, http.StatusNotModified, w.Code)
+
+		// 304の間は一覧を取得しない
+		mockUsecase.AssertNumberOfCalls(t, "ListMemos", 1)
+	})
+
+	t.Run("メモが変わるとETagも変わり本文を返す", func(t *testing.T) {
+		changes := []*domain.MemoListState{
+			{MaxUpdatedAt: updated.Add(time.Second), Count: 1},
+			{MaxUpdatedAt: updated, Count: 0},
+			{MaxUpdatedAt: updated, Count: 1, PinnedIDs: "1"},
+			{MaxUpdatedAt: updated, Count: 1, RemindedCount: 1},
+		}
+
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(state, nil).Once()
+		mockUsecase.On("ListMemos", mock.Anything, mock.Anything).Return(memos, 1, nil)
+		r := setupETagRouter(mockUsecase, 42)
+		etag := getMemoList(r, "/api/memos", "").Header().Get("ETag")
+
+		for _, changed := range changes {
+			mockUsecase.On("GetMemoListState", mock.Anything).Return(changed, nil).Once()
+			w := getMemoList(r, "/api/memos", etag)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NotEqual(t, etag, w.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("クエリやユーザーが異なればETagも異なる", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(state, nil)
+		mockUsecase.On("ListMemos", mock.Anything, mock.Anything).Return(memos, 1, nil)
+
+		etag := getMemoList(setupETagRouter(mockUsecase, 42), "/api/memos?page=1&limit=10", "").Header().Get("ETag")
+		assert.Equal(t, etag, getMemoList(setupETagRouter(mockUsecase, 42), "/api/memos?limit=10&page=1", "").Header().Get("ETag"))
+		assert.NotEqual(t, etag, getMemoList(setupETagRouter(mockUsecase, 42), "/api/memos?page=2&limit=10", "").Header().Get("ETag"))
+		assert.NotEqual(t, etag, getMemoList(setupETagRouter(mockUsecase, 42), "/api/memos?page=1&limit=10&enums=numeric", "").Header().Get("ETag"))
+		assert.NotEqual(t, etag, getMemoList(setupETagRouter(mockUsecase, 7), "/api/memos?page=1&limit=10", "").Header().Get("ETag"))
+	})
+
+	t.Run("期限切れのフィルターは現在時刻で結果が変わるためETagを付与しない", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("ListMemos", mock.Anything, mock.Anything).Return(memos, 1, nil)
+
+		w := getMemoList(setupETagRouter(mockUsecase, 42), "/api/memos?overdue=true", "*")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+		mockUsecase.AssertNotCalled(t, "GetMemoListState", mock.Anything)
+	})
+
+	t.Run("更新状況を取得できない場合はETagなしで一覧を返す", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, errors.New("connection refused"))
+		mockUsecase.On("ListMemos", mock.Anything, mock.Anything).Return(memos, 1, nil)
+
+		w := getMemoList(setupETagRouter(mockUsecase, 42), "/api/memos", "*")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+		assert.Empty(t, w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("未認証の場合もETagを付与し、認証済みのユーザーとは区別する", func(t *testing.T) {
+		mockUsecase := new(MockMemoUsecase)
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(state, nil)
+		mockUsecase.On("ListMemos", mock.Anything, mock.Anything).Return(memos, 1, nil)
+
+		w := getMemoList(setupETagRouter(mockUsecase, 0), "/api/memos", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		etag := w.Header().Get("ETag")
+		assert.NotEmpty(t, etag)
+		assert.NotEqual(t, etag, getMemoList(setupETagRouter(mockUsecase, 42), "/api/memos", "").Header().Get("ETag"))
+	})
+}
+
+// 本番と同じルート設定（メモAPIは認証なし）でも、一覧のETagと304が機能する
+func TestMemoHandler_ListMemos_ETagWithRoutes(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	logger, _ := logtest.NewNullLogger()
+	repo := repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	routes.SetupRoutes(r, handler.NewMemoHandler(usecase.NewMemoUsecase(repo), logger))
+
+	updated := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+	stateColumns := []string{"max", "count", "pinned", "reminded"}
+	memoColumns := []string{"id", "title", "content", "category", "tags", "priority", "status", "pinned",
+		"created_at", "updated_at", "completed_at", "trashed_at", "due_date", "remind_at", "reminded", "color", "version"}
+
+	// 1回目: 更新状況（user_id の条件なし）と一覧を取得する
+	sqlMock.ExpectQuery(`SELECT\s+MAX\(updated_at\).*FROM memos\s+WHERE 1=1$`).WithoutArgs().
+		WillReturnRows(sqlmock.NewRows(stateColumns).AddRow(updated, 1, "", 0))
+	sqlMock.ExpectQuery(`SELECT COUNT\(\*\) FROM memos`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	sqlMock.ExpectQuery(`SELECT .* FROM memos`).
+		WillReturnRows(sqlmock.NewRows(memoColumns).
+			AddRow(1, "Title", "Content", "", `[]`, "medium", "active", false, updated, updated, nil, nil, nil, nil, false, nil, 1))
+	// 2回目: 更新状況が同じなので一覧は取得しない
+	sqlMock.ExpectQuery(`SELECT\s+MAX\(updated_at\).*FROM memos\s+WHERE 1=1$`).WithoutArgs().
+		WillReturnRows(sqlmock.NewRows(stateColumns).AddRow(updated, 1, "", 0))
+
+	w := getMemoList(r, "/api/memos", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	w = getMemoList(r, "/api/memos", etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
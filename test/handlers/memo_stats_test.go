@@ -0,0 +1,90 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"memo-app/src/database"
+	"memo-app/src/infrastructure/repository"
+	"memo-app/src/interface/handler"
+	"memo-app/src/usecase"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupStatsRouter 実際のリポジトリとユースケースをsqlmockのDBで動かすルーター（userIDが0なら未認証）
+func setupStatsRouter(t *testing.T, userID int) (*gin.Engine, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	logger, _ := logtest.NewNullLogger()
+	repo := repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger)
+	memoHandler := handler.NewMemoHandler(usecase.NewMemoUsecase(repo), logger)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if userID != 0 {
+		r.Use(func(c *gin.Context) {
+			c.Set("user_id", userID)
+			c.Next()
+		})
+	}
+	r.GET("/api/memos/stats", memoHandler.GetMemoStats)
+	return r, mock
+}
+
+// 統計は ListTags と同じく、認証済みならユーザーのメモ、未認証ならすべてのメモを数える
+func TestMemoHandler_GetMemoStats_UserScope(t *testing.T) {
+	countColumns := []string{"active", "archived", "trashed", "low", "medium", "high", "last7", "last30"}
+
+	t.Run("未認証の場合はすべてのメモを数える", func(t *testing.T) {
+		router, mock := setupStatsRouter(t, 0)
+		mock.ExpectQuery(`FROM memos\s+WHERE 1=1$`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(countColumns).AddRow(3, 1, 2, 0, 4, 0, 1, 4))
+		mock.ExpectQuery(`SELECT .* AS category, COUNT\(\*\) FROM memos\s+WHERE status <> 'trashed' AND .* <> ''\s+GROUP BY 1`).
+			WithoutArgs().
+			WillReturnRows(sqlmock.NewRows([]string{"category", "count"}).AddRow("work", 4))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/memos/stats", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp handler.MemoStatsResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 4, resp.Total)
+		assert.Equal(t, 2, resp.ByStatus["trashed"])
+		assert.Equal(t, map[string]int{"work": 4}, resp.ByCategory)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("認証済みの場合はユーザーのメモだけを数える", func(t *testing.T) {
+		router, mock := setupStatsRouter(t, 42)
+		mock.ExpectQuery(`FROM memos\s+WHERE 1=1 AND user_id = \$3`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 42).
+			WillReturnRows(sqlmock.NewRows(countColumns).AddRow(1, 0, 0, 0, 1, 0, 1, 1))
+		mock.ExpectQuery(`SELECT .* AS category, COUNT\(\*\) FROM memos\s+WHERE .* AND user_id = \$1\s+GROUP BY 1`).
+			WithArgs(42).
+			WillReturnRows(sqlmock.NewRows([]string{"category", "count"}))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/memos/stats", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp handler.MemoStatsResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.Total)
+		assert.Equal(t, map[string]int{}, resp.ByCategory)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
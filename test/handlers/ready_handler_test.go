@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/handlers"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveReady(t *testing.T, h *handlers.ReadyHandler) (int, handlers.ReadyResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ready", h.Ready)
+
+	req, _ := http.NewRequest("GET", "/ready", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response handlers.ReadyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w.Code, response
+}
+
+func TestReadyHandler(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	t.Run("すべての依存先に接続できる場合は200", func(t *testing.T) {
+		h := handlers.NewReadyHandler(time.Second)
+		h.AddCheck("database", up)
+		h.AddOptionalCheck("log_uploader", up)
+
+		code, response := serveReady(t, h)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ready", response.Status)
+		assert.Equal(t, handlers.ComponentStatus{Status: "up", Required: true}, response.Components["database"])
+		assert.Equal(t, handlers.ComponentStatus{Status: "up"}, response.Components["log_uploader"])
+	})
+
+	t.Run("DBに接続できない場合は503と失敗した依存先を返す", func(t *testing.T) {
+		h := handlers.NewReadyHandler(time.Second)
+		h.AddCheck("database", down)
+
+		code, response := serveReady(t, h)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "not_ready", response.Status)
+		assert.Equal(t, "down", response.Components["database"].Status)
+		assert.Equal(t, "connection refused", response.Components["database"].Error)
+	})
+
+	t.Run("任意の依存先の失敗は報告のみで200", func(t *testing.T) {
+		h := handlers.NewReadyHandler(time.Second)
+		h.AddCheck("database", up)
+		h.AddOptionalCheck("log_uploader", down)
+
+		code, response := serveReady(t, h)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ready", response.Status)
+		assert.Equal(t, "down", response.Components["log_uploader"].Status)
+	})
+
+	t.Run("応答しない依存先はタイムアウトで失敗にする", func(t *testing.T) {
+		h := handlers.NewReadyHandler(20 * time.Millisecond)
+		h.AddCheck("database", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		start := time.Now()
+		code, response := serveReady(t, h)
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, context.DeadlineExceeded.Error(), response.Components["database"].Error)
+	})
+	t.Run("DBの接続プールの統計を含める", func(t *testing.T) {
+		sqlDB, _, err := sqlmock.New()
+		require.NoError(t, err)
+		defer sqlDB.Close()
+		sqlDB.SetMaxOpenConns(7)
+		db := &database.DB{DB: sqlDB}
+
+		h := handlers.NewReadyHandler(time.Second)
+		h.AddCheck("database", up)
+		h.AddStats("database", func() interface{} { return db.PoolStats() })
+
+		code, response := serveReady(t, h)
+
+		assert.Equal(t, http.StatusOK, code)
+		stats, ok := response.Stats["database"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, float64(7), stats["max_open_connections"])
+		assert.Contains(t, stats, "in_use")
+		assert.Contains(t, stats, "idle")
+		assert.Contains(t, stats, "wait_count")
+	})
+
+	t.Run("統計を追加しない場合はstatsを含めない", func(t *testing.T) {
+		h := handlers.NewReadyHandler(time.Second)
+		h.AddCheck("database", up)
+
+		_, response := serveReady(t, h)
+
+		assert.Nil(t, response.Stats)
+	})
+}
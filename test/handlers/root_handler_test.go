@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"memo-app/src/buildinfo"
+	"memo-app/src/handlers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	build := buildinfo.Info{Commit: "abc1234", BuildTime: "2024-06-30T09:00:00Z", GoVersion: "go1.22.0"}
+	h := handlers.NewRootHandler(build)
+	router := gin.New()
+	router.GET("/", h.Root)
+	router.GET("/version", h.Version)
+
+	t.Run("ルートは固定のバージョンを返さず、ビルド情報を返す", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"message": "Hello World",
+			"service": "memo-app-api-server",
+			"build": {"commit": "abc1234", "build_time": "2024-06-30T09:00:00Z", "go_version": "go1.22.0"}
+		}`, w.Body.String())
+	})
+
+	t.Run("バージョンはビルド情報そのもの", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/version", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"commit": "abc1234", "build_time": "2024-06-30T09:00:00Z", "go_version": "go1.22.0"}`, w.Body.String())
+	})
+}
@@ -0,0 +1,95 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"memo-app/src/domain"
+	"memo-app/src/interface/handler"
+	"memo-app/src/usecase"
+
+	"github.com/gin-gonic/gin"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryWebhookRepository はユーザーごとにWebhookを保持するインメモリのリポジトリ
+type memoryWebhookRepository struct {
+	domain.WebhookRepository
+	webhooks []domain.Webhook
+}
+
+func (r *memoryWebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	created := *webhook
+	created.ID = len(r.webhooks) + 1
+	created.UserID, _ = domain.UserIDFromContext(ctx)
+	r.webhooks = append(r.webhooks, created)
+	return &created, nil
+}
+
+func (r *memoryWebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	userID, _ := domain.UserIDFromContext(ctx)
+	webhooks := []domain.Webhook{}
+	for _, w := range r.webhooks {
+		if w.UserID == userID {
+			webhooks = append(webhooks, w)
+		}
+	}
+	return webhooks, nil
+}
+
+func setupWebhookRouter(userID int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger, _ := logtest.NewNullLogger()
+	h := handler.NewWebhookHandler(usecase.NewWebhookUsecase(&memoryWebhookRepository{}), logger)
+
+	r := gin.New()
+	if userID > 0 {
+		r.Use(func(c *gin.Context) { c.Set("user_id", userID) })
+	}
+	r.POST("/api/webhooks", h.CreateWebhook)
+	r.GET("/api/webhooks", h.ListWebhooks)
+	return r
+}
+
+func TestWebhookHandler(t *testing.T) {
+	t.Run("作成時のみシークレットを返す", func(t *testing.T) {
+		r := setupWebhookRouter(42)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/webhooks",
+			strings.NewReader(`{"url":"https://hooks.example.com","events":["memo.created"]}`)))
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var created handler.WebhookResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.Equal(t, []string{domain.MemoEventCreated}, created.Events)
+		assert.Len(t, created.Secret, 64)
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/webhooks", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var list handler.WebhookListResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.Len(t, list.Webhooks, 1)
+		assert.Empty(t, list.Webhooks[0].Secret)
+	})
+
+	t.Run("不正なURLは400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		setupWebhookRouter(42).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/webhooks", strings.NewReader(`{"url":"hooks.example.com"}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("未認証は401", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		setupWebhookRouter(0).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/webhooks", strings.NewReader(`{"url":"https://hooks.example.com"}`)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
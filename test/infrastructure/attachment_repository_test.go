@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+	"memo-app/src/infrastructure/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAttachmentRepository(t *testing.T) (domain.AttachmentRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	logger, _ := logtest.NewNullLogger()
+	return repository.NewAttachmentRepository(&database.DB{DB: sqlDB}, logger), mock
+}
+
+func TestAttachmentRepository_Create(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	attachment := &domain.MemoAttachment{MemoID: 7, Filename: "photo.png", ContentType: "image/png", Size: 1024, ObjectKey: "attachments/42/7/abc"}
+
+	t.Run("自分のメモにだけ登録する", func(t *testing.T) {
+		repo, mock := newAttachmentRepository(t)
+		mock.ExpectQuery(`INSERT INTO memo_attachments .* SELECT id, \$3, \$4, \$5, \$6, \$7 FROM \(SELECT id FROM memos WHERE id = \$1 AND user_id = \$2\) AS owned`).
+			WithArgs(7, 42, "photo.png", "image/png", int64(1024), "attachments/42/7/abc", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+		created, err := repo.Create(ctx, attachment)
+		require.NoError(t, err)
+		assert.Equal(t, 3, created.ID)
+		assert.Equal(t, "photo.png", created.Filename)
+		assert.False(t, created.CreatedAt.IsZero())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("他のユーザーのメモは見つからない", func(t *testing.T) {
+		repo, mock := newAttachmentRepository(t)
+		mock.ExpectQuery(`INSERT INTO memo_attachments`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.Create(ctx, attachment)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "memo not found")
+	})
+
+	t.Run("DBエラーを返す", func(t *testing.T) {
+		repo, mock := newAttachmentRepository(t)
+		mock.ExpectQuery(`INSERT INTO memo_attachments`).WillReturnError(errors.New("connection reset"))
+
+		_, err := repo.Create(ctx, attachment)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create memo attachment")
+	})
+}
+
+func TestAttachmentRepository_GetByID(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	columns := []string{"id", "memo_id", "filename", "content_type", "size", "object_key", "created_at"}
+
+	t.Run("メモとユーザーで絞り込んで取得する", func(t *testing.T) {
+		repo, mock := newAttachmentRepository(t)
+		now := time.Now()
+		mock.ExpectQuery(`FROM memo_attachments WHERE memo_id = \(SELECT id FROM memos WHERE id = \$1 AND user_id = \$2\) AND id = \$3`).
+			WithArgs(7, 42, 3).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(3, 7, "doc.pdf", "application/pdf", 2048, "attachments/42/7/def", now))
+
+		attachment, err := repo.GetByID(ctx, 7, 3)
+		require.NoError(t, err)
+		assert.Equal(t, "doc.pdf", attachment.Filename)
+		assert.Equal(t, int64(2048), attachment.Size)
+		assert.Equal(t, "attachments/42/7/def", attachment.ObjectKey)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("見つからない場合", func(t *testing.T) {
+		repo, mock := newAttachmentRepository(t)
+		mock.ExpectQuery(`FROM memo_attachments`).WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := repo.GetByID(ctx, 7, 3)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "attachment not found")
+	})
+}
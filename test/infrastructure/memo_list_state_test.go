@@ -0,0 +1,225 @@
+package repository_test
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+	"memo-app/src/infrastructure/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoRepository_MaxUpdatedAt(t *testing.T) {
+	newRepo := func(t *testing.T) (domain.MemoRepository, sqlmock.Sqlmock) {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+		logger, _ := logtest.NewNullLogger()
+		return repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger), mock
+	}
+	columns := []string{"max", "count", "pinned", "reminded"}
+
+	t.Run("ユーザーのメモ全体を1回のクエリで集計する", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		updated := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT\s+MAX\(updated_at\),\s+COUNT\(\*\),.*FILTER \(WHERE pinned\).*FROM memos\s+WHERE 1=1 AND user_id = \$1`).
+			WithArgs(42).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(updated, 3, "1,5", 1))
+
+		state, err := repo.MaxUpdatedAt(domain.WithUserID(context.Background(), 42))
+		require.NoError(t, err)
+		assert.Equal(t, &domain.MemoListState{MaxUpdatedAt: updated, Count: 3, PinnedIDs: "1,5", RemindedCount: 1}, state)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("未認証の場合はすべてのメモを集計する", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		updated := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`FROM memos\s+WHERE 1=1$`).
+			WithoutArgs().
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(updated, 5, "", 0))
+
+		state, err := repo.MaxUpdatedAt(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, &domain.MemoListState{MaxUpdatedAt: updated, Count: 5}, state)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("メモがない場合はゼロ値を返す", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(`FROM memos`).WithArgs(42).WillReturnRows(sqlmock.NewRows(columns).AddRow(nil, 0, "", 0))
+
+		state, err := repo.MaxUpdatedAt(domain.WithUserID(context.Background(), 42))
+		require.NoError(t, err)
+		assert.Equal(t, &domain.MemoListState{}, state)
+	})
+}
+
+// カーソルページングもオフセットページングと同じくピン留めしたメモを先頭に並べる
+func TestMemoRepository_ListCursorPinnedFirst(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	logger, _ := logtest.NewNullLogger()
+	repo := repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger)
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM memos`).WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`AND \(pinned, created_at, id\) < \(\$2, \$3, \$4\) ORDER BY pinned DESC, created_at DESC, id DESC LIMIT \$5`).
+		WithArgs(42, true, createdAt, 8, 2).
+		WillReturnRows(sqlmock.NewRows(memoRowColumns).
+			AddRow(5, "Title", "Content", "", `[]`, "medium", "active", false, createdAt, createdAt, nil, nil, nil, nil, false, nil, 1))
+
+	memos, total, err := repo.List(ctx, domain.MemoFilter{Limit: 2, Cursor: &domain.MemoCursor{Pinned: true, CreatedAt: createdAt, ID: 8}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, memos, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// オフセットページングの既定の並び順はカーソルページングと同じで、更新順は sort で指定する
+func TestMemoRepository_ListDefaultOrder(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		sort  []domain.SortField
+		order string
+	}{
+		{"既定ではピン留め・作成日時の順", nil, `ORDER BY pinned DESC, created_at DESC, id DESC LIMIT`},
+		{"sort=-updated_at で更新日時の順", []domain.SortField{{Field: "updated_at", Desc: true}}, `ORDER BY updated_at DESC, id DESC LIMIT`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			t.Cleanup(func() { sqlDB.Close() })
+			logger, _ := logtest.NewNullLogger()
+			repo := repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger)
+
+			mock.ExpectQuery(`SELECT COUNT\(\*\) FROM memos`).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			mock.ExpectQuery(regexp.QuoteMeta(tt.order)).
+				WillReturnRows(sqlmock.NewRows(memoRowColumns).
+					AddRow(5, "Title", "Content", "", `[]`, "medium", "active", false, now, now, nil, nil, nil, nil, false, nil, 1))
+
+			_, _, err = repo.List(ctx, domain.MemoFilter{Page: 1, Limit: 10, Sort: tt.sort})
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// タグ一覧はゴミ箱のメモを数えず、min_count も表示する件数と同じくメモ単位で判定する
+func TestMemoRepository_ListTagsQuery(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	logger, _ := logtest.NewNullLogger()
+	repo := repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger)
+
+	mock.ExpectQuery(`WHERE tag <> '' AND status <> 'trashed' AND user_id = \$1\s+GROUP BY tag\s+HAVING COUNT\(DISTINCT id\) >= \$2`).
+		WithArgs(42, 2, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"tag", "count"}).AddRow("golang", 3))
+
+	tags, err := repo.ListTags(ctx, domain.TagFilter{MinCount: 2, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.TagCount{{Tag: "golang", Count: 3}}, tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// カテゴリー一覧は ListTags と同じく、認証済みならユーザーのメモ、未認証ならすべてのメモを対象にする
+func TestMemoRepository_ListCategoriesUserScope(t *testing.T) {
+	newRepo := func(t *testing.T) (domain.MemoRepository, sqlmock.Sqlmock) {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+		logger, _ := logtest.NewNullLogger()
+		return repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger), mock
+	}
+
+	t.Run("認証済みの場合はユーザーのメモに限定する", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(`SELECT DISTINCT .* AS category FROM memos\s+WHERE .* <> '' AND user_id = \$1\s+ORDER BY category`).
+			WithArgs(42).
+			WillReturnRows(sqlmock.NewRows([]string{"category"}).AddRow("work"))
+
+		categories, err := repo.ListCategories(domain.WithUserID(context.Background(), 42))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"work"}, categories)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("未認証の場合はすべてのメモを対象にする", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(`SELECT DISTINCT .* AS category FROM memos\s+WHERE .* <> ''\s+ORDER BY category`).
+			WithoutArgs().
+			WillReturnRows(sqlmock.NewRows([]string{"category"}).AddRow("personal").AddRow("work"))
+
+		categories, err := repo.ListCategories(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"personal", "work"}, categories)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// 日本語を含む検索は 'simple' の全文検索では語の一部に一致しないため、部分一致で検索する
+func TestMemoRepository_SearchJapaneseSubstring(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	logger, _ := logtest.NewNullLogger()
+	repo := repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM memos .*AND \(title ILIKE \$2 OR content ILIKE \$2\)`).
+		WithArgs(42, "%議事録%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT .* FROM memos .*AND \(title ILIKE \$2 OR content ILIKE \$2\)`).
+		WithArgs(42, "%議事録%", 10, 0).
+		WillReturnRows(sqlmock.NewRows(memoRowColumns).
+			AddRow(7, "週次定例会議の議事録", "Content", "", `[]`, "medium", "active", false, now, now, nil, nil, nil, nil, false, nil, 1))
+
+	memos, total, err := repo.Search(ctx, "議事録", domain.MemoFilter{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, memos, 1)
+	assert.Nil(t, memos[0].SearchRank)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 検索履歴は200文字までに切り詰め、マルチバイト文字を途中で切らない
+func TestMemoRepository_RecordSearchQueryTruncatesByRune(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	logger, _ := logtest.NewNullLogger()
+	repo := repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger)
+
+	// 1文字3バイトのため、バイト単位で切ると200バイト目で文字が壊れる
+	query := strings.Repeat("議", 250)
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO search_queries`).
+		WithArgs(42, strings.Repeat("議", 200), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`DELETE FROM search_queries`).WithArgs(42, 10).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.RecordSearchQuery(ctx, query, 10))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
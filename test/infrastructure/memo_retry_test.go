@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+	"memo-app/src/infrastructure/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetryingMemoRepository(t *testing.T) (domain.MemoRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	logger, _ := logtest.NewNullLogger()
+
+	db := &database.DB{DB: sqlDB, ReadRetry: database.RetryConfig{MaxRetries: 2, Backoff: time.Millisecond}}
+	return repository.NewMemoRepository(db, logger), mock
+}
+
+func TestMemoRepository_ReadRetry(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	now := time.Now()
+	failover := &pq.Error{Code: "08006"}
+
+	t.Run("GetByIDは一時的なエラーを再試行する", func(t *testing.T) {
+		repo, mock := newRetryingMemoRepository(t)
+		mock.ExpectQuery(`SELECT .* FROM memos WHERE id = \$1`).WithArgs(7, 42).WillReturnError(&pq.Error{Code: "40001"})
+		mock.ExpectQuery(`SELECT .* FROM memos WHERE id = \$1`).WithArgs(7, 42).
+			WillReturnRows(sqlmock.NewRows(memoRowColumns).
+				AddRow(7, "Title", "Content", "", `[]`, "medium", "active", false, now, now, nil, nil, nil, nil, false, nil, 1))
+
+		memo, err := repo.GetByID(ctx, 7)
+		require.NoError(t, err)
+		assert.Equal(t, "Title", memo.Title)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Listは件数の取得からやり直す", func(t *testing.T) {
+		repo, mock := newRetryingMemoRepository(t)
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM memos`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`SELECT .* FROM memos`).WillReturnError(failover)
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM memos`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`SELECT .* FROM memos`).
+			WillReturnRows(sqlmock.NewRows(memoRowColumns).
+				AddRow(7, "Title", "Content", "", `[]`, "medium", "active", false, now, now, nil, nil, nil, nil, false, nil, 1))
+
+		memos, total, err := repo.List(ctx, domain.MemoFilter{Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Len(t, memos, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("再試行回数を使い切るとエラーを返す", func(t *testing.T) {
+		repo, mock := newRetryingMemoRepository(t)
+		for i := 0; i < 3; i++ {
+			mock.ExpectQuery(`SELECT .* FROM memos WHERE id = \$1`).WithArgs(7, 42).WillReturnError(failover)
+		}
+
+		_, err := repo.GetByID(ctx, 7)
+		require.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("一時的でないエラーは再試行しない", func(t *testing.T) {
+		repo, mock := newRetryingMemoRepository(t)
+		mock.ExpectQuery(`SELECT .* FROM memos WHERE id = \$1`).WithArgs(7, 42).WillReturnError(&pq.Error{Code: "42601"})
+
+		_, err := repo.GetByID(ctx, 7)
+		require.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("書き込みは再試行しない", func(t *testing.T) {
+		repo, mock := newRetryingMemoRepository(t)
+		mock.ExpectExec(`UPDATE memos SET .* WHERE id = \$1`).WithArgs(7, sqlmock.AnyArg(), "trashed", 42).WillReturnError(failover)
+
+		err := repo.Delete(ctx, 7)
+		require.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,176 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+	"memo-app/src/infrastructure/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var memoRowColumns = []string{"id", "title", "content", "category", "tags", "priority", "status", "pinned",
+	"created_at", "updated_at", "completed_at", "trashed_at", "due_date", "remind_at", "reminded", "color", "version"}
+
+func newTxMemoRepository(t *testing.T) (domain.MemoTransactor, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	logger, _ := logtest.NewNullLogger()
+
+	repo, ok := repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger).(domain.MemoTransactor)
+	require.True(t, ok)
+	return repo, mock
+}
+
+func TestMemoRepository_WithinTx(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	now := time.Now()
+
+	t.Run("ロックして読み取った後の削除を同じトランザクションでコミットする", func(t *testing.T) {
+		repo, mock := newTxMemoRepository(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT .* FROM memos WHERE id = \$1 AND user_id = \$2 FOR UPDATE`).WithArgs(7, 42).
+			WillReturnRows(sqlmock.NewRows(memoRowColumns).
+				AddRow(7, "Title", "Content", "", `[]`, "medium", "active", false, now, now, nil, nil, nil, nil, false, nil, 1))
+		mock.ExpectExec(`UPDATE memos SET .* WHERE id = \$1 AND status <> \$3 AND user_id = \$4`).WithArgs(7, sqlmock.AnyArg(), "trashed", 42).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.WithinTx(ctx, func(tx domain.MemoTx) error {
+			memo, err := tx.GetByIDForUpdate(ctx, 7)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, "Title", memo.Title)
+			return tx.Delete(ctx, 7)
+		})
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("エラーの場合はロールバックしてエラーをそのまま返す", func(t *testing.T) {
+		repo, mock := newTxMemoRepository(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).WithArgs(7, 42).WillReturnRows(sqlmock.NewRows(memoRowColumns))
+		mock.ExpectRollback()
+
+		err := repo.WithinTx(ctx, func(tx domain.MemoTx) error {
+			if _, err := tx.GetByIDForUpdate(ctx, 7); err != nil {
+				return err
+			}
+			return tx.Delete(ctx, 7)
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "memo not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("独自にトランザクションを使う操作は外側のトランザクションに参加する", func(t *testing.T) {
+		repo, mock := newTxMemoRepository(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE memos SET .* WHERE id = \$1 AND status <> \$3`).WithArgs(1, sqlmock.AnyArg(), "trashed", 42).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE memos SET .* WHERE id = \$1 AND status <> \$3`).WithArgs(2, sqlmock.AnyArg(), "trashed", 42).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE memos SET`).WithArgs(3, nil, "archived", sqlmock.AnyArg(), 42).WillReturnError(errors.New("deadlock detected"))
+		mock.ExpectRollback()
+
+		err := repo.WithinTx(ctx, func(tx domain.MemoTx) error {
+			if _, err := tx.DeleteMany(ctx, []int{1, 2}); err != nil {
+				return err
+			}
+			status := domain.StatusArchived
+			_, err := tx.BulkUpdate(ctx, []int{3}, domain.MemoBulkUpdate{Status: &status})
+			return err
+		})
+		require.Error(t, err)
+		// 先に成功した一括削除も含めてロールバックされる
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("パニックした場合もロールバックする", func(t *testing.T) {
+		repo, mock := newTxMemoRepository(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.Panics(t, func() {
+			repo.WithinTx(ctx, func(tx domain.MemoTx) error {
+				panic("boom")
+			})
+		})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// 一括作成は Create と同じ列（remind_at を含む）を1つのトランザクションで保存する
+func TestMemoRepository_CreateManyKeepsRemindAt(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	repo, mock := newTxMemoRepository(t)
+	remindAt := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO memos \(.*due_date, remind_at\)`).
+		WithArgs("Title", "Content", "", `["go"]`, "medium", "active", sqlmock.AnyArg(), sqlmock.AnyArg(), 42, nil, remindAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectCommit()
+
+	created, err := repo.(domain.MemoRepository).CreateMany(ctx, []domain.Memo{
+		{Title: "Title", Content: "Content", Tags: []string{"go"}, Priority: domain.PriorityMedium, RemindAt: &remindAt},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, 11, created[0].ID)
+	assert.Equal(t, &remindAt, created[0].RemindAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// ID指定のインポートも Create と同じく remind_at を保存する
+func TestMemoRepository_CreateWithIDsKeepsRemindAt(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	repo, mock := newTxMemoRepository(t)
+	remindAt := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO memos \(id, .*due_date, remind_at\)`).
+		WithArgs(7, "Title", "Content", "", `["go"]`, "medium", "active", sqlmock.AnyArg(), sqlmock.AnyArg(), 42, nil, remindAt).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec(`SELECT setval`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	created, err := repo.(domain.MemoRepository).CreateWithIDs(ctx, []domain.Memo{
+		{ID: 7, Title: "Title", Content: "Content", Tags: []string{"go"}, Priority: domain.PriorityMedium, RemindAt: &remindAt},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, &remindAt, created[0].RemindAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// メタデータの更新は Update と同じく変更前の内容を同じトランザクションで履歴に残し、期限は明示した場合だけ変更する
+func TestMemoRepository_UpdateMetadataRecordsRevision(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	repo, mock := newTxMemoRepository(t)
+	now := time.Now()
+	high := domain.PriorityHigh
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO memo_revisions .* SELECT id, title, content, category, tags, priority, \$5 FROM memos`).
+		WithArgs(7, nil, nil, "high", sqlmock.AnyArg(), 42).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`UPDATE memos SET .*due_date = CASE WHEN \$6 THEN \$7::timestamptz ELSE due_date END`).
+		WithArgs(7, nil, nil, "high", nil, true, nil, sqlmock.AnyArg(), 42).
+		WillReturnRows(sqlmock.NewRows(memoRowColumns).
+			AddRow(7, "Title", "Content", "", `[]`, "high", "active", false, now, now, nil, nil, nil, nil, false, nil, 2))
+	mock.ExpectCommit()
+
+	memo, err := repo.(domain.MemoRepository).UpdateMetadata(ctx, 7, domain.MemoMetadataUpdate{Priority: &high, DueDateSet: true})
+	require.NoError(t, err)
+	assert.Nil(t, memo.DueDate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
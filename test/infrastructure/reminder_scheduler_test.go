@@ -0,0 +1,162 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+	"memo-app/src/infrastructure/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reminderStore は期限の来たリマインダーを返し、通知済みの記録を保持するリポジトリ
+type reminderStore struct {
+	mu        sync.Mutex
+	reminders []domain.MemoReminder
+	marked    []int
+	listErr   error
+}
+
+func (s *reminderStore) ListDueReminders(ctx context.Context, now time.Time, limit int) ([]domain.MemoReminder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listErr != nil {
+		return nil, s.listErr
+	}
+	var due []domain.MemoReminder
+	for _, r := range s.reminders {
+		if !r.Memo.Reminded && !r.Memo.RemindAt.After(now) {
+			due = append(due, r)
+		}
+	}
+	return due, nil
+}
+
+func (s *reminderStore) MarkReminded(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.reminders {
+		if s.reminders[i].Memo.ID == id {
+			s.reminders[i].Memo.Reminded = true
+		}
+	}
+	s.marked = append(s.marked, id)
+	return nil
+}
+
+func (s *reminderStore) markedIDs() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.marked...)
+}
+
+// fakeNotifier は通知したメモのIDを記録し、failIDsのメモは失敗させる
+type fakeNotifier struct {
+	mu      sync.Mutex
+	sent    []int
+	failIDs map[int]bool
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, reminder domain.MemoReminder) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failIDs[reminder.Memo.ID] {
+		return errors.New("webhook unavailable")
+	}
+	n.sent = append(n.sent, reminder.Memo.ID)
+	return nil
+}
+
+func newReminder(id int, remindAt time.Time) domain.MemoReminder {
+	return domain.MemoReminder{UserID: 1, Memo: domain.Memo{ID: id, Title: "Reminder", RemindAt: &remindAt}}
+}
+
+func TestSendDueReminders(t *testing.T) {
+	now := time.Now()
+
+	t.Run("期限の来たリマインダーだけを通知して通知済みにする", func(t *testing.T) {
+		store := &reminderStore{reminders: []domain.MemoReminder{
+			newReminder(1, now.Add(-time.Minute)),
+			newReminder(2, now.Add(time.Hour)),
+		}}
+		n := &fakeNotifier{}
+		logger, _ := logtest.NewNullLogger()
+
+		sent := repository.SendDueReminders(context.Background(), store, n, now, logger)
+		assert.Equal(t, 1, sent)
+		assert.Equal(t, []int{1}, n.sent)
+		assert.Equal(t, []int{1}, store.markedIDs())
+
+		// 通知済みのリマインダーは再送しない
+		assert.Equal(t, 0, repository.SendDueReminders(context.Background(), store, n, now, logger))
+	})
+
+	t.Run("通知に失敗したリマインダーは通知済みにせず次回に再送する", func(t *testing.T) {
+		store := &reminderStore{reminders: []domain.MemoReminder{newReminder(1, now.Add(-time.Minute))}}
+		n := &fakeNotifier{failIDs: map[int]bool{1: true}}
+		logger, hook := logtest.NewNullLogger()
+
+		assert.Equal(t, 0, repository.SendDueReminders(context.Background(), store, n, now, logger))
+		assert.Empty(t, store.markedIDs())
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, "リマインダーの通知に失敗", hook.LastEntry().Message)
+
+		n.failIDs = nil
+		assert.Equal(t, 1, repository.SendDueReminders(context.Background(), store, n, now, logger))
+		assert.Equal(t, []int{1}, store.markedIDs())
+	})
+
+	t.Run("取得に失敗した場合はログに記録する", func(t *testing.T) {
+		store := &reminderStore{listErr: errors.New("connection refused")}
+		logger, hook := logtest.NewNullLogger()
+
+		assert.Equal(t, 0, repository.SendDueReminders(context.Background(), store, &fakeNotifier{}, now, logger))
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, "リマインダーの取得に失敗", hook.LastEntry().Message)
+	})
+}
+
+func TestStartReminderScheduler(t *testing.T) {
+	store := &reminderStore{reminders: []domain.MemoReminder{newReminder(1, time.Now().Add(-time.Minute))}}
+	logger, _ := logtest.NewNullLogger()
+
+	stop := repository.StartReminderScheduler(store, &fakeNotifier{}, 10*time.Millisecond, logger)
+	assert.Eventually(t, func() bool { return len(store.markedIDs()) == 1 }, time.Second, 5*time.Millisecond)
+	stop()
+}
+
+func TestReminderRepository_ListDueReminders(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	logger, _ := logtest.NewNullLogger()
+	repo := repository.NewReminderRepository(&database.DB{DB: sqlDB}, logger)
+
+	now := time.Now()
+	remindAt := now.Add(-time.Minute)
+	mock.ExpectQuery(`SELECT .*, user_id FROM memos WHERE remind_at <= \$1 AND reminded = false AND status = \$2 AND user_id IS NOT NULL ORDER BY remind_at, id LIMIT \$3`).
+		WithArgs(now, string(domain.StatusActive), 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "category", "tags", "priority", "status", "pinned",
+			"created_at", "updated_at", "completed_at", "trashed_at", "due_date", "remind_at", "reminded", "color", "version", "user_id"}).
+			AddRow(3, "Call", "Call back", "", `[]`, "medium", "active", false, now, now, nil, nil, nil, remindAt, false, nil, 1, 42))
+
+	reminders, err := repo.ListDueReminders(context.Background(), now, 100)
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, 42, reminders[0].UserID)
+	assert.Equal(t, 3, reminders[0].Memo.ID)
+	require.NotNil(t, reminders[0].Memo.RemindAt)
+	assert.True(t, remindAt.Equal(*reminders[0].Memo.RemindAt))
+
+	mock.ExpectExec(`UPDATE memos SET reminded = true WHERE id = \$1`).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.MarkReminded(context.Background(), 3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,155 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+	"memo-app/src/infrastructure/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 削除系のDBエラーはログに出力したうえで呼び出し元に返し、結果を参照してパニックしない
+func TestMemoRepository_DeleteErrorsPropagate(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	execErr := errors.New("connection reset by peer")
+
+	newRepo := func(t *testing.T) (domain.MemoRepository, sqlmock.Sqlmock, *logtest.Hook) {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+		logger, hook := logtest.NewNullLogger()
+		return repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger), mock, hook
+	}
+
+	tests := []struct {
+		name   string
+		expect func(mock sqlmock.Sqlmock)
+		call   func(repo domain.MemoRepository) error
+	}{
+		{
+			name: "Delete の実行エラー",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE memos SET .* WHERE id = \$1 AND status <> \$3 AND user_id = \$4`).WithArgs(7, sqlmock.AnyArg(), "trashed", 42).WillReturnError(execErr)
+			},
+			call: func(repo domain.MemoRepository) error { return repo.Delete(ctx, 7) },
+		},
+		{
+			name: "Delete の削除件数の取得エラー",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE memos SET`).WillReturnResult(sqlmock.NewErrorResult(execErr))
+			},
+			call: func(repo domain.MemoRepository) error { return repo.Delete(ctx, 7) },
+		},
+		{
+			name: "PermanentDelete の実行エラー",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`WITH deleted AS`).WithArgs(7, "trashed", 42).WillReturnError(execErr)
+			},
+			call: func(repo domain.MemoRepository) error { return repo.PermanentDelete(ctx, 7) },
+		},
+		{
+			name: "PermanentDelete の削除件数の取得エラー",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`WITH deleted AS`).WillReturnResult(sqlmock.NewErrorResult(execErr))
+			},
+			call: func(repo domain.MemoRepository) error { return repo.PermanentDelete(ctx, 7) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock, hook := newRepo(t)
+			tt.expect(mock)
+
+			var err error
+			require.NotPanics(t, func() { err = tt.call(repo) })
+			require.Error(t, err)
+			assert.ErrorIs(t, err, execErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			entry := hook.LastEntry()
+			require.NotNil(t, entry)
+			assert.Equal(t, logrus.ErrorLevel, entry.Level)
+			assert.Equal(t, 7, entry.Data["memo_id"])
+			assert.Equal(t, 42, entry.Data["user_id"])
+		})
+	}
+}
+
+// Delete は1段階ずつ進める UPDATE だけを発行し、行を物理削除しない
+func TestMemoRepository_DeleteIsStaged(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	now := time.Now()
+
+	newRepo := func(t *testing.T) (domain.MemoRepository, sqlmock.Sqlmock) {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+		logger, _ := logtest.NewNullLogger()
+		return repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger), mock
+	}
+
+	t.Run("アクティブはアーカイブ、アーカイブはゴミ箱に移動する", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectExec(`UPDATE memos SET\s+status = CASE status WHEN 'active' THEN 'archived' ELSE 'trashed' END,`+
+			`\s+completed_at = CASE WHEN status = 'active' AND completed_at IS NULL THEN \$2 ELSE completed_at END,`+
+			`\s+trashed_at = CASE WHEN status = 'archived' THEN \$2 ELSE trashed_at END,.*`+
+			`WHERE id = \$1 AND status <> \$3 AND user_id = \$4`).
+			WithArgs(7, sqlmock.AnyArg(), "trashed", 42).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, repo.Delete(ctx, 7))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ゴミ箱のメモは変更せずにエラーを返す", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectExec(`UPDATE memos SET`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT .* FROM memos WHERE id = \$1 AND user_id = \$2`).WithArgs(7, 42).
+			WillReturnRows(sqlmock.NewRows(memoRowColumns).
+				AddRow(7, "Title", "Content", "", `[]`, "medium", "trashed", false, now, now, now, now, nil, nil, false, nil, 3))
+
+		assert.EqualError(t, repo.Delete(ctx, 7), "memo is already in trash")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("存在しないメモ", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectExec(`UPDATE memos SET`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT .* FROM memos WHERE id = \$1 AND user_id = \$2`).WithArgs(7, 42).
+			WillReturnRows(sqlmock.NewRows(memoRowColumns))
+
+		err := repo.Delete(ctx, 7)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "memo not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// ゴミ箱の自動削除も PermanentDelete と同じ文でリサイクルログに複製する
+func TestMemoRepository_PurgeTrashedCopiesToRecycleLog(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	logger, _ := logtest.NewNullLogger()
+	repo := repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger)
+
+	mock.ExpectExec(`WITH deleted AS \(DELETE FROM memos WHERE status = \$1 AND trashed_at < \$2 RETURNING id, user_id, .*\)\s+`+
+		`INSERT INTO deleted_memos \(memo_id, user_id, .*\)\s+SELECT id, user_id, .* FROM deleted`).
+		WithArgs("trashed", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	purged, err := repo.PurgeTrashedOlderThan(context.Background(), 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,38 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+	"memo-app/src/infrastructure/repository"
+
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoRepository_LogsCarryUserAndRequestID(t *testing.T) {
+	// 接続できないDBを指定し、クエリ失敗時のエラーログを検証する
+	sqlDB, err := sql.Open("postgres", "host=127.0.0.1 port=1 user=memo dbname=memo sslmode=disable connect_timeout=1")
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	logger, hook := logtest.NewNullLogger()
+	repo := repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger)
+
+	ctx := domain.WithRequestID(domain.WithUserID(context.Background(), 42), "req-123")
+	_, err = repo.GetByID(ctx, 7)
+	require.Error(t, err)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.ErrorLevel, entry.Level)
+	assert.Equal(t, 42, entry.Data["user_id"])
+	assert.Equal(t, "req-123", entry.Data["request_id"])
+	assert.Equal(t, 7, entry.Data["memo_id"])
+}
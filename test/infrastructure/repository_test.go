@@ -199,6 +199,7 @@ func TestDomainEntity_Status(t *testing.T) {
 	}{
 		{"valid active status", domain.StatusActive, true},
 		{"valid archived status", domain.StatusArchived, true},
+		{"valid trashed status", domain.StatusTrashed, true},
 		{"invalid status", domain.Status("invalid"), false},
 		{"empty status", domain.Status(""), false},
 	}
@@ -210,6 +211,18 @@ func TestDomainEntity_Status(t *testing.T) {
 	}
 }
 
+func TestDomainEntity_EnumCodes(t *testing.T) {
+	assert.Equal(t, 1, domain.PriorityLow.Code())
+	assert.Equal(t, 2, domain.PriorityMedium.Code())
+	assert.Equal(t, 3, domain.PriorityHigh.Code())
+	assert.Equal(t, 0, domain.Priority("invalid").Code())
+
+	assert.Equal(t, 1, domain.StatusActive.Code())
+	assert.Equal(t, 2, domain.StatusArchived.Code())
+	assert.Equal(t, 3, domain.StatusTrashed.Code())
+	assert.Equal(t, 0, domain.Status("invalid").Code())
+}
+
 func TestDomainEntity_Memo(t *testing.T) {
 	now := time.Now()
 	memo := &domain.Memo{
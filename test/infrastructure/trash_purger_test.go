@@ -0,0 +1,125 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"memo-app/src/domain"
+	"memo-app/src/infrastructure/repository"
+
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// purgeRecorder はPurgeTrashedOlderThan・PurgeDeletedOlderThanの呼び出しを記録するリポジトリ
+type purgeRecorder struct {
+	domain.MemoRepository
+	mu          sync.Mutex
+	ages        []time.Duration
+	deletedAges []time.Duration
+	err         error
+}
+
+func (r *purgeRecorder) PurgeTrashedOlderThan(ctx context.Context, age time.Duration) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ages = append(r.ages, age)
+	return 3, r.err
+}
+
+func (r *purgeRecorder) PurgeDeletedOlderThan(ctx context.Context, age time.Duration) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deletedAges = append(r.deletedAges, age)
+	return 1, r.err
+}
+
+func (r *purgeRecorder) deletedCalls() []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Duration(nil), r.deletedAges...)
+}
+
+func (r *purgeRecorder) calls() []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Duration(nil), r.ages...)
+}
+
+func TestStartTrashPurge(t *testing.T) {
+	t.Run("間隔ごとに保持期間を渡して削除する", func(t *testing.T) {
+		repo := &purgeRecorder{}
+		logger, _ := logtest.NewNullLogger()
+
+		stop := repository.StartTrashPurge(repo, 10*time.Millisecond, 72*time.Hour, logger)
+		assert.Eventually(t, func() bool { return len(repo.calls()) >= 2 }, time.Second, 5*time.Millisecond)
+		stop()
+
+		for _, age := range repo.calls() {
+			assert.Equal(t, 72*time.Hour, age)
+		}
+	})
+
+	t.Run("停止後は削除しない", func(t *testing.T) {
+		repo := &purgeRecorder{}
+		logger, _ := logtest.NewNullLogger()
+
+		stop := repository.StartTrashPurge(repo, 10*time.Millisecond, time.Hour, logger)
+		assert.Eventually(t, func() bool { return len(repo.calls()) >= 1 }, time.Second, 5*time.Millisecond)
+		stop()
+
+		count := len(repo.calls())
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, count, len(repo.calls()))
+	})
+
+	t.Run("失敗はログに記録して継続する", func(t *testing.T) {
+		repo := &purgeRecorder{err: errors.New("connection refused")}
+		logger, hook := logtest.NewNullLogger()
+
+		stop := repository.StartTrashPurge(repo, 10*time.Millisecond, time.Hour, logger)
+		assert.Eventually(t, func() bool { return len(repo.calls()) >= 2 }, time.Second, 5*time.Millisecond)
+		stop()
+
+		var errorLogged bool
+		for _, entry := range hook.AllEntries() {
+			if entry.Level == logrus.ErrorLevel {
+				errorLogged = true
+			}
+		}
+		assert.True(t, errorLogged)
+	})
+}
+
+func TestStartTrashPurgeWithRecycleLog(t *testing.T) {
+	t.Run("ゴミ箱と一緒にリサイクルログも保持期間で削除する", func(t *testing.T) {
+		repo := &purgeRecorder{}
+		logger, _ := logtest.NewNullLogger()
+
+		stop := repository.StartTrashPurgeWithRecycleLog(repo, 10*time.Millisecond, 72*time.Hour, 720*time.Hour, logger)
+		assert.Eventually(t, func() bool { return len(repo.deletedCalls()) >= 2 }, time.Second, 5*time.Millisecond)
+		stop()
+
+		for _, age := range repo.calls() {
+			assert.Equal(t, 72*time.Hour, age)
+		}
+		for _, age := range repo.deletedCalls() {
+			assert.Equal(t, 720*time.Hour, age)
+		}
+	})
+
+	t.Run("保持期間が0の場合はリサイクルログを削除しない", func(t *testing.T) {
+		repo := &purgeRecorder{}
+		logger, _ := logtest.NewNullLogger()
+
+		stop := repository.StartTrashPurgeWithRecycleLog(repo, 10*time.Millisecond, time.Hour, 0, logger)
+		assert.Eventually(t, func() bool { return len(repo.calls()) >= 2 }, time.Second, 5*time.Millisecond)
+		stop()
+
+		assert.Empty(t, repo.deletedCalls())
+	})
+}
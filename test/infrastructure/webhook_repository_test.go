@@ -0,0 +1,81 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+	"memo-app/src/infrastructure/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var webhookRowColumns = []string{"id", "user_id", "url", "secret", "events", "created_at", "updated_at"}
+
+func newMockWebhookRepository(t *testing.T) (domain.WebhookRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	logger, _ := logtest.NewNullLogger()
+	return repository.NewWebhookRepository(&database.DB{DB: sqlDB}, logger), mock
+}
+
+func TestWebhookRepository(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	now := time.Now()
+
+	t.Run("作成したWebhookは認証済みユーザーの所有になる", func(t *testing.T) {
+		repo, mock := newMockWebhookRepository(t)
+		mock.ExpectQuery(`INSERT INTO webhooks \(user_id, url, secret, events, created_at, updated_at\)`).
+			WithArgs(42, "https://hooks.example.com", "secret", `["memo.created"]`, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+		webhook, err := repo.Create(ctx, &domain.Webhook{URL: "https://hooks.example.com", Secret: "secret", Events: []string{domain.MemoEventCreated}})
+		require.NoError(t, err)
+		assert.Equal(t, 3, webhook.ID)
+		assert.Equal(t, 42, webhook.UserID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("取得はユーザーで絞り込む", func(t *testing.T) {
+		repo, mock := newMockWebhookRepository(t)
+		mock.ExpectQuery(`SELECT .* FROM webhooks WHERE id = \$1 AND user_id = \$2`).
+			WithArgs(3, 42).
+			WillReturnRows(sqlmock.NewRows(webhookRowColumns))
+
+		_, err := repo.GetByID(ctx, 3)
+		assert.EqualError(t, err, "webhook not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("イベントを購読しているWebhookだけを返す", func(t *testing.T) {
+		repo, mock := newMockWebhookRepository(t)
+		mock.ExpectQuery(`SELECT .* FROM webhooks WHERE user_id = \$1 AND events @> \$2::jsonb ORDER BY id`).
+			WithArgs(42, `["memo.deleted"]`).
+			WillReturnRows(sqlmock.NewRows(webhookRowColumns).
+				AddRow(3, 42, "https://hooks.example.com", "secret", `["memo.created","memo.deleted"]`, now, now))
+
+		webhooks, err := repo.ListByEvent(context.Background(), 42, domain.MemoEventDeleted)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		assert.Equal(t, []string{domain.MemoEventCreated, domain.MemoEventDeleted}, webhooks[0].Events)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("存在しないWebhookは削除できない", func(t *testing.T) {
+		repo, mock := newMockWebhookRepository(t)
+		mock.ExpectExec(`DELETE FROM webhooks WHERE id = \$1 AND user_id = \$2`).
+			WithArgs(3, 42).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.EqualError(t, repo.Delete(ctx, 3), "webhook not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -221,6 +221,8 @@ func setupTestConfig() *config.Config {
 			GitHubRedirectURL:  "http://localhost:8000/api/auth/github/callback",
 			MaxAccountsPerIP:   3,
 			IPCooldownPeriod:   24 * time.Hour,
+			GitHubOAuthBaseURL: "https://github.com",
+			GitHubAPIBaseURL:   "https://api.github.com",
 		},
 	}
 }
@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"memo-app/src/config"
 	"memo-app/src/database"
@@ -106,6 +108,8 @@ func (suite *MemoIntegrationTestSuite) SetupSuite() {
 		api.PATCH("/:id/archive", suite.handler.ArchiveMemo)
 		api.PATCH("/:id/restore", suite.handler.RestoreMemo)
 		api.GET("/search", suite.handler.SearchMemos)
+		api.POST("/:id/promote", suite.handler.PromoteMemo)
+		api.POST("/:id/touch", suite.handler.TouchMemo)
 	}
 }
 
@@ -123,11 +127,14 @@ func (suite *MemoIntegrationTestSuite) SetupTest() {
 		return
 	}
 
-	// 各テスト前にmemosテーブルをクリーンアップ
+	// 各テスト前にmemos・deleted_memosテーブルをクリーンアップ
 	ctx := context.Background()
 	_, err := suite.db.ExecContext(ctx, "DELETE FROM memos")
+	if err == nil {
+		_, err = suite.db.ExecContext(ctx, "DELETE FROM deleted_memos")
+	}
 	if err != nil {
-		// memosテーブルが存在しない場合は作成
+		// テーブルが存在しない場合は作成
 		suite.createTablesIfNotExists()
 		_, err = suite.db.ExecContext(ctx, "DELETE FROM memos")
 		suite.Require().NoError(err)
@@ -259,15 +266,30 @@ func (suite *MemoIntegrationTestSuite) TestFullMemoLifecycle() {
 
 	suite.Equal(http.StatusNoContent, w.Code)
 
-	// 8. 削除後の取得確認（404エラーになるはず）
+	// 8. 削除は1段階ずつ進む（active → archived → trashed）。行は残り、ゴミ箱のメモは409になる
 	getDeletedURL := "/api/memos/" + fmt.Sprintf("%d", memoID)
-	req = httptest.NewRequest("GET", getDeletedURL, nil)
-	req.Header.Set("Authorization", "Bearer "+suite.testJWTToken)
+	for _, want := range []string{"archived", "trashed"} {
+		req = httptest.NewRequest("GET", getDeletedURL, nil)
+		req.Header.Set("Authorization", "Bearer "+suite.testJWTToken)
+		w = httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Require().Equal(http.StatusOK, w.Code)
+		suite.Contains(w.Body.String(), `"status":"`+want+`"`)
+
+		if want == "archived" {
+			req = httptest.NewRequest("DELETE", deleteURL, nil)
+			req.Header.Set("Authorization", "Bearer "+suite.testJWTToken)
+			w = httptest.NewRecorder()
+			suite.router.ServeHTTP(w, req)
+			suite.Equal(http.StatusNoContent, w.Code)
+		}
+	}
 
+	req = httptest.NewRequest("DELETE", deleteURL, nil)
+	req.Header.Set("Authorization", "Bearer "+suite.testJWTToken)
 	w = httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
-
-	suite.Equal(http.StatusNotFound, w.Code)
+	suite.Equal(http.StatusConflict, w.Code)
 }
 
 func (suite *MemoIntegrationTestSuite) TestSearchMemos() {
@@ -326,6 +348,1049 @@ func (suite *MemoIntegrationTestSuite) TestSearchMemos() {
 	suite.Require().NoError(err)
 }
 
+func (suite *MemoIntegrationTestSuite) TestSearchMemos_FullTextRanking() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	bodyOnly, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Weekly notes", Content: "Mentions kubernetes once"})
+	suite.Require().NoError(err)
+	inTitle, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Kubernetes deployment", Content: "Rolling out kubernetes clusters"})
+	suite.Require().NoError(err)
+	_, err = suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Unrelated", Content: "Nothing to see"})
+	suite.Require().NoError(err)
+
+	// タイトルに含むメモの方が関連度が高く先頭になる
+	memos, total, err := suite.usecase.SearchMemos(ctx, "kubernetes", domain.MemoFilter{Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Equal(2, total)
+	suite.Require().Len(memos, 2)
+	suite.Equal(inTitle.ID, memos[0].ID)
+	suite.Equal(bodyOnly.ID, memos[1].ID)
+	suite.Require().NotNil(memos[0].SearchRank)
+	suite.Require().NotNil(memos[1].SearchRank)
+	suite.Greater(*memos[0].SearchRank, *memos[1].SearchRank)
+
+	// 短いクエリは部分一致にフォールバックし、関連度は付与しない
+	memos, total, err = suite.usecase.SearchMemos(ctx, "ku", domain.MemoFilter{Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Equal(2, total)
+	for _, memo := range memos {
+		suite.Nil(memo.SearchRank)
+	}
+
+	// 日本語は空白で区切られないため、文の一部でも部分一致で見つかる
+	japanese, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "週次定例会議の議事録", Content: "来週までに資料を共有する"})
+	suite.Require().NoError(err)
+	memos, total, err = suite.usecase.SearchMemos(ctx, "議事録", domain.MemoFilter{Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Equal(1, total)
+	suite.Require().Len(memos, 1)
+	suite.Equal(japanese.ID, memos[0].ID)
+}
+
+func (suite *MemoIntegrationTestSuite) TestCaseInsensitiveCategory() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	// 正規化前に保存された小文字カテゴリーのメモ
+	_, err := suite.repo.Create(ctx, &domain.Memo{
+		Title:    "Lowercase Category Memo",
+		Content:  "Content",
+		Category: "work",
+		Tags:     []string{},
+		Priority: domain.PriorityMedium,
+	})
+	suite.Require().NoError(err)
+
+	filter := domain.MemoFilter{Category: "Work", Page: 1, Limit: 10}
+
+	// 無効時は大文字小文字を区別する
+	memos, total, err := suite.repo.List(ctx, filter)
+	suite.Require().NoError(err)
+	suite.Equal(0, total)
+	suite.Empty(memos)
+
+	// 有効時は "Work" で "work" のメモにマッチする
+	ciRepo := repository.NewMemoRepositoryWithConfig(suite.db, logger.Log, &config.MemoConfig{CaseInsensitiveCategory: true})
+	memos, total, err = ciRepo.List(ctx, filter)
+	suite.Require().NoError(err)
+	suite.Equal(1, total)
+	suite.Require().Len(memos, 1)
+	suite.Equal("work", memos[0].Category)
+}
+
+func (suite *MemoIntegrationTestSuite) TestTouchMemo() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	older, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{
+		Title:    "Older Memo",
+		Content:  "Content",
+		Category: "Work",
+		Tags:     []string{"touch"},
+		Priority: "low",
+	})
+	suite.Require().NoError(err)
+	newer, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{
+		Title:   "Newer Memo",
+		Content: "Content",
+	})
+	suite.Require().NoError(err)
+
+	before, err := suite.repo.GetByID(ctx, older.ID)
+	suite.Require().NoError(err)
+
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/memos/%d/touch", older.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.testJWTToken)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	var touched handler.MemoResponseDTO
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &touched))
+
+	// updated_at のみが進み、他のフィールドは変わらない
+	suite.True(touched.UpdatedAt.After(before.UpdatedAt))
+	suite.Equal(before.Title, touched.Title)
+	suite.Equal(before.Content, touched.Content)
+	suite.Equal(before.Category, touched.Category)
+	suite.Equal(before.Tags, touched.Tags)
+	suite.Equal(before.Priority.String(), touched.Priority)
+	suite.Equal(before.Status.String(), touched.Status)
+	suite.True(before.CreatedAt.Equal(touched.CreatedAt))
+
+	// updated_at 降順の一覧で先頭に移動する
+	memos, _, err := suite.usecase.ListMemos(ctx, domain.MemoFilter{Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Require().Len(memos, 2)
+	suite.Equal(older.ID, memos[0].ID)
+	suite.Equal(newer.ID, memos[1].ID)
+}
+
+func (suite *MemoIntegrationTestSuite) TestRecentSearchQueries() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+	suite.Require().NoError(suite.repo.ClearSearchQueries(ctx))
+
+	const limit = 3
+	for _, query := range []string{"golang", "docker", "golang", "postgres", "gin", ""} {
+		suite.Require().NoError(suite.repo.RecordSearchQuery(ctx, query, limit))
+	}
+
+	// 重複は1件にまとめられ、上限を超えた古い履歴は削除される
+	queries, err := suite.repo.ListSearchQueries(ctx)
+	suite.Require().NoError(err)
+	suite.Equal([]string{"gin", "postgres", "golang"}, queries)
+
+	suite.Require().NoError(suite.repo.ClearSearchQueries(ctx))
+	queries, err = suite.repo.ListSearchQueries(ctx)
+	suite.Require().NoError(err)
+	suite.Empty(queries)
+}
+
+func (suite *MemoIntegrationTestSuite) TestPaginationStableWithIdenticalTimestamps() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	// 一括インポートを想定し、同一のタイムスタンプを持つメモを作成
+	const count = 7
+	timestamp := time.Now().Truncate(time.Second)
+	expected := make(map[int]bool, count)
+	for i := 0; i < count; i++ {
+		var id int
+		err := suite.db.QueryRowContext(ctx,
+			`INSERT INTO memos (title, content, category, tags, priority, status, user_id, created_at, updated_at)
+			 VALUES ($1, 'Imported', '', '[]', 'medium', 'active', $2, $3, $3) RETURNING id`,
+			fmt.Sprintf("Imported %d", i), suite.testUserID, timestamp,
+		).Scan(&id)
+		suite.Require().NoError(err)
+		expected[id] = false
+	}
+
+	// 全ページを取得し、各メモがちょうど1回ずつ返されることを確認
+	const limit = 2
+	seen := make(map[int]int, count)
+	for page := 1; page <= (count+limit-1)/limit; page++ {
+		memos, total, err := suite.usecase.ListMemos(ctx, domain.MemoFilter{Page: page, Limit: limit})
+		suite.Require().NoError(err)
+		suite.Equal(count, total)
+		for _, memo := range memos {
+			seen[memo.ID]++
+		}
+	}
+
+	suite.Len(seen, count)
+	for id := range expected {
+		suite.Equal(1, seen[id], "memo %d should appear exactly once", id)
+	}
+}
+
+func (suite *MemoIntegrationTestSuite) TestListTags() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	for _, tags := range [][]string{
+		{"golang", "docker"},
+		{"golang", "api"},
+		{"golang", "docker"},
+		{"api"},
+		{"zebra"},
+	} {
+		_, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{
+			Title:   "Tagged Memo",
+			Content: "Content",
+			Tags:    tags,
+		})
+		suite.Require().NoError(err)
+	}
+
+	// ゴミ箱のメモのタグは数えない
+	trashed, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Trashed", Content: "Content", Tags: []string{"zebra", "trash-only"}})
+	suite.Require().NoError(err)
+	_, err = suite.repo.Trash(ctx, trashed.ID)
+	suite.Require().NoError(err)
+
+	// 件数の降順、同数の場合はタグ名の昇順
+	tags, err := suite.usecase.ListTags(ctx, domain.TagFilter{})
+	suite.Require().NoError(err)
+	suite.Equal([]domain.TagCount{
+		{Tag: "golang", Count: 3},
+		{Tag: "api", Count: 2},
+		{Tag: "docker", Count: 2},
+		{Tag: "zebra", Count: 1},
+	}, tags)
+
+	// limit と min_count の適用
+	tags, err = suite.usecase.ListTags(ctx, domain.TagFilter{Limit: 2})
+	suite.Require().NoError(err)
+	suite.Equal([]domain.TagCount{{Tag: "golang", Count: 3}, {Tag: "api", Count: 2}}, tags)
+
+	tags, err = suite.usecase.ListTags(ctx, domain.TagFilter{MinCount: 3})
+	suite.Require().NoError(err)
+	suite.Equal([]domain.TagCount{{Tag: "golang", Count: 3}}, tags)
+}
+
+func (suite *MemoIntegrationTestSuite) TestListTags_Normalization() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	// SanitizeTags を通らずに保存されたタグも同じ規則で正規化して集計する
+	for _, tags := range [][]string{
+		{"golang", " golang ", "golang"},
+		{"go  lang", "Golang"},
+		{"   "},
+	} {
+		_, err := suite.repo.Create(ctx, &domain.Memo{
+			Title:    "Raw Tags",
+			Content:  "Content",
+			Tags:     tags,
+			Priority: domain.PriorityMedium,
+			Status:   domain.StatusActive,
+		})
+		suite.Require().NoError(err)
+	}
+
+	tags, err := suite.usecase.ListTags(ctx, domain.TagFilter{})
+	suite.Require().NoError(err)
+	// 大文字小文字は区別し、同じメモ内の重複は1件として数える
+	suite.ElementsMatch([]domain.TagCount{
+		{Tag: "Golang", Count: 1},
+		{Tag: "go lang", Count: 1},
+		{Tag: "golang", Count: 1},
+	}, tags)
+
+	// 同じメモ内の重複タグは min_count の判定でも1件として数える
+	tags, err = suite.usecase.ListTags(ctx, domain.TagFilter{MinCount: 2})
+	suite.Require().NoError(err)
+	suite.Empty(tags)
+}
+
+func (suite *MemoIntegrationTestSuite) TestCategoryAndTagVariantsCollapse() {
+	ctx := domain.WithUserID(context.Background(), suite.createUser("normalize_labels"))
+	cfg := &config.MemoConfig{CaseInsensitiveCategory: true, CaseInsensitiveTags: true}
+	repo := repository.NewMemoRepositoryWithConfig(suite.db, logger.Log, cfg)
+	uc := usecase.NewMemoUsecaseWithConfig(repo, cfg)
+
+	// 書き込み時に正規化される表記揺れ
+	for _, variant := range []string{"Work", "work ", "WORK", " Work  "} {
+		_, err := uc.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Memo", Content: "Content", Category: variant, Tags: []string{variant}})
+		suite.Require().NoError(err)
+	}
+	// 正規化前に保存された表記揺れも集計では1件にまとまる
+	_, err := repo.Create(ctx, &domain.Memo{Title: "Raw", Content: "Content", Category: " ＷorK ", Tags: []string{"ｗＯＲＫ"}, Priority: domain.PriorityMedium})
+	suite.Require().NoError(err)
+
+	categories, err := uc.ListCategories(ctx)
+	suite.Require().NoError(err)
+	suite.Equal([]string{"work"}, categories)
+
+	tags, err := uc.ListTags(ctx, domain.TagFilter{})
+	suite.Require().NoError(err)
+	suite.Equal([]domain.TagCount{{Tag: "work", Count: 5}}, tags)
+
+	stats, err := repo.Stats(ctx)
+	suite.Require().NoError(err)
+	suite.Equal(map[string]int{"work": 5}, stats.ByCategory)
+
+	// 表記揺れのある条件でも正規化済みのメモに一致する
+	_, total, err := uc.ListMemos(ctx, domain.MemoFilter{Category: "WORK ", Tags: []string{" Work"}})
+	suite.Require().NoError(err)
+	suite.Equal(4, total)
+}
+
+func (suite *MemoIntegrationTestSuite) TestCursorPagination() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	const count = 5
+	expected := make(map[int]bool, count)
+	for i := 0; i < count; i++ {
+		memo, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{
+			Title:   fmt.Sprintf("Cursor Memo %d", i),
+			Content: "Content",
+		})
+		suite.Require().NoError(err)
+		expected[memo.ID] = true
+	}
+
+	seen := make(map[int]int, count)
+	cursor := ""
+	for page := 0; page < count; page++ {
+		req := httptest.NewRequest("GET", "/api/memos?limit=2&cursor="+cursor, nil)
+		req.Header.Set("Authorization", "Bearer "+suite.testJWTToken)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Require().Equal(http.StatusOK, w.Code)
+
+		var response handler.MemoListResponseDTO
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		for _, memo := range response.Memos {
+			seen[memo.ID]++
+		}
+
+		// ページング中に追加されたメモは既存のページ位置をずらさない
+		if page == 0 {
+			_, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Inserted", Content: "Content"})
+			suite.Require().NoError(err)
+		}
+
+		if response.NextCursor == "" {
+			break
+		}
+		cursor = response.NextCursor
+	}
+
+	suite.Len(seen, count)
+	for id := range expected {
+		suite.Equal(1, seen[id], "memo %d should appear exactly once", id)
+	}
+}
+
+func (suite *MemoIntegrationTestSuite) TestMultiFieldSort() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	for _, req := range []usecase.CreateMemoRequest{
+		{Title: "B", Content: "Content golang", Priority: "low"},
+		{Title: "A", Content: "Content golang", Priority: "high"},
+		{Title: "C", Content: "Content golang", Priority: "high"},
+		{Title: "D", Content: "Content golang", Priority: "medium"},
+	} {
+		_, err := suite.usecase.CreateMemo(ctx, req)
+		suite.Require().NoError(err)
+	}
+
+	titles := func(memos []domain.Memo) []string {
+		result := make([]string, len(memos))
+		for i, memo := range memos {
+			result[i] = memo.Title
+		}
+		return result
+	}
+
+	// priority は low < medium < high の順で比較される
+	memos, _, err := suite.usecase.ListMemos(ctx, domain.MemoFilter{
+		Page:  1,
+		Limit: 10,
+		Sort:  []domain.SortField{{Field: "priority", Desc: true}, {Field: "title"}},
+	})
+	suite.Require().NoError(err)
+	suite.Equal([]string{"A", "C", "D", "B"}, titles(memos))
+
+	// 検索結果にも同じ並び順が適用される
+	memos, _, err = suite.usecase.SearchMemos(ctx, "golang", domain.MemoFilter{
+		Page:  1,
+		Limit: 10,
+		Sort:  []domain.SortField{{Field: "title", Desc: true}},
+	})
+	suite.Require().NoError(err)
+	suite.Equal([]string{"D", "C", "B", "A"}, titles(memos))
+}
+
+func (suite *MemoIntegrationTestSuite) TestBulkDeleteScopedToUser() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	// 別ユーザーのメモを用意する
+	otherCtx := domain.WithUserID(context.Background(), suite.createUser("bulkdelete_other"))
+
+	own, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Own Memo", Content: "Content"})
+	suite.Require().NoError(err)
+	others, err := suite.usecase.CreateMemo(otherCtx, usecase.CreateMemoRequest{Title: "Other Memo", Content: "Content"})
+	suite.Require().NoError(err)
+
+	results, err := suite.usecase.BulkDeleteMemos(ctx, []int{own.ID, others.ID, 999999})
+	suite.Require().NoError(err)
+	suite.Equal([]usecase.BulkResult{
+		{ID: own.ID, Status: usecase.BulkStatusDeleted},
+		{ID: others.ID, Status: usecase.BulkStatusNotFound},
+		{ID: 999999, Status: usecase.BulkStatusNotFound},
+	}, results)
+
+	// 他ユーザーのメモは変更されず、自分のメモはアーカイブされて残る
+	other, err := suite.usecase.GetMemo(otherCtx, others.ID)
+	suite.Require().NoError(err)
+	suite.Equal(domain.StatusActive, other.Status)
+	mine, err := suite.usecase.GetMemo(ctx, own.ID)
+	suite.Require().NoError(err)
+	suite.Equal(domain.StatusArchived, mine.Status)
+}
+
+func (suite *MemoIntegrationTestSuite) TestBulkUpdate() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	first, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "First", Content: "Content", Category: "home"})
+	suite.Require().NoError(err)
+	second, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Second", Content: "Content", Category: "home"})
+	suite.Require().NoError(err)
+
+	archived := "archived"
+	result, err := suite.usecase.BulkUpdateMemos(ctx, []int{first.ID, second.ID, 999999}, usecase.UpdateMemoRequest{Status: &archived})
+	suite.Require().NoError(err)
+	suite.Equal(2, result.Updated)
+	suite.Equal([]int{999999}, result.SkippedIDs)
+
+	// 指定していないカテゴリーは維持され、完了日時が設定される
+	memo, err := suite.usecase.GetMemo(ctx, first.ID)
+	suite.Require().NoError(err)
+	suite.Equal(domain.StatusArchived, memo.Status)
+	suite.Equal("home", memo.Category)
+	suite.NotNil(memo.CompletedAt)
+}
+
+func (suite *MemoIntegrationTestSuite) TestTrashAndPermanentDelete() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	memo, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Trash Me", Content: "Content"})
+	suite.Require().NoError(err)
+
+	// ゴミ箱に入っていないメモは完全削除できない
+	suite.Equal(usecase.ErrMemoNotTrashed, suite.usecase.PermanentDeleteMemo(ctx, memo.ID))
+
+	trashed, err := suite.usecase.TrashMemo(ctx, memo.ID)
+	suite.Require().NoError(err)
+	suite.Equal(domain.StatusTrashed, trashed.Status)
+	suite.NotNil(trashed.TrashedAt)
+
+	// ステータス未指定の一覧にはゴミ箱のメモを含めない
+	memos, total, err := suite.usecase.ListMemos(ctx, domain.MemoFilter{})
+	suite.Require().NoError(err)
+	suite.Equal(0, total)
+	suite.Empty(memos)
+
+	_, total, err = suite.usecase.ListMemos(ctx, domain.MemoFilter{Status: domain.StatusTrashed})
+	suite.Require().NoError(err)
+	suite.Equal(1, total)
+
+	suite.Require().NoError(suite.usecase.PermanentDeleteMemo(ctx, memo.ID))
+	_, err = suite.usecase.GetMemo(ctx, memo.ID)
+	suite.Equal(usecase.ErrMemoNotFound, err)
+}
+
+func (suite *MemoIntegrationTestSuite) TestRestoreDeletedMemo() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+	otherCtx := domain.WithUserID(context.Background(), suite.createUser("recycle_other"))
+
+	deleteMemo := func(title string) *domain.Memo {
+		memo, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: title, Content: "Content", Tags: []string{"keep"}})
+		suite.Require().NoError(err)
+		_, err = suite.usecase.TrashMemo(ctx, memo.ID)
+		suite.Require().NoError(err)
+		suite.Require().NoError(suite.usecase.PermanentDeleteMemo(ctx, memo.ID))
+		return memo
+	}
+
+	first := deleteMemo("Recycled")
+	deleted, total, err := suite.usecase.ListDeletedMemos(ctx, domain.MemoFilter{})
+	suite.Require().NoError(err)
+	suite.Require().Equal(1, total)
+	suite.Equal(first.ID, deleted[0].Memo.ID)
+	suite.Equal([]string{"keep"}, deleted[0].Memo.Tags)
+
+	// 他のユーザーのリサイクルログは見えず、復元もできない
+	_, total, err = suite.usecase.ListDeletedMemos(otherCtx, domain.MemoFilter{})
+	suite.Require().NoError(err)
+	suite.Equal(0, total)
+	_, err = suite.usecase.RestoreDeletedMemo(otherCtx, deleted[0].ID)
+	suite.Equal(usecase.ErrDeletedMemoNotFound, err)
+
+	// 元のIDが空いていれば同じIDで active に戻る
+	restored, err := suite.usecase.RestoreDeletedMemo(ctx, deleted[0].ID)
+	suite.Require().NoError(err)
+	suite.Equal(first.ID, restored.ID)
+	suite.Equal(domain.StatusActive, restored.Status)
+	suite.Nil(restored.TrashedAt)
+	_, err = suite.usecase.RestoreDeletedMemo(ctx, deleted[0].ID)
+	suite.Equal(usecase.ErrDeletedMemoNotFound, err)
+
+	// 元のIDが使われている場合は新しいIDで復元する
+	second := deleteMemo("Recycled again")
+	_, err = suite.db.ExecContext(context.Background(),
+		`INSERT INTO memos (id, title, content, priority, user_id) VALUES ($1, 'Taken', 'Content', 'medium', $2)`,
+		second.ID, suite.testUserID)
+	suite.Require().NoError(err)
+	deleted, _, err = suite.usecase.ListDeletedMemos(ctx, domain.MemoFilter{})
+	suite.Require().NoError(err)
+	suite.Require().Len(deleted, 1)
+	restored, err = suite.usecase.RestoreDeletedMemo(ctx, deleted[0].ID)
+	suite.Require().NoError(err)
+	suite.NotEqual(second.ID, restored.ID)
+	suite.Equal("Recycled again", restored.Title)
+}
+
+func (suite *MemoIntegrationTestSuite) TestListSharedWithMe() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+	ownerCtx := domain.WithUserID(context.Background(), suite.createUser("shared_owner"))
+
+	// 自分のメモは共有一覧に含まれない
+	_, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Own Memo", Content: "Content"})
+	suite.Require().NoError(err)
+
+	shared, err := suite.usecase.CreateMemo(ownerCtx, usecase.CreateMemoRequest{Title: "Shared Memo", Content: "Content"})
+	suite.Require().NoError(err)
+	_, err = suite.usecase.CreateMemo(ownerCtx, usecase.CreateMemoRequest{Title: "Private Memo", Content: "Content"})
+	suite.Require().NoError(err)
+
+	_, err = suite.db.ExecContext(context.Background(),
+		`INSERT INTO memo_shares (memo_id, shared_with_user_id, permission) VALUES ($1, $2, 'write')`,
+		shared.ID, suite.testUserID)
+	suite.Require().NoError(err)
+
+	memos, total, err := suite.usecase.ListSharedMemos(ctx, domain.MemoFilter{})
+	suite.Require().NoError(err)
+	suite.Equal(1, total)
+	suite.Require().Len(memos, 1)
+	suite.Equal(shared.ID, memos[0].Memo.ID)
+	suite.Equal("Shared Memo", memos[0].Memo.Title)
+	suite.Equal("shared_owner", memos[0].OwnerUsername)
+	suite.Equal(domain.SharePermissionWrite, memos[0].Permission)
+
+	// 共有先でないユーザーからは見えない
+	memos, total, err = suite.usecase.ListSharedMemos(ownerCtx, domain.MemoFilter{})
+	suite.Require().NoError(err)
+	suite.Equal(0, total)
+	suite.Empty(memos)
+}
+
+func (suite *MemoIntegrationTestSuite) TestPurgeTrashedOlderThan() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	old, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Old Trash", Content: "Content"})
+	suite.Require().NoError(err)
+	recent, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Recent Trash", Content: "Content"})
+	suite.Require().NoError(err)
+	active, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Active", Content: "Content"})
+	suite.Require().NoError(err)
+
+	for _, id := range []int{old.ID, recent.ID} {
+		_, err = suite.usecase.TrashMemo(ctx, id)
+		suite.Require().NoError(err)
+	}
+	_, err = suite.db.ExecContext(context.Background(),
+		`UPDATE memos SET trashed_at = $2 WHERE id = $1`, old.ID, time.Now().Add(-48*time.Hour))
+	suite.Require().NoError(err)
+
+	purged, err := suite.repo.PurgeTrashedOlderThan(context.Background(), 24*time.Hour)
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), purged)
+
+	_, err = suite.usecase.GetMemo(ctx, old.ID)
+	suite.ErrorIs(err, usecase.ErrMemoNotFound)
+	for _, id := range []int{recent.ID, active.ID} {
+		_, err = suite.usecase.GetMemo(ctx, id)
+		suite.NoError(err)
+	}
+
+	// 自動削除したメモも完全削除と同じくリサイクルログから復元できる
+	deleted, total, err := suite.usecase.ListDeletedMemos(ctx, domain.MemoFilter{})
+	suite.Require().NoError(err)
+	suite.Require().Equal(1, total)
+	suite.Equal(old.ID, deleted[0].Memo.ID)
+	restored, err := suite.usecase.RestoreDeletedMemo(ctx, deleted[0].ID)
+	suite.Require().NoError(err)
+	suite.Equal("Old Trash", restored.Title)
+}
+
+func (suite *MemoIntegrationTestSuite) TestListMemos_TagFilter() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	ids := map[string]int{}
+	for name, tags := range map[string][]string{
+		"both":       {"golang", "api"},
+		"golangOnly": {"golang"},
+		"apiOnly":    {"api"},
+		"prefix":     {"golang-tips", "api"},
+	} {
+		memo, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{
+			Title:   name,
+			Content: "Content",
+			Tags:    tags,
+		})
+		suite.Require().NoError(err)
+		ids[name] = memo.ID
+	}
+
+	idsOf := func(memos []domain.Memo) []int {
+		result := make([]int, len(memos))
+		for i, memo := range memos {
+			result[i] = memo.ID
+		}
+		return result
+	}
+
+	// 単一タグは完全一致（部分一致の "golang-tips" は含まない）
+	memos, total, err := suite.usecase.ListMemos(ctx, domain.MemoFilter{Tags: []string{"golang"}, Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Equal(2, total)
+	suite.ElementsMatch([]int{ids["both"], ids["golangOnly"]}, idsOf(memos))
+
+	// 複数タグはすべてを含むメモのみ（AND条件）
+	memos, total, err = suite.usecase.ListMemos(ctx, domain.MemoFilter{Tags: []string{"golang", "api"}, Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Equal(1, total)
+	suite.Equal([]int{ids["both"]}, idsOf(memos))
+
+	memos, total, err = suite.usecase.ListMemos(ctx, domain.MemoFilter{Tags: []string{"golang", "missing"}, Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Equal(0, total)
+	suite.Empty(memos)
+}
+
+func (suite *MemoIntegrationTestSuite) TestImportMemosWithIDs() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	const importedID = 50000
+	memos, err := suite.usecase.ImportMemosWithIDs(ctx, []usecase.ImportMemoRequest{
+		{ID: importedID, CreateMemoRequest: usecase.CreateMemoRequest{Title: "Imported", Content: "Original"}},
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(memos, 1)
+	suite.Equal(importedID, memos[0].ID)
+
+	// 指定したIDがそのまま保存されている
+	memo, err := suite.usecase.GetMemo(ctx, importedID)
+	suite.Require().NoError(err)
+	suite.Equal("Imported", memo.Title)
+
+	// シーケンスが進められており、通常の作成でIDが衝突しない
+	created, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "After Import", Content: "Content"})
+	suite.Require().NoError(err)
+	suite.Greater(created.ID, importedID)
+
+	// 既存のIDを含むインポートは全体が失敗する
+	_, err = suite.usecase.ImportMemosWithIDs(ctx, []usecase.ImportMemoRequest{
+		{ID: importedID + 100, CreateMemoRequest: usecase.CreateMemoRequest{Title: "New", Content: "Content"}},
+		{ID: importedID, CreateMemoRequest: usecase.CreateMemoRequest{Title: "Duplicate", Content: "Content"}},
+	})
+	suite.Equal(usecase.ErrMemoIDConflict, err)
+
+	_, err = suite.usecase.GetMemo(ctx, importedID+100)
+	suite.Equal(usecase.ErrMemoNotFound, err)
+}
+
+func (suite *MemoIntegrationTestSuite) TestExportMemos_UserScoped() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+	otherCtx := domain.WithUserID(context.Background(), suite.createUser("export_other"))
+
+	first, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "First", Content: "Content"})
+	suite.Require().NoError(err)
+	second, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Second", Content: "Content"})
+	suite.Require().NoError(err)
+	_, err = suite.usecase.TrashMemo(ctx, second.ID)
+	suite.Require().NoError(err)
+	_, err = suite.usecase.CreateMemo(otherCtx, usecase.CreateMemoRequest{Title: "Other user", Content: "Content"})
+	suite.Require().NoError(err)
+
+	// 自分のメモのみをID順に、ゴミ箱のメモも含めて返す
+	var exported []int
+	err = suite.usecase.ExportMemos(ctx, func(memo *domain.Memo) error {
+		exported = append(exported, memo.ID)
+		return nil
+	})
+	suite.Require().NoError(err)
+	suite.Equal([]int{first.ID, second.ID}, exported)
+}
+
+func (suite *MemoIntegrationTestSuite) TestUpdateMemo_VersionConflict() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	created, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Title", Content: "Content"})
+	suite.Require().NoError(err)
+	suite.Equal(1, created.Version)
+
+	// 同じバージョンを読んだ2つのタブが順に更新すると、後者は競合になる
+	version := created.Version
+	first, err := suite.usecase.UpdateMemo(ctx, created.ID, usecase.UpdateMemoRequest{Content: stringPtr("Tab A"), Version: &version})
+	suite.Require().NoError(err)
+	suite.Equal(2, first.Version)
+
+	_, err = suite.usecase.UpdateMemo(ctx, created.ID, usecase.UpdateMemoRequest{Content: stringPtr("Tab B"), Version: &version})
+	suite.Equal(usecase.ErrVersionConflict, err)
+
+	// リポジトリでも古いバージョンでの上書きは拒否される
+	stale := *first
+	stale.Version = 1
+	stale.Content = "Stale"
+	_, err = suite.repo.Update(ctx, created.ID, &stale)
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "version conflict")
+
+	memo, err := suite.usecase.GetMemo(ctx, created.ID)
+	suite.Require().NoError(err)
+	suite.Equal("Tab A", memo.Content)
+	suite.Equal(2, memo.Version)
+}
+
+func (suite *MemoIntegrationTestSuite) TestMemoHistory_RecordAndRevert() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	created, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "v1", Content: "first", Tags: []string{"a"}})
+	suite.Require().NoError(err)
+
+	// 更新ごとに直前の内容が履歴として残る
+	_, err = suite.usecase.UpdateMemo(ctx, created.ID, usecase.UpdateMemoRequest{Title: stringPtr("v2"), Content: stringPtr("second")})
+	suite.Require().NoError(err)
+	history, err := suite.usecase.ListMemoHistory(ctx, created.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(history, 1)
+	suite.Equal("v1", history[0].Title)
+	suite.Equal("first", history[0].Content)
+	suite.Equal([]string{"a"}, history[0].Tags)
+
+	// 復元すると置き換えられた v2 も新しい履歴として残る
+	reverted, err := suite.usecase.RevertMemo(ctx, created.ID, history[0].ID)
+	suite.Require().NoError(err)
+	suite.Equal("v1", reverted.Title)
+	suite.Equal("first", reverted.Content)
+	suite.Equal(3, reverted.Version)
+
+	history, err = suite.usecase.ListMemoHistory(ctx, created.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(history, 2)
+	suite.Equal("v2", history[0].Title)
+
+	// 他のメモのリビジョンや他のユーザーのメモは見つからない扱い
+	another, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Another", Content: "Content"})
+	suite.Require().NoError(err)
+	_, err = suite.usecase.RevertMemo(ctx, another.ID, history[0].ID)
+	suite.Equal(usecase.ErrRevisionNotFound, err)
+
+	otherCtx := domain.WithUserID(context.Background(), suite.createUser("history_other"))
+	_, err = suite.usecase.ListMemoHistory(otherCtx, created.ID)
+	suite.Equal(usecase.ErrMemoNotFound, err)
+	_, err = suite.usecase.RevertMemo(otherCtx, created.ID, history[0].ID)
+	suite.Equal(usecase.ErrMemoNotFound, err)
+}
+
+func (suite *MemoIntegrationTestSuite) TestMemoStats_PerUser() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+	emptyCtx := domain.WithUserID(context.Background(), suite.createUser("stats_empty"))
+
+	// メモのないユーザーはすべて0
+	stats, err := suite.repo.Stats(emptyCtx)
+	suite.Require().NoError(err)
+	suite.Equal(domain.NewMemoStats(), stats)
+
+	_, err = suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "A", Content: "Content", Category: "work", Priority: "high"})
+	suite.Require().NoError(err)
+	archived, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "B", Content: "Content", Category: "work"})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.usecase.ArchiveMemo(ctx, archived.ID))
+	trashed, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "C", Content: "Content", Category: "home"})
+	suite.Require().NoError(err)
+	_, err = suite.usecase.TrashMemo(ctx, trashed.ID)
+	suite.Require().NoError(err)
+
+	stats, err = suite.usecase.GetMemoStats(ctx)
+	suite.Require().NoError(err)
+	suite.Equal(2, stats.Total)
+	suite.Equal(1, stats.ByStatus[domain.StatusActive])
+	suite.Equal(1, stats.ByStatus[domain.StatusArchived])
+	suite.Equal(1, stats.ByStatus[domain.StatusTrashed])
+	suite.Equal(1, stats.ByPriority[domain.PriorityHigh])
+	suite.Equal(1, stats.ByPriority[domain.PriorityMedium])
+	suite.Equal(map[string]int{"work": 2}, stats.ByCategory)
+	suite.Equal(2, stats.CreatedLast7Days)
+	suite.Equal(2, stats.CreatedLast30Days)
+
+	// 他のユーザーのメモは数えない
+	stats, err = suite.repo.Stats(emptyCtx)
+	suite.Require().NoError(err)
+	suite.Equal(0, stats.Total)
+
+	// 未認証の場合はすべてのメモを数える
+	stats, err = suite.usecase.GetMemoStats(context.Background())
+	suite.Require().NoError(err)
+	suite.GreaterOrEqual(stats.Total, 2)
+}
+
+func (suite *MemoIntegrationTestSuite) TestUpdateMemoMetadata_LeavesContentUntouched() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+	due := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+	created, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{
+		Title: "Title", Content: "Original content", Category: "work", Tags: []string{"old"}, Priority: "low",
+	})
+	suite.Require().NoError(err)
+
+	updated, err := suite.usecase.UpdateMemoMetadata(ctx, created.ID, usecase.UpdateMemoMetadataRequest{
+		Tags:    []string{"new"},
+		Color:   stringPtr("#123abc"),
+		DueDate: &due,
+	})
+	suite.Require().NoError(err)
+	suite.Equal("Title", updated.Title)
+	suite.Equal("Original content", updated.Content)
+	suite.Equal("work", updated.Category)
+	suite.Equal(domain.PriorityLow, updated.Priority)
+	suite.Equal([]string{"new"}, updated.Tags)
+	suite.Equal("#123abc", updated.Color)
+	suite.Require().NotNil(updated.DueDate)
+	suite.True(due.Equal(*updated.DueDate))
+
+	// 空文字の色は未設定に戻す
+	cleared, err := suite.usecase.UpdateMemoMetadata(ctx, created.ID, usecase.UpdateMemoMetadataRequest{Color: stringPtr("")})
+	suite.Require().NoError(err)
+	suite.Empty(cleared.Color)
+	suite.Equal([]string{"new"}, cleared.Tags)
+	suite.Require().NotNil(cleared.DueDate)
+
+	// 期限は明示的に解除できる
+	cleared, err = suite.usecase.UpdateMemoMetadata(ctx, created.ID, usecase.UpdateMemoMetadataRequest{ClearDueDate: true})
+	suite.Require().NoError(err)
+	suite.Nil(cleared.DueDate)
+
+	// 更新前のタグは Update と同じく履歴に残る
+	history, err := suite.usecase.ListMemoHistory(ctx, created.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(history, 1)
+	suite.Equal([]string{"old"}, history[0].Tags)
+
+	// 他のユーザーのメモは更新できない
+	otherCtx := domain.WithUserID(context.Background(), suite.createUser("metadata_other"))
+	_, err = suite.usecase.UpdateMemoMetadata(otherCtx, created.ID, usecase.UpdateMemoMetadataRequest{Color: stringPtr("#000000")})
+	suite.Equal(usecase.ErrMemoNotFound, err)
+}
+
+func (suite *MemoIntegrationTestSuite) TestImportMemos_Atomic() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+	valid := usecase.CreateMemoRequest{Title: "Imported", Content: "Content", Tags: []string{"import"}}
+
+	// 1件でも不正なメモがあれば何も作成しない
+	result, err := suite.usecase.ImportMemos(ctx, []usecase.CreateMemoRequest{valid, {Content: "No title"}}, true)
+	suite.Require().NoError(err)
+	suite.Equal(0, result.Imported)
+	suite.Len(result.Failed, 1)
+
+	memos, total, err := suite.usecase.ListMemos(ctx, domain.MemoFilter{Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Equal(0, total)
+	suite.Empty(memos)
+
+	result, err = suite.usecase.ImportMemos(ctx, []usecase.CreateMemoRequest{valid, valid}, true)
+	suite.Require().NoError(err)
+	suite.Equal(2, result.Imported)
+
+	memos, total, err = suite.usecase.ListMemos(ctx, domain.MemoFilter{Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Equal(2, total)
+	for _, memo := range memos {
+		suite.Equal([]string{"import"}, memo.Tags)
+	}
+}
+
+func (suite *MemoIntegrationTestSuite) TestDueDateFilters() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+	yesterday := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	nextWeek := time.Now().Add(7 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	overdue, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Overdue", Content: "Content", DueDate: &yesterday})
+	suite.Require().NoError(err)
+	suite.Require().NotNil(overdue.DueDate)
+	suite.True(yesterday.Equal(*overdue.DueDate))
+
+	upcoming, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Upcoming", Content: "Content", DueDate: &nextWeek})
+	suite.Require().NoError(err)
+	_, err = suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "No Due Date", Content: "Content"})
+	suite.Require().NoError(err)
+
+	// 期限切れでもアーカイブ済みのメモは対象外
+	done, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Done", Content: "Content", DueDate: &yesterday})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.usecase.ArchiveMemo(ctx, done.ID))
+
+	memos, total, err := suite.usecase.ListMemos(ctx, domain.MemoFilter{Overdue: true, Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Equal(1, total)
+	suite.Require().Len(memos, 1)
+	suite.Equal(overdue.ID, memos[0].ID)
+
+	after := time.Now()
+	memos, total, err = suite.usecase.ListMemos(ctx, domain.MemoFilter{DueAfter: &after, Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Equal(1, total)
+	suite.Require().Len(memos, 1)
+	suite.Equal(upcoming.ID, memos[0].ID)
+
+	before := time.Now()
+	_, total, err = suite.usecase.ListMemos(ctx, domain.MemoFilter{DueBefore: &before, Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Equal(2, total)
+}
+
+func (suite *MemoIntegrationTestSuite) TestPinnedFirstOrdering() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+
+	pinned, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Pinned", Content: "Content"})
+	suite.Require().NoError(err)
+	newer, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Newer", Content: "Content"})
+	suite.Require().NoError(err)
+
+	memo, err := suite.usecase.PinMemo(ctx, pinned.ID)
+	suite.Require().NoError(err)
+	suite.True(memo.Pinned)
+
+	memos, _, err := suite.usecase.ListMemos(ctx, domain.MemoFilter{Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Require().Len(memos, 2)
+	suite.Equal(pinned.ID, memos[0].ID)
+	suite.Equal(newer.ID, memos[1].ID)
+
+	// アーカイブと復元を経てもピン留めは保持される
+	suite.Require().NoError(suite.usecase.ArchiveMemo(ctx, pinned.ID))
+	suite.Require().NoError(suite.usecase.RestoreMemo(ctx, pinned.ID))
+	restored, err := suite.usecase.GetMemo(ctx, pinned.ID)
+	suite.Require().NoError(err)
+	suite.True(restored.Pinned)
+
+	// 他のユーザーはピン留めを変更できない
+	otherCtx := domain.WithUserID(context.Background(), suite.createUser("pin_other"))
+	_, err = suite.usecase.UnpinMemo(otherCtx, pinned.ID)
+	suite.ErrorIs(err, usecase.ErrMemoNotFound)
+
+	memo, err = suite.usecase.UnpinMemo(ctx, pinned.ID)
+	suite.Require().NoError(err)
+	suite.False(memo.Pinned)
+}
+
+func (suite *MemoIntegrationTestSuite) TestListOnThisDay() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+	otherCtx := domain.WithUserID(context.Background(), suite.createUser("on_this_day_other"))
+	today := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	seed := func(ctx context.Context, title string, createdAt time.Time) int {
+		memo, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: title, Content: "Content"})
+		suite.Require().NoError(err)
+		_, err = suite.db.ExecContext(context.Background(),
+			`UPDATE memos SET created_at = $2 WHERE id = $1`, memo.ID, createdAt)
+		suite.Require().NoError(err)
+		return memo.ID
+	}
+
+	lastYear := seed(ctx, "Last Year", today.AddDate(-1, 0, 0))
+	fourYearsAgo := seed(ctx, "Four Years Ago", today.AddDate(-4, 0, 0))
+	seed(ctx, "Today", today)
+	seed(ctx, "Next Day Last Year", today.AddDate(-1, 0, 1))
+	seed(ctx, "Previous Month Last Year", today.AddDate(-1, -1, 0))
+	trashed := seed(ctx, "Trashed", today.AddDate(-2, 0, 0))
+	_, err := suite.usecase.TrashMemo(ctx, trashed)
+	suite.Require().NoError(err)
+	seed(otherCtx, "Other User", today.AddDate(-3, 0, 0))
+
+	memos, err := suite.repo.ListOnThisDay(ctx, today)
+	suite.Require().NoError(err)
+	suite.Require().Len(memos, 2)
+	suite.Equal(lastYear, memos[0].ID)
+	suite.Equal(fourYearsAgo, memos[1].ID)
+}
+
+func (suite *MemoIntegrationTestSuite) TestListCategories() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+	otherCtx := domain.WithUserID(context.Background(), suite.createUser("categories_other"))
+
+	for _, category := range []string{"work", "personal", "work", ""} {
+		_, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Memo", Content: "Content", Category: category})
+		suite.Require().NoError(err)
+	}
+	_, err := suite.usecase.CreateMemo(otherCtx, usecase.CreateMemoRequest{Title: "Memo", Content: "Content", Category: "secret"})
+	suite.Require().NoError(err)
+
+	categories, err := suite.usecase.ListCategories(ctx)
+	suite.Require().NoError(err)
+	suite.Equal([]string{"personal", "work"}, categories)
+
+	categories, err = suite.usecase.ListCategories(otherCtx)
+	suite.Require().NoError(err)
+	suite.Equal([]string{"secret"}, categories)
+
+	// 未認証の場合は ListTags と同じくすべてのメモのカテゴリーを返す
+	categories, err = suite.usecase.ListCategories(context.Background())
+	suite.Require().NoError(err)
+	suite.Subset(categories, []string{"personal", "secret", "work"})
+}
+
+// countingPublisher は通知されたメモのイベント数を数える
+type countingPublisher struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (p *countingPublisher) PublishMemoEvent(ctx context.Context, event string, memo *domain.Memo) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.count++
+}
+
+func (suite *MemoIntegrationTestSuite) TestDeleteMemo_ConcurrentSameMemo() {
+	ctx := domain.WithUserID(context.Background(), suite.testUserID)
+	memo, err := suite.usecase.CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Hammered", Content: "Content"})
+	suite.Require().NoError(err)
+
+	// イベントを通知するユースケースで同じメモを同時に削除する
+	publisher := &countingPublisher{}
+	uc := usecase.NewMemoUsecaseWithPublisher(suite.repo, nil, publisher)
+
+	const workers = 20
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- uc.DeleteMemo(ctx, memo.ID)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// アーカイブとゴミ箱への移動の2件だけが成功し、残りはゴミ箱にあるため拒否される
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		suite.Equal(usecase.ErrMemoAlreadyTrashed, err)
+	}
+	suite.Equal(2, succeeded)
+	suite.Equal(2, publisher.count)
+
+	trashed, err := suite.usecase.GetMemo(ctx, memo.ID)
+	suite.Require().NoError(err)
+	suite.Equal(domain.StatusTrashed, trashed.Status)
+}
+
 func TestMemoIntegrationTestSuite(t *testing.T) {
 	// 統合テストはデータベース接続が必要なため、実際の環境でのみ実行
 	if testing.Short() {
@@ -363,11 +1428,81 @@ func (suite *MemoIntegrationTestSuite) createTablesIfNotExists() {
 		category VARCHAR(50),
 		tags JSONB DEFAULT '[]'::jsonb,
 		priority VARCHAR(10) NOT NULL DEFAULT 'medium' CHECK (priority IN ('low', 'medium', 'high')),
-		status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'archived')),
+		status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'archived', 'trashed')),
+		pinned BOOLEAN NOT NULL DEFAULT false,
 		user_id INTEGER DEFAULT 1,
 		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
 		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
-		completed_at TIMESTAMP WITH TIME ZONE
+		completed_at TIMESTAMP WITH TIME ZONE,
+		trashed_at TIMESTAMP WITH TIME ZONE,
+		due_date TIMESTAMP WITH TIME ZONE,
+		remind_at TIMESTAMP WITH TIME ZONE,
+		reminded BOOLEAN NOT NULL DEFAULT false,
+		color VARCHAR(7),
+		version INTEGER NOT NULL DEFAULT 1,
+		search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce(content, '')), 'B')
+		) STORED
+	);`
+
+	// memo_revisions テーブルの作成（メモの編集履歴）
+	memoRevisionsSQL := `
+	CREATE TABLE IF NOT EXISTS memo_revisions (
+		id SERIAL PRIMARY KEY,
+		memo_id INTEGER NOT NULL REFERENCES memos(id) ON DELETE CASCADE,
+		title VARCHAR(200) NOT NULL,
+		content TEXT NOT NULL,
+		category VARCHAR(50),
+		tags JSONB DEFAULT '[]'::jsonb,
+		priority VARCHAR(10) NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	// search_queries テーブルの作成（最近の検索クエリ履歴）
+	searchQueriesSQL := `
+	CREATE TABLE IF NOT EXISTS search_queries (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		query VARCHAR(200) NOT NULL,
+		searched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		UNIQUE (user_id, query)
+	);`
+
+	// memo_shares テーブルの作成（ユーザー間のメモ共有）
+	memoSharesSQL := `
+	CREATE TABLE IF NOT EXISTS memo_shares (
+		id SERIAL PRIMARY KEY,
+		memo_id INTEGER NOT NULL REFERENCES memos(id) ON DELETE CASCADE,
+		shared_with_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		permission VARCHAR(10) NOT NULL DEFAULT 'read' CHECK (permission IN ('read', 'write')),
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		UNIQUE (memo_id, shared_with_user_id)
+	);`
+
+	// deleted_memos テーブルの作成（完全削除したメモのリサイクルログ）
+	deletedMemosSQL := `
+	CREATE TABLE IF NOT EXISTS deleted_memos (
+		id SERIAL PRIMARY KEY,
+		memo_id INTEGER NOT NULL,
+		user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+		title VARCHAR(200) NOT NULL,
+		content TEXT NOT NULL,
+		category VARCHAR(50),
+		tags JSONB DEFAULT '[]'::jsonb,
+		priority VARCHAR(10) NOT NULL,
+		status VARCHAR(20) NOT NULL,
+		pinned BOOLEAN NOT NULL DEFAULT false,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		completed_at TIMESTAMP WITH TIME ZONE,
+		trashed_at TIMESTAMP WITH TIME ZONE,
+		due_date TIMESTAMP WITH TIME ZONE,
+		remind_at TIMESTAMP WITH TIME ZONE,
+		reminded BOOLEAN NOT NULL DEFAULT false,
+		color VARCHAR(7),
+		version INTEGER NOT NULL DEFAULT 1,
+		deleted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
 	);`
 
 	// インデックスの作成
@@ -387,10 +1522,34 @@ func (suite *MemoIntegrationTestSuite) createTablesIfNotExists() {
 	_, err = suite.db.ExecContext(ctx, memosSQL)
 	suite.Require().NoError(err, "Failed to create memos table")
 
+	_, err = suite.db.ExecContext(ctx, memoRevisionsSQL)
+	suite.Require().NoError(err, "Failed to create memo_revisions table")
+
+	_, err = suite.db.ExecContext(ctx, searchQueriesSQL)
+	suite.Require().NoError(err, "Failed to create search_queries table")
+
+	_, err = suite.db.ExecContext(ctx, memoSharesSQL)
+	suite.Require().NoError(err, "Failed to create memo_shares table")
+
+	_, err = suite.db.ExecContext(ctx, deletedMemosSQL)
+	suite.Require().NoError(err, "Failed to create deleted_memos table")
+
 	_, err = suite.db.ExecContext(ctx, indexSQL)
 	suite.Require().NoError(err, "Failed to create indexes")
 }
 
+// createUser は指定したユーザー名のユーザーを作成し（存在する場合は既存のユーザーを使い）、IDを返します
+func (suite *MemoIntegrationTestSuite) createUser(username string) int {
+	var userID int
+	err := suite.db.QueryRowContext(context.Background(), `
+	INSERT INTO users (username, email, password_hash, created_ip)
+	VALUES ($1, $1 || '@example.com', 'hashed_password', '127.0.0.1')
+	ON CONFLICT (username) DO UPDATE SET username = EXCLUDED.username
+	RETURNING id`, username).Scan(&userID)
+	suite.Require().NoError(err)
+	return userID
+}
+
 // createTestUser は テストユーザーを作成し、JWTトークンを生成します
 func (suite *MemoIntegrationTestSuite) createTestUser() {
 	ctx := context.Background()
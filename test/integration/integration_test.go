@@ -9,6 +9,8 @@ import (
 	"testing"
 	"time"
 
+	"memo-app/src/buildinfo"
+	"memo-app/src/handlers"
 	"memo-app/src/logger"
 	"memo-app/src/middleware"
 
@@ -67,7 +69,8 @@ func TestAPIEndpoints(t *testing.T) {
 				err := json.Unmarshal(body, &response)
 				require.NoError(t, err)
 				assert.Equal(t, "Hello World", response["message"])
-				assert.Equal(t, "2.0", response["version"])
+				assert.NotContains(t, response, "version")
+				assert.Contains(t, response, "build")
 				assert.Equal(t, "memo-app-api-server", response["service"])
 			},
 		},
@@ -334,19 +337,13 @@ func setupTestRouter() *gin.Engine {
 	// ミドルウェアを適用
 	r.Use(middleware.LoggerMiddleware())
 	r.Use(middleware.CORSMiddleware())
-	r.Use(middleware.RateLimitMiddleware())
+	// 連続リクエストを送るパフォーマンステストが制限に掛からないよう上限を緩める
+	r.Use(middleware.RateLimitMiddlewareWithConfig(middleware.RateLimitConfig{RPS: 1000, Burst: 1000}))
 
 	// パブリックルート
 	public := r.Group("/")
 	{
-		public.GET("/", func(c *gin.Context) {
-			logger.WithField("endpoint", "/").Info("Hello Worldエンドポイントにアクセス")
-			c.JSON(http.StatusOK, gin.H{
-				"message": "Hello World",
-				"version": "2.0",
-				"service": "memo-app-api-server",
-			})
-		})
+		public.GET("/", handlers.NewRootHandler(buildinfo.Get()).Root)
 
 		// サポートされていないHTTPメソッドのハンドラー（405エラー）
 		public.POST("/", func(c *gin.Context) {
@@ -0,0 +1,222 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"memo-app/src/domain"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// MemoFilterMatrixTestSuite exercises the list filters of the repository against a real Postgres,
+// checking both the returned rows and the total count so the COUNT query cannot drift from the SELECT.
+type MemoFilterMatrixTestSuite struct {
+	suite.Suite
+	base *MemoIntegrationTestSuite
+	ctx  context.Context
+	now  time.Time
+}
+
+// filterMatrixSeed is one memo inserted before the matrix runs
+type filterMatrixSeed struct {
+	title    string
+	category string
+	tags     []string
+	priority domain.Priority
+	status   domain.Status
+	due      *time.Duration // now からの相対時間（nilの場合は期限なし）
+	created  time.Duration
+	updated  time.Duration
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+// filterMatrixSeeds はタイトル順・ID順・作成日時順が一致し、更新日時順はその逆になるように並べている
+var filterMatrixSeeds = []filterMatrixSeed{
+	{title: "A", category: "work", tags: []string{"go", "db"}, priority: domain.PriorityHigh, status: domain.StatusActive,
+		due: durationPtr(24 * time.Hour), created: -5 * time.Hour, updated: -1 * time.Minute},
+	{title: "B", category: "work", tags: []string{"go"}, priority: domain.PriorityMedium, status: domain.StatusActive,
+		due: durationPtr(10 * 24 * time.Hour), created: -4 * time.Hour, updated: -2 * time.Minute},
+	{title: "C", category: "personal", tags: []string{"db"}, priority: domain.PriorityLow, status: domain.StatusArchived,
+		due: durationPtr(-2 * 24 * time.Hour), created: -3 * time.Hour, updated: -3 * time.Minute},
+	{title: "D", category: "personal", tags: []string{}, priority: domain.PriorityMedium, status: domain.StatusActive,
+		created: -2 * time.Hour, updated: -4 * time.Minute},
+	{title: "E", category: "work", tags: []string{"go", "db", "api"}, priority: domain.PriorityLow, status: domain.StatusTrashed,
+		due: durationPtr(3 * 24 * time.Hour), created: -1 * time.Hour, updated: -5 * time.Minute},
+}
+
+func (s *MemoFilterMatrixTestSuite) SetupSuite() {
+	// 接続・スキーマ作成はクリーンアーキテクチャ統合テストと共通
+	s.base = new(MemoIntegrationTestSuite)
+	s.base.SetT(s.T())
+	s.base.SetupSuite()
+	if s.base.db == nil {
+		return
+	}
+
+	s.now = time.Now()
+	s.ctx = domain.WithUserID(context.Background(), s.base.createUser("filter_matrix"))
+	s.seed()
+}
+
+func (s *MemoFilterMatrixTestSuite) TearDownSuite() {
+	if s.base != nil {
+		s.base.TearDownSuite()
+	}
+}
+
+func (s *MemoFilterMatrixTestSuite) SetupTest() {
+	if s.base == nil || s.base.db == nil {
+		s.T().Skip("データベースが利用可能でないため、テストをスキップします")
+	}
+}
+
+// seed はこのスイート専用ユーザーのメモを作り直す（状態・日時を直接指定するためSQLで挿入）
+func (s *MemoFilterMatrixTestSuite) seed() {
+	userID, _ := domain.UserIDFromContext(s.ctx)
+	_, err := s.base.db.ExecContext(context.Background(), "DELETE FROM memos WHERE user_id = $1", userID)
+	s.Require().NoError(err)
+
+	for _, seed := range filterMatrixSeeds {
+		tagsJSON, err := json.Marshal(seed.tags)
+		s.Require().NoError(err)
+
+		var due *time.Time
+		if seed.due != nil {
+			t := s.now.Add(*seed.due)
+			due = &t
+		}
+		_, err = s.base.db.ExecContext(context.Background(), `
+		INSERT INTO memos (title, content, category, tags, priority, status, created_at, updated_at, user_id, due_date)
+		VALUES ($1, 'content', $2, $3::jsonb, $4, $5, $6, $7, $8, $9)`,
+			seed.title, seed.category, string(tagsJSON), string(seed.priority), string(seed.status),
+			s.now.Add(seed.created), s.now.Add(seed.updated), userID, due)
+		s.Require().NoError(err)
+	}
+}
+
+// list runs the filter and returns the titles in result order along with the total
+func (s *MemoFilterMatrixTestSuite) list(filter domain.MemoFilter) ([]string, int) {
+	memos, total, err := s.base.repo.List(s.ctx, filter)
+	s.Require().NoError(err)
+
+	titles := make([]string, len(memos))
+	for i, memo := range memos {
+		titles[i] = memo.Title
+	}
+	return titles, total
+}
+
+func (s *MemoFilterMatrixTestSuite) TestFilterMatrix() {
+	before := func(d time.Duration) *time.Time {
+		t := s.now.Add(d)
+		return &t
+	}
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		filter   domain.MemoFilter
+		expected []string
+	}{
+		{name: "no filter excludes trashed", expected: []string{"A", "B", "C", "D"}},
+		{name: "single tag", filter: domain.MemoFilter{Tags: []string{"go"}}, expected: []string{"A", "B"}},
+		{name: "all tags (AND)", filter: domain.MemoFilter{Tags: []string{"go", "db"}}, expected: []string{"A"}},
+		{name: "unknown tag", filter: domain.MemoFilter{Tags: []string{"rust"}}, expected: []string{}},
+		{name: "tag and archived", filter: domain.MemoFilter{Tags: []string{"db"}, Status: domain.StatusArchived}, expected: []string{"C"}},
+		{name: "tags and trashed", filter: domain.MemoFilter{Tags: []string{"go", "db"}, Status: domain.StatusTrashed}, expected: []string{"E"}},
+		{name: "active", filter: domain.MemoFilter{Status: domain.StatusActive}, expected: []string{"A", "B", "D"}},
+		{name: "archived", filter: domain.MemoFilter{Status: domain.StatusArchived}, expected: []string{"C"}},
+		{name: "trashed", filter: domain.MemoFilter{Status: domain.StatusTrashed}, expected: []string{"E"}},
+		{name: "due range", filter: domain.MemoFilter{DueAfter: before(0), DueBefore: before(5 * day)}, expected: []string{"A"}},
+		{name: "due range including past", filter: domain.MemoFilter{DueAfter: before(-3 * day), DueBefore: before(2 * day)}, expected: []string{"A", "C"}},
+		{name: "due after and trashed", filter: domain.MemoFilter{DueAfter: before(0), Status: domain.StatusTrashed}, expected: []string{"E"}},
+		{name: "due before only", filter: domain.MemoFilter{DueBefore: before(2 * day)}, expected: []string{"A", "C"}},
+		{name: "tag and due range", filter: domain.MemoFilter{Tags: []string{"go"}, DueAfter: before(2 * day)}, expected: []string{"B"}},
+		{name: "category and priority", filter: domain.MemoFilter{Category: "work", Priority: domain.PriorityMedium}, expected: []string{"B"}},
+		{name: "category and tag", filter: domain.MemoFilter{Category: "personal", Tags: []string{"db"}}, expected: []string{"C"}},
+		{name: "overdue ignores archived", filter: domain.MemoFilter{Overdue: true}, expected: []string{}},
+		{name: "search and status", filter: domain.MemoFilter{Search: "content", Status: domain.StatusArchived}, expected: []string{"C"}},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// 作成日時の昇順で比較できるように並び順を固定する
+			filter := tt.filter
+			filter.Sort = []domain.SortField{{Field: "created_at"}}
+
+			filter.Page, filter.Limit = 1, 100
+			titles, total := s.list(filter)
+			s.Equal(tt.expected, titles)
+			s.Equal(len(tt.expected), total)
+
+			// ページを絞っても総数は変わらない
+			filter.Limit = 1
+			titles, total = s.list(filter)
+			s.Equal(len(tt.expected), total)
+			s.LessOrEqual(len(titles), 1)
+		})
+	}
+}
+
+func (s *MemoFilterMatrixTestSuite) TestSortMatrix() {
+	// 同順位はid降順（D, C, B, A の順）で並ぶ
+	tests := []struct {
+		field string
+		asc   []string
+		desc  []string
+	}{
+		{field: "id", asc: []string{"A", "B", "C", "D"}, desc: []string{"D", "C", "B", "A"}},
+		{field: "title", asc: []string{"A", "B", "C", "D"}, desc: []string{"D", "C", "B", "A"}},
+		{field: "category", asc: []string{"D", "C", "B", "A"}, desc: []string{"B", "A", "D", "C"}},
+		{field: "priority", asc: []string{"C", "D", "B", "A"}, desc: []string{"A", "D", "B", "C"}},
+		{field: "status", asc: []string{"D", "B", "A", "C"}, desc: []string{"C", "D", "B", "A"}},
+		{field: "created_at", asc: []string{"A", "B", "C", "D"}, desc: []string{"D", "C", "B", "A"}},
+		{field: "updated_at", asc: []string{"D", "C", "B", "A"}, desc: []string{"A", "B", "C", "D"}},
+	}
+
+	for _, tt := range tests {
+		for _, desc := range []bool{false, true} {
+			expected := tt.asc
+			if desc {
+				expected = tt.desc
+			}
+			s.Run(fmt.Sprintf("%s desc=%t", tt.field, desc), func() {
+				filter := domain.MemoFilter{Page: 1, Limit: 100, Sort: []domain.SortField{{Field: tt.field, Desc: desc}}}
+				titles, total := s.list(filter)
+				s.Equal(expected, titles)
+				s.Equal(4, total)
+
+				// 2ページ目は同じ並び順の続きになる
+				filter.Page, filter.Limit = 2, 2
+				titles, total = s.list(filter)
+				s.Equal(expected[2:], titles)
+				s.Equal(4, total)
+			})
+		}
+	}
+
+	s.Run("multiple keys", func() {
+		filter := domain.MemoFilter{Page: 1, Limit: 100, Sort: []domain.SortField{
+			{Field: "category", Desc: true},
+			{Field: "priority"},
+		}}
+		titles, _ := s.list(filter)
+		s.Equal([]string{"B", "A", "C", "D"}, titles)
+	})
+}
+
+func TestMemoFilterMatrixTestSuite(t *testing.T) {
+	// 実際のPostgreSQLが必要なため、shortモードではスキップ
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	suite.Run(t, new(MemoFilterMatrixTestSuite))
+}
@@ -198,10 +198,44 @@ func (m *mockMemoRepository) Update(ctx context.Context, id int, req *models.Upd
 }
 
 func (m *mockMemoRepository) Delete(ctx context.Context, id int) error {
-	_, exists := m.memos[id]
+	memo, exists := m.memos[id]
 	if !exists {
 		return fmt.Errorf("memo not found")
 	}
+	// 実際のリポジトリと同じく1段階ずつ進め、行は削除しない
+	now := time.Now()
+	switch memo.Status {
+	case "trashed":
+		return fmt.Errorf("memo is already in trash")
+	case "archived":
+		memo.Status = "trashed"
+		memo.TrashedAt = &now
+	default:
+		memo.Status = "archived"
+		memo.CompletedAt = &now
+	}
+	return nil
+}
+
+func (m *mockMemoRepository) Trash(ctx context.Context, id int) (*models.Memo, error) {
+	memo, exists := m.memos[id]
+	if !exists {
+		return nil, fmt.Errorf("memo not found")
+	}
+	now := time.Now()
+	memo.Status = "trashed"
+	memo.TrashedAt = &now
+	return memo, nil
+}
+
+func (m *mockMemoRepository) PermanentDelete(ctx context.Context, id int) error {
+	memo, exists := m.memos[id]
+	if !exists {
+		return fmt.Errorf("memo not found")
+	}
+	if memo.Status != "trashed" {
+		return fmt.Errorf("memo is not in trash")
+	}
 	delete(m.memos, id)
 	return nil
 }
@@ -376,14 +410,15 @@ func (suite *MemoIntegrationTestSuite) TestDeleteMemo() {
 	// ステータスコードを確認（204 No Content）
 	assert.Equal(suite.T(), http.StatusNoContent, w.Code)
 
-	// メモが実際に削除されていることを確認
+	// 削除は物理削除せず、アクティブなメモをアーカイブする
 	getReq, err := http.NewRequest("GET", "/api/memos/"+strconv.Itoa(memo.ID), nil)
 	require.NoError(suite.T(), err)
 
 	getW := httptest.NewRecorder()
 	suite.router.ServeHTTP(getW, getReq)
 
-	assert.Equal(suite.T(), http.StatusNotFound, getW.Code)
+	assert.Equal(suite.T(), http.StatusOK, getW.Code)
+	assert.Contains(suite.T(), getW.Body.String(), `"status":"archived"`)
 }
 
 // 存在しないメモの取得テスト
@@ -10,7 +10,9 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"memo-app/src/buildinfo"
 	"memo-app/src/domain"
+	"memo-app/src/handlers"
 	// "memo-app/src/interface/handler" // 現在は使用されていない
 	// "memo-app/src/logger" // 現在は使用されていない
 	"memo-app/src/middleware"
@@ -35,6 +37,74 @@ func (m *MockMemoUsecase) CreateMemo(ctx context.Context, req usecase.CreateMemo
 	return args.Get(0).(*domain.Memo), args.Error(1)
 }
 
+func (m *MockMemoUsecase) ImportMemosWithIDs(ctx context.Context, reqs []usecase.ImportMemoRequest) ([]domain.Memo, error) {
+	args := m.Called(ctx, reqs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ExportMemos(ctx context.Context, fn func(*domain.Memo) error) error {
+	args := m.Called(ctx, fn)
+	if memos, ok := args.Get(0).([]domain.Memo); ok {
+		for i := range memos {
+			if err := fn(&memos[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockMemoUsecase) ImportMemos(ctx context.Context, reqs []usecase.CreateMemoRequest, atomic bool) (*usecase.ImportResult, error) {
+	args := m.Called(ctx, reqs, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ImportResult), args.Error(1)
+}
+
+func (m *MockMemoUsecase) UpdateMemoMetadata(ctx context.Context, id int, req usecase.UpdateMemoMetadataRequest) (*domain.Memo, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) GetMemoStats(ctx context.Context) (*domain.MemoStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoStats), args.Error(1)
+}
+
+func (m *MockMemoUsecase) GetMemoListState(ctx context.Context) (*domain.MemoListState, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoListState), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ListMemoHistory(ctx context.Context, id int) ([]domain.MemoRevision, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MemoRevision), args.Error(1)
+}
+
+func (m *MockMemoUsecase) RevertMemo(ctx context.Context, id, revisionID int) (*domain.Memo, error) {
+	args := m.Called(ctx, id, revisionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
 func (m *MockMemoUsecase) GetMemo(ctx context.Context, id int) (*domain.Memo, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -61,6 +131,75 @@ func (m *MockMemoUsecase) DeleteMemo(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
+func (m *MockMemoUsecase) BulkDeleteMemos(ctx context.Context, ids []int) ([]usecase.BulkResult, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]usecase.BulkResult), args.Error(1)
+}
+
+func (m *MockMemoUsecase) BulkUpdateMemos(ctx context.Context, ids []int, req usecase.UpdateMemoRequest) (*usecase.BulkUpdateResult, error) {
+	args := m.Called(ctx, ids, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.BulkUpdateResult), args.Error(1)
+}
+
+func (m *MockMemoUsecase) TrashMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) PermanentDeleteMemo(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMemoUsecase) ListDeletedMemos(ctx context.Context, filter domain.MemoFilter) ([]domain.DeletedMemo, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]domain.DeletedMemo), args.Int(1), args.Error(2)
+}
+
+func (m *MockMemoUsecase) RestoreDeletedMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ListSharedMemos(ctx context.Context, filter domain.MemoFilter) ([]domain.SharedMemo, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]domain.SharedMemo), args.Int(1), args.Error(2)
+}
+
+func (m *MockMemoUsecase) ListCategories(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ListOnThisDay(ctx context.Context) ([]domain.Memo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Memo), args.Error(1)
+}
+
 func (m *MockMemoUsecase) ArchiveMemo(ctx context.Context, id int) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -76,6 +215,67 @@ func (m *MockMemoUsecase) SearchMemos(ctx context.Context, query string, filter
 	return args.Get(0).([]domain.Memo), args.Get(1).(int), args.Error(2)
 }
 
+func (m *MockMemoUsecase) PromoteMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) TouchMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) PinMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) UnpinMemo(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoUsecase) GetMemoDetail(ctx context.Context, id int, expand domain.MemoExpand) (*domain.MemoDetail, error) {
+	args := m.Called(ctx, id, expand)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoDetail), args.Error(1)
+}
+
+func (m *MockMemoUsecase) RecentSearchQueries(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMemoUsecase) ClearRecentSearchQueries(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockMemoUsecase) ListTags(ctx context.Context, filter domain.TagFilter) ([]domain.TagCount, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TagCount), args.Error(1)
+}
+
 // Setup test router with mocks and middleware
 func setupMockIntegrationRouter(mockUsecase *MockMemoUsecase) *gin.Engine {
 	gin.SetMode(gin.TestMode)
@@ -90,13 +290,7 @@ func setupMockIntegrationRouter(mockUsecase *MockMemoUsecase) *gin.Engine {
 	// memoHandler := handler.NewMemoHandler(mockUsecase, logger) // 現在は使用されていない
 
 	// Basic routes
-	r.GET("/", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Hello World",
-			"version": "2.0",
-			"service": "memo-app-api-server",
-		})
-	})
+	r.GET("/", handlers.NewRootHandler(buildinfo.Get()).Root)
 
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -269,6 +463,7 @@ func TestMockIntegrationSuite(t *testing.T) {
 		router := setupMockIntegrationRouter(mockUsecase)
 
 		// Mock setup
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
 		mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{
 			{
 				ID:      1,
@@ -323,6 +518,7 @@ func TestMockIntegrationSuite(t *testing.T) {
 		router := setupMockIntegrationRouter(mockUsecase)
 
 		// Mock setup for ListMemos
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
 		mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{}, 0, nil)
 
 		req, _ := http.NewRequest("GET", "/api/memos", nil)
@@ -341,6 +537,7 @@ func TestMockIntegrationSuite(t *testing.T) {
 		router := setupMockIntegrationRouter(mockUsecase)
 
 		// Mock setup for ListMemos
+		mockUsecase.On("GetMemoListState", mock.Anything).Return(nil, nil).Maybe()
 		mockUsecase.On("ListMemos", mock.Anything, mock.AnythingOfType("domain.MemoFilter")).Return([]domain.Memo{}, 0, nil)
 
 		req, _ := http.NewRequest("GET", "/api/memos", nil)
@@ -383,8 +580,7 @@ func TestMockMiddlewareIntegration(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		// レート制限はまだ実装されていないため、ヘッダーテストを無効化
-		// 将来実装時に有効化予定
-		// assert.NotEmpty(t, w.Header().Get("X-RateLimit-Limit"))
+		assert.NotEmpty(t, w.Header().Get("X-RateLimit-Limit"))
+		assert.NotEmpty(t, w.Header().Get("X-RateLimit-Remaining"))
 	})
 }
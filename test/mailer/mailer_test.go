@@ -0,0 +1,126 @@
+package mailer_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"memo-app/src/mailer"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sentMail 記録された送信メール
+type sentMail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// RecordingMailer 送信内容を記録するテスト用Mailer
+type RecordingMailer struct {
+	mu   sync.Mutex
+	Sent []sentMail
+}
+
+func (m *RecordingMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Sent = append(m.Sent, sentMail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+func TestSendTemplate(t *testing.T) {
+	data := struct {
+		Username  string
+		Link      string
+		ExpiresIn string
+	}{
+		Username:  "testuser",
+		Link:      "https://example.com/api/auth/verify?token=abc",
+		ExpiresIn: "24時間",
+	}
+
+	tests := []struct {
+		name            string
+		template        *mailer.Template
+		expectedSubject string
+	}{
+		{
+			name:            "メールアドレス確認",
+			template:        mailer.EmailVerificationTemplate,
+			expectedSubject: "[Memo App] メールアドレスの確認",
+		},
+		{
+			name:            "パスワードリセット",
+			template:        mailer.PasswordResetTemplate,
+			expectedSubject: "[Memo App] パスワードの再設定",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &RecordingMailer{}
+
+			err := mailer.SendTemplate(context.Background(), recorder, "user@example.com", tt.template, data)
+			require.NoError(t, err)
+
+			require.Len(t, recorder.Sent, 1)
+			sent := recorder.Sent[0]
+			assert.Equal(t, "user@example.com", sent.To)
+			assert.Equal(t, tt.expectedSubject, sent.Subject)
+			assert.Contains(t, sent.Body, "testuser")
+			assert.Contains(t, sent.Body, data.Link)
+			assert.Contains(t, sent.Body, "24時間")
+		})
+	}
+
+	t.Run("テンプレートの描画に失敗した場合は送信しない", func(t *testing.T) {
+		recorder := &RecordingMailer{}
+
+		// 存在しないフィールドを参照するデータ
+		err := mailer.SendTemplate(context.Background(), recorder, "user@example.com", mailer.EmailVerificationTemplate, struct{}{})
+		assert.Error(t, err)
+		assert.Empty(t, recorder.Sent)
+	})
+}
+
+func TestNew(t *testing.T) {
+	logger := logrus.New()
+
+	t.Run("デフォルトはログ出力", func(t *testing.T) {
+		m, err := mailer.New("", nil, logger)
+		require.NoError(t, err)
+		assert.IsType(t, &mailer.LogMailer{}, m)
+		assert.NoError(t, m.Send(context.Background(), "user@example.com", "subject", "body"))
+	})
+
+	t.Run("SMTPドライバー", func(t *testing.T) {
+		m, err := mailer.New(mailer.DriverSMTP, &mailer.SMTPConfig{
+			Host: "smtp.example.com",
+			Port: 587,
+			From: "noreply@example.com",
+		}, logger)
+		require.NoError(t, err)
+		assert.IsType(t, &mailer.SMTPMailer{}, m)
+	})
+
+	t.Run("SMTPドライバーでホスト未設定はエラー", func(t *testing.T) {
+		_, err := mailer.New(mailer.DriverSMTP, &mailer.SMTPConfig{From: "noreply@example.com"}, logger)
+		assert.Error(t, err)
+	})
+
+	t.Run("不明なドライバーはエラー", func(t *testing.T) {
+		_, err := mailer.New("sendgrid", nil, logger)
+		assert.Error(t, err)
+	})
+}
+
+func TestSMTPMailer_RejectsHeaderInjection(t *testing.T) {
+	m := mailer.NewSMTPMailer(&mailer.SMTPConfig{Host: "localhost", Port: 25, From: "noreply@example.com"})
+
+	err := m.Send(context.Background(), "user@example.com\r\nBcc: attacker@example.com", "subject", "body")
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"memo-app/src/markdown"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_Render(t *testing.T) {
+	r := markdown.NewRenderer()
+
+	t.Run("Markdownを変換する", func(t *testing.T) {
+		rendered, err := r.Render("# 見出し\n\n**太字** と [リンク](https://example.com)\n\n- a\n- b")
+		require.NoError(t, err)
+		assert.Contains(t, rendered, "<h1>見出し</h1>")
+		assert.Contains(t, rendered, "<strong>太字</strong>")
+		assert.Contains(t, rendered, `href="https://example.com"`)
+		assert.Contains(t, rendered, "<li>a</li>")
+	})
+
+	malicious := []struct {
+		name      string
+		source    string
+		forbidden []string
+	}{
+		{name: "javascript: のリンク", source: "[click](javascript:alert(1))", forbidden: []string{"javascript:", "alert"}},
+		{name: "大文字・エンティティで隠したjavascript: のリンク", source: "[click](JaVaScRiPt&#58;alert(1))", forbidden: []string{"javascript", "JaVaScRiPt", "alert"}},
+		{name: "javascript: の自動リンク", source: "<javascript:alert(1)>", forbidden: []string{"href", "alert(1)</a>"}},
+		{name: "data: の画像", source: "![x](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)", forbidden: []string{"data:", "src="}},
+		{name: "埋め込みのscriptタグ", source: "text\n\n<script>alert(1)</script>", forbidden: []string{"<script", "alert(1)"}},
+		{name: "インラインのscriptタグ", source: "hello <script>alert(1)</script> world", forbidden: []string{"<script"}},
+		{name: "イベントハンドラ属性", source: `<img src="x" onerror="alert(1)">`, forbidden: []string{"onerror", "<img"}},
+		{name: "iframe", source: `<iframe src="https://evil.example"></iframe>`, forbidden: []string{"<iframe"}},
+		{name: "styleタグ", source: "<style>body{display:none}</style>", forbidden: []string{"<style", "display:none"}},
+	}
+	for _, tt := range malicious {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := r.Render(tt.source)
+			require.NoError(t, err)
+			for _, forbidden := range tt.forbidden {
+				assert.NotContains(t, rendered, forbidden)
+			}
+		})
+	}
+}
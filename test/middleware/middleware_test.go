@@ -1,9 +1,14 @@
 package middleware_test
 
 import (
+	"compress/gzip"
+	"database/sql"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,7 +18,11 @@ import (
 	"memo-app/src/service"
 
 	"github.com/gin-gonic/gin"
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockJWTService は認証ミドルウェアテスト用のモック
@@ -27,12 +36,51 @@ func (m *MockJWTService) GenerateRefreshToken(userID int) (string, error) {
 	return "mock-refresh-token", nil
 }
 
+func (m *MockJWTService) GenerateAccessTokenWithVersion(userID, tokenVersion int) (string, error) {
+	return "mock-access-token", nil
+}
+
+func (m *MockJWTService) GenerateAccessTokenWithRole(userID, tokenVersion int, role string) (string, error) {
+	return "mock-access-token", nil
+}
+
+func (m *MockJWTService) GenerateRefreshTokenWithVersion(userID, tokenVersion int) (string, error) {
+	return "mock-refresh-token", nil
+}
+
 func (m *MockJWTService) ValidateToken(tokenString string) (*service.JWTClaims, error) {
-	if tokenString == "valid-token" {
+	switch tokenString {
+	case "valid-token-123":
 		return &service.JWTClaims{
 			UserID: 1,
 			Type:   "access",
 		}, nil
+	case "revoked-token-123":
+		// ユーザー2は全端末からログアウト済み（トークンバージョン1）のため、バージョン0のトークンは失効している
+		return &service.JWTClaims{
+			UserID:       2,
+			Type:         "access",
+			TokenVersion: 0,
+		}, nil
+	case "current-token-123":
+		return &service.JWTClaims{
+			UserID:       2,
+			Type:         "access",
+			TokenVersion: 1,
+		}, nil
+	case "admin-token-123":
+		return &service.JWTClaims{
+			UserID: 3,
+			Type:   "access",
+			Role:   models.RoleAdmin,
+		}, nil
+	case "demoted-admin-token-123":
+		// 管理者として発行されたトークンだが、ユーザー4はその後に一般ユーザーへ降格済み
+		return &service.JWTClaims{
+			UserID: 4,
+			Type:   "access",
+			Role:   models.RoleAdmin,
+		}, nil
 	}
 	return nil, assert.AnError
 }
@@ -54,6 +102,22 @@ func (m *MockJWTService) ValidateRefreshToken(tokenString string) (*service.JWTC
 	return nil, assert.AnError
 }
 
+func (m *MockJWTService) GenerateEmailVerificationToken(userID int, email string) (string, error) {
+	return "mock-verification-token", nil
+}
+
+func (m *MockJWTService) ValidateEmailVerificationToken(tokenString string) (*service.JWTClaims, error) {
+	return nil, assert.AnError
+}
+
+func (m *MockJWTService) InvalidateToken(tokenString string) error {
+	return nil
+}
+
+func (m *MockJWTService) IsTokenInvalidated(jti string) (bool, error) {
+	return false, nil
+}
+
 // MockUserRepository は認証ミドルウェアテスト用のモック
 type MockUserRepository struct{}
 
@@ -62,13 +126,38 @@ func (m *MockUserRepository) Create(user *models.User) error {
 }
 
 func (m *MockUserRepository) GetByID(id int) (*models.User, error) {
-	if id == 1 {
+	switch id {
+	case 1:
 		return &models.User{
 			ID:       1,
 			Username: "testuser",
 			Email:    "test@example.com",
 			IsActive: true,
 		}, nil
+	case 2:
+		return &models.User{
+			ID:           2,
+			Username:     "loggedout",
+			Email:        "loggedout@example.com",
+			IsActive:     true,
+			TokenVersion: 1,
+		}, nil
+	case 3:
+		return &models.User{
+			ID:       3,
+			Username: "admin",
+			Email:    "admin@example.com",
+			IsActive: true,
+			Role:     models.RoleAdmin,
+		}, nil
+	case 4:
+		return &models.User{
+			ID:       4,
+			Username: "demoted",
+			Email:    "demoted@example.com",
+			IsActive: true,
+			Role:     models.RoleUser,
+		}, nil
 	}
 	return nil, assert.AnError
 }
@@ -106,6 +195,18 @@ func (m *MockUserRepository) UpdateLastLogin(userID int) error {
 	return nil
 }
 
+func (m *MockUserRepository) MarkEmailVerified(userID int) error {
+	return nil
+}
+
+func (m *MockUserRepository) IncrementTokenVersion(userID int) (int, error) {
+	return 1, nil
+}
+
+func (m *MockUserRepository) Delete(userID int) error {
+	return nil
+}
+
 func (m *MockUserRepository) GetIPRegistration(ipAddress string) (*models.IPRegistration, error) {
 	return &models.IPRegistration{
 		IPAddress:  ipAddress,
@@ -122,6 +223,10 @@ func (m *MockUserRepository) UpdateIPRegistration(ipReg *models.IPRegistration)
 	return nil
 }
 
+func (m *MockUserRepository) ResetIPRegistration(ipAddress string) error {
+	return nil
+}
+
 func (m *MockUserRepository) GetUserCountByIP(ipAddress string) (int, error) {
 	return 1, nil
 }
@@ -180,6 +285,70 @@ func TestLoggerMiddleware(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "test")
 }
 
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func() *gin.Engine {
+		r := gin.New()
+		r.Use(middleware.RequestIDMiddleware())
+		r.Use(middleware.LoggerMiddleware())
+		r.GET("/test", func(c *gin.Context) {
+			logger.WithRequestID(c).Info("ハンドラーのログ")
+			c.String(http.StatusOK, c.GetString(logger.RequestIDKey))
+		})
+		return r
+	}
+	request := func(requestID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/test", nil)
+		if requestID != "" {
+			req.Header.Set(middleware.RequestIDHeader, requestID)
+		}
+		newRouter().ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("generates a UUID when absent", func(t *testing.T) {
+		w := request("")
+
+		requestID := w.Header().Get(middleware.RequestIDHeader)
+		assert.Len(t, requestID, 36)
+		assert.Equal(t, requestID, w.Body.String())
+		assert.NotEqual(t, requestID, request("").Header().Get(middleware.RequestIDHeader))
+	})
+
+	t.Run("keeps an incoming request ID", func(t *testing.T) {
+		w := request("trace-abc-123")
+
+		assert.Equal(t, "trace-abc-123", w.Header().Get(middleware.RequestIDHeader))
+		assert.Equal(t, "trace-abc-123", w.Body.String())
+	})
+
+	t.Run("replaces unsafe request IDs", func(t *testing.T) {
+		for _, requestID := range []string{"with space", "line\x01break", strings.Repeat("a", 129)} {
+			w := request(requestID)
+
+			assert.NotEqual(t, requestID, w.Header().Get(middleware.RequestIDHeader))
+			assert.Len(t, w.Header().Get(middleware.RequestIDHeader), 36)
+		}
+	})
+
+	t.Run("adds the request ID to every log line", func(t *testing.T) {
+		hook := logtest.NewLocal(logger.Log)
+		level := logger.Log.GetLevel()
+		logger.Log.SetLevel(logrus.InfoLevel)
+		defer logger.Log.SetLevel(level)
+
+		request("trace-log-1")
+
+		entries := hook.AllEntries()
+		require.Len(t, entries, 3)
+		for _, entry := range entries {
+			assert.Equal(t, "trace-log-1", entry.Data[logger.RequestIDKey], entry.Message)
+		}
+	})
+}
+
 func TestLoggerMiddlewareWithError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -295,6 +464,18 @@ func TestAuthMiddleware(t *testing.T) {
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   "Invalid token",
 		},
+		{
+			name:           "全端末からのログアウト前に発行されたtoken",
+			authHeader:     "Bearer revoked-token-123",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "Token has been revoked",
+		},
+		{
+			name:           "全端末からのログアウト後に発行されたtoken",
+			authHeader:     "Bearer current-token-123",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "protected resource",
+		},
 		{
 			name:           "有効なtoken",
 			authHeader:     "Bearer valid-token-123",
@@ -330,6 +511,447 @@ func TestAuthMiddleware(t *testing.T) {
 	}
 }
 
+func TestRequireEmailVerified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		enabled        bool
+		user           *models.User
+		expectedStatus int
+	}{
+		{
+			name:           "無効時は未確認でも許可",
+			enabled:        false,
+			user:           &models.User{ID: 1, EmailVerified: false},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "有効時は確認済みなら許可",
+			enabled:        true,
+			user:           &models.User{ID: 1, EmailVerified: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "有効時は未確認なら拒否",
+			enabled:        true,
+			user:           &models.User{ID: 1, EmailVerified: false},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "有効時に未認証なら拒否",
+			enabled:        true,
+			user:           nil,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tt.user != nil {
+					c.Set("user", tt.user)
+				}
+				c.Next()
+			})
+			r.Use(middleware.RequireEmailVerified(tt.enabled))
+			r.GET("/protected", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "protected resource"})
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/protected", nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func() *gin.Engine {
+		r := gin.New()
+		r.Use(middleware.AuthMiddleware(&MockJWTService{}, &MockUserRepository{}), middleware.RequireRole(models.RoleAdmin))
+		r.POST("/api/admin/logs/upload", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "admin resource"})
+		})
+		return r
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{"管理者は許可", "admin-token-123", http.StatusOK},
+		{"一般ユーザーは拒否", "valid-token-123", http.StatusForbidden},
+		// トークンのロールではなくDBのロールで判定する
+		{"降格した管理者は発行済みのトークンでも拒否", "demoted-admin-token-123", http.StatusForbidden},
+		{"未認証は拒否", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/api/admin/logs/upload", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			newRouter().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+
+	t.Run("AuthMiddlewareより前に使用した場合は401", func(t *testing.T) {
+		r := gin.New()
+		r.Use(middleware.RequireRole(models.RoleAdmin))
+		r.GET("/protected", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/protected", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("ロール未設定のユーザーは一般ユーザーとして扱う", func(t *testing.T) {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			c.Set("user", &models.User{ID: 1})
+			c.Next()
+		})
+		r.Use(middleware.RequireRole(models.RoleUser))
+		r.GET("/protected", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/protected", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+// memoryAPIKeyRepository APIキー認証テスト用のインメモリリポジトリ
+type memoryAPIKeyRepository struct {
+	keys []*models.APIKey
+}
+
+func (r *memoryAPIKeyRepository) Create(key *models.APIKey) error {
+	key.ID = len(r.keys) + 1
+	key.CreatedAt = time.Now()
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) GetByHash(keyHash string) (*models.APIKey, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			return key, nil
+		}
+	}
+	return nil, assert.AnError
+}
+
+func (r *memoryAPIKeyRepository) ListByUserID(userID int) ([]*models.APIKey, error) {
+	var keys []*models.APIKey
+	for _, key := range r.keys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *memoryAPIKeyRepository) Revoke(userID, keyID int) error {
+	for _, key := range r.keys {
+		if key.ID == keyID && key.UserID == userID && key.RevokedAt == nil {
+			now := time.Now()
+			key.RevokedAt = &now
+			return nil
+		}
+	}
+	return assert.AnError
+}
+
+func (r *memoryAPIKeyRepository) UpdateLastUsed(keyID int) error {
+	return nil
+}
+
+func TestAuthMiddlewareWithAPIKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	apiKeyService := service.NewAPIKeyService(&memoryAPIKeyRepository{})
+	created, err := apiKeyService.CreateAPIKey(1, "ci-bot")
+	assert.NoError(t, err)
+	assert.NotEqual(t, created.Key, created.APIKey.KeyHash, "平文のキーを保存してはいけない")
+
+	r := gin.New()
+	r.Use(middleware.AuthMiddlewareWithAPIKeys(&MockJWTService{}, &MockUserRepository{}, apiKeyService))
+	r.GET("/api/memos", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetInt("user_id"), "auth_method": c.GetString("auth_method")})
+	})
+
+	request := func(authHeader string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/memos", nil)
+		req.Header.Set("Authorization", authHeader)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("有効なAPIキーでメモにアクセスできる", func(t *testing.T) {
+		w := request("ApiKey " + created.Key)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"user_id":1`)
+		assert.Contains(t, w.Body.String(), `"auth_method":"api_key"`)
+	})
+
+	t.Run("X-API-Keyヘッダーでメモにアクセスできる", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/memos", nil)
+		req.Header.Set(middleware.APIKeyHeader, created.Key)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"user_id":1`)
+		assert.Contains(t, w.Body.String(), `"auth_method":"api_key"`)
+	})
+
+	t.Run("Bearer JWTも引き続き利用できる", func(t *testing.T) {
+		w := request("Bearer valid-token-123")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"auth_method":"jwt"`)
+	})
+
+	t.Run("不明なAPIキーは拒否される", func(t *testing.T) {
+		w := request("ApiKey memo_unknown")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid API key")
+	})
+
+	t.Run("失効したAPIキーは拒否される", func(t *testing.T) {
+		assert.NoError(t, apiKeyService.RevokeAPIKey(1, created.APIKey.ID))
+		w := request("ApiKey " + created.Key)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid API key")
+
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/memos", nil)
+		req.Header.Set(middleware.APIKeyHeader, created.Key)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("一覧にキーの平文は含まれない", func(t *testing.T) {
+		keys, err := apiKeyService.ListAPIKeys(1)
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+		assert.True(t, keys[0].IsRevoked())
+		assert.True(t, strings.HasPrefix(created.Key, keys[0].KeyPrefix))
+	})
+}
+
+func TestCORSMiddlewareWithConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(cfg middleware.CORSConfig) *gin.Engine {
+		r := gin.New()
+		r.Use(middleware.CORSMiddlewareWithConfig(cfg))
+		r.GET("/test", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "test"})
+		})
+		return r
+	}
+	request := func(r *gin.Engine, method, origin string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/test", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	allowlist := middleware.CORSConfig{
+		AllowedOrigins:   []string{"http://localhost:3000", "https://memo.example.com/"},
+		AllowCredentials: true,
+	}
+
+	t.Run("echoes an allowed origin with credentials", func(t *testing.T) {
+		w := request(newRouter(allowlist), "GET", "https://memo.example.com")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "https://memo.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "Origin", w.Header().Get("Vary"))
+	})
+
+	t.Run("answers preflight for an allowed origin", func(t *testing.T) {
+		w := request(newRouter(allowlist), "OPTIONS", "http://localhost:3000")
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "http://localhost:3000", w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("omits CORS headers for a disallowed origin", func(t *testing.T) {
+		w := request(newRouter(allowlist), "GET", "https://evil.example.com")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("allows conditional PATCH requests and exposes API headers", func(t *testing.T) {
+		r := newRouter(allowlist)
+		r.PATCH("/api/memos/:id/pin", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("OPTIONS", "/api/memos/1/pin", nil)
+		req.Header.Set("Origin", "http://localhost:3000")
+		req.Header.Set("Access-Control-Request-Method", "PATCH")
+		req.Header.Set("Access-Control-Request-Headers", "if-match, x-request-id")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "http://localhost:3000", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "PATCH")
+		for _, header := range []string{"If-Match", "If-None-Match", "X-Request-ID"} {
+			assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), header)
+		}
+		for _, header := range []string{"ETag", "X-Request-ID", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining"} {
+			assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), header)
+		}
+	})
+
+	t.Run("falls back to the wildcard without an allowlist", func(t *testing.T) {
+		w := request(newRouter(middleware.CORSConfig{AllowCredentials: true}), "GET", "https://anywhere.example.com")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+		// ワイルドカードと資格情報は併用できないため返さない
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	})
+}
+
+func TestGzipMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	large := strings.Repeat("memo content ", 200)
+	r := gin.New()
+	r.Use(middleware.GzipMiddlewareWithConfig(middleware.GzipConfig{MinSize: 1024, ExcludePaths: []string{"/health"}}))
+	r.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"content": large})
+	})
+	r.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"content": "short"})
+	})
+	r.GET("/health", func(c *gin.Context) {
+		c.String(http.StatusOK, large)
+	})
+	r.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(large))
+	})
+
+	request := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("compresses large responses", func(t *testing.T) {
+		w := request("/large", "gzip, deflate")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+		assert.Less(t, w.Body.Len(), len(large))
+
+		reader, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), large)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("leaves small responses uncompressed", func(t *testing.T) {
+		w := request("/small", "gzip")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.JSONEq(t, `{"content":"short"}`, w.Body.String())
+	})
+
+	t.Run("skips clients without gzip support", func(t *testing.T) {
+		for _, acceptEncoding := range []string{"", "deflate", "gzip;q=0"} {
+			w := request("/large", acceptEncoding)
+
+			assert.Empty(t, w.Header().Get("Content-Encoding"), acceptEncoding)
+			assert.Contains(t, w.Body.String(), large)
+		}
+	})
+
+	t.Run("skips the health check and compressed content types", func(t *testing.T) {
+		for _, path := range []string{"/health", "/image"} {
+			w := request(path, "gzip")
+
+			assert.Empty(t, w.Header().Get("Content-Encoding"), path)
+			assert.Equal(t, large, w.Body.String(), path)
+		}
+	})
+}
+
+func TestMaxBodySizeMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func() *gin.Engine {
+		r := gin.New()
+		r.Use(middleware.MaxBodySizeMiddlewareWithOverrides(1024, map[string]int64{"/import": 4096}))
+		handle := func(c *gin.Context) {
+			var body map[string]interface{}
+			if err := c.ShouldBindJSON(&body); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"message": "success"})
+		}
+		r.POST("/memos", handle)
+		r.POST("/import", handle)
+		return r
+	}
+	post := func(path string, size int) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, strings.NewReader(`{"content":"`+strings.Repeat("a", size)+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		newRouter().ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("rejects an oversized body with 413 before binding", func(t *testing.T) {
+		w := post("/memos", 2048)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), "1024 bytes")
+	})
+
+	t.Run("accepts bodies within the limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, post("/memos", 512).Code)
+	})
+
+	t.Run("applies the route override", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, post("/import", 2048).Code)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, post("/import", 8192).Code)
+	})
+}
+
 func TestRateLimitMiddleware(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -340,8 +962,7 @@ func TestRateLimitMiddleware(t *testing.T) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
-	// 複数回リクエストしてレート制限が動作することを確認
-	// 現在は空実装なので、すべてのリクエストが通る
+	// デフォルトのバースト内のリクエストはすべて通る
 	for i := 0; i < 10; i++ {
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/test", nil)
@@ -350,9 +971,74 @@ func TestRateLimitMiddleware(t *testing.T) {
 		r.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "20", w.Header().Get("X-RateLimit-Limit"))
+		remaining, err := strconv.Atoi(w.Header().Get("X-RateLimit-Remaining"))
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, remaining, 19-i+1) // 実行中に補充される分を許容
 	}
 }
 
+func TestRateLimitMiddleware_Exceeded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(limiter *middleware.RateLimiter, userID int) *gin.Engine {
+		r := gin.New()
+		if userID != 0 {
+			r.Use(func(c *gin.Context) { c.Set("user_id", userID) })
+		}
+		r.Use(middleware.RateLimitMiddlewareWithLimiter(limiter))
+		r.GET("/test", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "success"})
+		})
+		return r
+	}
+	request := func(r *gin.Engine, remoteAddr string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = remoteAddr
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("returns 429 with Retry-After once the burst is used", func(t *testing.T) {
+		r := newRouter(middleware.NewRateLimiter(middleware.RateLimitConfig{RPS: 0.5, Burst: 2}), 0)
+
+		assert.Equal(t, http.StatusOK, request(r, "10.0.0.1:1000").Code)
+		assert.Equal(t, http.StatusOK, request(r, "10.0.0.1:1000").Code)
+
+		w := request(r, "10.0.0.1:1000")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+		assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+		assert.Contains(t, w.Body.String(), "Too Many Requests")
+
+		// 別のIPは独立したバケット
+		assert.Equal(t, http.StatusOK, request(r, "10.0.0.2:1000").Code)
+	})
+
+	t.Run("keys authenticated requests by user", func(t *testing.T) {
+		limiter := middleware.NewRateLimiter(middleware.RateLimitConfig{RPS: 0.5, Burst: 1})
+
+		// 同じユーザーはIPが変わっても同じバケット
+		assert.Equal(t, http.StatusOK, request(newRouter(limiter, 1), "10.0.0.1:1000").Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(newRouter(limiter, 1), "10.0.0.2:1000").Code)
+		assert.Equal(t, http.StatusOK, request(newRouter(limiter, 2), "10.0.0.1:1000").Code)
+	})
+
+	t.Run("drops idle clients", func(t *testing.T) {
+		limiter := middleware.NewRateLimiter(middleware.RateLimitConfig{
+			RPS: 1, Burst: 1, IdleTTL: time.Millisecond, CleanupInterval: time.Millisecond,
+		})
+		limiter.Allow("ip:10.0.0.1")
+		limiter.Allow("ip:10.0.0.2")
+		assert.Equal(t, 2, limiter.Len())
+
+		time.Sleep(5 * time.Millisecond)
+		limiter.Allow("ip:10.0.0.3")
+		assert.Equal(t, 1, limiter.Len())
+	})
+}
+
 func TestMiddlewareChain(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -382,3 +1068,236 @@ func TestMiddlewareChain(t *testing.T) {
 	// CORSヘッダーが設定されていることを確認
 	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
 }
+
+func TestSizeMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	metrics := middleware.NewSizeMetrics([]float64{10, 100, 1000})
+	var alerts []middleware.SizeAlert
+	metrics.SetAlertHook(50, func(alert middleware.SizeAlert) {
+		alerts = append(alerts, alert)
+	})
+
+	r := gin.New()
+	r.Use(middleware.SizeMetricsMiddleware(metrics))
+	r.POST("/api/memos/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "hello")
+	})
+
+	body := strings.Repeat("a", 64)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/memos/1", strings.NewReader(body))
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// ルートはパスパラメータではなくテンプレートでラベル付けされる
+	requestHist, ok := metrics.Snapshot(middleware.RequestSizeMetric, "/api/memos/:id")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), requestHist.Count)
+	assert.Equal(t, float64(64), requestHist.Sum)
+	assert.Equal(t, []uint64{0, 1, 1}, requestHist.Counts)
+
+	responseHist, ok := metrics.Snapshot(middleware.ResponseSizeMetric, "/api/memos/:id")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), responseHist.Count)
+	assert.Equal(t, float64(len("hello")), responseHist.Sum)
+	assert.Equal(t, []uint64{1, 1, 1}, responseHist.Counts)
+
+	// 閾値を超えたのはリクエストボディのみ
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, middleware.RequestSizeMetric, alerts[0].Metric)
+		assert.Equal(t, int64(64), alerts[0].Size)
+		assert.Equal(t, "POST", alerts[0].Method)
+	}
+
+	var text strings.Builder
+	assert.NoError(t, metrics.WriteText(&text))
+	assert.Contains(t, text.String(), `http_request_size_bytes_bucket{route="/api/memos/:id",le="100"} 1`)
+	assert.Contains(t, text.String(), `http_response_size_bytes_sum{route="/api/memos/:id"} 5`)
+}
+
+func TestSizeMetricsMiddleware_UnknownContentLength(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	metrics := middleware.NewSizeMetrics(nil)
+	r := gin.New()
+	r.Use(middleware.SizeMetricsMiddleware(metrics))
+	r.POST("/upload", func(c *gin.Context) {
+		data, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, "%d", len(data))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/upload", strings.NewReader(strings.Repeat("b", 300)))
+	req.ContentLength = -1 // chunked 相当
+	r.ServeHTTP(w, req)
+
+	hist, ok := metrics.Snapshot(middleware.RequestSizeMetric, "/upload")
+	assert.True(t, ok)
+	assert.Equal(t, float64(300), hist.Sum)
+
+	// 一致しないルートは unmatched として記録される
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/nope", nil)
+	r.ServeHTTP(w, req)
+	_, ok = metrics.Snapshot(middleware.ResponseSizeMetric, "unmatched")
+	assert.True(t, ok)
+}
+
+// scrapeMetrics /metrics と同じハンドラーでレジストリの内容を取得
+func scrapeMetrics(t *testing.T, metrics *middleware.HTTPMetrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	metrics.Handler().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestHTTPMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	metrics := middleware.NewHTTPMetrics()
+	var inFlight string
+	r := gin.New()
+	r.Use(middleware.HTTPMetricsMiddleware(metrics))
+	r.GET("/api/memos/:id", func(c *gin.Context) {
+		inFlight = scrapeMetrics(t, metrics)
+		c.String(http.StatusOK, "ok")
+	})
+	r.POST("/api/memos", func(c *gin.Context) {
+		c.Status(http.StatusBadRequest)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/memos/1", nil)
+	r.ServeHTTP(w, req)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/memos", nil)
+	r.ServeHTTP(w, req)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/nope", nil)
+	r.ServeHTTP(w, req)
+
+	// 処理中はゲージが1、完了後は0に戻る
+	assert.Contains(t, inFlight, `http_requests_in_flight{method="GET",route="/api/memos/:id"} 1`)
+
+	text := scrapeMetrics(t, metrics)
+	assert.Contains(t, text, `http_requests_in_flight{method="GET",route="/api/memos/:id"} 0`)
+	assert.Contains(t, text, `http_requests_total{method="GET",route="/api/memos/:id",status="200"} 1`)
+	assert.Contains(t, text, `http_requests_total{method="POST",route="/api/memos",status="400"} 1`)
+	assert.Contains(t, text, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, text, `http_request_duration_seconds_count{method="GET",route="/api/memos/:id",status="200"} 1`)
+	assert.Contains(t, text, "go_goroutines")
+}
+
+func TestHTTPMetrics_MemosCreatedAndCollectors(t *testing.T) {
+	metrics := middleware.NewHTTPMetrics()
+	metrics.ObserveMemosCreated("api", 1)
+	metrics.ObserveMemosCreated("import", 3)
+	metrics.ObserveMemosCreated("import", 0)
+
+	sizeMetrics := middleware.NewSizeMetrics([]float64{10})
+	sizeMetrics.Observe(middleware.RequestSizeMetric, "/api/memos", 5)
+	require.NoError(t, metrics.Register(sizeMetrics))
+
+	// sql.Open は接続しないため、DBがなくてもプールの統計は取得できる
+	db, err := sql.Open("postgres", "host=localhost dbname=memo_test sslmode=disable")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, metrics.RegisterDBStats(db, "memo_test"))
+
+	text := scrapeMetrics(t, metrics)
+	assert.Contains(t, text, `memos_created_total{source="api"} 1`)
+	assert.Contains(t, text, `memos_created_total{source="import"} 3`)
+	assert.Contains(t, text, `http_request_size_bytes_bucket{route="/api/memos",le="10"} 1`)
+	assert.Contains(t, text, `http_request_size_bytes_sum{route="/api/memos"} 5`)
+	assert.Contains(t, text, `go_sql_open_connections{db_name="memo_test"} 0`)
+}
+
+func TestAdminTokenMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(token string) *gin.Engine {
+		r := gin.New()
+		r.Use(middleware.AdminTokenMiddleware(token))
+		r.POST("/api/admin/test", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "admin"})
+		})
+		return r
+	}
+
+	tests := []struct {
+		name           string
+		configured     string
+		header         string
+		expectedStatus int
+	}{
+		{"正しいトークン", "secret", "secret", http.StatusOK},
+		{"トークンなし", "secret", "", http.StatusForbidden},
+		{"誤ったトークン", "secret", "wrong", http.StatusForbidden},
+		{"トークン未設定の場合は無効", "", "", http.StatusNotFound},
+		{"トークン未設定の場合は任意のヘッダーでも無効", "", "anything", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/api/admin/test", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Admin-Token", tt.header)
+			}
+			newRouter(tt.configured).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestAPIKeyQueryAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	apiKeyService := service.NewAPIKeyService(&memoryAPIKeyRepository{})
+	created, err := apiKeyService.CreateAPIKey(1, "feed-reader")
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(middleware.LoggerMiddleware())
+	r.GET("/api/memos/feed.xml", middleware.APIKeyQueryAuthMiddleware(&MockUserRepository{}, apiKeyService), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetInt("user_id"), "auth_method": c.GetString("auth_method")})
+	})
+	request := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/api/memos/feed.xml"+query, nil))
+		return w
+	}
+
+	t.Run("クエリパラメータのAPIキーで認証できる", func(t *testing.T) {
+		w := request("?api_key=" + created.Key)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"user_id":1`)
+		assert.Contains(t, w.Body.String(), `"auth_method":"api_key"`)
+	})
+
+	t.Run("APIキーがない場合と不明なAPIキーは拒否される", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request("").Code)
+		assert.Equal(t, http.StatusUnauthorized, request("?api_key=memo_unknown").Code)
+	})
+
+	t.Run("リクエストログにAPIキーを残さない", func(t *testing.T) {
+		hook := logtest.NewLocal(logger.Log)
+		level := logger.Log.GetLevel()
+		logger.Log.SetLevel(logrus.InfoLevel)
+		defer logger.Log.SetLevel(level)
+
+		request("?api_key=" + created.Key)
+
+		require.NotEmpty(t, hook.AllEntries())
+		for _, entry := range hook.AllEntries() {
+			if uri, ok := entry.Data["uri"].(string); ok {
+				assert.NotContains(t, uri, created.Key)
+				assert.Contains(t, uri, "api_key=REDACTED")
+			}
+		}
+	})
+}
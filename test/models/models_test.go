@@ -2,6 +2,7 @@ package models_test
 
 import (
 	"testing"
+	"time"
 
 	"memo-app/src/models"
 
@@ -92,3 +93,18 @@ func TestMemoListResponse_Structure(t *testing.T) {
 	assert.Equal(t, 10, response.Limit)
 	assert.Equal(t, 1, response.TotalPages)
 }
+
+func TestIPRegistration_CountWithin(t *testing.T) {
+	now := time.Now()
+	window := 24 * time.Hour
+	ipReg := &models.IPRegistration{UserCount: 3}
+
+	// 期間の終わりちょうどで数え直す
+	ipReg.WindowStartedAt = now.Add(-window + time.Nanosecond)
+	assert.Equal(t, 3, ipReg.CountWithin(window, now))
+	ipReg.WindowStartedAt = now.Add(-window)
+	assert.Equal(t, 0, ipReg.CountWithin(window, now))
+
+	// 期間が0の場合は累計
+	assert.Equal(t, 3, ipReg.CountWithin(0, now))
+}
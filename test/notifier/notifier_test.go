@@ -0,0 +1,72 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"memo-app/src/domain"
+	"memo-app/src/notifier"
+
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	logger, _ := logtest.NewNullLogger()
+
+	n, err := notifier.New("log", "", logger)
+	require.NoError(t, err)
+	assert.IsType(t, &notifier.LogNotifier{}, n)
+
+	n, err = notifier.New("webhook", "https://hooks.example.com", logger)
+	require.NoError(t, err)
+	assert.IsType(t, &notifier.WebhookNotifier{}, n)
+
+	_, err = notifier.New("webhook", "", logger)
+	assert.Error(t, err)
+
+	_, err = notifier.New("sms", "", logger)
+	assert.Error(t, err)
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	remindAt := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+	reminder := domain.MemoReminder{UserID: 42, Memo: domain.Memo{ID: 7, Title: "Call", Content: "Call back", Priority: domain.PriorityHigh, RemindAt: &remindAt}}
+
+	t.Run("リマインダーをJSONでPOSTする", func(t *testing.T) {
+		var payload notifier.WebhookPayload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		err := notifier.NewWebhookNotifier(server.URL, server.Client()).Notify(context.Background(), reminder)
+		require.NoError(t, err)
+		assert.Equal(t, notifier.ReminderEvent, payload.Event)
+		assert.Equal(t, 42, payload.UserID)
+		assert.Equal(t, 7, payload.Memo.ID)
+		assert.Equal(t, "high", payload.Memo.Priority)
+		assert.Equal(t, []string{}, payload.Memo.Tags)
+		require.NotNil(t, payload.Memo.RemindAt)
+		assert.True(t, remindAt.Equal(*payload.Memo.RemindAt))
+	})
+
+	t.Run("2xx以外の応答はエラー", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		err := notifier.NewWebhookNotifier(server.URL, server.Client()).Notify(context.Background(), reminder)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "502")
+	})
+}
@@ -0,0 +1,120 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"memo-app/src/problem"
+	"memo-app/src/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(enabled bool, body any) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Middleware(enabled))
+	r.GET("/api/memos/:id", func(c *gin.Context) {
+		problem.JSON(c, http.StatusBadRequest, body)
+	})
+	return r
+}
+
+func request(r *gin.Engine, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/memos/abc", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestJSON(t *testing.T) {
+	legacy := gin.H{"error": "Invalid memo ID", "message": "id must be a positive integer"}
+
+	t.Run("Accept で要求されない場合は従来のボディを返す", func(t *testing.T) {
+		w := request(setupRouter(false, legacy), "application/json")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.JSONEq(t, `{"error":"Invalid memo ID","message":"id must be a positive integer"}`, w.Body.String())
+	})
+
+	t.Run("Accept で要求された場合は problem+json を返す", func(t *testing.T) {
+		w := request(setupRouter(false, legacy), "application/json, application/problem+json;q=0.9")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{
+			"type": "about:blank",
+			"title": "Invalid memo ID",
+			"status": 400,
+			"detail": "id must be a positive integer",
+			"instance": "/api/memos/abc"
+		}`, w.Body.String())
+	})
+
+	t.Run("設定で有効な場合は Accept に関わらず problem+json を返す", func(t *testing.T) {
+		w := request(setupRouter(true, legacy), "")
+
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Equal(t, "Invalid memo ID", got["title"])
+		assert.Equal(t, float64(http.StatusBadRequest), got["status"])
+	})
+}
+
+func TestFrom(t *testing.T) {
+	t.Run("バリデーションエラーは errors に変換する", func(t *testing.T) {
+		d := problem.From(http.StatusUnprocessableEntity, validator.ValidationErrors{Errors: []validator.ValidationError{
+			{Field: "title", Tag: "required", Message: "title is required"},
+		}})
+
+		assert.Equal(t, problem.DefaultType, d.Type)
+		assert.Equal(t, "Validation failed", d.Title)
+		assert.Equal(t, http.StatusUnprocessableEntity, d.Status)
+		assert.Equal(t, "VALIDATION_FAILED", d.Code)
+		require.Len(t, d.Errors, 1)
+		assert.Equal(t, "title", d.Errors[0].Field)
+	})
+
+	t.Run("field と追加のキーは errors と拡張メンバーになる", func(t *testing.T) {
+		d := problem.From(http.StatusConflict, gin.H{"error": "username already exists", "field": "username", "retry_after": 30})
+
+		assert.Equal(t, "username already exists", d.Title)
+		require.Len(t, d.Errors, 1)
+		assert.Equal(t, "username", d.Errors[0].Field)
+		assert.Equal(t, "username already exists", d.Errors[0].Message)
+
+		body, err := json.Marshal(d)
+		require.NoError(t, err)
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, float64(30), got["retry_after"])
+		assert.Equal(t, float64(http.StatusConflict), got["status"])
+	})
+
+	t.Run("拡張メンバーは標準メンバーを上書きしない", func(t *testing.T) {
+		d := problem.New(http.StatusTooManyRequests, "")
+		d.Extensions = map[string]any{"status": "overridden"}
+
+		body, err := json.Marshal(d)
+		require.NoError(t, err)
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, float64(http.StatusTooManyRequests), got["status"])
+		assert.Equal(t, "Too Many Requests", got["title"])
+	})
+
+	t.Run("未知のボディはHTTPステータスの説明を title にする", func(t *testing.T) {
+		d := problem.From(http.StatusNotFound, "not found")
+		assert.Equal(t, "Not Found", d.Title)
+		assert.Equal(t, problem.DefaultType, d.Type)
+	})
+}
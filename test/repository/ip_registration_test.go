@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"memo-app/src/models"
+	"memo-app/src/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_IPRegistration(t *testing.T) {
+	newRepo := func(t *testing.T) (repository.UserRepository, sqlmock.Sqlmock) {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+		return repository.NewUserRepository(sqlDB), mock
+	}
+
+	t.Run("登録数と期間の開始日時を取得する", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		now := time.Now()
+		started := now.Add(-time.Hour)
+		mock.ExpectQuery(`SELECT id, ip_address, registration_count, last_registration_at, window_started_at, first_registration_at, updated_at\s+FROM ip_registrations WHERE ip_address = \$1`).
+			WithArgs("192.168.1.1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "ip_address", "registration_count", "last_registration_at", "window_started_at", "first_registration_at", "updated_at"}).
+				AddRow(1, "192.168.1.1", 2, now, started, started, now))
+
+		ipReg, err := repo.GetIPRegistration("192.168.1.1")
+		require.NoError(t, err)
+		assert.Equal(t, 2, ipReg.UserCount)
+		assert.Equal(t, started, ipReg.WindowStartedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("登録数と期間の開始日時を更新する", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		now := time.Now()
+		mock.ExpectExec(`UPDATE ip_registrations\s+SET registration_count = \$2, last_registration_at = \$3, window_started_at = \$4, updated_at = \$5\s+WHERE id = \$1`).
+			WithArgs(1, 1, now, now, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.UpdateIPRegistration(&models.IPRegistration{ID: 1, UserCount: 1, LastUsedAt: now, WindowStartedAt: now})
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("リセットは記録を削除する", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectExec(`DELETE FROM ip_registrations WHERE ip_address = \$1`).
+			WithArgs("192.168.1.1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		require.NoError(t, repo.ResetIPRegistration("192.168.1.1"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
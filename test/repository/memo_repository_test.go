@@ -0,0 +1,103 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"memo-app/src/database"
+	"memo-app/src/domain"
+	"memo-app/src/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var memoRowColumns = []string{"id", "title", "content", "category", "tags", "priority", "status", "pinned",
+	"created_at", "updated_at", "completed_at", "trashed_at", "due_date", "remind_at", "reminded", "color", "version"}
+
+func newMockMemoRepository(t *testing.T) (*repository.MemoRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	logger, _ := logtest.NewNullLogger()
+	return repository.NewMemoRepository(&database.DB{DB: sqlDB}, logger), mock
+}
+
+// 旧APIのリポジトリもゴミ箱・完全削除はクリーンアーキテクチャ側の実装と同じ動作になる
+func TestMemoRepository_StagedDelete(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+
+	t.Run("ゴミ箱への移動はユーザーで絞り込み、trashedのメモを返す", func(t *testing.T) {
+		repo, mock := newMockMemoRepository(t)
+		now := time.Now()
+		mock.ExpectQuery(`UPDATE memos SET status = \$2, trashed_at = \$3, updated_at = \$3 WHERE id = \$1 AND user_id = \$4 RETURNING`).
+			WithArgs(7, "trashed", sqlmock.AnyArg(), 42).
+			WillReturnRows(sqlmock.NewRows(memoRowColumns).
+				AddRow(7, "Title", "Content", "Work", `["go"]`, "medium", "trashed", false, now, now, nil, now, nil, nil, false, nil, 2))
+
+		memo, err := repo.Trash(ctx, 7)
+		require.NoError(t, err)
+		assert.Equal(t, 7, memo.ID)
+		assert.Equal(t, "trashed", memo.Status)
+		assert.Equal(t, `["go"]`, memo.Tags)
+		require.NotNil(t, memo.TrashedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("存在しないメモはゴミ箱に移動できない", func(t *testing.T) {
+		repo, mock := newMockMemoRepository(t)
+		mock.ExpectQuery(`UPDATE memos SET status`).WillReturnRows(sqlmock.NewRows(memoRowColumns))
+
+		_, err := repo.Trash(ctx, 7)
+		assert.EqualError(t, err, "memo not found")
+	})
+
+	t.Run("完全削除はゴミ箱のメモをリサイクルログに複製して削除する", func(t *testing.T) {
+		repo, mock := newMockMemoRepository(t)
+		mock.ExpectExec(`WITH deleted AS \(DELETE FROM memos WHERE id = \$1 AND status = \$2 AND user_id = \$3 RETURNING .*\)\s+INSERT INTO deleted_memos`).
+			WithArgs(7, "trashed", 42).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.PermanentDelete(ctx, 7))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ゴミ箱にないメモは完全削除しない", func(t *testing.T) {
+		repo, mock := newMockMemoRepository(t)
+		now := time.Now()
+		mock.ExpectExec(`WITH deleted AS`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT .* FROM memos WHERE id = \$1 AND user_id = \$2`).
+			WithArgs(7, 42).
+			WillReturnRows(sqlmock.NewRows(memoRowColumns).
+				AddRow(7, "Title", "Content", "", `[]`, "medium", "archived", false, now, now, now, nil, nil, nil, false, nil, 1))
+
+		assert.EqualError(t, repo.PermanentDelete(ctx, 7), "memo is not in trash")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("完全削除のDBエラーは握りつぶさずに返す", func(t *testing.T) {
+		repo, mock := newMockMemoRepository(t)
+		mock.ExpectExec(`WITH deleted AS`).WillReturnError(errors.New("connection reset"))
+
+		err := repo.PermanentDelete(ctx, 7)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection reset")
+	})
+
+	t.Run("削除のDBエラーは握りつぶさずに返す", func(t *testing.T) {
+		repo, mock := newMockMemoRepository(t)
+		mock.ExpectExec(`UPDATE memos SET .* WHERE id = \$1 AND status <> \$3 AND user_id = \$4`).
+			WithArgs(7, sqlmock.AnyArg(), "trashed", 42).
+			WillReturnError(errors.New("connection reset"))
+
+		err := repo.Delete(ctx, 7)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection reset")
+	})
+}
@@ -0,0 +1,67 @@
+package repository_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"memo-app/src/repository"
+
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// cleanupRecorder はDeleteExpiredの呼び出しを記録するリポジトリ
+type cleanupRecorder struct {
+	repository.RevokedTokenRepository
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (r *cleanupRecorder) DeleteExpired(now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	return 1, r.err
+}
+
+func (r *cleanupRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+func TestStartRevokedTokenCleanup(t *testing.T) {
+	t.Run("停止するまで間隔ごとに削除する", func(t *testing.T) {
+		repo := &cleanupRecorder{}
+		logger, _ := logtest.NewNullLogger()
+
+		stop := repository.StartRevokedTokenCleanup(repo, 10*time.Millisecond, logger)
+		assert.Eventually(t, func() bool { return repo.count() >= 2 }, time.Second, 5*time.Millisecond)
+		stop()
+
+		count := repo.count()
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, count, repo.count())
+	})
+
+	t.Run("失敗はログに記録して継続する", func(t *testing.T) {
+		repo := &cleanupRecorder{err: errors.New("connection refused")}
+		logger, hook := logtest.NewNullLogger()
+
+		stop := repository.StartRevokedTokenCleanup(repo, 10*time.Millisecond, logger)
+		assert.Eventually(t, func() bool { return repo.count() >= 2 }, time.Second, 5*time.Millisecond)
+		stop()
+
+		var errorLogged bool
+		for _, entry := range hook.AllEntries() {
+			if entry.Level == logrus.ErrorLevel {
+				errorLogged = true
+			}
+		}
+		assert.True(t, errorLogged)
+	})
+}
@@ -0,0 +1,56 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+
+	"memo-app/src/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_Delete(t *testing.T) {
+	newRepo := func(t *testing.T) (repository.UserRepository, sqlmock.Sqlmock) {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+		return repository.NewUserRepository(sqlDB), mock
+	}
+
+	t.Run("本人のメモとユーザーを1つのトランザクションで削除する", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		// 他のユーザーのメモが消えないよう user_id で絞り込む
+		mock.ExpectExec(`DELETE FROM memos WHERE user_id = \$1`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(`DELETE FROM users WHERE id = \$1`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.Delete(1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ユーザーが存在しない場合はロールバックする", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM memos WHERE user_id = \$1`).WithArgs(99).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM users WHERE id = \$1`).WithArgs(99).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.EqualError(t, repo.Delete(99), "user not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("メモの削除に失敗した場合はユーザーを削除しない", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM memos WHERE user_id = \$1`).WithArgs(1).WillReturnError(errors.New("connection reset"))
+		mock.ExpectRollback()
+
+		err := repo.Delete(1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to delete memos")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -8,7 +8,9 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
+	"memo-app/src/models"
 	"memo-app/src/repository"
 
 	_ "github.com/lib/pq"
@@ -98,4 +100,33 @@ func TestUserRepository_Integration(t *testing.T) {
 		assert.NotNil(t, updatedUser.LastLoginAt)
 		t.Logf("更新後の最終ログイン時刻: %v", updatedUser.LastLoginAt)
 	})
+
+	t.Run("アカウント削除は他のユーザーのメモに影響しない", func(t *testing.T) {
+		suffix := time.Now().UnixNano()
+		newUser := func(name string) *models.User {
+			user := &models.User{
+				Username:  fmt.Sprintf("%s_%d", name, suffix),
+				Email:     fmt.Sprintf("%s_%d@example.com", name, suffix),
+				IsActive:  true,
+				CreatedIP: "127.0.0.1",
+			}
+			require.NoError(t, repo.Create(user))
+			_, err := db.Exec(`INSERT INTO memos (title, content, user_id) VALUES ($1, 'content', $2)`, name, user.ID)
+			require.NoError(t, err)
+			return user
+		}
+		deleted, other := newUser("delete_me"), newUser("keep_me")
+		defer repo.Delete(other.ID)
+
+		require.NoError(t, repo.Delete(deleted.ID))
+
+		_, err := repo.GetByID(deleted.ID)
+		assert.Error(t, err)
+
+		var count int
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM memos WHERE user_id = $1`, deleted.ID).Scan(&count))
+		assert.Zero(t, count)
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM memos WHERE user_id = $1`, other.ID).Scan(&count))
+		assert.Equal(t, 1, count)
+	})
 }
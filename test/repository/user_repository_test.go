@@ -68,6 +68,21 @@ func (m *MockUserRepository) UpdateLastLogin(userID int) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) MarkEmailVerified(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) IncrementTokenVersion(userID int) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Delete(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) GetIPRegistration(ipAddress string) (*models.IPRegistration, error) {
 	args := m.Called(ipAddress)
 	if args.Get(0) == nil {
@@ -86,6 +101,11 @@ func (m *MockUserRepository) UpdateIPRegistration(ipReg *models.IPRegistration)
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) ResetIPRegistration(ipAddress string) error {
+	args := m.Called(ipAddress)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) GetUserCountByIP(ipAddress string) (int, error) {
 	args := m.Called(ipAddress)
 	return args.Int(0), args.Error(1)
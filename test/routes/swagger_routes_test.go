@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"memo-app/src/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupSwaggerRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("有効な場合は生成済みの仕様書を返す", func(t *testing.T) {
+		router := gin.New()
+		routes.SetupSwaggerRoutes(router, true)
+
+		req := httptest.NewRequest("GET", "/swagger/doc.json", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var spec struct {
+			Paths       map[string]interface{} `json:"paths"`
+			Definitions map[string]interface{} `json:"definitions"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+		assert.Contains(t, spec.Paths, "/api/memos")
+		assert.Contains(t, spec.Paths, "/api/memos/{id}/permanent")
+		assert.Contains(t, spec.Definitions, "handler.MemoResponseDTO")
+		assert.Contains(t, spec.Definitions, "handler.MemoListResponseDTO")
+		assert.NotContains(t, spec.Definitions, "models.Memo")
+	})
+
+	t.Run("無効な場合はルートを登録しない", func(t *testing.T) {
+		router := gin.New()
+		routes.SetupSwaggerRoutes(router, false)
+
+		req := httptest.NewRequest("GET", "/swagger/index.html", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
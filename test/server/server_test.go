@@ -0,0 +1,137 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"memo-app/src/server"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startServer serves a handler that blocks until release is closed and signals started for every request
+func startServer(t *testing.T, ctx context.Context, timeout time.Duration) (addr string, started <-chan struct{}, release chan struct{}, done <-chan error) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	startedCh := make(chan struct{}, 1)
+	releaseCh := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startedCh <- struct{}{}
+		<-releaseCh
+		w.Write([]byte("done"))
+	})}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- server.Serve(ctx, srv, ln, timeout, logger)
+	}()
+	return ln.Addr().String(), startedCh, releaseCh, doneCh
+}
+
+// get sends a request on a fresh connection so it cannot reuse a kept-alive one
+func get(addr string) (*http.Response, error) {
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}, Timeout: 5 * time.Second}
+	return client.Get("http://" + addr + "/")
+}
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	addr, started, release, done := startServer(t, ctx, 5*time.Second)
+
+	type result struct {
+		body string
+		err  error
+	}
+	inFlight := make(chan result, 1)
+	go func() {
+		resp, err := get(addr)
+		if err != nil {
+			inFlight <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		inFlight <- result{body: string(body), err: err}
+	}()
+	<-started
+
+	// シャットダウンを要求すると新しい接続は受け付けない
+	cancel()
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return true
+		}
+		conn.Close()
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+
+	select {
+	case err := <-done:
+		t.Fatalf("処理中のリクエストを待たずに停止しました: %v", err)
+	default:
+	}
+
+	// 処理中のリクエストは最後まで応答する
+	close(release)
+	res := <-inFlight
+	require.NoError(t, res.err)
+	assert.Equal(t, "done", res.body)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("サーバーが停止しませんでした")
+	}
+}
+
+func TestServe_ClosesRequestsExceedingTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	addr, started, release, done := startServer(t, ctx, 50*time.Millisecond)
+	defer close(release)
+
+	inFlight := make(chan error, 1)
+	go func() {
+		resp, err := get(addr)
+		if err == nil {
+			_, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+		inFlight <- err
+	}()
+	<-started
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("期限を過ぎてもサーバーが停止しませんでした")
+	}
+	assert.Error(t, <-inFlight)
+}
+
+func TestServe_ReturnsServeError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ln.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	err = server.Serve(context.Background(), &http.Server{}, ln, time.Second, logger)
+	assert.Error(t, err)
+}
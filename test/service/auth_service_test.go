@@ -0,0 +1,952 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"memo-app/src/config"
+	"memo-app/src/models"
+	"memo-app/src/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockUserRepository ユーザーリポジトリのモック
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(user *models.User) error {
+	args := m.Called(user)
+	if args.Get(0) != nil {
+		// IDを設定（実際のDBでは自動設定される）
+		user.ID = args.Int(0)
+	}
+	return args.Error(1)
+}
+
+func (m *MockUserRepository) GetByID(id int) (*models.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByUsername(username string) (*models.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(email string) (*models.User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByGitHubID(githubID int64) (*models.User, error) {
+	args := m.Called(githubID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateLastLogin(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) IncrementTokenVersion(userID int) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Delete(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetIPRegistration(ipAddress string) (*models.IPRegistration, error) {
+	args := m.Called(ipAddress)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IPRegistration), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateIPRegistration(ipReg *models.IPRegistration) error {
+	args := m.Called(ipReg)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateIPRegistration(ipReg *models.IPRegistration) error {
+	args := m.Called(ipReg)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetUserCountByIP(ipAddress string) (int, error) {
+	args := m.Called(ipAddress)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserRepository) ResetIPRegistration(ipAddress string) error {
+	args := m.Called(ipAddress)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) IsEmailExists(email string) (bool, error) {
+	args := m.Called(email)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) IsUsernameExists(username string) (bool, error) {
+	args := m.Called(username)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) IsGitHubIDExists(githubID int64) (bool, error) {
+	args := m.Called(githubID)
+	return args.Bool(0), args.Error(1)
+}
+
+// recordingMailer 送信内容を記録するテスト用Mailer
+type recordingMailer struct {
+	to      []string
+	subject []string
+	body    []string
+}
+
+func (m *recordingMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.to = append(m.to, to)
+	m.subject = append(m.subject, subject)
+	m.body = append(m.body, body)
+	return nil
+}
+
+func newAuthTestConfig(verificationExpiresIn time.Duration) *config.Config {
+	return &config.Config{
+		Server: config.ServerConfig{BaseURL: "https://memo.example.com"},
+		Auth: config.AuthConfig{
+			JWTSecret:                  "test-secret-key-for-testing",
+			JWTExpiresIn:               time.Hour,
+			RefreshExpiresIn:           24 * time.Hour,
+			MaxAccountsPerIP:           3,
+			EmailVerificationExpiresIn: verificationExpiresIn,
+		},
+	}
+}
+
+var verificationLinkPattern = regexp.MustCompile(`https://memo\.example\.com/api/auth/verify\?token=(\S+)`)
+
+// registerAndCaptureToken ユーザー登録を行い、確認メールに含まれるトークンを返す
+func registerAndCaptureToken(t *testing.T, cfg *config.Config, userRepo *MockUserRepository) string {
+	t.Helper()
+
+	userRepo.On("IsEmailExists", "user@example.com").Return(false, nil)
+	userRepo.On("IsUsernameExists", "testuser").Return(false, nil)
+	userRepo.On("Create", mock.AnythingOfType("*models.User")).Return(1, nil)
+	userRepo.On("GetIPRegistration", "192.168.1.1").Return(nil, nil)
+	userRepo.On("CreateIPRegistration", mock.AnythingOfType("*models.IPRegistration")).Return(nil)
+
+	recorder := &recordingMailer{}
+	authService := service.NewAuthServiceWithMailer(userRepo, service.NewJWTService(cfg), cfg, recorder)
+
+	_, err := authService.Register(&models.RegisterRequest{
+		Username: "testuser",
+		Email:    "user@example.com",
+		Password: "Password123!",
+	}, "192.168.1.1")
+	require.NoError(t, err)
+
+	require.Len(t, recorder.to, 1)
+	assert.Equal(t, "user@example.com", recorder.to[0])
+	assert.Equal(t, "[Memo App] メールアドレスの確認", recorder.subject[0])
+	assert.Contains(t, recorder.body[0], "testuser")
+
+	matches := verificationLinkPattern.FindStringSubmatch(recorder.body[0])
+	require.Len(t, matches, 2, "確認メールに確認リンクが含まれていること")
+
+	token, err := url.QueryUnescape(matches[1])
+	require.NoError(t, err)
+	return token
+}
+
+func TestAuthService_VerifyEmail(t *testing.T) {
+	t.Run("確認に成功", func(t *testing.T) {
+		cfg := newAuthTestConfig(time.Hour)
+		userRepo := new(MockUserRepository)
+		token := registerAndCaptureToken(t, cfg, userRepo)
+
+		userRepo.On("GetByID", 1).Return(&models.User{ID: 1, Email: "user@example.com", IsActive: true}, nil)
+		userRepo.On("MarkEmailVerified", 1).Return(nil)
+
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+		assert.NoError(t, authService.VerifyEmail(token))
+
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("期限切れのトークン", func(t *testing.T) {
+		cfg := newAuthTestConfig(-time.Minute)
+		userRepo := new(MockUserRepository)
+		token := registerAndCaptureToken(t, cfg, userRepo)
+
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+		err := authService.VerifyEmail(token)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "verification token expired")
+
+		userRepo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything)
+	})
+
+	t.Run("使用済みのトークン", func(t *testing.T) {
+		cfg := newAuthTestConfig(time.Hour)
+		userRepo := new(MockUserRepository)
+		token := registerAndCaptureToken(t, cfg, userRepo)
+
+		// 1回目で確認済みになり、2回目は拒否される
+		userRepo.On("GetByID", 1).Return(&models.User{ID: 1, Email: "user@example.com", IsActive: true}, nil).Once()
+		userRepo.On("MarkEmailVerified", 1).Return(nil).Once()
+		userRepo.On("GetByID", 1).Return(&models.User{ID: 1, Email: "user@example.com", IsActive: true, EmailVerified: true}, nil).Once()
+
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+		require.NoError(t, authService.VerifyEmail(token))
+
+		err := authService.VerifyEmail(token)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "verification token already used")
+
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("不正なトークン", func(t *testing.T) {
+		cfg := newAuthTestConfig(time.Hour)
+		userRepo := new(MockUserRepository)
+
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+		err := authService.VerifyEmail("not-a-token")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid verification token")
+	})
+
+	t.Run("アクセストークンは確認トークンとして使用できない", func(t *testing.T) {
+		cfg := newAuthTestConfig(time.Hour)
+		userRepo := new(MockUserRepository)
+		jwtService := service.NewJWTService(cfg)
+
+		accessToken, err := jwtService.GenerateAccessToken(1)
+		require.NoError(t, err)
+
+		authService := service.NewAuthService(userRepo, jwtService, cfg)
+		err = authService.VerifyEmail(accessToken)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid verification token")
+	})
+}
+
+// memorySessionRepository テスト用のインメモリセッションストア
+type memorySessionRepository struct {
+	sessions []*models.RefreshSession
+}
+
+func (r *memorySessionRepository) Create(session *models.RefreshSession) error {
+	session.ID = len(r.sessions) + 1
+	session.CreatedAt = time.Now()
+	r.sessions = append(r.sessions, session)
+	return nil
+}
+
+func (r *memorySessionRepository) GetByTokenHash(tokenHash string) (*models.RefreshSession, error) {
+	for _, session := range r.sessions {
+		if session.TokenHash == tokenHash {
+			return session, nil
+		}
+	}
+	return nil, errors.New("session not found")
+}
+
+func (r *memorySessionRepository) ListActiveByUserID(userID int) ([]*models.RefreshSession, error) {
+	var active []*models.RefreshSession
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.IsActive(time.Now()) {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+func (r *memorySessionRepository) Revoke(sessionID int) error {
+	now := time.Now()
+	r.sessions[sessionID-1].RevokedAt = &now
+	return nil
+}
+
+func TestAuthService_MaxSessionsPerUser(t *testing.T) {
+	cfg := newAuthTestConfig(time.Hour)
+	cfg.Auth.MaxSessionsPerUser = 3
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("Password123!"), bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash := string(hash)
+	user := &models.User{ID: 1, Email: "user@example.com", PasswordHash: &passwordHash, IsActive: true}
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByEmail", "user@example.com").Return(user, nil)
+	userRepo.On("UpdateLastLogin", 1).Return(nil)
+	userRepo.On("GetByID", 1).Return(user, nil)
+
+	sessions := &memorySessionRepository{}
+	authService := service.NewAuthServiceWithSessions(userRepo, service.NewJWTService(cfg), cfg, &recordingMailer{}, sessions)
+
+	var refreshTokens []string
+	for i := 0; i < 4; i++ {
+		resp, err := authService.Login(&models.LoginRequest{Email: "user@example.com", Password: "Password123!"}, "192.168.1.1")
+		require.NoError(t, err)
+		refreshTokens = append(refreshTokens, resp.RefreshToken)
+	}
+
+	active, err := sessions.ListActiveByUserID(1)
+	require.NoError(t, err)
+	assert.Len(t, active, 3)
+
+	// 4回目のログインで最も古いセッションが失効している
+	_, err = authService.RefreshToken(refreshTokens[0])
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid refresh token")
+
+	// 残りのセッションは有効で、使用したトークンはローテーションにより再利用できない
+	_, err = authService.RefreshToken(refreshTokens[1])
+	require.NoError(t, err)
+	_, err = authService.RefreshToken(refreshTokens[1])
+	assert.Error(t, err)
+}
+
+// memoryPasswordResetRepository テスト用のインメモリパスワード再設定トークンストア
+type memoryPasswordResetRepository struct {
+	resets []*models.PasswordReset
+}
+
+func (r *memoryPasswordResetRepository) Create(reset *models.PasswordReset) error {
+	reset.ID = len(r.resets) + 1
+	reset.CreatedAt = time.Now()
+	r.resets = append(r.resets, reset)
+	return nil
+}
+
+func (r *memoryPasswordResetRepository) GetByTokenHash(tokenHash string) (*models.PasswordReset, error) {
+	for _, reset := range r.resets {
+		if reset.TokenHash == tokenHash {
+			return reset, nil
+		}
+	}
+	return nil, errors.New("password reset not found")
+}
+
+func (r *memoryPasswordResetRepository) MarkUsed(resetID int) error {
+	reset := r.resets[resetID-1]
+	if reset.UsedAt != nil {
+		return errors.New("password reset already used")
+	}
+	now := time.Now()
+	reset.UsedAt = &now
+	return nil
+}
+
+func (r *memoryPasswordResetRepository) InvalidateByUserID(userID int) error {
+	now := time.Now()
+	for _, reset := range r.resets {
+		if reset.UserID == userID && reset.UsedAt == nil {
+			reset.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+var passwordResetLinkPattern = regexp.MustCompile(`https://memo\.example\.com/reset-password\?token=(\S+)`)
+
+func TestAuthService_PasswordReset(t *testing.T) {
+	newUser := func(t *testing.T) *models.User {
+		hash, err := bcrypt.GenerateFromPassword([]byte("OldPassword123!"), bcrypt.MinCost)
+		require.NoError(t, err)
+		passwordHash := string(hash)
+		return &models.User{ID: 1, Username: "testuser", Email: "user@example.com", PasswordHash: &passwordHash, IsActive: true}
+	}
+
+	// requestToken 再設定をリクエストし、メールに含まれるトークンを返す
+	requestToken := func(t *testing.T, authService service.AuthService, recorder *recordingMailer) string {
+		t.Helper()
+		require.NoError(t, authService.RequestPasswordReset("user@example.com"))
+
+		require.NotEmpty(t, recorder.to)
+		last := len(recorder.to) - 1
+		assert.Equal(t, "user@example.com", recorder.to[last])
+		assert.Equal(t, "[Memo App] パスワードの再設定", recorder.subject[last])
+
+		matches := passwordResetLinkPattern.FindStringSubmatch(recorder.body[last])
+		require.Len(t, matches, 2, "メールに再設定リンクが含まれていること")
+		token, err := url.QueryUnescape(matches[1])
+		require.NoError(t, err)
+		return token
+	}
+
+	setup := func(t *testing.T, expiresIn time.Duration) (service.AuthService, *MockUserRepository, *memoryPasswordResetRepository, *recordingMailer, *models.User) {
+		cfg := newAuthTestConfig(time.Hour)
+		cfg.Auth.PasswordResetExpiresIn = expiresIn
+
+		user := newUser(t)
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByEmail", "user@example.com").Return(user, nil)
+		userRepo.On("GetByID", 1).Return(user, nil)
+
+		resets := &memoryPasswordResetRepository{}
+		recorder := &recordingMailer{}
+		authService := service.NewAuthServiceWithPasswordResets(userRepo, service.NewJWTService(cfg), cfg, recorder, nil, resets)
+		return authService, userRepo, resets, recorder, user
+	}
+
+	t.Run("再設定に成功し、トークンは再利用できない", func(t *testing.T) {
+		authService, userRepo, resets, recorder, user := setup(t, time.Hour)
+		userRepo.On("Update", user).Return(nil).Once()
+
+		token := requestToken(t, authService, recorder)
+		// 平文のトークンは保存しない
+		require.Len(t, resets.resets, 1)
+		assert.NotEqual(t, token, resets.resets[0].TokenHash)
+
+		require.NoError(t, authService.ResetPassword(token, "Sunny-Harbor7"))
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte("Sunny-Harbor7")))
+
+		err := authService.ResetPassword(token, "Quiet-River9")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "password reset token already used")
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("新しいトークンを発行すると以前のトークンは無効になる", func(t *testing.T) {
+		authService, userRepo, _, recorder, _ := setup(t, time.Hour)
+
+		first := requestToken(t, authService, recorder)
+		requestToken(t, authService, recorder)
+
+		err := authService.ResetPassword(first, "Sunny-Harbor7")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "password reset token already used")
+		userRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("期限切れのトークン", func(t *testing.T) {
+		authService, userRepo, _, recorder, _ := setup(t, -time.Minute)
+
+		token := requestToken(t, authService, recorder)
+		err := authService.ResetPassword(token, "Sunny-Harbor7")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "password reset token expired")
+		userRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("弱いパスワードは拒否され、トークンは使用済みにならない", func(t *testing.T) {
+		authService, userRepo, resets, recorder, _ := setup(t, time.Hour)
+
+		token := requestToken(t, authService, recorder)
+		err := authService.ResetPassword(token, "password")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "weak password")
+		assert.Nil(t, resets.resets[0].UsedAt)
+		userRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("不正なトークン", func(t *testing.T) {
+		authService, _, _, _, _ := setup(t, time.Hour)
+
+		err := authService.ResetPassword("unknown-token", "Sunny-Harbor7")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid password reset token")
+	})
+
+	t.Run("未登録のメールアドレスでもエラーにせず、メールも送信しない", func(t *testing.T) {
+		cfg := newAuthTestConfig(time.Hour)
+		cfg.Auth.PasswordResetExpiresIn = time.Hour
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByEmail", "unknown@example.com").Return(nil, errors.New("user not found"))
+
+		resets := &memoryPasswordResetRepository{}
+		recorder := &recordingMailer{}
+		authService := service.NewAuthServiceWithPasswordResets(userRepo, service.NewJWTService(cfg), cfg, recorder, nil, resets)
+
+		assert.NoError(t, authService.RequestPasswordReset("unknown@example.com"))
+		assert.Empty(t, recorder.to)
+		assert.Empty(t, resets.resets)
+	})
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	cfg := newAuthTestConfig(time.Hour)
+
+	newUser := func(t *testing.T) *models.User {
+		hash, err := bcrypt.GenerateFromPassword([]byte("Quiet-River9"), bcrypt.MinCost)
+		require.NoError(t, err)
+		passwordHash := string(hash)
+		return &models.User{ID: 1, Email: "user@example.com", PasswordHash: &passwordHash, IsActive: true}
+	}
+
+	t.Run("変更に成功し、既存のセッションは失効する", func(t *testing.T) {
+		user := newUser(t)
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByEmail", "user@example.com").Return(user, nil)
+		userRepo.On("UpdateLastLogin", 1).Return(nil)
+		userRepo.On("GetByID", 1).Return(user, nil)
+		userRepo.On("Update", user).Return(nil).Once()
+
+		sessions := &memorySessionRepository{}
+		authService := service.NewAuthServiceWithSessions(userRepo, service.NewJWTService(cfg), cfg, &recordingMailer{}, sessions)
+
+		resp, err := authService.Login(&models.LoginRequest{Email: "user@example.com", Password: "Quiet-River9"}, "192.168.1.1")
+		require.NoError(t, err)
+
+		require.NoError(t, authService.ChangePassword(1, "Quiet-River9", "Sunny-Harbor7"))
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte("Sunny-Harbor7")))
+
+		_, err = authService.RefreshToken(resp.RefreshToken)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid refresh token")
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("現在のパスワードが違う", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByID", 1).Return(newUser(t), nil)
+
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+		err := authService.ChangePassword(1, "Wrong-Pass1", "Sunny-Harbor7")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid current password")
+		userRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("弱いパスワード", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByID", 1).Return(newUser(t), nil)
+
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+		err := authService.ChangePassword(1, "Quiet-River9", "password")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "weak password")
+		userRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("現在と同じパスワード", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByID", 1).Return(newUser(t), nil)
+
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+		err := authService.ChangePassword(1, "Quiet-River9", "Quiet-River9")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must differ")
+	})
+
+	t.Run("GitHub認証のみのアカウント", func(t *testing.T) {
+		githubID := int64(12345)
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByID", 2).Return(&models.User{ID: 2, GitHubID: &githubID, IsActive: true}, nil)
+
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+		err := authService.ChangePassword(2, "anything", "Sunny-Harbor7")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "external authentication")
+	})
+}
+
+func TestAuthService_LogoutAll(t *testing.T) {
+	cfg := newAuthTestConfig(time.Hour)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("Quiet-River9"), bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash := string(hash)
+	user := &models.User{ID: 1, Email: "user@example.com", PasswordHash: &passwordHash, IsActive: true}
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByEmail", "user@example.com").Return(user, nil)
+	userRepo.On("UpdateLastLogin", 1).Return(nil)
+	userRepo.On("GetByID", 1).Return(user, nil)
+	userRepo.On("IncrementTokenVersion", 1).Run(func(args mock.Arguments) {
+		user.TokenVersion++
+	}).Return(1, nil).Once()
+
+	authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+	login := func() *models.AuthResponse {
+		resp, err := authService.Login(&models.LoginRequest{Email: "user@example.com", Password: "Quiet-River9"}, "192.168.1.1")
+		require.NoError(t, err)
+		return resp
+	}
+
+	// 2台の端末でログイン
+	first, second := login(), login()
+	_, err = authService.ValidateToken(first.AccessToken)
+	require.NoError(t, err)
+
+	require.NoError(t, authService.LogoutAll(1))
+
+	// ログアウト前に発行されたトークンはすべて無効
+	for _, resp := range []*models.AuthResponse{first, second} {
+		_, err = authService.ValidateToken(resp.AccessToken)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "token revoked")
+
+		_, err = authService.RefreshToken(resp.RefreshToken)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid refresh token")
+	}
+
+	// 再ログインすると新しいバージョンのトークンが発行される
+	_, err = authService.ValidateToken(login().AccessToken)
+	assert.NoError(t, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestAuthService_LoginThrottle(t *testing.T) {
+	cfg := newAuthTestConfig(time.Hour)
+	cfg.Auth.LoginMaxAttempts = 3
+	cfg.Auth.LoginMaxAttemptsPerIP = 10
+	cfg.Auth.LoginAttemptWindow = time.Minute
+	cfg.Auth.LoginLockoutDuration = time.Minute
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("Quiet-River9"), bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash := string(hash)
+	user := &models.User{ID: 1, Email: "user@example.com", PasswordHash: &passwordHash, IsActive: true}
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByEmail", "user@example.com").Return(user, nil)
+	userRepo.On("UpdateLastLogin", 1).Return(nil)
+
+	authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+	login := func(password, clientIP string) error {
+		_, err := authService.Login(&models.LoginRequest{Email: "user@example.com", Password: password}, clientIP)
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		err := login("Wrong-Pass1", "192.168.1.1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid credentials")
+	}
+
+	// 正しいパスワードでもロック中は拒否する
+	err = login("Quiet-River9", "192.168.1.1")
+	var throttled *service.LoginThrottledError
+	require.True(t, errors.As(err, &throttled))
+	assert.Greater(t, throttled.RetryAfter, time.Duration(0))
+
+	// 別のIPからはログインできる
+	assert.NoError(t, login("Quiet-River9", "192.168.1.2"))
+}
+
+func TestAuthService_LoginIncludesRole(t *testing.T) {
+	cfg := newAuthTestConfig(time.Hour)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("Quiet-River9"), bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash := string(hash)
+	user := &models.User{ID: 1, Email: "admin@example.com", PasswordHash: &passwordHash, IsActive: true, Role: models.RoleAdmin}
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByEmail", "admin@example.com").Return(user, nil)
+	userRepo.On("UpdateLastLogin", 1).Return(nil)
+
+	jwtService := service.NewJWTService(cfg)
+	authService := service.NewAuthService(userRepo, jwtService, cfg)
+	resp, err := authService.Login(&models.LoginRequest{Email: "admin@example.com", Password: "Quiet-River9"}, "192.168.1.1")
+	require.NoError(t, err)
+
+	// レスポンスのユーザーとアクセストークンにロールを含める
+	assert.Equal(t, models.RoleAdmin, resp.User.Role)
+	claims, err := jwtService.ValidateToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, claims.Role)
+}
+
+func TestAuthService_GetProfile(t *testing.T) {
+	cfg := newAuthTestConfig(time.Hour)
+	githubID := int64(12345)
+	user := &models.User{ID: 1, Username: "octocat", GitHubID: &githubID, IsActive: true}
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByID", 1).Return(user, nil)
+	userRepo.On("GetByID", 2).Return(nil, sql.ErrNoRows)
+
+	authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+
+	got, err := authService.GetProfile(1)
+	require.NoError(t, err)
+	assert.Equal(t, models.AuthProviderGitHub, got.ToPublic().AuthProvider)
+
+	_, err = authService.GetProfile(2)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestAuthService_ReservedUsernamesFromConfig(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	cfg := newAuthTestConfig(time.Hour)
+	cfg.Auth.ReservedUsernames = []string{"AcmeCorp"}
+
+	t.Run("設定した予約語では登録できない", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+
+		_, err := authService.Register(&models.RegisterRequest{
+			Username: "acmecorp",
+			Email:    "user@example.com",
+			Password: "Password123!",
+		}, "192.168.1.1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "username is reserved")
+		userRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("設定した予約語には変更できない", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByID", 1).Return(&models.User{ID: 1, Username: "testuser", Email: "user@example.com", IsActive: true}, nil)
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+
+		_, err := authService.UpdateProfile(1, &models.UpdateProfileRequest{Username: strPtr("ACMECORP")})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid profile")
+		userRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("通常のユーザー名は登録できる", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetIPRegistration", "192.168.1.1").Return(nil, nil)
+		userRepo.On("IsEmailExists", "user@example.com").Return(false, nil)
+		userRepo.On("IsUsernameExists", "normal_user1").Return(false, nil)
+		userRepo.On("Create", mock.AnythingOfType("*models.User")).Return(1, nil)
+		userRepo.On("CreateIPRegistration", mock.AnythingOfType("*models.IPRegistration")).Return(nil)
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+
+		_, err := authService.Register(&models.RegisterRequest{
+			Username: "normal_user1",
+			Email:    "user@example.com",
+			Password: "Password123!",
+		}, "192.168.1.1")
+		require.NoError(t, err)
+		userRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_UpdateProfile(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	setup := func(t *testing.T) (service.AuthService, *MockUserRepository, *recordingMailer, *models.User) {
+		cfg := newAuthTestConfig(time.Hour)
+		cfg.Auth.ReverifyEmailOnChange = true
+
+		user := &models.User{ID: 1, Username: "testuser", Email: "user@example.com", IsActive: true, EmailVerified: true}
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByID", 1).Return(user, nil)
+
+		recorder := &recordingMailer{}
+		return service.NewAuthServiceWithMailer(userRepo, service.NewJWTService(cfg), cfg, recorder), userRepo, recorder, user
+	}
+
+	t.Run("メールアドレスを変更すると未確認に戻して確認メールを送る", func(t *testing.T) {
+		authService, userRepo, recorder, _ := setup(t)
+		userRepo.On("IsUsernameExists", "newname").Return(false, nil)
+		userRepo.On("IsEmailExists", "new@example.com").Return(false, nil)
+		userRepo.On("Update", mock.AnythingOfType("*models.User")).Return(nil)
+
+		updated, err := authService.UpdateProfile(1, &models.UpdateProfileRequest{Username: strPtr("newname"), Email: strPtr("new@example.com")})
+		require.NoError(t, err)
+		assert.Equal(t, "newname", updated.Username)
+		assert.Equal(t, "new@example.com", updated.Email)
+		assert.False(t, updated.EmailVerified)
+		assert.Equal(t, []string{"new@example.com"}, recorder.to)
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("現在と同じ値は重複チェックしない", func(t *testing.T) {
+		authService, userRepo, recorder, _ := setup(t)
+		userRepo.On("Update", mock.AnythingOfType("*models.User")).Return(nil)
+
+		updated, err := authService.UpdateProfile(1, &models.UpdateProfileRequest{Username: strPtr("testuser"), Email: strPtr("User@Example.com")})
+		require.NoError(t, err)
+		assert.True(t, updated.EmailVerified)
+		assert.Empty(t, recorder.to)
+		userRepo.AssertNotCalled(t, "IsUsernameExists", mock.Anything)
+		userRepo.AssertNotCalled(t, "IsEmailExists", mock.Anything)
+	})
+
+	t.Run("他のユーザーが使用中の値は拒否する", func(t *testing.T) {
+		authService, userRepo, _, _ := setup(t)
+		userRepo.On("IsUsernameExists", "taken").Return(true, nil)
+		userRepo.On("IsEmailExists", "taken@example.com").Return(true, nil)
+
+		_, err := authService.UpdateProfile(1, &models.UpdateProfileRequest{Username: strPtr("taken")})
+		assert.EqualError(t, err, "username already exists")
+		_, err = authService.UpdateProfile(1, &models.UpdateProfileRequest{Email: strPtr("taken@example.com")})
+		assert.EqualError(t, err, "email already exists")
+		userRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("不正な形式とGitHub連携のフィールドは拒否する", func(t *testing.T) {
+		authService, userRepo, _, _ := setup(t)
+
+		_, err := authService.UpdateProfile(1, &models.UpdateProfileRequest{Username: strPtr("bad name!")})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid profile")
+		_, err = authService.UpdateProfile(1, &models.UpdateProfileRequest{Email: strPtr("not-an-email")})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid profile")
+		_, err = authService.UpdateProfile(1, &models.UpdateProfileRequest{GitHubUsername: strPtr("octocat")})
+		assert.EqualError(t, err, "read-only field: github_username")
+		userRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestAuthService_DeleteAccount(t *testing.T) {
+	cfg := newAuthTestConfig(time.Hour)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("Quiet-River9"), bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash := string(hash)
+
+	t.Run("削除後はトークンもログインも使えない", func(t *testing.T) {
+		user := &models.User{ID: 1, Email: "user@example.com", PasswordHash: &passwordHash, IsActive: true}
+
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByEmail", "user@example.com").Return(user, nil).Once()
+		userRepo.On("UpdateLastLogin", 1).Return(nil)
+		userRepo.On("GetByID", 1).Return(user, nil).Once()
+		userRepo.On("IncrementTokenVersion", 1).Return(1, nil).Once()
+		userRepo.On("Delete", 1).Return(nil).Once()
+		// 削除後はユーザーが存在しない
+		userRepo.On("GetByID", 1).Return(nil, sql.ErrNoRows)
+		userRepo.On("GetByEmail", "user@example.com").Return(nil, sql.ErrNoRows)
+
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+		resp, err := authService.Login(&models.LoginRequest{Email: "user@example.com", Password: "Quiet-River9"}, "192.168.1.1")
+		require.NoError(t, err)
+
+		require.NoError(t, authService.DeleteAccount(1, "Quiet-River9"))
+
+		_, err = authService.ValidateToken(resp.AccessToken)
+		assert.Error(t, err)
+		_, err = authService.RefreshToken(resp.RefreshToken)
+		assert.Error(t, err)
+		_, err = authService.Login(&models.LoginRequest{Email: "user@example.com", Password: "Quiet-River9"}, "192.168.1.1")
+		assert.Error(t, err)
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("ローカル認証のアカウントはパスワードが必要", func(t *testing.T) {
+		user := &models.User{ID: 1, Email: "user@example.com", PasswordHash: &passwordHash, IsActive: true}
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByID", 1).Return(user, nil)
+
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+		assert.EqualError(t, authService.DeleteAccount(1, ""), "password required")
+		assert.EqualError(t, authService.DeleteAccount(1, "Wrong-River9"), "invalid password")
+		userRepo.AssertNotCalled(t, "IncrementTokenVersion", mock.Anything)
+		userRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("GitHub認証のみのアカウントはパスワード不要", func(t *testing.T) {
+		githubID := int64(12345)
+		user := &models.User{ID: 2, Email: "octocat@example.com", GitHubID: &githubID, IsActive: true}
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByID", 2).Return(user, nil)
+		userRepo.On("IncrementTokenVersion", 2).Return(1, nil)
+		userRepo.On("Delete", 2).Return(nil)
+
+		authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+		require.NoError(t, authService.DeleteAccount(2, ""))
+		userRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_CheckIPLimit(t *testing.T) {
+	cfg := newAuthTestConfig(time.Hour)
+	cfg.Auth.IPRegistrationWindow = 30 * 24 * time.Hour
+
+	check := func(t *testing.T, ipReg *models.IPRegistration) error {
+		userRepo := new(MockUserRepository)
+		if ipReg == nil {
+			userRepo.On("GetIPRegistration", "192.168.1.1").Return(nil, nil)
+		} else {
+			userRepo.On("GetIPRegistration", "192.168.1.1").Return(ipReg, nil)
+		}
+		return service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg).CheckIPLimit("192.168.1.1")
+	}
+
+	t.Run("登録がないIPは許可", func(t *testing.T) {
+		assert.NoError(t, check(t, nil))
+	})
+
+	t.Run("期間内に上限に達したIPは拒否", func(t *testing.T) {
+		started := time.Now().Add(-cfg.Auth.IPRegistrationWindow + time.Minute)
+		err := check(t, &models.IPRegistration{IPAddress: "192.168.1.1", UserCount: 3, WindowStartedAt: started})
+		assert.EqualError(t, err, "maximum number of accounts per IP address exceeded")
+	})
+
+	t.Run("期間を過ぎたIPは再び許可", func(t *testing.T) {
+		started := time.Now().Add(-cfg.Auth.IPRegistrationWindow - time.Second)
+		assert.NoError(t, check(t, &models.IPRegistration{IPAddress: "192.168.1.1", UserCount: 3, WindowStartedAt: started}))
+	})
+
+	t.Run("期間が0の場合は累計で数える", func(t *testing.T) {
+		cumulative := newAuthTestConfig(time.Hour)
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetIPRegistration", "192.168.1.1").Return(&models.IPRegistration{UserCount: 3, WindowStartedAt: time.Now().AddDate(-1, 0, 0)}, nil)
+		err := service.NewAuthService(userRepo, service.NewJWTService(cumulative), cumulative).CheckIPLimit("192.168.1.1")
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthService_RegisterRestartsExpiredIPWindow(t *testing.T) {
+	cfg := newAuthTestConfig(time.Hour)
+	cfg.Auth.IPRegistrationWindow = 24 * time.Hour
+
+	expired := time.Now().Add(-25 * time.Hour)
+	ipReg := &models.IPRegistration{ID: 1, IPAddress: "192.168.1.1", UserCount: 3, LastUsedAt: expired, WindowStartedAt: expired}
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetIPRegistration", "192.168.1.1").Return(ipReg, nil)
+	userRepo.On("IsEmailExists", "user@example.com").Return(false, nil)
+	userRepo.On("IsUsernameExists", "testuser").Return(false, nil)
+	userRepo.On("Create", mock.AnythingOfType("*models.User")).Return(1, nil)
+	userRepo.On("UpdateIPRegistration", mock.AnythingOfType("*models.IPRegistration")).Return(nil)
+
+	authService := service.NewAuthServiceWithMailer(userRepo, service.NewJWTService(cfg), cfg, &recordingMailer{})
+	_, err := authService.Register(&models.RegisterRequest{Username: "testuser", Email: "user@example.com", Password: "Password123!"}, "192.168.1.1")
+	require.NoError(t, err)
+
+	// 期間を過ぎた登録数は破棄され、この登録から数え直す
+	updated := userRepo.Calls[len(userRepo.Calls)-1].Arguments.Get(0).(*models.IPRegistration)
+	assert.Equal(t, 1, updated.UserCount)
+	assert.WithinDuration(t, time.Now(), updated.WindowStartedAt, time.Minute)
+	userRepo.AssertExpectations(t)
+}
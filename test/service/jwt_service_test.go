@@ -1,10 +1,13 @@
 package service
 
 import (
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"memo-app/src/config"
+	"memo-app/src/models"
 	"memo-app/src/service"
 
 	"github.com/stretchr/testify/assert"
@@ -216,3 +219,198 @@ func TestJWTService_TokenTypes(t *testing.T) {
 	_, err = jwtService.ValidateAccessToken(refreshToken)
 	assert.Error(t, err, "リフレッシュトークンはアクセストークンとして検証されるべきではない")
 }
+
+func TestJWTService_TokenVersion(t *testing.T) {
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			JWTSecret:        "test-secret-key-for-testing",
+			JWTExpiresIn:     24 * time.Hour,
+			RefreshExpiresIn: 7 * 24 * time.Hour,
+		},
+	}
+	jwtService := service.NewJWTService(cfg)
+
+	accessToken, err := jwtService.GenerateAccessTokenWithVersion(123, 3)
+	require.NoError(t, err)
+	claims, err := jwtService.ValidateToken(accessToken)
+	require.NoError(t, err)
+	assert.Equal(t, 3, claims.TokenVersion)
+
+	refreshToken, err := jwtService.GenerateRefreshTokenWithVersion(123, 3)
+	require.NoError(t, err)
+	claims, err = jwtService.ValidateRefreshToken(refreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, 3, claims.TokenVersion)
+
+	// バージョンを指定しない場合は0
+	accessToken, err = jwtService.GenerateAccessToken(123)
+	require.NoError(t, err)
+	claims, err = jwtService.ValidateToken(accessToken)
+	require.NoError(t, err)
+	assert.Equal(t, 0, claims.TokenVersion)
+}
+
+func TestJWTService_Role(t *testing.T) {
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			JWTSecret:        "test-secret-key-for-testing",
+			JWTExpiresIn:     24 * time.Hour,
+			RefreshExpiresIn: 7 * 24 * time.Hour,
+		},
+	}
+	jwtService := service.NewJWTService(cfg)
+
+	accessToken, err := jwtService.GenerateAccessTokenWithRole(123, 2, models.RoleAdmin)
+	require.NoError(t, err)
+	claims, err := jwtService.ValidateToken(accessToken)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, claims.Role)
+	assert.Equal(t, 2, claims.TokenVersion)
+
+	// ロールを指定しない場合は含めない
+	accessToken, err = jwtService.GenerateAccessTokenWithVersion(123, 2)
+	require.NoError(t, err)
+	claims, err = jwtService.ValidateToken(accessToken)
+	require.NoError(t, err)
+	assert.Empty(t, claims.Role)
+}
+
+// memoryRevokedTokens はメモリ上で失効トークンを保持するリポジトリ
+type memoryRevokedTokens struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+	err     error
+}
+
+func newMemoryRevokedTokens() *memoryRevokedTokens {
+	return &memoryRevokedTokens{revoked: make(map[string]time.Time)}
+}
+
+func (r *memoryRevokedTokens) Revoke(jti string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revoked[jti] = expiresAt
+	return nil
+}
+
+func (r *memoryRevokedTokens) IsRevoked(jti string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return false, r.err
+	}
+	_, ok := r.revoked[jti]
+	return ok, nil
+}
+
+func (r *memoryRevokedTokens) DeleteExpired(now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for jti, expiresAt := range r.revoked {
+		if !expiresAt.After(now) {
+			delete(r.revoked, jti)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestJWTService_InvalidateToken(t *testing.T) {
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			JWTSecret:        "test-secret-key-for-testing",
+			JWTExpiresIn:     24 * time.Hour,
+			RefreshExpiresIn: 7 * 24 * time.Hour,
+		},
+	}
+
+	t.Run("失効させたアクセストークンは検証に失敗する", func(t *testing.T) {
+		revoked := newMemoryRevokedTokens()
+		jwtService := service.NewJWTServiceWithRevocation(cfg, revoked)
+
+		token, err := jwtService.GenerateAccessToken(1)
+		require.NoError(t, err)
+		other, err := jwtService.GenerateAccessToken(1)
+		require.NoError(t, err)
+
+		require.NoError(t, jwtService.InvalidateToken(token))
+
+		_, err = jwtService.ValidateToken(token)
+		assert.Error(t, err)
+		_, err = jwtService.ValidateAccessToken(token)
+		assert.Error(t, err)
+
+		// 同じユーザーの他のトークンは有効なまま
+		userID, err := jwtService.ValidateAccessToken(other)
+		require.NoError(t, err)
+		assert.Equal(t, 1, userID)
+
+		// 有効期限まで保持する
+		claims, err := jwtService.ValidateToken(other)
+		require.NoError(t, err)
+		assert.NotEmpty(t, claims.ID)
+		require.Len(t, revoked.revoked, 1)
+		for _, expiresAt := range revoked.revoked {
+			assert.WithinDuration(t, time.Now().Add(24*time.Hour), expiresAt, time.Minute)
+		}
+	})
+
+	t.Run("失効させたリフレッシュトークンは検証に失敗する", func(t *testing.T) {
+		jwtService := service.NewJWTServiceWithRevocation(cfg, newMemoryRevokedTokens())
+
+		token, err := jwtService.GenerateRefreshToken(1)
+		require.NoError(t, err)
+		require.NoError(t, jwtService.InvalidateToken(token))
+
+		_, err = jwtService.ValidateRefreshToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("IsTokenInvalidated", func(t *testing.T) {
+		jwtService := service.NewJWTServiceWithRevocation(cfg, newMemoryRevokedTokens())
+
+		token, err := jwtService.GenerateAccessToken(1)
+		require.NoError(t, err)
+		claims, err := jwtService.ValidateToken(token)
+		require.NoError(t, err)
+
+		invalidated, err := jwtService.IsTokenInvalidated(claims.ID)
+		require.NoError(t, err)
+		assert.False(t, invalidated)
+
+		require.NoError(t, jwtService.InvalidateToken(token))
+		invalidated, err = jwtService.IsTokenInvalidated(claims.ID)
+		require.NoError(t, err)
+		assert.True(t, invalidated)
+	})
+
+	t.Run("失効の確認に失敗した場合は拒否する", func(t *testing.T) {
+		revoked := newMemoryRevokedTokens()
+		jwtService := service.NewJWTServiceWithRevocation(cfg, revoked)
+
+		token, err := jwtService.GenerateAccessToken(1)
+		require.NoError(t, err)
+		revoked.err = errors.New("connection refused")
+
+		_, err = jwtService.ValidateToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("不正なトークンはエラー", func(t *testing.T) {
+		jwtService := service.NewJWTServiceWithRevocation(cfg, newMemoryRevokedTokens())
+		assert.Error(t, jwtService.InvalidateToken("invalid.token.here"))
+	})
+
+	t.Run("リポジトリがない場合はエラー", func(t *testing.T) {
+		jwtService := service.NewJWTService(cfg)
+
+		token, err := jwtService.GenerateAccessToken(1)
+		require.NoError(t, err)
+		assert.Error(t, jwtService.InvalidateToken(token))
+
+		// 失効を確認できないだけで検証は通る
+		_, err = jwtService.ValidateToken(token)
+		assert.NoError(t, err)
+	})
+}
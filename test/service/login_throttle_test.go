@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"memo-app/src/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock はテストで進められる時計
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestLoginThrottle(clock *fakeClock) *service.LoginThrottle {
+	return service.NewLoginThrottleWithClock(service.LoginThrottleConfig{
+		MaxAttempts:      3,
+		MaxAttemptsPerIP: 5,
+		Window:           time.Minute,
+		Lockout:          10 * time.Minute,
+	}, clock.Now)
+}
+
+func TestLoginThrottle(t *testing.T) {
+	t.Run("上限に達するとロックし、期間の経過で解除する", func(t *testing.T) {
+		clock := &fakeClock{now: time.Now()}
+		throttle := newTestLoginThrottle(clock)
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, throttle.Check("user@example.com", "10.0.0.1"))
+			throttle.RecordFailure("user@example.com", "10.0.0.1")
+		}
+
+		err := throttle.Check("user@example.com", "10.0.0.1")
+		var throttled *service.LoginThrottledError
+		require.True(t, errors.As(err, &throttled))
+		assert.Equal(t, 10*time.Minute, throttled.RetryAfter)
+		assert.Contains(t, err.Error(), "too many login attempts")
+
+		clock.now = clock.now.Add(10 * time.Minute)
+		assert.NoError(t, throttle.Check("user@example.com", "10.0.0.1"))
+	})
+
+	t.Run("メールアドレスの大文字小文字は区別しない", func(t *testing.T) {
+		throttle := newTestLoginThrottle(&fakeClock{now: time.Now()})
+		throttle.RecordFailure("User@Example.com", "10.0.0.1")
+		throttle.RecordFailure("user@example.com", "10.0.0.1")
+		throttle.RecordFailure(" USER@example.com", "10.0.0.1")
+
+		assert.Error(t, throttle.Check("user@example.com", "10.0.0.1"))
+	})
+
+	t.Run("別のIPからの失敗では被害者はロックされない", func(t *testing.T) {
+		throttle := newTestLoginThrottle(&fakeClock{now: time.Now()})
+		for i := 0; i < 3; i++ {
+			throttle.RecordFailure("victim@example.com", "10.0.0.66")
+		}
+
+		assert.Error(t, throttle.Check("victim@example.com", "10.0.0.66"))
+		assert.NoError(t, throttle.Check("victim@example.com", "10.0.0.1"))
+	})
+
+	t.Run("同じIPからは複数のメールアドレスにまたがってロックする", func(t *testing.T) {
+		throttle := newTestLoginThrottle(&fakeClock{now: time.Now()})
+		for i := 0; i < 5; i++ {
+			throttle.RecordFailure(string(rune('a'+i))+"@example.com", "10.0.0.66")
+		}
+
+		assert.Error(t, throttle.Check("other@example.com", "10.0.0.66"))
+		assert.NoError(t, throttle.Check("other@example.com", "10.0.0.1"))
+	})
+
+	t.Run("期間を過ぎた失敗は数えない", func(t *testing.T) {
+		clock := &fakeClock{now: time.Now()}
+		throttle := newTestLoginThrottle(clock)
+		throttle.RecordFailure("user@example.com", "10.0.0.1")
+		throttle.RecordFailure("user@example.com", "10.0.0.1")
+
+		clock.now = clock.now.Add(2 * time.Minute)
+		throttle.RecordFailure("user@example.com", "10.0.0.1")
+		assert.NoError(t, throttle.Check("user@example.com", "10.0.0.1"))
+	})
+
+	t.Run("成功すると失敗回数を消す", func(t *testing.T) {
+		throttle := newTestLoginThrottle(&fakeClock{now: time.Now()})
+		throttle.RecordFailure("user@example.com", "10.0.0.1")
+		throttle.RecordFailure("user@example.com", "10.0.0.1")
+		throttle.RecordSuccess("user@example.com", "10.0.0.1")
+		throttle.RecordFailure("user@example.com", "10.0.0.1")
+
+		assert.NoError(t, throttle.Check("user@example.com", "10.0.0.1"))
+	})
+
+	t.Run("上限が0の場合は制限しない", func(t *testing.T) {
+		throttle := service.NewLoginThrottle(service.LoginThrottleConfig{Window: time.Minute, Lockout: time.Minute})
+		for i := 0; i < 100; i++ {
+			throttle.RecordFailure("user@example.com", "10.0.0.1")
+		}
+		assert.NoError(t, throttle.Check("user@example.com", "10.0.0.1"))
+	})
+}
@@ -0,0 +1,166 @@
+package service_test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"memo-app/src/models"
+	"memo-app/src/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyTransport 最初のfailures回は接続エラー（dial失敗）を返すRoundTripper
+type flakyTransport struct {
+	failures int32
+	calls    int32
+	next     http.RoundTripper
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.AddInt32(&t.calls, 1) <= t.failures {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}
+	return t.next.RoundTrip(req)
+}
+
+func TestOAuthHTTPClient_GetRetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`ok`))
+	}))
+	defer server.Close()
+
+	client := service.NewOAuthHTTPClient(server.Client(), 2, time.Millisecond)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestOAuthHTTPClient_GetGivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := service.NewOAuthHTTPClient(server.Client(), 2, time.Millisecond)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestOAuthHTTPClient_PostRetriesOnlyConnectionErrors(t *testing.T) {
+	t.Run("接続エラーはリトライしてボディを再送する", func(t *testing.T) {
+		var received string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received = string(body)
+			w.Write([]byte(`ok`))
+		}))
+		defer server.Close()
+
+		transport := &flakyTransport{failures: 2, next: server.Client().Transport}
+		client := service.NewOAuthHTTPClient(&http.Client{Transport: transport}, 2, time.Millisecond)
+		req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("code=abc"))
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&transport.calls))
+		assert.Equal(t, "code=abc", received)
+	})
+
+	for _, status := range []int{http.StatusBadRequest, http.StatusInternalServerError} {
+		t.Run("ステータス "+http.StatusText(status)+" はリトライしない", func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			client := service.NewOAuthHTTPClient(server.Client(), 2, time.Millisecond)
+			req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("code=abc"))
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, status, resp.StatusCode)
+			assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestAuthService_HandleGitHubCallback_RetriesFlakyProvider(t *testing.T) {
+	var tokenCalls, userCalls int32
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login/oauth/access_token":
+			atomic.AddInt32(&tokenCalls, 1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"gho_test","token_type":"bearer"}`))
+		case "/user":
+			// ユーザー情報APIは一時的に5xxを返す
+			if atomic.AddInt32(&userCalls, 1) <= 2 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			assert.Equal(t, "token gho_test", r.Header.Get("Authorization"))
+			w.Write([]byte(`{"id":42,"login":"octocat","email":"octocat@example.com"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer provider.Close()
+
+	cfg := newAuthTestConfig(time.Hour)
+	cfg.Auth.GitHubOAuthBaseURL = provider.URL
+	cfg.Auth.GitHubAPIBaseURL = provider.URL
+	cfg.Auth.OAuthMaxRetries = 2
+	cfg.Auth.OAuthRetryBackoff = time.Millisecond
+
+	githubID := int64(42)
+	existing := &models.User{ID: 7, Username: "octocat", Email: "octocat@example.com", GitHubID: &githubID, IsActive: true}
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByGitHubID", githubID).Return(existing, nil)
+	userRepo.On("UpdateLastLogin", 7).Return(nil)
+
+	authService := service.NewAuthService(userRepo, service.NewJWTService(cfg), cfg)
+	resp, err := authService.HandleGitHubCallback("code", "state", "192.0.2.1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "octocat", resp.User.Username)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenCalls))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&userCalls))
+	userRepo.AssertExpectations(t)
+	userRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -45,6 +45,19 @@ func (m *MockMemoRepository) Delete(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
+func (m *MockMemoRepository) Trash(ctx context.Context, id int) (*models.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Memo), args.Error(1)
+}
+
+func (m *MockMemoRepository) PermanentDelete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func TestMemoService_ValidateCreateRequest(t *testing.T) {
 	logger := logrus.New()
 	mockRepo := new(MockMemoRepository)
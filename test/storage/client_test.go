@@ -0,0 +1,162 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"memo-app/src/storage"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// minioConfig テスト用MinIO（docker-compose.test.yml の minio-test）の接続設定
+func minioConfig(bucket string) *storage.S3Config {
+	return &storage.S3Config{
+		Endpoint:        getEnvOrDefault("S3_ENDPOINT", "http://localhost:9000"),
+		AccessKeyID:     getEnvOrDefault("S3_ACCESS_KEY_ID", "minioadmin"),
+		SecretAccessKey: getEnvOrDefault("S3_SECRET_ACCESS_KEY", "minioadmin"),
+		Region:          getEnvOrDefault("S3_REGION", "us-east-1"),
+		Bucket:          bucket,
+		UseSSL:          os.Getenv("S3_USE_SSL") == "true",
+	}
+}
+
+// setupMinIO テスト用のバケットを作成したクライアントを返す。MinIOに接続できない場合はスキップする
+func setupMinIO(t *testing.T) *storage.Client {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("短いテストモードでMinIO統合テストをスキップ")
+	}
+
+	config := minioConfig("memo-app-client-test")
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:         aws.String(config.Endpoint),
+		Region:           aws.String(config.Region),
+		Credentials:      credentials.NewStaticCredentials(config.AccessKeyID, config.SecretAccessKey, ""),
+		DisableSSL:       aws.Bool(!config.UseSSL),
+		S3ForcePathStyle: aws.Bool(true),
+		MaxRetries:       aws.Int(0),
+	})
+	require.NoError(t, err)
+	admin := s3.New(sess)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if _, err := admin.ListBucketsWithContext(ctx, &s3.ListBucketsInput{}); err != nil {
+		t.Skipf("MinIOに接続できないため、MinIO統合テストをスキップします: %v", err)
+	}
+	if _, err := admin.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(config.Bucket)}); err != nil {
+		_, err := admin.CreateBucketWithContext(ctx, &s3.CreateBucketInput{Bucket: aws.String(config.Bucket)})
+		require.NoError(t, err)
+	}
+
+	client, err := storage.NewClient(config)
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_WithBucket(t *testing.T) {
+	client, err := storage.NewClient(minioConfig("memo-app-logs"))
+	require.NoError(t, err)
+
+	attachments := client.WithBucket("memo-app-attachments")
+	assert.Equal(t, "memo-app-logs", client.Bucket())
+	assert.Equal(t, "memo-app-attachments", attachments.Bucket())
+
+	// 署名はローカルで計算するため接続先は不要
+	url, err := attachments.PresignGet("notes/1.txt", "", time.Minute)
+	require.NoError(t, err)
+	assert.Contains(t, url, "/memo-app-attachments/notes/1.txt?")
+	// ファイル名を指定しない場合はContent-Dispositionを付けない
+	assert.NotContains(t, url, "response-content-disposition")
+}
+
+func TestClient_MinIO(t *testing.T) {
+	client := setupMinIO(t)
+	ctx := context.Background()
+	key := "client-test/" + time.Now().Format("20060102150405.000000000") + ".txt"
+	content := []byte("メモのテスト\n")
+
+	t.Run("保存したオブジェクトを取得できる", func(t *testing.T) {
+		err := client.PutObject(ctx, key, bytes.NewReader(content), storage.PutOptions{
+			ContentType: "text/plain",
+			Metadata:    map[string]string{"source": "client-test"},
+		})
+		require.NoError(t, err)
+
+		body, err := client.GetObject(ctx, key)
+		require.NoError(t, err)
+		defer body.Close()
+		got, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+	})
+
+	t.Run("署名付きURLでダウンロードできる", func(t *testing.T) {
+		url, err := client.PresignGet(key, "memo.txt", time.Minute)
+		require.NoError(t, err)
+
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		got, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, content, got)
+		assert.Contains(t, resp.Header.Get("Content-Disposition"), "filename=memo.txt")
+	})
+
+	t.Run("バケットに接続できる", func(t *testing.T) {
+		assert.NoError(t, client.Health(ctx))
+		assert.Error(t, client.WithBucket("memo-app-missing-bucket").Health(ctx))
+	})
+
+	t.Run("削除したオブジェクトは取得できない", func(t *testing.T) {
+		require.NoError(t, client.DeleteObject(ctx, key))
+		_, err := client.GetObject(ctx, key)
+		assert.Error(t, err)
+	})
+}
+
+func TestLogUploader_MinIO(t *testing.T) {
+	client := setupMinIO(t)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	uploader := storage.NewLogUploaderWithClient(client, &storage.S3Config{Concurrency: 1}, logger)
+
+	dir := t.TempDir()
+	name := "app_" + time.Now().Format("2006-01-02_15-04-05.000000000") + ".log"
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte("level=info msg=test\n"), 0644))
+
+	require.NoError(t, uploader.UploadLogFile(path))
+
+	// ログは logs/ 以下に保存される
+	body, err := client.GetObject(context.Background(), "logs/"+name)
+	require.NoError(t, err)
+	defer body.Close()
+	got, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(got), "level=info"))
+	require.NoError(t, client.DeleteObject(context.Background(), "logs/"+name))
+}
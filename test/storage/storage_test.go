@@ -1,8 +1,12 @@
 package storage_test
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -207,6 +211,111 @@ func TestS3ConfigValidation(t *testing.T) {
 	})
 }
 
+// slowUploader は1ファイルごとに delay かかるFileUploaderで、同時実行数の最大値を記録する
+type slowUploader struct {
+	delay    time.Duration
+	inFlight int32
+	maxSeen  int32
+	mu       sync.Mutex
+	uploaded []string
+}
+
+func (u *slowUploader) UploadLogFileWithContext(ctx context.Context, filePath string) error {
+	n := atomic.AddInt32(&u.inFlight, 1)
+	defer atomic.AddInt32(&u.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&u.maxSeen)
+		if n <= max || atomic.CompareAndSwapInt32(&u.maxSeen, max, n) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(u.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	u.mu.Lock()
+	u.uploaded = append(u.uploaded, filePath)
+	u.mu.Unlock()
+	return nil
+}
+
+func TestUploadLogs_BoundedConcurrencyAndDeadline(t *testing.T) {
+	testLogger := logrus.New()
+	testLogger.SetLevel(logrus.ErrorLevel)
+
+	createLogs := func(t *testing.T, n int) string {
+		dir := t.TempDir()
+		for i := 0; i < n; i++ {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("app_%03d.log", i)), []byte("log"), 0644))
+		}
+		return dir
+	}
+
+	t.Run("すべてのファイルを同時実行数の上限内でアップロード", func(t *testing.T) {
+		dir := createLogs(t, 20)
+		uploader := &slowUploader{delay: 5 * time.Millisecond}
+
+		summary, err := storage.UploadLogs(context.Background(), uploader, dir, 0, 3, testLogger)
+
+		require.NoError(t, err)
+		assert.Equal(t, storage.UploadSummary{Uploaded: 20}, summary)
+		assert.LessOrEqual(t, atomic.LoadInt32(&uploader.maxSeen), int32(3))
+		remaining, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, remaining, "アップロードしたファイルは削除される")
+	})
+
+	t.Run("期限を過ぎたファイルはスキップして速やかに終了", func(t *testing.T) {
+		dir := createLogs(t, 100)
+		uploader := &slowUploader{delay: 20 * time.Millisecond}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		summary, err := storage.UploadLogs(ctx, uploader, dir, 0, 2, testLogger)
+		elapsed := time.Since(start)
+
+		require.NoError(t, err)
+		assert.Less(t, elapsed, 500*time.Millisecond)
+		assert.LessOrEqual(t, atomic.LoadInt32(&uploader.maxSeen), int32(2))
+		assert.Greater(t, summary.Skipped, 0)
+		assert.Zero(t, summary.Failed)
+		assert.Equal(t, 100, summary.Uploaded+summary.Skipped)
+		assert.Len(t, uploader.uploaded, summary.Uploaded)
+
+		// スキップしたファイルは次回のアップロードのために残す
+		remaining, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, remaining, summary.Skipped)
+	})
+
+	t.Run("シャットダウン時は通常より厳しい同時実行数を適用", func(t *testing.T) {
+		dir := createLogs(t, 5)
+		uploader, err := storage.NewLogUploader(&storage.S3Config{
+			Endpoint:    "http://127.0.0.1:1",
+			Region:      "us-east-1",
+			Bucket:      "test-bucket",
+			Concurrency: 8,
+		}, testLogger)
+		require.NoError(t, err)
+
+		// 接続できないエンドポイントでも期限内に終了し、ファイルは残る
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		summary, err := uploader.UploadOnShutdown(ctx, dir, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, summary.Uploaded+summary.Failed+summary.Skipped)
+		assert.Zero(t, summary.Uploaded)
+		remaining, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, remaining, 5)
+	})
+}
+
 func BenchmarkLogUploader(b *testing.B) {
 	testLogger := logrus.New()
 	testLogger.SetLevel(logrus.ErrorLevel)
@@ -230,3 +339,24 @@ func BenchmarkLogUploader(b *testing.B) {
 		}
 	})
 }
+
+func TestAttachmentStore_PresignGet(t *testing.T) {
+	client, err := storage.NewClient(&storage.S3Config{
+		Endpoint:        "http://localhost:9000",
+		AccessKeyID:     "test-access-key",
+		SecretAccessKey: "test-secret-key",
+		Region:          "us-east-1",
+		Bucket:          "memo-app-logs",
+	})
+	require.NoError(t, err)
+	store := storage.NewAttachmentStore(client.WithBucket("attachments-bucket"))
+
+	// 署名はローカルで計算するため接続先は不要
+	url, err := store.PresignGet("attachments/42/7/abc", "写真 1.png", 15*time.Minute)
+	require.NoError(t, err)
+	assert.Contains(t, url, "http://localhost:9000/attachments-bucket/attachments/42/7/abc?")
+	assert.Contains(t, url, "X-Amz-Expires=900")
+	assert.Contains(t, url, "X-Amz-Signature=")
+	// ファイル名付きでダウンロードさせる
+	assert.Contains(t, url, "response-content-disposition=attachment")
+}
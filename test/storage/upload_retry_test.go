@@ -0,0 +1,252 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"memo-app/src/storage"
+
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyUploader は各ファイルの最初の failures 回のアップロードを失敗させるFileUploader
+type flakyUploader struct {
+	failures int
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func (u *flakyUploader) UploadLogFileWithContext(ctx context.Context, filePath string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.attempts == nil {
+		u.attempts = make(map[string]int)
+	}
+	u.attempts[filePath]++
+	if u.attempts[filePath] <= u.failures {
+		return errors.New("connection reset by peer")
+	}
+	return nil
+}
+
+func createLogFiles(t *testing.T, n int) string {
+	t.Helper()
+	dir := t.TempDir()
+	for i := 0; i < n; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("app_%03d.log", i)), []byte("log"), 0644))
+	}
+	return dir
+}
+
+func TestUploadLogsWithRetry(t *testing.T) {
+	testLogger := logrus.New()
+	testLogger.SetLevel(logrus.ErrorLevel)
+
+	t.Run("一時的な失敗は再試行してアップロードする", func(t *testing.T) {
+		dir := createLogFiles(t, 3)
+		uploader := &flakyUploader{failures: 2}
+
+		summary, err := storage.UploadLogsWithRetry(context.Background(), uploader, dir, 0, 2,
+			storage.UploadRetry{MaxRetries: 2, Backoff: time.Millisecond}, testLogger)
+
+		require.NoError(t, err)
+		assert.Equal(t, storage.UploadSummary{Uploaded: 3}, summary)
+		for _, attempts := range uploader.attempts {
+			assert.Equal(t, 3, attempts)
+		}
+		remaining, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, remaining)
+	})
+
+	t.Run("再試行の上限を超えた場合は失敗としてファイルを残す", func(t *testing.T) {
+		dir := createLogFiles(t, 2)
+		uploader := &flakyUploader{failures: 10}
+
+		summary, err := storage.UploadLogsWithRetry(context.Background(), uploader, dir, 0, 2,
+			storage.UploadRetry{MaxRetries: 3, Backoff: time.Millisecond}, testLogger)
+
+		require.NoError(t, err)
+		assert.Equal(t, storage.UploadSummary{Failed: 2}, summary)
+		for _, attempts := range uploader.attempts {
+			assert.Equal(t, 4, attempts, "最初の1回と再試行3回")
+		}
+		remaining, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, remaining, 2)
+	})
+
+	t.Run("再試行を待つ間に期限を過ぎた場合は中断する", func(t *testing.T) {
+		dir := createLogFiles(t, 1)
+		uploader := &flakyUploader{failures: 10}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		summary, err := storage.UploadLogsWithRetry(ctx, uploader, dir, 0, 1,
+			storage.UploadRetry{MaxRetries: 5, Backoff: time.Minute}, testLogger)
+
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, 1, summary.Skipped)
+	})
+
+	t.Run("UploadLogsは再試行しない", func(t *testing.T) {
+		dir := createLogFiles(t, 1)
+		uploader := &flakyUploader{failures: 1}
+
+		summary, err := storage.UploadLogs(context.Background(), uploader, dir, 0, 1, testLogger)
+
+		require.NoError(t, err)
+		assert.Equal(t, storage.UploadSummary{Failed: 1}, summary)
+	})
+}
+
+func TestLogUploader_FailureAlert(t *testing.T) {
+	logger, hook := logtest.NewNullLogger()
+	// 接続できないエンドポイントに対して、すべてのアップロードが失敗する
+	uploader, err := storage.NewLogUploader(&storage.S3Config{
+		Endpoint:       "http://127.0.0.1:1",
+		Region:         "us-east-1",
+		Bucket:         "test-bucket",
+		AlertThreshold: 2,
+	}, logger)
+	require.NoError(t, err)
+
+	var alerts []storage.UploadFailureAlert
+	uploader.SetFailureAlertHook(func(ctx context.Context, alert storage.UploadFailureAlert) error {
+		alerts = append(alerts, alert)
+		return nil
+	})
+
+	alertEntries := func() int {
+		n := 0
+		for _, entry := range hook.AllEntries() {
+			if entry.Level == logrus.ErrorLevel && entry.Data["alert"] == storage.UploadAlertEvent {
+				n++
+			}
+		}
+		return n
+	}
+
+	dir := createLogFiles(t, 1)
+
+	// 閾値未満ではアラートを出さない
+	require.NoError(t, uploader.UploadOldLogs(dir, 0))
+	assert.Equal(t, 1, uploader.ConsecutiveFailures())
+	assert.Zero(t, alertEntries())
+	assert.Empty(t, alerts)
+
+	// 閾値に達するとアラートのログと通知を出す
+	require.NoError(t, uploader.UploadOldLogs(dir, 0))
+	assert.Equal(t, 2, uploader.ConsecutiveFailures())
+	assert.Equal(t, 1, alertEntries())
+	require.Len(t, alerts, 1)
+	assert.Equal(t, 2, alerts[0].ConsecutiveFailures)
+	assert.Equal(t, 1, alerts[0].Failed)
+	assert.Equal(t, "test-bucket", alerts[0].Bucket)
+
+	// ディレクトリの読み取りの失敗も連続失敗として数える
+	assert.Error(t, uploader.UploadOldLogs(filepath.Join(dir, "missing"), 0))
+	assert.Equal(t, 3, uploader.ConsecutiveFailures())
+	require.Len(t, alerts, 2)
+	assert.NotEmpty(t, alerts[1].Error)
+
+	// 失敗がなければ連続失敗の回数をリセットする
+	require.NoError(t, uploader.UploadOldLogs(t.TempDir(), 0))
+	assert.Zero(t, uploader.ConsecutiveFailures())
+}
+
+func TestNewUploadAlertWebhook(t *testing.T) {
+	t.Run("アラートをJSONでPOSTする", func(t *testing.T) {
+		var received map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		}))
+		defer server.Close()
+
+		notify := storage.NewUploadAlertWebhook(server.URL, nil)
+		err := notify(context.Background(), storage.UploadFailureAlert{
+			ConsecutiveFailures: 3,
+			Failed:              2,
+			Bucket:              "memo-app-logs",
+			OccurredAt:          time.Now(),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, storage.UploadAlertEvent, received["event"])
+		assert.Equal(t, float64(3), received["consecutive_failures"])
+		assert.Equal(t, float64(2), received["failed"])
+		assert.Equal(t, "memo-app-logs", received["bucket"])
+		assert.NotContains(t, received, "error")
+	})
+
+	t.Run("2xx以外の応答はエラー", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		err := storage.NewUploadAlertWebhook(server.URL, nil)(context.Background(), storage.UploadFailureAlert{})
+		assert.Error(t, err)
+	})
+}
+
+// keepingUploader は keep に含まれるファイルをアップロード後もローカルに残すFileUploader
+type keepingUploader struct {
+	flakyUploader
+	keep string
+}
+
+func (u *keepingUploader) KeepLocal(filePath string) bool {
+	return filePath == u.keep
+}
+
+func TestUploadLogs_KeepsActiveFile(t *testing.T) {
+	testLogger := logrus.New()
+	testLogger.SetLevel(logrus.ErrorLevel)
+	dir := createLogFiles(t, 3)
+	active := filepath.Join(dir, "app_002.log")
+	uploader := &keepingUploader{keep: active}
+
+	summary, err := storage.UploadLogs(context.Background(), uploader, dir, 0, 2, testLogger)
+
+	require.NoError(t, err)
+	assert.Equal(t, storage.UploadSummary{Uploaded: 3, Kept: 1}, summary)
+	assert.Equal(t, 1, uploader.attempts[active], "書き込み中のファイルもアップロードする")
+	remaining, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "app_002.log", remaining[0].Name())
+}
+
+func TestLogUploader_KeepLocal(t *testing.T) {
+	uploader, err := storage.NewLogUploader(&storage.S3Config{Region: "us-east-1", Bucket: "test-bucket"}, logrus.New())
+	require.NoError(t, err)
+
+	// 設定されていない場合はすべて削除対象
+	assert.False(t, uploader.KeepLocal("logs/app.log"))
+
+	uploader.SetActiveFile(func() string { return "logs/app_current.log" })
+	assert.True(t, uploader.KeepLocal("logs/app_current.log"))
+	assert.True(t, uploader.KeepLocal("./logs/../logs/app_current.log"))
+	assert.False(t, uploader.KeepLocal("logs/app_old.log"))
+
+	// ロガーの初期化前などでパスが空の場合
+	uploader.SetActiveFile(func() string { return "" })
+	assert.False(t, uploader.KeepLocal("logs/app_current.log"))
+}
@@ -0,0 +1,225 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"memo-app/src/config"
+	"memo-app/src/domain"
+	"memo-app/src/usecase"
+
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader is the signature of a PNG file, enough for content type detection
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// fakeAttachmentRepository はメモリ上に添付ファイルを記録する
+type fakeAttachmentRepository struct {
+	created   []domain.MemoAttachment
+	createErr error
+}
+
+func (r *fakeAttachmentRepository) Create(ctx context.Context, attachment *domain.MemoAttachment) (*domain.MemoAttachment, error) {
+	if r.createErr != nil {
+		return nil, r.createErr
+	}
+	created := *attachment
+	created.ID = len(r.created) + 1
+	r.created = append(r.created, created)
+	return &created, nil
+}
+
+func (r *fakeAttachmentRepository) GetByID(ctx context.Context, memoID, id int) (*domain.MemoAttachment, error) {
+	for _, attachment := range r.created {
+		if attachment.MemoID == memoID && attachment.ID == id {
+			return &attachment, nil
+		}
+	}
+	return nil, errors.New("attachment not found")
+}
+
+// fakeAttachmentStorage は保存されたファイルをキーごとに保持する
+type fakeAttachmentStorage struct {
+	objects map[string][]byte
+	types   map[string]string
+	deleted []string
+}
+
+func newFakeAttachmentStorage() *fakeAttachmentStorage {
+	return &fakeAttachmentStorage{objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (s *fakeAttachmentStorage) Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.objects[key] = data
+	s.types[key] = contentType
+	return nil
+}
+
+func (s *fakeAttachmentStorage) Delete(ctx context.Context, key string) error {
+	delete(s.objects, key)
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func (s *fakeAttachmentStorage) PresignGet(key, filename string, expiry time.Duration) (string, error) {
+	return "https://s3.example.com/" + key + "?filename=" + filename + "&expires=" + expiry.String(), nil
+}
+
+func newAttachmentUsecase(memoRepo domain.MemoRepository) (usecase.AttachmentUsecase, *fakeAttachmentRepository, *fakeAttachmentStorage) {
+	logger, _ := logtest.NewNullLogger()
+	cfg := config.DefaultAttachmentConfig()
+	cfg.MaxSize = 64
+	attachmentRepo := &fakeAttachmentRepository{}
+	storage := newFakeAttachmentStorage()
+	return usecase.NewAttachmentUsecase(memoRepo, attachmentRepo, storage, cfg, logger), attachmentRepo, storage
+}
+
+func TestAttachmentUsecase_UploadAttachment(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	upload := func(filename string, data []byte) usecase.UploadAttachmentRequest {
+		return usecase.UploadAttachmentRequest{Filename: filename, Size: int64(len(data)), File: bytes.NewReader(data)}
+	}
+
+	t.Run("内容から判定したContent-Typeで保存して記録する", func(t *testing.T) {
+		memoRepo := new(MockMemoRepository)
+		memoRepo.On("GetByID", mock.Anything, 7).Return(&domain.Memo{ID: 7}, nil)
+		uc, attachmentRepo, storage := newAttachmentUsecase(memoRepo)
+
+		attachment, err := uc.UploadAttachment(ctx, 7, upload(`C:\Users\me\写真.png`, pngHeader))
+		require.NoError(t, err)
+		assert.Equal(t, "写真.png", attachment.Filename)
+		assert.Equal(t, "image/png", attachment.ContentType)
+		assert.Equal(t, int64(len(pngHeader)), attachment.Size)
+		assert.Regexp(t, `^attachments/42/7/[0-9a-f]{32}$`, attachment.ObjectKey)
+
+		require.Len(t, attachmentRepo.created, 1)
+		// 判定のために読んだ先頭部分も含めてファイル全体を保存する
+		assert.Equal(t, pngHeader, storage.objects[attachment.ObjectKey])
+		assert.Equal(t, "image/png", storage.types[attachment.ObjectKey])
+	})
+
+	t.Run("許可されていない種類のファイルは拒否する", func(t *testing.T) {
+		memoRepo := new(MockMemoRepository)
+		uc, _, storage := newAttachmentUsecase(memoRepo)
+
+		// 拡張子を偽装しても内容で判定する
+		_, err := uc.UploadAttachment(ctx, 7, upload("image.png", []byte("<html><script>alert(1)</script></html>")))
+		assert.Equal(t, usecase.ErrAttachmentTypeNotAllowed, err)
+		assert.Empty(t, storage.objects)
+	})
+
+	t.Run("最大サイズを超えるファイルは拒否する", func(t *testing.T) {
+		memoRepo := new(MockMemoRepository)
+		uc, _, storage := newAttachmentUsecase(memoRepo)
+
+		_, err := uc.UploadAttachment(ctx, 7, upload("big.png", append(pngHeader, make([]byte, 64)...)))
+		assert.Equal(t, usecase.ErrAttachmentTooLarge, err)
+		assert.Empty(t, storage.objects)
+	})
+
+	t.Run("空のファイルとファイル名のないファイルは拒否する", func(t *testing.T) {
+		memoRepo := new(MockMemoRepository)
+		uc, _, _ := newAttachmentUsecase(memoRepo)
+
+		_, err := uc.UploadAttachment(ctx, 7, upload("empty.png", nil))
+		assert.Equal(t, usecase.ErrInvalidAttachment, err)
+		_, err = uc.UploadAttachment(ctx, 7, upload("../", pngHeader))
+		assert.Equal(t, usecase.ErrInvalidAttachment, err)
+	})
+
+	t.Run("他のユーザーのメモには保存しない", func(t *testing.T) {
+		memoRepo := new(MockMemoRepository)
+		memoRepo.On("GetByID", mock.Anything, 7).Return(nil, errors.New("memo not found"))
+		uc, _, storage := newAttachmentUsecase(memoRepo)
+
+		_, err := uc.UploadAttachment(ctx, 7, upload("photo.png", pngHeader))
+		assert.Equal(t, usecase.ErrMemoNotFound, err)
+		assert.Empty(t, storage.objects)
+	})
+
+	t.Run("記録に失敗した場合は保存したファイルを削除する", func(t *testing.T) {
+		memoRepo := new(MockMemoRepository)
+		memoRepo.On("GetByID", mock.Anything, 7).Return(&domain.Memo{ID: 7}, nil)
+		uc, attachmentRepo, storage := newAttachmentUsecase(memoRepo)
+		attachmentRepo.createErr = errors.New("connection reset")
+
+		_, err := uc.UploadAttachment(ctx, 7, upload("photo.png", pngHeader))
+		assert.Error(t, err)
+		assert.Len(t, storage.deleted, 1)
+		assert.Empty(t, storage.objects)
+	})
+
+	t.Run("認証が必要", func(t *testing.T) {
+		uc, _, _ := newAttachmentUsecase(new(MockMemoRepository))
+
+		_, err := uc.UploadAttachment(context.Background(), 7, upload("photo.png", pngHeader))
+		assert.Equal(t, usecase.ErrAttachmentUnauthorized, err)
+	})
+}
+
+func TestAttachmentUsecase_ListAttachments(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+
+	t.Run("自分のメモの添付ファイルを返す", func(t *testing.T) {
+		memoRepo := new(MockMemoRepository)
+		memoRepo.On("GetByID", mock.Anything, 7).Return(&domain.Memo{ID: 7}, nil)
+		memoRepo.On("ListAttachments", mock.Anything, 7).Return([]domain.MemoAttachment{{ID: 1, MemoID: 7}}, nil)
+		uc, _, _ := newAttachmentUsecase(memoRepo)
+
+		attachments, err := uc.ListAttachments(ctx, 7)
+		require.NoError(t, err)
+		assert.Len(t, attachments, 1)
+	})
+
+	t.Run("他のユーザーのメモは見つからない", func(t *testing.T) {
+		memoRepo := new(MockMemoRepository)
+		memoRepo.On("GetByID", mock.Anything, 7).Return(nil, errors.New("memo not found"))
+		uc, _, _ := newAttachmentUsecase(memoRepo)
+
+		_, err := uc.ListAttachments(ctx, 7)
+		assert.Equal(t, usecase.ErrMemoNotFound, err)
+		memoRepo.AssertNotCalled(t, "ListAttachments", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAttachmentUsecase_GetAttachmentDownload(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	memoRepo := new(MockMemoRepository)
+	memoRepo.On("GetByID", mock.Anything, 7).Return(&domain.Memo{ID: 7}, nil)
+	uc, _, _ := newAttachmentUsecase(memoRepo)
+
+	uploaded, err := uc.UploadAttachment(ctx, 7, usecase.UploadAttachmentRequest{Filename: "photo.png", Size: int64(len(pngHeader)), File: bytes.NewReader(pngHeader)})
+	require.NoError(t, err)
+
+	t.Run("有効期限付きの署名付きURLを返す", func(t *testing.T) {
+		before := time.Now()
+		attachment, download, err := uc.GetAttachmentDownload(ctx, 7, uploaded.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uploaded.ID, attachment.ID)
+		assert.Contains(t, download.URL, uploaded.ObjectKey)
+		assert.Contains(t, download.URL, "filename=photo.png")
+		assert.WithinDuration(t, before.Add(config.DefaultAttachmentConfig().URLExpiry), download.ExpiresAt, time.Second)
+	})
+
+	t.Run("他のメモの添付ファイルは見つからない", func(t *testing.T) {
+		_, _, err := uc.GetAttachmentDownload(ctx, 8, uploaded.ID)
+		assert.Equal(t, usecase.ErrAttachmentNotFound, err)
+	})
+
+	t.Run("認証が必要", func(t *testing.T) {
+		_, _, err := uc.GetAttachmentDownload(context.Background(), 7, uploaded.ID)
+		assert.Equal(t, usecase.ErrAttachmentUnauthorized, err)
+	})
+}
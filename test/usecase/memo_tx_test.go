@@ -0,0 +1,149 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+
+	"memo-app/src/config"
+	"memo-app/src/domain"
+	"memo-app/src/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// txMemoRepository はトランザクションを持つインメモリのリポジトリ
+// WithinTx は1件ずつ直列に実行し（行ロックの代わり）、fn がエラーを返した場合は変更を破棄する
+type txMemoRepository struct {
+	domain.MemoRepository // 使用しないメソッドは未実装
+
+	lock  sync.Mutex // トランザクション全体で保持する
+	mu    sync.Mutex // memos を保護する
+	memos map[int]domain.Memo
+}
+
+func newTxMemoRepository(memos ...domain.Memo) *txMemoRepository {
+	r := &txMemoRepository{memos: make(map[int]domain.Memo)}
+	for _, memo := range memos {
+		r.memos[memo.ID] = memo
+	}
+	return r
+}
+
+func (r *txMemoRepository) WithinTx(ctx context.Context, fn func(tx domain.MemoTx) error) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.mu.Lock()
+	snapshot := make(map[int]domain.Memo, len(r.memos))
+	for id, memo := range r.memos {
+		snapshot[id] = memo
+	}
+	r.mu.Unlock()
+
+	err := fn(r)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.memos = snapshot
+	}
+	return err
+}
+
+func (r *txMemoRepository) GetByID(ctx context.Context, id int) (*domain.Memo, error) {
+	// 他のゴルーチンに切り替わる機会を作り、競合が起きやすくする
+	runtime.Gosched()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	memo, ok := r.memos[id]
+	if !ok {
+		return nil, errors.New("memo not found")
+	}
+	return &memo, nil
+}
+
+func (r *txMemoRepository) GetByIDForUpdate(ctx context.Context, id int) (*domain.Memo, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *txMemoRepository) List(ctx context.Context, filter domain.MemoFilter) ([]domain.Memo, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var memos []domain.Memo
+	for _, memo := range r.memos {
+		if memo.Category == filter.Category && memo.Status == filter.Status {
+			memos = append(memos, memo)
+		}
+	}
+	return memos, len(memos), nil
+}
+
+// Delete は実際のリポジトリと同じく1段階ずつ進め（active → archived → trashed）、行は削除しない
+func (r *txMemoRepository) Delete(ctx context.Context, id int) error {
+	runtime.Gosched()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	memo, ok := r.memos[id]
+	if !ok {
+		return errors.New("memo not found")
+	}
+	switch memo.Status {
+	case domain.StatusActive:
+		memo.Status = domain.StatusArchived
+	case domain.StatusArchived:
+		memo.Status = domain.StatusTrashed
+	default:
+		return errors.New("memo is already in trash")
+	}
+	r.memos[id] = memo
+	return nil
+}
+
+func TestMemoUsecase_DeleteMemo_Concurrent(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	repo := newTxMemoRepository(domain.Memo{ID: 1, Title: "Title", Content: "Content", Status: domain.StatusActive})
+	publisher := &recordingPublisher{}
+	uc := usecase.NewMemoUsecaseWithPublisher(repo, nil, publisher)
+
+	const workers = 50
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = uc.DeleteMemo(ctx, 1)
+		}(i)
+	}
+	wg.Wait()
+
+	// 同じメモへの同時削除はアーカイブとゴミ箱への移動の2件だけが成功し、残りはゴミ箱にあるため拒否される
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.Equal(t, usecase.ErrMemoAlreadyTrashed, err)
+	}
+	assert.Equal(t, 2, succeeded)
+	assert.Equal(t, []string{domain.MemoEventUpdated, domain.MemoEventUpdated}, publisher.events)
+	// 行は削除されず、ゴミ箱に残る
+	require.Contains(t, repo.memos, 1)
+	assert.Equal(t, domain.StatusTrashed, repo.memos[1].Status)
+}
+
+func TestMemoUsecase_DeleteMemo_GuardRollsBack(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	repo := newTxMemoRepository(domain.Memo{ID: 1, Title: "Inbox", Content: "Content", Category: "inbox", Status: domain.StatusActive})
+	cfg := config.DefaultMemoConfig()
+	cfg.ProtectedCategories = []string{"inbox"}
+
+	err := usecase.NewMemoUsecaseWithConfig(repo, cfg).DeleteMemo(ctx, 1)
+	require.Equal(t, usecase.ErrLastActiveInCategory, err)
+	assert.Equal(t, domain.StatusActive, repo.memos[1].Status)
+}
@@ -2,9 +2,12 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"memo-app/src/config"
 	"memo-app/src/domain"
 	"memo-app/src/usecase"
 
@@ -33,6 +36,11 @@ func (m *MockMemoRepository) GetByID(ctx context.Context, id int) (*domain.Memo,
 	return args.Get(0).(*domain.Memo), args.Error(1)
 }
 
+func (m *MockMemoRepository) Exists(ctx context.Context, id int) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockMemoRepository) List(ctx context.Context, filter domain.MemoFilter) ([]domain.Memo, int, error) {
 	args := m.Called(ctx, filter)
 	return args.Get(0).([]domain.Memo), args.Get(1).(int), args.Error(2)
@@ -51,6 +59,138 @@ func (m *MockMemoRepository) Delete(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
+func (m *MockMemoRepository) DeleteMany(ctx context.Context, ids []int) ([]int, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockMemoRepository) Trash(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoRepository) PermanentDelete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMemoRepository) PurgeTrashedOlderThan(ctx context.Context, age time.Duration) (int64, error) {
+	args := m.Called(ctx, age)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMemoRepository) ListDeleted(ctx context.Context, filter domain.MemoFilter) ([]domain.DeletedMemo, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]domain.DeletedMemo), args.Int(1), args.Error(2)
+}
+
+func (m *MockMemoRepository) RestoreDeleted(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoRepository) PurgeDeletedOlderThan(ctx context.Context, age time.Duration) (int64, error) {
+	args := m.Called(ctx, age)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMemoRepository) ListCategories(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMemoRepository) ForEach(ctx context.Context, fn func(*domain.Memo) error) error {
+	args := m.Called(ctx, fn)
+	return args.Error(0)
+}
+
+func (m *MockMemoRepository) UpdateMetadata(ctx context.Context, id int, update domain.MemoMetadataUpdate) (*domain.Memo, error) {
+	args := m.Called(ctx, id, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoRepository) Stats(ctx context.Context) (*domain.MemoStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoStats), args.Error(1)
+}
+
+func (m *MockMemoRepository) MaxUpdatedAt(ctx context.Context) (*domain.MemoListState, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoListState), args.Error(1)
+}
+
+func (m *MockMemoRepository) GetRevision(ctx context.Context, memoID, revisionID int) (*domain.MemoRevision, error) {
+	args := m.Called(ctx, memoID, revisionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MemoRevision), args.Error(1)
+}
+
+func (m *MockMemoRepository) CreateMany(ctx context.Context, memos []domain.Memo) ([]domain.Memo, error) {
+	args := m.Called(ctx, memos)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoRepository) CreateWithIDs(ctx context.Context, memos []domain.Memo) ([]domain.Memo, error) {
+	args := m.Called(ctx, memos)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoRepository) ListOnThisDay(ctx context.Context, date time.Time) ([]domain.Memo, error) {
+	args := m.Called(ctx, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoRepository) ListSharedWithUser(ctx context.Context, filter domain.MemoFilter) ([]domain.SharedMemo, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]domain.SharedMemo), args.Int(1), args.Error(2)
+}
+
+func (m *MockMemoRepository) BulkUpdate(ctx context.Context, ids []int, update domain.MemoBulkUpdate) ([]int, error) {
+	args := m.Called(ctx, ids, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
 func (m *MockMemoRepository) Archive(ctx context.Context, id int) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -66,6 +206,72 @@ func (m *MockMemoRepository) Search(ctx context.Context, query string, filter do
 	return args.Get(0).([]domain.Memo), args.Get(1).(int), args.Error(2)
 }
 
+func (m *MockMemoRepository) Promote(ctx context.Context, id int, priority domain.Priority) (*domain.Memo, error) {
+	args := m.Called(ctx, id, priority)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoRepository) Touch(ctx context.Context, id int) (*domain.Memo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoRepository) SetPinned(ctx context.Context, id int, pinned bool) (*domain.Memo, error) {
+	args := m.Called(ctx, id, pinned)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Memo), args.Error(1)
+}
+
+func (m *MockMemoRepository) ListRevisions(ctx context.Context, memoID int) ([]domain.MemoRevision, error) {
+	args := m.Called(ctx, memoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MemoRevision), args.Error(1)
+}
+
+func (m *MockMemoRepository) ListAttachments(ctx context.Context, memoID int) ([]domain.MemoAttachment, error) {
+	args := m.Called(ctx, memoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MemoAttachment), args.Error(1)
+}
+
+func (m *MockMemoRepository) RecordSearchQuery(ctx context.Context, query string, limit int) error {
+	args := m.Called(ctx, query, limit)
+	return args.Error(0)
+}
+
+func (m *MockMemoRepository) ListSearchQueries(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMemoRepository) ClearSearchQueries(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockMemoRepository) ListTags(ctx context.Context, filter domain.TagFilter) ([]domain.TagCount, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TagCount), args.Error(1)
+}
+
 func TestMemoUsecase_CreateMemo(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -219,6 +425,94 @@ func TestMemoUsecase_GetMemo(t *testing.T) {
 	}
 }
 
+func TestMemoUsecase_RevealMemoOwnership(t *testing.T) {
+	notFound := errors.New("memo not found")
+	title := "Updated"
+	color := "#112233"
+
+	operations := map[string]func(uc usecase.MemoUsecase, id int) error{
+		"get": func(uc usecase.MemoUsecase, id int) error {
+			_, err := uc.GetMemo(context.Background(), id)
+			return err
+		},
+		"update": func(uc usecase.MemoUsecase, id int) error {
+			_, err := uc.UpdateMemo(context.Background(), id, usecase.UpdateMemoRequest{Title: &title})
+			return err
+		},
+		"delete": func(uc usecase.MemoUsecase, id int) error {
+			return uc.DeleteMemo(context.Background(), id)
+		},
+		"trash": func(uc usecase.MemoUsecase, id int) error {
+			_, err := uc.TrashMemo(context.Background(), id)
+			return err
+		},
+		"update metadata": func(uc usecase.MemoUsecase, id int) error {
+			_, err := uc.UpdateMemoMetadata(context.Background(), id, usecase.UpdateMemoMetadataRequest{Color: &color})
+			return err
+		},
+		"archive": func(uc usecase.MemoUsecase, id int) error {
+			return uc.ArchiveMemo(context.Background(), id)
+		},
+		"restore": func(uc usecase.MemoUsecase, id int) error {
+			return uc.RestoreMemo(context.Background(), id)
+		},
+		"permanent delete": func(uc usecase.MemoUsecase, id int) error {
+			return uc.PermanentDeleteMemo(context.Background(), id)
+		},
+		"promote": func(uc usecase.MemoUsecase, id int) error {
+			_, err := uc.PromoteMemo(context.Background(), id)
+			return err
+		},
+		"touch": func(uc usecase.MemoUsecase, id int) error {
+			_, err := uc.TouchMemo(context.Background(), id)
+			return err
+		},
+		"pin": func(uc usecase.MemoUsecase, id int) error {
+			_, err := uc.PinMemo(context.Background(), id)
+			return err
+		},
+	}
+
+	// 単一のメモを対象とする操作は、いずれもリポジトリの "memo not found" を返す
+	expectNotFound := func(mockRepo *MockMemoRepository, id interface{}) {
+		mockRepo.On("GetByID", mock.Anything, id).Return(nil, notFound).Maybe()
+		mockRepo.On("Delete", mock.Anything, id).Return(notFound).Maybe()
+		mockRepo.On("Trash", mock.Anything, id).Return(nil, notFound).Maybe()
+		mockRepo.On("UpdateMetadata", mock.Anything, id, mock.Anything).Return(nil, notFound).Maybe()
+		mockRepo.On("Archive", mock.Anything, id).Return(notFound).Maybe()
+		mockRepo.On("Restore", mock.Anything, id).Return(notFound).Maybe()
+		mockRepo.On("PermanentDelete", mock.Anything, id).Return(notFound).Maybe()
+		mockRepo.On("Promote", mock.Anything, id, mock.Anything).Return(nil, notFound).Maybe()
+		mockRepo.On("Touch", mock.Anything, id).Return(nil, notFound).Maybe()
+		mockRepo.On("SetPinned", mock.Anything, id, mock.Anything).Return(nil, notFound).Maybe()
+	}
+
+	for name, operation := range operations {
+		t.Run(name+" hides other users' memos by default", func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			expectNotFound(mockRepo, 5)
+
+			uc := usecase.NewMemoUsecase(mockRepo)
+			assert.Equal(t, usecase.ErrMemoNotFound, operation(uc, 5))
+			mockRepo.AssertNotCalled(t, "Exists", mock.Anything, mock.Anything)
+		})
+
+		t.Run(name+" distinguishes other users' memos when enabled", func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			expectNotFound(mockRepo, mock.Anything)
+			mockRepo.On("Exists", mock.Anything, 5).Return(true, nil)
+			mockRepo.On("Exists", mock.Anything, 999).Return(false, nil)
+
+			cfg := config.DefaultMemoConfig()
+			cfg.RevealMemoOwnership = true
+			uc := usecase.NewMemoUsecaseWithConfig(mockRepo, cfg)
+
+			assert.Equal(t, usecase.ErrMemoForbidden, operation(uc, 5))
+			assert.Equal(t, usecase.ErrMemoNotFound, operation(uc, 999))
+		})
+	}
+}
+
 func TestMemoUsecase_ListMemos(t *testing.T) {
 	mockRepo := new(MockMemoRepository)
 
@@ -344,3 +638,1094 @@ func TestMemoUsecase_RestoreMemo(t *testing.T) {
 		})
 	}
 }
+
+func TestMemoUsecase_PromoteMemo(t *testing.T) {
+	tests := []struct {
+		name             string
+		memoID           int
+		config           *config.MemoConfig
+		expectedPriority domain.Priority
+		repoErr          error
+		expectedError    error
+	}{
+		{
+			name:             "default config promotes to high",
+			memoID:           1,
+			config:           nil,
+			expectedPriority: domain.PriorityHigh,
+		},
+		{
+			name:             "configured target priority",
+			memoID:           1,
+			config:           &config.MemoConfig{PromotePriority: "medium"},
+			expectedPriority: domain.PriorityMedium,
+		},
+		{
+			name:             "invalid configured priority falls back to high",
+			memoID:           1,
+			config:           &config.MemoConfig{PromotePriority: "urgent"},
+			expectedPriority: domain.PriorityHigh,
+		},
+		{
+			name:             "memo not found",
+			memoID:           999,
+			config:           nil,
+			expectedPriority: domain.PriorityHigh,
+			repoErr:          errors.New("memo not found"),
+			expectedError:    usecase.ErrMemoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			if tt.repoErr != nil {
+				mockRepo.On("Promote", mock.Anything, tt.memoID, tt.expectedPriority).Return(nil, tt.repoErr)
+			} else {
+				mockRepo.On("Promote", mock.Anything, tt.memoID, tt.expectedPriority).Return(&domain.Memo{
+					ID:       tt.memoID,
+					Title:    "Test Memo",
+					Priority: tt.expectedPriority,
+					Status:   domain.StatusActive,
+					Pinned:   true,
+				}, nil)
+			}
+
+			uc := usecase.NewMemoUsecaseWithConfig(mockRepo, tt.config)
+
+			memo, err := uc.PromoteMemo(context.Background(), tt.memoID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, memo)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedPriority, memo.Priority)
+				assert.True(t, memo.Pinned)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoUsecase_CaseInsensitiveCategory(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           *config.MemoConfig
+		expectedCategory string
+	}{
+		{
+			name:             "disabled keeps category as is",
+			config:           nil,
+			expectedCategory: "Work",
+		},
+		{
+			name:             "enabled normalizes category to lowercase",
+			config:           &config.MemoConfig{CaseInsensitiveCategory: true},
+			expectedCategory: "work",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" on create", func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(m *domain.Memo) bool {
+				return m.Category == tt.expectedCategory
+			})).Return(&domain.Memo{ID: 1, Title: "Test Memo", Category: tt.expectedCategory}, nil)
+
+			uc := usecase.NewMemoUsecaseWithConfig(mockRepo, tt.config)
+
+			_, err := uc.CreateMemo(context.Background(), usecase.CreateMemoRequest{
+				Title:    "Test Memo",
+				Content:  "Content",
+				Category: "Work",
+			})
+
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+
+		t.Run(tt.name+" on update", func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{
+				ID:       1,
+				Title:    "Test Memo",
+				Content:  "Content",
+				Priority: domain.PriorityMedium,
+				Status:   domain.StatusActive,
+			}, nil)
+			mockRepo.On("Update", mock.Anything, 1, mock.MatchedBy(func(m *domain.Memo) bool {
+				return m.Category == tt.expectedCategory
+			})).Return(&domain.Memo{ID: 1, Title: "Test Memo", Category: tt.expectedCategory}, nil)
+
+			uc := usecase.NewMemoUsecaseWithConfig(mockRepo, tt.config)
+
+			category := "Work"
+			_, err := uc.UpdateMemo(context.Background(), 1, usecase.UpdateMemoRequest{Category: &category})
+
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoUsecase_NormalizeCategoryAndTags(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           *config.MemoConfig
+		expectedCategory string
+		expectedTags     []string
+	}{
+		{
+			name:             "whitespace is always normalized",
+			config:           nil,
+			expectedCategory: "Side Project",
+			expectedTags:     []string{"Go Lang", "go lang", "GO LANG"},
+		},
+		{
+			name:             "case is normalized when enabled",
+			config:           &config.MemoConfig{CaseInsensitiveCategory: true, CaseInsensitiveTags: true},
+			expectedCategory: "side project",
+			expectedTags:     []string{"go lang"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(m *domain.Memo) bool {
+				return m.Category == tt.expectedCategory && assert.ObjectsAreEqual(tt.expectedTags, m.Tags)
+			})).Return(&domain.Memo{ID: 1}, nil)
+
+			uc := usecase.NewMemoUsecaseWithConfig(mockRepo, tt.config)
+			_, err := uc.CreateMemo(context.Background(), usecase.CreateMemoRequest{
+				Title:    "Test Memo",
+				Content:  "Content",
+				Category: "  Side   Project ",
+				Tags:     []string{"Go Lang", " go  lang", "GO\tLANG ", "go lang"},
+			})
+
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("mixed-case and Japanese variants collapse on create and update", func(t *testing.T) {
+		tags := []string{"Golang", "golang", "ＧＯＬＡＮＧ", "ﾒﾓ", "メモ", "日本語　タグ", "日本語 タグ"}
+		cases := []struct {
+			config   *config.MemoConfig
+			expected []string
+		}{
+			{config: &config.MemoConfig{}, expected: []string{"Golang", "golang", "GOLANG", "メモ", "日本語 タグ"}},
+			{config: &config.MemoConfig{CaseInsensitiveTags: true}, expected: []string{"golang", "メモ", "日本語 タグ"}},
+		}
+
+		for _, tc := range cases {
+			mockRepo := new(MockMemoRepository)
+			matchesTags := mock.MatchedBy(func(m *domain.Memo) bool {
+				return assert.ObjectsAreEqual(tc.expected, m.Tags)
+			})
+			mockRepo.On("Create", mock.Anything, matchesTags).Return(&domain.Memo{ID: 1}, nil)
+			mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{ID: 1, Title: "Test Memo", Content: "Content", Status: domain.StatusActive}, nil)
+			mockRepo.On("Update", mock.Anything, 1, matchesTags).Return(&domain.Memo{ID: 1}, nil)
+
+			uc := usecase.NewMemoUsecaseWithConfig(mockRepo, tc.config)
+			_, err := uc.CreateMemo(context.Background(), usecase.CreateMemoRequest{Title: "Test Memo", Content: "Content", Tags: tags})
+			assert.NoError(t, err)
+			_, err = uc.UpdateMemo(context.Background(), 1, usecase.UpdateMemoRequest{Tags: tags})
+			assert.NoError(t, err)
+
+			mockRepo.AssertExpectations(t)
+		}
+	})
+
+	t.Run("list filters use the normalized form", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("List", mock.Anything, domain.MemoFilter{
+			Category: "work",
+			Tags:     []string{"go lang"},
+			Page:     1,
+			Limit:    10,
+		}).Return([]domain.Memo{}, 0, nil)
+
+		uc := usecase.NewMemoUsecaseWithConfig(mockRepo, &config.MemoConfig{CaseInsensitiveCategory: true, CaseInsensitiveTags: true})
+		_, _, err := uc.ListMemos(context.Background(), domain.MemoFilter{Category: " Work ", Tags: []string{"Go  Lang", "go lang"}})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestMemoUsecase_GetMemoDetail(t *testing.T) {
+	memo := &domain.Memo{ID: 1, Title: "Test Memo", Status: domain.StatusActive}
+	revisions := []domain.MemoRevision{{ID: 10, MemoID: 1, Title: "Old Title"}}
+	attachments := []domain.MemoAttachment{{ID: 20, MemoID: 1, Filename: "image.png"}}
+
+	tests := []struct {
+		name   string
+		expand domain.MemoExpand
+	}{
+		{name: "no expand", expand: domain.MemoExpand{}},
+		{name: "expand revisions", expand: domain.MemoExpand{Revisions: true}},
+		{name: "expand attachments", expand: domain.MemoExpand{Attachments: true}},
+		{name: "expand both", expand: domain.MemoExpand{Revisions: true, Attachments: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			mockRepo.On("GetByID", mock.Anything, 1).Return(memo, nil)
+			// 要求されたコレクションのみ取得されること（未設定の呼び出しはモックがpanicする）
+			if tt.expand.Revisions {
+				mockRepo.On("ListRevisions", mock.Anything, 1).Return(revisions, nil)
+			}
+			if tt.expand.Attachments {
+				mockRepo.On("ListAttachments", mock.Anything, 1).Return(attachments, nil)
+			}
+
+			uc := usecase.NewMemoUsecase(mockRepo)
+
+			detail, err := uc.GetMemoDetail(context.Background(), 1, tt.expand)
+
+			assert.NoError(t, err)
+			assert.Equal(t, memo, detail.Memo)
+			if tt.expand.Revisions {
+				assert.Equal(t, revisions, detail.Revisions)
+			} else {
+				assert.Nil(t, detail.Revisions)
+			}
+			if tt.expand.Attachments {
+				assert.Equal(t, attachments, detail.Attachments)
+			} else {
+				assert.Nil(t, detail.Attachments)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("memo not found", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("GetByID", mock.Anything, 999).Return(nil, errors.New("memo not found"))
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+
+		detail, err := uc.GetMemoDetail(context.Background(), 999, domain.MemoExpand{Revisions: true})
+
+		assert.ErrorIs(t, err, usecase.ErrMemoNotFound)
+		assert.Nil(t, detail)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestMemoUsecase_SearchMemos_RecordsRecentQuery(t *testing.T) {
+	filter := domain.MemoFilter{Page: 1, Limit: 10}
+
+	tests := []struct {
+		name        string
+		query       string
+		config      *config.MemoConfig
+		recordErr   error
+		expectStore bool
+	}{
+		{
+			name:        "non-empty query is recorded with configured limit",
+			query:       "golang",
+			config:      &config.MemoConfig{RecentQueriesLimit: 5},
+			expectStore: true,
+		},
+		{
+			name:        "empty query is not recorded",
+			query:       "   ",
+			config:      &config.MemoConfig{RecentQueriesLimit: 5},
+			expectStore: false,
+		},
+		{
+			name:        "zero limit disables recording",
+			query:       "golang",
+			config:      &config.MemoConfig{RecentQueriesLimit: 0},
+			expectStore: false,
+		},
+		{
+			name:        "record failure does not fail search",
+			query:       "golang",
+			config:      &config.MemoConfig{RecentQueriesLimit: 5},
+			recordErr:   assert.AnError,
+			expectStore: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			mockRepo.On("Search", mock.Anything, tt.query, filter).Return([]domain.Memo{{ID: 1, Title: "Go Memo"}}, 1, nil)
+			if tt.expectStore {
+				mockRepo.On("RecordSearchQuery", mock.Anything, tt.query, tt.config.RecentQueriesLimit).Return(tt.recordErr)
+			}
+
+			uc := usecase.NewMemoUsecaseWithConfig(mockRepo, tt.config)
+
+			memos, total, err := uc.SearchMemos(context.Background(), tt.query, filter)
+
+			assert.NoError(t, err)
+			assert.Len(t, memos, 1)
+			assert.Equal(t, 1, total)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoUsecase_ContentMaxLength(t *testing.T) {
+	cfg := &config.MemoConfig{ContentMaxLength: 20, ContentSoftLimit: 10}
+
+	t.Run("create accepts content at the hard limit", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Memo")).Return(&domain.Memo{ID: 1}, nil)
+
+		uc := usecase.NewMemoUsecaseWithConfig(mockRepo, cfg)
+		_, err := uc.CreateMemo(context.Background(), usecase.CreateMemoRequest{
+			Title:   "Long memo",
+			Content: strings.Repeat("あ", 20),
+		})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("create rejects content above the hard limit", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+
+		uc := usecase.NewMemoUsecaseWithConfig(mockRepo, cfg)
+		_, err := uc.CreateMemo(context.Background(), usecase.CreateMemoRequest{
+			Title:   "Too long memo",
+			Content: strings.Repeat("a", 21),
+		})
+
+		assert.Equal(t, usecase.ErrContentTooLong, err)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("update rejects content above the hard limit", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		content := strings.Repeat("a", 21)
+
+		uc := usecase.NewMemoUsecaseWithConfig(mockRepo, cfg)
+		_, err := uc.UpdateMemo(context.Background(), 1, usecase.UpdateMemoRequest{Content: &content})
+
+		assert.Equal(t, usecase.ErrContentTooLong, err)
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemoUsecase_ListTags(t *testing.T) {
+	cfg := &config.MemoConfig{TagsDefaultLimit: 100, TagsMaxLimit: 500}
+
+	tests := []struct {
+		name     string
+		filter   domain.TagFilter
+		expected domain.TagFilter
+	}{
+		{name: "defaults applied", filter: domain.TagFilter{}, expected: domain.TagFilter{MinCount: 1, Limit: 100}},
+		{name: "explicit values kept", filter: domain.TagFilter{MinCount: 3, Limit: 20}, expected: domain.TagFilter{MinCount: 3, Limit: 20}},
+		{name: "limit capped at configured max", filter: domain.TagFilter{Limit: 10000}, expected: domain.TagFilter{MinCount: 1, Limit: 500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			tags := []domain.TagCount{{Tag: "golang", Count: 5}, {Tag: "docker", Count: 2}}
+			mockRepo.On("ListTags", mock.Anything, tt.expected).Return(tags, nil)
+
+			uc := usecase.NewMemoUsecaseWithConfig(mockRepo, cfg)
+			result, err := uc.ListTags(context.Background(), tt.filter)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tags, result)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoUsecase_ListMemos_PageSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        *config.MemoConfig
+		limit         int
+		expectedLimit int
+	}{
+		{name: "configured default applied", config: &config.MemoConfig{DefaultPageSize: 25, MaxPageSize: 200}, limit: 0, expectedLimit: 25},
+		{name: "limit capped at configured max", config: &config.MemoConfig{DefaultPageSize: 25, MaxPageSize: 200}, limit: 500, expectedLimit: 200},
+		{name: "unset sizes fall back to the defaults", config: &config.MemoConfig{}, limit: 0, expectedLimit: 10},
+		{name: "unset max caps at the default max", config: &config.MemoConfig{}, limit: 500, expectedLimit: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			mockRepo.On("List", mock.Anything, domain.MemoFilter{Page: 1, Limit: tt.expectedLimit}).Return([]domain.Memo{}, 0, nil)
+
+			uc := usecase.NewMemoUsecaseWithConfig(mockRepo, tt.config)
+			_, _, err := uc.ListMemos(context.Background(), domain.MemoFilter{Limit: tt.limit})
+
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoUsecase_ImportMemosWithIDs(t *testing.T) {
+	t.Run("passes IDs through with defaults applied", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		expected := []domain.Memo{
+			{ID: 42, Title: "Imported", Content: "Content", Tags: []string{"legacy"}, Priority: domain.PriorityMedium, Status: domain.StatusActive},
+		}
+		mockRepo.On("CreateWithIDs", mock.Anything, expected).Return(expected, nil)
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		memos, err := uc.ImportMemosWithIDs(context.Background(), []usecase.ImportMemoRequest{
+			{ID: 42, CreateMemoRequest: usecase.CreateMemoRequest{Title: "Imported", Content: "Content", Tags: []string{"legacy"}}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, memos)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects missing, duplicate and non-positive IDs", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		uc := usecase.NewMemoUsecase(mockRepo)
+
+		valid := usecase.CreateMemoRequest{Title: "Title", Content: "Content"}
+		for _, reqs := range [][]usecase.ImportMemoRequest{
+			nil,
+			{{ID: 0, CreateMemoRequest: valid}},
+			{{ID: 1, CreateMemoRequest: valid}, {ID: 1, CreateMemoRequest: valid}},
+		} {
+			_, err := uc.ImportMemosWithIDs(context.Background(), reqs)
+			assert.Equal(t, usecase.ErrInvalidImportIDs, err)
+		}
+
+		_, err := uc.ImportMemosWithIDs(context.Background(), []usecase.ImportMemoRequest{
+			{ID: 1, CreateMemoRequest: usecase.CreateMemoRequest{Content: "Content"}},
+		})
+		assert.Equal(t, usecase.ErrInvalidTitle, err)
+		mockRepo.AssertNotCalled(t, "CreateWithIDs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("maps taken IDs to ErrMemoIDConflict", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("CreateWithIDs", mock.Anything, mock.Anything).Return(nil, errors.New("memo id already exists: 42"))
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		_, err := uc.ImportMemosWithIDs(context.Background(), []usecase.ImportMemoRequest{
+			{ID: 42, CreateMemoRequest: usecase.CreateMemoRequest{Title: "Title", Content: "Content"}},
+		})
+
+		assert.Equal(t, usecase.ErrMemoIDConflict, err)
+	})
+}
+
+func TestMemoUsecase_GetMemoStats(t *testing.T) {
+	// 認証の有無にかかわらずリポジトリに委ね、対象の絞り込みはリポジトリの userScope が行う
+	for name, ctx := range map[string]context.Context{
+		"authenticated":   domain.WithUserID(context.Background(), 7),
+		"unauthenticated": context.Background(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			expected := domain.NewMemoStats()
+			expected.Total = 5
+			mockRepo.On("Stats", ctx).Return(expected, nil)
+
+			uc := usecase.NewMemoUsecase(mockRepo)
+			stats, err := uc.GetMemoStats(ctx)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, stats)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoUsecase_GetMemoListState(t *testing.T) {
+	// 認証の有無にかかわらずリポジトリに委ね、対象の絞り込みはリポジトリの userScope が行う
+	for name, ctx := range map[string]context.Context{
+		"authenticated":   domain.WithUserID(context.Background(), 7),
+		"unauthenticated": context.Background(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			expected := &domain.MemoListState{MaxUpdatedAt: time.Now(), Count: 3, PinnedIDs: "1,2"}
+			mockRepo.On("MaxUpdatedAt", ctx).Return(expected, nil)
+
+			uc := usecase.NewMemoUsecase(mockRepo)
+			state, err := uc.GetMemoListState(ctx)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, state)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoUsecase_UpdateMemo_Version(t *testing.T) {
+	title := "Updated"
+
+	t.Run("stale version fails before writing", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{ID: 1, Title: "Title", Content: "Content", Version: 3}, nil)
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		stale := 2
+		_, err := uc.UpdateMemo(context.Background(), 1, usecase.UpdateMemoRequest{Title: &title, Version: &stale})
+
+		assert.Equal(t, usecase.ErrVersionConflict, err)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("passes the read version to the repository and maps conflicts", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{ID: 1, Title: "Title", Content: "Content", Version: 3}, nil)
+		mockRepo.On("Update", mock.Anything, 1, mock.MatchedBy(func(memo *domain.Memo) bool {
+			return memo.Version == 3 && memo.Title == title
+		})).Return(nil, errors.New("memo version conflict: expected 3, current 4"))
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		current := 3
+		_, err := uc.UpdateMemo(context.Background(), 1, usecase.UpdateMemoRequest{Title: &title, Version: &current})
+
+		assert.Equal(t, usecase.ErrVersionConflict, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestMemoUsecase_UpdateMemoMetadata(t *testing.T) {
+	t.Run("normalizes and passes only the provided fields", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		high := domain.PriorityHigh
+		color := "#A1B2C3"
+		expected := &domain.Memo{ID: 1, Content: "Original", Priority: high, Color: color}
+		mockRepo.On("UpdateMetadata", mock.Anything, 1, domain.MemoMetadataUpdate{
+			Tags:     []string{"go"},
+			Priority: &high,
+			Color:    &color,
+		}).Return(expected, nil)
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		priority := "high"
+		memo, err := uc.UpdateMemoMetadata(context.Background(), 1, usecase.UpdateMemoMetadataRequest{
+			Tags:     []string{" go ", "go"},
+			Priority: &priority,
+			Color:    &color,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, memo)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("due date is only applied when set or cleared", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		due := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+		mockRepo.On("UpdateMetadata", mock.Anything, 1, domain.MemoMetadataUpdate{DueDate: &due, DueDateSet: true}).Return(&domain.Memo{ID: 1}, nil).Once()
+		mockRepo.On("UpdateMetadata", mock.Anything, 1, domain.MemoMetadataUpdate{DueDateSet: true}).Return(&domain.Memo{ID: 1}, nil).Once()
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		_, err := uc.UpdateMemoMetadata(context.Background(), 1, usecase.UpdateMemoMetadataRequest{DueDate: &due})
+		assert.NoError(t, err)
+		_, err = uc.UpdateMemoMetadata(context.Background(), 1, usecase.UpdateMemoMetadataRequest{ClearDueDate: true})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("validates the request", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		uc := usecase.NewMemoUsecase(mockRepo)
+		invalidPriority := "urgent"
+		invalidColor := "red"
+
+		_, err := uc.UpdateMemoMetadata(context.Background(), 1, usecase.UpdateMemoMetadataRequest{})
+		assert.Equal(t, usecase.ErrEmptyMetadataUpdate, err)
+		_, err = uc.UpdateMemoMetadata(context.Background(), 1, usecase.UpdateMemoMetadataRequest{Priority: &invalidPriority})
+		assert.Equal(t, usecase.ErrInvalidPriority, err)
+		_, err = uc.UpdateMemoMetadata(context.Background(), 1, usecase.UpdateMemoMetadataRequest{Color: &invalidColor})
+		assert.Equal(t, usecase.ErrInvalidColor, err)
+		mockRepo.AssertNotCalled(t, "UpdateMetadata", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("maps missing memos to ErrMemoNotFound", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("UpdateMetadata", mock.Anything, 99, mock.Anything).Return(nil, errors.New("memo not found"))
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		empty := ""
+		_, err := uc.UpdateMemoMetadata(context.Background(), 99, usecase.UpdateMemoMetadataRequest{Color: &empty})
+		assert.Equal(t, usecase.ErrMemoNotFound, err)
+	})
+}
+
+func TestMemoUsecase_RevertMemo(t *testing.T) {
+	t.Run("restores the revision through a regular update", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		existing := &domain.Memo{ID: 1, Title: "Current", Content: "Current", Category: "work", Tags: []string{"new"},
+			Priority: domain.PriorityHigh, Status: domain.StatusActive, Color: "#ffffff", Version: 3}
+		revision := &domain.MemoRevision{ID: 7, MemoID: 1, Title: "Old", Content: "Old content", Category: "personal",
+			Tags: []string{"old"}, Priority: domain.PriorityLow}
+		mockRepo.On("GetByID", mock.Anything, 1).Return(existing, nil)
+		mockRepo.On("GetRevision", mock.Anything, 1, 7).Return(revision, nil)
+		mockRepo.On("Update", mock.Anything, 1, mock.MatchedBy(func(memo *domain.Memo) bool {
+			return memo.Title == "Old" && memo.Content == "Old content" && memo.Category == "personal" &&
+				memo.Priority == domain.PriorityLow && len(memo.Tags) == 1 && memo.Tags[0] == "old" &&
+				memo.Status == domain.StatusActive && memo.Color == "#ffffff" && memo.Version == 3
+		})).Return(&domain.Memo{ID: 1, Title: "Old", Version: 4}, nil)
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		memo, err := uc.RevertMemo(context.Background(), 1, 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, memo.Version)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("maps repository errors", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("GetByID", mock.Anything, 99).Return(nil, errors.New("memo not found"))
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{ID: 1, Version: 2}, nil)
+		mockRepo.On("GetRevision", mock.Anything, 1, 8).Return(nil, errors.New("revision not found"))
+		mockRepo.On("GetRevision", mock.Anything, 1, 7).Return(&domain.MemoRevision{ID: 7, MemoID: 1}, nil)
+		mockRepo.On("Update", mock.Anything, 1, mock.Anything).Return(nil, errors.New("memo version conflict: expected 2, current 3"))
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		_, err := uc.RevertMemo(context.Background(), 99, 7)
+		assert.Equal(t, usecase.ErrMemoNotFound, err)
+		_, err = uc.RevertMemo(context.Background(), 1, 8)
+		assert.Equal(t, usecase.ErrRevisionNotFound, err)
+		_, err = uc.RevertMemo(context.Background(), 1, 7)
+		assert.Equal(t, usecase.ErrVersionConflict, err)
+	})
+}
+
+func TestMemoUsecase_ListMemoHistory(t *testing.T) {
+	mockRepo := new(MockMemoRepository)
+	revisions := []domain.MemoRevision{{ID: 2, MemoID: 1}, {ID: 1, MemoID: 1}}
+	mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{ID: 1}, nil)
+	mockRepo.On("GetByID", mock.Anything, 99).Return(nil, errors.New("memo not found"))
+	mockRepo.On("ListRevisions", mock.Anything, 1).Return(revisions, nil)
+
+	uc := usecase.NewMemoUsecase(mockRepo)
+	result, err := uc.ListMemoHistory(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, revisions, result)
+
+	_, err = uc.ListMemoHistory(context.Background(), 99)
+	assert.Equal(t, usecase.ErrMemoNotFound, err)
+	mockRepo.AssertNotCalled(t, "ListRevisions", mock.Anything, 99)
+}
+
+func TestMemoUsecase_ImportMemos(t *testing.T) {
+	valid := usecase.CreateMemoRequest{Title: "Title", Content: "Content", Tags: []string{"go", " go "}}
+	invalid := usecase.CreateMemoRequest{Content: "Content"}
+
+	t.Run("creates memos independently and reports failures", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Memo")).Return(&domain.Memo{ID: 1}, nil).Once()
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		result, err := uc.ImportMemos(context.Background(), []usecase.CreateMemoRequest{valid, invalid}, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Imported)
+		assert.Equal(t, []usecase.ImportFailure{{Index: 1, Err: usecase.ErrInvalidTitle}}, result.Failed)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("atomic creates nothing when a memo is invalid", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		uc := usecase.NewMemoUsecase(mockRepo)
+
+		result, err := uc.ImportMemos(context.Background(), []usecase.CreateMemoRequest{valid, invalid}, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Imported)
+		assert.Len(t, result.Failed, 1)
+		mockRepo.AssertNotCalled(t, "CreateMany", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("atomic inserts every memo in one call", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		expected := []domain.Memo{
+			{Title: "Title", Content: "Content", Tags: []string{"go"}, Priority: domain.PriorityMedium, Status: domain.StatusActive},
+			{Title: "Title", Content: "Content", Tags: []string{"go"}, Priority: domain.PriorityMedium, Status: domain.StatusActive},
+		}
+		mockRepo.On("CreateMany", mock.Anything, expected).Return(expected, nil)
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		result, err := uc.ImportMemos(context.Background(), []usecase.CreateMemoRequest{valid, valid}, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Imported)
+		assert.Empty(t, result.Failed)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects empty imports", func(t *testing.T) {
+		uc := usecase.NewMemoUsecase(new(MockMemoRepository))
+		_, err := uc.ImportMemos(context.Background(), nil, false)
+		assert.Equal(t, usecase.ErrInvalidImport, err)
+	})
+}
+
+func TestMemoUsecase_BulkDeleteMemos(t *testing.T) {
+	t.Run("reports deleted and not found IDs in request order", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("DeleteMany", mock.Anything, []int{3, 1, 2}).Return([]int{1, 3}, nil)
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		results, err := uc.BulkDeleteMemos(context.Background(), []int{3, 1, 3, 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []usecase.BulkResult{
+			{ID: 3, Status: usecase.BulkStatusDeleted},
+			{ID: 1, Status: usecase.BulkStatusDeleted},
+			{ID: 2, Status: usecase.BulkStatusNotFound},
+		}, results)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects empty, oversized and non-positive ID lists", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		uc := usecase.NewMemoUsecase(mockRepo)
+
+		tooMany := make([]int, usecase.MaxBulkIDs+1)
+		for i := range tooMany {
+			tooMany[i] = i + 1
+		}
+
+		for _, ids := range [][]int{nil, tooMany, {1, 0}} {
+			_, err := uc.BulkDeleteMemos(context.Background(), ids)
+			assert.Equal(t, usecase.ErrInvalidBulkIDs, err)
+		}
+		mockRepo.AssertNotCalled(t, "DeleteMany", mock.Anything, mock.Anything)
+	})
+
+	t.Run("propagates repository errors", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("DeleteMany", mock.Anything, []int{1}).Return(nil, errors.New("database error"))
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		results, err := uc.BulkDeleteMemos(context.Background(), []int{1})
+
+		assert.Error(t, err)
+		assert.Nil(t, results)
+	})
+}
+
+func TestMemoUsecase_BulkUpdateMemos(t *testing.T) {
+	archived := "archived"
+	category := "work"
+
+	t.Run("applies status and category and reports skipped IDs", func(t *testing.T) {
+		status := domain.StatusArchived
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("BulkUpdate", mock.Anything, []int{1, 2, 3}, domain.MemoBulkUpdate{Category: &category, Status: &status}).
+			Return([]int{1, 3}, nil)
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		result, err := uc.BulkUpdateMemos(context.Background(), []int{1, 2, 3, 1}, usecase.UpdateMemoRequest{
+			Status:   &archived,
+			Category: &category,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &usecase.BulkUpdateResult{Updated: 2, SkippedIDs: []int{2}}, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid requests without touching the repository", func(t *testing.T) {
+		invalidStatus := "deleted"
+		title := "title"
+		tests := []struct {
+			name        string
+			req         usecase.UpdateMemoRequest
+			expectedErr error
+		}{
+			{name: "no fields", req: usecase.UpdateMemoRequest{}, expectedErr: usecase.ErrInvalidBulkUpdate},
+			{name: "unsupported field", req: usecase.UpdateMemoRequest{Status: &archived, Title: &title}, expectedErr: usecase.ErrInvalidBulkUpdate},
+			{name: "invalid status", req: usecase.UpdateMemoRequest{Status: &invalidStatus}, expectedErr: usecase.ErrInvalidStatus},
+			{name: "due date", req: usecase.UpdateMemoRequest{Status: &archived, DueDate: &time.Time{}}, expectedErr: usecase.ErrInvalidBulkUpdate},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockRepo := new(MockMemoRepository)
+				uc := usecase.NewMemoUsecase(mockRepo)
+
+				_, err := uc.BulkUpdateMemos(context.Background(), []int{1}, tt.req)
+				assert.Equal(t, tt.expectedErr, err)
+				mockRepo.AssertNotCalled(t, "BulkUpdate", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
+
+func TestMemoUsecase_PermanentDeleteMemo(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoError   error
+		expectedErr error
+	}{
+		{name: "trashed memo", repoError: nil, expectedErr: nil},
+		{name: "memo not in trash", repoError: errors.New("memo is not in trash"), expectedErr: usecase.ErrMemoNotTrashed},
+		{name: "memo not found", repoError: errors.New("memo not found"), expectedErr: usecase.ErrMemoNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			mockRepo.On("PermanentDelete", mock.Anything, 1).Return(tt.repoError)
+
+			uc := usecase.NewMemoUsecase(mockRepo)
+			err := uc.PermanentDeleteMemo(context.Background(), 1)
+
+			assert.Equal(t, tt.expectedErr, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoUsecase_RestoreDeletedMemo(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoMemo    *domain.Memo
+		repoError   error
+		expectedErr error
+	}{
+		{name: "restored", repoMemo: &domain.Memo{ID: 5, Status: domain.StatusActive}},
+		{name: "deleted memo not found", repoError: errors.New("deleted memo not found"), expectedErr: usecase.ErrDeletedMemoNotFound},
+		{name: "repository failure", repoError: errors.New("connection refused"), expectedErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMemoRepository)
+			mockRepo.On("RestoreDeleted", mock.Anything, 3).Return(tt.repoMemo, tt.repoError)
+
+			uc := usecase.NewMemoUsecase(mockRepo)
+			memo, err := uc.RestoreDeletedMemo(context.Background(), 3)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.repoMemo, memo)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoUsecase_ListDeletedMemos(t *testing.T) {
+	t.Run("normalizes the page and limit", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("ListDeleted", mock.Anything, mock.MatchedBy(func(filter domain.MemoFilter) bool {
+			return filter.Page == 1 && filter.Limit == 10
+		})).Return([]domain.DeletedMemo{{ID: 1, Memo: domain.Memo{ID: 9}}}, 1, nil)
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		deleted, total, err := uc.ListDeletedMemos(context.Background(), domain.MemoFilter{})
+
+		assert.NoError(t, err)
+		assert.Len(t, deleted, 1)
+		assert.Equal(t, 1, total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("caps the limit", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("ListDeleted", mock.Anything, mock.MatchedBy(func(filter domain.MemoFilter) bool {
+			return filter.Limit == 100
+		})).Return([]domain.DeletedMemo{}, 0, nil)
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		_, _, err := uc.ListDeletedMemos(context.Background(), domain.MemoFilter{Page: 1, Limit: 1000})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestMemoUsecase_DueDate(t *testing.T) {
+	dueDate := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+
+	t.Run("create passes the due date to the repository", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(memo *domain.Memo) bool {
+			return memo.DueDate != nil && memo.DueDate.Equal(dueDate)
+		})).Return(&domain.Memo{ID: 1, DueDate: &dueDate}, nil)
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		memo, err := uc.CreateMemo(context.Background(), usecase.CreateMemoRequest{Title: "Task", Content: "Do it", DueDate: &dueDate})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &dueDate, memo.DueDate)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("update keeps the due date unless one is given", func(t *testing.T) {
+		newDueDate := dueDate.Add(24 * time.Hour)
+		title := "Renamed"
+		tests := []struct {
+			name     string
+			req      usecase.UpdateMemoRequest
+			expected time.Time
+		}{
+			{name: "not given", req: usecase.UpdateMemoRequest{Title: &title}, expected: dueDate},
+			{name: "given", req: usecase.UpdateMemoRequest{DueDate: &newDueDate}, expected: newDueDate},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockRepo := new(MockMemoRepository)
+				mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{
+					ID: 1, Title: "Task", Content: "Do it", Priority: domain.PriorityMedium, Status: domain.StatusActive, DueDate: &dueDate,
+				}, nil)
+				mockRepo.On("Update", mock.Anything, 1, mock.MatchedBy(func(memo *domain.Memo) bool {
+					return memo.DueDate != nil && memo.DueDate.Equal(tt.expected)
+				})).Return(&domain.Memo{ID: 1}, nil)
+
+				uc := usecase.NewMemoUsecase(mockRepo)
+				_, err := uc.UpdateMemo(context.Background(), 1, tt.req)
+
+				assert.NoError(t, err)
+				mockRepo.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestMemoUsecase_ProtectedCategories(t *testing.T) {
+	cfg := config.DefaultMemoConfig()
+	cfg.ProtectedCategories = []string{"inbox"}
+	activeFilter := domain.MemoFilter{Category: "inbox", Status: domain.StatusActive, Page: 1, Limit: 1}
+
+	t.Run("archiving the last active memo is rejected", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{ID: 1, Category: "inbox", Status: domain.StatusActive}, nil)
+		mockRepo.On("List", mock.Anything, activeFilter).Return([]domain.Memo{{ID: 1}}, 1, nil)
+
+		uc := usecase.NewMemoUsecaseWithConfig(mockRepo, cfg)
+		err := uc.ArchiveMemo(context.Background(), 1)
+
+		assert.Equal(t, usecase.ErrLastActiveInCategory, err)
+		mockRepo.AssertNotCalled(t, "Archive", mock.Anything, mock.Anything)
+	})
+
+	t.Run("archiving a non-last active memo is allowed", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{ID: 1, Category: "inbox", Status: domain.StatusActive}, nil)
+		mockRepo.On("List", mock.Anything, activeFilter).Return([]domain.Memo{{ID: 2}}, 2, nil)
+		mockRepo.On("Archive", mock.Anything, 1).Return(nil)
+
+		uc := usecase.NewMemoUsecaseWithConfig(mockRepo, cfg)
+		err := uc.ArchiveMemo(context.Background(), 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("deleting and trashing the last active memo are rejected", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{ID: 1, Category: "inbox", Status: domain.StatusActive}, nil)
+		mockRepo.On("List", mock.Anything, activeFilter).Return([]domain.Memo{{ID: 1}}, 1, nil)
+
+		uc := usecase.NewMemoUsecaseWithConfig(mockRepo, cfg)
+
+		assert.Equal(t, usecase.ErrLastActiveInCategory, uc.DeleteMemo(context.Background(), 1))
+		_, err := uc.TrashMemo(context.Background(), 1)
+		assert.Equal(t, usecase.ErrLastActiveInCategory, err)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Trash", mock.Anything, mock.Anything)
+	})
+
+	t.Run("updating the last active memo to archived is rejected", func(t *testing.T) {
+		archived := "archived"
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{
+			ID: 1, Title: "Triage", Content: "Content", Category: "inbox", Priority: domain.PriorityMedium, Status: domain.StatusActive,
+		}, nil)
+		mockRepo.On("List", mock.Anything, activeFilter).Return([]domain.Memo{{ID: 1}}, 1, nil)
+
+		uc := usecase.NewMemoUsecaseWithConfig(mockRepo, cfg)
+		_, err := uc.UpdateMemo(context.Background(), 1, usecase.UpdateMemoRequest{Status: &archived})
+
+		assert.Equal(t, usecase.ErrLastActiveInCategory, err)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("bulk delete of every active memo in the category is rejected", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{ID: 1, Category: "inbox", Status: domain.StatusActive}, nil)
+		mockRepo.On("GetByID", mock.Anything, 2).Return(&domain.Memo{ID: 2, Category: "inbox", Status: domain.StatusActive}, nil)
+		mockRepo.On("List", mock.Anything, activeFilter).Return([]domain.Memo{{ID: 1}}, 2, nil)
+
+		uc := usecase.NewMemoUsecaseWithConfig(mockRepo, cfg)
+		_, err := uc.BulkDeleteMemos(context.Background(), []int{1, 2})
+
+		assert.Equal(t, usecase.ErrLastActiveInCategory, err)
+		mockRepo.AssertNotCalled(t, "DeleteMany", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unprotected categories are not checked", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Memo{ID: 1, Category: "work", Status: domain.StatusActive}, nil)
+		mockRepo.On("Archive", mock.Anything, 1).Return(nil)
+
+		uc := usecase.NewMemoUsecaseWithConfig(mockRepo, cfg)
+		err := uc.ArchiveMemo(context.Background(), 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemoUsecase_PinMemo(t *testing.T) {
+	t.Run("pin and unpin set the flag", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("SetPinned", mock.Anything, 1, true).Return(&domain.Memo{ID: 1, Pinned: true}, nil)
+		mockRepo.On("SetPinned", mock.Anything, 1, false).Return(&domain.Memo{ID: 1, Pinned: false}, nil)
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+
+		memo, err := uc.PinMemo(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.True(t, memo.Pinned)
+
+		memo, err = uc.UnpinMemo(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.False(t, memo.Pinned)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing memo maps to ErrMemoNotFound", func(t *testing.T) {
+		mockRepo := new(MockMemoRepository)
+		mockRepo.On("SetPinned", mock.Anything, 999, true).Return(nil, errors.New("memo not found"))
+
+		uc := usecase.NewMemoUsecase(mockRepo)
+		_, err := uc.PinMemo(context.Background(), 999)
+
+		assert.Equal(t, usecase.ErrMemoNotFound, err)
+	})
+}
+
+func TestMemoUsecase_ListOnThisDay(t *testing.T) {
+	mockRepo := new(MockMemoRepository)
+	mockRepo.On("ListOnThisDay", mock.Anything, mock.MatchedBy(func(date time.Time) bool {
+		return time.Since(date) < time.Minute
+	})).Return([]domain.Memo{{ID: 1}}, nil)
+
+	uc := usecase.NewMemoUsecase(mockRepo)
+	memos, err := uc.ListOnThisDay(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, memos, 1)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,245 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"memo-app/src/domain"
+	"memo-app/src/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockWebhookRepository は domain.WebhookRepository のモック実装
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	args := m.Called(ctx, webhook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetByID(ctx context.Context, id int) (*domain.Webhook, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Update(ctx context.Context, id int, webhook *domain.Webhook) (*domain.Webhook, error) {
+	args := m.Called(ctx, id, webhook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListByEvent(ctx context.Context, userID int, event string) ([]domain.Webhook, error) {
+	args := m.Called(ctx, userID, event)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func TestWebhookUsecase_CreateWebhook(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+
+	t.Run("イベント未指定の場合はすべてのイベントを購読し、シークレットを生成する", func(t *testing.T) {
+		repo := new(MockWebhookRepository)
+		repo.On("Create", ctx, mock.MatchedBy(func(w *domain.Webhook) bool {
+			return w.URL == "https://hooks.example.com/memos" && len(w.Secret) == 64 &&
+				assert.ObjectsAreEqual(domain.MemoEvents, w.Events)
+		})).Return(&domain.Webhook{ID: 1}, nil)
+
+		_, err := usecase.NewWebhookUsecase(repo).CreateWebhook(ctx, usecase.CreateWebhookRequest{URL: "https://hooks.example.com/memos"})
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("重複したイベントはまとめる", func(t *testing.T) {
+		repo := new(MockWebhookRepository)
+		repo.On("Create", ctx, mock.MatchedBy(func(w *domain.Webhook) bool {
+			return assert.ObjectsAreEqual([]string{domain.MemoEventDeleted}, w.Events) && w.Secret == "0123456789abcdef"
+		})).Return(&domain.Webhook{ID: 1}, nil)
+
+		_, err := usecase.NewWebhookUsecase(repo).CreateWebhook(ctx, usecase.CreateWebhookRequest{
+			URL:    "http://localhost:9000/hook",
+			Secret: "0123456789abcdef",
+			Events: []string{domain.MemoEventDeleted, domain.MemoEventDeleted},
+		})
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	invalid := []struct {
+		name        string
+		ctx         context.Context
+		req         usecase.CreateWebhookRequest
+		expectedErr error
+	}{
+		{"未認証", context.Background(), usecase.CreateWebhookRequest{URL: "https://hooks.example.com"}, usecase.ErrWebhookUnauthorized},
+		{"httpではないURL", ctx, usecase.CreateWebhookRequest{URL: "ftp://hooks.example.com"}, usecase.ErrInvalidWebhookURL},
+		{"相対URL", ctx, usecase.CreateWebhookRequest{URL: "/hooks"}, usecase.ErrInvalidWebhookURL},
+		{"不明なイベント", ctx, usecase.CreateWebhookRequest{URL: "https://hooks.example.com", Events: []string{"memo.viewed"}}, usecase.ErrInvalidWebhookEvents},
+		{"短すぎるシークレット", ctx, usecase.CreateWebhookRequest{URL: "https://hooks.example.com", Secret: "short"}, usecase.ErrInvalidWebhookSecret},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockWebhookRepository)
+			_, err := usecase.NewWebhookUsecase(repo).CreateWebhook(tt.ctx, tt.req)
+			assert.Equal(t, tt.expectedErr, err)
+			repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWebhookUsecase_UpdateWebhook(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	existing := &domain.Webhook{ID: 3, UserID: 42, URL: "https://old.example.com", Secret: "0123456789abcdef", Events: domain.MemoEvents}
+
+	t.Run("指定したフィールドだけを更新する", func(t *testing.T) {
+		repo := new(MockWebhookRepository)
+		repo.On("GetByID", ctx, 3).Return(existing, nil)
+		repo.On("Update", ctx, 3, mock.MatchedBy(func(w *domain.Webhook) bool {
+			return w.URL == "https://old.example.com" && w.Secret == existing.Secret &&
+				assert.ObjectsAreEqual([]string{domain.MemoEventCreated}, w.Events)
+		})).Return(existing, nil)
+
+		_, err := usecase.NewWebhookUsecase(repo).UpdateWebhook(ctx, 3, usecase.UpdateWebhookRequest{Events: []string{domain.MemoEventCreated}})
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("他のユーザーのWebhookは見つからない", func(t *testing.T) {
+		repo := new(MockWebhookRepository)
+		repo.On("GetByID", ctx, 3).Return(nil, errors.New("webhook not found"))
+
+		_, err := usecase.NewWebhookUsecase(repo).UpdateWebhook(ctx, 3, usecase.UpdateWebhookRequest{})
+		assert.Equal(t, usecase.ErrWebhookNotFound, err)
+	})
+}
+
+func TestWebhookUsecase_DeleteWebhook(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	repo := new(MockWebhookRepository)
+	repo.On("Delete", ctx, 3).Return(errors.New("webhook not found"))
+
+	assert.Equal(t, usecase.ErrWebhookNotFound, usecase.NewWebhookUsecase(repo).DeleteWebhook(ctx, 3))
+	assert.Equal(t, usecase.ErrWebhookUnauthorized, usecase.NewWebhookUsecase(repo).DeleteWebhook(context.Background(), 3))
+}
+
+// recordingPublisher は通知されたメモのイベントを記録する
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []string
+	memos  []*domain.Memo
+}
+
+func (p *recordingPublisher) PublishMemoEvent(ctx context.Context, event string, memo *domain.Memo) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	p.memos = append(p.memos, memo)
+}
+
+func TestMemoUsecase_PublishesMemoEvents(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), 42)
+	memo := &domain.Memo{ID: 1, Title: "Title", Content: "Content", Priority: domain.PriorityMedium, Status: domain.StatusActive}
+
+	t.Run("作成", func(t *testing.T) {
+		repo := new(MockMemoRepository)
+		repo.On("Create", ctx, mock.Anything).Return(memo, nil)
+		publisher := &recordingPublisher{}
+
+		_, err := usecase.NewMemoUsecaseWithPublisher(repo, nil, publisher).CreateMemo(ctx, usecase.CreateMemoRequest{Title: "Title", Content: "Content"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{domain.MemoEventCreated}, publisher.events)
+		assert.Equal(t, memo, publisher.memos[0])
+	})
+
+	t.Run("一括インポート（atomic）はコミット後に作成したメモごとに通知する", func(t *testing.T) {
+		remindAt := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+		created := []domain.Memo{{ID: 1, Title: "A", Content: "Content"}, {ID: 2, Title: "B", Content: "Content", RemindAt: &remindAt}}
+		repo := new(MockMemoRepository)
+		repo.On("CreateMany", ctx, mock.MatchedBy(func(memos []domain.Memo) bool {
+			return len(memos) == 2 && memos[1].RemindAt != nil && memos[1].RemindAt.Equal(remindAt)
+		})).Return(created, nil)
+		publisher := &recordingPublisher{}
+
+		result, err := usecase.NewMemoUsecaseWithPublisher(repo, nil, publisher).ImportMemos(ctx, []usecase.CreateMemoRequest{
+			{Title: "A", Content: "Content"},
+			{Title: "B", Content: "Content", RemindAt: &remindAt},
+		}, true)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, []string{domain.MemoEventCreated, domain.MemoEventCreated}, publisher.events)
+		assert.Equal(t, 2, publisher.memos[1].ID)
+	})
+
+	t.Run("削除はメモを残すため、アーカイブまたはゴミ箱に移動した後のメモを更新として通知する", func(t *testing.T) {
+		archived := *memo
+		archived.Status = domain.StatusArchived
+		repo := new(MockMemoRepository)
+		repo.On("Delete", ctx, 1).Return(nil)
+		repo.On("GetByID", ctx, 1).Return(&archived, nil)
+		publisher := &recordingPublisher{}
+
+		require.NoError(t, usecase.NewMemoUsecaseWithPublisher(repo, nil, publisher).DeleteMemo(ctx, 1))
+		assert.Equal(t, []string{domain.MemoEventUpdated}, publisher.events)
+		assert.Equal(t, domain.StatusArchived, publisher.memos[0].Status)
+	})
+
+	t.Run("完全削除は削除前のメモを通知する", func(t *testing.T) {
+		trashed := *memo
+		trashed.Status = domain.StatusTrashed
+		repo := new(MockMemoRepository)
+		repo.On("GetByID", ctx, 1).Return(&trashed, nil)
+		repo.On("PermanentDelete", ctx, 1).Return(nil)
+		publisher := &recordingPublisher{}
+
+		require.NoError(t, usecase.NewMemoUsecaseWithPublisher(repo, nil, publisher).PermanentDeleteMemo(ctx, 1))
+		assert.Equal(t, []string{domain.MemoEventDeleted}, publisher.events)
+		assert.Equal(t, &trashed, publisher.memos[0])
+	})
+
+	t.Run("失敗した操作は通知しない", func(t *testing.T) {
+		repo := new(MockMemoRepository)
+		repo.On("GetByID", ctx, 1).Return(memo, nil)
+		repo.On("PermanentDelete", ctx, 1).Return(errors.New("memo is not in trash"))
+		publisher := &recordingPublisher{}
+
+		err := usecase.NewMemoUsecaseWithPublisher(repo, nil, publisher).PermanentDeleteMemo(ctx, 1)
+		assert.Equal(t, usecase.ErrMemoNotTrashed, err)
+		assert.Empty(t, publisher.events)
+	})
+
+	t.Run("更新", func(t *testing.T) {
+		title := "New"
+		repo := new(MockMemoRepository)
+		repo.On("GetByID", ctx, 1).Return(memo, nil)
+		repo.On("Update", ctx, 1, mock.Anything).Return(memo, nil)
+		publisher := &recordingPublisher{}
+
+		_, err := usecase.NewMemoUsecaseWithPublisher(repo, nil, publisher).UpdateMemo(ctx, 1, usecase.UpdateMemoRequest{Title: &title})
+		require.NoError(t, err)
+		assert.Equal(t, []string{domain.MemoEventUpdated}, publisher.events)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"memo-app/src/validator"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // 認証関連のバリデーションテスト
@@ -157,6 +158,29 @@ func TestCustomValidator_AuthenticationValidation(t *testing.T) {
 		}
 	})
 
+	t.Run("追加の予約語テスト", func(t *testing.T) {
+		type UsernameTest struct {
+			Username string `validate:"required,username_format"`
+		}
+
+		custom := validator.NewCustomValidator()
+		custom.AddReservedUsernames("AcmeCorp", " badword ")
+
+		// 追加した予約語は大文字小文字を区別せずに拒否される
+		assert.Error(t, custom.Validate(&UsernameTest{Username: "acmecorp"}))
+		assert.Error(t, custom.Validate(&UsernameTest{Username: "ACMECORP"}))
+		assert.Error(t, custom.Validate(&UsernameTest{Username: "BadWord"}))
+
+		// デフォルトの予約語も引き続き拒否される
+		assert.Error(t, custom.Validate(&UsernameTest{Username: "admin"}))
+
+		// 通常のユーザー名は許可される
+		assert.NoError(t, custom.Validate(&UsernameTest{Username: "normal_user1"}))
+
+		// 他のバリデーターインスタンスには影響しない
+		assert.NoError(t, v.Validate(&UsernameTest{Username: "acmecorp"}))
+	})
+
 	t.Run("認証リクエスト構造体バリデーション", func(t *testing.T) {
 		type RegisterRequest struct {
 			Username string `validate:"required,min=3,max=30"`
@@ -345,6 +369,21 @@ func TestCustomValidator_SanitizeTags(t *testing.T) {
 			input:    []string{"valid_tag", "invalid<>tag", "日本語タグ"},
 			expected: []string{"valid_tag", "日本語タグ"},
 		},
+		{
+			name:     "全角英数字と半角カナは標準の表記に揃えて残す",
+			input:    []string{"Ｇｏ１２", "ﾒﾓ", "日本語　タグ"},
+			expected: []string{"Go12", "メモ", "日本語 タグ"},
+		},
+		{
+			name:     "表記揺れの重複は正規化後の形で除去する",
+			input:    []string{"Go", "Ｇｏ", "メモ", "ﾒﾓ", "go"},
+			expected: []string{"Go", "メモ", "go"},
+		},
+		{
+			name:     "全角の記号も正規化した上で不正な文字として除去する",
+			input:    []string{"ok", "ｉｎｖａｌｉｄ＜＞"},
+			expected: []string{"ok"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -415,3 +454,60 @@ func TestCustomValidator_ValidateID(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomValidator_ValidateTagCount(t *testing.T) {
+	v := validator.NewCustomValidator()
+
+	t.Run("上限以下のタグ数は許可", func(t *testing.T) {
+		assert.NoError(t, v.ValidateTagCount(nil, 2))
+		assert.NoError(t, v.ValidateTagCount([]string{"a", "b"}, 2))
+	})
+
+	t.Run("上限を超えるタグ数はTagsの検証エラー", func(t *testing.T) {
+		err := v.ValidateTagCount([]string{"a", "b", "c"}, 2)
+		require.Error(t, err)
+
+		validationErrors, ok := err.(validator.ValidationErrors)
+		require.True(t, ok)
+		require.Len(t, validationErrors.Errors, 1)
+		assert.Equal(t, "Tags", validationErrors.Errors[0].Field)
+		assert.Equal(t, "max_tags", validationErrors.Errors[0].Tag)
+		assert.Equal(t, 3, validationErrors.Errors[0].Value)
+		assert.Contains(t, validationErrors.Errors[0].Message, "2 個以下")
+	})
+
+	t.Run("上限が0以下の場合は検証しない", func(t *testing.T) {
+		assert.NoError(t, v.ValidateTagCount([]string{"a", "b", "c"}, 0))
+	})
+}
+
+func TestCustomValidator_SecurityRejections(t *testing.T) {
+	v := validator.NewCustomValidator()
+
+	type TestDTO struct {
+		Title    string `validate:"required,max=10,safe_text,no_sql_injection"`
+		Category string `validate:"omitempty,safe_category"`
+	}
+
+	t.Run("SQLインジェクションはno_sql_injectionとして報告", func(t *testing.T) {
+		err := v.Validate(&TestDTO{Title: "' OR 1=1"})
+		assert.Error(t, err)
+
+		rejections := v.SecurityRejections(err)
+		assert.Equal(t, []validator.SecurityRejection{{Field: "Title", Rule: "no_sql_injection"}}, rejections)
+	})
+
+	t.Run("攻撃パターン以外の拒否はルール名のまま報告", func(t *testing.T) {
+		err := v.Validate(&TestDTO{Title: "ok", Category: "bad category!"})
+		assert.Error(t, err)
+
+		rejections := v.SecurityRejections(err)
+		assert.Equal(t, []validator.SecurityRejection{{Field: "Category", Rule: "safe_category"}}, rejections)
+	})
+
+	t.Run("セキュリティ以外のルールは対象外", func(t *testing.T) {
+		err := v.Validate(&TestDTO{Title: "too long title"})
+		assert.Error(t, err)
+		assert.Empty(t, v.SecurityRejections(err))
+	})
+}
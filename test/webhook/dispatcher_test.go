@@ -0,0 +1,168 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"memo-app/src/domain"
+	"memo-app/src/interface/webhook"
+
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookStore はユーザーごとの購読を返すリポジトリ（ListByEvent のみ使用）
+type webhookStore struct {
+	domain.WebhookRepository
+	webhooks []domain.Webhook
+}
+
+func (s *webhookStore) ListByEvent(ctx context.Context, userID int, event string) ([]domain.Webhook, error) {
+	var matched []domain.Webhook
+	for _, w := range s.webhooks {
+		if w.UserID != userID {
+			continue
+		}
+		for _, e := range w.Events {
+			if e == event {
+				matched = append(matched, w)
+			}
+		}
+	}
+	return matched, nil
+}
+
+// delivery は受信したリクエスト
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver は受信したリクエストを記録し、最初の failures 回は500を返すテスト用のエンドポイント
+type receiver struct {
+	mu         sync.Mutex
+	deliveries []delivery
+	failures   int32
+	attempts   int32
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	if atomic.AddInt32(&r.attempts, 1) <= r.failures {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	r.mu.Lock()
+	r.deliveries = append(r.deliveries, delivery{header: req.Header.Clone(), body: body})
+	r.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *receiver) received() []delivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]delivery(nil), r.deliveries...)
+}
+
+func newTestDispatcher(t *testing.T, webhooks []domain.Webhook, maxAttempts int) *webhook.Dispatcher {
+	t.Helper()
+	logger, _ := logtest.NewNullLogger()
+	d := webhook.NewDispatcher(&webhookStore{webhooks: webhooks}, webhook.Config{
+		Workers:      2,
+		QueueSize:    10,
+		MaxAttempts:  maxAttempts,
+		RetryBackoff: 10 * time.Millisecond,
+		Timeout:      time.Second,
+	}, logger)
+	t.Cleanup(d.Close)
+	return d
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	d := newTestDispatcher(t, []domain.Webhook{
+		{ID: 1, UserID: 42, URL: server.URL, Secret: "0123456789abcdef", Events: []string{domain.MemoEventCreated}},
+		{ID: 2, UserID: 7, URL: server.URL, Secret: "fedcba9876543210", Events: domain.MemoEvents},
+	}, 3)
+
+	ctx := domain.WithUserID(context.Background(), 42)
+	d.PublishMemoEvent(ctx, domain.MemoEventCreated, &domain.Memo{ID: 5, Title: "Title", Priority: domain.PriorityHigh, Status: domain.StatusActive})
+	// 購読していないイベントと所有者のいないメモは送信しない
+	d.PublishMemoEvent(ctx, domain.MemoEventDeleted, &domain.Memo{ID: 5})
+	d.PublishMemoEvent(context.Background(), domain.MemoEventCreated, &domain.Memo{ID: 6})
+
+	require.Eventually(t, func() bool { return len(recv.received()) == 1 }, time.Second, 5*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	require.Len(t, recv.received(), 1)
+
+	got := recv.received()[0]
+	assert.Equal(t, domain.MemoEventCreated, got.header.Get(webhook.EventHeader))
+	assert.Equal(t, "sha256="+webhook.Sign("0123456789abcdef", got.body), got.header.Get(webhook.SignatureHeader))
+
+	var payload struct {
+		Event string `json:"event"`
+		Memo  struct {
+			ID       int    `json:"id"`
+			Priority string `json:"priority"`
+		} `json:"memo"`
+	}
+	require.NoError(t, json.Unmarshal(got.body, &payload))
+	assert.Equal(t, domain.MemoEventCreated, payload.Event)
+	assert.Equal(t, 5, payload.Memo.ID)
+	assert.Equal(t, "high", payload.Memo.Priority)
+}
+
+func TestDispatcher_RetriesFailedDeliveries(t *testing.T) {
+	t.Run("失敗した送信は再送する", func(t *testing.T) {
+		recv := &receiver{failures: 2}
+		server := httptest.NewServer(recv)
+		defer server.Close()
+
+		d := newTestDispatcher(t, []domain.Webhook{{ID: 1, UserID: 42, URL: server.URL, Secret: "secret", Events: domain.MemoEvents}}, 3)
+		d.PublishMemoEvent(domain.WithUserID(context.Background(), 42), domain.MemoEventUpdated, &domain.Memo{ID: 5})
+
+		require.Eventually(t, func() bool { return len(recv.received()) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&recv.attempts))
+	})
+
+	t.Run("試行回数の上限に達したら破棄する", func(t *testing.T) {
+		recv := &receiver{failures: 100}
+		server := httptest.NewServer(recv)
+		defer server.Close()
+
+		d := newTestDispatcher(t, []domain.Webhook{{ID: 1, UserID: 42, URL: server.URL, Secret: "secret", Events: domain.MemoEvents}}, 2)
+		d.PublishMemoEvent(domain.WithUserID(context.Background(), 42), domain.MemoEventUpdated, &domain.Memo{ID: 5})
+
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&recv.attempts) == 2 }, time.Second, 5*time.Millisecond)
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&recv.attempts))
+	})
+}
+
+func TestDispatcher_PublishDoesNotBlockOnSlowEndpoint(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	d := newTestDispatcher(t, []domain.Webhook{{ID: 1, UserID: 42, URL: server.URL, Secret: "secret", Events: domain.MemoEvents}}, 1)
+
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		d.PublishMemoEvent(domain.WithUserID(context.Background(), 42), domain.MemoEventCreated, &domain.Memo{ID: i})
+	}
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}